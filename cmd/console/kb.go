@@ -0,0 +1,189 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kubestellar/console/pkg/api"
+	"github.com/kubestellar/console/pkg/kbmirror"
+)
+
+const kbUsage = `Usage: console kb <command> [flags]
+
+  build    pack a console-kb checkout into a bundle for air-gapped installs;
+           with --base, only files changed since that bundle are packed
+  import   verify a bundle and install it into the mission mirror served
+           when MISSIONS_SOURCE=bundle (a running console picks it up on
+           its next sync)
+
+Examples:
+  console kb build --src ./console-kb --version 2026.10.17 --key kb-signing.pem -o console-kb-2026.10.17.tar.gz
+  console kb build --src ./console-kb --version 2026.10.24 --base console-kb-2026.10.17.tar.gz -o console-kb-2026.10.24-delta.tar.gz
+  console kb import console-kb-2026.10.17.tar.gz
+
+Signing keys are PKCS #8 PEM Ed25519 keys (openssl genpkey -algorithm ed25519).
+`
+
+// runKB implements "console kb" and returns the process exit code.
+func runKB(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, kbUsage)
+		return 2
+	}
+	switch args[0] {
+	case "build":
+		return runKBBuild(args[1:])
+	case "import":
+		return runKBImport(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, kbUsage)
+		return 2
+	}
+}
+
+func runKBBuild(args []string) int {
+	fs := flag.NewFlagSet("kb build", flag.ContinueOnError)
+	src := fs.String("src", "", "console-kb checkout to pack (required)")
+	version := fs.String("version", "", "bundle version, e.g. 2026.10.17 (required)")
+	source := fs.String("source", "", "provenance recorded in the manifest, e.g. kubestellar/console-kb@<commit>")
+	base := fs.String("base", "", "previous bundle; only changed files are packed")
+	keyPath := fs.String("key", "", "Ed25519 PEM signing key")
+	out := fs.String("o", "", "output bundle path (required)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), kbUsage+"\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *src == "" || *version == "" || *out == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	opts := kbmirror.BuildOptions{Version: *version, Source: *source}
+	if *base != "" {
+		m, err := readBundleManifest(*base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kb build: base %s: %v\n", *base, err)
+			return 1
+		}
+		opts.Base = m
+	}
+	if *keyPath != "" {
+		data, err := os.ReadFile(*keyPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kb build: %v\n", err)
+			return 1
+		}
+		if opts.Key, err = kbmirror.ParsePrivateKey(data); err != nil {
+			fmt.Fprintf(os.Stderr, "kb build: %s: %v\n", *keyPath, err)
+			return 1
+		}
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kb build: %v\n", err)
+		return 1
+	}
+	m, err := kbmirror.Build(f, *src, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(*out)
+		fmt.Fprintf(os.Stderr, "kb build: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "bundle:   %s\n", *out)
+	fmt.Fprintf(os.Stdout, "version:  %s\n", m.Version)
+	fmt.Fprintf(os.Stdout, "files:    %d\n", len(m.Files))
+	if m.Base != "" {
+		fmt.Fprintf(os.Stdout, "base:     %s (incremental)\n", m.Base)
+	}
+	if opts.Key != nil {
+		fmt.Fprintf(os.Stdout, "signed:   key %s\n", kbmirror.KeyID(opts.Key.Public().(ed25519.PublicKey)))
+	}
+	return 0
+}
+
+func runKBImport(args []string) int {
+	fs := flag.NewFlagSet("kb import", flag.ContinueOnError)
+	dir := fs.String("dir", "", "mission mirror directory (default: MISSIONS_KB_DIR or missions-kb next to the database)")
+	keyPath := fs.String("key", "", "Ed25519 PEM key bundles must be signed with (default: MISSIONS_KB_PUBLIC_KEY)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), kbUsage+"\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg := api.LoadConfigFromEnv()
+	if *dir == "" {
+		*dir = cfg.MissionsKBDir
+	}
+	if *dir == "" {
+		*dir = filepath.Join(filepath.Dir(cfg.DatabasePath), "missions-kb")
+	}
+	if *keyPath == "" {
+		*keyPath = cfg.MissionsKBPublicKey
+	}
+	var key ed25519.PublicKey
+	if *keyPath != "" {
+		data, err := os.ReadFile(*keyPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kb import: %v\n", err)
+			return 1
+		}
+		if key, err = kbmirror.ParsePublicKey(data); err != nil {
+			fmt.Fprintf(os.Stderr, "kb import: %s: %v\n", *keyPath, err)
+			return 1
+		}
+	}
+
+	mirror, err := kbmirror.Open(*dir, key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kb import: %v\n", err)
+		return 1
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kb import: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+	st, changed, err := mirror.Import(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kb import: %v\n", err)
+		return 1
+	}
+	if !changed {
+		fmt.Fprintf(os.Stdout, "version %s is already installed\n", st.Version)
+		return 0
+	}
+	fmt.Fprintf(os.Stdout, "installed: %s (%d files) in %s\n", st.Version, st.Files, *dir)
+	if st.SignatureVerified {
+		fmt.Fprintf(os.Stdout, "signature: verified with key %s\n", st.KeyID)
+	} else {
+		fmt.Fprintln(os.Stdout, "signature: not checked (no trusted key)")
+	}
+	return 0
+}
+
+func readBundleManifest(path string) (*kbmirror.Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return kbmirror.ReadManifest(f)
+}
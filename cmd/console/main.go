@@ -27,6 +27,19 @@ func main() {
 	}
 	slog.SetDefault(slog.New(logHandler))
 
+	// "console migrate ..." manages the database schema and exits.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+	// "console audit verify" checks the audit log hash chain and exits.
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(runAudit(os.Args[2:]))
+	}
+	// "console kb build|import" packs and installs console-kb bundles.
+	if len(os.Args) > 1 && os.Args[1] == "kb" {
+		os.Exit(runKB(os.Args[2:]))
+	}
+
 	// Parse flags
 	devMode := flag.Bool("dev", false, "Run in development mode")
 	port := flag.Int("port", 0, "Server port (default: 8080)")
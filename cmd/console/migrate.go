@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubestellar/console/pkg/api"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const migrateUsage = `Usage: console migrate [flags] <status|up|down>
+
+  status   list schema migrations and whether each has been applied
+  up       apply pending migrations (to --to, default: latest)
+  down     roll back migrations (to --to, default: one step)
+
+The database is DATABASE_URL when set, otherwise the SQLite file from --db
+or DATABASE_PATH.
+
+Flags:
+`
+
+// migrateTimeout bounds a migrate command run.
+const migrateTimeout = 10 * time.Minute
+
+var migrateCommands = map[string]bool{"status": true, "up": true, "down": true}
+
+// runMigrate implements "console migrate" and returns the process exit code.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "SQLite database path (default: DATABASE_PATH)")
+	to := fs.Int("to", -1, "target schema version")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), migrateUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || !migrateCommands[fs.Arg(0)] {
+		fs.Usage()
+		return 2
+	}
+
+	cfg := api.LoadConfigFromEnv()
+	if *dbPath != "" {
+		cfg.DatabasePath = *dbPath
+	}
+	db, err := openForMigrate(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	switch fs.Arg(0) {
+	case "status":
+		err = printMigrationStatus(ctx, db)
+	case "up":
+		target := *to
+		if target < 0 {
+			target = 0 // latest
+		}
+		var n int
+		n, err = db.MigrateUp(ctx, target)
+		if n > 0 || err == nil {
+			fmt.Fprintf(os.Stdout, "applied %d migration(s)\n", n)
+		}
+	case "down":
+		target := *to
+		if target < 0 {
+			var current int
+			if current, err = db.SchemaVersion(ctx); err != nil {
+				break
+			}
+			target = current - 1
+		}
+		var n int
+		n, err = db.MigrateDown(ctx, target)
+		if n > 0 || err == nil {
+			fmt.Fprintf(os.Stdout, "reverted %d migration(s)\n", n)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate %s: %v\n", fs.Arg(0), err)
+		if errors.Is(err, store.ErrSchemaTooNew) {
+			fmt.Fprintln(os.Stderr, "hint: run this command with the console version that created the database")
+		}
+		return 1
+	}
+	return 0
+}
+
+// openForMigrate opens the configured database without migrating it.
+func openForMigrate(cfg api.Config) (*store.SQLiteStore, error) {
+	if cfg.DatabaseURL != "" {
+		pg, err := store.OpenPostgresStore(cfg.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return pg.SQLiteStore, nil
+	}
+	ensureDir(cfg.DatabasePath)
+	return store.OpenSQLiteStore(cfg.DatabasePath)
+}
+
+func printMigrationStatus(ctx context.Context, db *store.SQLiteStore) error {
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := db.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\tREVERSIBLE")
+	for _, st := range status {
+		applied := "pending"
+		if st.AppliedAt != nil {
+			applied = st.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%t\n", st.Version, st.Name, applied, st.Reversible)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "schema version %d (latest %d)\n", current, store.LatestSchemaVersion())
+	if current > store.LatestSchemaVersion() {
+		return store.ErrSchemaTooNew
+	}
+	return nil
+}
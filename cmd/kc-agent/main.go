@@ -29,6 +29,9 @@ func main() {
 	port := flag.Int("port", 8585, "Port to listen on")
 	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file")
 	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated list of additional allowed WebSocket origins")
+	tunnelURL := flag.String("tunnel-url", os.Getenv("KC_TUNNEL_URL"), "Console URL to open an outbound reverse tunnel to (e.g. https://console.example.com)")
+	tunnelPairingCode := flag.String("tunnel-pairing-code", os.Getenv("KC_TUNNEL_PAIRING_CODE"), "One-time pairing code from the console, exchanged for a stored tunnel credential")
+	tunnelName := flag.String("tunnel-name", os.Getenv("KC_TUNNEL_NAME"), "Name shown for this agent in the console (default: hostname)")
 	version := flag.Bool("version", false, "Print version and exit")
 	flag.Parse()
 
@@ -53,6 +56,10 @@ func main() {
 		Port:           *port,
 		Kubeconfig:     *kubeconfig,
 		AllowedOrigins: origins,
+
+		TunnelURL:         *tunnelURL,
+		TunnelPairingCode: *tunnelPairingCode,
+		TunnelName:        *tunnelName,
 	})
 	if err != nil {
 		slog.Error("failed to create server", "error", err)
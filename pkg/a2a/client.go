@@ -0,0 +1,342 @@
+package a2a
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultClientTimeout = 60 * time.Second
+	// maxResponseBytes caps a non-streamed response and maxEventBytes one
+	// streamed event.
+	maxResponseBytes = 10 * 1024 * 1024 // 10 MiB
+	maxEventBytes    = 1024 * 1024      // 1 MiB
+	maxErrorBodyLen  = 512
+	jsonRPCVersion   = "2.0"
+)
+
+// cardPaths are tried in order; agent.json predates A2A 0.3.
+var cardPaths = []string{"/.well-known/agent-card.json", "/.well-known/agent.json"}
+
+// Client talks to one A2A agent. It is safe for concurrent use.
+type Client struct {
+	url        string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client. Its Timeout applies to non-streamed
+// calls; streams are bounded by the caller's context instead.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithBearerToken authenticates every request with token.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		if token != "" {
+			c.header.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
+// WithHeader adds a header to every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// NewClient creates a client for the agent whose JSON-RPC endpoint is
+// agentURL. The agent card is looked up relative to the same URL.
+func NewClient(agentURL string, opts ...Option) *Client {
+	c := &Client{
+		url:        strings.TrimRight(agentURL, "/"),
+		httpClient: &http.Client{Timeout: defaultClientTimeout},
+		header:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// URL returns the agent's endpoint.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Discover fetches the agent card.
+func (c *Client) Discover(ctx context.Context) (*AgentCard, error) {
+	var lastErr error
+	for _, p := range cardPaths {
+		card, err := c.fetchCard(ctx, c.url+p)
+		if err == nil {
+			return card, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("discover A2A agent at %s: %w", c.url, lastErr)
+}
+
+func (c *Client) fetchCard(ctx context.Context, url string) (*AgentCard, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+	var card AgentCard
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&card); err != nil {
+		return nil, fmt.Errorf("decode agent card: %w", err)
+	}
+	if card.Name == "" {
+		return nil, errors.New("agent card has no name")
+	}
+	return &card, nil
+}
+
+// NewTextMessage builds a user message. contextID continues an existing
+// conversation and taskID an existing task; either may be empty.
+func NewTextMessage(text, contextID, taskID string) Message {
+	return Message{
+		Kind:      KindMessage,
+		MessageID: uuid.NewString(),
+		Role:      RoleUser,
+		Parts:     []Part{TextPart(text)},
+		ContextID: contextID,
+		TaskID:    taskID,
+	}
+}
+
+// SendMessage sends a message and returns the agent's reply: a Message, or
+// a Task the agent created for it.
+func (c *Client) SendMessage(ctx context.Context, params MessageSendParams) (Event, error) {
+	var raw json.RawMessage
+	if err := c.call(ctx, MethodSendMessage, params, &raw); err != nil {
+		return Event{}, err
+	}
+	return decodeEvent(raw)
+}
+
+// StreamMessage sends a message and calls onEvent for each event the agent
+// streams back until the stream ends, onEvent returns an error, or ctx is
+// done. Agents that do not stream (Capabilities.Streaming false) should be
+// called with SendMessage.
+func (c *Client) StreamMessage(ctx context.Context, params MessageSendParams, onEvent func(Event) error) error {
+	return c.stream(ctx, MethodStreamMessage, params, onEvent)
+}
+
+// GetTask fetches a task. historyLength limits the returned history; zero
+// leaves it to the agent.
+func (c *Client) GetTask(ctx context.Context, taskID string, historyLength int) (*Task, error) {
+	params := map[string]any{"id": taskID}
+	if historyLength > 0 {
+		params["historyLength"] = historyLength
+	}
+	var task Task
+	if err := c.call(ctx, MethodGetTask, params, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CancelTask asks the agent to cancel a task and returns its new state.
+func (c *Client) CancelTask(ctx context.Context, taskID string) (*Task, error) {
+	var task Task
+	if err := c.call(ctx, MethodCancelTask, map[string]any{"id": taskID}, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ResubscribeTask resumes streaming events for a running task, e.g. after
+// a dropped connection.
+func (c *Client) ResubscribeTask(ctx context.Context, taskID string, onEvent func(Event) error) error {
+	return c.stream(ctx, MethodResubscribeTask, map[string]any{"id": taskID}, onEvent)
+}
+
+// Call sends a JSON-RPC request and returns the raw response body, for
+// callers that relay the agent's response as is. The caller closes it.
+func (c *Client) Call(ctx context.Context, method string, params any) (io.ReadCloser, error) {
+	resp, err := c.post(ctx, c.httpClient, method, params, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      string `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	resp, err := c.post(ctx, c.httpClient, method, params, "application/json")
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	var rpc rpcResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&rpc); err != nil {
+		return fmt.Errorf("decode A2A %s response: %w", method, err)
+	}
+	if rpc.Error != nil {
+		return rpc.Error
+	}
+	if len(rpc.Result) == 0 {
+		return fmt.Errorf("A2A %s response has no result", method)
+	}
+	return json.Unmarshal(rpc.Result, result)
+}
+
+func (c *Client) stream(ctx context.Context, method string, params any, onEvent func(Event) error) error {
+	// Streams run as long as the agent works; only ctx bounds them.
+	hc := *c.httpClient
+	hc.Timeout = 0
+	resp, err := c.post(ctx, &hc, method, params, "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	// An agent may answer a stream request with a single JSON response,
+	// e.g. an error before any event.
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var rpc rpcResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&rpc); err != nil {
+			return fmt.Errorf("decode A2A %s response: %w", method, err)
+		}
+		return dispatch(rpc, onEvent)
+	}
+
+	return readSSE(resp.Body, func(data []byte) error {
+		var rpc rpcResponse
+		if err := json.Unmarshal(data, &rpc); err != nil {
+			return fmt.Errorf("decode A2A %s event: %w", method, err)
+		}
+		return dispatch(rpc, onEvent)
+	})
+}
+
+func dispatch(rpc rpcResponse, onEvent func(Event) error) error {
+	if rpc.Error != nil {
+		return rpc.Error
+	}
+	ev, err := decodeEvent(rpc.Result)
+	if err != nil {
+		return err
+	}
+	return onEvent(ev)
+}
+
+func (c *Client) post(ctx context.Context, hc *http.Client, method string, params any, accept string) (*http.Response, error) {
+	body, err := json.Marshal(rpcRequest{JSONRPC: jsonRPCVersion, ID: uuid.NewString(), Method: method, Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("marshal A2A %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("A2A %s to %s failed: %w", method, c.url, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer drainAndClose(resp.Body)
+		return nil, fmt.Errorf("A2A %s: %w", method, statusError(resp))
+	}
+	return resp, nil
+}
+
+func (c *Client) setHeaders(req *http.Request) {
+	for k, vs := range c.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+}
+
+// readSSE calls onData with the data of each server-sent event.
+func readSSE(r io.Reader, onData func([]byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxEventBytes)
+	var data bytes.Buffer
+	flush := func() error {
+		if data.Len() == 0 {
+			return nil
+		}
+		defer data.Reset()
+		return onData(data.Bytes())
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read A2A event stream: %w", err)
+	}
+	return flush()
+}
+
+func statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLen))
+	return fmt.Errorf("%s returned %d: %s", resp.Request.URL, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// drainAndClose drains a response body so its connection can be reused.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxErrorBodyLen))
+	_ = body.Close()
+}
@@ -0,0 +1,252 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent is a minimal A2A agent. Each sent message creates a task that
+// completes after polls tasks/get calls, or streams its events.
+type fakeAgent struct {
+	t     *testing.T
+	polls int
+
+	mu        sync.Mutex
+	requests  []rpcRequest
+	tasks     map[string]*Task
+	canceled  []string
+	streamErr bool
+}
+
+func newFakeAgent(t *testing.T, polls int) (*fakeAgent, *httptest.Server) {
+	a := &fakeAgent{t: t, polls: polls, tasks: make(map[string]*Task)}
+	mux := http.NewServeMux()
+	// Only the legacy card path exists, to exercise the fallback.
+	mux.HandleFunc("/a2a/.well-known/agent.json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode(AgentCard{
+			Name:         "Triage Agent",
+			Capabilities: AgentCapabilities{Streaming: true},
+			Skills:       []AgentSkill{{ID: "triage", Name: "Triage"}},
+		})
+	})
+	mux.HandleFunc("/a2a", a.serveRPC)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return a, srv
+}
+
+func (a *fakeAgent) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var raw struct {
+		rpcRequest
+		Params json.RawMessage `json:"params"`
+	}
+	require.NoError(a.t, json.NewDecoder(r.Body).Decode(&raw))
+	assert.Equal(a.t, jsonRPCVersion, raw.JSONRPC)
+	assert.NotEmpty(a.t, raw.ID)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, raw.rpcRequest)
+
+	reply := func(result any) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": raw.ID, "result": result})
+	}
+	var params struct {
+		ID      string  `json:"id"`
+		Message Message `json:"message"`
+	}
+	_ = json.Unmarshal(raw.Params, &params)
+
+	switch raw.Method {
+	case MethodSendMessage:
+		if params.Message.Text() == "hello" {
+			reply(Message{Kind: KindMessage, MessageID: "m1", Role: RoleAgent, ContextID: "ctx-1", Parts: []Part{TextPart("hi there")}})
+			return
+		}
+		task := &Task{Kind: KindTask, ID: fmt.Sprintf("task-%d", len(a.tasks)+1), ContextID: "ctx-1", Status: TaskStatus{State: TaskStateSubmitted}}
+		a.tasks[task.ID] = task
+		reply(task)
+	case MethodGetTask:
+		task, ok := a.tasks[params.ID]
+		if !ok {
+			_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": raw.ID, "error": RPCError{Code: CodeTaskNotFound, Message: "task not found"}})
+			return
+		}
+		a.polls--
+		if a.polls <= 0 {
+			task.Status = TaskStatus{State: TaskStateCompleted}
+			task.Artifacts = []Artifact{{ArtifactID: "a1", Parts: []Part{TextPart("3 pods are crash-looping")}}}
+		} else {
+			task.Status = TaskStatus{State: TaskStateWorking, Message: &Message{Kind: KindMessage, Role: RoleAgent, Parts: []Part{TextPart("checking pods")}}}
+		}
+		reply(task)
+	case MethodCancelTask:
+		a.canceled = append(a.canceled, params.ID)
+		reply(Task{Kind: KindTask, ID: params.ID, Status: TaskStatus{State: TaskStateCanceled}})
+	case MethodStreamMessage:
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []any{
+			Task{Kind: KindTask, ID: "task-s", ContextID: "ctx-2", Status: TaskStatus{State: TaskStateSubmitted}},
+			TaskStatusUpdateEvent{Kind: KindStatusUpdate, TaskID: "task-s", Status: TaskStatus{State: TaskStateWorking, Message: &Message{Kind: KindMessage, Role: RoleAgent, Parts: []Part{TextPart("thinking")}}}},
+			TaskArtifactUpdateEvent{Kind: KindArtifactUpdate, TaskID: "task-s", Artifact: Artifact{ArtifactID: "a1", Parts: []Part{TextPart("part one, ")}}},
+			TaskArtifactUpdateEvent{Kind: KindArtifactUpdate, TaskID: "task-s", Artifact: Artifact{ArtifactID: "a1", Parts: []Part{TextPart("part two")}}, Append: true, LastChunk: true},
+		}
+		final := TaskStatusUpdateEvent{Kind: KindStatusUpdate, TaskID: "task-s", Status: TaskStatus{State: TaskStateCompleted}, Final: true}
+		if a.streamErr {
+			final.Status = TaskStatus{State: TaskStateFailed, Message: &Message{Kind: KindMessage, Role: RoleAgent, Parts: []Part{TextPart("model quota exceeded")}}}
+		}
+		events = append(events, final)
+		for _, ev := range events {
+			data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": raw.ID, "result": ev})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": raw.ID, "error": RPCError{Code: -32601, Message: "method not found"}})
+	}
+}
+
+func TestClient_Discover(t *testing.T) {
+	_, srv := newFakeAgent(t, 0)
+	c := NewClient(srv.URL+"/a2a/", WithBearerToken("tok"))
+
+	card, err := c.Discover(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Triage Agent", card.Name)
+	assert.True(t, card.Capabilities.Streaming)
+	assert.Equal(t, "triage", card.Skills[0].ID)
+
+	_, err = NewClient(srv.URL + "/missing").Discover(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClient_RunPollsTaskToCompletion(t *testing.T) {
+	agent, srv := newFakeAgent(t, 2)
+	c := NewClient(srv.URL + "/a2a")
+
+	var statuses []string
+	reply, err := c.Run(context.Background(), MessageSendParams{Message: NewTextMessage("why are pods failing?", "", "")}, RunOptions{
+		PollInterval: time.Millisecond,
+		OnStatus:     func(_ TaskState, text string) { statuses = append(statuses, text) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "3 pods are crash-looping", reply.Text)
+	assert.Equal(t, TaskStateCompleted, reply.State)
+	assert.Equal(t, "ctx-1", reply.ContextID)
+	assert.Equal(t, []string{"checking pods"}, statuses)
+
+	var methods []string
+	for _, r := range agent.requests {
+		methods = append(methods, r.Method)
+	}
+	assert.Equal(t, []string{MethodSendMessage, MethodGetTask, MethodGetTask}, methods)
+}
+
+func TestClient_RunDirectMessage(t *testing.T) {
+	_, srv := newFakeAgent(t, 0)
+	reply, err := NewClient(srv.URL+"/a2a").Run(context.Background(), MessageSendParams{Message: NewTextMessage("hello", "", "")}, RunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "hi there", reply.Text)
+	assert.Empty(t, reply.TaskID)
+}
+
+func TestClient_RunStreams(t *testing.T) {
+	agent, srv := newFakeAgent(t, 0)
+	c := NewClient(srv.URL + "/a2a")
+
+	var chunks []string
+	reply, err := c.Run(context.Background(), MessageSendParams{Message: NewTextMessage("summarize", "", "")}, RunOptions{
+		Stream: true,
+		OnText: func(s string) { chunks = append(chunks, s) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part one, ", "part two"}, chunks)
+	assert.Equal(t, "part one, part two", reply.Text)
+	assert.Equal(t, "task-s", reply.TaskID)
+	assert.Equal(t, "ctx-2", reply.ContextID)
+
+	agent.streamErr = true
+	_, err = c.Run(context.Background(), MessageSendParams{Message: NewTextMessage("summarize", "", "")}, RunOptions{Stream: true})
+	var taskErr *TaskError
+	require.ErrorAs(t, err, &taskErr)
+	assert.Equal(t, TaskStateFailed, taskErr.State)
+	assert.Equal(t, "model quota exceeded", taskErr.Message)
+}
+
+func TestClient_RunCancelsAbandonedTask(t *testing.T) {
+	agent, srv := newFakeAgent(t, 1000)
+	c := NewClient(srv.URL + "/a2a")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Run(ctx, MessageSendParams{Message: NewTextMessage("long job", "", "")}, RunOptions{PollInterval: 5 * time.Millisecond})
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	assert.Equal(t, []string{"task-1"}, agent.canceled)
+}
+
+func TestClient_TaskMethodsAndErrors(t *testing.T) {
+	_, srv := newFakeAgent(t, 1)
+	c := NewClient(srv.URL + "/a2a")
+	ctx := context.Background()
+
+	ev, err := c.SendMessage(ctx, MessageSendParams{Message: NewTextMessage("job", "", "")})
+	require.NoError(t, err)
+	require.NotNil(t, ev.Task)
+	assert.False(t, ev.Final())
+
+	task, err := c.GetTask(ctx, ev.Task.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, TaskStateCompleted, task.Status.State)
+	assert.Equal(t, "3 pods are crash-looping", task.Text())
+
+	task, err = c.CancelTask(ctx, ev.Task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TaskStateCanceled, task.Status.State)
+
+	_, err = c.GetTask(ctx, "nope", 0)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, CodeTaskNotFound, rpcErr.Code)
+}
+
+func TestReadSSE_MultiLineData(t *testing.T) {
+	var got []string
+	err := readSSE(strings.NewReader("event: x\ndata: {\"a\":\ndata: 1}\n\n: comment\ndata: last"), func(b []byte) error {
+		got = append(got, string(b))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"{\"a\":\n1}", "last"}, got)
+}
+
+func TestParseEndpoints(t *testing.T) {
+	eps, err := ParseEndpoints(" https://a.example.com/a2a/ , Triage Bot=http://triage.agents.svc:8080 ,https://a.example.com/a2a")
+	require.NoError(t, err)
+	require.Len(t, eps, 2)
+	assert.Equal(t, Endpoint{URL: "https://a.example.com/a2a"}, eps[0])
+	assert.Equal(t, Endpoint{Name: "triage-bot", URL: "http://triage.agents.svc:8080"}, eps[1])
+
+	assert.Equal(t, "a-example-com", eps[0].DefaultName(nil))
+	assert.Equal(t, "k8s-helper", eps[0].DefaultName(&AgentCard{Name: "K8s Helper!"}))
+	assert.Equal(t, "triage-bot", eps[1].DefaultName(&AgentCard{Name: "Other"}))
+
+	for _, bad := range []string{"ftp://x", "not a url", "=https://x.example.com"} {
+		_, err := ParseEndpoints(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,121 @@
+package a2a
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvAgentURLs lists the A2A agents to expose as AI providers:
+	// comma-separated endpoint URLs, each optionally prefixed "name=".
+	EnvAgentURLs = "A2A_AGENT_URLS"
+	// EnvAgentToken is an optional bearer token sent to every listed agent.
+	EnvAgentToken = "A2A_AGENT_TOKEN"
+
+	maxEndpointNameLen = 40
+)
+
+// Endpoint is one configured agent.
+type Endpoint struct {
+	// Name is the configured short name, or empty to name the agent after
+	// its card.
+	Name string
+	URL  string
+}
+
+// ParseEndpoints parses an EnvAgentURLs value such as
+// "https://a.example.com/a2a,triage=http://triage.agents.svc:8080".
+func ParseEndpoints(value string) ([]Endpoint, error) {
+	var out []Endpoint
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		var ep Endpoint
+		if name, rest, ok := strings.Cut(entry, "="); ok && !strings.Contains(name, "://") {
+			ep.Name = Slug(name)
+			if ep.Name == "" {
+				return nil, fmt.Errorf("invalid A2A agent name in %q", entry)
+			}
+			entry = strings.TrimSpace(rest)
+		}
+		u, err := url.Parse(entry)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid A2A agent URL %q: must be an http(s) URL", entry)
+		}
+		ep.URL = strings.TrimRight(u.String(), "/")
+		if seen[ep.URL] {
+			continue
+		}
+		seen[ep.URL] = true
+		out = append(out, ep)
+	}
+	return out, nil
+}
+
+// EndpointsFromEnv returns the agents listed in EnvAgentURLs. A malformed
+// value is logged and ignored so one typo does not stop startup.
+func EndpointsFromEnv() []Endpoint {
+	value := os.Getenv(EnvAgentURLs)
+	if value == "" {
+		return nil
+	}
+	endpoints, err := ParseEndpoints(value)
+	if err != nil {
+		slog.Error("[A2A] ignoring "+EnvAgentURLs, "error", err)
+		return nil
+	}
+	return endpoints
+}
+
+// ClientOptionsFromEnv returns the client options shared by the agents
+// listed in EnvAgentURLs.
+func ClientOptionsFromEnv() []Option {
+	if token := os.Getenv(EnvAgentToken); token != "" {
+		return []Option{WithBearerToken(token)}
+	}
+	return nil
+}
+
+// Slug turns an agent or host name into a short lower-case identifier for
+// provider names.
+func Slug(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			sb.WriteRune(r)
+			dash = false
+		case sb.Len() > 0 && !dash:
+			sb.WriteByte('-')
+			dash = true
+		}
+		if sb.Len() >= maxEndpointNameLen {
+			break
+		}
+	}
+	return strings.TrimRight(sb.String(), "-")
+}
+
+// DefaultName names an endpoint that has no configured name: after its
+// agent card when known, else after its host.
+func (e Endpoint) DefaultName(card *AgentCard) string {
+	if e.Name != "" {
+		return e.Name
+	}
+	if card != nil {
+		if s := Slug(card.Name); s != "" {
+			return s
+		}
+	}
+	if u, err := url.Parse(e.URL); err == nil {
+		return Slug(u.Hostname())
+	}
+	return "agent"
+}
@@ -0,0 +1,202 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPollInterval = time.Second
+	cancelTimeout       = 5 * time.Second
+)
+
+// RunOptions configures Client.Run.
+type RunOptions struct {
+	// Stream uses message/stream; otherwise the task is polled with
+	// tasks/get until it finishes.
+	Stream bool
+	// PollInterval is the tasks/get interval when not streaming.
+	PollInterval time.Duration
+	// OnText receives answer text as it arrives.
+	OnText func(text string)
+	// OnStatus receives interim status messages of a working task.
+	OnStatus func(state TaskState, text string)
+}
+
+// Reply is the outcome of Client.Run.
+type Reply struct {
+	Text      string
+	ContextID string
+	// TaskID is set when the agent ran a task. With State input-required
+	// the next message should carry it to continue the task.
+	TaskID string
+	State  TaskState
+}
+
+// TaskError reports a task that failed or was rejected, canceled or needs
+// authorization the client cannot provide.
+type TaskError struct {
+	TaskID  string
+	State   TaskState
+	Message string
+}
+
+func (e *TaskError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("A2A task %s %s", e.TaskID, e.State)
+	}
+	return fmt.Sprintf("A2A task %s %s: %s", e.TaskID, e.State, e.Message)
+}
+
+// Run sends a message and waits until the agent answers or its task
+// finishes or needs input. When ctx ends while a task is running the task
+// is canceled.
+func (c *Client) Run(ctx context.Context, params MessageSendParams, opts RunOptions) (*Reply, error) {
+	r := &runState{opts: opts, reply: Reply{ContextID: params.Message.ContextID}}
+	var err error
+	if opts.Stream {
+		err = c.StreamMessage(ctx, params, r.handle)
+		if err == nil && !r.done && r.reply.TaskID != "" {
+			// The stream ended early; the task may still be running.
+			err = c.poll(ctx, r)
+		}
+	} else {
+		var ev Event
+		ev, err = c.SendMessage(ctx, params)
+		if err == nil {
+			err = r.handle(ev)
+		}
+		if err == nil && !r.done {
+			err = c.poll(ctx, r)
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil && r.reply.TaskID != "" && !r.done {
+			c.cancelDetached(r.reply.TaskID)
+		}
+		return nil, err
+	}
+	return r.finish()
+}
+
+func (c *Client) poll(ctx context.Context, r *runState) error {
+	interval := r.opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for !r.done {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		task, err := c.GetTask(ctx, r.reply.TaskID, 0)
+		if err != nil {
+			return err
+		}
+		if err := r.handle(Event{Task: task}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) cancelDetached(taskID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+	if _, err := c.CancelTask(ctx, taskID); err != nil {
+		slog.Warn("[A2A] failed to cancel abandoned task", "agent", c.url, "task", taskID, "error", err)
+	}
+}
+
+type runState struct {
+	opts   RunOptions
+	reply  Reply
+	answer strings.Builder
+	status string // message of the final or paused status
+	done   bool
+}
+
+func (r *runState) text(s string) {
+	if s == "" {
+		return
+	}
+	r.answer.WriteString(s)
+	if r.opts.OnText != nil {
+		r.opts.OnText(s)
+	}
+}
+
+func (r *runState) track(taskID, contextID string, status TaskStatus) {
+	if taskID != "" {
+		r.reply.TaskID = taskID
+	}
+	if contextID != "" {
+		r.reply.ContextID = contextID
+	}
+	if status.State != "" {
+		r.reply.State = status.State
+	}
+	s := status.Message.Text()
+	switch {
+	case s == "":
+	case status.State.Terminal() || status.State.awaitsClient():
+		r.status = s
+	case r.opts.OnStatus != nil:
+		r.opts.OnStatus(status.State, s)
+	}
+}
+
+func (r *runState) handle(ev Event) error {
+	switch {
+	case ev.Message != nil:
+		if ev.Message.ContextID != "" {
+			r.reply.ContextID = ev.Message.ContextID
+		}
+		r.text(ev.Message.Text())
+	case ev.Task != nil:
+		r.track(ev.Task.ID, ev.Task.ContextID, ev.Task.Status)
+		if ev.Final() && r.answer.Len() == 0 {
+			// Artifacts were not streamed; take them from the task.
+			var texts []string
+			for i := range ev.Task.Artifacts {
+				if s := ev.Task.Artifacts[i].Text(); s != "" {
+					texts = append(texts, s)
+				}
+			}
+			r.text(strings.Join(texts, "\n\n"))
+		}
+	case ev.StatusUpdate != nil:
+		r.track(ev.StatusUpdate.TaskID, ev.StatusUpdate.ContextID, ev.StatusUpdate.Status)
+	case ev.ArtifactUpdate != nil:
+		r.track(ev.ArtifactUpdate.TaskID, ev.ArtifactUpdate.ContextID, TaskStatus{})
+		r.text(ev.ArtifactUpdate.Artifact.Text())
+	}
+	if ev.Final() {
+		r.done = true
+	}
+	return nil
+}
+
+func (r *runState) finish() (*Reply, error) {
+	switch r.reply.State {
+	case TaskStateFailed, TaskStateRejected, TaskStateCanceled, TaskStateAuthRequired:
+		return nil, &TaskError{TaskID: r.reply.TaskID, State: r.reply.State, Message: r.status}
+	}
+	if r.answer.Len() == 0 && r.status != "" {
+		// No artifacts: the final status message is the answer, e.g. the
+		// question of an input-required task.
+		r.text(r.status)
+	}
+	if !r.done && r.reply.TaskID == "" && r.answer.Len() == 0 {
+		return nil, errors.New("A2A agent ended the exchange without a reply")
+	}
+	r.reply.Text = r.answer.String()
+	return &r.reply, nil
+}
@@ -0,0 +1,321 @@
+// Package a2a is a client for the Agent2Agent (A2A) protocol: agent card
+// discovery, sending and streaming messages over JSON-RPC, and task status,
+// cancellation and artifacts. It works with any compliant agent; kagent and
+// Kagenti are configurations of it.
+package a2a
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSON-RPC methods defined by the A2A specification.
+const (
+	MethodSendMessage     = "message/send"
+	MethodStreamMessage   = "message/stream"
+	MethodGetTask         = "tasks/get"
+	MethodCancelTask      = "tasks/cancel"
+	MethodResubscribeTask = "tasks/resubscribe"
+)
+
+// Object kinds used as the "kind" discriminator in results and events.
+const (
+	KindMessage        = "message"
+	KindTask           = "task"
+	KindStatusUpdate   = "status-update"
+	KindArtifactUpdate = "artifact-update"
+
+	PartKindText = "text"
+	PartKindData = "data"
+	PartKindFile = "file"
+)
+
+// Role is the sender of a message.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAgent Role = "agent"
+)
+
+// AgentCard describes an agent, served at /.well-known/agent-card.json
+// (or agent.json before A2A 0.3).
+type AgentCard struct {
+	ProtocolVersion    string            `json:"protocolVersion,omitempty"`
+	Name               string            `json:"name"`
+	Description        string            `json:"description,omitempty"`
+	URL                string            `json:"url,omitempty"`
+	Version            string            `json:"version,omitempty"`
+	PreferredTransport string            `json:"preferredTransport,omitempty"`
+	Provider           *AgentProvider    `json:"provider,omitempty"`
+	Capabilities       AgentCapabilities `json:"capabilities"`
+	DefaultInputModes  []string          `json:"defaultInputModes,omitempty"`
+	DefaultOutputModes []string          `json:"defaultOutputModes,omitempty"`
+	Skills             []AgentSkill      `json:"skills,omitempty"`
+}
+
+// AgentProvider is the organization that runs an agent.
+type AgentProvider struct {
+	Organization string `json:"organization"`
+	URL          string `json:"url,omitempty"`
+}
+
+// AgentCapabilities lists the optional protocol features an agent supports.
+type AgentCapabilities struct {
+	Streaming              bool `json:"streaming,omitempty"`
+	PushNotifications      bool `json:"pushNotifications,omitempty"`
+	StateTransitionHistory bool `json:"stateTransitionHistory,omitempty"`
+}
+
+// AgentSkill is one thing an agent advertises it can do.
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+}
+
+// Part is one piece of message or artifact content. Kind selects which of
+// Text, Data or File is set.
+type Part struct {
+	Kind     string         `json:"kind"`
+	Text     string         `json:"text,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+	File     *FileContent   `json:"file,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// FileContent is a file carried inline (Bytes, base64) or by reference (URI).
+type FileContent struct {
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Bytes    string `json:"bytes,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// TextPart returns a text part.
+func TextPart(text string) Part {
+	return Part{Kind: PartKindText, Text: text}
+}
+
+// Message is one turn of a conversation.
+type Message struct {
+	Kind      string         `json:"kind"`
+	MessageID string         `json:"messageId"`
+	Role      Role           `json:"role"`
+	Parts     []Part         `json:"parts"`
+	ContextID string         `json:"contextId,omitempty"`
+	TaskID    string         `json:"taskId,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+// Text concatenates the message's text parts.
+func (m *Message) Text() string {
+	if m == nil {
+		return ""
+	}
+	return partsText(m.Parts)
+}
+
+// TaskState is the lifecycle state of a task.
+type TaskState string
+
+const (
+	TaskStateSubmitted     TaskState = "submitted"
+	TaskStateWorking       TaskState = "working"
+	TaskStateInputRequired TaskState = "input-required"
+	TaskStateAuthRequired  TaskState = "auth-required"
+	TaskStateCompleted     TaskState = "completed"
+	TaskStateCanceled      TaskState = "canceled"
+	TaskStateFailed        TaskState = "failed"
+	TaskStateRejected      TaskState = "rejected"
+	TaskStateUnknown       TaskState = "unknown"
+)
+
+// Terminal reports whether a task in this state will not change again.
+func (s TaskState) Terminal() bool {
+	switch s {
+	case TaskStateCompleted, TaskStateCanceled, TaskStateFailed, TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+// awaitsClient reports whether a task in this state is paused until the
+// client sends another message.
+func (s TaskState) awaitsClient() bool {
+	return s == TaskStateInputRequired || s == TaskStateAuthRequired
+}
+
+// TaskStatus is a task's current state and the agent's latest message.
+type TaskStatus struct {
+	State     TaskState `json:"state"`
+	Message   *Message  `json:"message,omitempty"`
+	Timestamp string    `json:"timestamp,omitempty"`
+}
+
+// Artifact is an output produced by a task.
+type Artifact struct {
+	ArtifactID  string         `json:"artifactId"`
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Parts       []Part         `json:"parts"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+// Text concatenates the artifact's text parts.
+func (a *Artifact) Text() string {
+	if a == nil {
+		return ""
+	}
+	return partsText(a.Parts)
+}
+
+// Task is a unit of work an agent runs in response to messages.
+type Task struct {
+	Kind      string         `json:"kind"`
+	ID        string         `json:"id"`
+	ContextID string         `json:"contextId,omitempty"`
+	Status    TaskStatus     `json:"status"`
+	Artifacts []Artifact     `json:"artifacts,omitempty"`
+	History   []Message      `json:"history,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+// Text is the task's answer: the text of its artifacts, or of its status
+// message when it produced none.
+func (t *Task) Text() string {
+	if t == nil {
+		return ""
+	}
+	var texts []string
+	for i := range t.Artifacts {
+		if s := t.Artifacts[i].Text(); s != "" {
+			texts = append(texts, s)
+		}
+	}
+	if len(texts) > 0 {
+		return strings.Join(texts, "\n\n")
+	}
+	return t.Status.Message.Text()
+}
+
+// TaskStatusUpdateEvent is streamed when a task changes state.
+type TaskStatusUpdateEvent struct {
+	Kind      string     `json:"kind"`
+	TaskID    string     `json:"taskId"`
+	ContextID string     `json:"contextId,omitempty"`
+	Status    TaskStatus `json:"status"`
+	Final     bool       `json:"final,omitempty"`
+}
+
+// TaskArtifactUpdateEvent is streamed when a task produces (part of) an
+// artifact. With Append set, the parts extend the artifact with the same ID.
+type TaskArtifactUpdateEvent struct {
+	Kind      string   `json:"kind"`
+	TaskID    string   `json:"taskId"`
+	ContextID string   `json:"contextId,omitempty"`
+	Artifact  Artifact `json:"artifact"`
+	Append    bool     `json:"append,omitempty"`
+	LastChunk bool     `json:"lastChunk,omitempty"`
+}
+
+// MessageSendConfiguration tunes how an agent handles a sent message.
+type MessageSendConfiguration struct {
+	AcceptedOutputModes []string `json:"acceptedOutputModes,omitempty"`
+	HistoryLength       *int     `json:"historyLength,omitempty"`
+	Blocking            bool     `json:"blocking,omitempty"`
+}
+
+// MessageSendParams are the params of message/send and message/stream.
+type MessageSendParams struct {
+	Message       Message                   `json:"message"`
+	Configuration *MessageSendConfiguration `json:"configuration,omitempty"`
+	Metadata      map[string]any            `json:"metadata,omitempty"`
+}
+
+// Event is one result of a call or one streamed event. Exactly one field
+// is set.
+type Event struct {
+	Message        *Message
+	Task           *Task
+	StatusUpdate   *TaskStatusUpdateEvent
+	ArtifactUpdate *TaskArtifactUpdateEvent
+}
+
+// Final reports whether the event ends the exchange: a direct message, a
+// task or status that is terminal or waiting for the client, or a status
+// update marked final.
+func (e Event) Final() bool {
+	switch {
+	case e.Message != nil:
+		return true
+	case e.Task != nil:
+		return e.Task.Status.State.Terminal() || e.Task.Status.State.awaitsClient()
+	case e.StatusUpdate != nil:
+		return e.StatusUpdate.Final || e.StatusUpdate.Status.State.Terminal() || e.StatusUpdate.Status.State.awaitsClient()
+	}
+	return false
+}
+
+func decodeEvent(raw json.RawMessage) (Event, error) {
+	var head struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return Event{}, fmt.Errorf("decode A2A result: %w", err)
+	}
+	var ev Event
+	var target any
+	switch head.Kind {
+	case KindMessage:
+		ev.Message = &Message{}
+		target = ev.Message
+	case KindTask:
+		ev.Task = &Task{}
+		target = ev.Task
+	case KindStatusUpdate:
+		ev.StatusUpdate = &TaskStatusUpdateEvent{}
+		target = ev.StatusUpdate
+	case KindArtifactUpdate:
+		ev.ArtifactUpdate = &TaskArtifactUpdateEvent{}
+		target = ev.ArtifactUpdate
+	default:
+		return Event{}, fmt.Errorf("unknown A2A result kind %q", head.Kind)
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return Event{}, fmt.Errorf("decode A2A %s: %w", head.Kind, err)
+	}
+	return ev, nil
+}
+
+func partsText(parts []Part) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		if p.Kind == PartKindText || (p.Kind == "" && p.Text != "") {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
+
+// Standard A2A JSON-RPC error codes.
+const (
+	CodeTaskNotFound                 = -32001
+	CodeTaskNotCancelable            = -32002
+	CodePushNotificationNotSupported = -32003
+	CodeUnsupportedOperation         = -32004
+)
+
+// RPCError is a JSON-RPC error returned by an agent.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("A2A error %d: %s", e.Code, e.Message)
+}
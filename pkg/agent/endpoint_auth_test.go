@@ -119,6 +119,9 @@ const (
 	// endpointPredictionsStats returns prediction statistics (sensitive).
 	endpointPredictionsStats = "/predictions/stats"
 
+	// endpointAIRouting returns AI routing state (sensitive — reveals usage).
+	endpointAIRouting = "/ai/routing"
+
 	// endpointDeviceAlerts returns device alerts (sensitive — reveals telemetry).
 	endpointDeviceAlerts = "/device/alerts"
 
@@ -206,6 +209,7 @@ var sensitiveEndpoints = []struct {
 	{endpointPredictionsAnalyze, "POST"},
 	{endpointPredictionsFeedback, "POST"},
 	{endpointPredictionsStats, "GET"},
+	{endpointAIRouting, "GET"},
 	{endpointDeviceAlerts, "GET"},
 	{endpointDeviceAlertsClear, "POST"},
 	{endpointHPAs, "GET"},
@@ -600,6 +604,7 @@ func resolveEndpointHandler(s *Server, path string) func(http.ResponseWriter, *h
 		endpointPredictionsAnalyze:  s.handlePredictionsAnalyze,
 		endpointPredictionsFeedback: s.handlePredictionsFeedback,
 		endpointPredictionsStats:    s.handlePredictionsStats,
+		endpointAIRouting:           s.handleAIRouting,
 		endpointDeviceAlerts:        s.handleDeviceAlerts,
 		endpointDeviceAlertsClear:   s.handleDeviceAlertsClear,
 		endpointArgoCDSync:          s.handleArgoCDSync,
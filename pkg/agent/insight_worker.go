@@ -9,6 +9,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/kubestellar/console/pkg/airouter"
 )
 
 // InsightEnrichmentCacheTTL is how long individual enrichments are cached
@@ -243,42 +245,40 @@ func (w *InsightWorker) callAIProvider(insights []InsightSummary) ([]AIInsightEn
 	ctx, cancel := context.WithTimeout(parent, InsightEnrichmentTimeout)
 	defer cancel()
 
-	// Build the effective priority list: if the user selected a primary
-	// agent, try it first, then fall back to the default list (skipping
-	// duplicates so the primary isn't attempted twice).
+	// The router tries the user's primary agent first, then the insights
+	// chain (or the default priority list when none is configured),
+	// skipping providers whose circuit breaker is open.
 	primaryAgent := w.registry.GetDefaultName()
-	order := buildProviderOrder(primaryAgent, defaultProviderPriority)
-
-	for _, name := range order {
-		provider, err := w.registry.Get(name)
-		if err != nil || !provider.IsAvailable() {
-			continue
-		}
-
-		req := &ChatRequest{
+	var enrichments []AIInsightEnrichment
+	resp, err := w.registry.Route(ctx, airouter.Request{
+		Category:  airouter.CategoryInsights,
+		Preferred: primaryAgent,
+		Fallback:  buildProviderOrder(primaryAgent, defaultProviderPriority),
+	}, func(ctx context.Context, provider AIProvider) (*ChatResponse, error) {
+		resp, err := provider.Chat(ctx, &ChatRequest{
 			SessionID: fmt.Sprintf("insight-enrich-%d", time.Now().Unix()),
 			Prompt:    prompt,
-		}
-
-		resp, err := provider.Chat(ctx, req)
+		})
 		if err != nil {
-			slog.Error("[InsightWorker] provider failed", "provider", name, "error", err)
-			continue
+			return nil, err
 		}
 		if resp == nil {
-			continue
+			return nil, fmt.Errorf("provider %s returned nil response", provider.Name())
 		}
-
-		enrichments, err := parseEnrichmentResponse(resp.Content, insights)
+		// An unparseable answer is a failure too, so the next provider gets
+		// a chance.
+		enrichments, err = parseEnrichmentResponse(resp.Content, insights)
 		if err != nil {
-			slog.Error("[InsightWorker] failed to parse response", "provider", name, "error", err)
-			continue
+			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
-
-		return enrichments, name, nil
+		resp.Agent = provider.Name()
+		return resp, nil
+	})
+	if err != nil {
+		slog.Error("[InsightWorker] enrichment failed", "error", err)
+		return nil, "", fmt.Errorf("no available AI providers: %w", err)
 	}
-
-	return nil, "", fmt.Errorf("no available AI providers")
+	return enrichments, resp.Agent, nil
 }
 
 // buildProviderOrder returns a provider ordering that places primaryAgent
@@ -0,0 +1,688 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// kubeToolListLimit caps how many items list_resources returns.
+	kubeToolListLimit = 200
+	// kubeToolDefaultLogLines / kubeToolMaxLogLines bound get_pod_logs.
+	kubeToolDefaultLogLines = 200
+	kubeToolMaxLogLines     = 2000
+	// kubeToolDefaultEvents / kubeToolMaxEvents bound get_events.
+	kubeToolDefaultEvents = 50
+	kubeToolMaxEvents     = 500
+	// kubeToolDescribeEvents is how many related events describe_resource adds.
+	kubeToolDescribeEvents = 20
+	// kubeToolMaxReplicas rejects obviously mistaken scale requests.
+	kubeToolMaxReplicas = 1000
+	// kubeToolCallTimeout bounds a single tool call against the apiserver.
+	kubeToolCallTimeout = 30 * time.Second
+
+	// rolloutRestartAnnotation is the pod-template annotation kubectl sets
+	// for "kubectl rollout restart".
+	rolloutRestartAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// kubeKind is a resource kind the tool set can list and fetch. Secrets are
+// deliberately absent: their contents must never be sent to a provider.
+type kubeKind struct {
+	namespaced bool
+	list       func(ctx context.Context, m *k8s.MultiClusterClient, cluster, namespace string) (any, error)
+	get        func(ctx context.Context, cs kubernetes.Interface, namespace, name string) (any, error)
+}
+
+var kubeKinds = map[string]kubeKind{
+	"pod": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetPods(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.CoreV1().Pods(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"deployment": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetDeployments(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.AppsV1().Deployments(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"statefulset": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetStatefulSets(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.AppsV1().StatefulSets(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"daemonset": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetDaemonSets(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.AppsV1().DaemonSets(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"replicaset": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetReplicaSets(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.AppsV1().ReplicaSets(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"job": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetJobs(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.BatchV1().Jobs(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"cronjob": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetCronJobs(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.BatchV1().CronJobs(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"service": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetServices(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.CoreV1().Services(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"ingress": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetIngresses(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.NetworkingV1().Ingresses(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"configmap": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetConfigMaps(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.CoreV1().ConfigMaps(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"persistentvolumeclaim": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetPVCs(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.CoreV1().PersistentVolumeClaims(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"horizontalpodautoscaler": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetHPAs(ctx, c, ns)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.AutoscalingV2().HorizontalPodAutoscalers(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+	"node": {false,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, _ string) (any, error) {
+			return m.GetNodes(ctx, c)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, _, n string) (any, error) {
+			return cs.CoreV1().Nodes().Get(ctx, n, metav1.GetOptions{})
+		}},
+	"persistentvolume": {false,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, _ string) (any, error) {
+			return m.GetPVs(ctx, c)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, _, n string) (any, error) {
+			return cs.CoreV1().PersistentVolumes().Get(ctx, n, metav1.GetOptions{})
+		}},
+	"namespace": {false,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, _ string) (any, error) {
+			cs, err := m.GetClient(c)
+			if err != nil {
+				return nil, err
+			}
+			list, err := cs.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			out := make([]map[string]string, 0, len(list.Items))
+			for _, ns := range list.Items {
+				out = append(out, map[string]string{"name": ns.Name, "phase": string(ns.Status.Phase)})
+			}
+			return out, nil
+		},
+		func(ctx context.Context, cs kubernetes.Interface, _, n string) (any, error) {
+			return cs.CoreV1().Namespaces().Get(ctx, n, metav1.GetOptions{})
+		}},
+	"event": {true,
+		func(ctx context.Context, m *k8s.MultiClusterClient, c, ns string) (any, error) {
+			return m.GetEvents(ctx, c, ns, kubeToolDefaultEvents)
+		},
+		func(ctx context.Context, cs kubernetes.Interface, ns, n string) (any, error) {
+			return cs.CoreV1().Events(ns).Get(ctx, n, metav1.GetOptions{})
+		}},
+}
+
+// kubeKindAliases maps kubectl short names and plurals to kubeKinds keys.
+var kubeKindAliases = map[string]string{
+	"po": "pod", "deploy": "deployment", "sts": "statefulset", "ds": "daemonset",
+	"rs": "replicaset", "cj": "cronjob", "svc": "service", "ing": "ingress",
+	"cm": "configmap", "pvc": "persistentvolumeclaim", "pv": "persistentvolume",
+	"hpa": "horizontalpodautoscaler", "no": "node", "ns": "namespace", "ev": "event",
+	"ingresses": "ingress",
+}
+
+func resolveKubeKind(raw string) (string, kubeKind, error) {
+	kind := strings.ToLower(strings.TrimSpace(raw))
+	if k, ok := kubeKindAliases[kind]; ok {
+		kind = k
+	}
+	if _, ok := kubeKinds[kind]; !ok {
+		kind = strings.TrimSuffix(kind, "s")
+	}
+	if kind == "secret" {
+		return "", kubeKind{}, fmt.Errorf("secrets are not available to the assistant")
+	}
+	k, ok := kubeKinds[kind]
+	if !ok {
+		return "", kubeKind{}, fmt.Errorf("unsupported kind %q (supported: %s)", raw, strings.Join(kubeKindNames(), ", "))
+	}
+	return kind, k, nil
+}
+
+func kubeKindNames() []string {
+	names := make([]string, 0, len(kubeKinds))
+	for name := range kubeKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// kubeTools is the built-in tool set API providers get for native function
+// calling. Reads go through MultiClusterClient (and its informer cache when
+// enabled); scale and restart are the only changes, and both are marked
+// mutating so runToolLoop asks the user first.
+type kubeTools struct {
+	client *k8s.MultiClusterClient
+	// defaultCluster is used when a call omits "cluster", typically the
+	// context the user is viewing.
+	defaultCluster string
+}
+
+func newKubeTools(client *k8s.MultiClusterClient, defaultCluster string) *kubeTools {
+	return &kubeTools{client: client, defaultCluster: defaultCluster}
+}
+
+func kubeToolSchema(required []string, props map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func kubeToolProp(typ, description string) map[string]any {
+	return map[string]any{"type": typ, "description": description}
+}
+
+func (t *kubeTools) Definitions() []ToolDefinition {
+	cluster := kubeToolProp("string", "Kubeconfig context of the cluster; defaults to the cluster the user is viewing")
+	namespace := kubeToolProp("string", "Namespace; omit for all namespaces or cluster-scoped kinds")
+	kind := kubeToolProp("string", "Resource kind, e.g. pod, deployment, service, node ("+strings.Join(kubeKindNames(), ", ")+")")
+	name := kubeToolProp("string", "Resource name")
+	workloadKind := map[string]any{"type": "string", "description": "Workload kind", "enum": []string{"deployment", "statefulset", "daemonset"}}
+	return []ToolDefinition{
+		{
+			Name:        "list_clusters",
+			Description: "List the Kubernetes clusters (kubeconfig contexts) the console can reach, with health.",
+			Parameters:  kubeToolSchema(nil, map[string]any{}),
+		},
+		{
+			Name:        "list_resources",
+			Description: "List resources of one kind with a status summary.",
+			Parameters:  kubeToolSchema([]string{"kind"}, map[string]any{"cluster": cluster, "kind": kind, "namespace": namespace}),
+		},
+		{
+			Name:        "get_resource",
+			Description: "Get the full spec and status of one resource as JSON.",
+			Parameters:  kubeToolSchema([]string{"kind", "name"}, map[string]any{"cluster": cluster, "kind": kind, "namespace": namespace, "name": name}),
+		},
+		{
+			Name:        "describe_resource",
+			Description: "Describe one resource: its metadata and status plus recent events about it, like kubectl describe.",
+			Parameters:  kubeToolSchema([]string{"kind", "name"}, map[string]any{"cluster": cluster, "kind": kind, "namespace": namespace, "name": name}),
+		},
+		{
+			Name:        "get_pod_logs",
+			Description: "Get the most recent log lines of a pod container.",
+			Parameters: kubeToolSchema([]string{"namespace", "pod"}, map[string]any{
+				"cluster":   cluster,
+				"namespace": kubeToolProp("string", "Pod namespace"),
+				"pod":       kubeToolProp("string", "Pod name"),
+				"container": kubeToolProp("string", "Container name; required when the pod has several"),
+				"tailLines": kubeToolProp("integer", fmt.Sprintf("Number of lines from the end (default %d, max %d)", kubeToolDefaultLogLines, kubeToolMaxLogLines)),
+			}),
+		},
+		{
+			Name:        "get_events",
+			Description: "List recent cluster events, newest first.",
+			Parameters: kubeToolSchema(nil, map[string]any{
+				"cluster":      cluster,
+				"namespace":    namespace,
+				"warningsOnly": kubeToolProp("boolean", "Only return Warning events"),
+				"limit":        kubeToolProp("integer", fmt.Sprintf("Maximum events (default %d, max %d)", kubeToolDefaultEvents, kubeToolMaxEvents)),
+			}),
+		},
+		{
+			Name:        "scale_workload",
+			Description: "Set the replica count of a deployment or statefulset. Requires user approval.",
+			Parameters: kubeToolSchema([]string{"namespace", "kind", "name", "replicas"}, map[string]any{
+				"cluster":   cluster,
+				"namespace": kubeToolProp("string", "Workload namespace"),
+				"kind":      map[string]any{"type": "string", "enum": []string{"deployment", "statefulset"}},
+				"name":      name,
+				"replicas":  kubeToolProp("integer", "Desired replica count"),
+			}),
+			Mutating: true,
+		},
+		{
+			Name:        "restart_workload",
+			Description: "Rolling-restart a deployment, statefulset or daemonset (kubectl rollout restart). Requires user approval.",
+			Parameters: kubeToolSchema([]string{"namespace", "kind", "name"}, map[string]any{
+				"cluster":   cluster,
+				"namespace": kubeToolProp("string", "Workload namespace"),
+				"kind":      workloadKind,
+				"name":      name,
+			}),
+			Mutating: true,
+		},
+	}
+}
+
+func (t *kubeTools) Summarize(call ToolCall) string {
+	args := kubeToolArgs(call.Input)
+	target := fmt.Sprintf("%s %s/%s in cluster %s", args.str("kind"), args.str("namespace"), args.str("name"), t.cluster(args))
+	switch call.Name {
+	case "scale_workload":
+		return fmt.Sprintf("Scale %s to %d replicas", target, args.int("replicas", -1))
+	case "restart_workload":
+		return "Rolling restart of " + target
+	}
+	return call.Name
+}
+
+func (t *kubeTools) Execute(ctx context.Context, call ToolCall) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, kubeToolCallTimeout)
+	defer cancel()
+	args := kubeToolArgs(call.Input)
+	switch call.Name {
+	case "list_clusters":
+		return t.listClusters(ctx)
+	case "list_resources":
+		return t.listResources(ctx, args)
+	case "get_resource":
+		return t.getResource(ctx, args)
+	case "describe_resource":
+		return t.describeResource(ctx, args)
+	case "get_pod_logs":
+		return t.podLogs(ctx, args)
+	case "get_events":
+		return t.events(ctx, args)
+	case "scale_workload":
+		return t.scale(ctx, args)
+	case "restart_workload":
+		return t.restart(ctx, args)
+	}
+	return "", fmt.Errorf("unknown tool %q", call.Name)
+}
+
+func (t *kubeTools) cluster(args kubeToolArgs) string {
+	if c := args.str("cluster"); c != "" {
+		return c
+	}
+	return t.defaultCluster
+}
+
+func (t *kubeTools) requireCluster(args kubeToolArgs) (string, error) {
+	c := t.cluster(args)
+	if c == "" {
+		return "", fmt.Errorf("cluster is required; call list_clusters to see the available contexts")
+	}
+	return c, nil
+}
+
+func (t *kubeTools) listClusters(ctx context.Context) (string, error) {
+	clusters, err := t.client.ListClusters(ctx)
+	if err != nil {
+		return "", err
+	}
+	type entry struct {
+		Context string `json:"context"`
+		Server  string `json:"server,omitempty"`
+		Healthy bool   `json:"healthy"`
+		Current bool   `json:"current,omitempty"`
+		Nodes   int    `json:"nodes,omitempty"`
+		Pods    int    `json:"pods,omitempty"`
+	}
+	out := make([]entry, 0, len(clusters))
+	for _, c := range clusters {
+		ctxName := c.Context
+		if ctxName == "" {
+			ctxName = c.Name
+		}
+		out = append(out, entry{Context: ctxName, Server: c.Server, Healthy: c.Healthy, Current: c.IsCurrent, Nodes: c.NodeCount, Pods: c.PodCount})
+	}
+	return kubeToolJSON(out)
+}
+
+func (t *kubeTools) listResources(ctx context.Context, args kubeToolArgs) (string, error) {
+	cluster, err := t.requireCluster(args)
+	if err != nil {
+		return "", err
+	}
+	_, kind, err := resolveKubeKind(args.str("kind"))
+	if err != nil {
+		return "", err
+	}
+	items, err := kind.list(ctx, t.client, cluster, args.str("namespace"))
+	if err != nil {
+		return "", err
+	}
+	return kubeToolJSONList(items)
+}
+
+// fetch resolves the kind and name in args and returns the typed object.
+func (t *kubeTools) fetch(ctx context.Context, args kubeToolArgs) (string, string, any, error) {
+	cluster, err := t.requireCluster(args)
+	if err != nil {
+		return "", "", nil, err
+	}
+	kindName, kind, err := resolveKubeKind(args.str("kind"))
+	if err != nil {
+		return "", "", nil, err
+	}
+	name := args.str("name")
+	if name == "" {
+		return "", "", nil, fmt.Errorf("name is required")
+	}
+	namespace := args.str("namespace")
+	if kind.namespaced && namespace == "" {
+		namespace = "default"
+	}
+	cs, err := t.client.GetClient(cluster)
+	if err != nil {
+		return "", "", nil, err
+	}
+	obj, err := kind.get(ctx, cs, namespace, name)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if o, ok := obj.(metav1.Object); ok {
+		o.SetManagedFields(nil)
+		if ann := o.GetAnnotations(); ann != nil {
+			delete(ann, "kubectl.kubernetes.io/last-applied-configuration")
+		}
+	}
+	return cluster, kindName, obj, nil
+}
+
+func (t *kubeTools) getResource(ctx context.Context, args kubeToolArgs) (string, error) {
+	_, _, obj, err := t.fetch(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	return kubeToolJSON(obj)
+}
+
+func (t *kubeTools) describeResource(ctx context.Context, args kubeToolArgs) (string, error) {
+	cluster, kindName, obj, err := t.fetch(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	// Keep metadata and status; the spec is available through get_resource.
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	var full map[string]any
+	if err := json.Unmarshal(raw, &full); err != nil {
+		return "", err
+	}
+	desc := map[string]any{"kind": kindName, "metadata": full["metadata"], "status": full["status"]}
+	if kindName == "configmap" {
+		// Show which keys exist, not their values.
+		keys := make([]string, 0)
+		if data, ok := full["data"].(map[string]any); ok {
+			for k := range data {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		desc["dataKeys"] = keys
+	}
+
+	if o, ok := obj.(metav1.Object); ok && kindName != "event" {
+		selector := "involvedObject.name=" + o.GetName()
+		events, evErr := t.client.GetEvents(ctx, cluster, o.GetNamespace(), kubeToolDescribeEvents, selector)
+		if evErr != nil {
+			desc["eventsError"] = evErr.Error()
+		} else {
+			desc["events"] = events
+		}
+	}
+	return kubeToolJSON(desc)
+}
+
+func (t *kubeTools) podLogs(ctx context.Context, args kubeToolArgs) (string, error) {
+	cluster, err := t.requireCluster(args)
+	if err != nil {
+		return "", err
+	}
+	namespace, pod := args.str("namespace"), args.str("pod")
+	if namespace == "" || pod == "" {
+		return "", fmt.Errorf("namespace and pod are required")
+	}
+	lines := args.int("tailLines", kubeToolDefaultLogLines)
+	if lines <= 0 {
+		lines = kubeToolDefaultLogLines
+	}
+	if lines > kubeToolMaxLogLines {
+		lines = kubeToolMaxLogLines
+	}
+	return t.client.GetPodLogs(ctx, cluster, namespace, pod, args.str("container"), int64(lines))
+}
+
+func (t *kubeTools) events(ctx context.Context, args kubeToolArgs) (string, error) {
+	cluster, err := t.requireCluster(args)
+	if err != nil {
+		return "", err
+	}
+	limit := args.int("limit", kubeToolDefaultEvents)
+	if limit <= 0 {
+		limit = kubeToolDefaultEvents
+	}
+	if limit > kubeToolMaxEvents {
+		limit = kubeToolMaxEvents
+	}
+	var events []k8s.Event
+	if args.bool("warningsOnly") {
+		events, err = t.client.GetWarningEvents(ctx, cluster, args.str("namespace"), limit)
+	} else {
+		events, err = t.client.GetEvents(ctx, cluster, args.str("namespace"), limit)
+	}
+	if err != nil {
+		return "", err
+	}
+	return kubeToolJSON(events)
+}
+
+// workloadTarget validates the arguments shared by the mutating tools.
+func (t *kubeTools) workloadTarget(args kubeToolArgs) (kubernetes.Interface, string, string, string, error) {
+	cluster, err := t.requireCluster(args)
+	if err != nil {
+		return nil, "", "", "", err
+	}
+	namespace, name := args.str("namespace"), args.str("name")
+	if namespace == "" || name == "" {
+		return nil, "", "", "", fmt.Errorf("namespace and name are required")
+	}
+	kind, _, err := resolveKubeKind(args.str("kind"))
+	if err != nil {
+		return nil, "", "", "", err
+	}
+	cs, err := t.client.GetClient(cluster)
+	if err != nil {
+		return nil, "", "", "", err
+	}
+	return cs, kind, namespace, name, nil
+}
+
+func (t *kubeTools) scale(ctx context.Context, args kubeToolArgs) (string, error) {
+	cs, kind, namespace, name, err := t.workloadTarget(args)
+	if err != nil {
+		return "", err
+	}
+	replicas := args.int("replicas", -1)
+	if replicas < 0 || replicas > kubeToolMaxReplicas {
+		return "", fmt.Errorf("replicas must be between 0 and %d", kubeToolMaxReplicas)
+	}
+	r := int32(replicas)
+	var previous int32
+	switch kind {
+	case "deployment":
+		d, err := cs.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		if d.Spec.Replicas != nil {
+			previous = *d.Spec.Replicas
+		}
+		d.Spec.Replicas = &r
+		if _, err := cs.AppsV1().Deployments(namespace).Update(ctx, d, metav1.UpdateOptions{}); err != nil {
+			return "", err
+		}
+	case "statefulset":
+		s, err := cs.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		if s.Spec.Replicas != nil {
+			previous = *s.Spec.Replicas
+		}
+		s.Spec.Replicas = &r
+		if _, err := cs.AppsV1().StatefulSets(namespace).Update(ctx, s, metav1.UpdateOptions{}); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("cannot scale a %s", kind)
+	}
+	return fmt.Sprintf("scaled %s %s/%s from %d to %d replicas", kind, namespace, name, previous, r), nil
+}
+
+func (t *kubeTools) restart(ctx context.Context, args kubeToolArgs) (string, error) {
+	cs, kind, namespace, name, err := t.workloadTarget(args)
+	if err != nil {
+		return "", err
+	}
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"template": map[string]any{"metadata": map[string]any{
+			"annotations": map[string]string{rolloutRestartAnnotation: time.Now().UTC().Format(time.RFC3339)},
+		}}},
+	})
+	if err != nil {
+		return "", err
+	}
+	switch kind {
+	case "deployment":
+		_, err = cs.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "statefulset":
+		_, err = cs.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "daemonset":
+		_, err = cs.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return "", fmt.Errorf("cannot restart a %s", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("restarted %s %s/%s", kind, namespace, name), nil
+}
+
+// kubeToolArgs reads loosely typed JSON arguments from the model.
+type kubeToolArgs map[string]any
+
+func (a kubeToolArgs) str(key string) string {
+	switch v := a[key].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (a kubeToolArgs) int(key string, def int) int {
+	switch v := a[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		var n int
+		if _, err := fmt.Sscan(v, &n); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func (a kubeToolArgs) bool(key string) bool {
+	switch v := a[key].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+func kubeToolJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// kubeToolJSONList marshals a slice, keeping at most kubeToolListLimit items.
+func kubeToolJSONList(items any) (string, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return string(data), nil
+	}
+	if len(list) <= kubeToolListLimit {
+		return string(data), nil
+	}
+	data, err = json.Marshal(map[string]any{
+		"items":     list[:kubeToolListLimit],
+		"total":     len(list),
+		"truncated": true,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestKubeTools(t *testing.T) (*kubeTools, *fake.Clientset) {
+	t.Helper()
+	replicas := int32(2)
+	cs := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "shop"},
+			Data: map[string]string{"password": "hunter2"}},
+	)
+	m, _ := k8s.NewMultiClusterClient("")
+	m.InjectClient("c1", cs)
+	return newKubeTools(m, "c1"), cs
+}
+
+func TestKubeTools_Definitions(t *testing.T) {
+	tools, _ := newTestKubeTools(t)
+	mutating := map[string]bool{}
+	for _, d := range tools.Definitions() {
+		mutating[d.Name] = d.Mutating
+		if d.Parameters["type"] != "object" {
+			t.Errorf("%s: parameters must be an object schema", d.Name)
+		}
+	}
+	for _, name := range []string{"list_resources", "get_resource", "describe_resource", "get_pod_logs", "get_events"} {
+		if m, ok := mutating[name]; !ok || m {
+			t.Errorf("%s should be a read-only tool", name)
+		}
+	}
+	for _, name := range []string{"scale_workload", "restart_workload"} {
+		if !mutating[name] {
+			t.Errorf("%s should be mutating", name)
+		}
+	}
+}
+
+func TestKubeTools_ReadTools(t *testing.T) {
+	tools, _ := newTestKubeTools(t)
+	ctx := context.Background()
+
+	out, err := tools.Execute(ctx, ToolCall{Name: "list_resources", Input: map[string]any{"kind": "pods", "namespace": "shop"}})
+	if err != nil || !strings.Contains(out, "web-1") {
+		t.Errorf("list_resources = %q, %v", out, err)
+	}
+
+	out, err = tools.Execute(ctx, ToolCall{Name: "get_resource", Input: map[string]any{"kind": "deploy", "namespace": "shop", "name": "web"}})
+	if err != nil || !strings.Contains(out, `"web"`) {
+		t.Fatalf("get_resource = %q, %v", out, err)
+	}
+	if strings.Contains(out, "managedFields") {
+		t.Error("get_resource should strip managedFields")
+	}
+
+	out, err = tools.Execute(ctx, ToolCall{Name: "describe_resource", Input: map[string]any{"kind": "configmap", "namespace": "shop", "name": "cfg"}})
+	if err != nil || !strings.Contains(out, "dataKeys") || !strings.Contains(out, "password") {
+		t.Fatalf("describe_resource = %q, %v", out, err)
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Error("describe_resource must not expose configmap values")
+	}
+
+	if _, err := tools.Execute(ctx, ToolCall{Name: "get_resource", Input: map[string]any{"kind": "secret", "namespace": "shop", "name": "x"}}); err == nil {
+		t.Error("secrets must be refused")
+	}
+}
+
+func TestKubeTools_ScaleAndRestart(t *testing.T) {
+	tools, cs := newTestKubeTools(t)
+	ctx := context.Background()
+
+	call := ToolCall{Name: "scale_workload", Input: map[string]any{"kind": "deployment", "namespace": "shop", "name": "web", "replicas": float64(5)}}
+	if got := tools.Summarize(call); got != "Scale deployment shop/web in cluster c1 to 5 replicas" {
+		t.Errorf("Summarize = %q", got)
+	}
+	if _, err := tools.Execute(ctx, call); err != nil {
+		t.Fatalf("scale_workload: %v", err)
+	}
+	d, _ := cs.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	if *d.Spec.Replicas != 5 {
+		t.Errorf("replicas = %d, want 5", *d.Spec.Replicas)
+	}
+
+	call.Input["replicas"] = float64(kubeToolMaxReplicas + 1)
+	if _, err := tools.Execute(ctx, call); err == nil {
+		t.Error("expected out-of-range replicas to be rejected")
+	}
+
+	if _, err := tools.Execute(ctx, ToolCall{Name: "restart_workload", Input: map[string]any{"kind": "deployment", "namespace": "shop", "name": "web"}}); err != nil {
+		t.Fatalf("restart_workload: %v", err)
+	}
+	d, _ = cs.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	if d.Spec.Template.Annotations[rolloutRestartAnnotation] == "" {
+		t.Error("restart should stamp the pod template")
+	}
+
+	if _, err := tools.Execute(ctx, ToolCall{Name: "restart_workload", Input: map[string]any{"kind": "pod", "namespace": "shop", "name": "web-1"}}); err == nil {
+		t.Error("expected restarting a pod to be rejected")
+	}
+}
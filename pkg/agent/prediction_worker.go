@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/k8s"
 	"github.com/kubestellar/console/pkg/safego"
 )
@@ -365,25 +366,30 @@ func (w *PredictionWorker) runAnalysis(specificProviders []string) {
 	maxPredictions := w.settings.MaxPredictions
 	w.mu.RUnlock()
 
-	for _, providerName := range providers {
-		provider, err := w.registry.Get(providerName)
-		if err != nil || !provider.IsAvailable() {
-			continue
-		}
-
-		predictions, err := w.analyzeWithProvider(ctx, provider, prompt)
+	// Without consensus the router walks the predictions chain and stops at
+	// the first success. Consensus asks every provider, so each request is
+	// pinned to one provider; budgets and breakers still apply to each.
+	analyze := func(req airouter.Request) {
+		var predictions []AIPrediction
+		resp, err := w.registry.Route(ctx, req, func(ctx context.Context, provider AIProvider) (*ChatResponse, error) {
+			var resp *ChatResponse
+			var err error
+			predictions, resp, err = w.analyzeWithProvider(ctx, provider, prompt)
+			return resp, err
+		})
 		if err != nil {
-			slog.Error("[PredictionWorker] provider error", "provider", providerName, "error", err)
-			continue
+			slog.Error("[PredictionWorker] provider error", "provider", req.Preferred, "error", err)
+			return
 		}
-
-		allPredictions[providerName] = predictions
-		usedProviders = append(usedProviders, providerName)
-
-		// If not in consensus mode, use first successful provider
-		if !consensusMode {
-			break
+		allPredictions[resp.Agent] = predictions
+		usedProviders = append(usedProviders, resp.Agent)
+	}
+	if consensusMode {
+		for _, providerName := range providers {
+			analyze(airouter.Request{Category: airouter.CategoryPredictions, Preferred: providerName, Pinned: true})
 		}
+	} else {
+		analyze(airouter.Request{Category: airouter.CategoryPredictions, Preferred: providers[0], Fallback: providers})
 	}
 
 	// Merge predictions
@@ -677,7 +683,9 @@ func (w *PredictionWorker) getAvailableProviders() []string {
 	return providers
 }
 
-func (w *PredictionWorker) analyzeWithProvider(ctx context.Context, provider AIProvider, prompt string) ([]AIPrediction, error) {
+// analyzeWithProvider asks one provider for predictions. An unparseable
+// answer is returned as an error so the router can try the next provider.
+func (w *PredictionWorker) analyzeWithProvider(ctx context.Context, provider AIProvider, prompt string) ([]AIPrediction, *ChatResponse, error) {
 	// Use the provider's chat interface
 	req := &ChatRequest{
 		SessionID: fmt.Sprintf("prediction-%d", time.Now().Unix()),
@@ -686,11 +694,12 @@ func (w *PredictionWorker) analyzeWithProvider(ctx context.Context, provider AIP
 
 	resp, err := provider.Chat(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if resp == nil {
-		return nil, fmt.Errorf("provider %s returned nil response", provider.Name())
+		return nil, nil, fmt.Errorf("provider %s returned nil response", provider.Name())
 	}
+	resp.Agent = provider.Name()
 
 	// Track token usage for navbar counter
 	if w.trackTokens != nil && resp.TokenUsage != nil {
@@ -698,7 +707,11 @@ func (w *PredictionWorker) analyzeWithProvider(ctx context.Context, provider AIP
 	}
 
 	// Parse response
-	return w.parseAIPredictions(resp.Content, provider.Name())
+	predictions, err := w.parseAIPredictions(resp.Content, provider.Name())
+	if err != nil {
+		return nil, resp, err
+	}
+	return predictions, resp, nil
 }
 
 func (w *PredictionWorker) parseAIPredictions(response string, providerName string) ([]AIPrediction, error) {
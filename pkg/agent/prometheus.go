@@ -1,13 +1,16 @@
 package agent
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -124,20 +127,13 @@ func (s *Server) handlePrometheusQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build the K8s API server proxy URL to reach Prometheus
-	proxyPath := fmt.Sprintf("/api/v1/namespaces/%s/services/%s:%s/proxy/api/v1/query",
-		url.PathEscape(namespace),
-		url.PathEscape(serviceName),
-		prometheusServicePort,
-	)
-
 	params := url.Values{}
 	params.Set("query", query)
 	if queryTime != "" {
 		params.Set("time", queryTime)
 	}
 
-	fullURL := fmt.Sprintf("%s%s?%s", config.Host, proxyPath, params.Encode())
+	fullURL := prometheusQueryURL(config, namespace, serviceName, params)
 
 	// Reuse an HTTP client per cluster API server URL to avoid creating a new
 	// TLS transport (and leaking connections) on every query (#7024).
@@ -165,6 +161,126 @@ func (s *Server) handlePrometheusQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// prometheusQueryURL builds the K8s API server proxy URL of a Prometheus
+// service's instant query endpoint.
+func prometheusQueryURL(config *rest.Config, namespace, service string, params url.Values) string {
+	proxyPath := fmt.Sprintf("/api/v1/namespaces/%s/services/%s:%s/proxy/api/v1/query",
+		url.PathEscape(namespace),
+		url.PathEscape(service),
+		prometheusServicePort,
+	)
+	return fmt.Sprintf("%s%s?%s", config.Host, proxyPath, params.Encode())
+}
+
+// PromSample is one series of a Prometheus instant query result.
+type PromSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// QueryPrometheus runs a PromQL instant query against a Prometheus service
+// through the cluster's API server proxy, the same path handlePrometheusQuery
+// serves to the browser, and returns the vector or scalar result. An empty
+// service selects "prometheus".
+func QueryPrometheus(ctx context.Context, config *rest.Config, namespace, service, query string) ([]PromSample, error) {
+	if config == nil {
+		return nil, fmt.Errorf("prometheus: rest config is nil")
+	}
+	if service == "" {
+		service = prometheusServiceName
+	}
+	if err := validateDNS1123Label("namespace", namespace); err != nil {
+		return nil, err
+	}
+	if err := validateDNS1123Label("service", service); err != nil {
+		return nil, err
+	}
+	if query == "" || len(query) > maxPromQLQueryLength {
+		return nil, fmt.Errorf("prometheus: query must be 1-%d characters", maxPromQLQueryLength)
+	}
+
+	client, err := getOrCreatePromClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus: get http client: %w", err)
+	}
+	params := url.Values{}
+	params.Set("query", query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, prometheusQueryURL(config, namespace, service, params), nil)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus: build request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus: query %s/%s: %w", namespace, service, err)
+	}
+	defer resp.Body.Close()
+	return parsePromQueryResponse(io.LimitReader(resp.Body, prometheusMaxResponseBytes))
+}
+
+// parsePromQueryResponse decodes a Prometheus /api/v1/query response body.
+func parsePromQueryResponse(r io.Reader) ([]PromSample, error) {
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("prometheus: decode response: %w", err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("prometheus: query failed: %s", body.Error)
+	}
+
+	switch body.Data.ResultType {
+	case "vector":
+		var series []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &series); err != nil {
+			return nil, fmt.Errorf("prometheus: decode vector: %w", err)
+		}
+		samples := make([]PromSample, 0, len(series))
+		for _, s := range series {
+			v, err := promSampleValue(s.Value)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, PromSample{Labels: s.Metric, Value: v})
+		}
+		return samples, nil
+	case "scalar":
+		var value [2]interface{}
+		if err := json.Unmarshal(body.Data.Result, &value); err != nil {
+			return nil, fmt.Errorf("prometheus: decode scalar: %w", err)
+		}
+		v, err := promSampleValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return []PromSample{{Labels: map[string]string{}, Value: v}}, nil
+	default:
+		return nil, fmt.Errorf("prometheus: unsupported result type %q", body.Data.ResultType)
+	}
+}
+
+// promSampleValue parses the [timestamp, "value"] pair Prometheus uses for
+// sample values.
+func promSampleValue(pair [2]interface{}) (float64, error) {
+	s, ok := pair[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("prometheus: sample value is not a string")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("prometheus: parse sample value: %w", err)
+	}
+	return v, nil
+}
+
 // getOrCreatePromClient returns a cached http.Client for the given REST config,
 // keyed by the API server Host URL. Clients are created once and reused to
 // avoid per-query TLS handshakes and connection leaks (#7024).
@@ -109,3 +109,32 @@ func TestPromCacheKey(t *testing.T) {
 		t.Error("Keys for different hosts should differ")
 	}
 }
+
+func TestParsePromQueryResponse(t *testing.T) {
+	vector := `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"pod":"a"},"value":[1700000000,"2.5"]},
+		{"metric":{"pod":"b"},"value":[1700000000,"0"]}]}}`
+	samples, err := parsePromQueryResponse(strings.NewReader(vector))
+	if err != nil {
+		t.Fatalf("vector: %v", err)
+	}
+	if len(samples) != 2 || samples[0].Labels["pod"] != "a" || samples[0].Value != 2.5 {
+		t.Errorf("vector samples = %+v", samples)
+	}
+
+	scalar := `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"7"]}}`
+	samples, err = parsePromQueryResponse(strings.NewReader(scalar))
+	if err != nil {
+		t.Fatalf("scalar: %v", err)
+	}
+	if len(samples) != 1 || samples[0].Value != 7 {
+		t.Errorf("scalar samples = %+v", samples)
+	}
+
+	if _, err := parsePromQueryResponse(strings.NewReader(`{"status":"error","error":"bad query"}`)); err == nil {
+		t.Error("expected error for failed query")
+	}
+	if _, err := parsePromQueryResponse(strings.NewReader(`{"status":"success","data":{"resultType":"matrix","result":[]}}`)); err == nil {
+		t.Error("expected error for matrix result")
+	}
+}
@@ -14,6 +14,7 @@ const (
 	TypeSelectAgent   MessageType = "select_agent"   // Select an AI agent
 	TypeCancelChat    MessageType = "cancel_chat"    // Cancel in-progress chat
 	TypeRenameContext MessageType = "rename_context"
+	TypeToolApproval  MessageType = "tool_approval" // Approve or deny a mutating tool call
 
 	// Response types
 	TypeResult        MessageType = "result"
@@ -29,6 +30,9 @@ const (
 	TypeMixedModeThinking  MessageType = "mixed_mode_thinking"  // Thinking agent phase indicator
 	TypeMixedModeExecuting MessageType = "mixed_mode_executing" // Execution agent phase indicator
 
+	// Native tool-calling types
+	TypeToolApprovalRequest MessageType = "tool_approval_request" // A mutating tool call awaits user approval
+
 	// Integrity & Sync types
 	TypeStateDigest MessageType = "state_digest" // Server-side state integrity digest (#12000)
 )
@@ -206,6 +210,13 @@ type ChatRequest struct {
 	// prompt-level dry-run instructions. Read-only commands (get, describe,
 	// logs, etc.) remain allowed. (#6442)
 	DryRun bool `json:"dryRun,omitempty"`
+	// Category is the token-budget category of the request (missions,
+	// diagnose, insights, predictions or other). Empty means missions.
+	Category string `json:"category,omitempty"`
+	// ToolApproval declares that the client answers TypeToolApprovalRequest
+	// messages. Without it, native tool calling offers read-only tools only,
+	// so a chat never waits on an approval nobody can give.
+	ToolApproval bool `json:"toolApproval,omitempty"`
 }
 
 // ChatStreamPayload is a streaming response chunk from chat
@@ -234,6 +245,25 @@ type ProgressPayload struct {
 	Output string         `json:"output,omitempty"` // Tool output (truncated)
 }
 
+// ToolApprovalRequestPayload asks the user to approve a mutating tool call
+// requested by an API provider's native function calling. The client answers
+// with a TypeToolApproval message carrying the same ApprovalID.
+type ToolApprovalRequestPayload struct {
+	ApprovalID string         `json:"approvalId"`
+	SessionID  string         `json:"sessionId"`
+	Agent      string         `json:"agent"`
+	Tool       string         `json:"tool"`
+	Input      map[string]any `json:"input,omitempty"`
+	Summary    string         `json:"summary"`             // Human-readable description of the change
+	ExpiresIn  int            `json:"expiresIn,omitempty"` // Seconds before the call is denied automatically
+}
+
+// ToolApprovalResponse is the client's answer to a ToolApprovalRequestPayload
+type ToolApprovalResponse struct {
+	ApprovalID string `json:"approvalId"`
+	Approved   bool   `json:"approved"`
+}
+
 // ProviderCheckResponse is returned by the /provider/check endpoint.
 // It tells the frontend whether a provider is ready and what is missing.
 type ProviderCheckResponse struct {
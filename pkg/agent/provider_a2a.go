@@ -0,0 +1,271 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/a2a"
+)
+
+const (
+	// a2aProviderPrefix namespaces A2A agents among the registry's providers.
+	a2aProviderPrefix        = "a2a-"
+	a2aDiscoverTimeout       = 3 * time.Second
+	a2aAvailabilityTimeout   = 1200 * time.Millisecond
+	a2aRediscoverInterval    = 30 * time.Second
+	a2aMaxTrackedSessions    = 1000
+	a2aMaxHistoryTranscribed = 20
+)
+
+// A2AProvider exposes one Agent2Agent agent (configured via A2A_AGENT_URLS)
+// as an AI provider. Agents keep conversation state themselves, so the
+// provider maps each chat session to the agent's context and any task
+// waiting for input.
+type A2AProvider struct {
+	name     string
+	endpoint a2a.Endpoint
+	client   *a2a.Client
+
+	mu            sync.Mutex
+	card          *a2a.AgentCard
+	lastDiscover  time.Time
+	conversations map[string]a2aConversation // by ChatRequest.SessionID
+}
+
+type a2aConversation struct {
+	contextID string
+	taskID    string // task waiting for input, if any
+}
+
+var _ AIProvider = (*A2AProvider)(nil)
+var _ StreamingProvider = (*A2AProvider)(nil)
+var _ HandshakeProvider = (*A2AProvider)(nil)
+
+// NewA2AProvidersFromEnv returns a provider for every agent listed in
+// A2A_AGENT_URLS, discovering their cards concurrently.
+func NewA2AProvidersFromEnv() []*A2AProvider {
+	endpoints := a2a.EndpointsFromEnv()
+	providers := make([]*A2AProvider, len(endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			providers[i] = NewA2AProvider(ep, a2a.ClientOptionsFromEnv()...)
+		}()
+	}
+	wg.Wait()
+
+	// Two agents with the same card name still need distinct provider names.
+	seen := make(map[string]int)
+	for _, p := range providers {
+		seen[p.name]++
+		if n := seen[p.name]; n > 1 {
+			p.name = fmt.Sprintf("%s-%d", p.name, n)
+		}
+	}
+	return providers
+}
+
+// NewA2AProvider creates a provider for one agent. The agent card is
+// fetched now to name the provider and retried later if the agent is down.
+func NewA2AProvider(ep a2a.Endpoint, opts ...a2a.Option) *A2AProvider {
+	p := &A2AProvider{
+		endpoint:      ep,
+		client:        a2a.NewClient(ep.URL, opts...),
+		conversations: make(map[string]a2aConversation),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a2aDiscoverTimeout)
+	defer cancel()
+	card, _ := p.discover(ctx)
+	p.name = a2aProviderPrefix + ep.DefaultName(card)
+	return p
+}
+
+// discover fetches the agent card and caches it.
+func (p *A2AProvider) discover(ctx context.Context) (*a2a.AgentCard, error) {
+	card, err := p.client.Discover(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastDiscover = time.Now()
+	if err != nil {
+		return nil, err
+	}
+	p.card = card
+	return card, nil
+}
+
+// agentCard returns the cached card, rediscovering at most every
+// a2aRediscoverInterval while the agent is unreachable.
+func (p *A2AProvider) agentCard(ctx context.Context) *a2a.AgentCard {
+	p.mu.Lock()
+	card, last := p.card, p.lastDiscover
+	p.mu.Unlock()
+	if card != nil || time.Since(last) < a2aRediscoverInterval {
+		return card
+	}
+	card, _ = p.discover(ctx)
+	return card
+}
+
+func (p *A2AProvider) cachedCard() *a2a.AgentCard {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.card
+}
+
+func (p *A2AProvider) Name() string {
+	return p.name
+}
+
+func (p *A2AProvider) DisplayName() string {
+	if card := p.cachedCard(); card != nil {
+		return card.Name + " (A2A)"
+	}
+	return p.name
+}
+
+func (p *A2AProvider) Description() string {
+	card := p.cachedCard()
+	if card == nil {
+		return fmt.Sprintf("A2A agent at %s", p.endpoint.URL)
+	}
+	if card.Description != "" {
+		return card.Description
+	}
+	return fmt.Sprintf("A2A agent %s at %s", card.Name, p.endpoint.URL)
+}
+
+func (p *A2AProvider) Provider() string {
+	if card := p.cachedCard(); card != nil && card.Provider != nil && card.Provider.Organization != "" {
+		return card.Provider.Organization
+	}
+	return "a2a"
+}
+
+func (p *A2AProvider) IsAvailable() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), a2aAvailabilityTimeout)
+	defer cancel()
+	return p.agentCard(ctx) != nil
+}
+
+// Capabilities is chat only: an arbitrary agent's tools are its own, so
+// missions that must run cluster commands are not routed to it.
+func (p *A2AProvider) Capabilities() ProviderCapability {
+	return CapabilityChat
+}
+
+func (p *A2AProvider) Handshake(ctx context.Context) *HandshakeResult {
+	card, err := p.discover(ctx)
+	if err != nil {
+		return &HandshakeResult{
+			Ready:   false,
+			State:   "failed",
+			Message: fmt.Sprintf("Cannot reach A2A agent at %s: %v", p.endpoint.URL, err),
+		}
+	}
+	return &HandshakeResult{
+		Ready:   true,
+		State:   "connected",
+		Message: fmt.Sprintf("Connected to A2A agent %s at %s", card.Name, p.endpoint.URL),
+		Version: card.Version,
+	}
+}
+
+func (p *A2AProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	return p.StreamChat(ctx, req, nil)
+}
+
+func (p *A2AProvider) StreamChat(ctx context.Context, req *ChatRequest, onChunk func(chunk string)) (*ChatResponse, error) {
+	return p.StreamChatWithProgress(ctx, req, onChunk, nil)
+}
+
+func (p *A2AProvider) StreamChatWithProgress(ctx context.Context, req *ChatRequest, onChunk func(chunk string), onProgress func(event StreamEvent)) (*ChatResponse, error) {
+	card := p.agentCard(ctx)
+	if card == nil {
+		return nil, fmt.Errorf("A2A agent at %s is not reachable", p.endpoint.URL)
+	}
+
+	conv := p.conversation(req.SessionID)
+	msg := a2a.NewTextMessage(p.buildPrompt(req, conv.contextID != ""), conv.contextID, conv.taskID)
+	opts := a2a.RunOptions{Stream: card.Capabilities.Streaming, OnText: onChunk}
+	if onProgress != nil {
+		opts.OnStatus = func(state a2a.TaskState, text string) {
+			onProgress(StreamEvent{Type: "thinking", Output: text})
+		}
+	}
+
+	reply, err := p.client.Run(ctx, a2a.MessageSendParams{
+		Message:       msg,
+		Configuration: &a2a.MessageSendConfiguration{AcceptedOutputModes: []string{"text", "text/plain"}},
+	}, opts)
+	if err != nil {
+		// A failed task cannot be continued; start over next time.
+		p.setConversation(req.SessionID, a2aConversation{contextID: conv.contextID})
+		return nil, err
+	}
+
+	next := a2aConversation{contextID: reply.ContextID}
+	if reply.State == a2a.TaskStateInputRequired {
+		next.taskID = reply.TaskID
+	}
+	p.setConversation(req.SessionID, next)
+
+	return &ChatResponse{
+		Content: reply.Text,
+		Agent:   p.name,
+		Done:    true,
+	}, nil
+}
+
+// buildPrompt adds the console's cluster context to the prompt. The
+// agent's own instructions stay in charge, so the console's system prompt
+// is not sent. History is transcribed only when the agent does not yet
+// hold the conversation.
+func (p *A2AProvider) buildPrompt(req *ChatRequest, agentHasContext bool) string {
+	var sb strings.Builder
+	if clusterCtx := req.Context["clusterContext"]; clusterCtx != "" {
+		sb.WriteString(fmt.Sprintf("Kubernetes cluster context: %s\n\n", clusterCtx))
+	}
+	if !agentHasContext && len(req.History) > 0 {
+		history := req.History
+		if len(history) > a2aMaxHistoryTranscribed {
+			history = history[len(history)-a2aMaxHistoryTranscribed:]
+		}
+		sb.WriteString("Conversation so far:\n")
+		for _, m := range history {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", m.Role, m.Content))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(req.Prompt)
+	return sb.String()
+}
+
+func (p *A2AProvider) conversation(sessionID string) a2aConversation {
+	if sessionID == "" {
+		return a2aConversation{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.conversations[sessionID]
+}
+
+func (p *A2AProvider) setConversation(sessionID string, conv a2aConversation) {
+	if sessionID == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.conversations[sessionID]; !ok && len(p.conversations) >= a2aMaxTrackedSessions {
+		// Forget an arbitrary session; it continues as a fresh context.
+		for k := range p.conversations {
+			delete(p.conversations, k)
+			break
+		}
+	}
+	p.conversations[sessionID] = conv
+}
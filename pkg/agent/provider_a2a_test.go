@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/a2a"
+)
+
+func TestA2AProvider_ContinuesConversationAndTasks(t *testing.T) {
+	var mu sync.Mutex
+	var sent []a2a.Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(a2a.AgentCard{Name: "Cost Advisor", Description: "Finds idle spend", Version: "1.2.0"})
+			return
+		}
+		var req struct {
+			ID     string `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				Message a2a.Message `json:"message"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, a2a.MethodSendMessage, req.Method)
+		mu.Lock()
+		sent = append(sent, req.Params.Message)
+		turn := len(sent)
+		mu.Unlock()
+
+		// The first turn asks a question; the answer completes the task.
+		task := a2a.Task{Kind: a2a.KindTask, ID: "task-1", ContextID: "ctx-9"}
+		if turn == 1 {
+			task.Status = a2a.TaskStatus{State: a2a.TaskStateInputRequired, Message: &a2a.Message{Kind: a2a.KindMessage, Role: a2a.RoleAgent, Parts: []a2a.Part{a2a.TextPart("Which namespace?")}}}
+		} else {
+			task.Status = a2a.TaskStatus{State: a2a.TaskStateCompleted}
+			task.Artifacts = []a2a.Artifact{{ArtifactID: "a", Parts: []a2a.Part{a2a.TextPart("prod wastes 4 GPUs")}}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": task})
+	}))
+	defer srv.Close()
+
+	p := NewA2AProvider(a2a.Endpoint{URL: srv.URL})
+	assert.Equal(t, "a2a-cost-advisor", p.Name())
+	assert.Equal(t, "Cost Advisor (A2A)", p.DisplayName())
+	assert.True(t, p.IsAvailable())
+	assert.Equal(t, CapabilityChat, p.Capabilities())
+	assert.True(t, p.Handshake(context.Background()).Ready)
+
+	resp, err := p.Chat(context.Background(), &ChatRequest{
+		SessionID: "s1",
+		Prompt:    "find idle spend",
+		History:   []ChatMessage{{Role: "user", Content: "earlier question"}},
+		Context:   map[string]string{"clusterContext": "prod-east"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Which namespace?", resp.Content)
+	assert.Equal(t, "a2a-cost-advisor", resp.Agent)
+
+	var chunks []string
+	resp, err = p.StreamChat(context.Background(), &ChatRequest{SessionID: "s1", Prompt: "prod"}, func(c string) { chunks = append(chunks, c) })
+	require.NoError(t, err)
+	assert.Equal(t, "prod wastes 4 GPUs", resp.Content)
+	assert.Equal(t, []string{"prod wastes 4 GPUs"}, chunks)
+
+	require.Len(t, sent, 2)
+	assert.Empty(t, sent[0].ContextID)
+	assert.Contains(t, sent[0].Text(), "prod-east")
+	assert.Contains(t, sent[0].Text(), "earlier question")
+	assert.Equal(t, "ctx-9", sent[1].ContextID)
+	assert.Equal(t, "task-1", sent[1].TaskID)
+	assert.Equal(t, "prod", sent[1].Text(), "the agent holds the history once it has the context")
+}
+
+func TestA2AProvider_UnreachableAgent(t *testing.T) {
+	p := NewA2AProvider(a2a.Endpoint{Name: "triage", URL: "http://127.0.0.1:1"})
+	assert.Equal(t, "a2a-triage", p.Name())
+	assert.False(t, p.IsAvailable())
+	assert.False(t, p.Handshake(context.Background()).Ready)
+	_, err := p.Chat(context.Background(), &ChatRequest{Prompt: "hi"})
+	assert.Error(t, err)
+}
@@ -222,6 +222,107 @@ func (c *ClaudeProvider) StreamChat(ctx context.Context, req *ChatRequest, onChu
 	}, nil
 }
 
+// ChatWithTools runs one step of a native tool-use conversation. Each turn
+// becomes an assistant message with tool_use blocks followed by a user
+// message carrying the matching tool_result blocks.
+func (c *ClaudeProvider) ChatWithTools(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("Claude provider not configured - ANTHROPIC_API_KEY not set")
+	}
+
+	messages := make([]map[string]any, 0, len(req.History)+1+2*len(turns))
+	for _, m := range c.buildMessages(req) {
+		messages = append(messages, map[string]any{"role": m["role"], "content": m["content"]})
+	}
+	for _, turn := range turns {
+		blocks := make([]map[string]any, 0, len(turn.Calls)+1)
+		if turn.Text != "" {
+			blocks = append(blocks, map[string]any{"type": "text", "text": turn.Text})
+		}
+		for _, call := range turn.Calls {
+			input := call.Input
+			if input == nil {
+				input = map[string]any{}
+			}
+			blocks = append(blocks, map[string]any{"type": "tool_use", "id": call.ID, "name": call.Name, "input": input})
+		}
+		results := make([]map[string]any, 0, len(turn.Results))
+		for _, r := range turn.Results {
+			results = append(results, map[string]any{
+				"type": "tool_result", "tool_use_id": r.CallID, "content": r.Content, "is_error": r.IsError,
+			})
+		}
+		messages = append(messages,
+			map[string]any{"role": "assistant", "content": blocks},
+			map[string]any{"role": "user", "content": results})
+	}
+
+	toolDefs := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		toolDefs = append(toolDefs, map[string]any{"name": t.Name, "description": t.Description, "input_schema": t.Parameters})
+	}
+
+	body := map[string]interface{}{
+		"model":      c.model,
+		"max_tokens": 4096,
+		"messages":   messages,
+		"tools":      toolDefs,
+	}
+	if req.SystemPrompt != "" {
+		body["system"] = req.SystemPrompt
+	} else {
+		body["system"] = DefaultSystemPrompt
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.getAPIURL(), bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLLMResponseBytes))
+		if readErr != nil {
+			body = []byte("(failed to read response body)")
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result claudeToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	step := &ToolStep{Usage: ProviderTokenUsage{
+		InputTokens:  result.Usage.InputTokens,
+		OutputTokens: result.Usage.OutputTokens,
+		TotalTokens:  result.Usage.InputTokens + result.Usage.OutputTokens,
+	}}
+	var text strings.Builder
+	for _, block := range result.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			step.Calls = append(step.Calls, ToolCall{ID: block.ID, Name: block.Name, Input: block.Input})
+		}
+	}
+	step.Text = text.String()
+	return step, nil
+}
+
 func (c *ClaudeProvider) buildMessages(req *ChatRequest) []map[string]string {
 	messages := make([]map[string]string, 0)
 
@@ -271,6 +372,23 @@ type claudeResponse struct {
 	} `json:"usage"`
 }
 
+// claudeToolResponse is a Messages API response that may contain tool_use
+// blocks alongside text.
+type claudeToolResponse struct {
+	Content []struct {
+		Type  string         `json:"type"`
+		Text  string         `json:"text,omitempty"`
+		ID    string         `json:"id,omitempty"`
+		Name  string         `json:"name,omitempty"`
+		Input map[string]any `json:"input,omitempty"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
 type claudeStreamEvent struct {
 	Type  string `json:"type"`
 	Delta *struct {
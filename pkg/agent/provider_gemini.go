@@ -233,6 +233,136 @@ func (g *GeminiProvider) StreamChat(ctx context.Context, req *ChatRequest, onChu
 	}, nil
 }
 
+// ChatWithTools runs one step of a native function-calling conversation.
+// Gemini has no call IDs on the wire, so results are matched by function
+// name and order: each turn becomes a model message with functionCall parts
+// followed by a user message with the matching functionResponse parts.
+func (g *GeminiProvider) ChatWithTools(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	if !g.IsAvailable() {
+		return nil, fmt.Errorf("Gemini provider not configured - GOOGLE_API_KEY not set")
+	}
+
+	contents := g.buildContents(req)
+	for _, turn := range turns {
+		parts := make([]map[string]any, 0, len(turn.Calls)+1)
+		if turn.Text != "" {
+			parts = append(parts, map[string]any{"text": turn.Text})
+		}
+		for _, call := range turn.Calls {
+			args := call.Input
+			if args == nil {
+				args = map[string]any{}
+			}
+			parts = append(parts, map[string]any{"functionCall": map[string]any{"name": call.Name, "args": args}})
+		}
+		responses := make([]map[string]any, 0, len(turn.Results))
+		for _, r := range turn.Results {
+			key := "content"
+			if r.IsError {
+				key = "error"
+			}
+			responses = append(responses, map[string]any{"functionResponse": map[string]any{
+				"name": r.Name, "response": map[string]any{key: r.Content},
+			}})
+		}
+		contents = append(contents,
+			map[string]interface{}{"role": "model", "parts": parts},
+			map[string]interface{}{"role": "user", "parts": responses})
+	}
+
+	declarations := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		declarations = append(declarations, map[string]any{"name": t.Name, "description": t.Description, "parameters": geminiToolSchema(t.Parameters)})
+	}
+
+	systemPrompt := req.SystemPrompt
+	if systemPrompt == "" {
+		systemPrompt = DefaultSystemPrompt
+	}
+	body := map[string]interface{}{
+		"contents": contents,
+		"generationConfig": map[string]interface{}{
+			"maxOutputTokens": 4096,
+		},
+		"systemInstruction": map[string]interface{}{
+			"parts": []map[string]string{
+				{"text": systemPrompt},
+			},
+		},
+		"tools": []map[string]any{{"functionDeclarations": declarations}},
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s:generateContent", geminiAPIBaseURL, g.model)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", GetConfigManager().GetAPIKey("gemini"))
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLLMResponseBytes))
+		if readErr != nil {
+			body = []byte("(failed to read response body)")
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result geminiToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	step := &ToolStep{}
+	if result.UsageMetadata != nil {
+		step.Usage = ProviderTokenUsage{
+			InputTokens:  result.UsageMetadata.PromptTokenCount,
+			OutputTokens: result.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:  result.UsageMetadata.TotalTokenCount,
+		}
+	}
+	if len(result.Candidates) == 0 {
+		return step, nil
+	}
+	var text strings.Builder
+	for _, part := range result.Candidates[0].Content.Parts {
+		if part.FunctionCall != nil {
+			step.Calls = append(step.Calls, ToolCall{Name: part.FunctionCall.Name, Input: part.FunctionCall.Args})
+			continue
+		}
+		text.WriteString(part.Text)
+	}
+	step.Text = text.String()
+	return step, nil
+}
+
+// geminiToolSchema drops JSON Schema keywords Gemini's OpenAPI subset
+// rejects; an object with no properties must omit "properties" entirely.
+func geminiToolSchema(schema map[string]any) map[string]any {
+	if props, ok := schema["properties"].(map[string]any); ok && len(props) == 0 {
+		out := make(map[string]any, len(schema))
+		for k, v := range schema {
+			if k != "properties" {
+				out[k] = v
+			}
+		}
+		return out
+	}
+	return schema
+}
+
 func (g *GeminiProvider) buildContents(req *ChatRequest) []map[string]interface{} {
 	contents := make([]map[string]interface{}, 0)
 
@@ -280,6 +410,27 @@ type geminiResponse struct {
 	} `json:"usageMetadata,omitempty"`
 }
 
+// geminiToolResponse is a generateContent response whose parts may be text
+// or function calls.
+type geminiToolResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text         string `json:"text,omitempty"`
+				FunctionCall *struct {
+					Name string         `json:"name"`
+					Args map[string]any `json:"args"`
+				} `json:"functionCall,omitempty"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata,omitempty"`
+}
+
 type geminiStreamEvent struct {
 	Candidates []struct {
 		Content struct {
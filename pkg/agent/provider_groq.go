@@ -115,3 +115,10 @@ func (g *GroqProvider) StreamChat(ctx context.Context, req *ChatRequest, onChunk
 		ctx, req, groqProviderKey, g.endpoint(), g.Name(), groqDefaultModel, onChunk, nil,
 	)
 }
+
+// ChatWithTools runs one step of a native function-calling conversation.
+func (g *GroqProvider) ChatWithTools(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	return chatWithToolsViaOpenAICompatible(
+		ctx, req, tools, turns, groqProviderKey, g.endpoint(), groqDefaultModel, nil,
+	)
+}
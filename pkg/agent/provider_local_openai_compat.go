@@ -22,7 +22,8 @@ import (
 //
 // These providers expose CapabilityChat only — they cannot shell out to
 // kubectl/helm, so missions (which need to run cluster commands) still route
-// through the tool-capable CLI agents. Registering them here lets the agent
+// through the tool-capable CLI agents unless the runner supports native
+// function calling (see ChatWithTools). Registering them here lets the agent
 // selector dropdown offer a local-LLM chat path without conflating it with
 // the mission-execution path. See docs/security/SECURITY-MODEL.md §3.
 type LocalOpenAICompatProvider struct {
@@ -113,6 +114,18 @@ func (p *LocalOpenAICompatProvider) StreamChat(ctx context.Context, req *ChatReq
 	return streamViaOpenAICompatibleWithHeaders(ctx, req, p.providerKey, endpoint, p.name, p.defaultModel, onChunk, nil)
 }
 
+// ChatWithTools runs one step of a native function-calling conversation.
+// Runners or models without tool support reject the request with a 400,
+// which runToolLoop answers by retrying as plain chat.
+func (p *LocalOpenAICompatProvider) ChatWithTools(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	endpoint := p.endpoint()
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s URL not configured (set %s)", p.displayName, p.urlEnvVar)
+	}
+	ensureLocalLLMPlaceholderKey(p.providerKey)
+	return chatWithToolsViaOpenAICompatible(ctx, req, tools, turns, p.providerKey, endpoint, p.defaultModel, nil)
+}
+
 // localLLMPlaceholderKey is the sentinel placeholder api-key used for local
 // runners that do not enforce authentication. It is never a real secret — just
 // a non-empty string so the Authorization header is well formed and the
@@ -213,6 +213,15 @@ func (o *OpenAIProvider) StreamChat(ctx context.Context, req *ChatRequest, onChu
 	}, nil
 }
 
+// ChatWithTools runs one step of a native function-calling conversation.
+func (o *OpenAIProvider) ChatWithTools(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	if !o.IsAvailable() {
+		return nil, fmt.Errorf("OpenAI provider not configured - OPENAI_API_KEY not set")
+	}
+	headers := map[string]string{"Authorization": "Bearer " + GetConfigManager().GetAPIKey("openai")}
+	return openAIToolStep(ctx, o.client, openAIEndpoint(), headers, o.model, req, tools, turns)
+}
+
 func (o *OpenAIProvider) buildMessages(req *ChatRequest) []map[string]string {
 	messages := make([]map[string]string, 0)
 
@@ -247,3 +247,144 @@ func buildOpenAIMessages(req *ChatRequest) []map[string]string {
 	messages = append(messages, map[string]string{"role": "user", "content": req.Prompt})
 	return messages
 }
+
+// chatWithToolsViaOpenAICompatible runs one step of a native function-calling
+// conversation against an OpenAI-compatible endpoint, resolving the API key
+// and model the same way as chatViaOpenAICompatibleWithHeaders.
+func chatWithToolsViaOpenAICompatible(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn, providerKey, endpoint, defaultModel string, extraHeaders map[string]string) (*ToolStep, error) {
+	cm := GetConfigManager()
+	apiKey := cm.GetAPIKey(providerKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key not configured for provider %s", providerKey)
+	}
+	headers := map[string]string{"Authorization": "Bearer " + apiKey}
+	for k, v := range extraHeaders {
+		headers[k] = v
+	}
+	return openAIToolStep(ctx, newAIProviderHTTPClient(), endpoint, headers, cm.GetModel(providerKey, defaultModel), req, tools, turns)
+}
+
+// openAIToolStep sends the conversation plus the turns of the current loop
+// with tool definitions to a chat completions endpoint and returns the
+// model's next step. Each turn becomes an assistant message with tool_calls
+// followed by one "tool" message per result.
+func openAIToolStep(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, model string, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	messages := make([]map[string]any, 0, len(req.History)+2+2*len(turns))
+	for _, m := range buildOpenAIMessages(req) {
+		messages = append(messages, map[string]any{"role": m["role"], "content": m["content"]})
+	}
+	for _, turn := range turns {
+		calls := make([]map[string]any, 0, len(turn.Calls))
+		for _, call := range turn.Calls {
+			args, err := json.Marshal(call.Input)
+			if err != nil || call.Input == nil {
+				args = []byte("{}")
+			}
+			calls = append(calls, map[string]any{
+				"id":       call.ID,
+				"type":     "function",
+				"function": map[string]any{"name": call.Name, "arguments": string(args)},
+			})
+		}
+		assistant := map[string]any{"role": "assistant", "tool_calls": calls, "content": nil}
+		if turn.Text != "" {
+			assistant["content"] = turn.Text
+		}
+		messages = append(messages, assistant)
+		for _, r := range turn.Results {
+			messages = append(messages, map[string]any{"role": "tool", "tool_call_id": r.CallID, "content": r.Content})
+		}
+	}
+
+	toolDefs := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		toolDefs = append(toolDefs, map[string]any{
+			"type":     "function",
+			"function": map[string]any{"name": t.Name, "description": t.Description, "parameters": t.Parameters},
+		})
+	}
+
+	body := map[string]any{
+		"messages":   messages,
+		"max_tokens": openAICompatMaxTokens,
+		"tools":      toolDefs,
+	}
+	if model != "" {
+		body["model"] = model
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		if k == "" || v == "" {
+			continue
+		}
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxLLMResponseBytes))
+		if err != nil {
+			slog.Warn("failed to read response body", "error", err)
+		}
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content   string `json:"content"`
+				ToolCalls []struct {
+					ID       string `json:"id"`
+					Function struct {
+						Name      string `json:"name"`
+						Arguments string `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	step := &ToolStep{Usage: ProviderTokenUsage{
+		InputTokens:  result.Usage.PromptTokens,
+		OutputTokens: result.Usage.CompletionTokens,
+		TotalTokens:  result.Usage.TotalTokens,
+	}}
+	if len(result.Choices) == 0 {
+		return step, nil
+	}
+	msg := result.Choices[0].Message
+	step.Text = msg.Content
+	for _, tc := range msg.ToolCalls {
+		input := map[string]any{}
+		if tc.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &input); err != nil {
+				slog.Warn("[Chat] model sent malformed tool arguments", "tool", tc.Function.Name, "error", err)
+			}
+		}
+		step.Calls = append(step.Calls, ToolCall{ID: tc.ID, Name: tc.Function.Name, Input: input})
+	}
+	return step, nil
+}
@@ -115,3 +115,10 @@ func (o *OpenRouterProvider) StreamChat(ctx context.Context, req *ChatRequest, o
 		ctx, req, openRouterProviderKey, o.endpoint(), o.Name(), openRouterDefaultModel, onChunk, o.extraHeaders(),
 	)
 }
+
+// ChatWithTools runs one step of a native function-calling conversation.
+func (o *OpenRouterProvider) ChatWithTools(ctx context.Context, req *ChatRequest, tools []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	return chatWithToolsViaOpenAICompatible(
+		ctx, req, tools, turns, openRouterProviderKey, o.endpoint(), openRouterDefaultModel, o.extraHeaders(),
+	)
+}
@@ -0,0 +1,287 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// scriptedToolProvider returns its steps in order and records the turns it
+// was given on each request.
+type scriptedToolProvider struct {
+	steps []*ToolStep
+	err   error
+	seen  [][]ToolTurn
+	defs  []ToolDefinition
+	plain bool
+}
+
+func (p *scriptedToolProvider) Name() string        { return "scripted" }
+func (p *scriptedToolProvider) DisplayName() string { return "Scripted" }
+func (p *scriptedToolProvider) Description() string { return "" }
+func (p *scriptedToolProvider) Provider() string    { return "test" }
+func (p *scriptedToolProvider) IsAvailable() bool   { return true }
+func (p *scriptedToolProvider) Capabilities() ProviderCapability {
+	return CapabilityChat
+}
+func (p *scriptedToolProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	return p.StreamChat(ctx, req, nil)
+}
+func (p *scriptedToolProvider) StreamChat(_ context.Context, _ *ChatRequest, onChunk func(string)) (*ChatResponse, error) {
+	p.plain = true
+	if onChunk != nil {
+		onChunk("plain")
+	}
+	return &ChatResponse{Content: "plain", Done: true}, nil
+}
+func (p *scriptedToolProvider) ChatWithTools(_ context.Context, _ *ChatRequest, defs []ToolDefinition, turns []ToolTurn) (*ToolStep, error) {
+	p.defs = defs
+	p.seen = append(p.seen, append([]ToolTurn(nil), turns...))
+	if p.err != nil {
+		return nil, p.err
+	}
+	i := len(p.seen) - 1
+	if i >= len(p.steps) {
+		return &ToolStep{Calls: []ToolCall{{Name: "read"}}}, nil
+	}
+	return p.steps[i], nil
+}
+
+type fakeToolSet struct {
+	executed []string
+}
+
+func (f *fakeToolSet) Definitions() []ToolDefinition {
+	return []ToolDefinition{
+		{Name: "read", Parameters: map[string]any{"type": "object"}},
+		{Name: "write", Parameters: map[string]any{"type": "object"}, Mutating: true},
+	}
+}
+
+func (f *fakeToolSet) Execute(_ context.Context, call ToolCall) (string, error) {
+	f.executed = append(f.executed, call.Name)
+	return "ok from " + call.Name, nil
+}
+
+func (f *fakeToolSet) Summarize(call ToolCall) string { return "run " + call.Name }
+
+func TestRunToolLoop_ExecutesCallsAndFeedsResultsBack(t *testing.T) {
+	p := &scriptedToolProvider{steps: []*ToolStep{
+		{Text: "Checking.", Calls: []ToolCall{{ID: "a", Name: "read"}, {Name: "missing"}}, Usage: ProviderTokenUsage{TotalTokens: 5}},
+		{Text: "All good.", Usage: ProviderTokenUsage{TotalTokens: 7}},
+	}}
+	tools := &fakeToolSet{}
+	var chunks []string
+	var events []StreamEvent
+
+	resp, err := runToolLoop(context.Background(), p, &ChatRequest{Prompt: "hi"}, tools, nil,
+		func(c string) { chunks = append(chunks, c) },
+		func(e StreamEvent) { events = append(events, e) })
+	if err != nil {
+		t.Fatalf("runToolLoop: %v", err)
+	}
+	if resp.Content != "Checking.\n\nAll good." {
+		t.Errorf("content = %q", resp.Content)
+	}
+	if strings.Join(chunks, "") != resp.Content {
+		t.Errorf("streamed chunks %q do not match content", chunks)
+	}
+	if resp.TokenUsage.TotalTokens != 12 || !resp.ToolsExecuted {
+		t.Errorf("usage = %+v, executed = %v", resp.TokenUsage, resp.ToolsExecuted)
+	}
+	if len(events) != 4 || events[0].Type != "tool_use" || events[1].Type != "tool_result" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	if len(p.seen) != 2 || len(p.seen[1]) != 1 {
+		t.Fatalf("second request should carry one turn, got %+v", p.seen)
+	}
+	results := p.seen[1][0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	if results[0].CallID != "a" || results[0].IsError || !strings.Contains(results[0].Content, "<cluster-data") {
+		t.Errorf("read result not wrapped as untrusted data: %+v", results[0])
+	}
+	if !results[1].IsError || results[1].CallID == "" {
+		t.Errorf("unknown tool should be an error with a synthesized ID: %+v", results[1])
+	}
+}
+
+func TestRunToolLoop_MutatingCallsNeedApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		approve toolApprovalFunc
+		ran     bool
+	}{
+		{"no approver", nil, false},
+		{"denied", func(context.Context, ToolCall, string) (bool, string) { return false, "nope" }, false},
+		{"approved", func(_ context.Context, _ ToolCall, summary string) (bool, string) {
+			return summary == "run write", ""
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &scriptedToolProvider{steps: []*ToolStep{
+				{Calls: []ToolCall{{Name: "write"}}},
+				{Text: "done"},
+			}}
+			tools := &fakeToolSet{}
+			if _, err := runToolLoop(context.Background(), p, &ChatRequest{}, tools, tt.approve, nil, nil); err != nil {
+				t.Fatalf("runToolLoop: %v", err)
+			}
+			if ran := len(tools.executed) == 1; ran != tt.ran {
+				t.Errorf("executed = %v, want ran %v", tools.executed, tt.ran)
+			}
+			if got := p.seen[1][0].Results[0].IsError; got == tt.ran {
+				t.Errorf("IsError = %v", got)
+			}
+		})
+	}
+}
+
+func TestRunToolLoop_OffersMutatingToolsOnlyWithApprover(t *testing.T) {
+	approve := func(context.Context, ToolCall, string) (bool, string) { return true, "" }
+	for _, tt := range []struct {
+		name    string
+		approve toolApprovalFunc
+		want    []string
+	}{
+		{"no approver", nil, []string{"read"}},
+		{"approver", approve, []string{"read", "write"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &scriptedToolProvider{steps: []*ToolStep{{Text: "done"}}}
+			if _, err := runToolLoop(context.Background(), p, &ChatRequest{}, &fakeToolSet{}, tt.approve, nil, nil); err != nil {
+				t.Fatalf("runToolLoop: %v", err)
+			}
+			var names []string
+			for _, d := range p.defs {
+				names = append(names, d.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("offered tools = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunToolLoop_StopsAfterMaxSteps(t *testing.T) {
+	p := &scriptedToolProvider{}
+	resp, err := runToolLoop(context.Background(), p, &ChatRequest{}, &fakeToolSet{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("runToolLoop: %v", err)
+	}
+	if len(p.seen) != maxToolLoopSteps {
+		t.Errorf("made %d requests, want %d", len(p.seen), maxToolLoopSteps)
+	}
+	if !strings.Contains(resp.Content, "Stopped after") {
+		t.Errorf("content = %q", resp.Content)
+	}
+}
+
+func TestRunToolLoop_FallsBackWhenToolsUnsupported(t *testing.T) {
+	p := &scriptedToolProvider{err: errors.New("API error (status 400): model does not support tools")}
+	resp, err := runToolLoop(context.Background(), p, &ChatRequest{}, &fakeToolSet{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("runToolLoop: %v", err)
+	}
+	if !p.plain || resp.Content != "plain" {
+		t.Errorf("expected plain chat fallback, got %+v", resp)
+	}
+
+	p = &scriptedToolProvider{err: errors.New("API error (status 500): boom")}
+	if _, err := runToolLoop(context.Background(), p, &ChatRequest{}, &fakeToolSet{}, nil, nil, nil); err == nil {
+		t.Error("expected a server error to be returned")
+	}
+}
+
+func TestClaudeProvider_ChatWithTools(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content":[{"type":"text","text":"Looking"},{"type":"tool_use","id":"tu_1","name":"read","input":{"kind":"pod"}}],
+			"stop_reason":"tool_use","usage":{"input_tokens":3,"output_tokens":4}}`))
+	}))
+	defer server.Close()
+	os.Setenv("ANTHROPIC_BASE_URL", server.URL)
+	defer os.Unsetenv("ANTHROPIC_BASE_URL")
+	os.Setenv("ANTHROPIC_API_KEY", "test-key")
+	defer os.Unsetenv("ANTHROPIC_API_KEY")
+
+	turns := []ToolTurn{{
+		Calls:   []ToolCall{{ID: "tu_0", Name: "read", Input: map[string]any{}}},
+		Results: []ToolResult{{CallID: "tu_0", Name: "read", Content: "x"}},
+	}}
+	step, err := NewClaudeProvider().ChatWithTools(context.Background(), &ChatRequest{Prompt: "hi"}, (&fakeToolSet{}).Definitions(), turns)
+	if err != nil {
+		t.Fatalf("ChatWithTools: %v", err)
+	}
+	if step.Text != "Looking" || len(step.Calls) != 1 || step.Calls[0].ID != "tu_1" || step.Calls[0].Input["kind"] != "pod" {
+		t.Errorf("unexpected step %+v", step)
+	}
+	if step.Usage.TotalTokens != 7 {
+		t.Errorf("usage = %+v", step.Usage)
+	}
+	if tools, _ := body["tools"].([]any); len(tools) != 2 {
+		t.Errorf("tools not sent: %v", body["tools"])
+	}
+	msgs, _ := body["messages"].([]any)
+	if len(msgs) < 3 {
+		t.Fatalf("expected prompt plus tool turn, got %v", msgs)
+	}
+	last, _ := msgs[len(msgs)-1].(map[string]any)
+	if !strings.Contains(mustJSON(t, last), `"tool_result"`) {
+		t.Errorf("last message should carry tool results: %v", last)
+	}
+}
+
+func TestOpenAIProvider_ChatWithTools(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"content":"","tool_calls":[{"id":"c1","type":"function",
+			"function":{"name":"read","arguments":"{\"kind\":\"node\"}"}}]}}],"usage":{"prompt_tokens":2,"completion_tokens":1,"total_tokens":3}}`))
+	}))
+	defer server.Close()
+	os.Setenv("OPENAI_BASE_URL", server.URL)
+	defer os.Unsetenv("OPENAI_BASE_URL")
+	os.Setenv("OPENAI_API_KEY", "test-key")
+	defer os.Unsetenv("OPENAI_API_KEY")
+
+	turns := []ToolTurn{{
+		Calls:   []ToolCall{{ID: "c0", Name: "read"}},
+		Results: []ToolResult{{CallID: "c0", Name: "read", Content: "x"}},
+	}}
+	step, err := NewOpenAIProvider().ChatWithTools(context.Background(), &ChatRequest{Prompt: "hi"}, (&fakeToolSet{}).Definitions(), turns)
+	if err != nil {
+		t.Fatalf("ChatWithTools: %v", err)
+	}
+	if len(step.Calls) != 1 || step.Calls[0].ID != "c1" || step.Calls[0].Input["kind"] != "node" {
+		t.Errorf("unexpected step %+v", step)
+	}
+	if step.Usage.TotalTokens != 3 {
+		t.Errorf("usage = %+v", step.Usage)
+	}
+	msgs, _ := body["messages"].([]any)
+	last, _ := msgs[len(msgs)-1].(map[string]any)
+	if last["role"] != "tool" || last["tool_call_id"] != "c0" {
+		t.Errorf("last message should be the tool result: %v", last)
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
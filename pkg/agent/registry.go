@@ -7,6 +7,8 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/kubestellar/console/pkg/airouter"
 )
 
 // Registry manages available AI providers
@@ -16,6 +18,9 @@ type Registry struct {
 	defaultAgent     string
 	selectedAgent    map[string]string    // sessionID -> agentName
 	selectedAgentLRU map[string]time.Time // sessionID -> last access time
+
+	routerOnce sync.Once
+	router     *airouter.Router
 }
 
 // Global registry instance
@@ -344,6 +349,12 @@ func InitializeProviders() error {
 	// so it should only be the default when no other agent is available (#3609).
 	registry.Register(NewCopilotCLIProvider())
 
+	// Register A2A agents listed in A2A_AGENT_URLS. They are chat-only, so
+	// like the local LLM runners below they never become the mission default.
+	for _, p := range NewA2AProvidersFromEnv() {
+		registry.Register(p)
+	}
+
 	// Register chat-only local LLM providers AFTER the tool-capable CLI agents.
 	// Rationale: missions need to execute cluster commands, so they must route
 	// to an agent that returns CapabilityToolExec. The local-LLM HTTP runners
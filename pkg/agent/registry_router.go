@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kubestellar/console/pkg/airouter"
+)
+
+// localRouterUser owns the per-user budget for kc-agent requests. kc-agent
+// serves the single user running it, so all of its traffic shares one
+// budget.
+const localRouterUser = "local"
+
+// Router returns the provider router shared by chat, predictions and
+// insights. It is configured from the AI_ROUTER_* environment on first use;
+// an invalid configuration is logged and replaced by the defaults.
+func (r *Registry) Router() *airouter.Router {
+	r.routerOnce.Do(func() {
+		cfg, err := airouter.ConfigFromEnv()
+		if err != nil {
+			slog.Error("[AIRouter] invalid routing configuration, budgets and chains disabled", "error", err)
+			cfg = airouter.Config{}
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.router = airouter.New(cfg, nil)
+	})
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.router
+}
+
+// SetRouter replaces the registry's router. The console calls it at startup
+// so kc-agent providers, Stellar and the admin endpoint share one router
+// with database-backed budgets.
+func (r *Registry) SetRouter(router *airouter.Router) {
+	r.routerOnce.Do(func() {})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.router = router
+}
+
+// Route runs call against the providers the router picks for req, in
+// order, and returns the first successful response. Providers that are not
+// registered or not available are skipped.
+func (r *Registry) Route(ctx context.Context, req airouter.Request, call func(context.Context, AIProvider) (*ChatResponse, error)) (*ChatResponse, error) {
+	var resp *ChatResponse
+	_, err := r.Router().Route(ctx, req,
+		func(name string) bool {
+			p, err := r.Get(name)
+			return err == nil && p.IsAvailable()
+		},
+		func(ctx context.Context, name string) (int64, error) {
+			p, err := r.Get(name)
+			if err != nil {
+				return 0, err
+			}
+			out, err := call(ctx, p)
+			if err != nil {
+				return 0, err
+			}
+			resp = out
+			return responseTokens(out), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// responseTokens is the token count a response is charged against the
+// budgets.
+func responseTokens(resp *ChatResponse) int64 {
+	if resp == nil || resp.TokenUsage == nil {
+		return 0
+	}
+	if resp.TokenUsage.TotalTokens > 0 {
+		return int64(resp.TokenUsage.TotalTokens)
+	}
+	return int64(resp.TokenUsage.InputTokens + resp.TokenUsage.OutputTokens)
+}
+
+// chatPinned sends req to a provider the caller already chose. The call
+// still goes through the router, pinned to that provider, so it is charged
+// against the budgets, feeds the provider's breaker and shows up in the
+// decision log.
+func (r *Registry) chatPinned(ctx context.Context, category string, provider AIProvider, req *ChatRequest) (*ChatResponse, error) {
+	return r.Route(ctx, airouter.Request{
+		UserID:    localRouterUser,
+		Category:  category,
+		Preferred: provider.Name(),
+		Pinned:    true,
+	}, func(ctx context.Context, _ AIProvider) (*ChatResponse, error) {
+		return provider.Chat(ctx, req)
+	})
+}
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/airouter"
+)
+
+// routedFakeProvider answers with a fixed token count or fails.
+type routedFakeProvider struct {
+	MockProvider
+	err    error
+	tokens int
+	calls  int
+}
+
+func (p *routedFakeProvider) Chat(_ context.Context, _ *ChatRequest) (*ChatResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &ChatResponse{Content: "ok from " + p.name, Agent: p.name, TokenUsage: &ProviderTokenUsage{TotalTokens: p.tokens}}, nil
+}
+
+func newRoutedTestRegistry(t *testing.T, providers ...AIProvider) *Registry {
+	t.Helper()
+	r := &Registry{providers: make(map[string]AIProvider)}
+	for _, p := range providers {
+		require.NoError(t, r.Register(p))
+	}
+	return r
+}
+
+func TestRegistryRoute_FallsBackAndRecordsDecision(t *testing.T) {
+	offline := &routedFakeProvider{MockProvider: MockProvider{name: "claude-code"}}
+	broken := &routedFakeProvider{MockProvider: MockProvider{name: "openai", available: true}, err: errors.New("status 500")}
+	healthy := &routedFakeProvider{MockProvider: MockProvider{name: "ollama", available: true}, tokens: 42}
+	r := newRoutedTestRegistry(t, offline, broken, healthy)
+
+	resp, err := r.Route(context.Background(), airouter.Request{
+		Category: airouter.CategoryInsights,
+		Fallback: []string{"claude-code", "missing", "openai", "ollama"},
+	}, func(ctx context.Context, p AIProvider) (*ChatResponse, error) {
+		return p.Chat(ctx, &ChatRequest{Prompt: "hi"})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok from ollama", resp.Content)
+	assert.Zero(t, offline.calls)
+	assert.Equal(t, 1, broken.calls)
+
+	snap := r.Router().Snapshot(context.Background())
+	require.Len(t, snap.Decisions, 1)
+	d := snap.Decisions[0]
+	assert.Equal(t, "ollama", d.Selected)
+	assert.Equal(t, int64(42), d.Tokens)
+	assert.Equal(t, []airouter.Skip{{Provider: "claude-code", Reason: "unavailable"}, {Provider: "missing", Reason: "unavailable"}}, d.Skipped)
+	assert.Equal(t, int64(42), snap.CategoryUsage[airouter.CategoryInsights])
+}
+
+func TestRegistryChatPinned_EnforcesBudget(t *testing.T) {
+	t.Setenv(airouter.EnvUserDailyTokens, "100")
+	p := &routedFakeProvider{MockProvider: MockProvider{name: "openai", available: true}, tokens: 150}
+	r := newRoutedTestRegistry(t, p)
+
+	_, err := r.chatPinned(context.Background(), airouter.CategoryMissions, p, &ChatRequest{Prompt: "first"})
+	require.NoError(t, err, "the budget is checked before the call")
+
+	_, err = r.chatPinned(context.Background(), airouter.CategoryMissions, p, &ChatRequest{Prompt: "runaway"})
+	var budgetErr *airouter.BudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, int64(150), budgetErr.Used)
+	assert.Equal(t, 1, p.calls)
+
+	s := &Server{}
+	msg := s.routeErrorResponse("m1", err)
+	payload, ok := msg.Payload.(protocol.ErrorPayload)
+	require.True(t, ok)
+	assert.Equal(t, "token_quota_exceeded", payload.Code)
+}
+
+func TestChatRouteCategory(t *testing.T) {
+	assert.Equal(t, airouter.CategoryMissions, chatRouteCategory(""))
+	assert.Equal(t, airouter.CategoryDiagnose, chatRouteCategory("diagnose"))
+	assert.Equal(t, airouter.CategoryOther, chatRouteCategory("made-up"))
+}
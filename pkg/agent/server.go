@@ -131,6 +131,14 @@ type Config struct {
 	Port           int
 	Kubeconfig     string
 	AllowedOrigins []string // Additional allowed origins (from --allowed-origins flag)
+
+	// Reverse tunnel to a remote console (from --tunnel-* flags). When
+	// TunnelURL is set the agent also dials out to the console and serves
+	// the same routes over that connection.
+	TunnelURL            string
+	TunnelPairingCode    string
+	TunnelName           string
+	TunnelCredentialFile string // defaults to ~/.kc/tunnel.json
 }
 
 // AllowedOrigins for WebSocket connections (can be extended via env var)
@@ -213,6 +221,12 @@ type Server struct {
 	dryRunSessions   map[string]bool
 	dryRunSessionsMu sync.RWMutex
 
+	// Native function calling for API providers. toolApprovals holds the
+	// mutating tool calls waiting for the user, keyed by approval ID.
+	nativeToolsDisabled bool // KC_NATIVE_TOOLS=false
+	toolApprovals       map[string]*pendingToolApproval
+	toolApprovalsMu     sync.Mutex
+
 	// Auto-update system
 	updateChecker *UpdateChecker
 
@@ -337,20 +351,22 @@ func NewServer(cfg Config) (*Server, error) {
 
 	now := time.Now()
 	server := &Server{
-		config:             cfg,
-		kubectl:            kubectl,
-		k8sClient:          k8sClient,
-		registry:           GetRegistry(),
-		clients:            make(map[*websocket.Conn]*wsClient),
-		allowedOrigins:     allowedOrigins,
-		agentToken:         agentToken,
-		tokenExplicit:      tokenExplicit,
-		sessionStart:       now,
-		todayDate:          now.Format("2006-01-02"),
-		activeChatCtxs:     make(map[string]activeChatEntry),
-		dryRunSessions:     make(map[string]bool),
-		resourceRetryState: make(map[string]clusterResourceRetryState),
-		sessionTokenQuota:  sessionQuota,
+		config:              cfg,
+		kubectl:             kubectl,
+		k8sClient:           k8sClient,
+		registry:            GetRegistry(),
+		clients:             make(map[*websocket.Conn]*wsClient),
+		allowedOrigins:      allowedOrigins,
+		agentToken:          agentToken,
+		tokenExplicit:       tokenExplicit,
+		sessionStart:        now,
+		todayDate:           now.Format("2006-01-02"),
+		activeChatCtxs:      make(map[string]activeChatEntry),
+		dryRunSessions:      make(map[string]bool),
+		nativeToolsDisabled: nativeToolsDisabledByEnv(),
+		toolApprovals:       make(map[string]*pendingToolApproval),
+		resourceRetryState:  make(map[string]clusterResourceRetryState),
+		sessionTokenQuota:   sessionQuota,
 		stellarClient: &http.Client{
 			Timeout: 5 * time.Second,
 			Transport: &http.Transport{
@@ -440,6 +456,14 @@ func (s *Server) checkOrigin(r *http.Request) bool {
 // fallback (#4264), we verify all three headers that browsers always send for
 // real WebSocket handshakes: Upgrade, Connection, and Sec-WebSocket-Key.
 func (s *Server) validateToken(r *http.Request) bool {
+	// Requests over the reverse tunnel were authenticated by the console:
+	// the tunnel credential proves the link and the user's session proves
+	// the caller. The relay strips Origin, so it also checks Origin against
+	// the console's allowlist before forwarding.
+	if isTunnelRequest(r) {
+		return true
+	}
+
 	// If no token configured, skip token validation
 	if s.agentToken == "" {
 		return true
@@ -676,6 +700,9 @@ func (s *Server) Start() error {
 	// Provider readiness check - runs handshake for a specific provider
 	mux.HandleFunc("/provider/check", s.handleProviderCheck)
 
+	// AI routing state - breakers, budgets and recent routing decisions
+	mux.HandleFunc("/ai/routing", s.handleAIRouting)
+
 	// Prediction endpoints
 	mux.HandleFunc("/predictions/ai", s.handlePredictionsAI)
 	mux.HandleFunc("/predictions/analyze", s.handlePredictionsAnalyze)
@@ -782,6 +809,14 @@ func (s *Server) Start() error {
 	slog.Info("health endpoint available", "url", "http://"+addr+"/health")
 	slog.Info("WebSocket endpoint available", "url", "ws://"+addr+"/ws")
 
+	if s.config.TunnelURL != "" {
+		tunnel, err := newTunnelClient(s.config, handler, s.stopCh)
+		if err != nil {
+			return err
+		}
+		safego.GoWith("agent-tunnel", tunnel.run)
+	}
+
 	// Validate all configured API keys on startup (run in background to not delay startup)
 	safego.GoWith("validate-all-keys", func() { s.ValidateAllKeys() })
 
@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/k8s"
 	"github.com/kubestellar/console/pkg/safego"
 )
@@ -313,7 +315,9 @@ func (s *Server) handleChatMessageStreaming(connCtx context.Context, conn *webso
 		}
 	}
 
-	if needsTools && !s.isToolCapableAgent(agentName) {
+	// API providers with native function calling run their own tool loop,
+	// so only fall back to mixed mode for providers that lack it.
+	if needsTools && !s.isToolCapableAgent(agentName) && !s.supportsNativeTools(agentName) {
 		// Try mixed-mode: use thinking agent + CLI execution agent
 		if toolAgent := s.findToolCapableAgent(); toolAgent != "" {
 			slog.Info("[Chat] mixed-mode routing", "thinking", agentName, "execution", toolAgent)
@@ -325,25 +329,6 @@ func (s *Server) handleChatMessageStreaming(connCtx context.Context, conn *webso
 
 	slog.Info("[Chat] final agent selection", "requested", req.Agent, "forceAgent", forceAgent, "selected", agentName, "sessionID", req.SessionID)
 
-	// Get the provider
-	provider, err := s.registry.Get(agentName)
-	if err != nil {
-		// Try default agent
-		slog.Info("[Chat] agent not found, trying default", "agent", agentName)
-		provider, err = s.registry.GetDefault()
-		if err != nil {
-			safeWrite(ctx, s.errorResponse(msg.ID, "no_agent", "No AI agent available. Please configure an API key"))
-			return
-		}
-		agentName = provider.Name()
-		slog.Info("[Chat] using default agent", "agent", agentName)
-	}
-
-	if !provider.IsAvailable() {
-		safeWrite(ctx, s.errorResponse(msg.ID, "agent_unavailable", "AI agent is not available"))
-		return
-	}
-
 	// Convert protocol history to provider history
 	var history []ChatMessage
 	for _, m := range req.History {
@@ -353,44 +338,96 @@ func (s *Server) handleChatMessageStreaming(connCtx context.Context, conn *webso
 		})
 	}
 
-	chatReq := &ChatRequest{
-		SessionID: req.SessionID,
-		Prompt:    req.Prompt,
-		History:   history,
-	}
+	// The router tries the selected agent first, then the category's chain
+	// (or the default agent), skipping providers whose circuit breaker is
+	// open and enforcing the daily token budgets. A provider that already
+	// streamed text or ran tools is never replaced: replaying the prompt on
+	// another provider would duplicate output or side effects.
+	resp, err := s.registry.Route(ctx, airouter.Request{
+		UserID:    localRouterUser,
+		Category:  chatRouteCategory(req.Category),
+		Preferred: agentName,
+		Fallback:  []string{s.registry.GetDefaultName()},
+	}, func(ctx context.Context, provider AIProvider) (*ChatResponse, error) {
+		if provider.Name() != agentName {
+			slog.Info("[Chat] routing to fallback agent", "from", agentName, "to", provider.Name(), "sessionID", req.SessionID)
+		}
+		agentName = provider.Name()
 
-	// #10463: Use ChatOnlySystemPrompt for providers that cannot execute
-	// commands, so the AI never claims it can run kubectl when it cannot.
-	if !provider.Capabilities().HasCapability(CapabilityToolExec) {
-		chatReq.SystemPrompt = ChatOnlySystemPrompt
-	}
+		chatReq := &ChatRequest{
+			SessionID: req.SessionID,
+			Prompt:    req.Prompt,
+			History:   history,
+		}
 
-	// Thread cluster context so tool-capable agents scope kubectl to the
-	// correct cluster, preventing multi-cluster context drift (#9485).
-	if req.ClusterContext != "" {
-		chatReq.Context = map[string]string{
-			"clusterContext": req.ClusterContext,
+		// #10463: Use ChatOnlySystemPrompt for providers that cannot execute
+		// commands, so the AI never claims it can run kubectl when it cannot.
+		if !provider.Capabilities().HasCapability(CapabilityToolExec) {
+			chatReq.SystemPrompt = ChatOnlySystemPrompt
 		}
-	}
 
-	s.enrichKagentiChatRequest(connCtx, provider, chatReq)
+		// Thread cluster context so tool-capable agents scope kubectl to the
+		// correct cluster, preventing multi-cluster context drift (#9485).
+		if req.ClusterContext != "" {
+			chatReq.Context = map[string]string{
+				"clusterContext": req.ClusterContext,
+			}
+		}
 
-	// Send initial progress message so user sees feedback immediately
-	safeWrite(ctx, protocol.Message{
-		ID:   msg.ID,
-		Type: protocol.TypeProgress,
-		Payload: protocol.ProgressPayload{
-			Step: fmt.Sprintf("Processing with %s...", agentName),
-		},
-	})
+		s.enrichKagentiChatRequest(connCtx, provider, chatReq)
+
+		// API providers with native function calling get the built-in Kubernetes
+		// tool set. Wrapping them as a StreamingProvider reuses the progress,
+		// heartbeat and error handling below; mutating calls ask the user over
+		// this WebSocket before they run. Clients that cannot answer approvals
+		// only get the read-only tools.
+		if tp := s.nativeToolProvider(provider); tp != nil {
+			chatReq.SystemPrompt = NativeToolsSystemPrompt
+			if req.ClusterContext != "" {
+				chatReq.SystemPrompt += "\n\nThe user is viewing cluster context " + req.ClusterContext + "; tools default to it."
+			}
+			var approve toolApprovalFunc
+			if req.ToolApproval {
+				approve = s.newToolApprover(conn, msg.ID, req.SessionID, agentName, req.DryRun, func(m protocol.Message) {
+					safeWrite(ctx, m)
+				})
+			} else {
+				chatReq.SystemPrompt += "\n\n" + readOnlyToolsNote
+			}
+			provider = &toolLoopProvider{
+				ToolCallingProvider: tp,
+				tools:               newKubeTools(s.k8sClient, req.ClusterContext),
+				approve:             approve,
+			}
+			slog.Info("[Chat] using native function calling", "agent", agentName, "sessionID", req.SessionID, "mutatingTools", req.ToolApproval)
+		}
+
+		// Send initial progress message so user sees feedback immediately
+		safeWrite(ctx, protocol.Message{
+			ID:   msg.ID,
+			Type: protocol.TypeProgress,
+			Payload: protocol.ProgressPayload{
+				Step: fmt.Sprintf("Processing with %s...", agentName),
+			},
+		})
+
+		// Check if provider supports streaming with progress events
+		streamingProvider, ok := provider.(StreamingProvider)
+		if !ok {
+			// Fall back to non-streaming for providers that don't support progress
+			resp, err := provider.Chat(ctx, chatReq)
+			if err != nil {
+				slog.Error("[Chat] execution error", "agent", agentName, "error", err)
+			}
+			return resp, err
+		}
 
-	// Check if provider supports streaming with progress events
-	var resp *ChatResponse
-	if streamingProvider, ok := provider.(StreamingProvider); ok {
 		// Use streaming with progress callbacks
 		var streamedContent strings.Builder
+		var emitted atomic.Bool
 
 		onChunk := func(chunk string) {
+			emitted.Store(true)
 			streamedContent.WriteString(chunk)
 			safeWrite(ctx, protocol.Message{
 				ID:   msg.ID,
@@ -406,6 +443,7 @@ func (s *Server) handleChatMessageStreaming(connCtx context.Context, conn *webso
 
 		const maxCmdDisplayLen = 60
 		onProgress := func(event StreamEvent) {
+			emitted.Store(true)
 			// Build human-readable step description
 			step := event.Tool
 			if event.Type == "tool_use" {
@@ -465,54 +503,38 @@ func (s *Server) handleChatMessageStreaming(connCtx context.Context, conn *webso
 		// sync.Once ensures close is called exactly once (#7179).
 		defer heartbeatOnce.Do(func() { close(heartbeatDone) })
 
-		resp, err = streamingProvider.StreamChatWithProgress(ctx, chatReq, onChunk, onProgress)
+		resp, err := streamingProvider.StreamChatWithProgress(ctx, chatReq, onChunk, onProgress)
 		if err != nil {
-			if ctx.Err() != nil {
-				// Distinguish timeout from user-initiated cancel (#2375)
-				if ctx.Err() == context.DeadlineExceeded {
-					slog.Info("[Chat] session timed out", "sessionID", req.SessionID, "timeout", missionExecutionTimeout)
-					safeWrite(context.Background(), s.errorResponse(msg.ID, "mission_timeout",
-						fmt.Sprintf("Mission timed out after %d minutes. The AI provider did not respond in time. You can retry or try a simpler prompt.", int(missionExecutionTimeout.Minutes()))))
-					return
-				}
-				slog.Info("[Chat] session cancelled", "sessionID", req.SessionID)
-				return
-			}
 			slog.Error("[Chat] streaming execution error", "agent", agentName, "error", err)
-			code, msg2 := classifyProviderError(err)
-			// Use background context so the error reaches the client even if
-			// the mission context expired between the ctx.Err() check above
-			// and this write (#6997).
-			safeWrite(context.Background(), s.errorResponse(msg.ID, code, msg2))
-			return
+			if emitted.Load() {
+				return nil, airouter.Permanent(err)
+			}
+			return nil, err
 		}
 
 		// Use streamed content if result content is empty
-		if resp.Content == "" {
+		if resp != nil && resp.Content == "" {
 			resp.Content = streamedContent.String()
 		}
-	} else {
-		// Fall back to non-streaming for providers that don't support progress
-		resp, err = provider.Chat(ctx, chatReq)
-		if err != nil {
-			if ctx.Err() != nil {
-				// Distinguish timeout from user-initiated cancel (#2375)
-				if ctx.Err() == context.DeadlineExceeded {
-					slog.Info("[Chat] session timed out", "sessionID", req.SessionID, "timeout", missionExecutionTimeout)
-					safeWrite(context.Background(), s.errorResponse(msg.ID, "mission_timeout",
-						fmt.Sprintf("Mission timed out after %d minutes. The AI provider did not respond in time. You can retry or try a simpler prompt.", int(missionExecutionTimeout.Minutes()))))
-					return
-				}
-				slog.Info("[Chat] session cancelled", "sessionID", req.SessionID)
+		return resp, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// Distinguish timeout from user-initiated cancel (#2375)
+			if ctx.Err() == context.DeadlineExceeded {
+				slog.Info("[Chat] session timed out", "sessionID", req.SessionID, "timeout", missionExecutionTimeout)
+				safeWrite(context.Background(), s.errorResponse(msg.ID, "mission_timeout",
+					fmt.Sprintf("Mission timed out after %d minutes. The AI provider did not respond in time. You can retry or try a simpler prompt.", int(missionExecutionTimeout.Minutes()))))
 				return
 			}
-			slog.Error("[Chat] execution error", "agent", agentName, "error", err)
-			code, msg2 := classifyProviderError(err)
-			// Use background context so the error reaches the client even if
-			// the mission context expired (#6997).
-			safeWrite(context.Background(), s.errorResponse(msg.ID, code, msg2))
+			slog.Info("[Chat] session cancelled", "sessionID", req.SessionID)
 			return
 		}
+		// Use background context so the error reaches the client even if
+		// the mission context expired between the ctx.Err() check above
+		// and this write (#6997).
+		safeWrite(context.Background(), s.routeErrorResponse(msg.ID, err))
+		return
 	}
 
 	// Don't send result if cancelled
@@ -790,21 +812,6 @@ func (s *Server) handleChatMessage(msg protocol.Message, forceAgent string, pare
 		agentName = s.registry.GetSelectedAgent(req.SessionID)
 	}
 
-	// Get the provider
-	provider, err := s.registry.Get(agentName)
-	if err != nil {
-		// Try default agent
-		provider, err = s.registry.GetDefault()
-		if err != nil {
-			return s.errorResponse(msg.ID, "no_agent", "No AI agent available. Please configure an API key (ANTHROPIC_API_KEY, OPENAI_API_KEY, or GOOGLE_API_KEY)")
-		}
-		agentName = provider.Name()
-	}
-
-	if !provider.IsAvailable() {
-		return s.errorResponse(msg.ID, "agent_unavailable", "AI agent is not available - API key may be missing")
-	}
-
 	// Convert protocol history to provider history
 	var history []ChatMessage
 	for _, msg := range req.History {
@@ -814,33 +821,11 @@ func (s *Server) handleChatMessage(msg protocol.Message, forceAgent string, pare
 		})
 	}
 
-	// Execute chat (non-streaming for WebSocket single response)
-	chatReq := &ChatRequest{
-		SessionID: req.SessionID,
-		Prompt:    req.Prompt,
-		History:   history,
-	}
-
-	// #10463: Use ChatOnlySystemPrompt for providers that cannot execute
-	// commands, so the AI never claims it can run kubectl when it cannot.
-	if !provider.Capabilities().HasCapability(CapabilityToolExec) {
-		chatReq.SystemPrompt = ChatOnlySystemPrompt
-	}
-
-	// Thread cluster context for non-streaming path (#9485).
-	if req.ClusterContext != "" {
-		chatReq.Context = map[string]string{
-			"clusterContext": req.ClusterContext,
-		}
-	}
-
 	parent := context.Background()
 	if len(parentCtx) > 0 && parentCtx[0] != nil {
 		parent = parentCtx[0]
 	}
 
-	s.enrichKagentiChatRequest(parent, provider, chatReq)
-
 	// #6678 — Previously this used context.Background() with no deadline,
 	// which meant a hung AI provider would block the WebSocket goroutine
 	// forever (the caller was a synchronous path from the read loop).
@@ -851,13 +836,54 @@ func (s *Server) handleChatMessage(msg protocol.Message, forceAgent string, pare
 	// disconnect cancels in-flight non-streaming AI calls.
 	ctx, cancel := context.WithTimeout(parent, handleChatMessageTimeout)
 	defer cancel()
-	resp, err := provider.Chat(ctx, chatReq)
+
+	// Same routing as the streaming path: selected agent, then the
+	// category chain or the default agent.
+	resp, err := s.registry.Route(ctx, airouter.Request{
+		UserID:    localRouterUser,
+		Category:  chatRouteCategory(req.Category),
+		Preferred: agentName,
+		Fallback:  []string{s.registry.GetDefaultName()},
+	}, func(ctx context.Context, provider AIProvider) (*ChatResponse, error) {
+		agentName = provider.Name()
+
+		// Execute chat (non-streaming for WebSocket single response)
+		chatReq := &ChatRequest{
+			SessionID: req.SessionID,
+			Prompt:    req.Prompt,
+			History:   history,
+		}
+
+		// #10463: Use ChatOnlySystemPrompt for providers that cannot execute
+		// commands, so the AI never claims it can run kubectl when it cannot.
+		if !provider.Capabilities().HasCapability(CapabilityToolExec) {
+			chatReq.SystemPrompt = ChatOnlySystemPrompt
+		}
+
+		// Thread cluster context for non-streaming path (#9485).
+		if req.ClusterContext != "" {
+			chatReq.Context = map[string]string{
+				"clusterContext": req.ClusterContext,
+			}
+		}
+
+		s.enrichKagentiChatRequest(parent, provider, chatReq)
+
+		resp, err := provider.Chat(ctx, chatReq)
+		if err != nil {
+			slog.Error("[Chat] execution error", "agent", agentName, "error", err, "timeout", handleChatMessageTimeout)
+		}
+		return resp, err
+	})
 	if err != nil {
-		slog.Error("[Chat] execution error", "agent", agentName, "error", err, "timeout", handleChatMessageTimeout)
 		if ctx.Err() == context.DeadlineExceeded {
 			return s.errorResponse(msg.ID, "timeout",
 				fmt.Sprintf("AI agent did not respond within %s", handleChatMessageTimeout))
 		}
+		var budgetErr *airouter.BudgetError
+		if errors.As(err, &budgetErr) || errors.Is(err, airouter.ErrNoProvider) {
+			return s.routeErrorResponse(msg.ID, err)
+		}
 		return s.errorResponse(msg.ID, "execution_error", "Failed to execute AI agent")
 	}
 
@@ -975,6 +1001,32 @@ func (s *Server) errorResponse(id, code, message string) protocol.Message {
 	}
 }
 
+// routeErrorResponse turns a routing failure into the client error. A
+// spent budget reuses the token-quota code the frontend already handles,
+// an empty chain means no agent is available, and provider failures are
+// classified as before.
+func (s *Server) routeErrorResponse(id string, err error) protocol.Message {
+	var budgetErr *airouter.BudgetError
+	switch {
+	case errors.As(err, &budgetErr):
+		return s.errorResponse(id, "token_quota_exceeded", budgetErr.Error()+". The budget resets at midnight.")
+	case err == airouter.ErrNoProvider: // nothing was tried, so there is no provider error to classify
+		return s.errorResponse(id, "no_agent", "No AI agent available. Please configure an API key")
+	default:
+		code, message := classifyProviderError(err)
+		return s.errorResponse(id, code, message)
+	}
+}
+
+// chatRouteCategory is the budget category of a chat request. Chats are
+// missions unless the client says otherwise.
+func chatRouteCategory(category string) string {
+	if category == "" {
+		return airouter.CategoryMissions
+	}
+	return airouter.NormalizeCategory(category)
+}
+
 // classifyProviderError inspects an AI provider error and returns a
 // specific error code + user-friendly message.  This lets the frontend
 // show targeted guidance (e.g. "restart kc-agent") instead of a raw JSON blob.
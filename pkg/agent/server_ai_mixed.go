@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/airouter"
 )
 
 func (s *Server) handleMixedModeChat(ctx context.Context, conn *websocket.Conn, msg protocol.Message, req protocol.ChatRequest, thinkingAgent, executionAgent string, sessionID string, writeMu *sync.Mutex, closed *atomic.Bool) {
@@ -112,13 +114,18 @@ User request: %s`, req.Prompt)
 		return
 	}
 
-	thinkingResp, err := thinkingProvider.Chat(ctx, &thinkingReq)
+	thinkingResp, err := s.registry.chatPinned(ctx, chatRouteCategory(req.Category), thinkingProvider, &thinkingReq)
 	if err != nil {
 		if ctx.Err() != nil {
 			slog.Info("[MixedMode] session cancelled", "sessionID", sessionID)
 			return
 		}
 		slog.Error("[MixedMode] thinking agent error", "error", err)
+		var budgetErr *airouter.BudgetError
+		if errors.As(err, &budgetErr) {
+			safeWrite(s.routeErrorResponse(msg.ID, err))
+			return
+		}
 		safeWrite(s.errorResponse(msg.ID, "mixed_mode_error", "Thinking agent error"))
 		return
 	}
@@ -185,7 +192,7 @@ User request: %s`, req.Prompt)
 		return
 	}
 
-	execResp, err := execProvider.Chat(ctx, &execReq)
+	execResp, err := s.registry.chatPinned(ctx, chatRouteCategory(req.Category), execProvider, &execReq)
 	if err != nil {
 		if ctx.Err() != nil {
 			slog.Info("[MixedMode] session cancelled during execution", "sessionID", sessionID)
@@ -248,7 +255,7 @@ Command output:
 		return
 	}
 
-	analysisResp, err := thinkingProvider.Chat(ctx, &analysisReq)
+	analysisResp, err := s.registry.chatPinned(ctx, chatRouteCategory(req.Category), thinkingProvider, &analysisReq)
 	if err != nil {
 		if ctx.Err() != nil {
 			slog.Info("[MixedMode] session cancelled during analysis", "sessionID", sessionID)
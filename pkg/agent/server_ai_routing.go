@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// handleAIRouting reports the provider router's state: the effective
+// policy, every circuit breaker, today's usage per category and for the
+// local user, and the most recent routing decisions.
+func (s *Server) handleAIRouting(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	router := s.registry.Router()
+	usage, err := router.UserUsage(r.Context(), localRouterUser)
+	if err != nil {
+		slog.Warn("[AIRouter] failed to read local token usage", "error", err)
+	}
+	writeJSON(w, map[string]interface{}{
+		"routing":   router.Snapshot(r.Context()),
+		"userUsage": usage,
+	})
+}
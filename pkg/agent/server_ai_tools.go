@@ -0,0 +1,170 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/kubestellar/console/pkg/agent/protocol"
+)
+
+const (
+	// nativeToolsEnvVar disables native function calling for API providers
+	// when set to "false", restoring mixed-mode routing through a CLI agent.
+	nativeToolsEnvVar = "KC_NATIVE_TOOLS"
+
+	// toolApprovalTimeout is how long a mutating tool call waits for the
+	// user before it is denied. The chat heartbeat keeps the stream alive
+	// meanwhile.
+	toolApprovalTimeout = 2 * time.Minute
+)
+
+// pendingToolApproval is a mutating call waiting for the user's answer. The
+// conn reference restricts answers to the connection that started the chat,
+// mirroring the ownership check on cancel_chat (#9712).
+type pendingToolApproval struct {
+	conn     *websocket.Conn
+	decision chan bool
+}
+
+// nativeToolsDisabledByEnv reports whether KC_NATIVE_TOOLS turns the native
+// function-calling loop off.
+func nativeToolsDisabledByEnv() bool {
+	v := strings.TrimSpace(os.Getenv(nativeToolsEnvVar))
+	return strings.EqualFold(v, "false") || v == "0"
+}
+
+// nativeToolProvider returns provider as a ToolCallingProvider when its chats
+// should run the native function-calling loop: the provider supports it,
+// cannot execute tools itself, and the agent has cluster access.
+func (s *Server) nativeToolProvider(provider AIProvider) ToolCallingProvider {
+	if provider == nil || s.k8sClient == nil || s.nativeToolsDisabled {
+		return nil
+	}
+	if provider.Capabilities().HasCapability(CapabilityToolExec) {
+		return nil
+	}
+	tp, _ := provider.(ToolCallingProvider)
+	return tp
+}
+
+// supportsNativeTools reports whether the named agent runs the native
+// function-calling loop, in which case mixed-mode routing is unnecessary.
+func (s *Server) supportsNativeTools(agentName string) bool {
+	provider, err := s.registry.Get(agentName)
+	if err != nil {
+		return false
+	}
+	return s.nativeToolProvider(provider) != nil
+}
+
+// newToolApprover returns the approval callback for one chat. Each mutating
+// call is announced to the client with a tool_approval_request message and
+// waits for a matching tool_approval answer, the timeout, or cancellation.
+// Dry-run sessions deny every change without asking (#6442).
+func (s *Server) newToolApprover(conn *websocket.Conn, msgID, sessionID, agentName string, dryRun bool, send func(protocol.Message)) toolApprovalFunc {
+	return func(ctx context.Context, call ToolCall, summary string) (bool, string) {
+		if dryRun {
+			return false, "the session is in dry-run mode, so no changes are made"
+		}
+
+		approvalID := uuid.New().String()
+		pending := &pendingToolApproval{conn: conn, decision: make(chan bool, 1)}
+		s.toolApprovalsMu.Lock()
+		if s.toolApprovals == nil {
+			s.toolApprovals = make(map[string]*pendingToolApproval)
+		}
+		s.toolApprovals[approvalID] = pending
+		s.toolApprovalsMu.Unlock()
+		defer func() {
+			s.toolApprovalsMu.Lock()
+			delete(s.toolApprovals, approvalID)
+			s.toolApprovalsMu.Unlock()
+		}()
+
+		slog.Info("[Chat] waiting for tool approval", "sessionID", sessionID, "tool", call.Name, "approvalID", approvalID)
+		send(protocol.Message{
+			ID:   msgID,
+			Type: protocol.TypeToolApprovalRequest,
+			Payload: protocol.ToolApprovalRequestPayload{
+				ApprovalID: approvalID,
+				SessionID:  sessionID,
+				Agent:      agentName,
+				Tool:       call.Name,
+				Input:      call.Input,
+				Summary:    summary,
+				ExpiresIn:  int(toolApprovalTimeout.Seconds()),
+			},
+		})
+
+		timer := time.NewTimer(toolApprovalTimeout)
+		defer timer.Stop()
+		select {
+		case approved := <-pending.decision:
+			slog.Info("[Chat] tool approval answered", "sessionID", sessionID, "tool", call.Name, "approved", approved)
+			if !approved {
+				return false, "the user declined this change"
+			}
+			return true, ""
+		case <-timer.C:
+			return false, fmt.Sprintf("no approval received within %s", toolApprovalTimeout)
+		case <-ctx.Done():
+			return false, "the chat was cancelled"
+		}
+	}
+}
+
+// handleToolApproval delivers the user's answer to a pending mutating tool
+// call. Only the connection that started the chat may answer.
+func (s *Server) handleToolApproval(conn *websocket.Conn, msg protocol.Message, writeMu *sync.Mutex) {
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		slog.Error("[Chat] failed to marshal tool approval payload", "error", err)
+		return
+	}
+	var req protocol.ToolApprovalResponse
+	if err := json.Unmarshal(payloadBytes, &req); err != nil || req.ApprovalID == "" {
+		s.writeToolApprovalError(conn, msg.ID, writeMu, "invalid_payload", "approvalId is required")
+		return
+	}
+
+	s.toolApprovalsMu.Lock()
+	pending, ok := s.toolApprovals[req.ApprovalID]
+	if ok && pending.conn == conn {
+		delete(s.toolApprovals, req.ApprovalID)
+	}
+	s.toolApprovalsMu.Unlock()
+
+	switch {
+	case !ok:
+		s.writeToolApprovalError(conn, msg.ID, writeMu, "approval_not_found", "No pending tool call with this approvalId")
+	case pending.conn != conn:
+		slog.Warn("[Chat] SECURITY: rejected tool approval from non-owning connection",
+			"approvalID", req.ApprovalID, "requester", conn.RemoteAddr())
+		s.writeToolApprovalError(conn, msg.ID, writeMu, "unauthorized_approval", "You do not own this session")
+	default:
+		// decision is buffered and written once, so this never blocks.
+		pending.decision <- req.Approved
+	}
+}
+
+func (s *Server) writeToolApprovalError(conn *websocket.Conn, msgID string, writeMu *sync.Mutex, code, message string) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if err := setWSWriteDeadline(conn, "[Chat] failed to set WebSocket write deadline",
+		"msgID", msgID, "type", protocol.TypeError); err != nil {
+		return
+	}
+	if err := conn.WriteJSON(s.errorResponse(msgID, code, message)); err != nil {
+		slog.Error("[Chat] failed to write tool approval error to WebSocket", "msgID", msgID, "error", err)
+	}
+	_ = clearWSWriteDeadline(conn, "[Chat] failed to clear WebSocket write deadline",
+		"msgID", msgID, "type", protocol.TypeError)
+}
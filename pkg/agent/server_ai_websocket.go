@@ -224,6 +224,10 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		} else if msg.Type == protocol.TypeCancelChat {
 			// Cancel an in-progress chat by session ID
 			s.handleCancelChat(conn, msg, writeMu)
+		} else if msg.Type == protocol.TypeToolApproval {
+			// Answer a pending mutating tool call. Handled inline like
+			// cancel_chat so it is never queued behind the chat it unblocks.
+			s.handleToolApproval(conn, msg, writeMu)
 		} else if msg.Type == protocol.TypeKubectl {
 			// Handle kubectl messages concurrently so one slow cluster
 			// doesn't block the entire WebSocket message loop.
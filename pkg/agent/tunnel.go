@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/kubestellar/console/pkg/agenttunnel"
+	"github.com/kubestellar/console/pkg/fileutil"
+)
+
+const (
+	// Console endpoints used by the reverse tunnel (pkg/api/routes_agent_tunnel.go).
+	tunnelRegisterPath = "/agent-tunnel/register"
+	tunnelConnectPath  = "/agent-tunnel/connect"
+
+	// tunnelFileName holds the credential obtained by pairing, under ~/.kc.
+	tunnelFileName = "tunnel.json"
+
+	tunnelMinBackoff     = 1 * time.Second
+	tunnelMaxBackoff     = 1 * time.Minute
+	tunnelRequestTimeout = 15 * time.Second
+	// tunnelStableAfter resets the reconnect backoff once a tunnel has
+	// stayed up this long.
+	tunnelStableAfter = 1 * time.Minute
+
+	tunnelReadHeaderTimeout = 10 * time.Second
+	tunnelIdleTimeout       = 120 * time.Second
+
+	maxTunnelResponseBytes = 64 * 1024
+)
+
+var (
+	// errTunnelNotPaired means there is no stored credential for the
+	// console and no pairing code to obtain one.
+	errTunnelNotPaired = errors.New("kc-agent is not paired with this console; start it with --tunnel-pairing-code")
+	// errTunnelRejected means the console refused the pairing code or the
+	// stored credential; retrying will not help.
+	errTunnelRejected = errors.New("console rejected the agent")
+)
+
+// tunnelRequestKey marks requests that arrived through the reverse tunnel.
+type tunnelRequestKey struct{}
+
+// isTunnelRequest reports whether r came through the reverse tunnel. The
+// console authenticated both the agent (its credential) and the user (their
+// session) before forwarding, so such requests need no agent token.
+func isTunnelRequest(r *http.Request) bool {
+	v, _ := r.Context().Value(tunnelRequestKey{}).(bool)
+	return v
+}
+
+func markTunnelRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tunnelRequestKey{}, true)))
+	})
+}
+
+// tunnelCredential is the pairing result stored in ~/.kc/tunnel.json.
+type tunnelCredential struct {
+	ConsoleURL string `json:"consoleUrl"`
+	AgentID    string `json:"agentId"`
+	Credential string `json:"credential"`
+}
+
+// tunnelClient keeps an outbound tunnel to a console open and serves the
+// agent's handler over it, so a console the browser reaches can use this
+// agent without the browser connecting to 127.0.0.1 (#6195).
+type tunnelClient struct {
+	consoleURL  string
+	pairingCode string
+	name        string
+	credPath    string
+	handler     http.Handler
+	stop        <-chan struct{}
+	httpClient  *http.Client
+}
+
+func newTunnelClient(cfg Config, handler http.Handler, stop <-chan struct{}) (*tunnelClient, error) {
+	u, err := url.Parse(strings.TrimRight(strings.TrimSpace(cfg.TunnelURL), "/"))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("invalid tunnel URL %q: must be an http(s) console URL", cfg.TunnelURL)
+	}
+	if u.Scheme == "http" {
+		slog.Warn("[Tunnel] console URL is not https; the agent credential will be sent in clear text", "url", u.String())
+	}
+	credPath := cfg.TunnelCredentialFile
+	if credPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		credPath = filepath.Join(homeDir, configDirName, tunnelFileName)
+	}
+	name := strings.TrimSpace(cfg.TunnelName)
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	return &tunnelClient{
+		consoleURL:  u.String(),
+		pairingCode: strings.TrimSpace(cfg.TunnelPairingCode),
+		name:        name,
+		credPath:    credPath,
+		handler:     markTunnelRequests(handler),
+		stop:        stop,
+		httpClient:  &http.Client{Timeout: tunnelRequestTimeout},
+	}, nil
+}
+
+// run connects and reconnects with exponential backoff until stop is
+// closed or the console rejects the agent.
+func (c *tunnelClient) run() {
+	backoff := tunnelMinBackoff
+	for {
+		started := time.Now()
+		err := c.connectOnce()
+		if errors.Is(err, errTunnelNotPaired) || errors.Is(err, errTunnelRejected) {
+			slog.Error("[Tunnel] giving up on reverse tunnel", "console", c.consoleURL, "error", err)
+			return
+		}
+		if time.Since(started) > tunnelStableAfter {
+			backoff = tunnelMinBackoff
+		}
+		slog.Warn("[Tunnel] disconnected from console, reconnecting", "console", c.consoleURL, "in", backoff, "error", err)
+
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, tunnelMaxBackoff)
+	}
+}
+
+func (c *tunnelClient) connectOnce() error {
+	cred, err := c.credential()
+	if err != nil {
+		return err
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(c.consoleURL, "http") + tunnelConnectPath
+	header := http.Header{"Authorization": []string{"Bearer " + cred.Credential}}
+	dialer := websocket.Dialer{HandshakeTimeout: tunnelRequestTimeout, Proxy: http.ProxyFromEnvironment}
+	conn, resp, err := dialer.Dial(wsURL, header)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("%w: credential unknown or revoked; pair again with --tunnel-pairing-code", errTunnelRejected)
+		}
+		return err
+	}
+	conn.SetReadLimit(agenttunnel.MaxMessageSize)
+
+	session := agenttunnel.NewSession(conn, true)
+	slog.Info("[Tunnel] connected to console", "console", c.consoleURL, "agentID", cred.AgentID)
+	srv := &http.Server{
+		Handler:           c.handler,
+		ReadHeaderTimeout: tunnelReadHeaderTimeout,
+		IdleTimeout:       tunnelIdleTimeout,
+	}
+	go func() {
+		select {
+		case <-c.stop:
+		case <-session.Done():
+		}
+		_ = session.Close()
+	}()
+	err = srv.Serve(session)
+	_ = srv.Close()
+	return err
+}
+
+// credential returns the stored credential for the console, pairing with
+// the configured code first if there is none.
+func (c *tunnelClient) credential() (*tunnelCredential, error) {
+	if data, err := os.ReadFile(c.credPath); err == nil {
+		var cred tunnelCredential
+		if err := json.Unmarshal(data, &cred); err != nil {
+			slog.Warn("[Tunnel] ignoring unreadable tunnel credential file", "path", c.credPath, "error", err)
+		} else if cred.ConsoleURL == c.consoleURL && cred.Credential != "" && c.pairingCode == "" {
+			return &cred, nil
+		}
+	}
+	if c.pairingCode == "" {
+		return nil, errTunnelNotPaired
+	}
+
+	cred, err := c.pair()
+	if err != nil {
+		return nil, err
+	}
+	// A code is single-use; reconnects use the stored credential.
+	c.pairingCode = ""
+	return cred, nil
+}
+
+func (c *tunnelClient) pair() (*tunnelCredential, error) {
+	body, err := json.Marshal(map[string]string{"code": c.pairingCode, "name": c.name})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.consoleURL+tunnelRegisterPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("pairing request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTunnelResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read pairing response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("%w: pairing code is invalid or expired", errTunnelRejected)
+	case resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("pairing failed (status %d): %s", resp.StatusCode, truncateString(string(data), 200))
+	}
+
+	var result struct {
+		AgentID    string `json:"agentId"`
+		Credential string `json:"credential"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || result.Credential == "" {
+		return nil, fmt.Errorf("invalid pairing response from console")
+	}
+	cred := &tunnelCredential{ConsoleURL: c.consoleURL, AgentID: result.AgentID, Credential: result.Credential}
+	if err := c.saveCredential(cred); err != nil {
+		// The tunnel still works for this run; the user pairs again after a
+		// restart.
+		slog.Error("[Tunnel] failed to save tunnel credential", "path", c.credPath, "error", err)
+	}
+	slog.Info("[Tunnel] paired with console", "console", c.consoleURL, "agentID", cred.AgentID)
+	return cred, nil
+}
+
+func (c *tunnelClient) saveCredential(cred *tunnelCredential) error {
+	if err := os.MkdirAll(filepath.Dir(c.credPath), configDirMode); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cred, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.AtomicWriteFile(c.credPath, data, configFileMode)
+}
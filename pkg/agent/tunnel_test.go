@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTunnelClient(t *testing.T, consoleURL, code string) *tunnelClient {
+	t.Helper()
+	c, err := newTunnelClient(Config{
+		TunnelURL:            consoleURL + "/",
+		TunnelPairingCode:    code,
+		TunnelName:           "laptop",
+		TunnelCredentialFile: filepath.Join(t.TempDir(), "kc", tunnelFileName),
+	}, http.NotFoundHandler(), make(chan struct{}))
+	require.NoError(t, err)
+	return c
+}
+
+func TestTunnelClient_PairsOnceAndStoresCredential(t *testing.T) {
+	registrations := 0
+	console := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, tunnelRegisterPath, r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["code"] != "ABCD-2345" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "laptop", body["name"])
+		registrations++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"agentId": "a1", "credential": "kca_secret"})
+	}))
+	defer console.Close()
+
+	c := newTestTunnelClient(t, console.URL, "ABCD-2345")
+	cred, err := c.credential()
+	require.NoError(t, err)
+	assert.Equal(t, "kca_secret", cred.Credential)
+	assert.Equal(t, console.URL, cred.ConsoleURL)
+
+	info, err := os.Stat(c.credPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(configFileMode), info.Mode().Perm())
+
+	// Reconnects, and restarts without a code, reuse the stored credential.
+	_, err = c.credential()
+	require.NoError(t, err)
+	restarted := newTestTunnelClient(t, console.URL, "")
+	restarted.credPath = c.credPath
+	cred, err = restarted.credential()
+	require.NoError(t, err)
+	assert.Equal(t, "kca_secret", cred.Credential)
+	assert.Equal(t, 1, registrations)
+
+	// The stored credential belongs to one console.
+	other := newTestTunnelClient(t, "https://other.example.com", "")
+	other.credPath = c.credPath
+	_, err = other.credential()
+	assert.ErrorIs(t, err, errTunnelNotPaired)
+
+	rejected := newTestTunnelClient(t, console.URL, "WRONG-CODE")
+	_, err = rejected.credential()
+	assert.ErrorIs(t, err, errTunnelRejected)
+}
+
+func TestNewTunnelClient_RejectsInvalidURL(t *testing.T) {
+	for _, u := range []string{"console.example.com", "ftp://console.example.com", "https://"} {
+		_, err := newTunnelClient(Config{TunnelURL: u}, http.NotFoundHandler(), nil)
+		assert.Error(t, err, u)
+	}
+}
+
+func TestValidateToken_AcceptsTunnelRequests(t *testing.T) {
+	s := &Server{agentToken: "secret", tokenExplicit: true}
+	var tunneled, direct bool
+	h := markTunnelRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tunneled = s.validateToken(r)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/clusters", nil))
+	direct = s.validateToken(httptest.NewRequest(http.MethodGet, "/clusters", nil))
+
+	assert.True(t, tunneled)
+	assert.False(t, direct)
+}
@@ -0,0 +1,335 @@
+// Package agenttunnel multiplexes byte streams over a single WebSocket so a
+// console can reach a kc-agent that dialed out to it. The agent serves its
+// regular HTTP handler on the Session (a net.Listener), and the console
+// dials streams with Session.DialContext, so HTTP requests, SSE and nested
+// WebSocket upgrades all travel over the one outbound connection.
+//
+// Wire format: every WebSocket binary message is one frame of
+//
+//	[1 byte type][4 byte big-endian stream ID][payload]
+//
+// Streams are flow controlled with a per-stream receive window so a slow
+// reader on one stream cannot stall the others.
+package agenttunnel
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	frameOpen   byte = 1 // open a stream; no payload
+	frameData   byte = 2 // stream bytes
+	frameWindow byte = 3 // 4-byte receive-window increment
+	frameClose  byte = 4 // sender closed the stream; data before it is delivered
+	frameReset  byte = 5 // abort the stream
+	framePing   byte = 6 // keepalive, stream 0
+	framePong   byte = 7 // keepalive reply, stream 0
+
+	frameHeaderLen = 5
+
+	// binaryMessage is the WebSocket binary message type (RFC 6455), which
+	// gorilla and fasthttp websocket both use.
+	binaryMessage = 2
+
+	// MaxFramePayload is the largest data payload in one frame.
+	MaxFramePayload = 32 * 1024
+	// MaxMessageSize is the largest WebSocket message a session sends;
+	// callers should set it as the connection's read limit.
+	MaxMessageSize = frameHeaderLen + MaxFramePayload
+
+	// streamWindow is each stream's receive window. It bounds the memory a
+	// stream buffers when its reader is slower than the peer's writer.
+	streamWindow = 256 * 1024
+
+	// maxStreams caps concurrent streams per session and acceptBacklog the
+	// streams waiting in Accept.
+	maxStreams    = 512
+	acceptBacklog = 64
+
+	// PingInterval is how often a session pings its peer; a session that
+	// hears nothing for PongWait is closed.
+	PingInterval = 20 * time.Second
+	PongWait     = 60 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+var (
+	// ErrSessionClosed is returned by operations on a closed session.
+	ErrSessionClosed = errors.New("agent tunnel closed")
+	// ErrStreamReset is returned when the peer aborts a stream.
+	ErrStreamReset = errors.New("agent tunnel stream reset")
+	// ErrTooManyStreams is returned by Open when the session is full.
+	ErrTooManyStreams = errors.New("agent tunnel has too many open streams")
+)
+
+// Conn is the WebSocket connection a session runs over. Both
+// *github.com/gorilla/websocket.Conn and the fasthttp/fiber websocket
+// connection satisfy it.
+type Conn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	Close() error
+}
+
+// Session multiplexes streams over one Conn. Either side may open streams;
+// the agent side only accepts them.
+type Session struct {
+	conn Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	streams map[uint32]*Stream
+	nextID  uint32
+	err     error
+
+	accept     chan *Stream
+	done       chan struct{}
+	readExited chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewSession starts a session over conn. The two ends of a tunnel must pass
+// different values for client so their stream IDs never collide. The
+// session owns conn and closes it when the session ends.
+func NewSession(conn Conn, client bool) *Session {
+	s := &Session{
+		conn:       conn,
+		streams:    make(map[uint32]*Stream),
+		nextID:     2,
+		accept:     make(chan *Stream, acceptBacklog),
+		done:       make(chan struct{}),
+		readExited: make(chan struct{}),
+	}
+	if client {
+		s.nextID = 1
+	}
+	go s.readLoop()
+	go s.keepalive()
+	return s
+}
+
+// Open starts a new stream to the peer.
+func (s *Session) Open() (*Stream, error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, s.err
+	}
+	if len(s.streams) >= maxStreams {
+		s.mu.Unlock()
+		return nil, ErrTooManyStreams
+	}
+	id := s.nextID
+	s.nextID += 2
+	st := newStream(s, id)
+	s.streams[id] = st
+	s.mu.Unlock()
+
+	if err := s.writeFrame(frameOpen, id, nil); err != nil {
+		s.removeStream(id)
+		return nil, err
+	}
+	return st, nil
+}
+
+// DialContext opens a stream and ignores network and address, so it can be
+// used as an http.Transport DialContext or a websocket.Dialer NetDialContext.
+func (s *Session) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Open()
+}
+
+// Accept waits for the peer to open a stream. It implements net.Listener,
+// so an http.Server can Serve a session.
+func (s *Session) Accept() (net.Conn, error) {
+	select {
+	case st := <-s.accept:
+		return st, nil
+	case <-s.done:
+		return nil, s.closeErr()
+	}
+}
+
+// Addr implements net.Listener.
+func (s *Session) Addr() net.Addr { return tunnelAddr{} }
+
+// Done is closed when the session ends.
+func (s *Session) Done() <-chan struct{} { return s.done }
+
+// Wait blocks until the session has ended and stopped using its Conn, for
+// servers whose connection object is recycled once the handler returns.
+func (s *Session) Wait() {
+	<-s.done
+	<-s.readExited
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+}
+
+// NumStreams returns how many streams are open.
+func (s *Session) NumStreams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.streams)
+}
+
+// Close ends the session and every stream on it.
+func (s *Session) Close() error {
+	s.shutdown(ErrSessionClosed)
+	return nil
+}
+
+func (s *Session) shutdown(err error) {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		streams := s.streams
+		s.streams = make(map[uint32]*Stream)
+		s.mu.Unlock()
+		for _, st := range streams {
+			st.fail(ErrSessionClosed)
+		}
+		close(s.done)
+		_ = s.conn.Close()
+	})
+}
+
+func (s *Session) closeErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		return ErrSessionClosed
+	}
+	return s.err
+}
+
+func (s *Session) removeStream(id uint32) {
+	s.mu.Lock()
+	delete(s.streams, id)
+	s.mu.Unlock()
+}
+
+func (s *Session) stream(id uint32) *Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams[id]
+}
+
+func (s *Session) writeFrame(typ byte, id uint32, payload []byte) error {
+	msg := make([]byte, frameHeaderLen+len(payload))
+	msg[0] = typ
+	binary.BigEndian.PutUint32(msg[1:frameHeaderLen], id)
+	copy(msg[frameHeaderLen:], payload)
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	select {
+	case <-s.done:
+		return s.closeErr()
+	default:
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := s.conn.WriteMessage(binaryMessage, msg); err != nil {
+		go s.shutdown(fmt.Errorf("agent tunnel write: %w", err))
+		return ErrSessionClosed
+	}
+	return nil
+}
+
+func (s *Session) keepalive() {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.writeFrame(framePing, 0, nil); err != nil {
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Session) readLoop() {
+	defer close(s.readExited)
+	for {
+		_ = s.conn.SetReadDeadline(time.Now().Add(PongWait))
+		typ, msg, err := s.conn.ReadMessage()
+		if err != nil {
+			s.shutdown(fmt.Errorf("agent tunnel read: %w", err))
+			return
+		}
+		if typ != binaryMessage || len(msg) < frameHeaderLen {
+			continue
+		}
+		id := binary.BigEndian.Uint32(msg[1:frameHeaderLen])
+		s.handleFrame(msg[0], id, msg[frameHeaderLen:])
+	}
+}
+
+func (s *Session) handleFrame(typ byte, id uint32, payload []byte) {
+	switch typ {
+	case framePing:
+		go func() { _ = s.writeFrame(framePong, 0, nil) }()
+	case framePong:
+	case frameOpen:
+		s.handleOpen(id)
+	case frameData:
+		if st := s.stream(id); st != nil && !st.receive(payload) {
+			slog.Warn("[AgentTunnel] peer overran stream window, resetting", "stream", id)
+			st.reset()
+		}
+	case frameWindow:
+		if st := s.stream(id); st != nil && len(payload) == 4 {
+			st.grant(int(binary.BigEndian.Uint32(payload)))
+		}
+	case frameClose:
+		if st := s.stream(id); st != nil {
+			st.remoteClose()
+		}
+	case frameReset:
+		if st := s.stream(id); st != nil {
+			s.removeStream(id)
+			st.fail(ErrStreamReset)
+		}
+	}
+}
+
+func (s *Session) handleOpen(id uint32) {
+	s.mu.Lock()
+	// The peer uses the other ID parity; a wrong parity or a reused ID is
+	// a protocol error, and a full session refuses the stream.
+	_, exists := s.streams[id]
+	if id == 0 || id%2 == s.nextID%2 || exists || len(s.streams) >= maxStreams || s.err != nil {
+		s.mu.Unlock()
+		go func() { _ = s.writeFrame(frameReset, id, nil) }()
+		return
+	}
+	st := newStream(s, id)
+	s.streams[id] = st
+	s.mu.Unlock()
+
+	select {
+	case s.accept <- st:
+	default:
+		slog.Warn("[AgentTunnel] accept backlog full, refusing stream", "stream", id)
+		st.reset()
+	}
+}
+
+type tunnelAddr struct{}
+
+func (tunnelAddr) Network() string { return "agent-tunnel" }
+func (tunnelAddr) String() string  { return "agent-tunnel" }
@@ -0,0 +1,161 @@
+package agenttunnel
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTunnelPair connects a console-side and an agent-side session over a
+// real WebSocket and serves agentHandler on the agent side.
+func newTunnelPair(t *testing.T, agentHandler http.Handler) (console, agent *Session) {
+	t.Helper()
+	consoleCh := make(chan *Session, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		conn.SetReadLimit(MaxMessageSize)
+		consoleCh <- NewSession(conn, false)
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	conn.SetReadLimit(MaxMessageSize)
+	agent = NewSession(conn, true)
+	console = <-consoleCh
+
+	httpSrv := &http.Server{Handler: agentHandler}
+	go func() { _ = httpSrv.Serve(agent) }()
+	t.Cleanup(func() {
+		_ = console.Close()
+		_ = agent.Close()
+		_ = httpSrv.Close()
+	})
+	return console, agent
+}
+
+func tunnelClient(s *Session) *http.Client {
+	return &http.Client{Transport: &http.Transport{DialContext: s.DialContext}}
+}
+
+func TestSession_ServesHTTPOverTunnel(t *testing.T) {
+	big := bytes.Repeat([]byte("0123456789abcdef"), 64*1024) // 1 MiB, several windows
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(big)
+	})
+	console, _ := newTunnelPair(t, mux)
+	client := tunnelClient(console)
+
+	resp, err := client.Post("http://agent/echo", "text/plain", bytes.NewReader(big))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "POST", resp.Header.Get("X-Method"))
+	assert.Equal(t, big, body)
+
+	// Several concurrent requests share the session.
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			resp, err := client.Get("http://agent/big")
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer resp.Body.Close()
+			got, err := io.ReadAll(resp.Body)
+			if err == nil && !bytes.Equal(got, big) {
+				err = io.ErrUnexpectedEOF
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestSession_CarriesWebSocketUpgrades(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	console, _ := newTunnelPair(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			typ, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(typ, append([]byte("echo:"), msg...))
+		}
+	}))
+
+	dialer := websocket.Dialer{NetDialContext: console.DialContext}
+	conn, _, err := dialer.Dial("ws://agent/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hi")))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "echo:hi", string(msg))
+}
+
+func TestSession_CloseEndsStreams(t *testing.T) {
+	blocked := make(chan struct{})
+	console, agent := newTunnelPair(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		close(blocked)
+		<-r.Context().Done()
+	}))
+
+	resp, err := tunnelClient(console).Get("http://agent/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	<-blocked
+
+	_ = agent.Close()
+	_, err = io.ReadAll(resp.Body)
+	assert.Error(t, err)
+	select {
+	case <-console.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("console session did not notice the agent closing")
+	}
+	_, err = console.DialContext(context.Background(), "tcp", "agent:80")
+	assert.Error(t, err)
+}
+
+func TestStream_ReadDeadline(t *testing.T) {
+	console, agent := newTunnelPair(t, http.NotFoundHandler())
+	_ = agent // the agent's http.Server accepts the stream and waits for a request
+
+	st, err := console.Open()
+	require.NoError(t, err)
+	require.NoError(t, st.SetReadDeadline(time.Now().Add(20*time.Millisecond)))
+	_, err = st.Read(make([]byte, 1))
+	require.Error(t, err)
+	var timeout interface{ Timeout() bool }
+	require.ErrorAs(t, err, &timeout)
+	assert.True(t, timeout.Timeout())
+}
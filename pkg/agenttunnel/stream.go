@@ -0,0 +1,251 @@
+package agenttunnel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Stream is one bidirectional byte stream in a Session. It implements
+// net.Conn. Close ends both directions: data already written is delivered
+// to the peer, after which its reads return io.EOF and its writes fail.
+type Stream struct {
+	session *Session
+	id      uint32
+
+	mu            sync.Mutex
+	buf           bytes.Buffer // received, not yet read
+	unacked       int          // bytes read since the last window update
+	sendWindow    int          // bytes the peer will still accept
+	remoteClosed  bool
+	localClosed   bool
+	err           error // set on reset or session close
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	readReady  chan struct{}
+	writeReady chan struct{}
+}
+
+func newStream(s *Session, id uint32) *Stream {
+	return &Stream{
+		session:    s,
+		id:         id,
+		sendWindow: streamWindow,
+		readReady:  make(chan struct{}, 1),
+		writeReady: make(chan struct{}, 1),
+	}
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// wait blocks until ch is signalled or the deadline passes.
+func wait(ch chan struct{}, deadline time.Time) error {
+	if deadline.IsZero() {
+		<-ch
+		return nil
+	}
+	d := time.Until(deadline)
+	if d <= 0 {
+		return os.ErrDeadlineExceeded
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ch:
+		return nil
+	case <-timer.C:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+// Read implements net.Conn.
+func (st *Stream) Read(p []byte) (int, error) {
+	for {
+		st.mu.Lock()
+		if st.buf.Len() > 0 {
+			n, _ := st.buf.Read(p)
+			st.unacked += n
+			var grant int
+			// Batch window updates so a byte-at-a-time reader does not
+			// cost one frame per read.
+			if st.unacked >= streamWindow/4 && !st.remoteClosed && st.err == nil {
+				grant, st.unacked = st.unacked, 0
+			}
+			st.mu.Unlock()
+			if grant > 0 {
+				var payload [4]byte
+				binary.BigEndian.PutUint32(payload[:], uint32(grant))
+				_ = st.session.writeFrame(frameWindow, st.id, payload[:])
+			}
+			return n, nil
+		}
+		switch {
+		case st.err != nil:
+			err := st.err
+			st.mu.Unlock()
+			return 0, err
+		case st.localClosed:
+			st.mu.Unlock()
+			return 0, net.ErrClosed
+		case st.remoteClosed:
+			st.mu.Unlock()
+			return 0, io.EOF
+		}
+		deadline := st.readDeadline
+		st.mu.Unlock()
+		if err := wait(st.readReady, deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Write implements net.Conn.
+func (st *Stream) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		st.mu.Lock()
+		switch {
+		case st.err != nil:
+			err := st.err
+			st.mu.Unlock()
+			return written, err
+		case st.localClosed:
+			st.mu.Unlock()
+			return written, net.ErrClosed
+		case st.remoteClosed:
+			st.mu.Unlock()
+			return written, io.ErrClosedPipe
+		}
+		if st.sendWindow == 0 {
+			deadline := st.writeDeadline
+			st.mu.Unlock()
+			if err := wait(st.writeReady, deadline); err != nil {
+				return written, err
+			}
+			continue
+		}
+		n := min(len(p)-written, st.sendWindow, MaxFramePayload)
+		st.sendWindow -= n
+		st.mu.Unlock()
+
+		if err := st.session.writeFrame(frameData, st.id, p[written:written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return written, nil
+}
+
+// Close implements net.Conn.
+func (st *Stream) Close() error {
+	st.mu.Lock()
+	if st.localClosed || st.err != nil {
+		st.mu.Unlock()
+		return nil
+	}
+	st.localClosed = true
+	st.buf.Reset()
+	st.mu.Unlock()
+	notify(st.readReady)
+	notify(st.writeReady)
+
+	st.session.removeStream(st.id)
+	return st.session.writeFrame(frameClose, st.id, nil)
+}
+
+// receive buffers data from the peer. It reports false when the peer
+// exceeded the window it was granted.
+func (st *Stream) receive(p []byte) bool {
+	st.mu.Lock()
+	if st.localClosed || st.err != nil {
+		st.mu.Unlock()
+		return true
+	}
+	if st.buf.Len()+len(p) > streamWindow {
+		st.mu.Unlock()
+		return false
+	}
+	st.buf.Write(p)
+	st.mu.Unlock()
+	notify(st.readReady)
+	return true
+}
+
+func (st *Stream) grant(n int) {
+	st.mu.Lock()
+	st.sendWindow += n
+	st.mu.Unlock()
+	notify(st.writeReady)
+}
+
+func (st *Stream) remoteClose() {
+	st.mu.Lock()
+	st.remoteClosed = true
+	st.mu.Unlock()
+	st.session.removeStream(st.id)
+	notify(st.readReady)
+	notify(st.writeReady)
+}
+
+// fail aborts the stream locally with err.
+func (st *Stream) fail(err error) {
+	st.mu.Lock()
+	if st.err == nil {
+		st.err = err
+	}
+	st.mu.Unlock()
+	notify(st.readReady)
+	notify(st.writeReady)
+}
+
+// reset aborts the stream on both ends. The frame is sent asynchronously
+// because reset is called from the session's read loop.
+func (st *Stream) reset() {
+	st.session.removeStream(st.id)
+	st.fail(ErrStreamReset)
+	go func() { _ = st.session.writeFrame(frameReset, st.id, nil) }()
+}
+
+// LocalAddr implements net.Conn.
+func (st *Stream) LocalAddr() net.Addr { return tunnelAddr{} }
+
+// RemoteAddr implements net.Conn.
+func (st *Stream) RemoteAddr() net.Addr { return tunnelAddr{} }
+
+// SetDeadline implements net.Conn.
+func (st *Stream) SetDeadline(t time.Time) error {
+	st.mu.Lock()
+	st.readDeadline, st.writeDeadline = t, t
+	st.mu.Unlock()
+	notify(st.readReady)
+	notify(st.writeReady)
+	return nil
+}
+
+// SetReadDeadline implements net.Conn.
+func (st *Stream) SetReadDeadline(t time.Time) error {
+	st.mu.Lock()
+	st.readDeadline = t
+	st.mu.Unlock()
+	notify(st.readReady)
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.
+func (st *Stream) SetWriteDeadline(t time.Time) error {
+	st.mu.Lock()
+	st.writeDeadline = t
+	st.mu.Unlock()
+	notify(st.writeReady)
+	return nil
+}
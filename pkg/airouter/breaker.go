@@ -0,0 +1,95 @@
+package airouter
+
+import (
+	"time"
+)
+
+// BreakerState is the state of one provider's circuit breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen skips the provider until the cooldown ends.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe through; its outcome closes or
+	// re-opens the breaker.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerStatus is the admin view of one breaker.
+type BreakerStatus struct {
+	Provider            string       `json:"provider"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenUntil           *time.Time   `json:"openUntil,omitempty"`
+	LastError           string       `json:"lastError,omitempty"`
+	LastLatencyMs       int64        `json:"lastLatencyMs"`
+	Successes           int64        `json:"successes"`
+	Failures            int64        `json:"failures"`
+}
+
+// breaker tracks one provider. It is guarded by the Router's mutex.
+type breaker struct {
+	status BreakerStatus
+	// probing is set while the half-open probe is in flight so concurrent
+	// requests keep skipping the provider.
+	probing bool
+}
+
+// allow reports whether a request may use the provider now and, if not,
+// why. An expired open breaker moves to half-open and admits one probe.
+func (b *breaker) allow(now time.Time) (bool, string) {
+	switch b.status.State {
+	case BreakerOpen:
+		if b.status.OpenUntil != nil && now.Before(*b.status.OpenUntil) {
+			return false, "circuit open until " + b.status.OpenUntil.Format(time.RFC3339)
+		}
+		b.status.State = BreakerHalfOpen
+		b.probing = true
+		return true, ""
+	case BreakerHalfOpen:
+		if b.probing {
+			return false, "circuit half-open, probe in flight"
+		}
+		b.probing = true
+		return true, ""
+	default:
+		return true, ""
+	}
+}
+
+// record applies the outcome of one attempt. A call slower than slowCall
+// counts as a failure even when it succeeded.
+func (b *breaker) record(now time.Time, err error, latency time.Duration, cfg Config) {
+	b.probing = false
+	b.status.LastLatencyMs = latency.Milliseconds()
+	slow := cfg.BreakerSlowCall > 0 && latency > cfg.BreakerSlowCall
+	if err == nil && !slow {
+		b.status.Successes++
+		b.status.ConsecutiveFailures = 0
+		b.status.State = BreakerClosed
+		b.status.OpenUntil = nil
+		return
+	}
+
+	b.status.Failures++
+	b.status.ConsecutiveFailures++
+	if err != nil {
+		b.status.LastError = err.Error()
+	} else {
+		b.status.LastError = "slow call: " + latency.Round(time.Millisecond).String()
+	}
+	// A failed probe re-opens at once; otherwise wait for the threshold.
+	if b.status.State == BreakerHalfOpen || b.status.ConsecutiveFailures >= cfg.BreakerFailures {
+		until := now.Add(cfg.BreakerCooldown)
+		b.status.State = BreakerOpen
+		b.status.OpenUntil = &until
+	}
+}
+
+// release gives back a half-open probe slot that was never used, e.g.
+// because the request was cancelled before the provider answered.
+func (b *breaker) release() {
+	b.probing = false
+}
@@ -0,0 +1,244 @@
+// Package airouter picks which AI provider serves a request. It walks an
+// ordered fallback chain per task category, skips providers whose circuit
+// breaker is open, enforces daily token budgets per user and per category,
+// and keeps a bounded log of routing decisions for administrators.
+//
+// The package knows nothing about concrete providers: kc-agent and the
+// Stellar provider registry both hand it provider names and a callback that
+// performs one attempt.
+package airouter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task categories. They match the token-usage widget categories so the
+// budgets an operator sets line up with what users see.
+const (
+	CategoryMissions    = "missions"
+	CategoryDiagnose    = "diagnose"
+	CategoryInsights    = "insights"
+	CategoryPredictions = "predictions"
+	CategoryStellar     = "stellar"
+	CategoryOther       = "other"
+
+	// DefaultChainKey names the chain used by categories without their own.
+	DefaultChainKey = "default"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	// EnvChains holds per-category chains: "insights=ollama,openai;default=claude,openai".
+	EnvChains = "AI_ROUTER_CHAINS"
+	// EnvUserDailyTokens caps the tokens one user may spend per day.
+	EnvUserDailyTokens = "AI_ROUTER_USER_DAILY_TOKENS"
+	// EnvCategoryDailyTokens caps each category across all users: "missions=500000,stellar=200000".
+	EnvCategoryDailyTokens = "AI_ROUTER_CATEGORY_DAILY_TOKENS"
+	// EnvBudgetAction is "reject" (default) or "downgrade".
+	EnvBudgetAction = "AI_ROUTER_BUDGET_ACTION"
+	// EnvDowngradeChain lists the providers used once a budget is spent
+	// and the action is "downgrade", typically local models.
+	EnvDowngradeChain = "AI_ROUTER_DOWNGRADE_CHAIN"
+	// EnvBreakerFailures is the number of consecutive failures that opens a breaker.
+	EnvBreakerFailures = "AI_ROUTER_BREAKER_FAILURES"
+	// EnvBreakerSlowCall is the latency above which a call counts as a failure (0 disables).
+	EnvBreakerSlowCall = "AI_ROUTER_BREAKER_SLOW_CALL"
+	// EnvBreakerCooldown is how long an open breaker skips its provider.
+	EnvBreakerCooldown = "AI_ROUTER_BREAKER_COOLDOWN"
+)
+
+// BudgetAction says what happens to a request once a budget is spent.
+type BudgetAction string
+
+const (
+	// BudgetReject fails the request with a *BudgetError.
+	BudgetReject BudgetAction = "reject"
+	// BudgetDowngrade routes the request to the downgrade chain instead.
+	BudgetDowngrade BudgetAction = "downgrade"
+)
+
+const (
+	// defaultBreakerFailures opens a breaker after three failures in a row,
+	// enough to ride out a single transient error without hammering a
+	// provider that is down.
+	defaultBreakerFailures = 3
+	// defaultBreakerCooldown is how long an open breaker waits before it
+	// lets one probe request through.
+	defaultBreakerCooldown = time.Minute
+)
+
+// Config is the routing policy. The zero value routes every request to the
+// caller's preferred provider and fallback list with breakers at defaults
+// and no budgets.
+type Config struct {
+	// Chains maps a category (or DefaultChainKey) to its ordered providers.
+	// A configured chain replaces the caller's built-in fallback list.
+	Chains map[string][]string `json:"chains,omitempty"`
+	// UserDailyTokens caps each user's daily tokens; 0 means unlimited.
+	UserDailyTokens int64 `json:"userDailyTokens,omitempty"`
+	// CategoryDailyTokens caps each category's daily tokens across all users.
+	CategoryDailyTokens map[string]int64 `json:"categoryDailyTokens,omitempty"`
+	// BudgetAction applies to both budget kinds. Empty means reject.
+	BudgetAction BudgetAction `json:"budgetAction,omitempty"`
+	// DowngradeChain is used instead of the normal chain when a budget is
+	// spent and BudgetAction is downgrade. Its usage is still recorded but
+	// never rejected.
+	DowngradeChain []string `json:"downgradeChain,omitempty"`
+	// BreakerFailures is the consecutive-failure threshold (default 3).
+	BreakerFailures int `json:"breakerFailures"`
+	// BreakerSlowCall marks calls slower than this as failures; 0 disables.
+	BreakerSlowCall time.Duration `json:"breakerSlowCall"`
+	// BreakerCooldown is how long an open breaker stays open (default 1m).
+	BreakerCooldown time.Duration `json:"breakerCooldown"`
+}
+
+// ConfigFromEnv reads the AI_ROUTER_* variables. Malformed values are
+// reported rather than ignored so a typo in a budget cannot silently
+// disable it.
+func ConfigFromEnv() (Config, error) {
+	var cfg Config
+	var err error
+	if cfg.Chains, err = parseChains(os.Getenv(EnvChains)); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", EnvChains, err)
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvUserDailyTokens)); v != "" {
+		if cfg.UserDailyTokens, err = parseTokens(v); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", EnvUserDailyTokens, err)
+		}
+	}
+	if cfg.CategoryDailyTokens, err = parseCategoryTokens(os.Getenv(EnvCategoryDailyTokens)); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", EnvCategoryDailyTokens, err)
+	}
+	switch action := BudgetAction(strings.ToLower(strings.TrimSpace(os.Getenv(EnvBudgetAction)))); action {
+	case "", BudgetReject, BudgetDowngrade:
+		cfg.BudgetAction = action
+	default:
+		return Config{}, fmt.Errorf("%s: unknown action %q (want reject or downgrade)", EnvBudgetAction, action)
+	}
+	cfg.DowngradeChain = splitList(os.Getenv(EnvDowngradeChain))
+	if v := strings.TrimSpace(os.Getenv(EnvBreakerFailures)); v != "" {
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil || n < 1 {
+			return Config{}, fmt.Errorf("%s: want a positive integer, got %q", EnvBreakerFailures, v)
+		}
+		cfg.BreakerFailures = n
+	}
+	if cfg.BreakerSlowCall, err = parseDuration(os.Getenv(EnvBreakerSlowCall)); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", EnvBreakerSlowCall, err)
+	}
+	if cfg.BreakerCooldown, err = parseDuration(os.Getenv(EnvBreakerCooldown)); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", EnvBreakerCooldown, err)
+	}
+	return cfg, nil
+}
+
+// NormalizeCategory maps a client-supplied category onto the known set so
+// untrusted input cannot create arbitrary budget buckets. Unknown values
+// become CategoryOther.
+func NormalizeCategory(category string) string {
+	switch category {
+	case CategoryMissions, CategoryDiagnose, CategoryInsights, CategoryPredictions, CategoryStellar:
+		return category
+	default:
+		return CategoryOther
+	}
+}
+
+// withDefaults fills unset breaker settings.
+func (c Config) withDefaults() Config {
+	if c.BreakerFailures <= 0 {
+		c.BreakerFailures = defaultBreakerFailures
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = defaultBreakerCooldown
+	}
+	if c.BudgetAction == "" {
+		c.BudgetAction = BudgetReject
+	}
+	return c
+}
+
+// chain returns the configured chain for category, falling back to the
+// default chain. ok is false when neither is configured.
+func (c Config) chain(category string) ([]string, bool) {
+	if chain, ok := c.Chains[category]; ok && len(chain) > 0 {
+		return chain, true
+	}
+	if chain, ok := c.Chains[DefaultChainKey]; ok && len(chain) > 0 {
+		return chain, true
+	}
+	return nil, false
+}
+
+func parseChains(raw string) (map[string][]string, error) {
+	out := map[string][]string{}
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		category, list, ok := strings.Cut(entry, "=")
+		category = strings.TrimSpace(category)
+		providers := splitList(list)
+		if !ok || category == "" || len(providers) == 0 {
+			return nil, fmt.Errorf("entry %q: want category=provider[,provider...]", entry)
+		}
+		out[category] = providers
+	}
+	return out, nil
+}
+
+func parseCategoryTokens(raw string) (map[string]int64, error) {
+	out := map[string]int64{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		category, value, ok := strings.Cut(entry, "=")
+		category = strings.TrimSpace(category)
+		if !ok || category == "" {
+			return nil, fmt.Errorf("entry %q: want category=tokens", entry)
+		}
+		n, err := parseTokens(value)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		out[category] = n
+	}
+	return out, nil
+}
+
+func parseTokens(raw string) (int64, error) {
+	n, err := strconv.ParseInt(strings.ReplaceAll(strings.TrimSpace(raw), "_", ""), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("want a non-negative token count, got %q", raw)
+	}
+	return n, nil
+}
+
+func parseDuration(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("want a duration such as 45s, got %q", raw)
+	}
+	return d, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
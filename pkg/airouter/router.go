@@ -0,0 +1,374 @@
+package airouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxDecisions bounds the in-memory decision log. It is sized for "what
+// happened in the last few minutes" during an incident, not for history;
+// token history lives in the usage store.
+const maxDecisions = 200
+
+// ErrNoProvider is returned when every provider in the chain was
+// unavailable, skipped by its breaker, or failed.
+var ErrNoProvider = errors.New("no AI provider available")
+
+// BudgetError is returned when a daily token budget is spent and the
+// budget action is reject.
+type BudgetError struct {
+	// Scope is "user" or "category".
+	Scope    string `json:"scope"`
+	UserID   string `json:"userId,omitempty"`
+	Category string `json:"category"`
+	Limit    int64  `json:"limit"`
+	Used     int64  `json:"used"`
+	// Unverified is set when the usage store could not be read. The
+	// budget is then treated as spent rather than left unmetered.
+	Unverified bool `json:"unverified,omitempty"`
+}
+
+func (e *BudgetError) Error() string {
+	if e.Unverified {
+		if e.Scope == "user" {
+			return "daily AI token budget for user could not be checked"
+		}
+		return fmt.Sprintf("daily AI token budget for %s could not be checked", e.Category)
+	}
+	if e.Scope == "user" {
+		return fmt.Sprintf("daily AI token budget exhausted for user (%d of %d tokens used)", e.Used, e.Limit)
+	}
+	return fmt.Sprintf("daily AI token budget exhausted for %s (%d of %d tokens used)", e.Category, e.Used, e.Limit)
+}
+
+// permanentError stops the chain: the failure must not be retried on
+// another provider, e.g. because output was already streamed to the user.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err so Route returns it instead of trying the next
+// provider. The failure still counts against the provider's breaker.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// Request describes what needs routing.
+type Request struct {
+	// UserID scopes the per-user budget; empty skips it (system work).
+	UserID   string
+	Category string
+	// Preferred is tried first, e.g. the agent the user selected.
+	Preferred string
+	// Fallback is the caller's built-in order, used when no chain is
+	// configured for the category.
+	Fallback []string
+	// Pinned restricts the request to Preferred: budgets and its breaker
+	// still apply, but nothing else is tried. Used when the caller fans
+	// out to several providers itself or cannot replay a request.
+	Pinned bool
+}
+
+// Skip records a provider that was passed over without being called.
+type Skip struct {
+	Provider string `json:"provider"`
+	Reason   string `json:"reason"`
+}
+
+// Attempt records one failed call.
+type Attempt struct {
+	Provider  string `json:"provider"`
+	Error     string `json:"error"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// Decision is one routed request as shown to administrators.
+type Decision struct {
+	Time       time.Time `json:"time"`
+	UserID     string    `json:"userId,omitempty"`
+	Category   string    `json:"category"`
+	Chain      []string  `json:"chain"`
+	Downgraded bool      `json:"downgraded,omitempty"`
+	Skipped    []Skip    `json:"skipped,omitempty"`
+	Attempts   []Attempt `json:"attempts,omitempty"`
+	Selected   string    `json:"selected,omitempty"`
+	LatencyMs  int64     `json:"latencyMs,omitempty"`
+	Tokens     int64     `json:"tokens,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Snapshot is the admin view of the router.
+type Snapshot struct {
+	Config   Config          `json:"config"`
+	Breakers []BreakerStatus `json:"breakers"`
+	// CategoryUsage is today's spend per category across all users.
+	CategoryUsage map[string]int64 `json:"categoryUsage"`
+	// Decisions are the most recent routing decisions, newest first.
+	Decisions []Decision `json:"decisions"`
+}
+
+// Router applies one routing policy. It is safe for concurrent use.
+type Router struct {
+	cfg   Config
+	usage UsageStore
+	now   func() time.Time
+
+	mu           sync.Mutex
+	breakers     map[string]*breaker
+	decisions    []Decision
+	nextDecision int
+}
+
+// New returns a Router for cfg. usage may be nil, in which case budgets
+// are tracked in memory and reset with the process.
+func New(cfg Config, usage UsageStore) *Router {
+	if usage == nil {
+		usage = NewMemoryUsage()
+	}
+	return &Router{
+		cfg:      cfg.withDefaults(),
+		usage:    usage,
+		now:      time.Now,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// Config returns the effective policy.
+func (r *Router) Config() Config {
+	return r.cfg
+}
+
+// Route runs call against each provider of the request's chain until one
+// succeeds, and returns that provider's name. available reports whether a
+// provider is registered and usable; call performs the request and returns
+// the tokens it spent. A *BudgetError is returned when a budget is spent
+// and the action is reject; ErrNoProvider wraps the last failure when the
+// chain is exhausted.
+func (r *Router) Route(ctx context.Context, req Request, available func(string) bool, call func(ctx context.Context, provider string) (int64, error)) (string, error) {
+	d := Decision{Time: r.now(), UserID: req.UserID, Category: req.Category}
+	start := d.Time
+
+	chain, err := r.plan(ctx, req, &d)
+	if err != nil {
+		d.Error = err.Error()
+		r.logDecision(d)
+		return "", err
+	}
+	d.Chain = chain
+
+	var lastErr error
+	for _, name := range chain {
+		if available != nil && !available(name) {
+			d.Skipped = append(d.Skipped, Skip{Provider: name, Reason: "unavailable"})
+			continue
+		}
+		if ok, reason := r.allow(name); !ok {
+			d.Skipped = append(d.Skipped, Skip{Provider: name, Reason: reason})
+			continue
+		}
+
+		callStart := r.now()
+		tokens, callErr := call(ctx, name)
+		latency := r.now().Sub(callStart)
+
+		// A cancelled request says nothing about the provider's health.
+		if callErr != nil && ctx.Err() != nil {
+			r.release(name)
+			d.Attempts = append(d.Attempts, Attempt{Provider: name, Error: callErr.Error(), LatencyMs: latency.Milliseconds()})
+			d.Error = ctx.Err().Error()
+			r.logDecision(d)
+			return "", callErr
+		}
+		r.record(name, callErr, latency)
+
+		if callErr == nil {
+			d.Selected = name
+			d.Tokens = tokens
+			d.LatencyMs = r.now().Sub(start).Milliseconds()
+			r.recordUsage(ctx, req, tokens)
+			r.logDecision(d)
+			return name, nil
+		}
+
+		slog.Warn("[AIRouter] provider failed", "provider", name, "category", req.Category, "error", callErr)
+		d.Attempts = append(d.Attempts, Attempt{Provider: name, Error: callErr.Error(), LatencyMs: latency.Milliseconds()})
+		var perm permanentError
+		if errors.As(callErr, &perm) {
+			d.Error = callErr.Error()
+			r.logDecision(d)
+			return "", perm.err
+		}
+		lastErr = callErr
+	}
+
+	err = ErrNoProvider
+	if lastErr != nil {
+		err = fmt.Errorf("%w: %w", ErrNoProvider, lastErr)
+	}
+	d.Error = err.Error()
+	r.logDecision(d)
+	return "", err
+}
+
+// Snapshot returns the breakers, today's category usage and the recent
+// decisions. Category usage is left empty when the usage store fails.
+func (r *Router) Snapshot(ctx context.Context) Snapshot {
+	categories, err := r.usage.CategoryUsage(ctx)
+	if err != nil {
+		slog.Error("[AIRouter] failed to read category token usage", "error", err)
+		categories = map[string]int64{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := Snapshot{
+		Config:        r.cfg,
+		Breakers:      make([]BreakerStatus, 0, len(r.breakers)),
+		CategoryUsage: categories,
+		Decisions:     make([]Decision, 0, len(r.decisions)),
+	}
+	for _, b := range r.breakers {
+		out.Breakers = append(out.Breakers, b.status)
+	}
+	sort.Slice(out.Breakers, func(i, j int) bool { return out.Breakers[i].Provider < out.Breakers[j].Provider })
+	// Walk the ring backwards from the newest entry.
+	for i := 0; i < len(r.decisions); i++ {
+		idx := (r.nextDecision - 1 - i + len(r.decisions)) % len(r.decisions)
+		out.Decisions = append(out.Decisions, r.decisions[idx])
+	}
+	return out
+}
+
+// UserUsage returns today's usage for userID from the usage store.
+func (r *Router) UserUsage(ctx context.Context, userID string) (Usage, error) {
+	return r.usage.UserUsage(ctx, userID)
+}
+
+// plan checks the budgets and returns the ordered chain to try.
+func (r *Router) plan(ctx context.Context, req Request, d *Decision) ([]string, error) {
+	if budgetErr := r.checkBudget(ctx, req); budgetErr != nil {
+		if r.cfg.BudgetAction == BudgetDowngrade && len(r.cfg.DowngradeChain) > 0 {
+			slog.Info("[AIRouter] budget exhausted, downgrading", "scope", budgetErr.Scope, "category", req.Category, "user", req.UserID, "chain", r.cfg.DowngradeChain)
+			d.Downgraded = true
+			return dedupe(r.cfg.DowngradeChain), nil
+		}
+		slog.Warn("[AIRouter] budget exhausted, rejecting", "scope", budgetErr.Scope, "category", req.Category, "user", req.UserID, "used", budgetErr.Used, "limit", budgetErr.Limit)
+		return nil, budgetErr
+	}
+
+	if req.Pinned {
+		return dedupe([]string{req.Preferred}), nil
+	}
+	rest := req.Fallback
+	if chain, ok := r.cfg.chain(req.Category); ok {
+		rest = chain
+	}
+	chain := make([]string, 0, len(rest)+1)
+	if req.Preferred != "" {
+		chain = append(chain, req.Preferred)
+	}
+	return dedupe(append(chain, rest...)), nil
+}
+
+// checkBudget returns the first spent budget for the request, or nil. A
+// usage store error fails closed: the budget is reported as spent, so the
+// downgrade chain or a rejection applies instead of unmetered spend.
+func (r *Router) checkBudget(ctx context.Context, req Request) *BudgetError {
+	if limit, ok := r.cfg.CategoryDailyTokens[req.Category]; ok && limit > 0 {
+		categories, err := r.usage.CategoryUsage(ctx)
+		if err != nil {
+			slog.Error("[AIRouter] failed to read category token usage, treating budget as spent", "category", req.Category, "error", err)
+			return &BudgetError{Scope: "category", Category: req.Category, Limit: limit, Unverified: true}
+		}
+		if used := categories[req.Category]; used >= limit {
+			return &BudgetError{Scope: "category", Category: req.Category, Limit: limit, Used: used}
+		}
+	}
+	if req.UserID != "" && r.cfg.UserDailyTokens > 0 {
+		u, err := r.usage.UserUsage(ctx, req.UserID)
+		if err != nil {
+			slog.Error("[AIRouter] failed to read user token usage, treating budget as spent", "user", req.UserID, "error", err)
+			return &BudgetError{Scope: "user", UserID: req.UserID, Category: req.Category, Limit: r.cfg.UserDailyTokens, Unverified: true}
+		}
+		if u.Total >= r.cfg.UserDailyTokens {
+			return &BudgetError{Scope: "user", UserID: req.UserID, Category: req.Category, Limit: r.cfg.UserDailyTokens, Used: u.Total}
+		}
+	}
+	return nil
+}
+
+func (r *Router) recordUsage(ctx context.Context, req Request, tokens int64) {
+	if tokens <= 0 {
+		return
+	}
+	if err := r.usage.Record(ctx, req.UserID, req.Category, tokens); err != nil {
+		slog.Error("[AIRouter] failed to record token usage", "user", req.UserID, "category", req.Category, "tokens", tokens, "error", err)
+	}
+}
+
+func (r *Router) allow(name string) (bool, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.breaker(name).allow(r.now())
+}
+
+func (r *Router) record(name string, err error, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.breaker(name)
+	wasOpen := b.status.State == BreakerOpen
+	b.record(r.now(), err, latency, r.cfg)
+	if !wasOpen && b.status.State == BreakerOpen {
+		slog.Warn("[AIRouter] circuit opened", "provider", name, "failures", b.status.ConsecutiveFailures, "until", b.status.OpenUntil, "lastError", b.status.LastError)
+	}
+}
+
+func (r *Router) release(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breaker(name).release()
+}
+
+// breaker returns the breaker for name, creating it closed. Callers hold r.mu.
+func (r *Router) breaker(name string) *breaker {
+	b, ok := r.breakers[name]
+	if !ok {
+		b = &breaker{status: BreakerStatus{Provider: name, State: BreakerClosed}}
+		r.breakers[name] = b
+	}
+	return b
+}
+
+func (r *Router) logDecision(d Decision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.decisions) < maxDecisions {
+		r.decisions = append(r.decisions, d)
+		r.nextDecision = len(r.decisions) % maxDecisions
+		return
+	}
+	r.decisions[r.nextDecision] = d
+	r.nextDecision = (r.nextDecision + 1) % maxDecisions
+}
+
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,290 @@
+package airouter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock lets tests move time without sleeping.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestRouter(cfg Config) (*Router, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)}
+	r := New(cfg, nil)
+	r.now = clock.now
+	r.usage.(*MemoryUsage).now = clock.now
+	return r, clock
+}
+
+func failing(failures map[string]error, tokens int64, calls *[]string) func(context.Context, string) (int64, error) {
+	return func(_ context.Context, name string) (int64, error) {
+		*calls = append(*calls, name)
+		if err := failures[name]; err != nil {
+			return 0, err
+		}
+		return tokens, nil
+	}
+}
+
+func TestRoute_ChainOrderAndFallback(t *testing.T) {
+	r, _ := newTestRouter(Config{Chains: map[string][]string{
+		CategoryInsights: {"ollama", "openai"},
+		DefaultChainKey:  {"claude", "openai"},
+	}})
+
+	var calls []string
+	name, err := r.Route(context.Background(), Request{Category: CategoryInsights, Preferred: "gemini", Fallback: []string{"bob"}},
+		func(n string) bool { return n != "gemini" },
+		failing(map[string]error{"ollama": errors.New("connection refused")}, 10, &calls))
+	require.NoError(t, err)
+	assert.Equal(t, "openai", name)
+	assert.Equal(t, []string{"ollama", "openai"}, calls, "configured chain replaces the caller fallback")
+
+	// Unconfigured categories use the default chain; without any chain the
+	// caller's fallback applies.
+	calls = nil
+	name, err = r.Route(context.Background(), Request{Category: CategoryDiagnose}, nil, failing(nil, 1, &calls))
+	require.NoError(t, err)
+	assert.Equal(t, "claude", name)
+
+	r2, _ := newTestRouter(Config{})
+	calls = nil
+	name, err = r2.Route(context.Background(), Request{Category: CategoryDiagnose, Preferred: "bob", Fallback: []string{"claude-code", "bob"}}, nil,
+		failing(map[string]error{"bob": errors.New("boom")}, 1, &calls))
+	require.NoError(t, err)
+	assert.Equal(t, "claude-code", name)
+	assert.Equal(t, []string{"bob", "claude-code"}, calls)
+
+	d := r.Snapshot(context.Background()).Decisions
+	require.Len(t, d, 2)
+	assert.Equal(t, CategoryDiagnose, d[0].Category, "newest first")
+	assert.Equal(t, []Skip{{Provider: "gemini", Reason: "unavailable"}}, d[1].Skipped)
+	assert.Equal(t, "ollama", d[1].Attempts[0].Provider)
+	assert.Equal(t, "openai", d[1].Selected)
+}
+
+func TestRoute_BreakerOpensAndRecovers(t *testing.T) {
+	r, clock := newTestRouter(Config{BreakerFailures: 2, BreakerCooldown: time.Minute})
+	down := map[string]error{"openai": errors.New("429 quota exceeded")}
+	req := Request{Category: CategoryPredictions, Fallback: []string{"openai", "ollama"}}
+
+	var calls []string
+	for i := 0; i < 3; i++ {
+		_, err := r.Route(context.Background(), req, nil, failing(down, 1, &calls))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"openai", "ollama", "openai", "ollama", "ollama"}, calls, "third request skips the open breaker")
+
+	snap := r.Snapshot(context.Background())
+	require.Len(t, snap.Breakers, 2)
+	assert.Equal(t, "openai", snap.Breakers[1].Provider)
+	assert.Equal(t, BreakerOpen, snap.Breakers[1].State)
+	assert.Contains(t, snap.Decisions[0].Skipped[0].Reason, "circuit open")
+
+	// After the cooldown one probe goes through and closes the breaker.
+	clock.advance(time.Minute + time.Second)
+	calls = nil
+	name, err := r.Route(context.Background(), req, nil, failing(nil, 1, &calls))
+	require.NoError(t, err)
+	assert.Equal(t, "openai", name)
+	assert.Equal(t, BreakerClosed, r.Snapshot(context.Background()).Breakers[1].State)
+}
+
+func TestRoute_SlowCallsTripBreaker(t *testing.T) {
+	r, clock := newTestRouter(Config{BreakerFailures: 1, BreakerSlowCall: 10 * time.Second})
+	slow := func(_ context.Context, _ string) (int64, error) {
+		clock.advance(30 * time.Second)
+		return 5, nil
+	}
+	name, err := r.Route(context.Background(), Request{Category: CategoryDiagnose, Fallback: []string{"claude"}}, nil, slow)
+	require.NoError(t, err, "a slow answer is still returned")
+	assert.Equal(t, "claude", name)
+
+	_, err = r.Route(context.Background(), Request{Category: CategoryDiagnose, Fallback: []string{"claude"}}, nil, slow)
+	assert.ErrorIs(t, err, ErrNoProvider)
+	assert.Contains(t, r.Snapshot(context.Background()).Breakers[0].LastError, "slow call")
+}
+
+func TestRoute_PermanentAndCancelledErrors(t *testing.T) {
+	r, _ := newTestRouter(Config{})
+	streamErr := errors.New("stream broke mid-answer")
+	var calls []string
+	_, err := r.Route(context.Background(), Request{Category: CategoryDiagnose, Fallback: []string{"a", "b"}}, nil,
+		func(_ context.Context, n string) (int64, error) {
+			calls = append(calls, n)
+			return 0, Permanent(streamErr)
+		})
+	assert.Equal(t, streamErr, err)
+	assert.Equal(t, []string{"a"}, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.Route(ctx, Request{Category: CategoryDiagnose, Fallback: []string{"c", "d"}}, nil,
+		func(ctx context.Context, _ string) (int64, error) { return 0, ctx.Err() })
+	assert.ErrorIs(t, err, context.Canceled)
+	for _, b := range r.Snapshot(context.Background()).Breakers {
+		if b.Provider == "c" {
+			assert.Zero(t, b.Failures, "cancellation is not a provider failure")
+		}
+	}
+}
+
+func TestRoute_Budgets(t *testing.T) {
+	r, clock := newTestRouter(Config{
+		UserDailyTokens:     100,
+		CategoryDailyTokens: map[string]int64{CategoryMissions: 150},
+	})
+	var calls []string
+	call := failing(nil, 80, &calls)
+	req := Request{UserID: "alice", Category: CategoryMissions, Fallback: []string{"openai"}}
+
+	_, err := r.Route(context.Background(), req, nil, call)
+	require.NoError(t, err)
+	_, err = r.Route(context.Background(), req, nil, call)
+	require.NoError(t, err, "budgets are checked before the call, so the last call may overshoot")
+
+	_, err = r.Route(context.Background(), req, nil, call)
+	var budgetErr *BudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "category", budgetErr.Scope)
+	assert.Equal(t, int64(160), budgetErr.Used)
+
+	_, err = r.Route(context.Background(), Request{UserID: "alice", Category: CategoryDiagnose, Fallback: []string{"openai"}}, nil, call)
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "user", budgetErr.Scope)
+
+	_, err = r.Route(context.Background(), Request{UserID: "bob", Category: CategoryDiagnose, Fallback: []string{"openai"}}, nil, call)
+	assert.NoError(t, err, "other users keep their own budget")
+	assert.Len(t, calls, 3)
+
+	// Budgets reset with the day.
+	clock.advance(24 * time.Hour)
+	_, err = r.Route(context.Background(), req, nil, call)
+	assert.NoError(t, err)
+}
+
+func TestRoute_CategoryBudgetSurvivesRestart(t *testing.T) {
+	cfg := Config{CategoryDailyTokens: map[string]int64{CategoryMissions: 100}}
+	usage := NewMemoryUsage()
+	var calls []string
+	call := failing(nil, 120, &calls)
+	req := Request{Category: CategoryMissions, Fallback: []string{"openai"}}
+
+	_, err := New(cfg, usage).Route(context.Background(), req, nil, call)
+	require.NoError(t, err, "system work is metered too")
+
+	restarted := New(cfg, usage)
+	_, err = restarted.Route(context.Background(), Request{UserID: "alice", Category: CategoryMissions, Fallback: []string{"openai"}}, nil, call)
+	var budgetErr *BudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "category", budgetErr.Scope)
+	assert.Equal(t, int64(120), budgetErr.Used)
+	assert.Equal(t, map[string]int64{CategoryMissions: 120}, restarted.Snapshot(context.Background()).CategoryUsage)
+	assert.Len(t, calls, 1)
+}
+
+// brokenUsage is a UsageStore whose reads fail.
+type brokenUsage struct{ *MemoryUsage }
+
+func (brokenUsage) UserUsage(context.Context, string) (Usage, error) {
+	return Usage{}, errors.New("database is locked")
+}
+
+func (brokenUsage) CategoryUsage(context.Context) (map[string]int64, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestRoute_UsageStoreErrorFailsClosed(t *testing.T) {
+	usage := brokenUsage{NewMemoryUsage()}
+	var calls []string
+	call := failing(nil, 10, &calls)
+
+	for _, cfg := range []Config{
+		{UserDailyTokens: 100},
+		{CategoryDailyTokens: map[string]int64{CategoryMissions: 100}},
+	} {
+		_, err := New(cfg, usage).Route(context.Background(), Request{UserID: "alice", Category: CategoryMissions, Fallback: []string{"openai"}}, nil, call)
+		var budgetErr *BudgetError
+		require.ErrorAs(t, err, &budgetErr)
+		assert.True(t, budgetErr.Unverified)
+		assert.Contains(t, budgetErr.Error(), "could not be checked")
+	}
+	assert.Empty(t, calls)
+
+	r := New(Config{UserDailyTokens: 100, BudgetAction: BudgetDowngrade, DowngradeChain: []string{"ollama"}}, usage)
+	_, err := r.Route(context.Background(), Request{UserID: "alice", Category: CategoryMissions, Preferred: "openai"}, nil, call)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ollama"}, calls, "an unverified budget takes the downgrade chain")
+}
+
+func TestRoute_BudgetDowngrade(t *testing.T) {
+	r, _ := newTestRouter(Config{
+		UserDailyTokens: 10,
+		BudgetAction:    BudgetDowngrade,
+		DowngradeChain:  []string{"ollama"},
+	})
+	var calls []string
+	req := Request{UserID: "alice", Category: CategoryDiagnose, Preferred: "openai"}
+	for i := 0; i < 2; i++ {
+		_, err := r.Route(context.Background(), req, nil, failing(nil, 50, &calls))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"openai", "ollama"}, calls)
+	assert.True(t, r.Snapshot(context.Background()).Decisions[0].Downgraded)
+}
+
+func TestRoute_DecisionLogIsBounded(t *testing.T) {
+	r, _ := newTestRouter(Config{})
+	var calls []string
+	for i := 0; i < maxDecisions+5; i++ {
+		_, err := r.Route(context.Background(), Request{Category: CategoryDiagnose, UserID: string(rune('a' + i%26)), Fallback: []string{"x"}}, nil, failing(nil, 1, &calls))
+		require.NoError(t, err)
+	}
+	d := r.Snapshot(context.Background()).Decisions
+	require.Len(t, d, maxDecisions)
+	assert.Equal(t, string(rune('a'+(maxDecisions+4)%26)), d[0].UserID)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvChains, "insights=ollama, openai ; default=claude")
+	t.Setenv(EnvUserDailyTokens, "200_000")
+	t.Setenv(EnvCategoryDailyTokens, "missions=500000,stellar=1000")
+	t.Setenv(EnvBudgetAction, "Downgrade")
+	t.Setenv(EnvDowngradeChain, "ollama")
+	t.Setenv(EnvBreakerFailures, "5")
+	t.Setenv(EnvBreakerSlowCall, "45s")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"insights": {"ollama", "openai"}, "default": {"claude"}}, cfg.Chains)
+	assert.Equal(t, int64(200000), cfg.UserDailyTokens)
+	assert.Equal(t, map[string]int64{"missions": 500000, "stellar": 1000}, cfg.CategoryDailyTokens)
+	assert.Equal(t, BudgetDowngrade, cfg.BudgetAction)
+	assert.Equal(t, []string{"ollama"}, cfg.DowngradeChain)
+	assert.Equal(t, 5, cfg.BreakerFailures)
+	assert.Equal(t, 45*time.Second, cfg.BreakerSlowCall)
+	assert.Equal(t, defaultBreakerCooldown, New(cfg, nil).Config().BreakerCooldown)
+
+	for env, bad := range map[string]string{
+		EnvChains:              "insights",
+		EnvUserDailyTokens:     "-1",
+		EnvCategoryDailyTokens: "missions",
+		EnvBudgetAction:        "throttle",
+		EnvBreakerFailures:     "0",
+		EnvBreakerCooldown:     "soon",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, bad)
+			_, err := ConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package airouter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// dayLayout keys daily counters by calendar day in local time, matching
+// the daily reset of the token-usage widget.
+const dayLayout = "2006-01-02"
+
+// DayKey returns the key of the daily budget window containing t.
+func DayKey(t time.Time) string {
+	return t.Format(dayLayout)
+}
+
+// Usage is one user's token spend for the current day.
+type Usage struct {
+	Total      int64            `json:"total"`
+	ByCategory map[string]int64 `json:"byCategory,omitempty"`
+}
+
+// UsageStore persists daily token usage per user and per category. The
+// console backs it with the server-only ai_budget_usage table so budgets
+// survive restarts and are shared by replicas; kc-agent uses MemoryUsage.
+type UsageStore interface {
+	// UserUsage returns today's usage for userID.
+	UserUsage(ctx context.Context, userID string) (Usage, error)
+	// CategoryUsage returns today's spend per category across all users,
+	// including system work.
+	CategoryUsage(ctx context.Context) (map[string]int64, error)
+	// Record adds tokens spent in category today. An empty userID is
+	// system work and only counts toward the category totals.
+	Record(ctx context.Context, userID, category string, tokens int64) error
+}
+
+// MemoryUsage is an in-process UsageStore whose counters reset at the
+// start of each local day.
+type MemoryUsage struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	day        string
+	users      map[string]*Usage
+	categories map[string]int64
+}
+
+// NewMemoryUsage returns an empty MemoryUsage.
+func NewMemoryUsage() *MemoryUsage {
+	return &MemoryUsage{now: time.Now, users: make(map[string]*Usage), categories: make(map[string]int64)}
+}
+
+// UserUsage implements UsageStore.
+func (m *MemoryUsage) UserUsage(_ context.Context, userID string) (Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollover()
+	u, ok := m.users[userID]
+	if !ok {
+		return Usage{}, nil
+	}
+	out := Usage{Total: u.Total, ByCategory: make(map[string]int64, len(u.ByCategory))}
+	for k, v := range u.ByCategory {
+		out.ByCategory[k] = v
+	}
+	return out, nil
+}
+
+// CategoryUsage implements UsageStore.
+func (m *MemoryUsage) CategoryUsage(_ context.Context) (map[string]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollover()
+	out := make(map[string]int64, len(m.categories))
+	for k, v := range m.categories {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// Record implements UsageStore.
+func (m *MemoryUsage) Record(_ context.Context, userID, category string, tokens int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollover()
+	m.categories[category] += tokens
+	if userID == "" {
+		return nil
+	}
+	u, ok := m.users[userID]
+	if !ok {
+		u = &Usage{ByCategory: map[string]int64{}}
+		m.users[userID] = u
+	}
+	u.Total += tokens
+	u.ByCategory[category] += tokens
+	return nil
+}
+
+func (m *MemoryUsage) rollover() {
+	if day := DayKey(m.now()); day != m.day {
+		m.day = day
+		m.users = make(map[string]*Usage)
+		m.categories = make(map[string]int64)
+	}
+}
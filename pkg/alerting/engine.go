@@ -0,0 +1,516 @@
+package alerting
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// DefaultInterval is how often rules are evaluated when no interval is
+// configured.
+const DefaultInterval = time.Minute
+
+// Alert instance statuses. A pending instance matches its rule but has not
+// yet held for the rule's duration; a firing one has been sent.
+const (
+	StatusPending = "pending"
+	StatusFiring  = "firing"
+	// statusResolved is sent when a firing instance stops matching.
+	statusResolved = "resolved"
+)
+
+// ErrRuleNotFound is returned for an unknown rule ID.
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+// Engine evaluates alert rules against a Source and sends state changes to
+// a Notifier. Instance state is persisted, so an alert that is firing when
+// the console restarts resolves (rather than fires again) once it clears.
+type Engine struct {
+	store    Store
+	source   Source
+	notifier Notifier
+	interval time.Duration
+	now      func() time.Time
+
+	// mu serializes evaluation cycles and rule writes so a rule deleted
+	// mid-cycle cannot have its states written back.
+	mu sync.Mutex
+}
+
+// NewEngine creates an engine that evaluates every interval, or every
+// DefaultInterval when interval is not positive.
+func NewEngine(s Store, source Source, notifier Notifier, interval time.Duration) *Engine {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Engine{
+		store:    s,
+		source:   source,
+		notifier: notifier,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run evaluates rules immediately and then every interval until ctx is
+// cancelled.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Evaluate(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("[Alerting] evaluation failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finding is one resource matching a rule in the current cycle.
+type finding struct {
+	fingerprint string
+	alert       notifications.Alert
+}
+
+// Evaluate runs one evaluation cycle over every rule.
+func (e *Engine) Evaluate(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rules, err := e.listRules(ctx)
+	if err != nil {
+		return fmt.Errorf("list rules: %w", err)
+	}
+	stored, err := e.store.ListAlertStates(ctx)
+	if err != nil {
+		return fmt.Errorf("list alert states: %w", err)
+	}
+	states := make(map[string]map[string]store.AlertState)
+	for _, st := range stored {
+		if states[st.RuleID] == nil {
+			states[st.RuleID] = make(map[string]store.AlertState)
+		}
+		states[st.RuleID][st.Fingerprint] = st
+	}
+	clusters, err := e.source.Clusters(ctx)
+	if err != nil {
+		return fmt.Errorf("list clusters: %w", err)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		ruleStates := states[rule.ID]
+		delete(states, rule.ID)
+		if !rule.Enabled {
+			e.clear(ctx, rule, ruleStates, nil, nil)
+			continue
+		}
+		findings, unknown := e.evaluateRule(ctx, rule, clusters)
+		e.apply(ctx, rule, ruleStates, findings, unknown)
+	}
+
+	// States left over belong to rules that no longer exist.
+	for ruleID, orphaned := range states {
+		for fp := range orphaned {
+			if err := e.store.DeleteAlertState(ctx, ruleID, fp); err != nil {
+				slog.Warn("[Alerting] failed to delete orphaned alert state", "rule", ruleID, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// apply advances the state of every instance of rule given this cycle's
+// findings. Instances on clusters in unknown keep their state.
+func (e *Engine) apply(ctx context.Context, rule *Rule, states map[string]store.AlertState, findings []finding, unknown map[string]bool) {
+	now := e.now().UTC()
+	hold := time.Duration(rule.Condition.Duration) * time.Second
+	seen := make(map[string]bool, len(findings))
+
+	for _, f := range findings {
+		seen[f.fingerprint] = true
+		st, ok := states[f.fingerprint]
+		if !ok {
+			st = store.AlertState{
+				RuleID:      rule.ID,
+				Fingerprint: f.fingerprint,
+				Status:      StatusPending,
+				ActiveSince: now,
+			}
+		}
+		alert := f.alert
+		alert.ID = alertID(rule.ID, f.fingerprint)
+		alert.RuleID = rule.ID
+		alert.RuleName = rule.Name
+		alert.Severity = rule.Severity
+
+		if st.Status == StatusPending && now.Sub(st.ActiveSince) >= hold {
+			alert.Status = StatusFiring
+			alert.FiredAt = now
+			if err := e.send(rule, alert); err != nil {
+				// Stay pending so the next cycle retries the notification.
+				slog.Warn("[Alerting] failed to send alert", "rule", rule.ID, "alert", alert.ID, "error", err)
+			} else {
+				st.Status = StatusFiring
+				st.FiredAt = &now
+			}
+		}
+		if st.Status == StatusPending {
+			alert.Status = StatusPending
+		} else if st.FiredAt != nil {
+			alert.Status = StatusFiring
+			alert.FiredAt = *st.FiredAt
+		}
+		data, err := json.Marshal(alert)
+		if err != nil {
+			slog.Warn("[Alerting] failed to encode alert", "rule", rule.ID, "error", err)
+			continue
+		}
+		st.Alert = data
+		if err := e.store.SaveAlertState(ctx, st); err != nil {
+			slog.Warn("[Alerting] failed to save alert state", "rule", rule.ID, "alert", alert.ID, "error", err)
+		}
+	}
+	e.clear(ctx, rule, states, seen, unknown)
+}
+
+// clear resolves instances of rule that are not in seen and not on a
+// cluster in unknown.
+func (e *Engine) clear(ctx context.Context, rule *Rule, states map[string]store.AlertState, seen, unknown map[string]bool) {
+	for fp, st := range states {
+		if seen[fp] {
+			continue
+		}
+		var alert notifications.Alert
+		if err := json.Unmarshal(st.Alert, &alert); err != nil {
+			slog.Warn("[Alerting] dropping undecodable alert state", "rule", rule.ID, "error", err)
+		} else if unknown[alert.Cluster] {
+			continue
+		} else if st.Status == StatusFiring {
+			alert.Status = statusResolved
+			alert.Message = "Resolved: " + alert.Message
+			if err := e.send(rule, alert); err != nil {
+				// Keep the state so the next cycle retries the resolution.
+				slog.Warn("[Alerting] failed to send resolution", "rule", rule.ID, "alert", alert.ID, "error", err)
+				continue
+			}
+		}
+		if err := e.store.DeleteAlertState(ctx, rule.ID, fp); err != nil {
+			slog.Warn("[Alerting] failed to delete alert state", "rule", rule.ID, "error", err)
+		}
+	}
+}
+
+func (e *Engine) send(rule *Rule, alert notifications.Alert) error {
+	if len(rule.Channels) > 0 {
+		return e.notifier.SendAlertToChannels(alert, rule.Channels)
+	}
+	return e.notifier.SendAlert(alert)
+}
+
+// alertID derives a stable ID for an alert instance so notifiers that
+// deduplicate (PagerDuty, OpsGenie) pair its firing and resolved events.
+func alertID(ruleID, fingerprint string) string {
+	sum := sha256.Sum256([]byte(ruleID + "\x00" + fingerprint))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// evaluateRule returns the resources matching rule and the clusters that
+// could not be checked.
+func (e *Engine) evaluateRule(ctx context.Context, rule *Rule, clusters []ClusterStatus) ([]finding, map[string]bool) {
+	c := &rule.Condition
+	var findings []finding
+	unknown := make(map[string]bool)
+
+	for _, cl := range clusters {
+		if !matches(c.Clusters, cl.Name) {
+			continue
+		}
+		if c.Type == ConditionClusterUnreachable {
+			if !cl.Reachable {
+				findings = append(findings, finding{
+					fingerprint: cl.Name,
+					alert: notifications.Alert{
+						Message:      fmt.Sprintf("Cluster %s is unreachable", cl.Name),
+						Details:      map[string]interface{}{"error": cl.Error},
+						Cluster:      cl.Name,
+						Resource:     cl.Name,
+						ResourceKind: "Cluster",
+					},
+				})
+			}
+			continue
+		}
+		if !cl.Reachable {
+			unknown[cl.Name] = true
+			continue
+		}
+		found, err := e.evaluateCluster(ctx, c, cl)
+		if err != nil {
+			slog.Debug("[Alerting] cluster not evaluated", "rule", rule.ID, "cluster", cl.Name, "error", err)
+			unknown[cl.Name] = true
+			continue
+		}
+		findings = append(findings, found...)
+	}
+	return findings, unknown
+}
+
+func (e *Engine) evaluateCluster(ctx context.Context, c *Condition, cl ClusterStatus) ([]finding, error) {
+	var findings []finding
+	switch c.Type {
+	case ConditionNodeNotReady:
+		if cl.ReadyNodes < cl.NodeCount {
+			findings = append(findings, finding{
+				fingerprint: cl.Name,
+				alert: notifications.Alert{
+					Message: fmt.Sprintf("%d of %d nodes in %s are not ready",
+						cl.NodeCount-cl.ReadyNodes, cl.NodeCount, cl.Name),
+					Details:      map[string]interface{}{"nodeCount": cl.NodeCount, "readyNodes": cl.ReadyNodes},
+					Cluster:      cl.Name,
+					Resource:     cl.Name,
+					ResourceKind: "Cluster",
+				},
+			})
+		}
+
+	case ConditionPodCrash:
+		issues, err := e.source.PodIssues(ctx, cl.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range issues {
+			if !matches(c.Namespaces, p.Namespace) || float64(p.Restarts) < c.threshold() {
+				continue
+			}
+			findings = append(findings, finding{
+				fingerprint: cl.Name + "/" + p.Namespace + "/" + p.Name,
+				alert: notifications.Alert{
+					Message: fmt.Sprintf("Pod %s/%s has restarted %d times (%s)",
+						p.Namespace, p.Name, p.Restarts, p.Status),
+					Details:      map[string]interface{}{"restarts": p.Restarts, "status": p.Status, "reason": p.Reason},
+					Cluster:      cl.Name,
+					Namespace:    p.Namespace,
+					Resource:     p.Name,
+					ResourceKind: "Pod",
+				},
+			})
+		}
+
+	case ConditionWarningEvents:
+		events, err := e.source.WarningEvents(ctx, cl.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			if !matches(c.Namespaces, ev.Namespace) || !matches(c.Reasons, ev.Reason) || float64(ev.Count) < c.threshold() {
+				continue
+			}
+			findings = append(findings, finding{
+				fingerprint: cl.Name + "/" + ev.Namespace + "/" + ev.Object + "/" + ev.Reason,
+				alert: notifications.Alert{
+					Message:      fmt.Sprintf("%s on %s: %s", ev.Reason, ev.Object, ev.Message),
+					Details:      map[string]interface{}{"reason": ev.Reason, "count": ev.Count},
+					Cluster:      cl.Name,
+					Namespace:    ev.Namespace,
+					Resource:     ev.Object,
+					ResourceKind: "Event",
+				},
+			})
+		}
+
+	case ConditionGPUUsage:
+		gpu, err := e.source.GPUCapacity(ctx, cl.Name)
+		if err != nil {
+			return nil, err
+		}
+		if gpu.Total == 0 {
+			break
+		}
+		pct := float64(gpu.Allocated) * 100 / float64(gpu.Total)
+		if pct > c.threshold() {
+			findings = append(findings, finding{
+				fingerprint: cl.Name,
+				alert: notifications.Alert{
+					Message: fmt.Sprintf("GPU allocation in %s is %.0f%% (%d of %d)",
+						cl.Name, pct, gpu.Allocated, gpu.Total),
+					Details:      map[string]interface{}{"allocated": gpu.Allocated, "total": gpu.Total, "percent": pct},
+					Cluster:      cl.Name,
+					Resource:     cl.Name,
+					ResourceKind: "Cluster",
+				},
+			})
+		}
+
+	case ConditionPrometheus:
+		samples, err := e.source.PrometheusQuery(ctx, cl.Name, c.PrometheusNamespace, c.PrometheusService, c.Query)
+		if err != nil {
+			return nil, err
+		}
+		compare := comparators[c.Comparator]
+		for _, s := range samples {
+			if !compare(s.Value, c.threshold()) {
+				continue
+			}
+			labels := formatLabels(s.Labels)
+			findings = append(findings, finding{
+				fingerprint: cl.Name + "/" + labels,
+				alert: notifications.Alert{
+					Message: fmt.Sprintf("%s%s = %g (%s %g)",
+						c.Query, labels, s.Value, c.Comparator, c.threshold()),
+					Details:   map[string]interface{}{"value": s.Value, "labels": s.Labels},
+					Cluster:   cl.Name,
+					Namespace: s.Labels["namespace"],
+					Resource:  s.Labels["pod"],
+				},
+			})
+		}
+	}
+	return findings, nil
+}
+
+// formatLabels renders labels in PromQL selector form with sorted keys.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// ListRules returns every rule, oldest first.
+func (e *Engine) ListRules(ctx context.Context) ([]Rule, error) {
+	return e.listRules(ctx)
+}
+
+func (e *Engine) listRules(ctx context.Context) ([]Rule, error) {
+	docs, err := e.store.ListAlertRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]Rule, 0, len(docs))
+	for _, doc := range docs {
+		var r Rule
+		if err := json.Unmarshal(doc, &r); err != nil {
+			slog.Warn("[Alerting] skipping undecodable alert rule", "error", err)
+			continue
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// GetRule returns the rule with the given ID or ErrRuleNotFound.
+func (e *Engine) GetRule(ctx context.Context, id string) (*Rule, error) {
+	rules, err := e.listRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].ID == id {
+			return &rules[i], nil
+		}
+	}
+	return nil, ErrRuleNotFound
+}
+
+// SaveRule validates and stores rule. A rule without an ID is created with
+// a new one; otherwise the existing rule is replaced, keeping its creation
+// time. Validation errors are returned unwrapped for display.
+func (e *Engine) SaveRule(ctx context.Context, rule *Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now().UTC()
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = now
+	} else {
+		existing, err := e.GetRule(ctx, rule.ID)
+		if err != nil {
+			return err
+		}
+		rule.CreatedAt = existing.CreatedAt
+	}
+	rule.UpdatedAt = now
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	return e.store.SaveAlertRule(ctx, rule.ID, data)
+}
+
+// DeleteRule removes a rule, first resolving its firing alerts so they do
+// not stay open in downstream incident tools.
+func (e *Engine) DeleteRule(ctx context.Context, id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rule, err := e.GetRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	stored, err := e.store.ListAlertStates(ctx)
+	if err != nil {
+		return err
+	}
+	states := make(map[string]store.AlertState)
+	for _, st := range stored {
+		if st.RuleID == id {
+			states[st.Fingerprint] = st
+		}
+	}
+	e.clear(ctx, rule, states, nil, nil)
+	return e.store.DeleteAlertRule(ctx, id)
+}
+
+// ActiveAlert is a pending or firing alert instance.
+type ActiveAlert struct {
+	notifications.Alert
+	ActiveSince time.Time `json:"activeSince"`
+}
+
+// ActiveAlerts returns every pending and firing alert instance.
+func (e *Engine) ActiveAlerts(ctx context.Context) ([]ActiveAlert, error) {
+	states, err := e.store.ListAlertStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ActiveAlert, 0, len(states))
+	for _, st := range states {
+		var a ActiveAlert
+		if err := json.Unmarshal(st.Alert, &a.Alert); err != nil {
+			continue
+		}
+		a.Status = st.Status
+		a.ActiveSince = st.ActiveSince
+		out = append(out, a)
+	}
+	return out, nil
+}
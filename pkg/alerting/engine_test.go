@@ -0,0 +1,327 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+type memStore struct {
+	rules  map[string][]byte
+	order  []string
+	states map[string]store.AlertState
+}
+
+func newMemStore() *memStore {
+	return &memStore{rules: map[string][]byte{}, states: map[string]store.AlertState{}}
+}
+
+func (m *memStore) ListAlertRules(context.Context) ([][]byte, error) {
+	out := make([][]byte, 0, len(m.order))
+	for _, id := range m.order {
+		out = append(out, m.rules[id])
+	}
+	return out, nil
+}
+
+func (m *memStore) SaveAlertRule(_ context.Context, id string, data []byte) error {
+	if _, ok := m.rules[id]; !ok {
+		m.order = append(m.order, id)
+	}
+	m.rules[id] = data
+	return nil
+}
+
+func (m *memStore) DeleteAlertRule(_ context.Context, id string) error {
+	delete(m.rules, id)
+	for i, o := range m.order {
+		if o == id {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	for k, st := range m.states {
+		if st.RuleID == id {
+			delete(m.states, k)
+		}
+	}
+	return nil
+}
+
+func (m *memStore) ListAlertStates(context.Context) ([]store.AlertState, error) {
+	out := make([]store.AlertState, 0, len(m.states))
+	for _, st := range m.states {
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+func (m *memStore) SaveAlertState(_ context.Context, st store.AlertState) error {
+	m.states[st.RuleID+"|"+st.Fingerprint] = st
+	return nil
+}
+
+func (m *memStore) DeleteAlertState(_ context.Context, ruleID, fp string) error {
+	delete(m.states, ruleID+"|"+fp)
+	return nil
+}
+
+type fakeSource struct {
+	clusters []ClusterStatus
+	pods     map[string][]PodIssue
+	podErr   error
+	gpu      map[string]GPUCapacity
+	samples  []Sample
+}
+
+func (f *fakeSource) Clusters(context.Context) ([]ClusterStatus, error) { return f.clusters, nil }
+
+func (f *fakeSource) PodIssues(_ context.Context, cluster string) ([]PodIssue, error) {
+	return f.pods[cluster], f.podErr
+}
+
+func (f *fakeSource) WarningEvents(context.Context, string) ([]WarningEvent, error) {
+	return nil, nil
+}
+
+func (f *fakeSource) GPUCapacity(_ context.Context, cluster string) (GPUCapacity, error) {
+	return f.gpu[cluster], nil
+}
+
+func (f *fakeSource) PrometheusQuery(context.Context, string, string, string, string) ([]Sample, error) {
+	return f.samples, nil
+}
+
+type fakeNotifier struct {
+	sent []notifications.Alert
+	err  error
+}
+
+func (n *fakeNotifier) SendAlert(a notifications.Alert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, a)
+	return nil
+}
+
+func (n *fakeNotifier) SendAlertToChannels(a notifications.Alert, _ []notifications.NotificationChannel) error {
+	return n.SendAlert(a)
+}
+
+func newTestEngine(t *testing.T, src *fakeSource, rule Rule) (*Engine, *memStore, *fakeNotifier, *time.Time) {
+	t.Helper()
+	st := newMemStore()
+	n := &fakeNotifier{}
+	e := NewEngine(st, src, n, 0)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	if err := e.SaveRule(context.Background(), &rule); err != nil {
+		t.Fatalf("SaveRule: %v", err)
+	}
+	return e, st, n, &now
+}
+
+func evaluate(t *testing.T, e *Engine) {
+	t.Helper()
+	if err := e.Evaluate(context.Background()); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+}
+
+func TestEngine_FiresAndResolves(t *testing.T) {
+	src := &fakeSource{
+		clusters: []ClusterStatus{{Name: "prod", Reachable: true}},
+		pods: map[string][]PodIssue{"prod": {
+			{Name: "api-1", Namespace: "default", Status: "CrashLoopBackOff", Restarts: 7},
+			{Name: "api-2", Namespace: "default", Restarts: 1},
+		}},
+	}
+	e, st, n, _ := newTestEngine(t, src, Rule{
+		Name: "crashes", Enabled: true, Severity: notifications.SeverityCritical,
+		Condition: Condition{Type: ConditionPodCrash},
+	})
+
+	evaluate(t, e)
+	if len(n.sent) != 1 || n.sent[0].Status != StatusFiring || n.sent[0].Resource != "api-1" {
+		t.Fatalf("sent = %+v, want one firing alert for api-1", n.sent)
+	}
+	firing := n.sent[0]
+	if firing.RuleName != "crashes" || firing.Severity != notifications.SeverityCritical {
+		t.Errorf("alert rule fields = %q/%q", firing.RuleName, firing.Severity)
+	}
+
+	// Still matching: no repeat notification.
+	evaluate(t, e)
+	if len(n.sent) != 1 {
+		t.Fatalf("sent %d alerts while still firing, want 1", len(n.sent))
+	}
+
+	src.pods = nil
+	evaluate(t, e)
+	if len(n.sent) != 2 || n.sent[1].Status != statusResolved {
+		t.Fatalf("sent = %+v, want resolution", n.sent)
+	}
+	if n.sent[1].ID != firing.ID {
+		t.Errorf("resolved ID %q != firing ID %q", n.sent[1].ID, firing.ID)
+	}
+	if len(st.states) != 0 {
+		t.Errorf("states = %d after resolve, want 0", len(st.states))
+	}
+}
+
+func TestEngine_DurationHoldsPending(t *testing.T) {
+	src := &fakeSource{
+		clusters: []ClusterStatus{{Name: "prod", Reachable: true}},
+		gpu:      map[string]GPUCapacity{"prod": {Total: 8, Allocated: 8}},
+	}
+	e, _, n, now := newTestEngine(t, src, Rule{
+		Name: "gpu", Enabled: true,
+		Condition: Condition{Type: ConditionGPUUsage, Duration: 300},
+	})
+
+	evaluate(t, e)
+	active, _ := e.ActiveAlerts(context.Background())
+	if len(n.sent) != 0 || len(active) != 1 || active[0].Status != StatusPending {
+		t.Fatalf("sent=%d active=%+v, want one pending and nothing sent", len(n.sent), active)
+	}
+
+	*now = now.Add(5 * time.Minute)
+	evaluate(t, e)
+	if len(n.sent) != 1 || n.sent[0].Status != StatusFiring {
+		t.Fatalf("sent = %+v, want firing after duration", n.sent)
+	}
+}
+
+func TestEngine_UnknownClusterKeepsState(t *testing.T) {
+	src := &fakeSource{
+		clusters: []ClusterStatus{{Name: "prod", Reachable: true}},
+		pods:     map[string][]PodIssue{"prod": {{Name: "api", Namespace: "default", Restarts: 10}}},
+	}
+	e, st, n, _ := newTestEngine(t, src, Rule{
+		Name: "crashes", Enabled: true, Condition: Condition{Type: ConditionPodCrash},
+	})
+	evaluate(t, e)
+
+	src.pods = nil
+	src.podErr = errors.New("timeout")
+	evaluate(t, e)
+	src.podErr = nil
+	src.clusters[0].Reachable = false
+	evaluate(t, e)
+
+	if len(n.sent) != 1 || len(st.states) != 1 {
+		t.Fatalf("sent=%d states=%d, want firing alert kept while cluster is unknown", len(n.sent), len(st.states))
+	}
+}
+
+func TestEngine_SendFailureRetries(t *testing.T) {
+	src := &fakeSource{clusters: []ClusterStatus{{Name: "edge", Reachable: false}}}
+	e, _, n, _ := newTestEngine(t, src, Rule{
+		Name: "down", Enabled: true, Condition: Condition{Type: ConditionClusterUnreachable},
+	})
+
+	n.err = errors.New("slack down")
+	evaluate(t, e)
+	n.err = nil
+	evaluate(t, e)
+	if len(n.sent) != 1 || n.sent[0].Cluster != "edge" {
+		t.Fatalf("sent = %+v, want alert delivered on retry", n.sent)
+	}
+}
+
+func TestEngine_PrometheusComparator(t *testing.T) {
+	threshold := 0.5
+	src := &fakeSource{
+		clusters: []ClusterStatus{{Name: "prod", Reachable: true}},
+		samples: []Sample{
+			{Labels: map[string]string{"pod": "a"}, Value: 0.9},
+			{Labels: map[string]string{"pod": "b"}, Value: 0.1},
+		},
+	}
+	e, _, n, _ := newTestEngine(t, src, Rule{
+		Name: "errors", Enabled: true,
+		Condition: Condition{Type: ConditionPrometheus, Query: "rate(errors[5m])", Threshold: &threshold},
+	})
+	evaluate(t, e)
+	if len(n.sent) != 1 || n.sent[0].Resource != "a" {
+		t.Fatalf("sent = %+v, want one alert for pod a", n.sent)
+	}
+}
+
+func TestEngine_DisableAndDeleteResolve(t *testing.T) {
+	src := &fakeSource{clusters: []ClusterStatus{
+		{Name: "a", Reachable: false},
+		{Name: "b", Reachable: false},
+	}}
+	e, st, n, _ := newTestEngine(t, src, Rule{
+		Name: "down", Enabled: true, Condition: Condition{Type: ConditionClusterUnreachable},
+	})
+	evaluate(t, e)
+	rules, _ := e.ListRules(context.Background())
+	rule := rules[0]
+
+	rule.Condition.Clusters = []string{"a"}
+	if err := e.SaveRule(context.Background(), &rule); err != nil {
+		t.Fatalf("SaveRule: %v", err)
+	}
+	evaluate(t, e)
+	if err := e.DeleteRule(context.Background(), rule.ID); err != nil {
+		t.Fatalf("DeleteRule: %v", err)
+	}
+
+	var statuses []string
+	for _, a := range n.sent {
+		statuses = append(statuses, a.Cluster+":"+a.Status)
+	}
+	sort.Strings(statuses)
+	want := []string{"a:firing", "a:resolved", "b:firing", "b:resolved"}
+	if len(statuses) != len(want) {
+		t.Fatalf("sent = %v, want %v", statuses, want)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Fatalf("sent = %v, want %v", statuses, want)
+		}
+	}
+	if len(st.states) != 0 || len(st.rules) != 0 {
+		t.Errorf("rules=%d states=%d after delete, want 0", len(st.rules), len(st.states))
+	}
+	if err := e.DeleteRule(context.Background(), rule.ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("second delete err = %v, want ErrRuleNotFound", err)
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		ok   bool
+	}{
+		{"defaults", Rule{Name: "x", Condition: Condition{Type: ConditionPodCrash}}, true},
+		{"missing name", Rule{Condition: Condition{Type: ConditionPodCrash}}, false},
+		{"browser-only type", Rule{Name: "x", Condition: Condition{Type: "dns_failure"}}, false},
+		{"prometheus without query", Rule{Name: "x", Condition: Condition{Type: ConditionPrometheus}}, false},
+		{"bad severity", Rule{Name: "x", Severity: "urgent", Condition: Condition{Type: ConditionGPUUsage}}, false},
+		{"negative duration", Rule{Name: "x", Condition: Condition{Type: ConditionGPUUsage, Duration: -1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err == nil) != tt.ok {
+				t.Fatalf("Validate() err = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+
+	r := Rule{Name: "x", Condition: Condition{Type: ConditionPodCrash}}
+	_ = r.Validate()
+	if r.Severity != notifications.SeverityWarning || r.Condition.threshold() != defaultPodRestartThreshold {
+		t.Errorf("defaults not applied: %+v", r)
+	}
+}
@@ -0,0 +1,185 @@
+// Package alerting evaluates persisted alert rules on a schedule and sends
+// their firing and resolved alerts through the notification service, so
+// alerts reach on-call whether or not anyone has the console open.
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/notifications"
+)
+
+// ConditionType names what an alert rule watches.
+type ConditionType string
+
+const (
+	// ConditionClusterUnreachable fires for each cluster the console cannot
+	// reach.
+	ConditionClusterUnreachable ConditionType = "cluster_unreachable"
+	// ConditionNodeNotReady fires for each cluster with nodes that are not
+	// Ready.
+	ConditionNodeNotReady ConditionType = "node_not_ready"
+	// ConditionPodCrash fires for each pod that has restarted at least
+	// Threshold times (default 5).
+	ConditionPodCrash ConditionType = "pod_crash"
+	// ConditionWarningEvents fires for each object with a Warning event
+	// seen at least Threshold times (default 1), optionally limited to
+	// Reasons.
+	ConditionWarningEvents ConditionType = "warning_events"
+	// ConditionGPUUsage fires for each cluster whose GPU allocation exceeds
+	// Threshold percent (default 90).
+	ConditionGPUUsage ConditionType = "gpu_usage"
+	// ConditionPrometheus fires for each sample of a PromQL instant query
+	// whose value satisfies Comparator Threshold.
+	ConditionPrometheus ConditionType = "prometheus"
+)
+
+// Condition defaults.
+const (
+	defaultPodRestartThreshold  = 5
+	defaultWarningEventCount    = 1
+	defaultGPUUsagePercent      = 90
+	defaultPrometheusNamespace  = "monitoring"
+	defaultPrometheusService    = "prometheus"
+	defaultPrometheusComparator = ">"
+	// maxConditionDuration caps how long a condition may have to hold
+	// before firing.
+	maxConditionDuration = 24 * 60 * 60
+	// maxPromQLLength matches the kc-agent limit on PromQL queries.
+	maxPromQLLength = 2048
+)
+
+// Condition is the test an alert rule applies. The JSON field names match
+// the console's browser-side AlertCondition so rules can move between the
+// two.
+type Condition struct {
+	Type ConditionType `json:"type"`
+	// Threshold's meaning depends on Type; nil selects its default.
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Duration is how many seconds the condition must hold before the
+	// alert fires; 0 fires on the first evaluation that matches.
+	Duration int `json:"duration,omitempty"`
+	// Clusters and Namespaces limit the rule; empty means all.
+	Clusters   []string `json:"clusters,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Reasons limits warning_events to these event reasons.
+	Reasons []string `json:"reasons,omitempty"`
+	// Query, Comparator and the Prometheus service location configure
+	// prometheus conditions. The query runs in every matching cluster.
+	Query               string `json:"query,omitempty"`
+	Comparator          string `json:"comparator,omitempty"`
+	PrometheusNamespace string `json:"prometheusNamespace,omitempty"`
+	PrometheusService   string `json:"prometheusService,omitempty"`
+}
+
+// Rule is a persisted alert rule.
+type Rule struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	Enabled     bool                        `json:"enabled"`
+	Severity    notifications.AlertSeverity `json:"severity"`
+	Condition   Condition                   `json:"condition"`
+	// Channels receive the rule's alerts. With none, alerts go to the
+	// notifiers registered on the server.
+	Channels  []notifications.NotificationChannel `json:"channels,omitempty"`
+	CreatedAt time.Time                           `json:"createdAt"`
+	UpdatedAt time.Time                           `json:"updatedAt"`
+}
+
+// Validate checks the rule and fills in condition defaults.
+func (r *Rule) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = notifications.SeverityWarning
+	case notifications.SeverityCritical, notifications.SeverityWarning, notifications.SeverityInfo:
+	default:
+		return fmt.Errorf("unknown severity %q", r.Severity)
+	}
+	return r.Condition.validate()
+}
+
+func (c *Condition) validate() error {
+	if c.Duration < 0 || c.Duration > maxConditionDuration {
+		return fmt.Errorf("duration must be between 0 and %d seconds", maxConditionDuration)
+	}
+	switch c.Type {
+	case ConditionClusterUnreachable, ConditionNodeNotReady:
+	case ConditionPodCrash:
+		c.setDefaultThreshold(defaultPodRestartThreshold)
+	case ConditionWarningEvents:
+		c.setDefaultThreshold(defaultWarningEventCount)
+	case ConditionGPUUsage:
+		c.setDefaultThreshold(defaultGPUUsagePercent)
+	case ConditionPrometheus:
+		c.Query = strings.TrimSpace(c.Query)
+		if c.Query == "" {
+			return fmt.Errorf("prometheus condition requires a query")
+		}
+		if len(c.Query) > maxPromQLLength {
+			return fmt.Errorf("query exceeds %d characters", maxPromQLLength)
+		}
+		if c.Threshold == nil {
+			return fmt.Errorf("prometheus condition requires a threshold")
+		}
+		if c.Comparator == "" {
+			c.Comparator = defaultPrometheusComparator
+		}
+		if _, ok := comparators[c.Comparator]; !ok {
+			return fmt.Errorf("unknown comparator %q", c.Comparator)
+		}
+		if c.PrometheusNamespace == "" {
+			c.PrometheusNamespace = defaultPrometheusNamespace
+		}
+		if c.PrometheusService == "" {
+			c.PrometheusService = defaultPrometheusService
+		}
+	case "":
+		return fmt.Errorf("condition type is required")
+	default:
+		return fmt.Errorf("condition type %q is not evaluated server-side", c.Type)
+	}
+	return nil
+}
+
+func (c *Condition) setDefaultThreshold(v float64) {
+	if c.Threshold == nil {
+		c.Threshold = &v
+	}
+}
+
+func (c *Condition) threshold() float64 {
+	if c.Threshold == nil {
+		return 0
+	}
+	return *c.Threshold
+}
+
+var comparators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// matches reports whether value is selected by a Clusters or Namespaces
+// filter.
+func matches(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if f == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package alerting
+
+import (
+	"context"
+
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// ClusterStatus is the reachability and node readiness of one cluster.
+type ClusterStatus struct {
+	Name       string
+	Reachable  bool
+	NodeCount  int
+	ReadyNodes int
+	Error      string
+}
+
+// PodIssue is a pod in a failing or restarting state.
+type PodIssue struct {
+	Name      string
+	Namespace string
+	Status    string
+	Reason    string
+	Restarts  int
+}
+
+// WarningEvent is an aggregated Warning event for one object.
+type WarningEvent struct {
+	Namespace string
+	Object    string
+	Reason    string
+	Message   string
+	Count     int
+}
+
+// GPUCapacity is the GPU allocation of one cluster.
+type GPUCapacity struct {
+	Total     int
+	Allocated int
+}
+
+// Sample is one element of a Prometheus instant query result.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Source reads the cluster data alert rules are evaluated against.
+type Source interface {
+	Clusters(ctx context.Context) ([]ClusterStatus, error)
+	PodIssues(ctx context.Context, cluster string) ([]PodIssue, error)
+	WarningEvents(ctx context.Context, cluster string) ([]WarningEvent, error)
+	GPUCapacity(ctx context.Context, cluster string) (GPUCapacity, error)
+	PrometheusQuery(ctx context.Context, cluster, namespace, service, query string) ([]Sample, error)
+}
+
+// Store persists alert rules and alert instance state. store.Store
+// satisfies it.
+type Store interface {
+	ListAlertRules(ctx context.Context) ([][]byte, error)
+	SaveAlertRule(ctx context.Context, id string, data []byte) error
+	DeleteAlertRule(ctx context.Context, id string) error
+	ListAlertStates(ctx context.Context) ([]store.AlertState, error)
+	SaveAlertState(ctx context.Context, state store.AlertState) error
+	DeleteAlertState(ctx context.Context, ruleID, fingerprint string) error
+}
+
+// Notifier delivers alerts. *notifications.Service satisfies it.
+type Notifier interface {
+	SendAlert(alert notifications.Alert) error
+	SendAlertToChannels(alert notifications.Alert, channels []notifications.NotificationChannel) error
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubestellar/console/pkg/agent"
+	"github.com/kubestellar/console/pkg/alerting"
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	// alertEventLimit caps the Warning events read per cluster and
+	// evaluation cycle.
+	alertEventLimit = 500
+	// alertClusterTimeout bounds each per-cluster read of an evaluation.
+	alertClusterTimeout = 15 * time.Second
+)
+
+// k8sAlertSource feeds the alert engine from the multi-cluster client.
+type k8sAlertSource struct {
+	client *k8s.MultiClusterClient
+}
+
+func newAlertSource(client *k8s.MultiClusterClient) alerting.Source {
+	return &k8sAlertSource{client: client}
+}
+
+func (a *k8sAlertSource) Clusters(ctx context.Context) ([]alerting.ClusterStatus, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("kubernetes client not available")
+	}
+	health, err := a.client.GetAllClusterHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]alerting.ClusterStatus, 0, len(health))
+	for _, h := range health {
+		out = append(out, alerting.ClusterStatus{
+			Name:       h.Cluster,
+			Reachable:  h.Reachable,
+			NodeCount:  h.NodeCount,
+			ReadyNodes: h.ReadyNodes,
+			Error:      h.ErrorMessage,
+		})
+	}
+	return out, nil
+}
+
+func (a *k8sAlertSource) PodIssues(ctx context.Context, cluster string) ([]alerting.PodIssue, error) {
+	ctx, cancel := context.WithTimeout(ctx, alertClusterTimeout)
+	defer cancel()
+	issues, err := a.client.FindPodIssues(ctx, cluster, "")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]alerting.PodIssue, 0, len(issues))
+	for _, p := range issues {
+		out = append(out, alerting.PodIssue{
+			Name:      p.Name,
+			Namespace: p.Namespace,
+			Status:    p.Status,
+			Reason:    p.Reason,
+			Restarts:  p.Restarts,
+		})
+	}
+	return out, nil
+}
+
+func (a *k8sAlertSource) WarningEvents(ctx context.Context, cluster string) ([]alerting.WarningEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, alertClusterTimeout)
+	defer cancel()
+	events, err := a.client.GetWarningEvents(ctx, cluster, "", alertEventLimit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]alerting.WarningEvent, 0, len(events))
+	for _, ev := range events {
+		out = append(out, alerting.WarningEvent{
+			Namespace: ev.Namespace,
+			Object:    ev.Object,
+			Reason:    ev.Reason,
+			Message:   ev.Message,
+			Count:     int(ev.Count),
+		})
+	}
+	return out, nil
+}
+
+func (a *k8sAlertSource) GPUCapacity(ctx context.Context, cluster string) (alerting.GPUCapacity, error) {
+	ctx, cancel := context.WithTimeout(ctx, alertClusterTimeout)
+	defer cancel()
+	nodes, err := a.client.GetGPUNodes(ctx, cluster)
+	if err != nil {
+		return alerting.GPUCapacity{}, err
+	}
+	var capacity alerting.GPUCapacity
+	for _, n := range nodes {
+		capacity.Total += n.GPUCount
+		capacity.Allocated += n.GPUAllocated
+	}
+	return capacity, nil
+}
+
+// PrometheusQuery runs the query through the same API server service proxy
+// kc-agent's /prometheus/query endpoint uses.
+func (a *k8sAlertSource) PrometheusQuery(ctx context.Context, cluster, namespace, service, query string) ([]alerting.Sample, error) {
+	config, err := a.client.GetRestConfig(cluster)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, alertClusterTimeout)
+	defer cancel()
+	samples, err := agent.QueryPrometheus(ctx, config, namespace, service, query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]alerting.Sample, 0, len(samples))
+	for _, s := range samples {
+		out = append(out, alerting.Sample{Labels: s.Labels, Value: s.Value})
+	}
+	return out, nil
+}
@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -46,6 +47,22 @@ const (
 	ActionUpdateGPUReservation = "update_gpu_reservation"
 	ActionDeleteGPUReservation = "delete_gpu_reservation"
 	ActionShareMissionGitHub   = "share_mission_github"
+	ActionSyncMissionKB        = "sync_mission_kb"
+
+	// Supply chain (#9648): license allow/warn/deny policy changes.
+	ActionSaveLicensePolicy = "save_license_policy"
+
+	// Server-side alert rule changes.
+	ActionSaveAlertRule   = "save_alert_rule"
+	ActionDeleteAlertRule = "delete_alert_rule"
+
+	// Notification silences and maintenance windows.
+	ActionCreateSilence = "create_notification_silence"
+	ActionDeleteSilence = "delete_notification_silence"
+
+	// kc-agent reverse tunnel pairing.
+	ActionPairAgent   = "pair_agent"
+	ActionRevokeAgent = "revoke_agent"
 )
 
 // storeMu guards the package-level store reference.
@@ -112,4 +129,23 @@ func Log(c *fiber.Ctx, action, targetType, targetID string, details ...string) {
 			slog.Error("audit: failed to persist audit entry", "error", err, "action", action)
 		}
 	}
+
+	// Feed the SIEM export pipeline. Fiber reuses request buffers, so
+	// strings kept past the handler are cloned.
+	cluster := c.Params("cluster")
+	if cluster == "" {
+		cluster = c.Query("cluster")
+	}
+	resource := targetType
+	if targetID != "" {
+		resource += "/" + targetID
+	}
+	RecordEvent(PipelineEvent{
+		ID:        uuid.NewString(),
+		Cluster:   strings.Clone(cluster),
+		EventType: action,
+		Resource:  strings.Clone(resource),
+		User:      userID.String(),
+		Timestamp: time.Now().UTC(),
+	})
 }
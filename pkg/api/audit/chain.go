@@ -0,0 +1,431 @@
+package audit
+
+// Tamper-evident audit chain — 21 CFR Part 11 / #8670.
+//
+// store.InsertAuditLog links every audit_log row to its predecessor by hash
+// (store.AuditEntryHash). Editing or deleting a row breaks the link to the
+// next one. The row hash is unkeyed, so a Checkpointer signs the chain head
+// with an Ed25519 key every interval, linking each checkpoint to the one
+// before it: rewriting signed history, or deleting checkpoints to hide a
+// rewrite, is caught too, and a verifier holding the key can require a
+// recent checkpoint to bound what an attacker could rewrite unnoticed.
+// VerifyChain walks the chain and reports the first broken link.
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/fileutil"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// DefaultCheckpointInterval is how often the chain head is signed.
+	DefaultCheckpointInterval = time.Hour
+	// chainPageSize is how many rows VerifyChain reads per query.
+	chainPageSize = 1000
+	// checkpointKeyMode keeps the signing key private to the console.
+	checkpointKeyMode = 0o600
+	// checkpointPayloadVersion prefixes the signed checkpoint payload.
+	checkpointPayloadVersion = "kubestellar-console-audit-checkpoint/v2"
+	// checkpointStaleFactor is how many checkpoint intervals may pass
+	// before a verifier holding the key treats the latest checkpoint as
+	// stale: one missed tick is tolerated, two are not.
+	checkpointStaleFactor = 2
+)
+
+// ChainStore is the subset of store.Store the audit chain reads and writes.
+type ChainStore interface {
+	ListAuditChain(ctx context.Context, afterID int64, limit int) ([]store.AuditEntry, error)
+	AuditLegacyCutoff(ctx context.Context) (int64, error)
+	QueryAuditLogs(ctx context.Context, limit int, userID, action string) ([]store.AuditEntry, error)
+	InsertAuditCheckpoint(ctx context.Context, cp *store.AuditCheckpoint) error
+	ListAuditCheckpoints(ctx context.Context) ([]store.AuditCheckpoint, error)
+}
+
+var (
+	keyMu              sync.RWMutex
+	checkpointKey      ed25519.PrivateKey
+	checkpointInterval time.Duration
+)
+
+// SetCheckpointKey installs the key checkpoints are signed and verified
+// with and the interval they are written at. Call once at startup, next to
+// SetStore. A zero interval uses DefaultCheckpointInterval.
+func SetCheckpointKey(key ed25519.PrivateKey, interval time.Duration) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	checkpointKey = key
+	checkpointInterval = interval
+}
+
+// CheckpointMaxAge returns how old the latest checkpoint may be before
+// VerifyChain reports it stale, for a checkpointer writing every interval.
+// A zero interval uses DefaultCheckpointInterval.
+func CheckpointMaxAge(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+	return checkpointStaleFactor * interval
+}
+
+// InstalledCheckpointMaxAge is CheckpointMaxAge for the interval installed
+// with SetCheckpointKey.
+func InstalledCheckpointMaxAge() time.Duration {
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	return CheckpointMaxAge(checkpointInterval)
+}
+
+// CheckpointPublicKey returns the public half of the installed checkpoint
+// key, or nil when none is set.
+func CheckpointPublicKey() ed25519.PublicKey {
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	if checkpointKey == nil {
+		return nil
+	}
+	return checkpointKey.Public().(ed25519.PublicKey)
+}
+
+// LoadOrCreateCheckpointKey reads the PKCS #8 PEM Ed25519 key at path,
+// generating and saving a new one when the file does not exist.
+func LoadOrCreateCheckpointKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil || block.Type != "PRIVATE KEY" {
+			return nil, fmt.Errorf("%s: not a PEM private key", path)
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ed, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s: not an Ed25519 key", path)
+		}
+		return ed, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := fileutil.AtomicWriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), checkpointKeyMode); err != nil {
+		return nil, err
+	}
+	slog.Info("[Audit] generated audit checkpoint signing key", "path", path, "keyID", CheckpointKeyID(key.Public().(ed25519.PublicKey)))
+	return key, nil
+}
+
+// ParseCheckpointPublicKey reads the verification key from PEM data holding
+// either the checkpoint private key or its PKIX public key, so auditors can
+// verify without the signing key.
+func ParseCheckpointPublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if priv, ok := key.(ed25519.PrivateKey); ok {
+			key = priv.Public()
+		}
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("not an Ed25519 key")
+	}
+	return pub, nil
+}
+
+// CheckpointKeyID identifies a checkpoint key: the first 8 bytes of the
+// SHA-256 of the public key, hex-encoded.
+func CheckpointKeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// checkpointPayload is the byte string a checkpoint signs.
+func checkpointPayload(cp store.AuditCheckpoint) []byte {
+	return []byte(checkpointPayloadVersion + "|" + strconv.FormatInt(cp.LastID, 10) + "|" + cp.Hash + "|" + cp.PrevHash + "|" + cp.KeyID + "|" + cp.CreatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// checkpointDigest is what the next checkpoint's PrevHash must hold: the
+// SHA-256 of cp's signed payload and signature.
+func checkpointDigest(cp store.AuditCheckpoint) string {
+	sum := sha256.Sum256(append(checkpointPayload(cp), "|"+cp.Signature...))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteCheckpoint signs the current chain head, linked to the latest
+// checkpoint. It writes one even when the head has not moved, so the
+// latest checkpoint's age shows the checkpointer is still running; before
+// the first chained row the checkpoint covers nothing (LastID 0).
+func WriteCheckpoint(ctx context.Context, s ChainStore, key ed25519.PrivateKey) (*store.AuditCheckpoint, error) {
+	return writeCheckpoint(ctx, s, key, time.Now())
+}
+
+func writeCheckpoint(ctx context.Context, s ChainStore, key ed25519.PrivateKey, now time.Time) (*store.AuditCheckpoint, error) {
+	head, err := s.QueryAuditLogs(ctx, 1, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("read audit chain head: %w", err)
+	}
+	existing, err := s.ListAuditCheckpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list audit checkpoints: %w", err)
+	}
+
+	cp := &store.AuditCheckpoint{
+		KeyID:     CheckpointKeyID(key.Public().(ed25519.PublicKey)),
+		CreatedAt: now.UTC().Truncate(time.Microsecond),
+	}
+	if len(head) > 0 && head[0].Hash != "" {
+		cp.LastID, cp.Hash = head[0].ID, head[0].Hash
+	}
+	if n := len(existing); n > 0 {
+		cp.PrevHash = checkpointDigest(existing[n-1])
+	}
+	cp.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, checkpointPayload(*cp)))
+	if err := s.InsertAuditCheckpoint(ctx, cp); err != nil {
+		return nil, fmt.Errorf("save audit checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// Checkpointer signs the chain head on an interval.
+type Checkpointer struct {
+	store    ChainStore
+	key      ed25519.PrivateKey
+	interval time.Duration
+}
+
+// NewCheckpointer creates a Checkpointer. A zero interval uses
+// DefaultCheckpointInterval.
+func NewCheckpointer(s ChainStore, key ed25519.PrivateKey, interval time.Duration) *Checkpointer {
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+	return &Checkpointer{store: s, key: key, interval: interval}
+}
+
+// Run writes a checkpoint at startup, every interval, and once more on
+// shutdown, until ctx is cancelled.
+func (c *Checkpointer) Run(ctx context.Context) {
+	c.checkpoint(ctx)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is already done; give the final checkpoint its own deadline.
+			final, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			c.checkpoint(final)
+			cancel()
+			return
+		case <-ticker.C:
+			c.checkpoint(ctx)
+		}
+	}
+}
+
+func (c *Checkpointer) checkpoint(ctx context.Context) {
+	cp, err := WriteCheckpoint(ctx, c.store, c.key)
+	if err != nil {
+		slog.Error("[Audit] failed to write audit checkpoint", "error", err)
+		return
+	}
+	slog.Debug("[Audit] audit chain checkpoint written", "lastID", cp.LastID, "keyID", cp.KeyID)
+}
+
+// ChainReport is the result of VerifyChain.
+type ChainReport struct {
+	Valid bool `json:"valid"`
+	// TotalRecords counts every audit_log row read.
+	TotalRecords int `json:"total_records"`
+	// LegacyRecords counts rows written before the chain existed, up to
+	// the cutoff recorded when it was introduced; they carry no hash.
+	LegacyRecords   int `json:"legacy_records"`
+	VerifiedRecords int `json:"verified_records"`
+	Checkpoints     int `json:"checkpoints"`
+	// VerifiedCheckpoints counts checkpoints whose signature, link to the
+	// previous checkpoint and covered row all matched.
+	VerifiedCheckpoints int `json:"verified_checkpoints"`
+	// BrokenAtID is the audit_log ID of the first broken link, 0 when the
+	// chain is intact.
+	BrokenAtID int64     `json:"broken_at_id,omitempty"`
+	HeadID     int64     `json:"head_id"`
+	HeadHash   string    `json:"head_hash,omitempty"`
+	KeyID      string    `json:"key_id,omitempty"`
+	Message    string    `json:"message"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+func (r *ChainReport) broken(id int64, format string, args ...interface{}) *ChainReport {
+	r.Valid = false
+	r.BrokenAtID = id
+	r.Message = fmt.Sprintf("chain broken at record %d: ", id) + fmt.Sprintf(format, args...)
+	return r
+}
+
+// VerifyChain walks the whole audit chain and reports the first broken
+// link: a row whose hash does not match its contents, whose previous-hash
+// does not match the row before it, an unchained row after the legacy
+// cutoff, a checkpoint that does not follow the one before it, or a
+// checkpoint that does not match the row it covers.
+//
+// When pub is set every checkpoint must be signed with it, and the latest
+// must be younger than maxAge (see CheckpointMaxAge): without that, an
+// attacker with database access could drop the newest checkpoints and
+// rewrite the rows after the remaining ones. A zero maxAge skips the
+// recency check, for verifying a copy of a stopped console's database.
+func VerifyChain(ctx context.Context, s ChainStore, pub ed25519.PublicKey, maxAge time.Duration) (*ChainReport, error) {
+	report := &ChainReport{Valid: true, VerifiedAt: time.Now().UTC()}
+	cutoff, err := s.AuditLegacyCutoff(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read audit legacy cutoff: %w", err)
+	}
+	checkpoints, err := s.ListAuditCheckpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list audit checkpoints: %w", err)
+	}
+	report.Checkpoints = len(checkpoints)
+	keyID := ""
+	if pub != nil {
+		keyID = CheckpointKeyID(pub)
+		report.KeyID = keyID
+	}
+	byLastID := make(map[int64][]store.AuditCheckpoint, len(checkpoints))
+	prevDigest := ""
+	var prevLastID int64
+	for _, cp := range checkpoints {
+		if pub != nil {
+			if cp.KeyID != keyID {
+				return report.broken(cp.LastID, "checkpoint %d is signed by unknown key %q", cp.ID, cp.KeyID), nil
+			}
+			sig, err := base64.StdEncoding.DecodeString(cp.Signature)
+			if err != nil || !ed25519.Verify(pub, checkpointPayload(cp), sig) {
+				return report.broken(cp.LastID, "checkpoint %d has an invalid signature", cp.ID), nil
+			}
+		}
+		if cp.PrevHash != prevDigest || cp.LastID < prevLastID {
+			return report.broken(cp.LastID, "checkpoint %d does not follow the checkpoint before it (deleted or reordered checkpoint)", cp.ID), nil
+		}
+		prevDigest, prevLastID = checkpointDigest(cp), cp.LastID
+		if cp.LastID == 0 {
+			// Written before the first chained row; covers nothing.
+			if cp.Hash != "" {
+				return report.broken(0, "checkpoint %d covers no record but carries a hash", cp.ID), nil
+			}
+			report.VerifiedCheckpoints++
+			continue
+		}
+		byLastID[cp.LastID] = append(byLastID[cp.LastID], cp)
+	}
+
+	var (
+		after    int64
+		prevHash string
+	)
+	for {
+		page, err := s.ListAuditChain(ctx, after, chainPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("read audit chain: %w", err)
+		}
+		for _, e := range page {
+			report.TotalRecords++
+			after = e.ID
+			if e.ID <= cutoff {
+				if e.Hash != "" || e.PrevHash != "" {
+					return report.broken(e.ID, "record predates the chain but carries a hash"), nil
+				}
+				report.LegacyRecords++
+				continue
+			}
+			if e.Hash == "" {
+				return report.broken(e.ID, "record has no hash"), nil
+			}
+			if e.PrevHash != prevHash {
+				return report.broken(e.ID, "previous-hash does not match the preceding record (deleted or reordered record)"), nil
+			}
+			if store.AuditEntryHash(e) != e.Hash {
+				return report.broken(e.ID, "record contents do not match its hash (modified record)"), nil
+			}
+			for _, cp := range byLastID[e.ID] {
+				if cp.Hash != e.Hash {
+					return report.broken(e.ID, "record hash does not match checkpoint %d (chain rewritten)", cp.ID), nil
+				}
+				report.VerifiedCheckpoints++
+			}
+			delete(byLastID, e.ID)
+			prevHash = e.Hash
+			report.VerifiedRecords++
+			report.HeadID = e.ID
+			report.HeadHash = e.Hash
+		}
+		if len(page) < chainPageSize {
+			break
+		}
+	}
+
+	// Checkpoints left over cover rows that no longer exist.
+	for _, cp := range checkpoints {
+		if _, missing := byLastID[cp.LastID]; missing {
+			return report.broken(cp.LastID, "record covered by checkpoint %d is missing (truncated log)", cp.ID), nil
+		}
+	}
+	if pub != nil && maxAge > 0 {
+		if len(checkpoints) == 0 {
+			if report.VerifiedRecords > 0 {
+				return report.broken(report.HeadID, "no checkpoint signed by key %s covers the chain", keyID), nil
+			}
+		} else if latest := checkpoints[len(checkpoints)-1]; report.VerifiedAt.Sub(latest.CreatedAt) > maxAge {
+			return report.broken(report.HeadID, "latest checkpoint %d was signed at %s, more than %s ago (checkpoints removed or checkpointer stopped)",
+				latest.ID, latest.CreatedAt.UTC().Format(time.RFC3339), maxAge), nil
+		}
+	}
+	switch {
+	case report.VerifiedRecords == 0:
+		report.Message = "no chained records yet"
+	default:
+		report.Message = fmt.Sprintf("hash chain intact: %d records verified", report.VerifiedRecords)
+	}
+	return report, nil
+}
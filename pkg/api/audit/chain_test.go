@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// newChainTestStore returns a store holding five chained audit entries and
+// a raw connection to the same database for tampering with them.
+func newChainTestStore(t *testing.T) (*store.SQLiteStore, *sql.DB) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "chain.db")
+	s, err := store.NewSQLiteStore(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	ctx := context.Background()
+	for _, action := range []string{ActionUpdateRole, ActionDeleteUser, ActionUpdateRole, ActionDeleteUser, ActionUnauthorizedAttempt} {
+		require.NoError(t, s.InsertAuditLog(ctx, "admin-1", action, `{"target_type":"user","target_id":"u2"}`))
+	}
+	raw, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { raw.Close() })
+	return s, raw
+}
+
+func TestVerifyChain_Intact(t *testing.T) {
+	s, _ := newChainTestStore(t)
+	ctx := context.Background()
+	key, err := LoadOrCreateCheckpointKey(filepath.Join(t.TempDir(), "checkpoint.key"))
+	require.NoError(t, err)
+
+	cp, err := WriteCheckpoint(ctx, s, key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), cp.LastID)
+	assert.Empty(t, cp.PrevHash)
+	again, err := WriteCheckpoint(ctx, s, key)
+	require.NoError(t, err)
+	assert.Equal(t, cp.LastID, again.LastID, "an idle chain is re-signed at the same head")
+	assert.Equal(t, checkpointDigest(*cp), again.PrevHash)
+
+	report, err := VerifyChain(ctx, s, key.Public().(ed25519.PublicKey), time.Hour)
+	require.NoError(t, err)
+	assert.True(t, report.Valid, report.Message)
+	assert.Equal(t, 5, report.VerifiedRecords)
+	assert.Equal(t, 2, report.VerifiedCheckpoints)
+	assert.Equal(t, cp.Hash, report.HeadHash)
+}
+
+func TestVerifyChain_LegacyCutoff(t *testing.T) {
+	s, raw := newChainTestStore(t)
+	ctx := context.Background()
+
+	// Rows above the cutoff recorded by the migration must be chained.
+	_, err := raw.Exec(`UPDATE audit_log SET hash = '', prev_hash = '' WHERE id <= 2`)
+	require.NoError(t, err)
+	report, err := VerifyChain(ctx, s, nil, 0)
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, int64(1), report.BrokenAtID, report.Message)
+
+	// Below it they are legacy, and the chain starts after them.
+	_, err = raw.Exec(`UPDATE audit_chain_meta SET legacy_cutoff_id = 2`)
+	require.NoError(t, err)
+	_, err = raw.Exec(`UPDATE audit_log SET prev_hash = '' WHERE id = 3`)
+	require.NoError(t, err)
+	report, err = VerifyChain(ctx, s, nil, 0)
+	require.NoError(t, err)
+	assert.False(t, report.Valid, "row 3 was hashed with its original prev_hash")
+	assert.Equal(t, int64(3), report.BrokenAtID, report.Message)
+}
+
+func TestVerifyChain_CheckpointKeyAndRecency(t *testing.T) {
+	ctx := context.Background()
+	key, err := LoadOrCreateCheckpointKey(filepath.Join(t.TempDir(), "checkpoint.key"))
+	require.NoError(t, err)
+	pub := key.Public().(ed25519.PublicKey)
+	other, err := LoadOrCreateCheckpointKey(filepath.Join(t.TempDir(), "other.key"))
+	require.NoError(t, err)
+
+	t.Run("no checkpoint", func(t *testing.T) {
+		s, _ := newChainTestStore(t)
+		report, err := VerifyChain(ctx, s, pub, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, report.Valid, report.Message)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		s, _ := newChainTestStore(t)
+		_, err := WriteCheckpoint(ctx, s, other)
+		require.NoError(t, err)
+		report, err := VerifyChain(ctx, s, pub, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Contains(t, report.Message, "unknown key")
+	})
+	t.Run("stale checkpoint", func(t *testing.T) {
+		s, _ := newChainTestStore(t)
+		_, err := writeCheckpoint(ctx, s, key, time.Now().Add(-3*time.Hour))
+		require.NoError(t, err)
+		report, err := VerifyChain(ctx, s, pub, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, report.Valid, report.Message)
+		report, err = VerifyChain(ctx, s, pub, 0)
+		require.NoError(t, err)
+		assert.True(t, report.Valid, "recency is not checked with a zero max age")
+	})
+	t.Run("deleted latest checkpoints", func(t *testing.T) {
+		s, raw := newChainTestStore(t)
+		_, err := writeCheckpoint(ctx, s, key, time.Now().Add(-3*time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, s.InsertAuditLog(ctx, "admin-1", ActionDeleteUser, `{}`))
+		_, err = WriteCheckpoint(ctx, s, key)
+		require.NoError(t, err)
+		_, err = raw.Exec(`DELETE FROM audit_checkpoints WHERE id = 2`)
+		require.NoError(t, err)
+		report, err := VerifyChain(ctx, s, pub, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, report.Valid, report.Message)
+	})
+}
+
+func TestVerifyChain_DetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name   string
+		tamper string
+		broken int64
+	}{
+		{"modified row", `UPDATE audit_log SET user_id = 'intruder' WHERE id = 3`, 3},
+		{"deleted row", `DELETE FROM audit_log WHERE id = 2`, 3},
+		{"truncated tail", `DELETE FROM audit_log WHERE id >= 4`, 5},
+		{"forged checkpoint", `UPDATE audit_checkpoints SET last_id = 4`, 4},
+		{"deleted checkpoint", `DELETE FROM audit_checkpoints WHERE id = 1`, 5},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, raw := newChainTestStore(t)
+			key, err := LoadOrCreateCheckpointKey(filepath.Join(t.TempDir(), "checkpoint.key"))
+			require.NoError(t, err)
+			for i := 0; i < 2; i++ {
+				_, err = WriteCheckpoint(ctx, s, key)
+				require.NoError(t, err)
+			}
+
+			_, err = raw.Exec(tc.tamper)
+			require.NoError(t, err)
+			report, err := VerifyChain(ctx, s, key.Public().(ed25519.PublicKey), time.Hour)
+			require.NoError(t, err)
+			assert.False(t, report.Valid)
+			assert.Equal(t, tc.broken, report.BrokenAtID, report.Message)
+		})
+	}
+}
+
+func TestCheckpointKey_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.key")
+	key, err := LoadOrCreateCheckpointKey(path)
+	require.NoError(t, err)
+	reloaded, err := LoadOrCreateCheckpointKey(path)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(reloaded))
+}
@@ -5,17 +5,14 @@ package audit
 // Exports Kubernetes audit events to SIEM destinations:
 // Splunk HEC, Elastic SIEM, generic Webhook, and RFC 5424 Syslog.
 //
-// This file currently ships the Webhook destination as the first concrete
-// adapter. Splunk, Elastic, and Syslog remain stubs that return a structured
-// "destination not yet supported" error (tracked by #9643) rather than
-// silently pretending the send succeeded.
+// Destinations whose required settings are missing fall back to stubs that
+// return a structured "destination not yet supported" error rather than
+// silently pretending the send succeeded. Delivery is spooled to disk and
+// retried per destination; see pipeline.go.
 //
 // TODO (#9643): Remaining work for the full export engine —
 //   - Kubernetes API server audit webhook backend
-//   - Splunk HEC, Elastic bulk, and RFC 5424 Syslog adapters
-//   - Configurable event filters and batch sizes via ConfigMap
 //   - Per-destination TLS client certificate management
-//   - Circuit breaker and retry with exponential back-off for degraded destinations
 //   - Prometheus metrics: events_exported_total, export_errors_total, export_lag_seconds
 
 import (
@@ -24,7 +21,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 )
@@ -64,6 +63,8 @@ type ExportDestination struct {
 	Filters         []string            `json:"filters"`
 	TLSEnabled      bool                `json:"tls_enabled"`
 	BatchSize       int                 `json:"batch_size"`
+	// Backlog is the number of spooled events not yet delivered.
+	Backlog uint64 `json:"backlog"`
 }
 
 // PipelineEvent represents an audit event routed through the export pipeline.
@@ -206,13 +207,13 @@ type registry struct {
 	destinations []ExportDestination
 	adapters     map[string]Destination // keyed by ExportDestination.ID
 	buffer       []PipelineEvent
+	stats        map[string]*destinationStats // keyed by ExportDestination.ID
+	pipeline     *Pipeline                    // nil until OpenPipeline
 }
 
-var defaultRegistry = &registry{adapters: map[string]Destination{}}
+var defaultRegistry = &registry{adapters: map[string]Destination{}, stats: map[string]*destinationStats{}}
 
-// DestinationConfig is the minimal user-supplied shape for configuring a
-// destination. Richer fields (filters, TLS, batch size) live on
-// ExportDestination itself once the full UI lands.
+// DestinationConfig is the user-supplied shape for configuring a destination.
 //
 // Provider-specific fields (Token, Network, Index, Tag) are optional and
 // ignored by providers that do not consume them. Keeping them on one struct
@@ -232,6 +233,25 @@ type DestinationConfig struct {
 	Network string `json:"network,omitempty"`
 	// Tag is consumed by ProviderSyslog as the syslog program tag.
 	Tag string `json:"tag,omitempty"`
+
+	// Filters restricts which events reach the destination; see
+	// eventFilter for the syntax. Empty sends every event.
+	Filters []string `json:"filters,omitempty"`
+	// BatchSize caps the events per Send. Zero uses defaultBatchSize.
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// LoadDestinationsFile reads a JSON array of DestinationConfig from path.
+func LoadDestinationsFile(path string) ([]DestinationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []DestinationConfig
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfgs, nil
 }
 
 // RegisterDestination wires a configured destination into the registry. Each
@@ -243,6 +263,12 @@ func RegisterDestination(cfg DestinationConfig) (Destination, error) {
 	if cfg.ID == "" {
 		return nil, errors.New("destination config: id is required")
 	}
+	if cfg.BatchSize < 0 || cfg.BatchSize > maxBatchSize {
+		return nil, fmt.Errorf("destination config: batch_size must be between 0 and %d", maxBatchSize)
+	}
+	if _, err := parseFilters(cfg.Filters); err != nil {
+		return nil, fmt.Errorf("destination config: %w", err)
+	}
 	var (
 		adapter Destination
 		err     error
@@ -290,40 +316,109 @@ func RegisterDestination(cfg DestinationConfig) (Destination, error) {
 		return nil, fmt.Errorf("destination config: unknown provider %q", cfg.Provider)
 	}
 
+	dest := ExportDestination{
+		ID:        cfg.ID,
+		Name:      cfg.Name,
+		Provider:  cfg.Provider,
+		Endpoint:  cfg.URL,
+		Status:    StatusActive,
+		Filters:   cfg.Filters,
+		BatchSize: cfg.BatchSize,
+	}
 	defaultRegistry.mu.Lock()
-	defer defaultRegistry.mu.Unlock()
-	defaultRegistry.destinations = append(defaultRegistry.destinations, ExportDestination{
-		ID:       cfg.ID,
-		Name:     cfg.Name,
-		Provider: cfg.Provider,
-		Endpoint: cfg.URL,
-		Status:   StatusActive,
-	})
+	if d, _ := defaultRegistry.destinationLocked(cfg.ID); d != nil {
+		// Re-registering replaces the config; the spool cursor is kept so
+		// nothing is redelivered or skipped.
+		*d = dest
+	} else {
+		defaultRegistry.destinations = append(defaultRegistry.destinations, dest)
+		defaultRegistry.stats[cfg.ID] = &destinationStats{}
+	}
 	defaultRegistry.adapters[cfg.ID] = adapter
+	p := defaultRegistry.pipeline
+	defaultRegistry.mu.Unlock()
+	if p != nil {
+		p.track(cfg.ID)
+		p.startWorker(cfg.ID)
+	}
 	return adapter, nil
 }
 
+// destinationLocked returns the destination and stats for id. Callers hold
+// defaultRegistry.mu.
+func (r *registry) destinationLocked(id string) (*ExportDestination, *destinationStats) {
+	for i := range r.destinations {
+		if r.destinations[i].ID == id {
+			return &r.destinations[i], r.stats[id]
+		}
+	}
+	return nil, nil
+}
+
+// lookupDestination returns the adapter and a snapshot of the config for id.
+func lookupDestination(id string) (Destination, ExportDestination, bool) {
+	defaultRegistry.mu.RLock()
+	defer defaultRegistry.mu.RUnlock()
+	adapter, ok := defaultRegistry.adapters[id]
+	if !ok {
+		return nil, ExportDestination{}, false
+	}
+	d, _ := defaultRegistry.destinationLocked(id)
+	return adapter, *d, true
+}
+
+func destinationIDs() []string {
+	defaultRegistry.mu.RLock()
+	defer defaultRegistry.mu.RUnlock()
+	ids := make([]string, 0, len(defaultRegistry.destinations))
+	for _, d := range defaultRegistry.destinations {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
 // ListDestinations returns a snapshot of the currently-configured destinations.
 func ListDestinations() []ExportDestination {
 	defaultRegistry.mu.RLock()
 	defer defaultRegistry.mu.RUnlock()
+	now := time.Now()
 	out := make([]ExportDestination, len(defaultRegistry.destinations))
 	copy(out, defaultRegistry.destinations)
+	for i := range out {
+		if st := defaultRegistry.stats[out[i].ID]; st != nil {
+			out[i].EventsPerMinute = st.eventsPerMinute(now)
+			out[i].Backlog = st.backlog
+		}
+	}
 	return out
 }
 
 // RecordEvent pushes an event into the in-memory ring buffer used by the
-// summary aggregator. Overflow drops the oldest entry — this is best-effort
-// telemetry until the durable pipeline in #9643 is ready.
+// summary aggregator and, once OpenPipeline has run, into the durable spool
+// for delivery to every destination whose filters match. The ring buffer
+// drops the oldest entry on overflow; the spool does not.
 func RecordEvent(evt PipelineEvent) {
 	defaultRegistry.mu.Lock()
-	defer defaultRegistry.mu.Unlock()
+	evt.DestinationCount = 0
+	for _, d := range defaultRegistry.destinations {
+		if f, err := parseFilters(d.Filters); err == nil && f.match(evt) {
+			evt.DestinationCount++
+		}
+	}
 	defaultRegistry.buffer = append(defaultRegistry.buffer, evt)
 	if len(defaultRegistry.buffer) > maxBufferedEvents {
 		// Drop oldest entries to keep the buffer bounded.
 		overflow := len(defaultRegistry.buffer) - maxBufferedEvents
 		defaultRegistry.buffer = defaultRegistry.buffer[overflow:]
 	}
+	p := defaultRegistry.pipeline
+	defaultRegistry.mu.Unlock()
+
+	if p != nil {
+		if err := p.append(evt); err != nil {
+			slog.Error("[Audit] failed to spool event for export", "event", evt.ID, "error", err)
+		}
+	}
 }
 
 // RecentEvents returns a snapshot of the in-memory event buffer (newest last).
@@ -343,17 +438,26 @@ const summaryWindow24h = 24 * time.Hour
 const summaryRateWindow = time.Minute
 
 // BuildSummary aggregates counts from the in-memory buffer and registered
-// destinations. This replaces the hard-coded demo numbers returned by the
-// handler while the full live pipeline is still being built (#9643).
+// destinations. ErrorRate is the share of delivery attempts that failed.
 func BuildSummary(now time.Time) ExportSummary {
 	defaultRegistry.mu.RLock()
 	defer defaultRegistry.mu.RUnlock()
 
 	active := 0
+	var attempts, failures int64
 	for _, d := range defaultRegistry.destinations {
 		if d.Status == StatusActive {
 			active++
 		}
+		failures += int64(d.ErrorCount)
+		attempts += int64(d.ErrorCount)
+		if st := defaultRegistry.stats[d.ID]; st != nil {
+			attempts += st.sentBatches
+		}
+	}
+	errorRate := 0.0
+	if attempts > 0 {
+		errorRate = float64(failures) / float64(attempts)
 	}
 
 	var events24h int64
@@ -374,7 +478,7 @@ func BuildSummary(now time.Time) ExportSummary {
 		ActiveDestinations: active,
 		EventsPerMinute:    eventsLastMinute,
 		TotalEvents24h:     events24h,
-		ErrorRate:          0,
+		ErrorRate:          errorRate,
 		EvaluatedAt:        now,
 	}
 }
@@ -387,4 +491,6 @@ func ResetForTest() {
 	defaultRegistry.destinations = nil
 	defaultRegistry.adapters = map[string]Destination{}
 	defaultRegistry.buffer = nil
+	defaultRegistry.stats = map[string]*destinationStats{}
+	defaultRegistry.pipeline = nil
 }
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/gxp"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// gxpChainSource exposes the console audit chain to the GxP engine.
+type gxpChainSource struct {
+	store ChainStore
+}
+
+// NewGxPChainSource returns a gxp.ChainSource reading the audit chain in s.
+// Checkpoint signatures are verified with the key installed by
+// SetCheckpointKey.
+func NewGxPChainSource(s ChainStore) gxp.ChainSource {
+	return gxpChainSource{store: s}
+}
+
+func (g gxpChainSource) Records(ctx context.Context, limit int) ([]gxp.AuditRecord, error) {
+	entries, err := g.store.QueryAuditLogs(ctx, limit, "", "")
+	if err != nil {
+		return nil, err
+	}
+	// QueryAuditLogs is newest first; the GxP trail reads oldest first.
+	records := make([]gxp.AuditRecord, len(entries))
+	for i, e := range entries {
+		records[len(entries)-1-i] = gxpRecord(e)
+	}
+	return records, nil
+}
+
+func (g gxpChainSource) Verify(ctx context.Context) (gxp.ChainStatus, error) {
+	report, err := VerifyChain(ctx, g.store, CheckpointPublicKey(), InstalledCheckpointMaxAge())
+	if err != nil {
+		return gxp.ChainStatus{}, err
+	}
+	status := gxp.ChainStatus{
+		Valid:           report.Valid,
+		TotalRecords:    report.TotalRecords,
+		VerifiedRecords: report.VerifiedRecords,
+		BrokenAtIndex:   -1,
+		VerifiedAt:      report.VerifiedAt.Format(time.RFC3339),
+		Message:         report.Message,
+		LegacyRecords:   report.LegacyRecords,
+		Checkpoints:     report.Checkpoints,
+	}
+	if !report.Valid {
+		status.BrokenAtIndex = report.LegacyRecords + report.VerifiedRecords
+		status.BrokenRecordID = strconv.FormatInt(report.BrokenAtID, 10)
+	}
+	return status, nil
+}
+
+// gxpRecord maps an audit_log row written by Log onto a GxP record.
+func gxpRecord(e store.AuditEntry) gxp.AuditRecord {
+	var detail struct {
+		TargetType string `json:"target_type"`
+		TargetID   string `json:"target_id"`
+		Details    string `json:"details"`
+	}
+	_ = json.Unmarshal([]byte(e.Detail), &detail)
+	resource := detail.TargetType
+	if detail.TargetID != "" {
+		resource += "/" + detail.TargetID
+	}
+	return gxp.AuditRecord{
+		ID:           strconv.FormatInt(e.ID, 10),
+		Timestamp:    e.Timestamp,
+		UserID:       e.UserID,
+		Action:       e.Action,
+		Resource:     resource,
+		Detail:       detail.Details,
+		PreviousHash: e.PrevHash,
+		RecordHash:   e.Hash,
+	}
+}
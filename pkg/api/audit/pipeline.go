@@ -0,0 +1,422 @@
+package audit
+
+// Durable SIEM export pipeline — #9643.
+//
+// RecordEvent appends every audit event to the on-disk spool (spool.go). One
+// worker per destination reads the spool from that destination's cursor,
+// drops events its filters exclude, and sends the rest in batches of
+// BatchSize. A failed batch is retried with exponential back-off until the
+// destination accepts it; only then does the cursor move, so an outage
+// delays events instead of losing them.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pipeline defaults.
+const (
+	defaultBatchSize       = 100
+	maxBatchSize           = 5000
+	defaultSegmentBytes    = 8 << 20 // 8 MB
+	defaultSpoolMaxBytes   = 1 << 30 // 1 GB
+	defaultSyncInterval    = time.Second
+	defaultInitialBackoff  = time.Second
+	defaultMaxBackoff      = 5 * time.Minute
+	defaultCompactInterval = time.Minute
+	// sendTimeout bounds one batch delivery attempt.
+	sendTimeout = time.Minute
+	// downAfterFailures is how many consecutive failed attempts move a
+	// destination from degraded to down.
+	downAfterFailures = 5
+)
+
+// PipelineOptions tunes the export pipeline. Zero values use the defaults.
+type PipelineOptions struct {
+	// SegmentBytes is the size at which the spool starts a new segment file.
+	SegmentBytes int64
+	// MaxSpoolBytes caps the spool on disk. When a destination stays down
+	// long enough to reach it, the oldest undelivered events are dropped.
+	MaxSpoolBytes int64
+	// SyncInterval is how often appended events are fsynced.
+	SyncInterval time.Duration
+	// InitialBackoff and MaxBackoff bound the retry delay of a failing
+	// destination.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (o PipelineOptions) withDefaults() PipelineOptions {
+	if o.SegmentBytes <= 0 {
+		o.SegmentBytes = defaultSegmentBytes
+	}
+	if o.MaxSpoolBytes <= 0 {
+		o.MaxSpoolBytes = defaultSpoolMaxBytes
+	}
+	if o.SyncInterval <= 0 {
+		o.SyncInterval = defaultSyncInterval
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = defaultInitialBackoff
+	}
+	if o.MaxBackoff < o.InitialBackoff {
+		o.MaxBackoff = max(defaultMaxBackoff, o.InitialBackoff)
+	}
+	return o
+}
+
+// Pipeline delivers spooled audit events to the registered destinations.
+type Pipeline struct {
+	spool *spool
+	opts  PipelineOptions
+
+	mu      sync.Mutex
+	ctx     context.Context // set while Run is active
+	workers map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// OpenPipeline opens (or creates) the spool in dir and makes the pipeline
+// the sink for RecordEvent. Events recorded before Run starts are spooled
+// and delivered once it does.
+func OpenPipeline(dir string, opts PipelineOptions) (*Pipeline, error) {
+	opts = opts.withDefaults()
+	sp, err := openSpool(dir, opts.SegmentBytes, opts.MaxSpoolBytes)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pipeline{spool: sp, opts: opts, workers: map[string]context.CancelFunc{}}
+	defaultRegistry.mu.Lock()
+	defaultRegistry.pipeline = p
+	defaultRegistry.mu.Unlock()
+	for _, id := range destinationIDs() {
+		p.track(id)
+	}
+	return p, nil
+}
+
+// track pins the spool cursor of destination id, so a new destination
+// receives every event recorded after it was registered even if its worker
+// starts later.
+func (p *Pipeline) track(id string) {
+	if _, err := p.spool.cursor(id); err != nil {
+		slog.Error("[Audit] failed to initialise export cursor", "destination", id, "error", err)
+	}
+}
+
+// Run starts a worker for every registered destination, and for each one
+// registered later, then blocks until ctx is cancelled. It syncs and closes
+// the spool before returning.
+func (p *Pipeline) Run(ctx context.Context) {
+	p.mu.Lock()
+	p.ctx = ctx
+	p.mu.Unlock()
+	for _, id := range destinationIDs() {
+		p.startWorker(id)
+	}
+
+	syncTicker := time.NewTicker(p.opts.SyncInterval)
+	defer syncTicker.Stop()
+	compactTicker := time.NewTicker(defaultCompactInterval)
+	defer compactTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.ctx = nil
+			p.mu.Unlock()
+			p.wg.Wait()
+			defaultRegistry.mu.Lock()
+			if defaultRegistry.pipeline == p {
+				defaultRegistry.pipeline = nil
+			}
+			defaultRegistry.mu.Unlock()
+			if err := p.spool.close(); err != nil {
+				slog.Error("[Audit] failed to close export spool", "error", err)
+			}
+			return
+		case <-syncTicker.C:
+			if err := p.spool.sync(); err != nil {
+				slog.Error("[Audit] failed to sync export spool", "error", err)
+			}
+		case <-compactTicker.C:
+			if err := p.spool.compact(destinationIDs()); err != nil {
+				slog.Warn("[Audit] failed to compact export spool", "error", err)
+			}
+		}
+	}
+}
+
+// append spools evt for delivery.
+func (p *Pipeline) append(evt PipelineEvent) error {
+	_, err := p.spool.append(evt)
+	return err
+}
+
+// startWorker starts delivery to destination id if Run is active and no
+// worker is running for it yet.
+func (p *Pipeline) startWorker(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx == nil {
+		return
+	}
+	if _, ok := p.workers[id]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.workers[id] = cancel
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			p.mu.Lock()
+			delete(p.workers, id)
+			p.mu.Unlock()
+			cancel()
+		}()
+		p.deliver(ctx, id)
+	}()
+}
+
+// deliver runs the read → filter → send → ack loop for one destination.
+func (p *Pipeline) deliver(ctx context.Context, id string) {
+	cursor, _ := p.spool.cursor(id)
+	var pos spoolPos
+	backoff := p.opts.InitialBackoff
+	for {
+		adapter, dest, ok := lookupDestination(id)
+		if !ok {
+			return
+		}
+		batchSize := dest.BatchSize
+		if batchSize <= 0 {
+			batchSize = defaultBatchSize
+		}
+		wake := p.spool.wait()
+		recs, err := p.spool.read(&pos, cursor, batchSize)
+		if err != nil {
+			slog.Error("[Audit] failed to read export spool", "destination", id, "error", err)
+			if !sleepCtx(ctx, p.opts.InitialBackoff) {
+				return
+			}
+			continue
+		}
+		if len(recs) == 0 {
+			updateBacklog(id, p.spool.head()-cursor)
+			select {
+			case <-ctx.Done():
+				return
+			case <-wake:
+			}
+			continue
+		}
+
+		filter, _ := parseFilters(dest.Filters)
+		events := make([]PipelineEvent, 0, len(recs))
+		for _, r := range recs {
+			if filter.match(r.Event) {
+				events = append(events, r.Event)
+			}
+		}
+		last := recs[len(recs)-1].Seq
+
+		if len(events) > 0 {
+			sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			err = adapter.Send(sendCtx, events)
+			cancel()
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				recordFailure(id, err)
+				slog.Warn("[Audit] export batch failed, will retry", "destination", id, "events", len(events), "retryIn", backoff, "error", err)
+				if !sleepCtx(ctx, backoff) {
+					return
+				}
+				backoff = min(backoff*2, p.opts.MaxBackoff)
+				// Re-read the same records on the next attempt.
+				pos = spoolPos{}
+				continue
+			}
+			recordSuccess(id, events)
+		}
+		backoff = p.opts.InitialBackoff
+		if err := p.spool.ack(id, last); err != nil {
+			slog.Error("[Audit] failed to persist export cursor", "destination", id, "error", err)
+		}
+		cursor = last
+		updateBacklog(id, p.spool.head()-cursor)
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Per-destination filters
+// -----------------------------------------------------------------------------
+
+// Filter keys accepted in ExportDestination.Filters.
+const (
+	FilterEventType = "event_type"
+	FilterCluster   = "cluster"
+	FilterUser      = "user"
+)
+
+// eventFilter is the parsed form of ExportDestination.Filters. Each entry is
+// "key=pattern" or "key!=pattern" where key is event_type, cluster or user
+// and pattern may use path.Match globs ("delete_*"). Include patterns for
+// the same key are ORed, different keys are ANDed, and an event matching
+// any exclude pattern is dropped. No filters means every event is sent.
+type eventFilter struct {
+	include map[string][]string
+	exclude map[string][]string
+}
+
+func parseFilters(filters []string) (eventFilter, error) {
+	f := eventFilter{include: map[string][]string{}, exclude: map[string][]string{}}
+	for _, raw := range filters {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		target := f.include
+		key, pattern, ok := strings.Cut(raw, "!=")
+		if ok {
+			target = f.exclude
+		} else if key, pattern, ok = strings.Cut(raw, "="); !ok {
+			return f, fmt.Errorf("filter %q: want key=pattern or key!=pattern", raw)
+		}
+		key, pattern = strings.TrimSpace(key), strings.TrimSpace(pattern)
+		switch key {
+		case FilterEventType, FilterCluster, FilterUser:
+		default:
+			return f, fmt.Errorf("filter %q: unknown key %q (want %s, %s or %s)", raw, key, FilterEventType, FilterCluster, FilterUser)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return f, fmt.Errorf("filter %q: %w", raw, err)
+		}
+		target[key] = append(target[key], pattern)
+	}
+	return f, nil
+}
+
+func (f eventFilter) match(e PipelineEvent) bool {
+	fields := map[string]string{FilterEventType: e.EventType, FilterCluster: e.Cluster, FilterUser: e.User}
+	for key, patterns := range f.exclude {
+		if matchAny(patterns, fields[key]) {
+			return false
+		}
+	}
+	for key, patterns := range f.include {
+		if !matchAny(patterns, fields[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, v string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, v); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// Live destination status
+// -----------------------------------------------------------------------------
+
+// rateWindow is the trailing window EventsPerMinute is computed over.
+const rateWindow = time.Minute
+
+// destinationStats is the live delivery state kept next to each
+// ExportDestination.
+type destinationStats struct {
+	consecutiveFailures int
+	sentBatches         int64
+	sends               []rateSample
+	backlog             uint64
+}
+
+type rateSample struct {
+	at time.Time
+	n  int
+}
+
+func (s *destinationStats) eventsPerMinute(now time.Time) int {
+	total := 0
+	for _, r := range s.sends {
+		if now.Sub(r.at) < rateWindow {
+			total += r.n
+		}
+	}
+	return total
+}
+
+func (s *destinationStats) prune(now time.Time) {
+	i := 0
+	for i < len(s.sends) && now.Sub(s.sends[i].at) >= rateWindow {
+		i++
+	}
+	s.sends = s.sends[i:]
+}
+
+func recordSuccess(id string, events []PipelineEvent) {
+	now := time.Now().UTC()
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+	d, st := defaultRegistry.destinationLocked(id)
+	if d == nil {
+		return
+	}
+	st.consecutiveFailures = 0
+	st.sentBatches++
+	st.prune(now)
+	st.sends = append(st.sends, rateSample{at: now, n: len(events)})
+	d.TotalEvents += int64(len(events))
+	d.LastEventAt = &now
+	d.Status = StatusActive
+}
+
+func recordFailure(id string, err error) {
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+	d, st := defaultRegistry.destinationLocked(id)
+	if d == nil {
+		return
+	}
+	msg := err.Error()
+	st.consecutiveFailures++
+	d.ErrorCount++
+	d.LastError = &msg
+	d.Status = StatusDegraded
+	if st.consecutiveFailures >= downAfterFailures || errors.Is(err, ErrDestinationUnsupported) {
+		d.Status = StatusDown
+	}
+}
+
+func updateBacklog(id string, backlog uint64) {
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+	if _, st := defaultRegistry.destinationLocked(id); st != nil {
+		st.backlog = backlog
+	}
+}
@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyDestination fails every Send while down is set and records the
+// batches it accepts.
+type flakyDestination struct {
+	mu      sync.Mutex
+	down    bool
+	batches [][]PipelineEvent
+}
+
+func (d *flakyDestination) Send(_ context.Context, events []PipelineEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.down {
+		return errors.New("503 service unavailable")
+	}
+	d.batches = append(d.batches, append([]PipelineEvent(nil), events...))
+	return nil
+}
+
+func (d *flakyDestination) Provider() DestinationProvider { return ProviderWebhook }
+
+func (d *flakyDestination) setDown(down bool) {
+	d.mu.Lock()
+	d.down = down
+	d.mu.Unlock()
+}
+
+func (d *flakyDestination) delivered() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var ids []string
+	for _, b := range d.batches {
+		for _, e := range b {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// registerTestDestination registers a webhook destination and swaps in
+// adapter for its Send.
+func registerTestDestination(t *testing.T, cfg DestinationConfig, adapter Destination) {
+	t.Helper()
+	cfg.Provider = ProviderWebhook
+	cfg.URL = "http://siem.example.com/ingest"
+	defaultRegistry.mu.Lock()
+	p := defaultRegistry.pipeline
+	defaultRegistry.pipeline = nil // start the worker after the swap below
+	defaultRegistry.mu.Unlock()
+	_, err := RegisterDestination(cfg)
+	require.NoError(t, err)
+	defaultRegistry.mu.Lock()
+	defaultRegistry.adapters[cfg.ID] = adapter
+	defaultRegistry.pipeline = p
+	defaultRegistry.mu.Unlock()
+	if p != nil {
+		p.track(cfg.ID)
+		p.startWorker(cfg.ID)
+	}
+}
+
+func startTestPipeline(t *testing.T, dir string) (*Pipeline, context.CancelFunc) {
+	t.Helper()
+	p, err := OpenPipeline(dir, PipelineOptions{
+		SegmentBytes:   512,
+		SyncInterval:   10 * time.Millisecond,
+		InitialBackoff: 5 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	stop := func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+	return p, stop
+}
+
+func recordN(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = prefix + string(rune('a'+i))
+		RecordEvent(PipelineEvent{ID: ids[i], EventType: ActionDeleteUser, Timestamp: time.Now().UTC()})
+	}
+	return ids
+}
+
+func TestPipeline_RetriesThroughOutage(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	startTestPipeline(t, t.TempDir())
+
+	dest := &flakyDestination{down: true}
+	registerTestDestination(t, DestinationConfig{ID: "splunk", BatchSize: 4}, dest)
+
+	want := recordN("e", 10)
+	require.Eventually(t, func() bool {
+		d := ListDestinations()[0]
+		return d.ErrorCount >= 2 && d.Status != StatusActive
+	}, 5*time.Second, 5*time.Millisecond)
+	d := ListDestinations()[0]
+	require.NotNil(t, d.LastError)
+	assert.Contains(t, *d.LastError, "503")
+	assert.Empty(t, dest.delivered())
+
+	dest.setDown(false)
+	require.Eventually(t, func() bool {
+		return len(dest.delivered()) == len(want) && ListDestinations()[0].Backlog == 0
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, want, dest.delivered())
+	dest.mu.Lock()
+	for _, b := range dest.batches {
+		assert.LessOrEqual(t, len(b), 4)
+	}
+	dest.mu.Unlock()
+
+	d = ListDestinations()[0]
+	assert.Equal(t, StatusActive, d.Status)
+	assert.Equal(t, int64(10), d.TotalEvents)
+	assert.Equal(t, 10, d.EventsPerMinute)
+	assert.Greater(t, BuildSummary(time.Now()).ErrorRate, 0.0)
+}
+
+func TestPipeline_ResumesFromCursorAfterRestart(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	dir := t.TempDir()
+	_, stop := startTestPipeline(t, dir)
+
+	first := &flakyDestination{}
+	registerTestDestination(t, DestinationConfig{ID: "elastic"}, first)
+	recordN("a", 3)
+	require.Eventually(t, func() bool { return len(first.delivered()) == 3 }, 5*time.Second, 5*time.Millisecond)
+
+	// Events recorded while the destination is down stay in the spool
+	// across a restart.
+	first.setDown(true)
+	pending := recordN("b", 5)
+	stop()
+
+	ResetForTest()
+	startTestPipeline(t, dir)
+	second := &flakyDestination{}
+	registerTestDestination(t, DestinationConfig{ID: "elastic"}, second)
+	require.Eventually(t, func() bool { return len(second.delivered()) == len(pending) }, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, pending, second.delivered())
+}
+
+func TestPipeline_Filters(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	startTestPipeline(t, t.TempDir())
+
+	dest := &flakyDestination{}
+	registerTestDestination(t, DestinationConfig{
+		ID:      "soc",
+		Filters: []string{"event_type=delete_*", "event_type=update_role", "cluster!=dev"},
+	}, dest)
+
+	RecordEvent(PipelineEvent{ID: "keep-1", EventType: ActionDeleteUser, Cluster: "prod"})
+	RecordEvent(PipelineEvent{ID: "drop-type", EventType: "login", Cluster: "prod"})
+	RecordEvent(PipelineEvent{ID: "drop-cluster", EventType: ActionUpdateRole, Cluster: "dev"})
+	RecordEvent(PipelineEvent{ID: "keep-2", EventType: ActionUpdateRole})
+
+	require.Eventually(t, func() bool { return ListDestinations()[0].Backlog == 0 && len(dest.delivered()) == 2 }, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"keep-1", "keep-2"}, dest.delivered())
+
+	events := RecentEvents()
+	require.Len(t, events, 4)
+	assert.Equal(t, 1, events[0].DestinationCount)
+	assert.Equal(t, 0, events[1].DestinationCount)
+}
+
+func TestParseFilters_Invalid(t *testing.T) {
+	for _, f := range []string{"namespace=kube-system", "event_type", "user=[", "=x"} {
+		_, err := parseFilters([]string{f})
+		assert.Error(t, err, f)
+	}
+	_, err := RegisterDestination(DestinationConfig{ID: "x", Provider: ProviderWebhook, URL: "http://x", Filters: []string{"bogus=1"}})
+	assert.Error(t, err)
+}
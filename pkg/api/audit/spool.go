@@ -0,0 +1,445 @@
+package audit
+
+// On-disk spool (write-ahead log) for the SIEM export pipeline — #9643.
+//
+// Events are appended as newline-delimited JSON records to segment files
+// named after the sequence number of their first record. Each destination
+// tracks the last sequence it has delivered in cursors.json; a segment is
+// deleted once every destination has moved past it. Cursors only advance
+// after a destination accepts a batch, so delivery is at-least-once across
+// restarts and outages.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/fileutil"
+)
+
+const (
+	spoolSegmentExt  = ".wal"
+	spoolCursorsFile = "cursors.json"
+	// spoolFileMode keeps audit data readable by the console user only.
+	spoolFileMode = 0o600
+	spoolDirMode  = 0o700
+)
+
+// spoolRecord is one line of a segment file.
+type spoolRecord struct {
+	Seq   uint64        `json:"seq"`
+	Event PipelineEvent `json:"event"`
+}
+
+type spoolSegment struct {
+	first uint64 // sequence of the first record
+	path  string
+	size  int64
+}
+
+// spoolPos remembers where a reader stopped so the next read can seek
+// instead of rescanning the segment from the start.
+type spoolPos struct {
+	seq    uint64 // last sequence returned
+	first  uint64 // segment the offset belongs to
+	offset int64
+}
+
+// spool is the durable event log shared by every destination worker.
+type spool struct {
+	dir             string
+	maxSegmentBytes int64
+	maxBytes        int64
+
+	mu       sync.Mutex
+	segments []spoolSegment // sorted by first; the last one is active
+	active   *os.File
+	nextSeq  uint64
+	dirty    bool // active segment has unsynced writes
+	cursors  map[string]uint64
+	changed  chan struct{} // closed and replaced on every append
+	closed   bool
+	dropped  uint64 // events discarded because the spool hit maxBytes
+}
+
+func openSpool(dir string, maxSegmentBytes, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, spoolDirMode); err != nil {
+		return nil, fmt.Errorf("audit spool: create %s: %w", dir, err)
+	}
+	s := &spool{
+		dir:             dir,
+		maxSegmentBytes: maxSegmentBytes,
+		maxBytes:        maxBytes,
+		nextSeq:         1,
+		cursors:         map[string]uint64{},
+		changed:         make(chan struct{}),
+	}
+	if err := s.loadSegments(); err != nil {
+		return nil, err
+	}
+	if err := s.loadCursors(); err != nil {
+		return nil, err
+	}
+	if len(s.segments) == 0 {
+		if err := s.rotateLocked(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	last := &s.segments[len(s.segments)-1]
+	f, err := os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, spoolFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("audit spool: open %s: %w", last.path, err)
+	}
+	s.active = f
+	return s, nil
+}
+
+// loadSegments indexes existing segment files and recovers the next
+// sequence number from the newest one, truncating a torn final record left
+// by a crash mid-write.
+func (s *spool) loadSegments() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("audit spool: read %s: %w", s.dir, err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, spoolSegmentExt) {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return fmt.Errorf("audit spool: stat %s: %w", name, err)
+		}
+		s.segments = append(s.segments, spoolSegment{first: first, path: filepath.Join(s.dir, name), size: info.Size()})
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].first < s.segments[j].first })
+	if len(s.segments) == 0 {
+		return nil
+	}
+
+	last := &s.segments[len(s.segments)-1]
+	s.nextSeq = last.first
+	f, err := os.Open(last.path)
+	if err != nil {
+		return fmt.Errorf("audit spool: open %s: %w", last.path, err)
+	}
+	defer f.Close()
+	var good int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// EOF, possibly after a partial record without its newline.
+			break
+		}
+		var rec spoolRecord
+		if json.Unmarshal(line, &rec) != nil {
+			break
+		}
+		good += int64(len(line))
+		s.nextSeq = rec.Seq + 1
+	}
+	if good < last.size {
+		slog.Warn("[Audit] truncating torn record at end of spool segment", "segment", last.path, "bytes", last.size-good)
+		if err := os.Truncate(last.path, good); err != nil {
+			return fmt.Errorf("audit spool: truncate %s: %w", last.path, err)
+		}
+		last.size = good
+	}
+	return nil
+}
+
+func (s *spool) loadCursors() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, spoolCursorsFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("audit spool: read cursors: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.cursors); err != nil {
+		return fmt.Errorf("audit spool: decode cursors: %w", err)
+	}
+	return nil
+}
+
+// rotateLocked closes the active segment and starts a new one at nextSeq.
+func (s *spool) rotateLocked() error {
+	if s.active != nil {
+		if err := s.active.Sync(); err != nil {
+			return fmt.Errorf("audit spool: sync segment: %w", err)
+		}
+		if err := s.active.Close(); err != nil {
+			return fmt.Errorf("audit spool: close segment: %w", err)
+		}
+		s.active = nil
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("%020d%s", s.nextSeq, spoolSegmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, spoolFileMode)
+	if err != nil {
+		return fmt.Errorf("audit spool: create segment: %w", err)
+	}
+	s.active = f
+	s.dirty = false
+	s.segments = append(s.segments, spoolSegment{first: s.nextSeq, path: path})
+	s.enforceMaxBytesLocked()
+	return nil
+}
+
+// enforceMaxBytesLocked drops the oldest closed segments while the spool
+// exceeds maxBytes. This only happens when a destination has been failing
+// for long enough to fill the spool; it is logged as an error because the
+// dropped events are lost for every destination that had not sent them.
+func (s *spool) enforceMaxBytesLocked() {
+	if s.maxBytes <= 0 {
+		return
+	}
+	var total int64
+	for _, seg := range s.segments {
+		total += seg.size
+	}
+	for total > s.maxBytes && len(s.segments) > 1 {
+		oldest := s.segments[0]
+		lost := s.segments[1].first - oldest.first
+		if err := os.Remove(oldest.path); err != nil && !os.IsNotExist(err) {
+			slog.Error("[Audit] failed to drop spool segment", "segment", oldest.path, "error", err)
+			return
+		}
+		s.segments = s.segments[1:]
+		total -= oldest.size
+		s.dropped += lost
+		slog.Error("[Audit] spool full, dropped oldest undelivered events", "events", lost, "maxBytes", s.maxBytes)
+	}
+}
+
+// append writes evt to the active segment and returns its sequence number.
+func (s *spool) append(evt PipelineEvent) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, fmt.Errorf("audit spool: closed")
+	}
+	rec := spoolRecord{Seq: s.nextSeq, Event: evt}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return 0, fmt.Errorf("audit spool: encode event: %w", err)
+	}
+	line = append(line, '\n')
+	if _, err := s.active.Write(line); err != nil {
+		return 0, fmt.Errorf("audit spool: write event: %w", err)
+	}
+	s.segments[len(s.segments)-1].size += int64(len(line))
+	s.nextSeq++
+	s.dirty = true
+	if s.segments[len(s.segments)-1].size >= s.maxSegmentBytes {
+		if err := s.rotateLocked(); err != nil {
+			return rec.Seq, err
+		}
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return rec.Seq, nil
+}
+
+// sync flushes the active segment to disk. Appends are synced in batches by
+// the pipeline rather than one fsync per audit entry.
+func (s *spool) sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || !s.dirty {
+		return nil
+	}
+	s.dirty = false
+	return s.active.Sync()
+}
+
+// wait returns a channel that is closed on the next append.
+func (s *spool) wait() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+// head returns the sequence of the newest record, or 0 when empty.
+func (s *spool) head() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nextSeq - 1
+}
+
+// cursor returns the last sequence id has delivered. A destination seen
+// for the first time starts at the current head, so it receives events
+// recorded from now on rather than the spool's whole history.
+func (s *spool) cursor(id string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.cursors[id]; ok {
+		return c, nil
+	}
+	s.cursors[id] = s.nextSeq - 1
+	return s.cursors[id], s.saveCursorsLocked()
+}
+
+// ack records that id has delivered every record up to seq.
+func (s *spool) ack(id string, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq <= s.cursors[id] {
+		return nil
+	}
+	s.cursors[id] = seq
+	return s.saveCursorsLocked()
+}
+
+func (s *spool) saveCursorsLocked() error {
+	data, err := json.Marshal(s.cursors)
+	if err != nil {
+		return err
+	}
+	return fileutil.AtomicWriteFile(filepath.Join(s.dir, spoolCursorsFile), data, spoolFileMode)
+}
+
+// compact deletes closed segments every destination in ids has delivered,
+// and forgets cursors of destinations no longer configured.
+func (s *spool) compact(ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	keep := make(map[string]bool, len(ids))
+	minCursor := s.nextSeq - 1
+	for _, id := range ids {
+		keep[id] = true
+		if c, ok := s.cursors[id]; ok && c < minCursor {
+			minCursor = c
+		}
+	}
+	pruned := false
+	for id := range s.cursors {
+		if !keep[id] {
+			delete(s.cursors, id)
+			pruned = true
+		}
+	}
+	// Segment i is fully delivered when the next one starts at or before
+	// minCursor+1.
+	for len(s.segments) > 1 && s.segments[1].first <= minCursor+1 {
+		if err := os.Remove(s.segments[0].path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("audit spool: remove segment: %w", err)
+		}
+		s.segments = s.segments[1:]
+	}
+	if pruned {
+		return s.saveCursorsLocked()
+	}
+	return nil
+}
+
+// read returns up to max records after the given sequence, resuming from
+// pos when it still points at that sequence.
+func (s *spool) read(pos *spoolPos, after uint64, max int) ([]spoolRecord, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("audit spool: closed")
+	}
+	if after >= s.nextSeq-1 {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	segments := append([]spoolSegment(nil), s.segments...)
+	s.mu.Unlock()
+
+	// Find the segment holding after+1; if it was dropped, start at the
+	// oldest remaining one.
+	idx := 0
+	for i, seg := range segments {
+		if seg.first <= after+1 {
+			idx = i
+		}
+	}
+	var out []spoolRecord
+	for ; idx < len(segments) && len(out) < max; idx++ {
+		seg := segments[idx]
+		var offset int64
+		if pos.seq == after && pos.first == seg.first {
+			offset = pos.offset
+		}
+		recs, end, err := readSegment(seg.path, offset, after, max-len(out))
+		if err != nil {
+			return out, err
+		}
+		out = append(out, recs...)
+		if len(recs) > 0 {
+			after = recs[len(recs)-1].Seq
+			*pos = spoolPos{seq: after, first: seg.first, offset: end}
+		}
+	}
+	return out, nil
+}
+
+// readSegment reads up to max complete records with a sequence above after,
+// starting at offset, and returns them with the offset just past the last.
+func readSegment(path string, offset int64, after uint64, max int) ([]spoolRecord, int64, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// Dropped or compacted since the segment list was copied.
+		return nil, offset, nil
+	}
+	if err != nil {
+		return nil, offset, fmt.Errorf("audit spool: open %s: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, fmt.Errorf("audit spool: seek %s: %w", path, err)
+	}
+	r := bufio.NewReader(f)
+	var out []spoolRecord
+	for len(out) < max {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// EOF or a record still being written; stop before it.
+			return out, offset, nil
+		}
+		offset += int64(len(line))
+		var rec spoolRecord
+		if err := json.Unmarshal(bytes.TrimSpace(line), &rec); err != nil {
+			slog.Warn("[Audit] skipping undecodable spool record", "segment", path, "error", err)
+			continue
+		}
+		if rec.Seq > after {
+			out = append(out, rec)
+		}
+	}
+	return out, offset, nil
+}
+
+func (s *spool) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if err := s.active.Sync(); err != nil {
+		s.active.Close()
+		return err
+	}
+	return s.active.Close()
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/sbom"
+	"github.com/kubestellar/console/pkg/compliance/signing"
+	"github.com/kubestellar/console/pkg/compliance/slsa"
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// healthyClusterNames lists the clusters live compliance evaluations cover
+// by default.
+func (s *Server) healthyClusterNames(ctx context.Context) ([]string, error) {
+	healthy, _, err := s.k8sClient.HealthyClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(healthy))
+	for _, cl := range healthy {
+		names = append(names, cl.Name)
+	}
+	return names, nil
+}
+
+// newLiveSigningEngine builds the signature verification engine from the
+// SIGNING_* trust settings. With no trust roots configured images are still
+// inventoried and reported as signed/unsigned, but none verify.
+func newLiveSigningEngine(cfg Config, k8sClient *k8s.MultiClusterClient) (*signing.Engine, error) {
+	opts, err := signingVerifyOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := signing.NewVerifier(oci.NewClient(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return signing.NewLiveEngine(k8s.NewSigningInventory(k8sClient), verifier), nil
+}
+
+// newLiveSBOMEngine builds the SBOM engine, matching components against the
+// offline OSV feed in SBOM_OSV_FEED_DIR when one is configured.
+func newLiveSBOMEngine(cfg Config, k8sClient *k8s.MultiClusterClient) (*sbom.Engine, error) {
+	return sbom.NewLiveEngine(k8s.NewSigningInventory(k8sClient), oci.NewClient(), sbom.LiveOptions{
+		FeedDir: cfg.SBOMOSVFeedDir,
+	})
+}
+
+// newLiveSLSAEngine builds the SLSA provenance engine. Attestations are
+// verified against the SIGNING_* trust roots and scored against the
+// per-namespace policies in SLSA_POLICY_FILE.
+func newLiveSLSAEngine(cfg Config, k8sClient *k8s.MultiClusterClient) (*slsa.Engine, error) {
+	opts, err := signingVerifyOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := signing.NewVerifier(oci.NewClient(), opts)
+	if err != nil {
+		return nil, err
+	}
+	var policies slsa.Policies
+	if cfg.SLSAPolicyFile != "" {
+		if policies, err = slsa.LoadPolicies(cfg.SLSAPolicyFile); err != nil {
+			return nil, fmt.Errorf("SLSA_POLICY_FILE: %w", err)
+		}
+	}
+	return slsa.NewLiveEngine(k8s.NewSigningInventory(k8sClient), oci.NewClient(), verifier, policies), nil
+}
+
+func signingVerifyOptions(cfg Config) (signing.VerifyOptions, error) {
+	var opts signing.VerifyOptions
+	for _, path := range splitList(cfg.SigningPublicKeys, ",") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return opts, fmt.Errorf("SIGNING_PUBLIC_KEYS: %w", err)
+		}
+		keys, err := signing.ParsePublicKeys(data)
+		if err != nil {
+			return opts, fmt.Errorf("SIGNING_PUBLIC_KEYS %s: %w", path, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, k := range keys {
+			opts.Keys = append(opts.Keys, signing.TrustedKey{Name: name, Key: k})
+		}
+	}
+	for _, pair := range splitList(cfg.SigningKeylessIdentities, ";") {
+		issuer, subject, ok := strings.Cut(pair, "=")
+		if !ok || issuer == "" || subject == "" {
+			return opts, fmt.Errorf("SIGNING_KEYLESS_IDENTITIES: %q is not issuer=subject-regexp", pair)
+		}
+		opts.Identities = append(opts.Identities, signing.KeylessIdentity{Issuer: issuer, SubjectRegexp: subject})
+	}
+	if cfg.SigningFulcioRoots != "" {
+		data, err := os.ReadFile(cfg.SigningFulcioRoots)
+		if err != nil {
+			return opts, fmt.Errorf("SIGNING_FULCIO_ROOTS: %w", err)
+		}
+		opts.FulcioRoots = x509.NewCertPool()
+		if !opts.FulcioRoots.AppendCertsFromPEM(data) {
+			return opts, fmt.Errorf("SIGNING_FULCIO_ROOTS: no certificates in %s", cfg.SigningFulcioRoots)
+		}
+	}
+	if cfg.SigningRekorPublicKeys != "" {
+		data, err := os.ReadFile(cfg.SigningRekorPublicKeys)
+		if err != nil {
+			return opts, fmt.Errorf("SIGNING_REKOR_PUBLIC_KEYS: %w", err)
+		}
+		if opts.RekorKeys, err = signing.ParsePublicKeys(data); err != nil {
+			return opts, fmt.Errorf("SIGNING_REKOR_PUBLIC_KEYS: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+func splitList(s, sep string) []string {
+	var out []string
+	for _, part := range strings.Split(s, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
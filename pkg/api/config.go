@@ -5,6 +5,7 @@ import (
 	"os"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/kubestellar/console/pkg/settings"
 )
@@ -39,6 +40,16 @@ const (
 	// continue to work. Larger deployments can raise this for big form posts;
 	// smaller appliances can lower it to tighten the DoS surface.
 	envMaxBodyBytes = "MAX_BODY_BYTES"
+
+	// minAlertEvalInterval keeps ALERT_EVAL_INTERVAL from hammering every
+	// cluster's API server with rule evaluations.
+	minAlertEvalInterval = 10 * time.Second
+	// minAuditCheckpointInterval keeps AUDIT_CHECKPOINT_INTERVAL from
+	// filling audit_checkpoints with a row per audit entry.
+	minAuditCheckpointInterval = time.Minute
+	// minMissionsKBSyncInterval keeps MISSIONS_KB_SYNC_INTERVAL from
+	// polling the bundle registry in a tight loop.
+	minMissionsKBSyncInterval = time.Minute
 )
 
 // Config holds server configuration
@@ -47,6 +58,7 @@ type Config struct {
 	DevMode               bool
 	SkipOnboarding        bool
 	DatabasePath          string
+	DatabaseURL           string // PostgreSQL DSN; when set it replaces the SQLite database at DatabasePath
 	GitHubClientID        string
 	GitHubSecret          string
 	GitHubURL             string // GitHub base URL (e.g., "https://github.ibm.com"), defaults to "https://github.com"
@@ -70,6 +82,15 @@ type Config struct {
 	FeedbackRepoName    string // GitHub repo name (e.g., "console")
 	// GitHub activity rewards
 	RewardsGitHubOrgs string // Org filter for GitHub search (e.g., "org:kubestellar org:llm-d")
+	// Bearer token Alertmanager must send to /webhooks/alertmanager (empty = receiver disabled)
+	AlertmanagerWebhookToken string
+	// SIEM audit export: JSON file listing destinations (empty = export disabled)
+	AuditExportConfig string
+	// Directory for the audit export spool (empty = "audit-spool" next to the database)
+	AuditSpoolDir string
+	// Ed25519 key signing audit chain checkpoints (empty = checkpoints disabled).
+	// Keep it off the database volume so the log cannot be re-signed.
+	AuditCheckpointKey string
 	// Benchmark data configuration (Google Drive)
 	BenchmarkGoogleDriveAPIKey string // API key for fetching benchmark data from Google Drive
 	BenchmarkFolderID          string // Google Drive folder ID containing benchmark results
@@ -113,6 +134,67 @@ type Config struct {
 	NoLocalAgent bool
 	// Watchdog support: when set, the backend listens on this port instead of Port
 	BackendPort int
+	// Image signature verification trust roots (live supply-chain signing).
+	// SigningPublicKeys is a comma-separated list of PEM public key files.
+	// SigningKeylessIdentities is a semicolon-separated list of
+	// "issuer=subject-regexp" pairs accepted for Fulcio keyless signatures.
+	// SigningFulcioRoots and SigningRekorPublicKeys are PEM files.
+	SigningPublicKeys        string
+	SigningKeylessIdentities string
+	SigningFulcioRoots       string
+	SigningRekorPublicKeys   string
+	// SBOMOSVFeedDir is a directory of offline OSV vulnerability records
+	// (*.json or the per-ecosystem all.zip exports) that SBOM components
+	// are matched against. Empty disables vulnerability correlation.
+	SBOMOSVFeedDir string
+	// SLSAPolicyFile is a YAML file of per-namespace SLSA provenance
+	// policies (trusted builders, source repos, minimum level). Empty
+	// verifies attestations without builder or source requirements.
+	SLSAPolicyFile string
+	// AlertEvalInterval is how often server-side alert rules are evaluated
+	// (ALERT_EVAL_INTERVAL, a Go duration). Zero uses the engine default.
+	AlertEvalInterval time.Duration
+	// AuditCheckpointInterval is how often the audit chain head is signed
+	// (AUDIT_CHECKPOINT_INTERVAL, a Go duration). Zero uses the default.
+	AuditCheckpointInterval time.Duration
+	// GPUChargebackConfig is a JSON file of GPU chargeback rates, teams and
+	// the monthly report email (GPU_CHARGEBACK_CONFIG; empty = GPU-hours
+	// only, no email).
+	GPUChargebackConfig string
+	// MissionsSource selects where the mission knowledge base is served
+	// from (MISSIONS_SOURCE): "github" (default, live from
+	// kubestellar/console-kb) or "bundle" (a local, verified console-kb
+	// bundle mirror for air-gapped installs).
+	MissionsSource string
+	// MissionsKBDir holds imported console-kb bundles (MISSIONS_KB_DIR;
+	// empty = "missions-kb" next to the database).
+	MissionsKBDir string
+	// MissionsKBBundle is a bundle file imported whenever it changes
+	// (MISSIONS_KB_BUNDLE).
+	MissionsKBBundle string
+	// MissionsKBOCI is an OCI artifact reference to pull bundles from
+	// (MISSIONS_KB_OCI), with optional registry basic-auth credentials.
+	MissionsKBOCI         string
+	MissionsKBOCIUsername string
+	MissionsKBOCIPassword string
+	// MissionsKBPublicKey is an Ed25519 PEM key; when set every bundle must
+	// be signed with it (MISSIONS_KB_PUBLIC_KEY).
+	MissionsKBPublicKey string
+	// MissionsKBSyncInterval is how often the bundle sources are checked
+	// (MISSIONS_KB_SYNC_INTERVAL, a Go duration). Zero uses the default.
+	MissionsKBSyncInterval time.Duration
+	// InformerCache serves the hot Kubernetes resources (pods, deployments,
+	// events, nodes, services) from per-cluster shared informers instead of
+	// a LIST per request, and lets SSE streams push changes with
+	// ?watch=true (K8S_INFORMER_CACHE=true).
+	InformerCache bool
+	// InformerResources limits the informer cache to a comma-separated
+	// subset of those resources (K8S_INFORMER_RESOURCES; empty = all).
+	InformerResources string
+	// AgentTunnelEnabled lets kc-agents dial out to this console over an
+	// authenticated reverse tunnel so their owners can use them remotely
+	// (AGENT_TUNNEL_ENABLED=true).
+	AgentTunnelEnabled bool
 }
 
 // LoadConfigFromEnv loads configuration from environment variables
@@ -177,6 +259,7 @@ func LoadConfigFromEnv() Config {
 		Port:                  port,
 		DevMode:               devMode,
 		DatabasePath:          dbPath,
+		DatabaseURL:           os.Getenv("DATABASE_URL"),
 		GitHubClientID:        githubClientID,
 		GitHubSecret:          githubSecret,
 		GitHubURL:             getEnvOrDefault("GITHUB_URL", "https://github.com"),
@@ -199,6 +282,25 @@ func LoadConfigFromEnv() Config {
 		FeedbackRepoName:    getEnvOrDefault("FEEDBACK_REPO_NAME", "console"),
 		// GitHub activity rewards
 		RewardsGitHubOrgs: getEnvOrDefault("REWARDS_GITHUB_ORGS", "repo:kubestellar/console repo:kubestellar/console-marketplace repo:kubestellar/console-kb repo:kubestellar/docs"),
+		// Alertmanager webhook receiver
+		AlertmanagerWebhookToken: os.Getenv("ALERTMANAGER_WEBHOOK_TOKEN"),
+		// SIEM audit export pipeline
+		AuditExportConfig: os.Getenv("AUDIT_EXPORT_CONFIG"),
+		AuditSpoolDir:     os.Getenv("AUDIT_SPOOL_DIR"),
+		// Audit chain checkpoints
+		AuditCheckpointKey:      os.Getenv("AUDIT_CHECKPOINT_KEY"),
+		AuditCheckpointInterval: parseAuditCheckpointInterval(os.Getenv("AUDIT_CHECKPOINT_INTERVAL")),
+		// GPU chargeback reports
+		GPUChargebackConfig: os.Getenv("GPU_CHARGEBACK_CONFIG"),
+		// Mission knowledge base source
+		MissionsSource:         getEnvOrDefault("MISSIONS_SOURCE", "github"),
+		MissionsKBDir:          os.Getenv("MISSIONS_KB_DIR"),
+		MissionsKBBundle:       os.Getenv("MISSIONS_KB_BUNDLE"),
+		MissionsKBOCI:          os.Getenv("MISSIONS_KB_OCI"),
+		MissionsKBOCIUsername:  os.Getenv("MISSIONS_KB_OCI_USERNAME"),
+		MissionsKBOCIPassword:  os.Getenv("MISSIONS_KB_OCI_PASSWORD"),
+		MissionsKBPublicKey:    os.Getenv("MISSIONS_KB_PUBLIC_KEY"),
+		MissionsKBSyncInterval: parseMissionsKBSyncInterval(os.Getenv("MISSIONS_KB_SYNC_INTERVAL")),
 		// Skip onboarding questionnaire for new users
 		SkipOnboarding: os.Getenv("SKIP_ONBOARDING") == "true",
 		// Benchmark data from Google Drive
@@ -228,7 +330,68 @@ func LoadConfigFromEnv() Config {
 		NoLocalAgent: os.Getenv("NO_LOCAL_AGENT") == "true",
 		// Watchdog backend port override
 		BackendPort: backendPort,
+		// Image signature verification trust roots
+		SigningPublicKeys:        os.Getenv("SIGNING_PUBLIC_KEYS"),
+		SigningKeylessIdentities: os.Getenv("SIGNING_KEYLESS_IDENTITIES"),
+		SigningFulcioRoots:       os.Getenv("SIGNING_FULCIO_ROOTS"),
+		SigningRekorPublicKeys:   os.Getenv("SIGNING_REKOR_PUBLIC_KEYS"),
+		// Offline vulnerability feed for SBOM correlation
+		SBOMOSVFeedDir: os.Getenv("SBOM_OSV_FEED_DIR"),
+		// Per-namespace SLSA provenance policy
+		SLSAPolicyFile: os.Getenv("SLSA_POLICY_FILE"),
+		// Server-side alert rule evaluation cadence
+		AlertEvalInterval: parseAlertEvalInterval(os.Getenv("ALERT_EVAL_INTERVAL")),
+		// Opt-in per-cluster informer cache
+		InformerCache:     os.Getenv("K8S_INFORMER_CACHE") == "true",
+		InformerResources: os.Getenv("K8S_INFORMER_RESOURCES"),
+		// Opt-in kc-agent reverse tunnel
+		AgentTunnelEnabled: os.Getenv("AGENT_TUNNEL_ENABLED") == "true",
+	}
+}
+
+// parseAlertEvalInterval parses ALERT_EVAL_INTERVAL, returning zero (the
+// engine default) when it is unset or invalid.
+func parseAlertEvalInterval(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < minAlertEvalInterval {
+		slog.Warn("invalid ALERT_EVAL_INTERVAL; using default",
+			"value", raw, "minimum", minAlertEvalInterval)
+		return 0
+	}
+	return d
+}
+
+// parseAuditCheckpointInterval parses AUDIT_CHECKPOINT_INTERVAL, returning
+// zero (the default) when it is unset or invalid.
+func parseAuditCheckpointInterval(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < minAuditCheckpointInterval {
+		slog.Warn("invalid AUDIT_CHECKPOINT_INTERVAL; using default",
+			"value", raw, "minimum", minAuditCheckpointInterval)
+		return 0
+	}
+	return d
+}
+
+// parseMissionsKBSyncInterval parses MISSIONS_KB_SYNC_INTERVAL, returning
+// zero (the default) when it is unset or invalid.
+func parseMissionsKBSyncInterval(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < minMissionsKBSyncInterval {
+		slog.Warn("invalid MISSIONS_KB_SYNC_INTERVAL; using default",
+			"value", raw, "minimum", minMissionsKBSyncInterval)
+		return 0
 	}
+	return d
 }
 
 func getEnvOrDefault(key, defaultVal string) string {
@@ -77,13 +77,6 @@ type GPUUtilizationWorker struct {
 
 // NewGPUUtilizationWorker creates a new GPU utilization worker
 func NewGPUUtilizationWorker(s store.Store, k8sClient *k8s.MultiClusterClient, notificationService *notifications.Service) *GPUUtilizationWorker {
-	intervalMs := defaultUtilPollIntervalMs
-	if envVal := os.Getenv("GPU_UTIL_POLL_INTERVAL_MS"); envVal != "" {
-		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
-			intervalMs = parsed
-		}
-	}
-
 	gpuMetricsEnabled := os.Getenv("GPU_METRICS_ENABLED") == "true"
 
 	overThreshold := defaultOverThreshold
@@ -114,7 +107,7 @@ func NewGPUUtilizationWorker(s store.Store, k8sClient *k8s.MultiClusterClient, n
 	return &GPUUtilizationWorker{
 		store:               s,
 		k8sClient:           k8sClient,
-		interval:            time.Duration(intervalMs) * time.Millisecond,
+		interval:            gpuUtilPollInterval(),
 		stopCh:              make(chan struct{}),
 		baseCtx:             ctx,
 		baseCancel:          cancel,
@@ -128,6 +121,18 @@ func NewGPUUtilizationWorker(s store.Store, k8sClient *k8s.MultiClusterClient, n
 	}
 }
 
+// gpuUtilPollInterval returns the snapshot polling interval, overridable
+// with GPU_UTIL_POLL_INTERVAL_MS.
+func gpuUtilPollInterval() time.Duration {
+	intervalMs := defaultUtilPollIntervalMs
+	if envVal := os.Getenv("GPU_UTIL_POLL_INTERVAL_MS"); envVal != "" {
+		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
+			intervalMs = parsed
+		}
+	}
+	return time.Duration(intervalMs) * time.Millisecond
+}
+
 // Start begins the background polling loop
 func (w *GPUUtilizationWorker) Start() {
 	safego.GoWith("gpu-utilization-worker", func() {
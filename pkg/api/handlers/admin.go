@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"log/slog"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/store"
 )
 
 // AdminHandler exposes internal operational state for administrator visibility.
 type AdminHandler struct {
-	tracker *middleware.FailureTracker
+	tracker   *middleware.FailureTracker
+	k8sClient *k8s.MultiClusterClient
+	store     store.Store
+	router    *airouter.Router
 }
 
 // NewAdminHandler creates a handler wired to the given FailureTracker.
@@ -26,3 +34,54 @@ func (h *AdminHandler) GetRateLimitStatus(c *fiber.Ctx) error {
 	}
 	return c.JSON(h.tracker.Status())
 }
+
+// WithInformerCache enables GET /api/admin/informer-cache for the given
+// client. Only admins may read it.
+func (h *AdminHandler) WithInformerCache(client *k8s.MultiClusterClient, s store.Store) *AdminHandler {
+	h.k8sClient = client
+	h.store = s
+	return h
+}
+
+// GetInformerCacheStatus reports whether the informer cache is enabled and,
+// per cluster, which resources have synced, how many objects are cached and
+// the last watch error.
+func (h *AdminHandler) GetInformerCacheStatus(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	if isDemoMode(c) || !h.k8sClient.InformerCacheEnabled() {
+		return c.JSON(fiber.Map{"enabled": false, "clusters": []k8s.InformerClusterStatus{}})
+	}
+	return c.JSON(fiber.Map{"enabled": true, "clusters": h.k8sClient.InformerStatus()})
+}
+
+// WithAIRouter enables GET /api/admin/ai-routing for the given router. Only
+// admins may read it.
+func (h *AdminHandler) WithAIRouter(router *airouter.Router, s store.Store) *AdminHandler {
+	h.router = router
+	h.store = s
+	return h
+}
+
+// GetAIRouting returns the AI routing policy, every provider's circuit
+// breaker, today's token spend per category and the recent routing
+// decisions. ?user=<id> adds that user's spend for the day.
+func (h *AdminHandler) GetAIRouting(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	if isDemoMode(c) || h.router == nil {
+		return c.JSON(fiber.Map{"enabled": false})
+	}
+	resp := fiber.Map{"enabled": true, "routing": h.router.Snapshot(c.UserContext())}
+	if userID := c.Query("user"); userID != "" {
+		usage, err := h.router.UserUsage(c.UserContext(), userID)
+		if err != nil {
+			slog.Error("[Admin] failed to read user token usage", "user", userID, "error", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to read user token usage")
+		}
+		resp["userUsage"] = usage
+	}
+	return c.JSON(resp)
+}
@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAdminHandler_GetRateLimitStatus(t *testing.T) {
@@ -51,3 +58,46 @@ func TestAdminHandler_GetRateLimitStatus(t *testing.T) {
 		assert.Equal(t, 1, status.Keys[0].Failures)
 	})
 }
+
+func TestAdminHandler_GetAIRouting(t *testing.T) {
+	adminID := uuid.New()
+	viewerID := uuid.New()
+	mockStore := new(test.MockStore)
+	mockStore.On("GetUser", adminID).Return(&models.User{ID: adminID, Role: models.UserRoleAdmin}, nil)
+	mockStore.On("GetUser", viewerID).Return(&models.User{ID: viewerID, Role: models.UserRoleViewer}, nil)
+	mockStore.On("CountUsersByRole").Return(1, 0, 1, nil)
+
+	router := airouter.New(airouter.Config{}, nil)
+	_, err := router.Route(context.Background(), airouter.Request{UserID: "alice", Category: airouter.CategoryMissions, Preferred: "claude"},
+		func(string) bool { return true },
+		func(context.Context, string) (int64, error) { return 25, nil })
+	require.NoError(t, err)
+
+	h := NewAdminHandler(nil).WithAIRouter(router, mockStore)
+	newApp := func(userID uuid.UUID) *fiber.App {
+		app := fiber.New()
+		app.Get("/admin/ai-routing", func(c *fiber.Ctx) error {
+			c.Locals("userID", userID)
+			return h.GetAIRouting(c)
+		})
+		return app
+	}
+
+	resp, err := newApp(viewerID).Test(httptest.NewRequest("GET", "/admin/ai-routing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = newApp(adminID).Test(httptest.NewRequest("GET", "/admin/ai-routing?user=alice", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body struct {
+		Enabled   bool              `json:"enabled"`
+		Routing   airouter.Snapshot `json:"routing"`
+		UserUsage airouter.Usage    `json:"userUsage"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.True(t, body.Enabled)
+	require.Len(t, body.Routing.Decisions, 1)
+	assert.Equal(t, "claude", body.Routing.Decisions[0].Selected)
+	assert.Equal(t, int64(25), body.UserUsage.Total)
+}
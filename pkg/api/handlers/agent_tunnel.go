@@ -0,0 +1,599 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	gorillaws "github.com/gorilla/websocket"
+
+	"github.com/kubestellar/console/pkg/agenttunnel"
+	"github.com/kubestellar/console/pkg/api/audit"
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// agentPairingCodeTTL is how long a pairing code can be exchanged.
+	agentPairingCodeTTL = 10 * time.Minute
+	// maxPendingPairingCodes caps unexchanged codes per user.
+	maxPendingPairingCodes = 5
+	// agentPairingCodeLen is the number of code characters, shown to the
+	// user as two dash-separated halves.
+	agentPairingCodeLen = 8
+	// agentPairingAlphabet omits characters that are easy to misread.
+	agentPairingAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	// agentCredentialPrefix marks tunnel credentials so they are
+	// recognizable in config files and secret scanners.
+	agentCredentialPrefix = "kca_"
+	agentCredentialBytes  = 32
+	maxAgentNameLen       = 64
+
+	// agentTunnelHeaderTimeout bounds how long the console waits for the
+	// agent to start answering a proxied request. Bodies (SSE, logs) may
+	// stream for longer.
+	agentTunnelHeaderTimeout = 90 * time.Second
+	agentTunnelStreamBuf     = 32 * 1024
+
+	// agentTunnelHost is the placeholder host of proxied requests; the
+	// tunnel ignores it.
+	agentTunnelHost = "kc-agent"
+
+	agentTunnelCredentialLocal = "agentTunnelCredential"
+	agentTunnelLocal           = "agentTunnel"
+	agentTunnelTargetLocal     = "agentTunnelTarget"
+)
+
+// agentTunnelHopHeaders are not forwarded in either direction. Credentials
+// for the console (cookie, bearer JWT) never reach the agent.
+var agentTunnelHopHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+	"Host":                true,
+	"Content-Length":      true,
+	"Authorization":       true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"Origin":              true,
+	"Referer":             true,
+}
+
+type agentPairing struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+}
+
+// agentTunnel is one connected kc-agent.
+type agentTunnel struct {
+	cred        models.AgentTunnelCredential
+	session     *agenttunnel.Session
+	client      *http.Client
+	dialer      *gorillaws.Dialer
+	connectedAt time.Time
+}
+
+// AgentTunnelHandler lets a kc-agent dial out to the console and serves the
+// agent's HTTP routes and WebSocket streams to its owner through that
+// tunnel (#6195), so a shared console can reach agents on engineers'
+// laptops. Agents pair with a one-time code and then authenticate with a
+// per-agent credential.
+//
+// Pairing codes and live tunnels are held in memory, so an agent must
+// pair with and connect to the same console replica.
+//
+// The relay drops Origin before forwarding, so the agent cannot apply its
+// own allowlist; Proxy checks Origin against the console's instead.
+type AgentTunnelHandler struct {
+	store          store.Store
+	allowedOrigins map[string]bool
+
+	mu       sync.Mutex
+	pairings map[string]agentPairing
+	tunnels  map[uuid.UUID][]*agentTunnel // by user, newest last
+
+	wsRelay fiber.Handler
+	connect fiber.Handler
+}
+
+// NewAgentTunnelHandler creates the agent tunnel handler. allowedOrigins
+// are the browser origins the console accepts (its CORS origins); proxied
+// requests from any other origin are rejected.
+func NewAgentTunnelHandler(s store.Store, allowedOrigins []string) *AgentTunnelHandler {
+	h := &AgentTunnelHandler{
+		store:          s,
+		allowedOrigins: make(map[string]bool, len(allowedOrigins)),
+		pairings:       make(map[string]agentPairing),
+		tunnels:        make(map[uuid.UUID][]*agentTunnel),
+	}
+	for _, origin := range allowedOrigins {
+		if origin = normalizeOrigin(origin); origin != "" {
+			h.allowedOrigins[origin] = true
+		}
+	}
+	h.wsRelay = websocket.New(h.relayWebSocket)
+	h.connect = websocket.New(h.serveTunnel)
+	return h
+}
+
+// CreatePairingCode issues a one-time code the user passes to kc-agent
+// (--tunnel-pairing-code) to pair it with their account.
+func (h *AgentTunnelHandler) CreatePairingCode(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == uuid.Nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Missing user")
+	}
+
+	code, err := newAgentPairingCode()
+	if err != nil {
+		slog.Error("[AgentTunnel] failed to generate pairing code", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create pairing code")
+	}
+	now := time.Now()
+	expiresAt := now.Add(agentPairingCodeTTL)
+
+	h.mu.Lock()
+	pending := 0
+	for k, p := range h.pairings {
+		if now.After(p.expiresAt) {
+			delete(h.pairings, k)
+			continue
+		}
+		if p.userID == userID {
+			pending++
+		}
+	}
+	if pending >= maxPendingPairingCodes {
+		h.mu.Unlock()
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "too many unused pairing codes; wait for them to expire"})
+	}
+	h.pairings[code] = agentPairing{userID: userID, expiresAt: expiresAt}
+	h.mu.Unlock()
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code":      code[:agentPairingCodeLen/2] + "-" + code[agentPairingCodeLen/2:],
+		"expiresAt": expiresAt.UTC(),
+	})
+}
+
+// Register exchanges a pairing code for a per-agent credential. It is
+// called by kc-agent, which has no console session, so it sits outside
+// the JWT-protected /api group.
+func (h *AgentTunnelHandler) Register(c *fiber.Ctx) error {
+	var body struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	code := normalizeAgentPairingCode(body.Code)
+	name := strings.TrimSpace(body.Name)
+	if name == "" {
+		name = "kc-agent"
+	}
+	if len(name) > maxAgentNameLen {
+		name = name[:maxAgentNameLen]
+	}
+
+	h.mu.Lock()
+	pairing, ok := h.pairings[code]
+	delete(h.pairings, code)
+	h.mu.Unlock()
+	if !ok || time.Now().After(pairing.expiresAt) {
+		slog.Warn("[AgentTunnel] rejected invalid or expired pairing code", "ip", c.IP())
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired pairing code")
+	}
+
+	credential, err := newAgentCredential()
+	if err != nil {
+		slog.Error("[AgentTunnel] failed to generate credential", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to pair agent")
+	}
+	cred := &models.AgentTunnelCredential{
+		UserID:    pairing.userID,
+		Name:      name,
+		TokenHash: hashAgentCredential(credential),
+	}
+	if err := h.store.CreateAgentTunnelCredential(c.UserContext(), cred); err != nil {
+		slog.Error("[AgentTunnel] failed to store credential", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to pair agent")
+	}
+	c.Locals("userID", pairing.userID)
+	audit.Log(c, audit.ActionPairAgent, "agent", cred.ID.String(), name)
+	slog.Info("[AgentTunnel] agent paired", "agentID", cred.ID, "user", pairing.userID, "name", name)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"agentId":    cred.ID,
+		"credential": credential,
+	})
+}
+
+// AuthenticateAgent checks the tunnel credential on the connect request
+// before it is upgraded to a WebSocket.
+func (h *AgentTunnelHandler) AuthenticateAgent(c *fiber.Ctx) error {
+	auth := c.Get("Authorization")
+	credential := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	if credential == "" || credential == auth || !strings.HasPrefix(credential, agentCredentialPrefix) {
+		return fiber.NewError(fiber.StatusUnauthorized, "Missing agent credential")
+	}
+	cred, err := h.store.GetAgentTunnelCredentialByTokenHash(c.UserContext(), hashAgentCredential(credential))
+	if err != nil {
+		slog.Error("[AgentTunnel] credential lookup failed", "error", err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Agent authentication unavailable")
+	}
+	if cred == nil {
+		slog.Warn("[AgentTunnel] rejected unknown agent credential", "ip", c.IP())
+		return fiber.NewError(fiber.StatusUnauthorized, "Unknown or revoked agent credential")
+	}
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	c.Locals(agentTunnelCredentialLocal, *cred)
+	return c.Next()
+}
+
+// Connect upgrades an authenticated agent connection and serves the tunnel
+// until it drops.
+func (h *AgentTunnelHandler) Connect(c *fiber.Ctx) error {
+	return h.connect(c)
+}
+
+func (h *AgentTunnelHandler) serveTunnel(conn *websocket.Conn) {
+	cred, ok := conn.Locals(agentTunnelCredentialLocal).(models.AgentTunnelCredential)
+	if !ok {
+		_ = conn.Close()
+		return
+	}
+	conn.SetReadLimit(agenttunnel.MaxMessageSize)
+	session := agenttunnel.NewSession(conn, false)
+	t := &agentTunnel{
+		cred:    cred,
+		session: session,
+		client: &http.Client{Transport: &http.Transport{
+			DialContext:           session.DialContext,
+			ResponseHeaderTimeout: agentTunnelHeaderTimeout,
+			DisableCompression:    true,
+		}},
+		dialer:      &gorillaws.Dialer{NetDialContext: session.DialContext, HandshakeTimeout: agentTunnelHeaderTimeout},
+		connectedAt: time.Now().UTC(),
+	}
+	h.addTunnel(t)
+	slog.Info("[AgentTunnel] agent connected", "agentID", cred.ID, "user", cred.UserID, "name", cred.Name)
+	if err := h.store.TouchAgentTunnelCredential(context.Background(), cred.ID, t.connectedAt); err != nil {
+		slog.Warn("[AgentTunnel] failed to record agent connection", "agentID", cred.ID, "error", err)
+	}
+
+	// The fiber connection is recycled when this handler returns, so wait
+	// until the session has stopped using it.
+	session.Wait()
+	h.removeTunnel(t)
+	t.client.CloseIdleConnections()
+	slog.Info("[AgentTunnel] agent disconnected", "agentID", cred.ID, "user", cred.UserID)
+}
+
+func (h *AgentTunnelHandler) addTunnel(t *agentTunnel) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// A reconnecting agent replaces its previous session.
+	for _, old := range h.tunnels[t.cred.UserID] {
+		if old.cred.ID == t.cred.ID {
+			_ = old.session.Close()
+		}
+	}
+	h.tunnels[t.cred.UserID] = append(h.tunnels[t.cred.UserID], t)
+}
+
+func (h *AgentTunnelHandler) removeTunnel(t *agentTunnel) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	list := h.tunnels[t.cred.UserID]
+	for i, other := range list {
+		if other == t {
+			list = append(list[:i:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(h.tunnels, t.cred.UserID)
+		return
+	}
+	h.tunnels[t.cred.UserID] = list
+}
+
+// tunnelFor returns the user's most recently connected agent.
+func (h *AgentTunnelHandler) tunnelFor(userID uuid.UUID) *agentTunnel {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	list := h.tunnels[userID]
+	for i := len(list) - 1; i >= 0; i-- {
+		select {
+		case <-list[i].session.Done():
+		default:
+			return list[i]
+		}
+	}
+	return nil
+}
+
+// ListAgents returns the user's paired agents and whether each is
+// connected.
+func (h *AgentTunnelHandler) ListAgents(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	creds, err := h.store.ListAgentTunnelCredentials(c.UserContext(), userID)
+	if err != nil {
+		slog.Error("[AgentTunnel] failed to list agents", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list agents")
+	}
+
+	connected := make(map[uuid.UUID]time.Time)
+	h.mu.Lock()
+	for _, t := range h.tunnels[userID] {
+		connected[t.cred.ID] = t.connectedAt
+	}
+	h.mu.Unlock()
+
+	type agentView struct {
+		models.AgentTunnelCredential
+		Connected   bool       `json:"connected"`
+		ConnectedAt *time.Time `json:"connectedAt,omitempty"`
+	}
+	out := make([]agentView, 0, len(creds))
+	for _, cred := range creds {
+		v := agentView{AgentTunnelCredential: cred}
+		if at, ok := connected[cred.ID]; ok {
+			v.Connected = true
+			v.ConnectedAt = &at
+		}
+		out = append(out, v)
+	}
+	return c.JSON(fiber.Map{"agents": out})
+}
+
+// RevokeAgent deletes an agent's credential and drops its tunnel.
+func (h *AgentTunnelHandler) RevokeAgent(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid agent id")
+	}
+	if err := h.store.DeleteAgentTunnelCredential(c.UserContext(), userID, id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Agent not found")
+		}
+		slog.Error("[AgentTunnel] failed to revoke agent", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to revoke agent")
+	}
+
+	h.mu.Lock()
+	for _, t := range h.tunnels[userID] {
+		if t.cred.ID == id {
+			_ = t.session.Close()
+		}
+	}
+	h.mu.Unlock()
+	audit.Log(c, audit.ActionRevokeAgent, "agent", id.String())
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Proxy forwards a request for the caller's kc-agent through its tunnel.
+// /api/agent-tunnel/proxy/<path> maps to <path> on the agent, so pointing
+// the frontend's agent URL at /api/agent-tunnel/proxy reuses every agent
+// route, including the /ws and /ws/exec streams.
+func (h *AgentTunnelHandler) Proxy(c *fiber.Ctx) error {
+	if origin := c.Get(fiber.HeaderOrigin); !h.originAllowed(c, origin) {
+		slog.Warn("[AgentTunnel] rejected proxied request from unauthorized origin",
+			"origin", origin, "path", c.Path())
+		return fiber.NewError(fiber.StatusForbidden, "Origin not allowed")
+	}
+	t := h.tunnelFor(middleware.GetUserID(c))
+	if t == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "no kc-agent is connected for this user"})
+	}
+	target := agentTunnelTarget(c)
+	if websocket.IsWebSocketUpgrade(c) {
+		c.Locals(agentTunnelLocal, t)
+		c.Locals(agentTunnelTargetLocal, target)
+		return h.wsRelay(c)
+	}
+	return h.proxyHTTP(c, t, target)
+}
+
+// originAllowed reports whether a proxied request may reach the agent. A
+// request without Origin is not cross-site (browsers send it on every
+// WebSocket handshake and cross-origin request); otherwise the origin must
+// be one of the console's own or the host the console is served from.
+func (h *AgentTunnelHandler) originAllowed(c *fiber.Ctx, origin string) bool {
+	if origin == "" {
+		return true
+	}
+	if h.allowedOrigins[normalizeOrigin(origin)] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, string(c.Request().Host()))
+}
+
+// normalizeOrigin lower-cases an origin and drops a trailing slash, so
+// configured URLs compare equal to the Origin header browsers send.
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+}
+
+// agentTunnelTarget is the agent path and query for a proxied request,
+// without the agent token query parameters the browser adds for a direct
+// agent connection.
+func agentTunnelTarget(c *fiber.Ctx) string {
+	target := path.Clean("/" + c.Params("*"))
+	args := c.Context().QueryArgs()
+	args.Del("token")
+	args.Del("_token")
+	if q := string(args.QueryString()); q != "" {
+		target += "?" + q
+	}
+	return target
+}
+
+func (h *AgentTunnelHandler) proxyHTTP(c *fiber.Ctx, t *agentTunnel, target string) error {
+	// The response body is streamed after this handler returns, so the
+	// upstream request must not be tied to the fiber context.
+	req, err := http.NewRequestWithContext(context.Background(), c.Method(), "http://"+agentTunnelHost+target, bytes.NewReader(c.Body()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid agent request")
+	}
+	c.Request().Header.VisitAll(func(k, v []byte) {
+		key := http.CanonicalHeaderKey(string(k))
+		if !agentTunnelHopHeaders[key] {
+			req.Header.Add(key, string(v))
+		}
+	})
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		slog.Warn("[AgentTunnel] proxied request failed", "agentID", t.cred.ID, "path", req.URL.Path, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "kc-agent tunnel request failed"})
+	}
+	for k, vs := range resp.Header {
+		if agentTunnelHopHeaders[k] || strings.HasPrefix(k, "Access-Control-") {
+			continue
+		}
+		for _, v := range vs {
+			c.Response().Header.Add(k, v)
+		}
+	}
+	c.Status(resp.StatusCode)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		c.Set("X-Accel-Buffering", "no")
+	}
+
+	// Stream the body and flush each read so SSE events are not held back.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer resp.Body.Close()
+		buf := make([]byte, agentTunnelStreamBuf)
+		for {
+			n, readErr := resp.Body.Read(buf)
+			if n > 0 {
+				if _, err := w.Write(buf[:n]); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+			if readErr != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// relayWebSocket bridges a browser WebSocket to the same path on the agent.
+func (h *AgentTunnelHandler) relayWebSocket(conn *websocket.Conn) {
+	t, _ := conn.Locals(agentTunnelLocal).(*agentTunnel)
+	target, _ := conn.Locals(agentTunnelTargetLocal).(string)
+	if t == nil {
+		_ = conn.Close()
+		return
+	}
+
+	upstream, resp, err := t.dialer.Dial("ws://"+agentTunnelHost+target, nil)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		slog.Warn("[AgentTunnel] agent WebSocket dial failed", "agentID", t.cred.ID, "path", target, "error", err)
+		_ = conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "kc-agent unreachable"))
+		_ = conn.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		defer func() { done <- struct{}{} }()
+		for {
+			typ, msg, err := upstream.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(typ, msg); err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		defer func() { done <- struct{}{} }()
+		for {
+			typ, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := upstream.WriteMessage(typ, msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Closing both ends unblocks the other pump; wait for it before the
+	// fiber connection is recycled.
+	<-done
+	_ = upstream.Close()
+	_ = conn.Close()
+	<-done
+}
+
+func newAgentPairingCode() (string, error) {
+	max := big.NewInt(int64(len(agentPairingAlphabet)))
+	b := make([]byte, agentPairingCodeLen)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = agentPairingAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// normalizeAgentPairingCode accepts codes as typed by a user: any case,
+// with or without the dash.
+func normalizeAgentPairingCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func newAgentCredential() (string, error) {
+	b := make([]byte, agentCredentialBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return agentCredentialPrefix + hex.EncodeToString(b), nil
+}
+
+func hashAgentCredential(credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/agenttunnel"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// testConsoleOrigin is the frontend origin the test console allows.
+const testConsoleOrigin = "https://console.example.com"
+
+// startAgentTunnelApp serves the tunnel routes on a real listener (WebSocket
+// upgrades need one) with every /api request authenticated as userID.
+func startAgentTunnelApp(t *testing.T, userID uuid.UUID) (baseURL string, h *AgentTunnelHandler) {
+	t.Helper()
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "tunnel.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	h = NewAgentTunnelHandler(db, []string{testConsoleOrigin})
+	app := fiber.New()
+	app.Post("/agent-tunnel/register", h.Register)
+	app.Get("/agent-tunnel/connect", h.AuthenticateAgent, h.Connect)
+	api := app.Group("/api", func(c *fiber.Ctx) error {
+		c.Locals("userID", userID)
+		return c.Next()
+	})
+	api.Post("/agent-tunnel/pairing-codes", h.CreatePairingCode)
+	api.Get("/agent-tunnel/agents", h.ListAgents)
+	api.All("/agent-tunnel/proxy/*", h.Proxy)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+	return "http://" + ln.Addr().String(), h
+}
+
+func postJSON(t *testing.T, url string, body any) (int, map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func pairAgent(t *testing.T, baseURL string) string {
+	t.Helper()
+	status, out := postJSON(t, baseURL+"/api/agent-tunnel/pairing-codes", nil)
+	require.Equal(t, http.StatusCreated, status)
+	code := out["code"].(string)
+
+	// Codes are accepted as typed: lower case, without the dash.
+	status, out = postJSON(t, baseURL+"/agent-tunnel/register", map[string]string{
+		"code": strings.ToLower(strings.ReplaceAll(code, "-", "")),
+		"name": "laptop",
+	})
+	require.Equal(t, http.StatusCreated, status)
+	credential := out["credential"].(string)
+	require.True(t, strings.HasPrefix(credential, agentCredentialPrefix))
+
+	// A code is single-use.
+	status, _ = postJSON(t, baseURL+"/agent-tunnel/register", map[string]string{"code": code})
+	assert.Equal(t, http.StatusUnauthorized, status)
+	return credential
+}
+
+func TestAgentTunnel_PairAndProxy(t *testing.T) {
+	userID := uuid.New()
+	baseURL, h := startAgentTunnelApp(t, userID)
+	credential := pairAgent(t, baseURL)
+
+	wsURL := "ws" + strings.TrimPrefix(baseURL, "http") + "/agent-tunnel/connect"
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": []string{"Bearer kca_unknown"}})
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Authorization": []string{"Bearer " + credential}})
+	require.NoError(t, err)
+	conn.SetReadLimit(agenttunnel.MaxMessageSize)
+	session := agenttunnel.NewSession(conn, true)
+	t.Cleanup(func() { _ = session.Close() })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/clusters", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Query", r.URL.RawQuery)
+		w.Header().Set("X-Authorization", r.Header.Get("Authorization"))
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		_, _ = io.WriteString(w, `{"clusters":[]}`)
+	})
+	agentSrv := &http.Server{Handler: mux}
+	go func() { _ = agentSrv.Serve(session) }()
+	t.Cleanup(func() { _ = agentSrv.Close() })
+
+	require.Eventually(t, func() bool { return h.tunnelFor(userID) != nil }, 5*time.Second, 10*time.Millisecond)
+
+	req, err := http.NewRequest(http.MethodGet, baseURL+"/api/agent-tunnel/proxy/clusters?token=secret&x=1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer console-jwt")
+	proxied, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(proxied.Body)
+	proxied.Body.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, proxied.StatusCode)
+	assert.JSONEq(t, `{"clusters":[]}`, string(body))
+	assert.Equal(t, "x=1", proxied.Header.Get("X-Query"), "agent token query params are stripped")
+	assert.Empty(t, proxied.Header.Get("X-Authorization"), "console credentials never reach the agent")
+	assert.Empty(t, proxied.Header.Get("Access-Control-Allow-Origin"))
+
+	// The agent never sees Origin, so the relay enforces the console's
+	// allowlist for both HTTP and WebSocket requests.
+	for origin, want := range map[string]int{
+		"https://Console.example.com": http.StatusOK,
+		baseURL:                       http.StatusOK,
+		"https://attacker.example":    http.StatusForbidden,
+		"https://console.example.com.attacker.example": http.StatusForbidden,
+	} {
+		req, err := http.NewRequest(http.MethodGet, baseURL+"/api/agent-tunnel/proxy/clusters", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, resp.StatusCode, "origin %s", origin)
+	}
+	_, resp, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(baseURL, "http")+"/api/agent-tunnel/proxy/ws",
+		http.Header{"Origin": []string{"https://attacker.example"}})
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = http.Get(baseURL + "/api/agent-tunnel/agents")
+	require.NoError(t, err)
+	var list struct {
+		Agents []struct {
+			Name      string `json:"name"`
+			Connected bool   `json:"connected"`
+		} `json:"agents"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	require.Len(t, list.Agents, 1)
+	assert.Equal(t, "laptop", list.Agents[0].Name)
+	assert.True(t, list.Agents[0].Connected)
+
+	_ = session.Close()
+	require.Eventually(t, func() bool { return h.tunnelFor(userID) == nil }, 5*time.Second, 10*time.Millisecond)
+	resp, err = http.Get(baseURL + "/api/agent-tunnel/proxy/clusters")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestNormalizeAgentPairingCode(t *testing.T) {
+	assert.Equal(t, "ABCD2345", normalizeAgentPairingCode(" abcd-2345 "))
+	assert.Equal(t, "ABCD2345", normalizeAgentPairingCode("ABCD 2345"))
+}
@@ -16,6 +16,13 @@ func NewAirGapHandler() *AirGapHandler {
 	return &AirGapHandler{engine: airgap.NewEngine()}
 }
 
+// WithConsoleCheck adds a live console requirement to the assessment and
+// returns the handler for chaining.
+func (h *AirGapHandler) WithConsoleCheck(check airgap.ConsoleCheck) *AirGapHandler {
+	h.engine.AddConsoleCheck(check)
+	return h
+}
+
 // RegisterPublicRoutes mounts read-only endpoints on the given router group.
 func (h *AirGapHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/compliance/airgap")
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kubestellar/console/pkg/alerting"
+	"github.com/kubestellar/console/pkg/api/audit"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// AlertRuleHandler serves the server-side alert rules evaluated by the
+// alerting engine and the alerts they have raised.
+type AlertRuleHandler struct {
+	engine *alerting.Engine
+	store  store.Store
+}
+
+// NewAlertRuleHandler creates an alert rule handler. s gates rule access to
+// console admins, since rule channels carry webhook URLs and API keys.
+func NewAlertRuleHandler(engine *alerting.Engine, s store.Store) *AlertRuleHandler {
+	return &AlertRuleHandler{engine: engine, store: s}
+}
+
+// RegisterRoutes mounts /api/alerts/rules and /api/alerts/active.
+func (h *AlertRuleHandler) RegisterRoutes(r fiber.Router) {
+	r.Get("/alerts/rules", h.ListRules)
+	r.Post("/alerts/rules", h.CreateRule)
+	r.Put("/alerts/rules/:id", h.UpdateRule)
+	r.Delete("/alerts/rules/:id", h.DeleteRule)
+	r.Get("/alerts/active", h.ListActive)
+}
+
+// ListRules returns every alert rule. Admin only.
+// GET /api/alerts/rules
+func (h *AlertRuleHandler) ListRules(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	rules, err := h.engine.ListRules(c.UserContext())
+	if err != nil {
+		slog.Error("[Alerting] failed to list rules", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list alert rules",
+		})
+	}
+	return c.JSON(rules)
+}
+
+// CreateRule adds an alert rule. Admin only.
+// POST /api/alerts/rules
+func (h *AlertRuleHandler) CreateRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	var rule alerting.Rule
+	if err := c.BodyParser(&rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	rule.ID = ""
+	if err := h.saveRule(c, &rule); err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// UpdateRule replaces an alert rule. Admin only.
+// PUT /api/alerts/rules/:id
+func (h *AlertRuleHandler) UpdateRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	var rule alerting.Rule
+	if err := c.BodyParser(&rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	rule.ID = c.Params("id")
+	if err := h.saveRule(c, &rule); err != nil {
+		return err
+	}
+	return c.JSON(rule)
+}
+
+func (h *AlertRuleHandler) saveRule(c *fiber.Ctx, rule *alerting.Rule) error {
+	if err := rule.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := h.engine.SaveRule(c.UserContext(), rule); err != nil {
+		if errors.Is(err, alerting.ErrRuleNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Alert rule not found")
+		}
+		slog.Error("[Alerting] failed to save rule", "rule", rule.ID, "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save alert rule")
+	}
+	audit.Log(c, audit.ActionSaveAlertRule, "alert_rule", rule.ID)
+	return nil
+}
+
+// DeleteRule removes an alert rule, resolving its firing alerts. Admin only.
+// DELETE /api/alerts/rules/:id
+func (h *AlertRuleHandler) DeleteRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	id := c.Params("id")
+	if err := h.engine.DeleteRule(c.UserContext(), id); err != nil {
+		if errors.Is(err, alerting.ErrRuleNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Alert rule not found")
+		}
+		slog.Error("[Alerting] failed to delete rule", "rule", id, "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete alert rule")
+	}
+	audit.Log(c, audit.ActionDeleteAlertRule, "alert_rule", id)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListActive returns the pending and firing alerts raised by server-side
+// rules.
+// GET /api/alerts/active
+func (h *AlertRuleHandler) ListActive(c *fiber.Ctx) error {
+	active, err := h.engine.ActiveAlerts(c.UserContext())
+	if err != nil {
+		slog.Error("[Alerting] failed to list active alerts", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list active alerts",
+		})
+	}
+	return c.JSON(active)
+}
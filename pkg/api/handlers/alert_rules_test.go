@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/alerting"
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+	"github.com/kubestellar/console/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type noClusters struct{ alerting.Source }
+
+func (noClusters) Clusters(context.Context) ([]alerting.ClusterStatus, error) { return nil, nil }
+
+func TestAlertRuleHandler(t *testing.T) {
+	env := setupTestEnv(t)
+	mockStore := env.Store.(*test.MockStore)
+	engine := alerting.NewEngine(env.Store, noClusters{}, notifications.NewService(), 0)
+	NewAlertRuleHandler(engine, env.Store).RegisterRoutes(env.App)
+
+	req := httptest.NewRequest("POST", "/alerts/rules", strings.NewReader(`{"name":"x","condition":{"type":"dns_failure"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 400, resp.StatusCode, "browser-only condition types are rejected")
+
+	var saved []byte
+	mockStore.On("SaveAlertRule", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]byte) }).
+		Return(nil).Once()
+	req = httptest.NewRequest("POST", "/alerts/rules", strings.NewReader(`{"name":"Crash loops","enabled":true,"condition":{"type":"pod_crash"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp2, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp2.Body.Close() })
+	assert.Equal(t, 201, resp2.StatusCode)
+	var rule alerting.Rule
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&rule))
+	assert.NotEmpty(t, rule.ID)
+	assert.Equal(t, notifications.SeverityWarning, rule.Severity)
+	require.NotNil(t, rule.Condition.Threshold)
+	assert.Equal(t, 5.0, *rule.Condition.Threshold)
+
+	mockStore.On("ListAlertRules").Return([][]byte{saved}, nil)
+	req = httptest.NewRequest("GET", "/alerts/rules", nil)
+	resp3, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp3.Body.Close() })
+	var rules []alerting.Rule
+	require.NoError(t, json.NewDecoder(resp3.Body).Decode(&rules))
+	require.Len(t, rules, 1)
+	assert.Equal(t, rule.ID, rules[0].ID)
+
+	req = httptest.NewRequest("DELETE", "/alerts/rules/missing", nil)
+	resp4, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp4.Body.Close() })
+	assert.Equal(t, 404, resp4.StatusCode)
+
+	mockStore.On("ListAlertStates").Return([]store.AlertState{{
+		RuleID: rule.ID, Fingerprint: "prod/default/api", Status: alerting.StatusFiring,
+		Alert: []byte(`{"id":"a1","ruleName":"Crash loops","cluster":"prod"}`),
+	}}, nil)
+	req = httptest.NewRequest("GET", "/alerts/active", nil)
+	resp5, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp5.Body.Close() })
+	var active []alerting.ActiveAlert
+	require.NoError(t, json.NewDecoder(resp5.Body).Decode(&active))
+	require.Len(t, active, 1)
+	assert.Equal(t, "prod", active[0].Cluster)
+	assert.Equal(t, alerting.StatusFiring, active[0].Status)
+}
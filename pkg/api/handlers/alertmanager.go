@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// alertmanagerMaxBodyBytes caps a webhook payload. Alertmanager batches a
+// whole alert group into one POST, so this is larger than the GitHub cap.
+const alertmanagerMaxBodyBytes = 4 << 20 // 4 MB
+
+// AlertmanagerStellarStore is the slice of the Stellar store the receiver
+// writes feed entries through.
+type AlertmanagerStellarStore interface {
+	CreateStellarNotification(ctx context.Context, notification *store.StellarNotification) error
+	NotificationExistsByDedup(ctx context.Context, userID, dedupeKey string) (bool, error)
+}
+
+// AlertmanagerHandler receives Alertmanager webhook notifications and feeds
+// them into the notification queue and the Stellar event feed.
+type AlertmanagerHandler struct {
+	token       string
+	service     *notifications.Service
+	stellar     AlertmanagerStellarStore
+	broadcaster SSEBroadcaster
+}
+
+// NewAlertmanagerHandler creates the receiver. Requests must carry token as
+// a bearer token; an empty token disables the endpoint. stellar and
+// broadcaster may be nil, in which case alerts only go to notifiers.
+func NewAlertmanagerHandler(token string, service *notifications.Service, stellar AlertmanagerStellarStore, broadcaster SSEBroadcaster) *AlertmanagerHandler {
+	return &AlertmanagerHandler{
+		token:       token,
+		service:     service,
+		stellar:     stellar,
+		broadcaster: broadcaster,
+	}
+}
+
+// Receive ingests an Alertmanager v4 webhook payload. Alerts are attributed
+// to the cluster in their labels, or to ?cluster= for Alertmanagers without
+// a cluster external label. Alertmanager re-sends firing alerts on every
+// group interval; repeats are deduplicated by fingerprint, so any failure
+// is reported as a 5xx and the whole batch can safely be retried.
+// POST /webhooks/alertmanager
+func (h *AlertmanagerHandler) Receive(c *fiber.Ctx) error {
+	if h.token == "" {
+		slog.Info("[Alertmanager] Rejected: ALERTMANAGER_WEBHOOK_TOKEN not configured")
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Alertmanager receiver not configured")
+	}
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid Alertmanager token")
+	}
+	if len(c.Body()) > alertmanagerMaxBodyBytes {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, "Alertmanager payload too large")
+	}
+
+	var payload notifications.AlertmanagerPayload
+	if err := json.Unmarshal(c.Body(), &payload); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
+	}
+	if err := payload.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.UserContext()
+	alerts := payload.ToAlerts(strings.TrimSpace(c.Query("cluster")))
+	failed := 0
+	for _, alert := range alerts {
+		if err := h.service.SendAlert(alert); err != nil {
+			slog.Error("[Alertmanager] failed to route alert", "alert", alert.ID, "error", err)
+			failed++
+			continue
+		}
+		if err := h.recordStellarEvent(ctx, alert); err != nil {
+			slog.Error("[Alertmanager] failed to record Stellar event", "alert", alert.ID, "error", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("Failed to ingest %d of %d alerts", failed, len(alerts)))
+	}
+	slog.Info("[Alertmanager] ingested alerts", "receiver", payload.Receiver, "status", payload.Status, "count", len(alerts))
+	return c.JSON(fiber.Map{"received": len(alerts)})
+}
+
+// recordStellarEvent adds the alert to the Stellar feed as an event, which
+// is what the Stellar observer scans for auto-watches and nudges. The dedupe
+// key follows the "ev:cluster:namespace:name:..." layout of Kubernetes
+// events and is unique per firing, so a repeat of the same firing is a no-op
+// while a re-fire after resolution shows up again.
+func (h *AlertmanagerHandler) recordStellarEvent(ctx context.Context, alert notifications.Alert) error {
+	if h.stellar == nil {
+		return nil
+	}
+	pod := ""
+	if alert.ResourceKind == "Pod" {
+		pod = alert.Resource
+	}
+	dedupKey := fmt.Sprintf("am:%s:%s:%s:%s:%d",
+		alert.Cluster, alert.Namespace, pod, notifications.Fingerprint(alert), alert.FiredAt.Unix())
+
+	title := alert.RuleName
+	if pod != "" {
+		title = fmt.Sprintf("%s — %s/%s", alert.RuleName, alert.Namespace, pod)
+	}
+	severity := string(alert.Severity)
+	if alert.Status == notifications.AlertStatusResolved {
+		dedupKey += ":resolved"
+		title = "Resolved: " + title
+		severity = string(notifications.SeverityInfo)
+	}
+
+	exists, err := h.stellar.NotificationExistsByDedup(ctx, "system", dedupKey)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	notif := &store.StellarNotification{
+		UserID:    "system",
+		Type:      "event",
+		Severity:  severity,
+		Title:     title,
+		Body:      alert.Message,
+		Cluster:   alert.Cluster,
+		Namespace: alert.Namespace,
+		DedupeKey: dedupKey,
+	}
+	if err := h.stellar.CreateStellarNotification(ctx, notif); err != nil {
+		return err
+	}
+	if h.broadcaster != nil {
+		h.broadcaster.Broadcast(SSEEvent{Type: "notification", Data: notif})
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const alertmanagerTestPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"KubePodCrashLooping\"}",
+  "status": "firing",
+  "receiver": "console",
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [{
+    "status": "firing",
+    "labels": {"alertname": "KubePodCrashLooping", "severity": "warning", "namespace": "shop", "pod": "api-1"},
+    "annotations": {"summary": "api-1 is crash looping"},
+    "startsAt": "2026-03-01T12:00:00Z",
+    "endsAt": "0001-01-01T00:00:00Z",
+    "fingerprint": "abc123"
+  }]
+}`
+
+func TestAlertmanagerReceiver(t *testing.T) {
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "am.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	svc := notifications.NewService()
+	svc.EnableQueue(db, notifications.QueueOptions{})
+	svc.RegisterWebhookNotifier("ops", "https://hooks.example.com/ops")
+
+	app := fiber.New()
+	app.Post("/webhooks/alertmanager", NewAlertmanagerHandler("s3cret", svc, db, nil).Receive)
+	post := func(token, body string) int {
+		req := httptest.NewRequest("POST", "/webhooks/alertmanager?cluster=prod", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 401, post("", alertmanagerTestPayload))
+	assert.Equal(t, 401, post("wrong", alertmanagerTestPayload))
+	assert.Equal(t, 400, post("s3cret", `{"version":"3","alerts":[]}`))
+
+	// Alertmanager repeats firing alerts; the second post must not add anything.
+	assert.Equal(t, 200, post("s3cret", alertmanagerTestPayload))
+	assert.Equal(t, 200, post("s3cret", alertmanagerTestPayload))
+
+	ctx := context.Background()
+	deliveries, err := svc.Queue().Deliveries(ctx, notifications.DeliveryPending, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Len(t, deliveries[0].Alerts, 1)
+	assert.Equal(t, "am:prod:abc123", deliveries[0].Alerts[0].ID)
+	assert.Equal(t, "prod", deliveries[0].Alerts[0].Cluster)
+
+	feed, err := db.ListStellarNotifications(ctx, "system", 10, false)
+	require.NoError(t, err)
+	require.Len(t, feed, 1)
+	assert.Equal(t, "event", feed[0].Type)
+	assert.Equal(t, "warning", feed[0].Severity)
+	assert.Equal(t, "KubePodCrashLooping — shop/api-1", feed[0].Title)
+	assert.Equal(t, "prod", feed[0].Cluster)
+
+	resolved := strings.ReplaceAll(alertmanagerTestPayload, `"status": "firing"`, `"status": "resolved"`)
+	assert.Equal(t, 200, post("s3cret", resolved))
+	feed, err = db.ListStellarNotifications(ctx, "system", 10, false)
+	require.NoError(t, err)
+	require.Len(t, feed, 2)
+}
+
+func TestAlertmanagerReceiver_Disabled(t *testing.T) {
+	app := fiber.New()
+	app.Post("/webhooks/alertmanager", NewAlertmanagerHandler("", notifications.NewService(), nil, nil).Receive)
+	req := httptest.NewRequest("POST", "/webhooks/alertmanager", strings.NewReader(alertmanagerTestPayload))
+	req.Header.Set("Authorization", "Bearer ")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 503, resp.StatusCode)
+}
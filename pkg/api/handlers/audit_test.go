@@ -6,11 +6,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kubestellar/console/pkg/api/audit"
 	"github.com/kubestellar/console/pkg/models"
 	"github.com/kubestellar/console/pkg/store"
 	"github.com/kubestellar/console/pkg/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetAuditLog(t *testing.T) {
@@ -108,3 +110,40 @@ func TestGetAuditLog(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
 }
+
+func TestVerifyAuditChain(t *testing.T) {
+	first := store.AuditEntry{ID: 1, Timestamp: "2026-05-01T10:00:00Z", UserID: "u1", Action: "update_role"}
+	first.Hash = store.AuditEntryHash(first)
+	second := store.AuditEntry{ID: 2, Timestamp: "2026-05-01T10:05:00Z", UserID: "u1", Action: "delete_user", PrevHash: first.Hash}
+	second.Hash = store.AuditEntryHash(second)
+
+	verify := func(t *testing.T, entries []store.AuditEntry) audit.ChainReport {
+		env := setupTestEnv(t)
+		handler := NewAuditHandler(env.Store)
+		env.App.Get("/api/audit/verify", handler.VerifyAuditChain)
+		env.Store.(*test.MockStore).On("AuditLegacyCutoff").Return(int64(0), nil)
+		env.Store.(*test.MockStore).On("ListAuditCheckpoints").Return([]store.AuditCheckpoint{}, nil)
+		env.Store.(*test.MockStore).On("ListAuditChain", int64(0), mock.Anything).Return(entries, nil)
+
+		resp, err := env.App.Test(httptest.NewRequest("GET", "/api/audit/verify", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var report audit.ChainReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return report
+	}
+
+	t.Run("Intact", func(t *testing.T) {
+		report := verify(t, []store.AuditEntry{first, second})
+		assert.True(t, report.Valid)
+		assert.Equal(t, 2, report.VerifiedRecords)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := first
+		tampered.UserID = "someone-else"
+		report := verify(t, []store.AuditEntry{tampered, second})
+		assert.False(t, report.Valid)
+		assert.Equal(t, int64(1), report.BrokenAtID)
+	})
+}
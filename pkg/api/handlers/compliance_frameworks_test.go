@@ -142,6 +142,9 @@ func (m *handlerMockProber) EncryptionAtRestEnabled(_ context.Context, _ string)
 func (m *handlerMockProber) ImageVulnerabilities(_ context.Context, _ string) (int, int, int, error) {
 	return 10, 0, 0, nil
 }
+func (m *handlerMockProber) ImageProvenance(_ context.Context, _ string) (int, int, int, error) {
+	return 10, 10, 10, nil
+}
 func (m *handlerMockProber) ClusterAdminBindings(_ context.Context, _ string) (int, error) {
 	return 0, nil
 }
@@ -206,6 +209,9 @@ func (m *errorProber) EncryptionAtRestEnabled(_ context.Context, _ string) (bool
 func (m *errorProber) ImageVulnerabilities(_ context.Context, _ string) (int, int, int, error) {
 	return 0, 0, 0, errors.New("fail")
 }
+func (m *errorProber) ImageProvenance(_ context.Context, _ string) (int, int, int, error) {
+	return 0, 0, 0, errors.New("fail")
+}
 func (m *errorProber) ClusterAdminBindings(_ context.Context, _ string) (int, error) {
 	return 0, errors.New("fail")
 }
@@ -38,6 +38,20 @@ type ConsolePersistenceHandlers struct {
 	// deployer is used by reconcileDeployment. When nil, k8sClient is used.
 	// Tests can inject a fake to exercise per-cluster failure paths.
 	deployer workloadDeployer
+	// rolloutClient drives progressive (RollingUpdate/Canary) rollouts.
+	// When nil, k8sClient is used. Tests inject fake dynamic clients here.
+	rolloutClient k8s.RolloutClient
+
+	// reconciles tracks the in-flight reconcile per WorkloadDeployment so a
+	// newer generation cancels the rollout it supersedes.
+	reconcilesMu sync.Mutex
+	reconciles   map[string]*reconcileHandle
+}
+
+// reconcileHandle identifies one in-flight reconcile so it can be cancelled
+// by, and told apart from, the reconcile that supersedes it.
+type reconcileHandle struct {
+	cancel context.CancelFunc
 }
 
 // NewConsolePersistenceHandlers creates a new console persistence handlers instance
@@ -187,10 +201,12 @@ func (h *ConsolePersistenceHandlers) handleResourceEvent(event k8s.ConsoleResour
 		h.hub.BroadcastAll(msg)
 	}
 
-	// Trigger reconciliation on newly observed WorkloadDeployment CRs.
-	// Only act on ADDED events — MODIFIED covers status updates from the
-	// reconciler itself and would cause reconcile loops, DELETED is a no-op.
-	if event.Type != "ADDED" || event.ResourceType != "WorkloadDeployment" {
+	// Trigger reconciliation on newly observed WorkloadDeployment CRs and on
+	// spec changes (suspend/resume, autoPromote, new targets). MODIFIED events
+	// whose generation was already observed are the reconciler's own status
+	// writes — the status subresource does not bump metadata.generation — so
+	// they are ignored to avoid reconcile loops. DELETED is a no-op.
+	if event.ResourceType != "WorkloadDeployment" || (event.Type != "ADDED" && event.Type != "MODIFIED") {
 		return
 	}
 	wd, ok := event.Resource.(*v1alpha1.WorkloadDeployment)
@@ -199,14 +215,49 @@ func (h *ConsolePersistenceHandlers) handleResourceEvent(event k8s.ConsoleResour
 			"type", event.ResourceType, "name", event.Name)
 		return
 	}
+	if event.Type == "MODIFIED" && (wd.Generation == 0 || wd.Generation == wd.Status.ObservedGeneration) {
+		return
+	}
+	// The informer replays ADDED for every existing CR when the watcher
+	// (re)starts. A rollout that already reached a terminal phase for the
+	// current generation must not be redeployed on every console restart.
+	if event.Type == "ADDED" && wd.Generation != 0 && wd.Generation == wd.Status.ObservedGeneration &&
+		(wd.Status.Phase == k8s.RolloutPhaseComplete || wd.Status.Phase == k8s.RolloutPhaseFailed) {
+		return
+	}
 	// Use a detached context with a wall-clock bound so reconciliation
 	// survives independently of the watcher's event dispatch goroutine and
 	// cannot run forever. 5 minutes matches the prior CreateWorkloadDeployment
-	// detached timeout.
+	// detached timeout; progressive strategies pause between clusters and
+	// wait on health checks, so they get a longer bound.
 	const reconcileTimeout = 5 * time.Minute
-	reconcileCtx, reconcileCancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	const progressiveReconcileTimeout = 6 * time.Hour
+	timeout := reconcileTimeout
+	if k8s.IsProgressiveStrategy(wd.Spec.Strategy) {
+		timeout = progressiveReconcileTimeout
+	}
+	reconcileCtx, reconcileCancel := context.WithTimeout(context.Background(), timeout)
+	key := wd.Namespace + "/" + wd.Name
+	handle := &reconcileHandle{cancel: reconcileCancel}
+	h.reconcilesMu.Lock()
+	if h.reconciles == nil {
+		h.reconciles = make(map[string]*reconcileHandle)
+	}
+	if prev, ok := h.reconciles[key]; ok {
+		prev.cancel()
+	}
+	h.reconciles[key] = handle
+	h.reconcilesMu.Unlock()
 	safego.Go(func() {
-		defer reconcileCancel()
+		defer func() {
+			reconcileCancel()
+			h.reconcilesMu.Lock()
+			// Only clear our own entry; a newer reconcile may have replaced it.
+			if h.reconciles[key] == handle {
+				delete(h.reconciles, key)
+			}
+			h.reconcilesMu.Unlock()
+		}()
 		h.reconcileDeployment(reconcileCtx, wd)
 	})
 }
@@ -635,8 +686,18 @@ func (h *ConsolePersistenceHandlers) reconcileDeployment(ctx context.Context, wd
 		wd.ResourceVersion = updated.ResourceVersion
 	}
 
+	// A rollout that was Paused (suspended or awaiting canary promotion) or
+	// interrupted mid-flight resumes rather than re-applying clusters that
+	// already completed.
+	resume := wd.Status.Phase == k8s.RolloutPhasePaused || wd.Status.Phase == k8s.RolloutPhaseInProgress
+
 	// Transition to InProgress
 	wd.Status.Phase = "InProgress"
+	wd.Status.ObservedGeneration = wd.Generation
+	if !resume || wd.Status.StartedAt == nil {
+		now := metav1.Now()
+		wd.Status.StartedAt = &now
+	}
 	updateStatus(wd)
 
 	// ---- Step 1: Resolve the referenced ManagedWorkload ----
@@ -661,6 +722,14 @@ func (h *ConsolePersistenceHandlers) reconcileDeployment(ctx context.Context, wd
 		return
 	}
 
+	// Progressive strategies, dry runs and suspended deployments are driven
+	// step by step by the rollout controller; everything else is applied to
+	// all targets at once below.
+	if k8s.IsProgressiveStrategy(wd.Spec.Strategy) || wd.Spec.DryRun || wd.Spec.Suspend {
+		h.runRollout(ctx, wd, workload, targets, resume, updateStatus)
+		return
+	}
+
 	// Initialize per-cluster statuses
 	wd.Status.ClusterStatuses = make([]v1alpha1.ClusterRolloutStatus, len(targets))
 	for i, cluster := range targets {
@@ -783,6 +852,35 @@ func (h *ConsolePersistenceHandlers) reconcileDeployment(ctx context.Context, wd
 	}
 }
 
+// runRollout hands a resolved WorkloadDeployment to the rollout controller.
+func (h *ConsolePersistenceHandlers) runRollout(
+	ctx context.Context,
+	wd *v1alpha1.WorkloadDeployment,
+	workload *v1alpha1.ManagedWorkload,
+	targets []string,
+	resume bool,
+	updateFn func(*v1alpha1.WorkloadDeployment),
+) {
+	client := h.rolloutClient
+	if client == nil && h.k8sClient != nil {
+		client = h.k8sClient
+	}
+	controller := &k8s.RolloutController{
+		Client: client,
+		UpdateStatus: func(_ context.Context, wd *v1alpha1.WorkloadDeployment) {
+			updateFn(wd)
+		},
+		Refresh: func(ctx context.Context) (*v1alpha1.WorkloadDeployment, error) {
+			pc, _, err := h.persistenceStore.GetActiveClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return k8s.NewConsolePersistence(pc).GetWorkloadDeployment(ctx, wd.Namespace, wd.Name)
+		},
+	}
+	controller.Run(ctx, wd, workload, targets, resume)
+}
+
 // setTerminalStatus sets the deployment to a terminal phase (Complete/Failed),
 // records a completion timestamp and a history entry, then persists the status.
 func (h *ConsolePersistenceHandlers) setTerminalStatus(
@@ -6,6 +6,7 @@ import (
 	"github.com/kubestellar/console/pkg/api/v1alpha1"
 	"github.com/kubestellar/console/pkg/k8s"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // newTestHandler returns a minimal ConsolePersistenceHandlers for unit tests
@@ -225,3 +226,30 @@ func TestSetTerminalStatus_SanitizedMessage(t *testing.T) {
 		})
 	}
 }
+
+// TestHandleResourceEvent_ReplayedCompletedDeploymentIsSkipped covers the
+// informer replaying ADDED for a finished CR after a console restart: the
+// rollout already completed for this generation, so no reconcile may start.
+func TestHandleResourceEvent_ReplayedCompletedDeploymentIsSkipped(t *testing.T) {
+	for _, phase := range []string{k8s.RolloutPhaseComplete, k8s.RolloutPhaseFailed} {
+		t.Run(phase, func(t *testing.T) {
+			h := newTestHandler()
+			wd := &v1alpha1.WorkloadDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "console", Generation: 3},
+				Status:     v1alpha1.WorkloadDeploymentStatus{Phase: phase, ObservedGeneration: 3},
+			}
+
+			h.handleResourceEvent(k8s.ConsoleResourceEvent{
+				Type:         "ADDED",
+				ResourceType: "WorkloadDeployment",
+				Name:         wd.Name,
+				Namespace:    wd.Namespace,
+				Resource:     wd,
+			})
+
+			h.reconcilesMu.Lock()
+			defer h.reconcilesMu.Unlock()
+			assert.Empty(t, h.reconciles, "replayed terminal deployment must not start a rollout")
+		})
+	}
+}
@@ -59,6 +59,11 @@ type GPUHandler struct {
 	store           store.Store
 	clusterCapacity ClusterCapacityProvider
 	k8sClient       gpuProvisioningClient
+	// now is the lifecycle clock; tests override it.
+	now func() time.Time
+	// wake nudges RunLifecycle to sweep early, e.g. after a cancellation
+	// frees capacity a waitlisted reservation could use.
+	wake chan struct{}
 }
 
 // NewGPUHandler creates a new GPU handler.
@@ -67,7 +72,13 @@ type GPUHandler struct {
 // k8sClient enables synchronous namespace+quota provisioning; if nil,
 // reservations are created with "pending" status (no cluster access).
 func NewGPUHandler(s store.Store, capacityProvider ClusterCapacityProvider, k8sClient gpuProvisioningClient) *GPUHandler {
-	return &GPUHandler{store: s, clusterCapacity: capacityProvider, k8sClient: k8sClient}
+	return &GPUHandler{
+		store:           s,
+		clusterCapacity: capacityProvider,
+		k8sClient:       k8sClient,
+		now:             time.Now,
+		wake:            make(chan struct{}, 1),
+	}
 }
 
 // CreateReservation creates a new GPU reservation
@@ -105,29 +116,11 @@ func (h *GPUHandler) CreateReservation(c *fiber.Ctx) error {
 	if input.StartDate == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Start date is required")
 	}
-	if _, err := time.Parse(time.RFC3339, input.StartDate); err != nil {
+	startTime, err := time.Parse(time.RFC3339, input.StartDate)
+	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Start date must be RFC 3339 format (e.g. 2024-01-15T09:00:00Z)")
 	}
 
-	// #6612: resolve server-side capacity up front so we can pass it into
-	// the atomic CreateGPUReservationWithCapacity call below. The old
-	// two-step flow (checkOverAllocation THEN CreateGPUReservation) was
-	// TOCTOU-racy: two concurrent requests could both read the same stale
-	// reserved total, both pass the check, and both insert — pushing the
-	// cluster above its declared capacity. Passing the capacity into a
-	// single SQL statement makes the check+insert atomic.
-	capacity := 0
-	if h.clusterCapacity != nil {
-		capacity = h.clusterCapacity(c.Context(), input.Cluster)
-	}
-	// A pre-check is still useful when capacity > 0 so clients get a
-	// descriptive 409 message with available/reserved numbers, but the
-	// authoritative decision is made inside the transaction below.
-	// Pass the already-resolved capacity so we don't re-fetch it (#6958).
-	if err := h.checkOverAllocationWithCapacity(c.Context(), input.Cluster, input.GPUCount, nil, capacity); err != nil {
-		return err
-	}
-
 	// Get user info for user_name
 	user, err := h.store.GetUser(c.UserContext(), userID)
 	if err != nil || user == nil {
@@ -148,6 +141,7 @@ func (h *GPUHandler) CreateReservation(c *fiber.Ctx) error {
 		StartDate:     input.StartDate,
 		DurationHours: input.DurationHours,
 		Notes:         input.Notes,
+		Status:        models.ReservationStatusActive,
 		QuotaName:     input.QuotaName,
 		QuotaEnforced: input.QuotaEnforced,
 	}
@@ -156,13 +150,40 @@ func (h *GPUHandler) CreateReservation(c *fiber.Ctx) error {
 	// both, neither) uniformly. Called here so validation and capacity
 	// checks see the canonical shape before the store call below.
 	reservation.NormalizeGPUTypes()
+	// A window that has not started yet only books capacity; the lifecycle
+	// worker provisions it at the start time (see RunLifecycle).
+	if startTime.After(h.now()) {
+		reservation.Status = models.ReservationStatusScheduled
+	}
+
+	// #6612: resolve server-side capacity up front so we can pass it into
+	// the atomic CreateGPUReservationWithCapacity call below. The old
+	// two-step flow (checkOverAllocation THEN CreateGPUReservation) was
+	// TOCTOU-racy: two concurrent requests could both read the same stale
+	// reserved total, both pass the check, and both insert — pushing the
+	// cluster above its declared capacity. Passing the capacity into a
+	// single transaction makes the check+insert atomic.
+	capacity := 0
+	if h.clusterCapacity != nil {
+		capacity = h.clusterCapacity(c.Context(), input.Cluster)
+	}
+	// A pre-check is still useful when capacity > 0 so a request that
+	// cannot fit is rejected (or waitlisted) before anything is provisioned,
+	// but the authoritative decision is made inside the transaction below.
+	// Pass the already-resolved capacity so we don't re-fetch it (#6958).
+	if err := h.checkOverAllocationWithCapacity(c.Context(), reservation, nil, capacity); err != nil {
+		if input.Waitlist && isGPUCapacityConflict(err) {
+			return h.createWaitlisted(c, reservation)
+		}
+		return err
+	}
 
 	// Synchronous provisioning: create namespace + ResourceQuota on the
 	// target cluster before persisting the reservation. This eliminates
 	// the "pending" state — the caller gets either "active" (provisioned)
 	// or an immediate error.
 	provisioned := false
-	if h.k8sClient != nil {
+	if h.k8sClient != nil && reservation.Status == models.ReservationStatusActive {
 		if provErr := h.provisionOnCluster(c.Context(), reservation); provErr != nil {
 			slog.Error("[gpu] synchronous provisioning failed",
 				"cluster", reservation.Cluster,
@@ -171,7 +192,6 @@ func (h *GPUHandler) CreateReservation(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusServiceUnavailable,
 				"failed to provision cluster resources")
 		}
-		reservation.Status = models.ReservationStatusActive
 		provisioned = true
 	}
 
@@ -180,6 +200,9 @@ func (h *GPUHandler) CreateReservation(c *fiber.Ctx) error {
 			h.cleanupProvisionedResources(c.Context(), reservation)
 		}
 		if errors.Is(err, store.ErrGPUQuotaExceeded) {
+			if input.Waitlist {
+				return h.createWaitlisted(c, reservation)
+			}
 			return fiber.NewError(fiber.StatusConflict,
 				"requested GPUs exceed available capacity")
 		}
@@ -193,6 +216,27 @@ func (h *GPUHandler) CreateReservation(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(reservation)
 }
 
+// createWaitlisted stores a reservation that did not fit its window as
+// waitlisted. It holds no capacity and provisions nothing until the
+// lifecycle worker promotes it. Responds 202 Accepted.
+func (h *GPUHandler) createWaitlisted(c *fiber.Ctx, r *models.GPUReservation) error {
+	r.Status = models.ReservationStatusWaitlisted
+	r.QuotaEnforced = false
+	if err := h.store.CreateGPUReservation(c.UserContext(), r); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create reservation")
+	}
+	audit.Log(c, audit.ActionCreateGPUReservation, "gpu_reservation", r.ID.String(),
+		fmt.Sprintf("cluster=%s namespace=%s gpus=%d status=%s", r.Cluster, r.Namespace, r.GPUCount, r.Status))
+	return c.Status(fiber.StatusAccepted).JSON(r)
+}
+
+// isGPUCapacityConflict reports whether err is the 409 returned when a
+// reservation does not fit its cluster's capacity.
+func isGPUCapacityConflict(err error) bool {
+	var fe *fiber.Error
+	return errors.As(err, &fe) && fe.Code == fiber.StatusConflict
+}
+
 // getCallerUser looks up the calling user and returns it. Returns nil + error
 // response if the user cannot be resolved.
 func (h *GPUHandler) getCallerUser(c *fiber.Ctx) (*models.User, error) {
@@ -296,6 +340,7 @@ func (h *GPUHandler) UpdateReservation(c *fiber.Ctx) error {
 	if authErr := requireOwnerOrAdmin(c, user, existing.UserID); authErr != nil {
 		return authErr
 	}
+	previous := *existing
 
 	var input models.UpdateGPUReservationInput
 	if err := c.BodyParser(&input); err != nil {
@@ -372,7 +417,13 @@ func (h *GPUHandler) UpdateReservation(c *fiber.Ctx) error {
 		newStatus := *input.Status
 		if !newStatus.IsValid() {
 			return fiber.NewError(fiber.StatusBadRequest,
-				fmt.Sprintf("Invalid status %q; must be one of: pending, active, completed, cancelled", newStatus))
+				fmt.Sprintf("Invalid status %q; must be one of: scheduled, waitlisted, active, completed, cancelled", newStatus))
+		}
+		// Scheduling, promotion and activation follow the reservation's
+		// window and the cluster's capacity; callers may only end one.
+		if newStatus != existing.Status && !newStatus.IsTerminal() {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("Status %q is set by the reservation lifecycle; only completed or cancelled may be requested", newStatus))
 		}
 		if !existing.Status.CanTransitionTo(newStatus) {
 			return fiber.NewError(fiber.StatusBadRequest,
@@ -387,19 +438,20 @@ func (h *GPUHandler) UpdateReservation(c *fiber.Ctx) error {
 		existing.QuotaEnforced = *input.QuotaEnforced
 	}
 
-	// Re-validate capacity whenever the cluster, GPU count, or status changes —
-	// not just when GPUCount is provided (#5423). Uses atomic
+	// Re-validate capacity whenever the cluster, GPU count, status, or
+	// window changes — not just when GPUCount is provided (#5423). Uses atomic
 	// UpdateGPUReservationWithCapacity to eliminate the TOCTOU race (#6957).
 	clusterChanged := input.Cluster != nil
 	countChanged := input.GPUCount != nil
 	statusChanged := input.Status != nil
-	if clusterChanged || countChanged || statusChanged {
+	windowChanged := input.StartDate != nil || input.DurationHours != nil
+	if clusterChanged || countChanged || statusChanged || windowChanged {
 		capacity := 0
 		if h.clusterCapacity != nil {
 			capacity = h.clusterCapacity(c.Context(), existing.Cluster)
 		}
 		// Descriptive pre-check for a nicer error message.
-		if err := h.checkOverAllocationWithCapacity(c.Context(), existing.Cluster, existing.GPUCount, &existing.ID, capacity); err != nil {
+		if err := h.checkOverAllocationWithCapacity(c.Context(), existing, &existing.ID, capacity); err != nil {
 			return err
 		}
 		// Atomic capacity-checked update (#6957).
@@ -416,12 +468,14 @@ func (h *GPUHandler) UpdateReservation(c *fiber.Ctx) error {
 		// #9890: persist audit entry after successful mutation.
 		audit.Log(c, audit.ActionUpdateGPUReservation, "gpu_reservation", existing.ID.String(),
 			fmt.Sprintf("cluster=%s namespace=%s gpus=%d status=%s", existing.Cluster, existing.Namespace, existing.GPUCount, existing.Status))
+		h.applyReservationChange(c.Context(), &previous, existing)
 		return c.JSON(existing)
 	}
 
 	if err := h.store.UpdateGPUReservation(c.UserContext(), existing); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update reservation")
 	}
+	h.applyReservationChange(c.Context(), &previous, existing)
 
 	// #9890: persist audit entry after successful mutation.
 	audit.Log(c, audit.ActionUpdateGPUReservation, "gpu_reservation", existing.ID.String(),
@@ -458,6 +512,10 @@ func (h *GPUHandler) DeleteReservation(c *fiber.Ctx) error {
 	if err := h.store.DeleteGPUReservation(c.UserContext(), id); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete reservation")
 	}
+	if existing.Status == models.ReservationStatusActive && existing.QuotaEnforced {
+		h.cleanupProvisionedResources(c.Context(), existing)
+	}
+	h.wakeLifecycle()
 
 	// #9890: persist audit entry after successful mutation.
 	audit.Log(c, audit.ActionDeleteGPUReservation, "gpu_reservation", existing.ID.String(),
@@ -568,24 +626,33 @@ func (h *GPUHandler) GetBulkUtilizations(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
-// checkOverAllocationWithCapacity verifies that the requested GPU count does
-// not exceed the already-resolved cluster capacity. The capacity parameter
-// must be fetched once by the caller and reused to avoid inconsistencies from
-// multiple fetches (#6958). excludeID is used on updates to exclude the
-// current reservation from the "already reserved" tally.
-func (h *GPUHandler) checkOverAllocationWithCapacity(ctx context.Context, cluster string, gpuCount int, excludeID *uuid.UUID, capacity int) error {
-	if capacity <= 0 {
-		// No capacity data — skip the pre-check. The authoritative check
-		// is inside the atomic SQL statement.
+// checkOverAllocationWithCapacity verifies that the reservation fits the
+// already-resolved cluster capacity for its whole window: the peak GPUs held
+// by the cluster's other active and scheduled reservations at any instant
+// of the window, plus its own count, must not exceed capacity. The capacity
+// parameter must be fetched once by the caller and reused to avoid
+// inconsistencies from multiple fetches (#6958). excludeID is used on
+// updates to exclude the current reservation from the tally.
+func (h *GPUHandler) checkOverAllocationWithCapacity(ctx context.Context, r *models.GPUReservation, excludeID *uuid.UUID, capacity int) error {
+	if capacity <= 0 || !r.Status.HoldsCapacity() {
+		// No capacity data, or a waitlisted/ended reservation that holds
+		// none — skip the pre-check. The authoritative check is inside
+		// the store transaction.
 		return nil
 	}
 
-	reserved, err := h.store.GetClusterReservedGPUCount(ctx, cluster, excludeID)
+	var reserved int
+	var err error
+	if start, end, ok := r.Window(); ok {
+		reserved, err = h.store.GetClusterPeakReservedGPUs(ctx, r.Cluster, start, end, excludeID)
+	} else {
+		reserved, err = h.store.GetClusterReservedGPUCount(ctx, r.Cluster, excludeID)
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to check cluster GPU usage")
 	}
 
-	if reserved+gpuCount > capacity {
+	if reserved+r.GPUCount > capacity {
 		return fiber.NewError(fiber.StatusConflict,
 			"requested GPUs exceed available capacity")
 	}
@@ -593,6 +660,29 @@ func (h *GPUHandler) checkOverAllocationWithCapacity(ctx context.Context, cluste
 	return nil
 }
 
+// applyReservationChange brings the cluster in line with an update that
+// has been stored: an active reservation that ended loses its quota, and a
+// resized one gets its quota's hard limit updated. Any change that could
+// free capacity wakes the lifecycle worker to promote the waitlist.
+func (h *GPUHandler) applyReservationChange(ctx context.Context, before, after *models.GPUReservation) {
+	if before.Status == models.ReservationStatusActive && before.QuotaEnforced && h.k8sClient != nil {
+		switch {
+		case after.Status.IsTerminal():
+			h.cleanupProvisionedResources(ctx, before)
+		case after.Status == models.ReservationStatusActive && after.GPUCount != before.GPUCount:
+			if err := h.provisionOnCluster(ctx, after); err != nil {
+				slog.Warn("[gpu] failed to resize ResourceQuota",
+					"reservation", after.ID, "cluster", after.Cluster, "error", err)
+			}
+		}
+	}
+	if after.Status.IsTerminal() || after.GPUCount < before.GPUCount ||
+		after.Cluster != before.Cluster || after.StartDate != before.StartDate ||
+		after.DurationHours != before.DurationHours {
+		h.wakeLifecycle()
+	}
+}
+
 // provisionOnCluster creates the namespace (if it doesn't already exist) and a
 // ResourceQuota enforcing the GPU limit on the target cluster. This runs
 // synchronously during reservation creation so the caller gets an immediate
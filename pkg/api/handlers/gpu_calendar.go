@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/models"
+)
+
+// gpuCalendarDefaultSpan is the window the capacity calendar covers when
+// the caller gives no "to".
+const gpuCalendarDefaultSpan = 14 * 24 * time.Hour
+
+// gpuCalendarMaxSpan caps the window one calendar request may cover.
+const gpuCalendarMaxSpan = 90 * 24 * time.Hour
+
+// gpuCapacityCalendar is the GetCapacityCalendar response.
+type gpuCapacityCalendar struct {
+	Cluster string `json:"cluster"`
+	// Capacity is the cluster's GPU total, or 0 when unknown.
+	Capacity     int                         `json:"capacity"`
+	From         time.Time                   `json:"from"`
+	To           time.Time                   `json:"to"`
+	Segments     []models.GPUCapacitySegment `json:"segments"`
+	PeakReserved int                         `json:"peak_reserved"`
+	Reservations []models.GPUReservation     `json:"reservations"`
+	Waitlist     []models.GPUReservation     `json:"waitlist"`
+}
+
+// GetCapacityCalendar reports how a cluster's GPUs are booked over a time
+// window: the GPUs reserved and still available in each stretch between
+// reservation boundaries, the active and scheduled reservations overlapping
+// the window, and the waitlist in promotion order.
+//
+// Query parameters:
+//   - cluster: the cluster (required)
+//   - from: RFC 3339 start of the window (default: now)
+//   - to: RFC 3339 end of the window (default: from + 14 days, max 90 days)
+func (h *GPUHandler) GetCapacityCalendar(c *fiber.Ctx) error {
+	if _, uerr := h.getCallerUser(c); uerr != nil {
+		return uerr
+	}
+
+	cluster := c.Query("cluster")
+	if cluster == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Cluster is required")
+	}
+	if err := mcpValidateName("cluster", cluster); err != nil {
+		return err
+	}
+
+	from := h.now().UTC()
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "from must be RFC 3339 format (e.g. 2024-01-15T09:00:00Z)")
+		}
+		from = t
+	}
+	to := from.Add(gpuCalendarDefaultSpan)
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "to must be RFC 3339 format (e.g. 2024-01-29T09:00:00Z)")
+		}
+		to = t
+	}
+	if !from.Before(to) {
+		return fiber.NewError(fiber.StatusBadRequest, "from must be before to")
+	}
+	if to.Sub(from) > gpuCalendarMaxSpan {
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("window must not exceed %d days", int(gpuCalendarMaxSpan/(24*time.Hour))))
+	}
+
+	reservations, err := h.store.ListClusterGPUReservations(c.UserContext(), cluster, []models.ReservationStatus{
+		models.ReservationStatusActive,
+		models.ReservationStatusScheduled,
+		models.ReservationStatusWaitlisted,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list reservations")
+	}
+
+	capacity := 0
+	if h.clusterCapacity != nil {
+		capacity = h.clusterCapacity(c.Context(), cluster)
+	}
+
+	resp := gpuCapacityCalendar{
+		Cluster:      cluster,
+		Capacity:     capacity,
+		From:         from,
+		To:           to,
+		Segments:     models.GPUCapacitySegments(reservations, from, to, uuid.Nil, capacity),
+		PeakReserved: models.PeakReservedGPUs(reservations, from, to, uuid.Nil),
+		Reservations: []models.GPUReservation{},
+		Waitlist:     []models.GPUReservation{},
+	}
+	for _, r := range reservations {
+		switch {
+		case r.Status == models.ReservationStatusWaitlisted:
+			resp.Waitlist = append(resp.Waitlist, r)
+		case r.Overlaps(from, to):
+			resp.Reservations = append(resp.Reservations, r)
+		}
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kubestellar/console/pkg/chargeback"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// chargebackMaxSpan caps the date range of one chargeback report.
+const chargebackMaxSpan = 366 * 24 * time.Hour
+
+// chargebackDateLayout is the date-only form accepted for from/to.
+const chargebackDateLayout = "2006-01-02"
+
+// GPUChargebackHandler serves GPU chargeback and showback reports.
+type GPUChargebackHandler struct {
+	store        store.Store
+	cfg          *chargeback.Config
+	maxSampleGap time.Duration
+	now          func() time.Time
+}
+
+// NewGPUChargebackHandler creates a chargeback handler. cfg may be nil, in
+// which case reports carry GPU-hours but no cost and no teams.
+// maxSampleGap is how long one utilization snapshot may count for.
+func NewGPUChargebackHandler(s store.Store, cfg *chargeback.Config, maxSampleGap time.Duration) *GPUChargebackHandler {
+	if cfg == nil {
+		cfg = &chargeback.Config{}
+	}
+	return &GPUChargebackHandler{store: s, cfg: cfg, maxSampleGap: maxSampleGap, now: time.Now}
+}
+
+// GetReport returns a chargeback report. Admin only: it prices every
+// user's reservations.
+//
+// Query parameters:
+//   - from, to: RFC 3339 time or YYYY-MM-DD date (UTC); default: the
+//     current month to date
+//   - group_by: user (default), namespace or team
+//   - format: json (default) or csv; csv is sent as a download
+func (h *GPUChargebackHandler) GetReport(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+
+	now := h.now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = parseChargebackTime(v); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "from must be an RFC 3339 time or a YYYY-MM-DD date")
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = parseChargebackTime(v); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "to must be an RFC 3339 time or a YYYY-MM-DD date")
+		}
+	}
+	if !from.Before(to) {
+		return fiber.NewError(fiber.StatusBadRequest, "from must be before to")
+	}
+	if to.Sub(from) > chargebackMaxSpan {
+		return fiber.NewError(fiber.StatusBadRequest, "date range must not exceed 366 days")
+	}
+
+	groupBy := chargeback.GroupBy(c.Query("group_by", string(chargeback.GroupByUser)))
+	if !groupBy.IsValid() {
+		return fiber.NewError(fiber.StatusBadRequest, "group_by must be one of: user, namespace, team")
+	}
+	format := c.Query("format", chargeback.FormatJSON)
+	if format != chargeback.FormatJSON && format != chargeback.FormatCSV {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be json or csv")
+	}
+
+	report, err := chargeback.Generate(c.UserContext(), h.store, h.cfg, chargeback.Options{
+		From:         from,
+		To:           to,
+		GroupBy:      groupBy,
+		Now:          now,
+		MaxSampleGap: h.maxSampleGap,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrUtilizationRangeTooLarge) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, "too much utilization data in range; narrow the date range")
+		}
+		slog.Error("[GPUChargeback] report generation failed", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate chargeback report")
+	}
+
+	if format == chargeback.FormatJSON {
+		return c.JSON(report)
+	}
+	data, contentType, err := chargeback.Encode(report, format)
+	if err != nil {
+		slog.Error("[GPUChargeback] report encoding failed", "format", format, "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to encode chargeback report")
+	}
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", chargeback.Filename(report, format)))
+	return c.Send(data)
+}
+
+func parseChargebackTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(chargebackDateLayout, v)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/chargeback"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPUChargebackGetReport(t *testing.T) {
+	env := setupTestEnv(t)
+	start := time.Date(2026, 9, 10, 0, 0, 0, 0, time.UTC)
+	store := &gpuTestStore{
+		user: &models.User{ID: testAdminUserID, GitHubLogin: "alice", Role: models.UserRoleAdmin},
+		listAll: []models.GPUReservation{
+			{ID: uuid.New(), UserName: "alice", Cluster: "c1", Namespace: "ml-train", GPUType: "A100", GPUCount: 2,
+				StartDate: start.Format(time.RFC3339), DurationHours: 5, Status: models.ReservationStatusCompleted, ActivatedAt: &start},
+			{ID: uuid.New(), UserName: "bob", Cluster: "c1", Namespace: "web", GPUCount: 1,
+				StartDate: start.Format(time.RFC3339), DurationHours: 10, Status: models.ReservationStatusCompleted, ActivatedAt: &start},
+		},
+	}
+	cfg := &chargeback.Config{
+		HourlyRates: map[string]float64{"A100": 3},
+		Teams:       []chargeback.TeamConfig{{Name: "ml", Namespaces: []string{"ml-*"}}},
+	}
+	require.NoError(t, cfg.Validate())
+	handler := NewGPUChargebackHandler(store, cfg, time.Hour)
+	env.App.Get("/api/gpu/chargeback", handler.GetReport)
+
+	get := func(query string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, "/api/gpu/chargeback"+query, nil)
+		require.NoError(t, err)
+		resp, err := env.App.Test(req, 5000)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("json by team", func(t *testing.T) {
+		resp := get("?from=2026-09-01&to=2026-10-01&group_by=team")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var report chargeback.Report
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		require.Len(t, report.Rows, 2)
+		assert.Equal(t, "ml", report.Rows[0].Group)
+		assert.Equal(t, 30.0, report.Rows[0].Cost)
+		assert.Equal(t, 100.0, report.Rows[0].IdleWastePct)
+		assert.Equal(t, chargeback.UnassignedTeam, report.Rows[1].Group)
+		assert.Equal(t, 20.0, report.Total.ReservedGPUHours)
+	})
+
+	t.Run("csv download", func(t *testing.T) {
+		resp := get("?from=2026-09-01&to=2026-10-01&format=csv")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "gpu-chargeback-user-2026-09-01-2026-10-01.csv")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "user,alice,")
+		assert.Contains(t, string(body), "user,total,")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, q := range []string{"?group_by=cluster", "?format=xml", "?from=yesterday", "?from=2026-10-01&to=2026-09-01", "?from=2024-01-01&to=2026-01-01"} {
+			resp := get(q)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "query %q", q)
+		}
+	})
+
+	t.Run("admin only", func(t *testing.T) {
+		store.user = &models.User{ID: testAdminUserID, GitHubLogin: "alice", Role: models.UserRoleViewer}
+		resp := get("")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// gpuLifecycleInterval is how often RunLifecycle sweeps reservations when
+// nothing wakes it earlier.
+const gpuLifecycleInterval = time.Minute
+
+// gpuReservationNoticeLead is how long before a reservation starts or ends
+// its owner is told it is about to.
+const gpuReservationNoticeLead = time.Hour
+
+// gpuReservationsActionURL is where lifecycle notifications link to.
+const gpuReservationsActionURL = "/gpu-reservations"
+
+// RunLifecycle drives reservations through their windows until ctx is
+// done: scheduled reservations are provisioned when they start, active ones
+// are torn down when they end, and waitlisted ones are promoted as capacity
+// frees up. Owners are notified before and after each transition.
+func (h *GPUHandler) RunLifecycle(ctx context.Context) {
+	ticker := time.NewTicker(gpuLifecycleInterval)
+	defer ticker.Stop()
+	for {
+		h.ReconcileReservations(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-h.wake:
+		}
+	}
+}
+
+// wakeLifecycle asks RunLifecycle for an early sweep without blocking.
+func (h *GPUHandler) wakeLifecycle() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ReconcileReservations runs one lifecycle sweep. It is exported so the
+// sweep can be driven directly in tests.
+func (h *GPUHandler) ReconcileReservations(ctx context.Context) {
+	reservations, err := h.store.ListClusterGPUReservations(ctx, "", []models.ReservationStatus{
+		models.ReservationStatusActive,
+		models.ReservationStatusScheduled,
+		models.ReservationStatusWaitlisted,
+	})
+	if err != nil {
+		slog.Error("[gpu] lifecycle: failed to list reservations", "error", err)
+		return
+	}
+	now := h.now()
+
+	// Ending and starting windows first, so capacity they free is seen by
+	// the waitlist promotion below in the same sweep.
+	var waitlist []*models.GPUReservation
+	for i := range reservations {
+		r := &reservations[i]
+		switch r.Status {
+		case models.ReservationStatusActive:
+			h.reconcileActive(ctx, r, now)
+		case models.ReservationStatusScheduled:
+			h.reconcileScheduled(ctx, r, now)
+		case models.ReservationStatusWaitlisted:
+			waitlist = append(waitlist, r)
+		}
+	}
+	// The store lists oldest first, so the waitlist is served FIFO.
+	for _, r := range waitlist {
+		h.promoteWaitlisted(ctx, r, now)
+	}
+}
+
+// reconcileActive expires an active reservation whose window has ended and
+// warns its owner shortly before it does. Undated reservations never expire.
+func (h *GPUHandler) reconcileActive(ctx context.Context, r *models.GPUReservation, now time.Time) {
+	_, end, ok := r.Window()
+	if !ok {
+		return
+	}
+	switch {
+	case !now.Before(end):
+		if r.QuotaEnforced {
+			h.cleanupProvisionedResources(ctx, r)
+		}
+		r.Status = models.ReservationStatusCompleted
+		r.NotifiedStage = models.ReservationNotifyEnded
+		if h.saveReservation(ctx, r, models.ReservationStatusActive) {
+			h.notifyOwner(ctx, r, "GPU reservation ended",
+				fmt.Sprintf("Your reservation %q of %d GPU(s) on %s has ended and its quota was removed.", r.Title, r.GPUCount, r.Cluster))
+		}
+	case now.Add(gpuReservationNoticeLead).After(end) && !r.NotifiedStage.Reached(models.ReservationNotifyEndsSoon):
+		r.NotifiedStage = models.ReservationNotifyEndsSoon
+		if h.saveReservation(ctx, r, models.ReservationStatusActive) {
+			h.notifyOwner(ctx, r, "GPU reservation ending soon",
+				fmt.Sprintf("Your reservation %q of %d GPU(s) on %s ends at %s.", r.Title, r.GPUCount, r.Cluster, end.UTC().Format(time.RFC3339)))
+		}
+	}
+}
+
+// reconcileScheduled activates a scheduled reservation once its window
+// starts and warns its owner shortly before. A reservation whose whole
+// window passed without activating is completed. If provisioning fails the
+// reservation stays scheduled and is retried on the next sweep.
+func (h *GPUHandler) reconcileScheduled(ctx context.Context, r *models.GPUReservation, now time.Time) {
+	start, end, ok := r.Window()
+	if !ok {
+		return
+	}
+	switch {
+	case !now.Before(end):
+		r.Status = models.ReservationStatusCompleted
+		r.NotifiedStage = models.ReservationNotifyEnded
+		if h.saveReservation(ctx, r, models.ReservationStatusScheduled) {
+			h.notifyOwner(ctx, r, "GPU reservation ended",
+				fmt.Sprintf("Your reservation %q on %s ended before it could be activated.", r.Title, r.Cluster))
+		}
+	case !now.Before(start):
+		if h.k8sClient != nil {
+			if err := h.provisionOnCluster(ctx, r); err != nil {
+				slog.Error("[gpu] lifecycle: provisioning failed, will retry",
+					"reservation", r.ID, "cluster", r.Cluster, "namespace", r.Namespace, "error", err)
+				return
+			}
+		}
+		r.Status = models.ReservationStatusActive
+		r.NotifiedStage = models.ReservationNotifyStarted
+		if !h.saveReservation(ctx, r, models.ReservationStatusScheduled) {
+			// Leave no quota behind for a reservation that was cancelled
+			// meanwhile, or that the store still considers scheduled (the
+			// next sweep provisions it again).
+			if r.QuotaEnforced {
+				h.cleanupProvisionedResources(ctx, r)
+			}
+			return
+		}
+		h.notifyOwner(ctx, r, "GPU reservation started",
+			fmt.Sprintf("Your reservation %q of %d GPU(s) on %s is active until %s.", r.Title, r.GPUCount, r.Cluster, end.UTC().Format(time.RFC3339)))
+	case now.Add(gpuReservationNoticeLead).After(start) && !r.NotifiedStage.Reached(models.ReservationNotifyStartsSoon):
+		r.NotifiedStage = models.ReservationNotifyStartsSoon
+		if h.saveReservation(ctx, r, models.ReservationStatusScheduled) {
+			h.notifyOwner(ctx, r, "GPU reservation starting soon",
+				fmt.Sprintf("Your reservation %q of %d GPU(s) on %s starts at %s.", r.Title, r.GPUCount, r.Cluster, start.UTC().Format(time.RFC3339)))
+		}
+	}
+}
+
+// promoteWaitlisted schedules a waitlisted reservation if its cluster now
+// has capacity for its window, activating it straight away when the window
+// has already started. A reservation whose window ended while it waited is
+// cancelled.
+func (h *GPUHandler) promoteWaitlisted(ctx context.Context, r *models.GPUReservation, now time.Time) {
+	if _, end, ok := r.Window(); ok && !now.Before(end) {
+		r.Status = models.ReservationStatusCancelled
+		if h.saveReservation(ctx, r, models.ReservationStatusWaitlisted) {
+			h.notifyOwner(ctx, r, "GPU reservation not fulfilled",
+				fmt.Sprintf("Your waitlisted reservation %q on %s was cancelled: no capacity freed up before its window ended.", r.Title, r.Cluster))
+		}
+		return
+	}
+	if h.clusterCapacity == nil {
+		return
+	}
+	capacity := h.clusterCapacity(ctx, r.Cluster)
+	if capacity <= 0 {
+		// Capacity unknown (cluster unreachable); try again next sweep.
+		return
+	}
+
+	r.Status = models.ReservationStatusScheduled
+	applied, err := h.store.TransitionGPUReservation(ctx, r, models.ReservationStatusWaitlisted, capacity)
+	if err != nil || !applied {
+		r.Status = models.ReservationStatusWaitlisted
+		if err != nil && !errors.Is(err, store.ErrGPUQuotaExceeded) {
+			slog.Error("[gpu] lifecycle: failed to promote waitlisted reservation",
+				"reservation", r.ID, "error", err)
+		}
+		return
+	}
+	h.notifyOwner(ctx, r, "GPU reservation confirmed",
+		fmt.Sprintf("Capacity freed up on %s: your waitlisted reservation %q of %d GPU(s) is now scheduled.", r.Cluster, r.Title, r.GPUCount))
+	h.reconcileScheduled(ctx, r, now)
+}
+
+// saveReservation persists a lifecycle change made to a reservation the
+// sweep read in status from, and reports whether it stuck. Only the
+// lifecycle columns are written, and nothing is written if the reservation
+// left from since the sweep listed it (e.g. its owner cancelled it).
+func (h *GPUHandler) saveReservation(ctx context.Context, r *models.GPUReservation, from models.ReservationStatus) bool {
+	applied, err := h.store.TransitionGPUReservation(ctx, r, from, 0)
+	if err != nil {
+		slog.Error("[gpu] lifecycle: failed to update reservation",
+			"reservation", r.ID, "status", r.Status, "error", err)
+		return false
+	}
+	if !applied {
+		slog.Info("[gpu] lifecycle: reservation changed during sweep, skipping",
+			"reservation", r.ID, "expected", from)
+	}
+	return applied
+}
+
+// notifyOwner sends the reservation's owner an in-app notification.
+func (h *GPUHandler) notifyOwner(ctx context.Context, r *models.GPUReservation, title, message string) {
+	n := &models.Notification{
+		ID:               uuid.New(),
+		UserID:           r.UserID,
+		NotificationType: models.NotificationTypeGPUReservation,
+		Title:            title,
+		Message:          message,
+		ActionURL:        gpuReservationsActionURL,
+	}
+	if err := h.store.CreateNotification(ctx, n); err != nil {
+		slog.Warn("[gpu] lifecycle: failed to notify owner",
+			"reservation", r.ID, "user", r.UserID, "error", err)
+	}
+}
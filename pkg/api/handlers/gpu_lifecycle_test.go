@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postGPUReservation(t *testing.T, env *testEnv, input map[string]any) (*http.Response, models.GPUReservation) {
+	t.Helper()
+	body, err := json.Marshal(input)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "/api/gpu/reservations", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var reservation models.GPUReservation
+	if resp.StatusCode < http.StatusBadRequest {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reservation))
+	}
+	return resp, reservation
+}
+
+func TestGPUCreateReservation_WaitlistsWhenWindowIsFull(t *testing.T) {
+	env := setupTestEnv(t)
+	store := &gpuTestStore{
+		user:            &models.User{ID: testAdminUserID, GitHubLogin: "alice"},
+		clusterReserved: 3,
+	}
+	k8sClient := &gpuProvisioningTestClient{}
+	handler := NewGPUHandler(store, stubCapacity(4), k8sClient)
+	env.App.Post("/api/gpu/reservations", handler.CreateReservation)
+
+	resp, reservation := postGPUReservation(t, env, map[string]any{
+		"title":          "Queue me",
+		"cluster":        "cluster-a",
+		"namespace":      "ml",
+		"gpu_count":      3,
+		"start_date":     "2026-03-16T00:00:00Z",
+		"duration_hours": 8,
+		"quota_enforced": true,
+		"waitlist":       true,
+	})
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, models.ReservationStatusWaitlisted, reservation.Status)
+	assert.False(t, reservation.QuotaEnforced)
+	require.NotNil(t, store.created)
+	assert.Equal(t, models.ReservationStatusWaitlisted, store.created.Status)
+	assert.Nil(t, k8sClient.quotaSpec, "a waitlisted reservation must not be provisioned")
+}
+
+func TestGPUCreateReservation_FutureStartIsScheduled(t *testing.T) {
+	env := setupTestEnv(t)
+	store := &gpuTestStore{user: &models.User{ID: testAdminUserID, GitHubLogin: "alice"}}
+	k8sClient := &gpuProvisioningTestClient{}
+	handler := NewGPUHandler(store, stubCapacity(8), k8sClient)
+	env.App.Post("/api/gpu/reservations", handler.CreateReservation)
+
+	resp, reservation := postGPUReservation(t, env, map[string]any{
+		"title":          "Next week",
+		"cluster":        "cluster-a",
+		"namespace":      "ml",
+		"gpu_count":      2,
+		"start_date":     time.Now().Add(7 * 24 * time.Hour).UTC().Format(time.RFC3339),
+		"duration_hours": 8,
+	})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, models.ReservationStatusScheduled, reservation.Status)
+	assert.Nil(t, k8sClient.quotaSpec, "provisioning waits for the window to start")
+}
+
+func TestGPUUpdateReservation_LifecycleStatusesAreNotSettable(t *testing.T) {
+	env := setupTestEnv(t)
+	resID := uuid.New()
+	store := &gpuTestStore{
+		user: &models.User{ID: testAdminUserID, GitHubLogin: "alice", Role: models.UserRoleAdmin},
+		reservations: map[uuid.UUID]*models.GPUReservation{
+			resID: {ID: resID, UserID: testAdminUserID, GPUCount: 2, DurationHours: 8, Cluster: "c1", Status: models.ReservationStatusWaitlisted},
+		},
+	}
+	handler := NewGPUHandler(store, nil, nil)
+	env.App.Put("/api/gpu/reservations/:id", handler.UpdateReservation)
+
+	body, err := json.Marshal(map[string]any{"status": "active"})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPut, "/api/gpu/reservations/"+resID.String(), bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Nil(t, store.updated)
+}
+
+func TestGPUUpdateReservation_CancelRemovesQuota(t *testing.T) {
+	env := setupTestEnv(t)
+	resID := uuid.New()
+	store := &gpuTestStore{
+		user: &models.User{ID: testAdminUserID, GitHubLogin: "alice", Role: models.UserRoleAdmin},
+		reservations: map[uuid.UUID]*models.GPUReservation{
+			resID: {ID: resID, UserID: testAdminUserID, GPUCount: 2, DurationHours: 8, Cluster: "c1", Namespace: "ml",
+				Status: models.ReservationStatusActive, QuotaName: "gpu-reservation-ml", QuotaEnforced: true},
+		},
+	}
+	k8sClient := &gpuProvisioningTestClient{}
+	handler := NewGPUHandler(store, nil, k8sClient)
+	env.App.Put("/api/gpu/reservations/:id", handler.UpdateReservation)
+
+	body, err := json.Marshal(map[string]any{"status": "cancelled"})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPut, "/api/gpu/reservations/"+resID.String(), bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, k8sClient.deleteQuotaCalls)
+	assert.Equal(t, "gpu-reservation-ml", k8sClient.deleteQuotaName)
+	assert.Len(t, handler.wake, 1, "cancelling frees capacity and should wake the lifecycle worker")
+}
+
+func TestGPUReconcileReservations(t *testing.T) {
+	now := time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	owner := uuid.New()
+
+	expired := models.GPUReservation{ID: uuid.New(), UserID: owner, Title: "expired", Cluster: "c1", Namespace: "old",
+		GPUCount: 4, StartDate: at(-9 * time.Hour), DurationHours: 8, Status: models.ReservationStatusActive,
+		QuotaName: "gpu-reservation-old", QuotaEnforced: true, NotifiedStage: models.ReservationNotifyEndsSoon}
+	ending := models.GPUReservation{ID: uuid.New(), UserID: owner, Title: "ending", Cluster: "c1", Namespace: "ending",
+		GPUCount: 1, StartDate: at(-7*time.Hour - 30*time.Minute), DurationHours: 8, Status: models.ReservationStatusActive,
+		NotifiedStage: models.ReservationNotifyStarted}
+	starting := models.GPUReservation{ID: uuid.New(), UserID: owner, Title: "starting", Cluster: "c1", Namespace: "new",
+		GPUCount: 2, StartDate: at(-time.Minute), DurationHours: 8, Status: models.ReservationStatusScheduled}
+	later := models.GPUReservation{ID: uuid.New(), UserID: owner, Title: "later", Cluster: "c1", Namespace: "later",
+		GPUCount: 2, StartDate: at(24 * time.Hour), DurationHours: 8, Status: models.ReservationStatusScheduled}
+	queued := models.GPUReservation{ID: uuid.New(), UserID: owner, Title: "queued", Cluster: "c1", Namespace: "queued",
+		GPUCount: 2, StartDate: at(-time.Hour), DurationHours: 8, Status: models.ReservationStatusWaitlisted}
+	stale := models.GPUReservation{ID: uuid.New(), UserID: owner, Title: "stale", Cluster: "c1", Namespace: "stale",
+		GPUCount: 2, StartDate: at(-48 * time.Hour), DurationHours: 8, Status: models.ReservationStatusWaitlisted}
+
+	store := &gpuTestStore{listAll: []models.GPUReservation{expired, ending, starting, later, queued, stale}}
+	k8sClient := &gpuProvisioningTestClient{}
+	handler := NewGPUHandler(store, stubCapacity(8), k8sClient)
+	handler.now = func() time.Time { return now }
+
+	handler.ReconcileReservations(context.Background())
+
+	final := map[string]models.GPUReservation{}
+	for _, r := range store.updates {
+		final[r.Title] = r
+	}
+	assert.Equal(t, models.ReservationStatusCompleted, final["expired"].Status)
+	assert.Equal(t, 1, k8sClient.deleteQuotaCalls)
+	assert.Equal(t, "gpu-reservation-old", k8sClient.deleteQuotaName)
+
+	assert.Equal(t, models.ReservationStatusActive, final["ending"].Status)
+	assert.Equal(t, models.ReservationNotifyEndsSoon, final["ending"].NotifiedStage)
+
+	assert.Equal(t, models.ReservationStatusActive, final["starting"].Status)
+	assert.True(t, final["starting"].QuotaEnforced)
+
+	assert.NotContains(t, final, "later", "a window a day away needs no change yet")
+
+	assert.Equal(t, models.ReservationStatusActive, final["queued"].Status, "promoted and, having started, activated")
+	assert.Equal(t, "queued", k8sClient.quotaSpec.Namespace)
+
+	assert.Equal(t, models.ReservationStatusCancelled, final["stale"].Status)
+
+	titles := make([]string, 0, len(store.notifications))
+	for _, n := range store.notifications {
+		assert.Equal(t, owner, n.UserID)
+		assert.Equal(t, models.NotificationTypeGPUReservation, n.NotificationType)
+		titles = append(titles, n.Title)
+	}
+	assert.ElementsMatch(t, []string{
+		"GPU reservation ended",
+		"GPU reservation ending soon",
+		"GPU reservation started",
+		"GPU reservation confirmed",
+		"GPU reservation started",
+		"GPU reservation not fulfilled",
+	}, titles)
+}
+
+func TestGPUReconcileReservations_SkipsReservationsChangedMeanwhile(t *testing.T) {
+	now := time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)
+	starting := models.GPUReservation{ID: uuid.New(), UserID: uuid.New(), Title: "starting", Cluster: "c1", Namespace: "new",
+		GPUCount: 2, StartDate: now.Add(-time.Minute).Format(time.RFC3339), DurationHours: 8, Status: models.ReservationStatusScheduled}
+	queued := models.GPUReservation{ID: uuid.New(), UserID: uuid.New(), Title: "queued", Cluster: "c1", Namespace: "queued",
+		GPUCount: 2, StartDate: now.Add(-time.Hour).Format(time.RFC3339), DurationHours: 8, Status: models.ReservationStatusWaitlisted}
+
+	// Both owners cancel after the sweep listed their reservations.
+	store := &gpuTestStore{
+		listAll: []models.GPUReservation{starting, queued},
+		changed: map[uuid.UUID]models.ReservationStatus{
+			starting.ID: models.ReservationStatusCancelled,
+			queued.ID:   models.ReservationStatusCancelled,
+		},
+	}
+	k8sClient := &gpuProvisioningTestClient{}
+	handler := NewGPUHandler(store, stubCapacity(8), k8sClient)
+	handler.now = func() time.Time { return now }
+
+	handler.ReconcileReservations(context.Background())
+
+	assert.Empty(t, store.updates, "a cancelled reservation must not be written back")
+	assert.Empty(t, store.notifications)
+	assert.Equal(t, 1, k8sClient.deleteQuotaCalls, "quota provisioned for the cancelled reservation is removed")
+	assert.Equal(t, "new", k8sClient.deleteQuotaNamespace)
+}
+
+func TestGPUGetCapacityCalendar(t *testing.T) {
+	env := setupTestEnv(t)
+	from := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	at := func(h int) string { return from.Add(time.Duration(h) * time.Hour).Format(time.RFC3339) }
+	store := &gpuTestStore{
+		user: &models.User{ID: testAdminUserID, GitHubLogin: "alice"},
+		listAll: []models.GPUReservation{
+			{ID: uuid.New(), Cluster: "cluster-a", GPUCount: 6, StartDate: at(0), DurationHours: 12, Status: models.ReservationStatusActive},
+			{ID: uuid.New(), Cluster: "cluster-a", GPUCount: 4, StartDate: at(6), DurationHours: 12, Status: models.ReservationStatusWaitlisted},
+			{ID: uuid.New(), Cluster: "cluster-a", GPUCount: 2, StartDate: at(48), DurationHours: 12, Status: models.ReservationStatusScheduled},
+			{ID: uuid.New(), Cluster: "cluster-b", GPUCount: 8, StartDate: at(0), DurationHours: 24, Status: models.ReservationStatusActive},
+		},
+	}
+	handler := NewGPUHandler(store, stubCapacity(8), nil)
+	env.App.Get("/api/gpu/calendar", handler.GetCapacityCalendar)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/gpu/calendar?cluster=cluster-a&from="+at(0)+"&to="+at(24), nil)
+	require.NoError(t, err)
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var calendar gpuCapacityCalendar
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&calendar))
+	assert.Equal(t, 8, calendar.Capacity)
+	assert.Equal(t, 6, calendar.PeakReserved)
+	require.Len(t, calendar.Segments, 2)
+	assert.Equal(t, 2, calendar.Segments[0].Available)
+	assert.Equal(t, 8, calendar.Segments[1].Available)
+	assert.Len(t, calendar.Reservations, 1)
+	assert.Len(t, calendar.Waitlist, 1)
+
+	for _, q := range []string{"", "?cluster=Bad_Name", "?cluster=cluster-a&from=" + at(0) + "&to=" + at(24*91)} {
+		req, err := http.NewRequest(http.MethodGet, "/api/gpu/calendar"+q, nil)
+		require.NoError(t, err)
+		resp, err := env.App.Test(req, 5000)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "query %q", q)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kubestellar/console/pkg/k8s"
@@ -31,8 +32,13 @@ type gpuTestStore struct {
 	reservations       map[uuid.UUID]*models.GPUReservation
 	updateErr          error
 	updated            *models.GPUReservation
+	updates            []models.GPUReservation
+	notifications      []models.Notification
 	bulkSnapshots      map[string][]models.GPUUtilizationSnapshot
 	bulkSnapshotsErr   error
+	// changed holds statuses set behind the sweep's back, as if by a
+	// concurrent request, after listAll was read.
+	changed map[uuid.UUID]models.ReservationStatus
 }
 
 type gpuProvisioningTestClient struct {
@@ -149,6 +155,38 @@ func (s *gpuTestStore) UpdateGPUReservation(_ context.Context, reservation *mode
 	}
 	copy := *reservation
 	s.updated = &copy
+	s.updates = append(s.updates, copy)
+	return nil
+}
+
+func (s *gpuTestStore) GetClusterPeakReservedGPUs(_ context.Context, cluster string, start, end time.Time, excludeID *uuid.UUID) (int, error) {
+	if s.clusterReservedErr != nil {
+		return 0, s.clusterReservedErr
+	}
+	return s.clusterReserved, nil
+}
+
+func (s *gpuTestStore) ListClusterGPUReservations(_ context.Context, cluster string, statuses []models.ReservationStatus) ([]models.GPUReservation, error) {
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	var out []models.GPUReservation
+	for _, r := range s.listAll {
+		if cluster != "" && r.Cluster != cluster {
+			continue
+		}
+		for _, st := range statuses {
+			if r.Status == st {
+				out = append(out, r)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *gpuTestStore) CreateNotification(_ context.Context, n *models.Notification) error {
+	s.notifications = append(s.notifications, *n)
 	return nil
 }
 
@@ -164,6 +202,40 @@ func (s *gpuTestStore) UpdateGPUReservationWithCapacity(ctx context.Context, res
 	return s.UpdateGPUReservation(ctx, reservation)
 }
 
+// TransitionGPUReservation mirrors the conditional lifecycle write: it only
+// applies while the reservation's latest status is still from.
+func (s *gpuTestStore) TransitionGPUReservation(ctx context.Context, reservation *models.GPUReservation, from models.ReservationStatus, capacity int) (bool, error) {
+	if s.updateErr != nil {
+		return false, s.updateErr
+	}
+	if current, ok := s.currentStatus(reservation.ID); ok && current != from {
+		return false, nil
+	}
+	if capacity > 0 && reservation.Status.HoldsCapacity() && s.clusterReserved+reservation.GPUCount > capacity {
+		return false, store.ErrGPUQuotaExceeded
+	}
+	return true, s.UpdateGPUReservation(ctx, reservation)
+}
+
+// currentStatus is the status the store would hold for id: the last
+// update or concurrent change, else the listed row.
+func (s *gpuTestStore) currentStatus(id uuid.UUID) (models.ReservationStatus, bool) {
+	for i := len(s.updates) - 1; i >= 0; i-- {
+		if s.updates[i].ID == id {
+			return s.updates[i].Status, true
+		}
+	}
+	if st, ok := s.changed[id]; ok {
+		return st, true
+	}
+	for _, r := range s.listAll {
+		if r.ID == id {
+			return r.Status, true
+		}
+	}
+	return "", false
+}
+
 // GetGPUReservationsByIDs returns reservations from the test store's
 // reservations map in a single call (#6963).
 func (s *gpuTestStore) GetGPUReservationsByIDs(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.GPUReservation, error) {
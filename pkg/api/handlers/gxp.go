@@ -1,14 +1,18 @@
 package handlers
 
 import (
+	"log/slog"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/kubestellar/console/pkg/compliance/gxp"
+	"github.com/kubestellar/console/pkg/store"
 )
 
 // GxPHandler serves GxP / 21 CFR Part 11 compliance endpoints.
 type GxPHandler struct {
 	engine *gxp.Engine
+	store  store.Store
 }
 
 // NewGxPHandler creates a handler backed by a GxP engine.
@@ -16,6 +20,13 @@ func NewGxPHandler() *GxPHandler {
 	return &GxPHandler{engine: gxp.NewEngine()}
 }
 
+// NewLiveGxPHandler creates a handler reporting on the real audit chain.
+// engine should come from gxp.NewLiveEngine; s gates the endpoints to
+// console admins, since the trail names users and their actions.
+func NewLiveGxPHandler(engine *gxp.Engine, s store.Store) *GxPHandler {
+	return &GxPHandler{engine: engine, store: s}
+}
+
 // RegisterPublicRoutes mounts read-only endpoints on the given router group.
 func (h *GxPHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/compliance/gxp")
@@ -26,8 +37,60 @@ func (h *GxPHandler) RegisterPublicRoutes(r fiber.Router) {
 	g.Get("/summary", h.getSummary)
 }
 
+// RegisterLiveRoutes mounts the live audit chain endpoints.
+func (h *GxPHandler) RegisterLiveRoutes(r fiber.Router) {
+	g := r.Group("/compliance/gxp/live")
+	g.Get("/config", h.getLiveConfig)
+	g.Get("/records", h.listLiveRecords)
+	g.Get("/chain/verify", h.verifyLiveChain)
+	g.Get("/summary", h.getLiveSummary)
+}
+
 func (h *GxPHandler) getConfig(c *fiber.Ctx) error       { return c.JSON(h.engine.GetConfig()) }
 func (h *GxPHandler) listRecords(c *fiber.Ctx) error      { return c.JSON(h.engine.AuditRecords()) }
 func (h *GxPHandler) listSignatures(c *fiber.Ctx) error   { return c.JSON(h.engine.Signatures()) }
 func (h *GxPHandler) verifyChain(c *fiber.Ctx) error      { return c.JSON(h.engine.VerifyChain()) }
 func (h *GxPHandler) getSummary(c *fiber.Ctx) error       { return c.JSON(h.engine.Summary()) }
+
+func (h *GxPHandler) getLiveConfig(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	return c.JSON(h.engine.GetConfig())
+}
+
+func (h *GxPHandler) listLiveRecords(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	records, err := h.engine.LiveRecords(c.UserContext())
+	if err != nil {
+		slog.Error("[GxP] failed to read audit chain", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to read audit chain")
+	}
+	return c.JSON(records)
+}
+
+func (h *GxPHandler) verifyLiveChain(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	status, err := h.engine.LiveVerifyChain(c.UserContext())
+	if err != nil {
+		slog.Error("[GxP] failed to verify audit chain", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to verify audit chain")
+	}
+	return c.JSON(status)
+}
+
+func (h *GxPHandler) getLiveSummary(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	summary, err := h.engine.LiveSummary(c.UserContext())
+	if err != nil {
+		slog.Error("[GxP] failed to summarize audit chain", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to summarize audit chain")
+	}
+	return c.JSON(summary)
+}
@@ -1,14 +1,38 @@
 package handlers
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/kubestellar/console/pkg/compliance/hipaa"
 )
 
+// hipaaLiveTimeout bounds one live evaluation across all clusters.
+const hipaaLiveTimeout = 60 * time.Second
+
+// ClusterNameLister returns the clusters a live compliance evaluation covers
+// when the request does not name one.
+type ClusterNameLister func(ctx context.Context) ([]string, error)
+
 // HIPAAHandler serves HIPAA Security Rule compliance endpoints.
 type HIPAAHandler struct {
-	engine *hipaa.Engine
+	engine   *hipaa.Engine
+	clusters ClusterNameLister
+}
+
+// requestClusters returns the cluster named by ?cluster=, or every cluster
+// from lister when the request does not name one.
+func requestClusters(ctx context.Context, c *fiber.Ctx, lister ClusterNameLister) ([]string, error) {
+	if cluster := c.Query("cluster"); cluster != "" {
+		return []string{cluster}, nil
+	}
+	if lister == nil {
+		return nil, nil
+	}
+	return lister(ctx)
 }
 
 // NewHIPAAHandler creates a handler backed by a HIPAA engine.
@@ -16,6 +40,12 @@ func NewHIPAAHandler() *HIPAAHandler {
 	return &HIPAAHandler{engine: hipaa.NewEngine()}
 }
 
+// NewLiveHIPAAHandler creates a handler that evaluates engine against the
+// clusters returned by clusters. engine should come from hipaa.NewLiveEngine.
+func NewLiveHIPAAHandler(engine *hipaa.Engine, clusters ClusterNameLister) *HIPAAHandler {
+	return &HIPAAHandler{engine: engine, clusters: clusters}
+}
+
 // RegisterPublicRoutes mounts read-only endpoints on the given router group.
 func (h *HIPAAHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/compliance/hipaa")
@@ -25,7 +55,75 @@ func (h *HIPAAHandler) RegisterPublicRoutes(r fiber.Router) {
 	g.Get("/summary", h.getSummary)
 }
 
+// RegisterLiveRoutes mounts the live-evaluation endpoints. Every endpoint
+// accepts an optional ?cluster= to evaluate a single cluster.
+func (h *HIPAAHandler) RegisterLiveRoutes(r fiber.Router) {
+	g := r.Group("/compliance/hipaa/live")
+	g.Get("/report", h.getLiveReport)
+	g.Get("/safeguards", h.listLiveSafeguards)
+	g.Get("/phi-namespaces", h.listLivePHINamespaces)
+	g.Get("/data-flows", h.listLiveDataFlows)
+	g.Get("/summary", h.getLiveSummary)
+	g.Post("/refresh", h.refreshLive)
+}
+
 func (h *HIPAAHandler) listSafeguards(c *fiber.Ctx) error   { return c.JSON(h.engine.Safeguards()) }
 func (h *HIPAAHandler) listPHINamespaces(c *fiber.Ctx) error { return c.JSON(h.engine.PHINamespaces()) }
 func (h *HIPAAHandler) listDataFlows(c *fiber.Ctx) error     { return c.JSON(h.engine.DataFlows()) }
 func (h *HIPAAHandler) getSummary(c *fiber.Ctx) error        { return c.JSON(h.engine.Summary()) }
+
+func (h *HIPAAHandler) getLiveReport(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *hipaa.Report) any { return r })
+}
+
+func (h *HIPAAHandler) listLiveSafeguards(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *hipaa.Report) any { return r.Safeguards })
+}
+
+func (h *HIPAAHandler) listLivePHINamespaces(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *hipaa.Report) any { return r.PHINamespaces })
+}
+
+func (h *HIPAAHandler) listLiveDataFlows(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *hipaa.Report) any { return r.DataFlows })
+}
+
+func (h *HIPAAHandler) getLiveSummary(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *hipaa.Report) any { return r.Summary })
+}
+
+// refreshLive drops cached evaluations (for ?cluster= or all clusters) and
+// returns a freshly probed report.
+func (h *HIPAAHandler) refreshLive(c *fiber.Ctx) error {
+	h.engine.Invalidate(c.Query("cluster"))
+	return h.getLiveReport(c)
+}
+
+// withLiveReport evaluates the requested clusters and renders the part of the
+// report selected by pick.
+func (h *HIPAAHandler) withLiveReport(c *fiber.Ctx, pick func(*hipaa.Report) any) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), hipaaLiveTimeout)
+	defer cancel()
+
+	clusters, err := requestClusters(ctx, c, h.clusters)
+	if err != nil {
+		slog.Error("[HIPAA] failed to list clusters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list clusters",
+		})
+	}
+	if h.engine.IsLive() && len(clusters) == 0 {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "no healthy clusters available",
+		})
+	}
+
+	report, err := h.engine.Evaluate(ctx, clusters)
+	if err != nil {
+		slog.Error("[HIPAA] live evaluation failed", "clusters", clusters, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "evaluation failed",
+		})
+	}
+	return c.JSON(pick(report))
+}
@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kubestellar/console/pkg/compliance/hipaa"
+	"github.com/kubestellar/console/pkg/k8s"
 )
 
 func setupHIPAAApp() *fiber.App {
@@ -104,3 +109,45 @@ func TestHIPAASummary(t *testing.T) {
 		t.Errorf("expected score 60, got %d", summary.OverallScore)
 	}
 }
+
+func TestHIPAALiveSummary(t *testing.T) {
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	k8sClient.InjectClient("prod", k8sfake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "patients", Labels: map[string]string{hipaa.DefaultPHILabel: "true"}}},
+	))
+	engine := hipaa.NewLiveEngine(k8s.NewHIPAAProber(k8sClient), hipaa.LiveOptions{})
+	h := NewLiveHIPAAHandler(engine, func(context.Context) ([]string, error) { return []string{"prod"}, nil })
+	app := fiber.New()
+	h.RegisterLiveRoutes(app.Group("/api"))
+
+	req, _ := http.NewRequest("GET", "/api/compliance/hipaa/live/summary", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var summary hipaa.Summary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if summary.Mode != hipaa.ModeLive || summary.PHINamespaces != 1 || summary.CompliantNS != 0 {
+		t.Errorf("unexpected live summary: %+v", summary)
+	}
+
+	// No healthy clusters is a 503 rather than an empty report.
+	h = NewLiveHIPAAHandler(engine, func(context.Context) ([]string, error) { return nil, nil })
+	app = fiber.New()
+	h.RegisterLiveRoutes(app.Group("/api"))
+	req, _ = http.NewRequest("POST", "/api/compliance/hipaa/live/refresh", nil)
+	resp, err = app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", resp.StatusCode)
+	}
+}
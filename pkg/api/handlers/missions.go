@@ -41,9 +41,12 @@ func (h *MissionsHandler) RegisterRoutes(g fiber.Router) {
 	g.Post("/share/slack", h.ShareToSlack)
 	g.Post("/share/github", h.ShareToGitHub)
 	g.Get("/gaps", h.GetKBGaps)
+	g.Get("/kb/status", h.GetKBStatus)
+	g.Post("/kb/sync", h.SyncKB)
 }
 
-// RegisterPublicRoutes registers unauthenticated browse/file routes (proxies to public GitHub repo).
+// RegisterPublicRoutes registers unauthenticated browse/file routes (proxies to
+// the public GitHub repo, or serves the local bundle mirror when configured).
 func (h *MissionsHandler) RegisterPublicRoutes(g fiber.Router) {
 	g.Get("/browse", h.BrowseConsoleKB)
 	g.Get("/file", h.GetMissionFile)
@@ -51,6 +54,27 @@ func (h *MissionsHandler) RegisterPublicRoutes(g fiber.Router) {
 	g.Get("/scores/:project/:id", h.GetMissionScore)
 }
 
+// kbHiddenFiles are files hidden from the browser UI — infrastructure and
+// metadata entries that are not missions and would confuse users.
+// #6421 — Any dot-prefixed entry is hidden by the dotfile check in
+// hiddenKBEntry, so this map only needs to cover non-dot files.
+var kbHiddenFiles = map[string]bool{
+	"index.json":        true,
+	"search-state.json": true,
+}
+
+// hiddenKBEntry reports whether a browse entry is hidden from the browser UI.
+func hiddenKBEntry(name, entryType string) bool {
+	if entryType == "file" && kbHiddenFiles[name] {
+		return true
+	}
+	// #6421 — Skip any dotfile/dotdir (standard hidden-entry convention).
+	// This is intentionally exhaustive rather than an allowlist so that
+	// newly-added infrastructure dirs (.gitlab, .vscode, .well-known…)
+	// don't leak into the mission browser UI automatically.
+	return strings.HasPrefix(name, ".")
+}
+
 // githubGet makes a GET request to the GitHub API, falling back to unauthenticated if token is expired.
 func (h *MissionsHandler) githubGet(url string, clientToken string) (*http.Response, error) {
 	req, err := http.NewRequest("GET", url, nil)
@@ -214,6 +238,9 @@ func (h *MissionsHandler) BrowseConsoleKB(c *fiber.Ctx) error {
 		slog.Warn("[missions] rejected browse path", "path", path, "error", err)
 		return c.Status(400).JSON(fiber.Map{"error": "invalid path parameter"})
 	}
+	if h.mirror != nil {
+		return h.browseMirror(c, path)
+	}
 	url := fmt.Sprintf("%s/repos/kubestellar/console-kb/contents/%s?ref=master", h.githubAPIURL, path)
 	cacheKey := "browse:" + path
 
@@ -281,15 +308,6 @@ func (h *MissionsHandler) BrowseConsoleKB(c *fiber.Ctx) error {
 		ghEntries = []map[string]interface{}{single}
 	}
 
-	// Files and directories to hide from the browser UI — infrastructure
-	// and metadata entries that are not missions and would confuse users.
-	// #6421 — Any dot-prefixed entry is hidden by the dotfile check below,
-	// so this map only needs to cover non-dot files.
-	hiddenFiles := map[string]bool{
-		"index.json":        true,
-		"search-state.json": true,
-	}
-
 	entries := make([]fiber.Map, 0, len(ghEntries))
 	for _, e := range ghEntries {
 		entryType, _ := e["type"].(string)
@@ -297,15 +315,7 @@ func (h *MissionsHandler) BrowseConsoleKB(c *fiber.Ctx) error {
 			entryType = "directory"
 		}
 		name, _ := e["name"].(string)
-		// Skip infrastructure files that are not missions
-		if entryType == "file" && hiddenFiles[name] {
-			continue
-		}
-		// #6421 — Skip any dotfile/dotdir (standard hidden-entry convention).
-		// This is intentionally exhaustive rather than an allowlist so that
-		// newly-added infrastructure dirs (.gitlab, .vscode, .well-known…)
-		// don't leak into the mission browser UI automatically.
-		if strings.HasPrefix(name, ".") {
+		if hiddenKBEntry(name, entryType) {
 			continue
 		}
 		path, _ := e["path"].(string)
@@ -330,20 +340,27 @@ func (h *MissionsHandler) BrowseConsoleKB(c *fiber.Ctx) error {
 		slog.Info("[missions] cache MISS, stored (browse)", "path", path)
 	}
 
-	// Record zero-result browse paths for the KB gap tracker.
-	// Fires asynchronously so it never delays the response.
-	if len(entries) == 0 && h.store != nil {
-		safego.GoWith("kb-gap-record", func() {
-			if err := h.store.RecordKBGap(context.Background(), path); err != nil {
-				slog.Warn("[missions] failed to record KB gap", "path", path, "error", err)
-			}
-		})
+	if len(entries) == 0 {
+		h.recordKBGap(path)
 	}
 
 	c.Set("X-Cache", "MISS")
 	return c.JSON(entries)
 }
 
+// recordKBGap records a zero-result browse path for the KB gap tracker.
+// Fires asynchronously so it never delays the response.
+func (h *MissionsHandler) recordKBGap(path string) {
+	if h.store == nil {
+		return
+	}
+	safego.GoWith("kb-gap-record", func() {
+		if err := h.store.RecordKBGap(context.Background(), path); err != nil {
+			slog.Warn("[missions] failed to record KB gap", "path", path, "error", err)
+		}
+	})
+}
+
 // GetKBGaps returns the top zero-result KB browse paths, ordered by hit count.
 // GET /api/missions/gaps?limit=20
 func (h *MissionsHandler) GetKBGaps(c *fiber.Ctx) error {
@@ -392,6 +409,9 @@ func (h *MissionsHandler) GetMissionFile(c *fiber.Ctx) error {
 		slog.Warn("[missions] invalid ref parameter", "error", err)
 		return c.Status(400).JSON(fiber.Map{"error": "invalid ref parameter"})
 	}
+	if h.mirror != nil {
+		return h.serveMirrorFile(c, path)
+	}
 
 	cacheKey := "file:" + ref + ":" + path
 	url := fmt.Sprintf("%s/kubestellar/console-kb/%s/%s", h.githubRawURL, ref, path)
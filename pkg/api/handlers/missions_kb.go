@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/api/audit"
+	"github.com/kubestellar/console/pkg/kbmirror"
+)
+
+// kbIndexPath is the mission index every browser listing and score lookup
+// is served from.
+const kbIndexPath = "fixes/index.json"
+
+// Mission sources selectable with MISSIONS_SOURCE.
+const (
+	MissionsSourceGitHub = "github"
+	MissionsSourceBundle = "bundle"
+)
+
+// WithMirror serves the knowledge base from a local console-kb bundle
+// mirror instead of GitHub, for air-gapped and rate-limited installs.
+// syncer pulls new bundles on POST /api/missions/kb/sync and may be nil.
+func (h *MissionsHandler) WithMirror(m *kbmirror.Mirror, syncer *kbmirror.Syncer) *MissionsHandler {
+	h.mirror = m
+	h.mirrorSync = syncer
+	return h
+}
+
+// browseMirror lists a directory of the installed bundle in the same
+// normalized [{name, path, type, size}] shape as the GitHub path.
+func (h *MissionsHandler) browseMirror(c *fiber.Ctx, path string) error {
+	items, err := h.mirror.List(path)
+	if err != nil {
+		return h.mirrorError(c, err, path)
+	}
+	entries := make([]fiber.Map, 0, len(items))
+	for _, e := range items {
+		entryType := e.Type
+		if entryType == "dir" {
+			entryType = "directory"
+		}
+		if hiddenKBEntry(e.Name, entryType) {
+			continue
+		}
+		entries = append(entries, fiber.Map{
+			"name": e.Name,
+			"path": e.Path,
+			"type": entryType,
+			"size": int(e.Size),
+		})
+	}
+	if len(entries) == 0 {
+		h.recordKBGap(path)
+	}
+	h.setMirrorHeaders(c)
+	return c.JSON(entries)
+}
+
+// serveMirrorFile returns a file of the installed bundle. The bundle is a
+// snapshot of one ref, so the ref parameter is not consulted.
+func (h *MissionsHandler) serveMirrorFile(c *fiber.Ctx, path string) error {
+	data, err := h.mirror.ReadFile(path)
+	if err != nil {
+		return h.mirrorError(c, err, path)
+	}
+	h.setMirrorHeaders(c)
+	c.Set("Content-Type", "text/plain")
+	return c.Send(data)
+}
+
+func (h *MissionsHandler) setMirrorHeaders(c *fiber.Ctx) {
+	c.Set("X-KB-Source", MissionsSourceBundle)
+	c.Set("X-KB-Version", h.mirror.Status().Version)
+}
+
+func (h *MissionsHandler) mirrorError(c *fiber.Ctx, err error, path string) error {
+	switch {
+	case errors.Is(err, kbmirror.ErrNotInstalled):
+		return c.Status(http.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "mission knowledge base bundle not installed",
+			"code":  "kb_not_installed",
+		})
+	case errors.Is(err, kbmirror.ErrNotFound):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "file not found"})
+	default:
+		slog.Error("[missions] failed to read bundle", "path", path, "error", err)
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": "failed to read mission knowledge base"})
+	}
+}
+
+// GetKBStatus reports where missions are served from and, for a bundle
+// mirror, the installed version, its verification and the last sync.
+// GET /api/missions/kb/status
+func (h *MissionsHandler) GetKBStatus(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	if h.mirror == nil {
+		return c.JSON(fiber.Map{"source": MissionsSourceGitHub})
+	}
+	resp := fiber.Map{"source": MissionsSourceBundle, "bundle": h.mirror.Status()}
+	if h.mirrorSync != nil {
+		resp["sync"] = h.mirrorSync.State()
+	}
+	return c.JSON(resp)
+}
+
+// SyncKB checks the bundle file and OCI artifact for a new console-kb
+// version now instead of waiting for the next scheduled sync.
+// POST /api/missions/kb/sync
+func (h *MissionsHandler) SyncKB(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	if h.mirrorSync == nil {
+		return fiber.NewError(fiber.StatusConflict, "missions are not served from a bundle mirror")
+	}
+	before := h.mirror.Status().Version
+	st, err := h.mirrorSync.Sync(c.UserContext())
+	if st.Version != before {
+		audit.Log(c, audit.ActionSyncMissionKB, "mission_kb", st.Version, "from "+before)
+	}
+	if err != nil {
+		slog.Error("[missions] console-kb sync failed", "error", err)
+		return c.Status(http.StatusBadGateway).JSON(fiber.Map{
+			"error":  "console-kb sync failed",
+			"detail": err.Error(),
+			"bundle": st,
+		})
+	}
+	return c.JSON(fiber.Map{"source": MissionsSourceBundle, "bundle": st, "sync": h.mirrorSync.State()})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/kbmirror"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+// setupMirrorTest installs a bundle of files into a fresh mirror and returns
+// an app whose missions handler serves from it. GitHub is pointed at a
+// server that fails the test if it is ever reached.
+func setupMirrorTest(t *testing.T, files map[string]string) (*fiber.App, *MissionsHandler) {
+	t.Helper()
+	src := t.TempDir()
+	for name, body := range files {
+		p := filepath.Join(src, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(body), 0o644))
+	}
+	mirror, err := kbmirror.Open(t.TempDir(), nil)
+	require.NoError(t, err)
+	if len(files) > 0 {
+		var buf bytes.Buffer
+		_, err = kbmirror.Build(&buf, src, kbmirror.BuildOptions{Version: "2026.10.17"})
+		require.NoError(t, err)
+		_, _, err = mirror.Import(&buf)
+		require.NoError(t, err)
+	}
+
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected GitHub request %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(github.Close)
+
+	app, handler := setupMissionsTest()
+	handler.githubAPIURL = github.URL
+	handler.githubRawURL = github.URL
+	handler.WithMirror(mirror, nil)
+	return app, handler
+}
+
+func TestMissions_Mirror_Browse(t *testing.T) {
+	app, _ := setupMirrorTest(t, map[string]string{
+		"fixes/index.json":           indexWithScores,
+		"fixes/coredns/dns-fix.json": `{"id":"dns-fix"}`,
+		"README.md":                  "# console-kb",
+	})
+
+	req, err := http.NewRequest("GET", "/api/missions/browse?path=fixes", nil)
+	require.NoError(t, err)
+	resp, err := app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, MissionsSourceBundle, resp.Header.Get("X-KB-Source"))
+	assert.Equal(t, "2026.10.17", resp.Header.Get("X-KB-Version"))
+
+	var entries []map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+	names := map[string]string{}
+	for _, e := range entries {
+		names[e["name"].(string)] = e["type"].(string)
+	}
+	assert.Equal(t, "directory", names["coredns"])
+	assert.NotContains(t, names, "index.json", "index files stay hidden from the browser")
+}
+
+func TestMissions_Mirror_GetFile(t *testing.T) {
+	app, _ := setupMirrorTest(t, map[string]string{
+		"fixes/coredns/dns-fix.json": `{"id":"dns-fix"}`,
+	})
+
+	req, err := http.NewRequest("GET", "/api/missions/file?path=fixes/coredns/dns-fix.json&ref=main", nil)
+	require.NoError(t, err)
+	resp, err := app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"id":"dns-fix"}`, string(body))
+
+	req, err = http.NewRequest("GET", "/api/missions/file?path=fixes/missing.json", nil)
+	require.NoError(t, err)
+	resp, err = app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestMissions_Mirror_ScoresFromBundleIndex(t *testing.T) {
+	app, _ := setupMirrorTest(t, map[string]string{"fixes/index.json": indexWithScores})
+
+	req, err := http.NewRequest("GET", "/api/missions/scores", nil)
+	require.NoError(t, err)
+	resp, err := app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, float64(1), body["count"])
+}
+
+func TestMissions_Mirror_NotInstalled(t *testing.T) {
+	app, _ := setupMirrorTest(t, nil)
+
+	req, err := http.NewRequest("GET", "/api/missions/browse?path=fixes", nil)
+	require.NoError(t, err)
+	resp, err := app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "kb_not_installed", body["code"])
+}
+
+func TestGetKBStatus_Mirror(t *testing.T) {
+	mockStore := new(test.MockStore)
+	userID := uuid.New()
+	mockStore.On("GetUser", userID).Return(&models.User{Role: models.UserRoleAdmin}, nil)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", userID)
+		return c.Next()
+	})
+	_, handler := setupMirrorTest(t, map[string]string{"fixes/index.json": indexWithScores})
+	handler.WithStore(mockStore)
+	handler.RegisterRoutes(app.Group("/api/missions"))
+
+	req, err := http.NewRequest("GET", "/api/missions/kb/status", nil)
+	require.NoError(t, err)
+	resp, err := app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Source string          `json:"source"`
+		Bundle kbmirror.Status `json:"bundle"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, MissionsSourceBundle, body.Source)
+	assert.True(t, body.Bundle.Installed)
+	assert.Equal(t, "2026.10.17", body.Bundle.Version)
+	assert.Equal(t, 1, body.Bundle.Files)
+
+	req, err = http.NewRequest("POST", "/api/missions/kb/sync", nil)
+	require.NoError(t, err)
+	resp, err = app.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
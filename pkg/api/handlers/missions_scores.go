@@ -15,8 +15,16 @@ import (
 // ---------- Score Exposure ----------
 
 func (h *MissionsHandler) fetchMissionIndex(c *fiber.Ctx) (*indexJsonFormat, error) {
-	cacheKey := "index:master:fixes/index.json"
-	url := fmt.Sprintf("%s/kubestellar/console-kb/master/fixes/index.json", h.githubRawURL)
+	if h.mirror != nil {
+		body, err := h.mirror.ReadFile(kbIndexPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index from bundle: %w", err)
+		}
+		return parseMissionIndex(body)
+	}
+
+	cacheKey := "index:master:" + kbIndexPath
+	url := fmt.Sprintf("%s/kubestellar/console-kb/master/%s", h.githubRawURL, kbIndexPath)
 
 	res, err := h.fetchWithCache(c, cacheKey, url, "(index json)")
 	if err != nil {
@@ -38,6 +46,10 @@ func (h *MissionsHandler) fetchMissionIndex(c *fiber.Ctx) (*indexJsonFormat, err
 		slog.Info("[missions] cache MISS, stored (index json)", "bytes", len(res.Body))
 	}
 
+	return parseMissionIndex(body)
+}
+
+func parseMissionIndex(body []byte) (*indexJsonFormat, error) {
 	var index indexJsonFormat
 	if err := json.Unmarshal(body, &index); err != nil {
 		slog.Error("[missions] failed to parse index json", "error", err)
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kubestellar/console/pkg/kbmirror"
 	"github.com/kubestellar/console/pkg/store"
 )
 
@@ -127,6 +128,10 @@ type MissionsHandler struct {
 	githubRawURL string // defaults to "https://raw.githubusercontent.com"
 	cache        *missionsResponseCache
 	store        store.Store // optional; nil disables gap tracking
+	// mirror, when set, serves console-kb from a local bundle instead of
+	// GitHub; mirrorSync pulls new bundles into it and may be nil.
+	mirror     *kbmirror.Mirror
+	mirrorSync *kbmirror.Syncer
 }
 
 // cacheStatus indicates whether a fetchWithCache result came from a fresh cache
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kubestellar/console/pkg/api/audit"
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/notifications"
+)
+
+const (
+	// defaultDeliveryListLimit and maxDeliveryListLimit bound
+	// GET /api/notifications/queue.
+	defaultDeliveryListLimit = 100
+	maxDeliveryListLimit     = 500
+)
+
+// queue returns the delivery queue or a 503 when it is not enabled.
+func (h *NotificationHandler) queue() (*notifications.Queue, error) {
+	q := h.service.Queue()
+	if q == nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Notification queue is not enabled")
+	}
+	return q, nil
+}
+
+// GetQueueStatus returns per-notifier delivery status and recent deliveries,
+// optionally filtered by ?status=pending|sent|failed|silenced.
+// GET /api/notifications/queue
+func (h *NotificationHandler) GetQueueStatus(c *fiber.Ctx) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	q, err := h.queue()
+	if err != nil {
+		return err
+	}
+	limit := defaultDeliveryListLimit
+	if raw := c.Query("limit"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			limit = min(n, maxDeliveryListLimit)
+		}
+	}
+
+	targets, err := q.TargetStatuses(c.UserContext())
+	if err != nil {
+		slog.Error("[Notifications] failed to summarize queue", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to load notification queue")
+	}
+	deliveries, err := q.Deliveries(c.UserContext(), c.Query("status"), limit)
+	if err != nil {
+		slog.Error("[Notifications] failed to list deliveries", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to load notification queue")
+	}
+	return c.JSON(fiber.Map{
+		"targets":    targets,
+		"deliveries": deliveries,
+	})
+}
+
+// RetryDelivery schedules a failed delivery for another round of attempts.
+// POST /api/notifications/queue/:id/retry
+func (h *NotificationHandler) RetryDelivery(c *fiber.Ctx) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	q, err := h.queue()
+	if err != nil {
+		return err
+	}
+	d, err := q.Retry(c.UserContext(), c.Params("id"))
+	if errors.Is(err, notifications.ErrDeliveryNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Delivery not found")
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	return c.JSON(d)
+}
+
+// ListSilences returns every silence and maintenance window.
+// GET /api/notifications/silences
+func (h *NotificationHandler) ListSilences(c *fiber.Ctx) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	q, err := h.queue()
+	if err != nil {
+		return err
+	}
+	silences, err := q.Silences(c.UserContext())
+	if err != nil {
+		slog.Error("[Notifications] failed to list silences", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list silences")
+	}
+	return c.JSON(silences)
+}
+
+// CreateSilence mutes matching alerts for a time range.
+// POST /api/notifications/silences
+func (h *NotificationHandler) CreateSilence(c *fiber.Ctx) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	q, err := h.queue()
+	if err != nil {
+		return err
+	}
+	var silence notifications.Silence
+	if err := c.BodyParser(&silence); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	silence.ID = ""
+	silence.CreatedBy = middleware.GetGitHubLogin(c)
+	if err := q.SaveSilence(c.UserContext(), &silence); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	audit.Log(c, audit.ActionCreateSilence, "notification_silence", silence.ID)
+	return c.Status(fiber.StatusCreated).JSON(silence)
+}
+
+// DeleteSilence ends a silence immediately.
+// DELETE /api/notifications/silences/:id
+func (h *NotificationHandler) DeleteSilence(c *fiber.Ctx) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	q, err := h.queue()
+	if err != nil {
+		return err
+	}
+	id := c.Params("id")
+	if err := q.DeleteSilence(c.UserContext(), id); err != nil {
+		slog.Error("[Notifications] failed to delete silence", "id", id, "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete silence")
+	}
+	audit.Log(c, audit.ActionDeleteSilence, "notification_silence", id)
+	return c.SendStatus(fiber.StatusNoContent)
+}
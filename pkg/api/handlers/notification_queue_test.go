@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationQueueHandlers(t *testing.T) {
+	env := setupTestEnv(t)
+	svc := notifications.NewService()
+	h := NewNotificationHandler(env.Store, svc)
+	env.App.Get("/api/notifications/queue", h.GetQueueStatus)
+	env.App.Get("/api/notifications/silences", h.ListSilences)
+	env.App.Post("/api/notifications/silences", h.CreateSilence)
+	env.App.Delete("/api/notifications/silences/:id", h.DeleteSilence)
+
+	req := httptest.NewRequest("GET", "/api/notifications/queue", nil)
+	resp, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 503, resp.StatusCode, "queue not enabled")
+
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "queue.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	svc.EnableQueue(db, notifications.QueueOptions{})
+	svc.RegisterWebhookNotifier("ops", "https://hooks.example.com/ops")
+	require.NoError(t, svc.SendAlert(notifications.Alert{ID: "a1", RuleName: "Crash loops", Cluster: "prod", Message: "api crashing"}))
+
+	req = httptest.NewRequest("GET", "/api/notifications/queue?status=pending", nil)
+	resp2, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp2.Body.Close() })
+	assert.Equal(t, 200, resp2.StatusCode)
+	var status struct {
+		Targets    []notifications.TargetStatus `json:"targets"`
+		Deliveries []notifications.Delivery     `json:"deliveries"`
+	}
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&status))
+	require.Len(t, status.Deliveries, 1)
+	assert.Equal(t, "webhook:ops", status.Deliveries[0].Target)
+	assert.Equal(t, "a1", status.Deliveries[0].Alerts[0].ID)
+	require.Len(t, status.Targets, 1)
+	assert.Equal(t, 1, status.Targets[0].Pending)
+
+	req = httptest.NewRequest("POST", "/api/notifications/silences", strings.NewReader(`{"matchers":[{"name":"pod","value":"x"}],"endsAt":"2099-01-01T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp3, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp3.Body.Close() })
+	assert.Equal(t, 400, resp3.StatusCode)
+
+	endsAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	body := fmt.Sprintf(`{"kind":"maintenance","matchers":[{"name":"cluster","operator":"=~","value":"staging-.*"}],"endsAt":%q,"comment":"upgrade"}`, endsAt)
+	req = httptest.NewRequest("POST", "/api/notifications/silences", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp4, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp4.Body.Close() })
+	assert.Equal(t, 201, resp4.StatusCode)
+	var created notifications.Silence
+	require.NoError(t, json.NewDecoder(resp4.Body).Decode(&created))
+	assert.NotEmpty(t, created.ID)
+	assert.False(t, created.StartsAt.IsZero(), "start defaults to now")
+
+	req = httptest.NewRequest("GET", "/api/notifications/silences", nil)
+	resp5, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp5.Body.Close() })
+	var silences []notifications.Silence
+	require.NoError(t, json.NewDecoder(resp5.Body).Decode(&silences))
+	require.Len(t, silences, 1)
+	assert.Equal(t, notifications.SilenceKindMaintenance, silences[0].Kind)
+
+	req = httptest.NewRequest("DELETE", "/api/notifications/silences/"+created.ID, nil)
+	resp6, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp6.Body.Close() })
+	assert.Equal(t, 204, resp6.StatusCode)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/kubestellar/console/pkg/api/audit"
@@ -31,6 +32,14 @@ type TestNotificationRequest struct {
 	Config map[string]interface{} `json:"config"`
 }
 
+// PreviewTemplateRequest is the body of POST /api/notifications/templates/preview.
+// Alert is optional; a sample alert is rendered when it is omitted.
+type PreviewTemplateRequest struct {
+	TitleTemplate string               `json:"titleTemplate"`
+	BodyTemplate  string               `json:"bodyTemplate"`
+	Alert         *notifications.Alert `json:"alert,omitempty"`
+}
+
 // SendAlertNotificationRequest represents a request to send an alert notification
 type SendAlertNotificationRequest struct {
 	Alert    notifications.Alert                 `json:"alert"`
@@ -85,6 +94,55 @@ func (h *NotificationHandler) TestNotification(c *fiber.Ctx) error {
 	})
 }
 
+// PreviewTemplate renders message templates against an alert so they can be
+// checked while editing, without sending anything.
+// POST /api/notifications/templates/preview
+func (h *NotificationHandler) PreviewTemplate(c *fiber.Ctx) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+
+	var req PreviewTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	tmpl, err := notifications.ParseMessageTemplate(req.TitleTemplate, req.BodyTemplate)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	alert := notifications.Alert{
+		ID:           "preview",
+		RuleID:       "preview-rule",
+		RuleName:     "Pod crash looping",
+		Severity:     notifications.SeverityWarning,
+		Status:       notifications.AlertStatusFiring,
+		Message:      "api-7d9f4 restarted 5 times in 10 minutes",
+		Cluster:      "prod-east",
+		Namespace:    "shop",
+		Resource:     "api-7d9f4",
+		ResourceKind: "Pod",
+		FiredAt:      time.Now().UTC(),
+	}
+	if req.Alert != nil {
+		alert = *req.Alert
+	}
+	title, body, err := tmpl.Execute(alert)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"title": title,
+		"body":  body,
+	})
+}
+
 // SendAlertNotification sends an alert notification to configured channels
 // POST /api/notifications/send
 func (h *NotificationHandler) SendAlertNotification(c *fiber.Ctx) error {
@@ -24,6 +24,7 @@ func TestNotificationHandlers(t *testing.T) {
 	env.App.Post("/api/notifications/test", h.TestNotification)
 	env.App.Post("/api/notifications/send", h.SendAlertNotification)
 	env.App.Get("/api/notifications/config", h.GetNotificationConfig)
+	env.App.Post("/api/notifications/templates/preview", h.PreviewTemplate)
 
 	t.Run("TestNotification - Admin Required", func(t *testing.T) {
 		// Non-admin user
@@ -60,6 +61,24 @@ func TestNotificationHandlers(t *testing.T) {
 		assert.True(t, result["success"].(bool))
 	})
 
+	t.Run("PreviewTemplate", func(t *testing.T) {
+		body := `{"titleTemplate":"{{.Cluster}}: {{.RuleName}}","bodyTemplate":"{{upper .Message}}","alert":{"ruleName":"Crash","cluster":"prod","message":"api down"}}`
+		req := httptest.NewRequest("POST", "/api/notifications/templates/preview", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := env.App.Test(req)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result map[string]string
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Equal(t, "prod: Crash", result["title"])
+		assert.Equal(t, "API DOWN", result["body"])
+
+		req = httptest.NewRequest("POST", "/api/notifications/templates/preview", strings.NewReader(`{"titleTemplate":"{{.Nope}}"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ = env.App.Test(req)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
 	t.Run("GetNotificationConfig", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/api/notifications/config", nil)
 		resp, _ := env.App.Test(req)
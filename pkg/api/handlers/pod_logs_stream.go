@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/safego"
+)
+
+// podLogFollowDeadline is the maximum wall-clock time a follow-mode log
+// stream stays open. Clients reconnect with ?since= to continue.
+const podLogFollowDeadline = time.Hour
+
+// podLogReadDeadline bounds a non-follow stream, which ends on its own once
+// every selected container's log has been read.
+const podLogReadDeadline = 2 * time.Minute
+
+// podLogHeartbeatInterval is how often an SSE comment is sent on an idle
+// stream so proxies do not close it.
+const podLogHeartbeatInterval = 15 * time.Second
+
+// podLogDefaultTail is the number of existing lines each container starts
+// with when neither tail nor since is given.
+const podLogDefaultTail = 100
+
+// podLogMaxFilters caps the include/exclude patterns per request.
+const podLogMaxFilters = 16
+
+// podLogMaxPatternLen caps the length of a single regex query parameter.
+const podLogMaxPatternLen = 512
+
+// podLogMaxListItems caps the comma-separated clusters and namespaces.
+const podLogMaxListItems = 50
+
+// SSE event names of the pod log stream.
+const (
+	sseEventLog         = "log"
+	sseEventTailStarted = "tail_started"
+	sseEventTailStopped = "tail_stopped"
+)
+
+// podLogStreamEvent is the SSE payload of one log line or tail change.
+type podLogStreamEvent struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	Line      string `json:"line,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// StreamPodLogs streams the logs of every container selected by a label
+// selector across clusters and namespaces via SSE, stern-style. Lines from
+// all containers are interleaved as "log" events carrying a
+// "cluster namespace/pod container" prefix.
+//
+// Query parameters:
+//   - clusters: comma-separated clusters (default: all healthy clusters)
+//   - namespaces: comma-separated namespaces (default: all)
+//   - selector: label selector, e.g. app=web
+//   - container: regex matched against container names
+//   - include, exclude: regexes (repeatable) a line must / must not match
+//   - since: duration (10m) or RFC 3339 time to start from
+//   - tail: existing lines per container (default 100 unless since is set)
+//   - previous: tail the previous instance of each container
+//   - follow: keep streaming and pick up new pods (default true)
+//   - timestamps: prefix lines with their timestamp
+func (h *MCPHandlers) StreamPodLogs(c *fiber.Ctx) error {
+	opts, err := parsePodLogStreamOptions(c)
+	if err != nil {
+		return err
+	}
+	if isDemoMode(c) {
+		return streamDemoPodLogs(c)
+	}
+	if h.k8sClient == nil {
+		return errNoClusterAccess(c)
+	}
+
+	if len(opts.Clusters) == 0 {
+		healthy, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			slog.Error("[PodLogs] internal error", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range healthy {
+			opts.Clusters = append(opts.Clusters, cl.Name)
+		}
+		if len(opts.Clusters) == 0 {
+			return streamEmptySSE(c)
+		}
+	}
+
+	// Captured before SetBodyStreamWriter; the fiber.Ctx may be reused by
+	// the time the callback runs (#6029, #6480).
+	userID := middleware.GetUserID(c)
+	requestCtx := c.UserContext()
+	deadline := podLogReadDeadline
+	if opts.Follow {
+		deadline = podLogFollowDeadline
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		streamCtx, streamCancel := context.WithTimeout(requestCtx, deadline)
+		defer streamCancel()
+		if userID != uuid.Nil {
+			sessionID := registerSSESession(userID, streamCancel)
+			defer unregisterSSESession(userID, sessionID)
+		}
+
+		// The heartbeat writes from its own goroutine, so every write to w
+		// goes through mu.
+		var mu sync.Mutex
+		emitEvent := func(name string, data interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			if streamCtx.Err() != nil {
+				return
+			}
+			if err := writeSSEEvent(w, name, data); err != nil {
+				slog.Info("[PodLogs] write failed, cancelling stream", "event", name, "error", err)
+				streamCancel()
+			}
+		}
+
+		safego.GoWith("pod-logs-heartbeat", func() {
+			ticker := time.NewTicker(podLogHeartbeatInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-streamCtx.Done():
+					return
+				case <-ticker.C:
+					mu.Lock()
+					_, err := w.WriteString(": ping\n\n")
+					if err == nil {
+						err = w.Flush()
+					}
+					mu.Unlock()
+					if err != nil {
+						streamCancel()
+						return
+					}
+				}
+			}
+		})
+
+		_ = h.k8sClient.StreamPodLogs(streamCtx, opts, func(e k8s.PodLogEvent) {
+			payload := podLogStreamEvent{
+				Cluster:   e.Cluster,
+				Namespace: e.Namespace,
+				Pod:       e.Pod,
+				Container: e.Container,
+				Line:      e.Line,
+				Error:     e.Error,
+			}
+			if e.Pod != "" {
+				payload.Prefix = e.Prefix()
+			}
+			switch e.Type {
+			case k8s.PodLogLine:
+				emitEvent(sseEventLog, payload)
+			case k8s.PodLogTailStarted:
+				emitEvent(sseEventTailStarted, payload)
+			case k8s.PodLogTailStopped:
+				emitEvent(sseEventTailStopped, payload)
+			case k8s.PodLogError:
+				emitEvent(sseEventClusterError, payload)
+			}
+		})
+
+		reason := "completed"
+		if err := streamCtx.Err(); err == context.DeadlineExceeded {
+			reason = "deadline"
+		} else if err != nil {
+			reason = "cancelled"
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if err := writeSSEEvent(w, sseEventDone, fiber.Map{"reason": reason}); err != nil {
+			slog.Info("[PodLogs] stream write failed", "event", sseEventDone, "error", err)
+		}
+	})
+
+	return nil
+}
+
+// parsePodLogStreamOptions validates the StreamPodLogs query parameters.
+func parsePodLogStreamOptions(c *fiber.Ctx) (k8s.PodLogStreamOptions, error) {
+	opts := k8s.PodLogStreamOptions{
+		Previous:   c.QueryBool("previous", false),
+		Follow:     c.QueryBool("follow", true),
+		Timestamps: c.QueryBool("timestamps", false),
+	}
+	if opts.Previous {
+		// A terminated container's log is complete; there is nothing to follow.
+		opts.Follow = false
+	}
+
+	var err error
+	if opts.Clusters, err = splitPodLogList("clusters", c.Query("clusters"), "cluster"); err != nil {
+		return opts, err
+	}
+	if opts.Namespaces, err = splitPodLogList("namespaces", c.Query("namespaces"), "namespace"); err != nil {
+		return opts, err
+	}
+
+	opts.LabelSelector = c.Query("selector")
+	if err := mcpValidateLabelSelector(opts.LabelSelector); err != nil {
+		return opts, err
+	}
+
+	if container := c.Query("container"); container != "" {
+		if opts.Container, err = compilePodLogPattern("container", container); err != nil {
+			return opts, err
+		}
+	}
+	if opts.Include, err = compilePodLogPatterns(c, "include"); err != nil {
+		return opts, err
+	}
+	if opts.Exclude, err = compilePodLogPatterns(c, "exclude"); err != nil {
+		return opts, err
+	}
+
+	if since := c.Query("since"); since != "" {
+		var t time.Time
+		if d, derr := time.ParseDuration(since); derr == nil && d > 0 {
+			t = time.Now().Add(-d)
+		} else if t, err = time.Parse(time.RFC3339, since); err != nil {
+			return opts, fiber.NewError(fiber.StatusBadRequest,
+				"invalid since: must be a positive duration (e.g. 10m) or an RFC 3339 time")
+		}
+		opts.SinceTime = &t
+	}
+
+	tail := c.QueryInt("tail", 0)
+	if err := mcpValidatePositiveInt("tail", tail, mcpMaxTailLines); err != nil {
+		return opts, err
+	}
+	if tail == 0 && opts.SinceTime == nil {
+		tail = podLogDefaultTail
+	}
+	if tail > 0 {
+		tailLines := int64(tail)
+		opts.TailLines = &tailLines
+	}
+	return opts, nil
+}
+
+// splitPodLogList splits a comma-separated list of Kubernetes names.
+func splitPodLogList(param, value, itemParam string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if err := mcpValidateName(itemParam, item); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	if len(out) > podLogMaxListItems {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid %s: at most %d entries", param, podLogMaxListItems))
+	}
+	return out, nil
+}
+
+// compilePodLogPatterns compiles every value of a repeatable regex query
+// parameter.
+func compilePodLogPatterns(c *fiber.Ctx, param string) ([]*regexp.Regexp, error) {
+	values := c.Context().QueryArgs().PeekMulti(param)
+	if len(values) > podLogMaxFilters {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid %s: at most %d patterns", param, podLogMaxFilters))
+	}
+	var out []*regexp.Regexp
+	for _, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+		re, err := compilePodLogPattern(param, string(v))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+func compilePodLogPattern(param, pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > podLogMaxPatternLen {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid %s: exceeds maximum length of %d characters", param, podLogMaxPatternLen))
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid %s: %v", param, err))
+	}
+	return re, nil
+}
+
+// streamDemoPodLogs replays the demo log as a finished stream.
+func streamDemoPodLogs(c *fiber.Ctx) error {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ev := podLogStreamEvent{Cluster: "demo", Namespace: "default", Pod: "api-server-7d8f9c6b5-x2k4m", Container: "api"}
+		ev.Prefix = ev.Cluster + " " + ev.Namespace + "/" + ev.Pod + " " + ev.Container
+		for _, line := range strings.Split(getDemoPodLogs(), "\n") {
+			ev.Line = line
+			if err := writeSSEEvent(w, sseEventLog, ev); err != nil {
+				slog.Info("[PodLogs] demo stream write failed", "event", sseEventLog, "error", err)
+				return
+			}
+		}
+		if err := writeSSEEvent(w, sseEventDone, fiber.Map{"reason": "completed", "source": "demo"}); err != nil {
+			slog.Info("[PodLogs] demo stream write failed", "event", sseEventDone, "error", err)
+		}
+	})
+	return nil
+}
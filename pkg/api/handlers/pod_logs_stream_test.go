@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func seedLogPod(name, namespace, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "img"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:        "main",
+				ContainerID: "containerd://" + name,
+				State:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now()}},
+			}},
+		},
+	}
+}
+
+func TestStreamPodLogs(t *testing.T) {
+	env := setupTestEnv(t)
+	injectTypedCluster(env, "cluster-a", seedLogPod("web-a", "default", "web"), seedLogPod("db-a", "default", "db"))
+	injectTypedCluster(env, "cluster-b", seedLogPod("web-b", "prod", "web"))
+
+	handler := NewMCPHandlers(nil, env.K8sClient, nil)
+	env.App.Get("/api/mcp/pods/logs/stream", handler.StreamPodLogs)
+
+	get := func(query url.Values) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, "/api/mcp/pods/logs/stream?"+query.Encode(), nil)
+		require.NoError(t, err)
+		resp, err := env.App.Test(req, sseTestTimeoutMs)
+		require.NoError(t, err)
+		return resp.StatusCode, readSSEBody(t, resp)
+	}
+
+	t.Run("selector across clusters", func(t *testing.T) {
+		status, body := get(url.Values{
+			"clusters": {"cluster-a,cluster-b"},
+			"selector": {"app=web"},
+			"follow":   {"false"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"prefix":"cluster-a default/web-a main"`)
+		assert.Contains(t, body, `"prefix":"cluster-b prod/web-b main"`)
+		assert.Contains(t, body, `"line":"fake logs"`)
+		assert.NotContains(t, body, "db-a")
+		assert.Contains(t, body, "event: "+sseEventDone)
+	})
+
+	t.Run("exclude drops lines", func(t *testing.T) {
+		status, body := get(url.Values{
+			"clusters": {"cluster-a"},
+			"follow":   {"false"},
+			"exclude":  {"^fake"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, "event: "+sseEventTailStarted)
+		assert.NotContains(t, body, "event: "+sseEventLog+"\n")
+	})
+
+	t.Run("namespace filter", func(t *testing.T) {
+		status, body := get(url.Values{
+			"clusters":   {"cluster-a,cluster-b"},
+			"namespaces": {"prod"},
+			"follow":     {"false"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, "web-b")
+		assert.NotContains(t, body, "web-a")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, q := range []url.Values{
+			{"include": {"("}},
+			{"container": {"[a-"}},
+			{"since": {"yesterday"}},
+			{"tail": {"-1"}},
+			{"clusters": {"Bad_Name"}},
+			{"selector": {"app=web;rm"}},
+		} {
+			status, _ := get(q)
+			assert.Equal(t, http.StatusBadRequest, status, "query %v", q)
+		}
+	})
+}
@@ -484,3 +484,56 @@ func TestSetTerminalStatus(t *testing.T) {
 	assert.Len(t, wd.Status.History, 2)
 	assert.Equal(t, 2, wd.Status.History[1].Revision)
 }
+
+func TestReconcileDeployment_DryRunUsesRolloutPlan(t *testing.T) {
+	// A dry-run RollingUpdate must record the plan without calling the
+	// all-at-once deployer, and must record the observed generation so the
+	// watcher ignores the resulting status-only MODIFIED events.
+	mw := &v1alpha1.ManagedWorkload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "ManagedWorkload",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "test-ns"},
+		Spec: v1alpha1.ManagedWorkloadSpec{
+			SourceCluster:   "source-cluster",
+			SourceNamespace: "default",
+			WorkloadRef: v1alpha1.WorkloadReference{
+				Kind: "Deployment",
+				Name: "nginx",
+			},
+		},
+	}
+	mwU, _ := mw.ToUnstructured()
+
+	wd := &v1alpha1.WorkloadDeployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "WorkloadDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "wd-dry", Namespace: "test-ns", Generation: 3},
+		Spec: v1alpha1.WorkloadDeploymentSpec{
+			WorkloadRef:    v1alpha1.ResourceReference{Name: "my-app"},
+			TargetClusters: []string{"cluster-b", "cluster-a"},
+			Strategy:       k8s.StrategyRollingUpdate,
+			DryRun:         true,
+		},
+	}
+	wdU, _ := wd.ToUnstructured()
+
+	h, _ := setupReconcileEnv(t, mwU, wdU)
+	h.deployer = &fakeDeployer{err: fmt.Errorf("deployer must not be called for a dry run")}
+
+	h.reconcileDeployment(context.Background(), wd)
+
+	assert.Equal(t, "Complete", wd.Status.Phase)
+	assert.Equal(t, int64(3), wd.Status.ObservedGeneration)
+	require.Len(t, wd.Status.ClusterStatuses, 2)
+	assert.Equal(t, "cluster-a", wd.Status.ClusterStatuses[0].Cluster)
+	for _, cs := range wd.Status.ClusterStatuses {
+		assert.Equal(t, "Skipped", cs.Phase)
+		assert.Contains(t, cs.Message, "Dry run")
+	}
+	require.Len(t, wd.Status.History, 1)
+	assert.Contains(t, wd.Status.History[0].Message, "nothing was applied")
+}
@@ -10,7 +10,8 @@ package handlers
 // of hard-coded demo numbers. Splunk / Elastic / Syslog remain stubs that
 // surface a structured "destination not yet supported" error.
 //
-// TODO (#9643): Wire live export engine once pkg/api/audit/export.go engine is complete.
+// Delivery itself runs in the spooled pipeline in pkg/api/audit; these
+// endpoints report its live per-destination status.
 
 import (
 	"time"
@@ -61,6 +61,9 @@ type sseClusterStreamConfig struct {
 	// (matched against ClusterInfo.Name). If the named cluster is not present
 	// in the dedupe set the handler responds with 404 (#6039).
 	clusterFilter string
+	// watch is the informer resource whose changes re-push a cluster's data
+	// when the client asks for ?watch=true. Empty disables watch mode.
+	watch k8s.InformerResource
 }
 
 // writeSSEEvent writes one SSE event to the buffered writer and flushes.
@@ -95,6 +98,22 @@ const ssePerClusterTimeout = 10 * time.Second
 // sseSlowClusterTimeout is a reduced timeout for clusters that recently timed out.
 const sseSlowClusterTimeout = 3 * time.Second
 
+// sseWatchDeadline is how long a ?watch=true stream stays open. EventSource
+// reconnects on its own once it closes.
+const sseWatchDeadline = 30 * time.Minute
+
+// sseWatchDebounce coalesces a burst of informer changes into one refetch
+// per cluster.
+const sseWatchDebounce = time.Second
+
+// sseWatchHeartbeat is how often an idle watch stream writes a comment so
+// proxies do not time it out.
+const sseWatchHeartbeat = 15 * time.Second
+
+// sseSourceWatch is the cluster_data source for data re-sent on a watch
+// stream after an informer change.
+const sseSourceWatch = "watch"
+
 // sseCacheTTL is how long cached SSE responses are considered fresh.
 const sseCacheTTL = 15 * time.Second
 
@@ -255,6 +274,26 @@ func StopSSECacheEvictor() {
 	}
 }
 
+// writeSSEComment writes an SSE comment line, which clients ignore, to keep
+// an idle stream alive.
+func writeSSEComment(w *bufio.Writer, text string) error {
+	sanitized := strings.NewReplacer("\n", "", "\r", "").Replace(text)
+	if _, err := fmt.Fprintf(w, ": %s\n\n", sanitized); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flush: %w", err)
+	}
+	return nil
+}
+
+// sseCacheKey keys a user's cached per-cluster stream data. #7044 — the user
+// ID prevents cross-user data leakage between different roles (e.g. admin
+// vs viewer); the namespace prevents cross-namespace leakage (#4151).
+func sseCacheKey(userID uuid.UUID, cfg sseClusterStreamConfig, cluster string) string {
+	return userID.String() + ":" + cfg.demoKey + ":" + cluster + ":" + cfg.namespace
+}
+
 func sseCacheGet(key string) interface{} {
 	// Fast path: take a read lock for the common case (entry exists and is
 	// fresh). Previously this used an exclusive Lock which serialized every
@@ -313,6 +352,10 @@ func sseCacheSet(key string, data interface{}) {
 //   - Cached results (< 15s old) are served instantly without goroutines
 //   - Clusters that recently timed out get a reduced 3s timeout
 //   - Clusters exceeding 5s are marked slow for future requests
+//
+// With ?watch=true on a stream that names an informer resource, and the
+// informer cache enabled, the stream stays open after "done" and re-sends a
+// cluster's data whenever its informer reports a change (see followClusters).
 func streamClusters(
 	c *fiber.Ctx,
 	h *MCPHandlers,
@@ -360,6 +403,17 @@ func streamClusters(
 		offline = filteredOffline
 	}
 
+	// Subscribe before the snapshot is taken so no change between the
+	// snapshot and the follow loop is missed. nil when the informer cache
+	// is disabled, in which case the stream ends after "done" as usual.
+	var sub *k8s.InformerSubscription
+	deadline := sseOverallDeadline
+	if cfg.watch != "" && c.QueryBool("watch") {
+		if sub = h.k8sClient.SubscribeInformerChanges(); sub != nil {
+			deadline = sseWatchDeadline
+		}
+	}
+
 	// Capture the authenticated user ID before entering the deferred
 	// SetBodyStreamWriter callback. The fiber.Ctx may be reused by the time
 	// the callback runs, so c.Locals is not safe to read inside it (#6029).
@@ -388,8 +442,9 @@ func streamClusters(
 		// disconnect-driven cancellation was a lie: nothing ever cancelled
 		// the goroutines on client disconnect — only the overall deadline or
 		// a Logout fired cancel().
-		streamCtx, streamCancel := context.WithTimeout(requestCtx, sseOverallDeadline)
+		streamCtx, streamCancel := context.WithTimeout(requestCtx, deadline)
 		defer streamCancel()
+		defer sub.Close()
 
 		// Register this stream's cancel with the per-user SSE session
 		// registry so a later Logout call can tear the stream down promptly
@@ -439,13 +494,14 @@ func streamClusters(
 		// Spawn goroutines only for healthy/unknown clusters
 		var wg sync.WaitGroup
 		for _, cl := range healthy {
-			// #7044 — Include user ID in cache key to prevent cross-user data
-			// leakage between different roles (e.g. admin vs viewer).
-			// Also includes namespace to prevent cross-namespace data leakage (#4151).
-			cacheKey := userID.String() + ":" + cfg.demoKey + ":" + cl.Name + ":" + cfg.namespace
-
-			// Check response cache — serve instantly if fresh
-			if cached := sseCacheGet(cacheKey); cached != nil {
+			cacheKey := sseCacheKey(userID, cfg, cl.Name)
+
+			// Check response cache — serve instantly if fresh. Clusters
+			// served by a synced informer are skipped: the cache would only
+			// add up to sseCacheTTL of staleness to data that is already
+			// local.
+			fromInformer := cfg.watch != "" && h.k8sClient.InformerSynced(cl.Name, cfg.watch)
+			if cached := sseCacheGet(cacheKey); cached != nil && !fromInformer {
 				mu.Lock()
 				completedClusters++
 				ok := emitEvent(sseEventClusterData, fiber.Map{
@@ -556,17 +612,94 @@ func streamClusters(
 		}
 
 		mu.Lock()
-		emitEvent(sseEventDone, fiber.Map{
+		ok := emitEvent(sseEventDone, fiber.Map{
 			"totalClusters":     totalClusters,
 			"completedClusters": completedClusters,
 			"skippedOffline":    len(offline),
 		})
 		mu.Unlock()
+
+		if ok && sub != nil {
+			clusters := make([]k8s.ClusterInfo, 0, totalClusters)
+			clusters = append(clusters, healthy...)
+			clusters = append(clusters, offline...)
+			followClusters(streamCtx, w, h, sub, cfg, clusters, userID, fetchFn)
+		}
 	})
 
 	return nil
 }
 
+// followClusters keeps a ?watch=true stream open after its initial snapshot.
+// Whenever the informer for cfg.watch reports a change on one of the
+// stream's clusters, that cluster's data is refetched from the informer
+// cache and re-sent as a "cluster_data" event with source "watch". It
+// returns when the client disconnects or the stream deadline passes.
+func followClusters(
+	ctx context.Context,
+	w *bufio.Writer,
+	h *MCPHandlers,
+	sub *k8s.InformerSubscription,
+	cfg sseClusterStreamConfig,
+	clusters []k8s.ClusterInfo,
+	userID uuid.UUID,
+	fetchFn func(ctx context.Context, clusterName string) (interface{}, error),
+) {
+	inStream := make(map[string]bool, len(clusters))
+	for _, cl := range clusters {
+		inStream[cl.Name] = true
+	}
+	heartbeat := time.NewTicker(sseWatchHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if err := writeSSEComment(w, "keepalive"); err != nil {
+				return
+			}
+		case <-sub.C:
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(sseWatchDebounce):
+			}
+			for _, change := range sub.Drain() {
+				// Only push clusters the informer can serve; refetching an
+				// unsynced cluster would turn every change into a LIST.
+				if change.Resource != cfg.watch || !inStream[change.Cluster] ||
+					!h.k8sClient.InformerSynced(change.Cluster, cfg.watch) {
+					continue
+				}
+				fetchCtx, cancel := context.WithTimeout(ctx, cfg.clusterTimeout)
+				data, err := fetchFn(fetchCtx, change.Cluster)
+				cancel()
+				if err != nil {
+					slog.Warn("[SSE] watch refetch failed", "cluster", change.Cluster, "stream", cfg.demoKey, "error", err)
+					if writeSSEEvent(w, sseEventClusterError, fiber.Map{
+						"cluster": change.Cluster,
+						"error":   "cluster query failed",
+					}) != nil {
+						return
+					}
+					continue
+				}
+				sseCacheSet(sseCacheKey(userID, cfg, change.Cluster), data)
+				if err := writeSSEEvent(w, sseEventClusterData, fiber.Map{
+					"cluster":   change.Cluster,
+					cfg.demoKey: data,
+					"source":    sseSourceWatch,
+				}); err != nil {
+					slog.Info("[SSE] watch write failed, closing stream", "error", err)
+					return
+				}
+			}
+		}
+	}
+}
+
 // streamEmptySSE returns an empty SSE stream with just a done event.
 // Used when no clusters are configured to avoid error states on the frontend.
 func streamEmptySSE(c *fiber.Ctx) error {
@@ -632,6 +765,7 @@ func (h *MCPHandlers) GetPodsStream(c *fiber.Ctx) error {
 		demoKey:        "pods",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerPods,
 		clusterFilter:  clusterFilter,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		pods, err := h.k8sClient.GetPods(ctx, cluster, namespace)
@@ -657,6 +791,7 @@ func (h *MCPHandlers) FindPodIssuesStream(c *fiber.Ctx) error {
 		demoKey:        "issues",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerPods,
 		clusterFilter:  clusterFilter,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		issues, err := h.k8sClient.FindPodIssues(ctx, cluster, namespace)
@@ -682,6 +817,7 @@ func (h *MCPHandlers) GetDeploymentsStream(c *fiber.Ctx) error {
 		demoKey:        "deployments",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerDeployments,
 		clusterFilter:  clusterFilter,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		deps, err := h.k8sClient.GetDeployments(ctx, cluster, namespace)
@@ -715,6 +851,7 @@ func (h *MCPHandlers) GetEventsStream(c *fiber.Ctx) error {
 		demoKey:        "events",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerEvents,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		events, err := h.k8sClient.GetEvents(ctx, cluster, namespace, limit)
 		if err != nil {
@@ -738,6 +875,7 @@ func (h *MCPHandlers) GetServicesStream(c *fiber.Ctx) error {
 		demoKey:        "services",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerServices,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		svcs, err := h.k8sClient.GetServices(ctx, cluster, namespace)
 		if err != nil {
@@ -787,6 +925,7 @@ func (h *MCPHandlers) FindDeploymentIssuesStream(c *fiber.Ctx) error {
 		demoKey:        "issues",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerDeployments,
 		clusterFilter:  clusterFilter,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		issues, err := h.k8sClient.FindDeploymentIssues(ctx, cluster, namespace)
@@ -810,6 +949,7 @@ func (h *MCPHandlers) GetNodesStream(c *fiber.Ctx) error {
 	return streamClusters(c, h, sseClusterStreamConfig{
 		demoKey:        "nodes",
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerNodes,
 		clusterFilter:  clusterFilter,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		return h.k8sClient.GetNodes(ctx, cluster)
@@ -879,6 +1019,7 @@ func (h *MCPHandlers) GetWarningEventsStream(c *fiber.Ctx) error {
 		demoKey:        "events",
 		namespace:      namespace,
 		clusterTimeout: ssePerClusterTimeout,
+		watch:          k8s.InformerEvents,
 		clusterFilter:  clusterFilter,
 	}, func(ctx context.Context, cluster string) (interface{}, error) {
 		return h.k8sClient.GetWarningEvents(ctx, cluster, namespace, limit)
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
@@ -15,6 +16,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/api/middleware"
 	"github.com/kubestellar/console/pkg/k8s"
 	"github.com/kubestellar/console/pkg/safego"
@@ -35,6 +37,7 @@ const (
 	stellarMaxScheduleLength      = 128
 	stellarMaxToolsPerMission     = 32
 	stellarMaxToolNameLength      = 64
+	stellarMaxScopeNameLength     = 253
 	stellarMaxPromptLength        = 5000
 	stellarMaxProviderBaseURLLen  = 2048
 	stellarDigestLookbackHours    = 24
@@ -89,6 +92,7 @@ type StellarStore interface {
 	GetStellarMission(ctx context.Context, userID string, missionID string) (*store.StellarMission, error)
 	CreateStellarMission(ctx context.Context, mission *store.StellarMission) error
 	UpdateStellarMission(ctx context.Context, mission *store.StellarMission) error
+	SetStellarMissionRunTimes(ctx context.Context, missionID string, lastRunAt, nextRunAt *time.Time) error
 	DeleteStellarMission(ctx context.Context, userID string, missionID string) error
 
 	ListStellarExecutions(ctx context.Context, userID, missionID, status string, limit, offset int) ([]store.StellarExecution, error)
@@ -104,6 +108,7 @@ type StellarStore interface {
 	CompleteDueStellarActions(ctx context.Context, now time.Time) ([]store.StellarAction, error)
 	GetDueApprovedStellarActions(ctx context.Context, now time.Time, limit int) ([]store.StellarAction, error)
 	UpdateStellarActionStatus(ctx context.Context, actionID, status, outcome, rejectReason string) error
+	RescheduleStellarAction(ctx context.Context, actionID string, next time.Time) error
 
 	ListStellarMemoryEntries(ctx context.Context, userID, cluster, category string, limit, offset int) ([]store.StellarMemoryEntry, error)
 	SearchStellarMemoryEntries(ctx context.Context, userID, query string, limit int) ([]store.StellarMemoryEntry, error)
@@ -611,7 +616,27 @@ func (h *StellarHandler) resolveProviderAndModel(ctx context.Context, userID, pr
 	if err != nil {
 		return providers.ResolvedProvider{}, err
 	}
-	return h.providerRegistry.Resolve(preferredProvider, preferredModel, userCfg), nil
+	return h.providerRegistry.ResolveForUser(stellarBudgetUserID(userID), preferredProvider, preferredModel, userCfg), nil
+}
+
+// stellarBudgetUserID is the user whose daily token budget a Stellar call
+// counts against. Stellar user IDs use the same keys as the token-usage
+// rows; background work runs as "system", which has no personal budget.
+func stellarBudgetUserID(userID string) string {
+	if userID == "system" {
+		return ""
+	}
+	return userID
+}
+
+// respondIfBudgetExceeded answers 429 when err is a spent daily AI token
+// budget. handled is false for any other error.
+func respondIfBudgetExceeded(c *fiber.Ctx, err error) (handled bool, respErr error) {
+	var budgetErr *airouter.BudgetError
+	if !errors.As(err, &budgetErr) {
+		return false, nil
+	}
+	return true, c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": budgetErr.Error()})
 }
 
 func (h *StellarHandler) resolveUserProvider(ctx context.Context, userID string) (*providers.ResolvedUserProvider, error) {
@@ -79,6 +79,11 @@ func (h *StellarHandler) CreateAction(c *fiber.Ctx) error {
 	if body.Description == "" || body.ActionType == "" || body.Cluster == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "description, actionType, and cluster are required"})
 	}
+	if body.CronExpr != "" {
+		if err := scheduler.ValidateSchedule(body.CronExpr); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid cronExpr: " + err.Error()})
+		}
+	}
 	parametersJSON, err := json.Marshal(body.Parameters)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid parameters"})
@@ -467,6 +472,9 @@ func (h *StellarHandler) executeLLMAction(c *fiber.Ctx, userID string, body exec
 	})
 	durationMs := int(time.Since(startTime).Milliseconds())
 
+	if handled, respErr := respondIfBudgetExceeded(c, genErr); handled {
+		return respErr
+	}
 	if genErr != nil {
 		slog.Error("stellar: action-execute LLM call failed", "error", genErr, "userID", userID)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "AI provider error"})
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/kubestellar/console/pkg/stellar/scheduler"
 	"github.com/kubestellar/console/pkg/store"
 )
 
@@ -50,6 +53,8 @@ type upsertStellarMissionRequest struct {
 	MemoryScope    string   `json:"memoryScope"`
 	Enabled        bool     `json:"enabled"`
 	ToolBindings   []string `json:"toolBindings"`
+	Cluster        string   `json:"cluster"`
+	Namespace      string   `json:"namespace"`
 }
 
 func (h *StellarHandler) CreateMission(c *fiber.Ctx) error {
@@ -62,6 +67,7 @@ func (h *StellarHandler) CreateMission(c *fiber.Ctx) error {
 		return err
 	}
 	mission.UserID = userID
+	mission.NextRunAt = h.nextMissionRun(c.UserContext(), mission)
 	if err := h.store.CreateStellarMission(c.UserContext(), mission); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create mission"})
 	}
@@ -98,6 +104,9 @@ func (h *StellarHandler) UpdateMission(c *fiber.Ctx) error {
 	mission.CreatedAt = existing.CreatedAt
 	mission.LastRunAt = existing.LastRunAt
 	mission.NextRunAt = existing.NextRunAt
+	if mission.Schedule != existing.Schedule || mission.Enabled != existing.Enabled || mission.NextRunAt == nil {
+		mission.NextRunAt = h.nextMissionRun(c.UserContext(), mission)
+	}
 
 	if err := h.store.UpdateStellarMission(c.UserContext(), mission); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update mission"})
@@ -123,6 +132,24 @@ func (h *StellarHandler) DeleteMission(c *fiber.Ctx) error {
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// nextMissionRun computes the first cron fire for a mission in the owner's
+// timezone, or nil when the mission is disabled or unscheduled.
+func (h *StellarHandler) nextMissionRun(ctx context.Context, mission *store.StellarMission) *time.Time {
+	if !mission.Enabled || mission.Schedule == "" {
+		return nil
+	}
+	timezone := stellarDefaultTimezone
+	if prefs, err := h.store.GetStellarPreferences(ctx, mission.UserID); err == nil && prefs != nil {
+		timezone = prefs.Timezone
+	}
+	next, err := scheduler.NextRun(mission.Schedule, timezone, time.Now().UTC())
+	if err != nil {
+		return nil
+	}
+	return &next
+}
+
 func parseMissionPayload(c *fiber.Ctx) (*store.StellarMission, error) {
 	var body upsertStellarMissionRequest
 	if err := c.BodyParser(&body); err != nil {
@@ -140,6 +167,11 @@ func parseMissionPayload(c *fiber.Ctx) (*store.StellarMission, error) {
 	if len(body.Schedule) > stellarMaxScheduleLength {
 		return nil, fiber.NewError(fiber.StatusBadRequest, "schedule must be <= 128 chars")
 	}
+	if body.Schedule != "" {
+		if err := scheduler.ValidateSchedule(body.Schedule); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "invalid schedule: "+err.Error())
+		}
+	}
 	body.TriggerType = strings.TrimSpace(body.TriggerType)
 	if body.TriggerType == "" {
 		body.TriggerType = stellarDefaultTriggerType
@@ -169,6 +201,11 @@ func parseMissionPayload(c *fiber.Ctx) (*store.StellarMission, error) {
 		}
 		tools = append(tools, tool)
 	}
+	body.Cluster = strings.TrimSpace(body.Cluster)
+	body.Namespace = strings.TrimSpace(body.Namespace)
+	if len(body.Cluster) > stellarMaxScopeNameLength || len(body.Namespace) > stellarMaxScopeNameLength {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "cluster and namespace must be <= 253 chars")
+	}
 	return &store.StellarMission{
 		Name:           body.Name,
 		Goal:           body.Goal,
@@ -178,5 +215,7 @@ func parseMissionPayload(c *fiber.Ctx) (*store.StellarMission, error) {
 		MemoryScope:    body.MemoryScope,
 		Enabled:        body.Enabled,
 		ToolBindings:   tools,
+		Cluster:        body.Cluster,
+		Namespace:      body.Namespace,
 	}, nil
 }
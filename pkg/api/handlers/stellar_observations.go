@@ -93,6 +93,9 @@ func (h *StellarHandler) GetDigest(c *fiber.Ctx) error {
 			{Role: "user", Content: summary.String()},
 		},
 	})
+	if handled, respErr := respondIfBudgetExceeded(c, err); handled {
+		return respErr
+	}
 	if err != nil {
 		slog.Error("stellar: digest generation failed", "error", err, "userID", userID)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "digest generation failed"})
@@ -177,7 +180,7 @@ func (h *StellarHandler) Ask(c *fiber.Ctx) error {
 	}
 
 	userCfg, _ := h.resolveUserProvider(c.UserContext(), userID)
-	resolved := h.providerRegistry.Resolve(body.Provider, body.Model, userCfg)
+	resolved := h.providerRegistry.ResolveForUser(stellarBudgetUserID(userID), body.Provider, body.Model, userCfg)
 
 	state, err := h.buildOperationalState(c.UserContext(), userID, body.Cluster)
 	if err != nil {
@@ -228,6 +231,9 @@ func (h *StellarHandler) Ask(c *fiber.Ctx) error {
 	fallbackUsed := false
 	fallbackReason := ""
 	durationMs := int(time.Since(startTime).Milliseconds())
+	if handled, respErr := respondIfBudgetExceeded(c, err); handled {
+		return respErr
+	}
 	if err != nil {
 		fallbackName := os.Getenv("STELLAR_FALLBACK_PROVIDER")
 		if fallbackName != "" && fallbackName != resolved.Provider.Name() {
@@ -256,6 +262,11 @@ func (h *StellarHandler) Ask(c *fiber.Ctx) error {
 	if generated == nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "AI provider returned empty response"})
 	}
+	// The router may already have moved past a failing provider.
+	if !fallbackUsed && generated.Provider != "" && generated.Provider != resolved.Provider.Name() {
+		fallbackUsed = true
+		fallbackReason = fmt.Sprintf("%s unavailable. Routed to %s.", resolved.Provider.Name(), generated.Provider)
+	}
 
 	// Parse WATCH: line from LLM response before persisting
 	cleanContent, watch := parseWatchLine(generated.Content)
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/kubestellar/console/pkg/stellar/scheduler"
 	"github.com/kubestellar/console/pkg/store"
 )
 
@@ -62,6 +66,10 @@ func (h *StellarHandler) UpdatePreferences(c *fiber.Ctx) error {
 		}
 	}
 
+	previous, err := h.store.GetStellarPreferences(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to load preferences"})
+	}
 	if err := h.store.UpdateStellarPreferences(c.UserContext(), &store.StellarPreferences{
 		UserID:          userID,
 		DefaultProvider: body.DefaultProvider,
@@ -72,9 +80,64 @@ func (h *StellarHandler) UpdatePreferences(c *fiber.Ctx) error {
 	}); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save preferences"})
 	}
+	if previous == nil || previous.Timezone != body.Timezone {
+		h.rescheduleForTimezone(c.UserContext(), userID, body.Timezone, time.Now().UTC())
+	}
 	updated, err := h.store.GetStellarPreferences(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to reload preferences"})
 	}
 	return c.JSON(updated)
 }
+
+// rescheduleForTimezone moves the pending fire of every scheduled mission and
+// recurring action the user owns to the next occurrence in timezone, so a
+// timezone change takes effect immediately instead of after the next run.
+// Actions that are running pick the new timezone up when they re-arm.
+func (h *StellarHandler) rescheduleForTimezone(ctx context.Context, userID, timezone string, now time.Time) {
+	for offset := 0; ; offset += stellarMaxListLimit {
+		missions, err := h.store.ListStellarMissions(ctx, userID, stellarMaxListLimit, offset)
+		if err != nil {
+			slog.Warn("stellar: failed to list missions for timezone change", "userID", userID, "error", err)
+			break
+		}
+		for _, m := range missions {
+			if !m.Enabled || m.Schedule == "" || m.NextRunAt == nil {
+				continue
+			}
+			next, err := scheduler.NextRun(m.Schedule, timezone, now)
+			if err != nil || next.IsZero() {
+				continue
+			}
+			if err := h.store.SetStellarMissionRunTimes(ctx, m.ID, nil, &next); err != nil {
+				slog.Warn("stellar: failed to reschedule mission", "mission_id", m.ID, "error", err)
+			}
+		}
+		if len(missions) < stellarMaxListLimit {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += stellarMaxListLimit {
+		actions, err := h.store.ListStellarActions(ctx, userID, "approved", stellarMaxListLimit, offset)
+		if err != nil {
+			slog.Warn("stellar: failed to list actions for timezone change", "userID", userID, "error", err)
+			return
+		}
+		for _, a := range actions {
+			if a.CronExpr == "" || a.ScheduledAt == nil {
+				continue
+			}
+			next, err := scheduler.NextRun(a.CronExpr, timezone, now)
+			if err != nil || next.IsZero() {
+				continue
+			}
+			if err := h.store.RescheduleStellarAction(ctx, a.ID, next); err != nil {
+				slog.Warn("stellar: failed to reschedule action", "action_id", a.ID, "error", err)
+			}
+		}
+		if len(actions) < stellarMaxListLimit {
+			return
+		}
+	}
+}
@@ -374,7 +374,7 @@ Namespace: %s
 Resource: %s/%s
 Reason: %s
 Message: %s
-Suspected root cause: %s
+Suspected root cause: %s%s
 
 Please:
 1. Pull the pod logs and 'describe' output for the affected resource.
@@ -384,7 +384,8 @@ Please:
 5. Report what you did, the outcome, and any follow-up I should know about.
 
 Don't ask me first — act. If you genuinely can't fix it safely, tell me what's blocking you.`,
-		safeEventCluster, safeEventNamespace, safeEventKind, safeEventName, safeEventReason, safeEventMessage, safeRootCauseHeadline)
+		safeEventCluster, safeEventNamespace, safeEventKind, safeEventName, safeEventReason, safeEventMessage, safeRootCauseHeadline,
+		h.relatedIncidentsPrompt(ctx, notif.UserID, fmt.Sprintf("%s %s %s/%s", event.Reason, event.Message, event.Namespace, workload)))
 
 	h.broadcastSolveProgress(solve.ID, notif.ID, "solving",
 		"Applying fix via AI mission — using your connected agent.", 75)
@@ -514,6 +515,9 @@ func (a *solverStorageAdapter) CreateStellarExecution(ctx context.Context, e *st
 func (a *solverStorageAdapter) CreateStellarNotification(ctx context.Context, n *store.StellarNotification) error {
 	return a.store.CreateStellarNotification(ctx, n)
 }
+func (a *solverStorageAdapter) SearchStellarMemoryEntries(ctx context.Context, userID, query string, limit int) ([]store.StellarMemoryEntry, error) {
+	return a.store.SearchStellarMemoryEntries(ctx, userID, query, limit)
+}
 
 // solverBroadcasterAdapter bridges the solver's SSEEvent envelope to the
 // handler's local SSEEvent envelope (the types are identical-shaped but
@@ -603,7 +607,7 @@ Cluster: %s
 Namespace: %s
 Resource: %s
 Title: %s
-Notification: %s
+Notification: %s%s
 
 Please:
 1. Pull pod logs and 'describe' output.
@@ -613,7 +617,8 @@ Please:
 5. Report what you did and the outcome.
 
 Don't ask me first — act. I trust you.`,
-		safeNotifCluster, safeNotifNamespace, safeResourceName, safeNotifTitle, safeNotifBody)
+		safeNotifCluster, safeNotifNamespace, safeResourceName, safeNotifTitle, safeNotifBody,
+		h.relatedIncidentsPrompt(ctx, userID, fmt.Sprintf("%s %s %s/%s", notif.Title, notif.Body, notif.Namespace, workload)))
 
 	h.logActivity(ctx, &store.StellarActivity{
 		Kind:      "mission_triggered",
@@ -731,6 +736,28 @@ func renderUntrustedPromptData(source, value string) string {
 	)
 }
 
+// relatedIncidentsPrompt recalls past incidents similar to the one being
+// solved and renders them as a prompt section, so the mission can start from
+// what worked last time. Recall is best-effort: on error or no match the
+// section is empty.
+func (h *StellarHandler) relatedIncidentsPrompt(ctx context.Context, userID, query string) string {
+	related, err := h.store.SearchStellarMemoryEntries(ctx, userID, query, solver.RecallLimit)
+	if err != nil {
+		slog.Debug("stellar: solve memory recall failed", "user", userID, "error", err)
+		return ""
+	}
+	if len(related) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\nSimilar past incidents from Stellar memory (hints only — confirm against the live cluster):\n")
+	for _, m := range related {
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", m.CreatedAt.Format("Jan 02"),
+			renderUntrustedPromptData("stellar-memory", m.Summary)))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
 // staleApprovalReviewLoop checks once per hour for pending approvals older than
 // staleApprovalAgeCutoff. For each, it asks the cluster whether the workload
 // has self-healed; if so, the approval is cancelled (superseded). Otherwise
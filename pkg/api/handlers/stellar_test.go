@@ -107,6 +107,48 @@ func TestStellarPreferencesRoundTrip(t *testing.T) {
 	require.Equal(t, http.StatusOK, putResp.StatusCode)
 }
 
+func TestStellarTimezoneChangeReschedulesMissions(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	app, _ := newStellarTestApp(t)
+
+	send := func(method, path string, body any) *http.Response {
+		raw, _ := json.Marshal(body)
+		req, err := http.NewRequest(method, path, bytes.NewReader(raw))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, stellarTestFiberTimeoutMs)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := send(http.MethodPost, "/api/stellar/missions", map[string]any{
+		"name": "morning-check", "goal": "Check prod.", "schedule": "0 9 * * *",
+		"triggerType": "cron", "enabled": true, "cluster": "prod-a", "namespace": "payments",
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created store.StellarMission
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.NotNil(t, created.NextRunAt)
+	assert.Equal(t, 9, created.NextRunAt.UTC().Hour())
+	assert.Equal(t, "prod-a", created.Cluster)
+	assert.Equal(t, "payments", created.Namespace)
+
+	resp = send(http.MethodPut, "/api/stellar/preferences", map[string]any{"timezone": "Asia/Kolkata"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = send(http.MethodGet, "/api/stellar/missions/"+created.ID, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var updated store.StellarMission
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+	require.NotNil(t, updated.NextRunAt)
+	local := updated.NextRunAt.In(kolkata)
+	assert.Equal(t, 9, local.Hour())
+	assert.Equal(t, 0, local.Minute())
+}
+
 func TestStellarMissionAndActionFlow(t *testing.T) {
 	app, _ := newStellarTestApp(t)
 
@@ -245,3 +287,24 @@ func TestStellarResolveWatchReturnsJSON(t *testing.T) {
 	assert.Equal(t, "resolved", resolved["status"])
 	assert.NotNil(t, resolved["inactivityTimeoutMs"])
 }
+
+func TestStellarRelatedIncidentsPrompt(t *testing.T) {
+	_, sqlStore := newStellarTestApp(t)
+	ctx := context.Background()
+	stellarStore := sqlStore.(StellarStore)
+	h := NewStellarHandler(stellarStore, nil)
+
+	assert.Empty(t, h.relatedIncidentsPrompt(ctx, "u1", "payments-api"), "no memories means no prompt section")
+
+	require.NoError(t, stellarStore.CreateStellarMemoryEntry(ctx, &store.StellarMemoryEntry{
+		UserID:     "u1",
+		Cluster:    "prod",
+		Category:   "incident",
+		Summary:    "payments-api ran out of memory <fixed by raising limits>",
+		Importance: 5,
+	}))
+	section := h.relatedIncidentsPrompt(ctx, "u1", "payments-api")
+	assert.Contains(t, section, "Similar past incidents")
+	assert.Contains(t, section, `trust="untrusted"`)
+	assert.Contains(t, section, "payments-api ran out of memory &lt;fixed by raising limits&gt;")
+}
@@ -8,23 +8,36 @@ package handlers
 //   - SLSA Provenance (#9647): L0–L4 level badges per workload
 //   - License Compliance (#9648): deny/warn-list violation detection
 //
-// All handlers currently serve demo data via the respective engine stubs.
-// Full backend integration is tracked in the individual sub-issues.
+// The public handlers serve demo data via the respective engine stubs.
+// Each also has authenticated live endpoints backed by real registries;
+// licenses additionally expose the operator's allow/warn/deny policy.
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/kubestellar/console/pkg/api/audit"
+	"github.com/kubestellar/console/pkg/api/middleware"
 	"github.com/kubestellar/console/pkg/compliance/licenses"
 	"github.com/kubestellar/console/pkg/compliance/sbom"
 	"github.com/kubestellar/console/pkg/compliance/signing"
 	"github.com/kubestellar/console/pkg/compliance/slsa"
+	"github.com/kubestellar/console/pkg/store"
 )
 
 // ─── SBOM Handler (#9644) ────────────────────────────────────────────────────
 
+// sbomLiveTimeout bounds one live SBOM pass; cold caches mean up to two
+// registry lookups per distinct image digest.
+const sbomLiveTimeout = 2 * time.Minute
+
 // SBOMHandler serves Software Bill of Materials endpoints.
 type SBOMHandler struct {
-	engine *sbom.Engine
+	engine   *sbom.Engine
+	clusters ClusterNameLister
 }
 
 // NewSBOMHandler creates an SBOM handler backed by a stub engine.
@@ -32,6 +45,13 @@ func NewSBOMHandler() *SBOMHandler {
 	return &SBOMHandler{engine: sbom.NewEngine()}
 }
 
+// NewLiveSBOMHandler creates a handler that collects the SBOMs of images
+// running on the clusters returned by clusters. engine should come from
+// sbom.NewLiveEngine.
+func NewLiveSBOMHandler(engine *sbom.Engine, clusters ClusterNameLister) *SBOMHandler {
+	return &SBOMHandler{engine: engine, clusters: clusters}
+}
+
 // RegisterPublicRoutes mounts SBOM endpoints under /api/supply-chain/sbom.
 func (h *SBOMHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/supply-chain/sbom")
@@ -42,11 +62,71 @@ func (h *SBOMHandler) RegisterPublicRoutes(r fiber.Router) {
 func (h *SBOMHandler) getSummary(c *fiber.Ctx) error   { return c.JSON(h.engine.Summary()) }
 func (h *SBOMHandler) listDocuments(c *fiber.Ctx) error { return c.JSON(h.engine.Documents()) }
 
+// RegisterLiveRoutes mounts live SBOM endpoints under
+// /api/supply-chain/sbom/live. Every endpoint accepts an optional ?cluster=
+// to evaluate a single cluster.
+func (h *SBOMHandler) RegisterLiveRoutes(r fiber.Router) {
+	g := r.Group("/supply-chain/sbom/live")
+	g.Get("/report", h.getLiveReport)
+	g.Get("/summary", h.getLiveSummary)
+	g.Get("/documents", h.listLiveDocuments)
+	g.Post("/refresh", h.refreshLive)
+}
+
+func (h *SBOMHandler) getLiveReport(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *sbom.Report) any { return r })
+}
+
+func (h *SBOMHandler) getLiveSummary(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *sbom.Report) any { return r.Summary })
+}
+
+func (h *SBOMHandler) listLiveDocuments(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *sbom.Report) any { return r.Documents })
+}
+
+// refreshLive drops cached SBOMs, reloads the OSV feed and re-evaluates.
+func (h *SBOMHandler) refreshLive(c *fiber.Ctx) error {
+	h.engine.Invalidate()
+	return h.getLiveReport(c)
+}
+
+func (h *SBOMHandler) withLiveReport(c *fiber.Ctx, pick func(*sbom.Report) any) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), sbomLiveTimeout)
+	defer cancel()
+
+	clusters, err := requestClusters(ctx, c, h.clusters)
+	if err != nil {
+		slog.Error("[SBOM] failed to list clusters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list clusters",
+		})
+	}
+	if h.engine.IsLive() && len(clusters) == 0 {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "no healthy clusters available",
+		})
+	}
+	report, err := h.engine.Evaluate(ctx, clusters)
+	if err != nil {
+		slog.Error("[SBOM] live evaluation failed", "clusters", clusters, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "SBOM evaluation failed",
+		})
+	}
+	return c.JSON(pick(report))
+}
+
 // ─── Signing Handler (#9646) ─────────────────────────────────────────────────
 
+// signingLiveTimeout bounds one live verification pass; cold caches mean a
+// registry round-trip per distinct image digest.
+const signingLiveTimeout = 2 * time.Minute
+
 // SigningHandler serves Sigstore/Cosign verification endpoints.
 type SigningHandler struct {
-	engine *signing.Engine
+	engine   *signing.Engine
+	clusters ClusterNameLister
 }
 
 // NewSigningHandler creates a signing handler backed by a stub engine.
@@ -54,6 +134,13 @@ func NewSigningHandler() *SigningHandler {
 	return &SigningHandler{engine: signing.NewEngine()}
 }
 
+// NewLiveSigningHandler creates a handler that verifies the images running on
+// the clusters returned by clusters. engine should come from
+// signing.NewLiveEngine.
+func NewLiveSigningHandler(engine *signing.Engine, clusters ClusterNameLister) *SigningHandler {
+	return &SigningHandler{engine: engine, clusters: clusters}
+}
+
 // RegisterPublicRoutes mounts signing endpoints under /api/supply-chain/signing.
 func (h *SigningHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/supply-chain/signing")
@@ -66,11 +153,76 @@ func (h *SigningHandler) getSummary(c *fiber.Ctx) error    { return c.JSON(h.eng
 func (h *SigningHandler) listImages(c *fiber.Ctx) error    { return c.JSON(h.engine.Images()) }
 func (h *SigningHandler) listPolicies(c *fiber.Ctx) error  { return c.JSON(h.engine.Policies()) }
 
+// RegisterLiveRoutes mounts live verification endpoints under
+// /api/supply-chain/signing/live. Every endpoint accepts an optional
+// ?cluster= to evaluate a single cluster.
+func (h *SigningHandler) RegisterLiveRoutes(r fiber.Router) {
+	g := r.Group("/supply-chain/signing/live")
+	g.Get("/report", h.getLiveReport)
+	g.Get("/summary", h.getLiveSummary)
+	g.Get("/images", h.listLiveImages)
+	g.Get("/policies", h.listLivePolicies)
+	g.Post("/refresh", h.refreshLive)
+}
+
+func (h *SigningHandler) getLiveReport(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *signing.Report) any { return r })
+}
+
+func (h *SigningHandler) getLiveSummary(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *signing.Report) any { return r.Summary })
+}
+
+func (h *SigningHandler) listLiveImages(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *signing.Report) any { return r.Images })
+}
+
+func (h *SigningHandler) listLivePolicies(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *signing.Report) any { return r.Policies })
+}
+
+// refreshLive drops cached verifications and re-verifies every image.
+func (h *SigningHandler) refreshLive(c *fiber.Ctx) error {
+	h.engine.Invalidate()
+	return h.getLiveReport(c)
+}
+
+func (h *SigningHandler) withLiveReport(c *fiber.Ctx, pick func(*signing.Report) any) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), signingLiveTimeout)
+	defer cancel()
+
+	clusters, err := requestClusters(ctx, c, h.clusters)
+	if err != nil {
+		slog.Error("[Signing] failed to list clusters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list clusters",
+		})
+	}
+	if h.engine.IsLive() && len(clusters) == 0 {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "no healthy clusters available",
+		})
+	}
+	report, err := h.engine.Evaluate(ctx, clusters)
+	if err != nil {
+		slog.Error("[Signing] live verification failed", "clusters", clusters, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "verification failed",
+		})
+	}
+	return c.JSON(pick(report))
+}
+
 // ─── SLSA Handler (#9647) ────────────────────────────────────────────────────
 
+// slsaLiveTimeout bounds one live provenance pass; cold caches mean a
+// referrers lookup and attestation fetch per distinct image digest.
+const slsaLiveTimeout = 2 * time.Minute
+
 // SLSAHandler serves SLSA provenance tracking endpoints.
 type SLSAHandler struct {
-	engine *slsa.Engine
+	engine   *slsa.Engine
+	clusters ClusterNameLister
 }
 
 // NewSLSAHandler creates a SLSA handler backed by a stub engine.
@@ -78,6 +230,13 @@ func NewSLSAHandler() *SLSAHandler {
 	return &SLSAHandler{engine: slsa.NewEngine()}
 }
 
+// NewLiveSLSAHandler creates a handler that verifies the provenance of
+// images running in the clusters returned by clusters, using an engine from
+// slsa.NewLiveEngine.
+func NewLiveSLSAHandler(engine *slsa.Engine, clusters ClusterNameLister) *SLSAHandler {
+	return &SLSAHandler{engine: engine, clusters: clusters}
+}
+
 // RegisterPublicRoutes mounts SLSA endpoints under /api/supply-chain/slsa.
 func (h *SLSAHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/supply-chain/slsa")
@@ -88,11 +247,72 @@ func (h *SLSAHandler) RegisterPublicRoutes(r fiber.Router) {
 func (h *SLSAHandler) getSummary(c *fiber.Ctx) error    { return c.JSON(h.engine.Summary()) }
 func (h *SLSAHandler) listWorkloads(c *fiber.Ctx) error { return c.JSON(h.engine.Workloads()) }
 
+// RegisterLiveRoutes mounts live SLSA endpoints under
+// /api/supply-chain/slsa/live. Every endpoint accepts an optional ?cluster=
+// to evaluate a single cluster.
+func (h *SLSAHandler) RegisterLiveRoutes(r fiber.Router) {
+	g := r.Group("/supply-chain/slsa/live")
+	g.Get("/report", h.getLiveReport)
+	g.Get("/summary", h.getLiveSummary)
+	g.Get("/workloads", h.listLiveWorkloads)
+	g.Post("/refresh", h.refreshLive)
+}
+
+func (h *SLSAHandler) getLiveReport(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *slsa.Report) any { return r })
+}
+
+func (h *SLSAHandler) getLiveSummary(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *slsa.Report) any { return r.Summary })
+}
+
+func (h *SLSAHandler) listLiveWorkloads(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *slsa.Report) any { return r.Workloads })
+}
+
+// refreshLive drops cached provenance and re-evaluates.
+func (h *SLSAHandler) refreshLive(c *fiber.Ctx) error {
+	h.engine.Invalidate()
+	return h.getLiveReport(c)
+}
+
+func (h *SLSAHandler) withLiveReport(c *fiber.Ctx, pick func(*slsa.Report) any) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), slsaLiveTimeout)
+	defer cancel()
+
+	clusters, err := requestClusters(ctx, c, h.clusters)
+	if err != nil {
+		slog.Error("[SLSA] failed to list clusters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list clusters",
+		})
+	}
+	if h.engine.IsLive() && len(clusters) == 0 {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "no healthy clusters available",
+		})
+	}
+	report, err := h.engine.Evaluate(ctx, clusters)
+	if err != nil {
+		slog.Error("[SLSA] live evaluation failed", "clusters", clusters, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "SLSA evaluation failed",
+		})
+	}
+	return c.JSON(pick(report))
+}
+
 // ─── License Compliance Handler (#9648) ─────────────────────────────────────
 
+// licenseLiveTimeout bounds one live license pass, which includes the SBOM
+// lookups it depends on.
+const licenseLiveTimeout = 2 * time.Minute
+
 // LicenseHandler serves open-source license compliance endpoints.
 type LicenseHandler struct {
-	engine *licenses.Engine
+	engine   *licenses.Engine
+	clusters ClusterNameLister
+	store    store.Store
 }
 
 // NewLicenseHandler creates a license compliance handler backed by a stub engine.
@@ -100,6 +320,13 @@ func NewLicenseHandler() *LicenseHandler {
 	return &LicenseHandler{engine: licenses.NewEngine()}
 }
 
+// NewLiveLicenseHandler creates a handler that classifies the packages of
+// workloads running in the clusters returned by clusters, using an engine
+// from licenses.NewLiveEngine. s gates policy changes to console admins.
+func NewLiveLicenseHandler(engine *licenses.Engine, clusters ClusterNameLister, s store.Store) *LicenseHandler {
+	return &LicenseHandler{engine: engine, clusters: clusters, store: s}
+}
+
 // RegisterPublicRoutes mounts license endpoints under /api/supply-chain/licenses.
 func (h *LicenseHandler) RegisterPublicRoutes(r fiber.Router) {
 	g := r.Group("/supply-chain/licenses")
@@ -111,3 +338,118 @@ func (h *LicenseHandler) RegisterPublicRoutes(r fiber.Router) {
 func (h *LicenseHandler) getSummary(c *fiber.Ctx) error      { return c.JSON(h.engine.Summary()) }
 func (h *LicenseHandler) listPackages(c *fiber.Ctx) error    { return c.JSON(h.engine.Packages()) }
 func (h *LicenseHandler) listCategories(c *fiber.Ctx) error  { return c.JSON(h.engine.Categories()) }
+
+// RegisterLiveRoutes mounts live license endpoints under
+// /api/supply-chain/licenses/live and the policy under
+// /api/supply-chain/licenses/policy. Every live endpoint accepts an optional
+// ?cluster= to evaluate a single cluster.
+func (h *LicenseHandler) RegisterLiveRoutes(r fiber.Router) {
+	g := r.Group("/supply-chain/licenses/live")
+	g.Get("/report", h.getLiveReport)
+	g.Get("/summary", h.getLiveSummary)
+	g.Get("/packages", h.listLivePackages)
+	g.Get("/categories", h.listLiveCategories)
+	g.Get("/workloads", h.listLiveWorkloads)
+	g.Get("/clusters", h.listLiveClusters)
+	g.Post("/refresh", h.refreshLive)
+
+	r.Get("/supply-chain/licenses/policy", h.getPolicy)
+	r.Put("/supply-chain/licenses/policy", h.putPolicy)
+}
+
+func (h *LicenseHandler) getLiveReport(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *licenses.Report) any { return r })
+}
+
+func (h *LicenseHandler) getLiveSummary(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *licenses.Report) any { return r.Summary })
+}
+
+func (h *LicenseHandler) listLivePackages(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *licenses.Report) any { return r.Packages })
+}
+
+func (h *LicenseHandler) listLiveCategories(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *licenses.Report) any { return r.Categories })
+}
+
+func (h *LicenseHandler) listLiveWorkloads(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *licenses.Report) any { return r.Workloads })
+}
+
+func (h *LicenseHandler) listLiveClusters(c *fiber.Ctx) error {
+	return h.withLiveReport(c, func(r *licenses.Report) any { return r.Clusters })
+}
+
+// refreshLive drops cached SBOMs and re-evaluates.
+func (h *LicenseHandler) refreshLive(c *fiber.Ctx) error {
+	h.engine.Invalidate()
+	return h.getLiveReport(c)
+}
+
+func (h *LicenseHandler) withLiveReport(c *fiber.Ctx, pick func(*licenses.Report) any) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), licenseLiveTimeout)
+	defer cancel()
+
+	clusters, err := requestClusters(ctx, c, h.clusters)
+	if err != nil {
+		slog.Error("[Licenses] failed to list clusters", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to list clusters",
+		})
+	}
+	if h.engine.IsLive() && len(clusters) == 0 {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "no healthy clusters available",
+		})
+	}
+	report, err := h.engine.Evaluate(ctx, clusters)
+	if err != nil {
+		slog.Error("[Licenses] live evaluation failed", "clusters", clusters, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "license evaluation failed",
+		})
+	}
+	return c.JSON(pick(report))
+}
+
+// getPolicy returns the license policy in effect.
+// GET /api/supply-chain/licenses/policy
+func (h *LicenseHandler) getPolicy(c *fiber.Ctx) error {
+	policy, err := h.engine.Policy(c.UserContext())
+	if err != nil {
+		slog.Error("[Licenses] failed to load policy", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to load license policy",
+		})
+	}
+	return c.JSON(policy)
+}
+
+// putPolicy replaces the license policy. Admin only.
+// PUT /api/supply-chain/licenses/policy
+func (h *LicenseHandler) putPolicy(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	var policy licenses.Policy
+	if err := c.BodyParser(&policy); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if err := policy.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	saved, err := h.engine.SetPolicy(c.UserContext(), policy, middleware.GetGitHubLogin(c))
+	if err != nil {
+		slog.Error("[Licenses] failed to save policy", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to save license policy",
+		})
+	}
+	audit.Log(c, audit.ActionSaveLicensePolicy, "license_policy", "fleet")
+	return c.JSON(saved)
+}
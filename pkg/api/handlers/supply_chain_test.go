@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/kubestellar/console/pkg/compliance/licenses"
+	"github.com/kubestellar/console/pkg/compliance/oci"
 	"github.com/kubestellar/console/pkg/compliance/sbom"
 	"github.com/kubestellar/console/pkg/compliance/signing"
 	"github.com/kubestellar/console/pkg/compliance/slsa"
+	"github.com/kubestellar/console/pkg/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -147,3 +152,152 @@ func TestSupplyChainHandlers(t *testing.T) {
 		})
 	})
 }
+
+type fakeSigningInventory struct{}
+
+func (fakeSigningInventory) RunningImages(context.Context, string) ([]signing.RunningImage, error) {
+	// No digest yet, so verification never reaches a registry.
+	return []signing.RunningImage{{Image: "ghcr.io/org/api:v1", Workload: "api", Namespace: "shop"}}, nil
+}
+
+func (fakeSigningInventory) ClusterImagePolicies(context.Context, string) ([]signing.ClusterImagePolicy, error) {
+	return []signing.ClusterImagePolicy{{Name: "org", Globs: []string{"ghcr.io/org/**"}, Authorities: 1}}, nil
+}
+
+func TestSigningLiveHandler(t *testing.T) {
+	env := setupTestEnv(t)
+	verifier, err := signing.NewVerifier(oci.NewClient(), signing.VerifyOptions{})
+	require.NoError(t, err)
+	engine := signing.NewLiveEngine(fakeSigningInventory{}, verifier)
+	h := NewLiveSigningHandler(engine, func(context.Context) ([]string, error) { return []string{"prod"}, nil })
+	h.RegisterLiveRoutes(env.App)
+
+	req := httptest.NewRequest("GET", "/supply-chain/signing/live/summary", nil)
+	resp, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 200, resp.StatusCode)
+	var summary signing.Summary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+	assert.Equal(t, signing.ModeLive, summary.Mode)
+	assert.Equal(t, 1, summary.TotalImages)
+	assert.Equal(t, 1, summary.UnsignedImages)
+	assert.Equal(t, 1, summary.PolicyViolations)
+
+	req = httptest.NewRequest("GET", "/supply-chain/signing/live/images?cluster=edge", nil)
+	resp2, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp2.Body.Close() })
+	var images []signing.Image
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&images))
+	require.Len(t, images, 1)
+	assert.Equal(t, "edge", images[0].Cluster)
+}
+
+func TestSBOMLiveHandler(t *testing.T) {
+	env := setupTestEnv(t)
+	engine, err := sbom.NewLiveEngine(fakeSigningInventory{}, oci.NewClient(), sbom.LiveOptions{})
+	require.NoError(t, err)
+	h := NewLiveSBOMHandler(engine, func(context.Context) ([]string, error) { return []string{"prod"}, nil })
+	h.RegisterLiveRoutes(env.App)
+
+	req := httptest.NewRequest("GET", "/supply-chain/sbom/live/summary", nil)
+	resp, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 200, resp.StatusCode)
+	var summary sbom.Summary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+	assert.Equal(t, sbom.ModeLive, summary.Mode)
+	assert.Equal(t, 1, summary.TotalWorkloads)
+	assert.Equal(t, 0, summary.SBOMCoverage)
+
+	req = httptest.NewRequest("GET", "/supply-chain/sbom/live/documents?cluster=edge", nil)
+	resp2, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp2.Body.Close() })
+	var docs []sbom.Document
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&docs))
+	assert.Empty(t, docs)
+}
+
+func TestSLSALiveHandler(t *testing.T) {
+	env := setupTestEnv(t)
+	verifier, err := signing.NewVerifier(oci.NewClient(), signing.VerifyOptions{})
+	require.NoError(t, err)
+	engine := slsa.NewLiveEngine(fakeSigningInventory{}, oci.NewClient(), verifier, nil)
+	h := NewLiveSLSAHandler(engine, func(context.Context) ([]string, error) { return []string{"prod"}, nil })
+	h.RegisterLiveRoutes(env.App)
+
+	req := httptest.NewRequest("GET", "/supply-chain/slsa/live/summary", nil)
+	resp, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 200, resp.StatusCode)
+	var summary slsa.Summary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+	assert.Equal(t, slsa.ModeLive, summary.Mode)
+	assert.Equal(t, 1, summary.TotalWorkloads)
+	assert.Equal(t, slsa.Level0, summary.FleetPosture)
+
+	req = httptest.NewRequest("GET", "/supply-chain/slsa/live/workloads?cluster=edge", nil)
+	resp2, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp2.Body.Close() })
+	var workloads []slsa.Workload
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&workloads))
+	require.Len(t, workloads, 1)
+	assert.Equal(t, "edge", workloads[0].Cluster)
+	assert.False(t, workloads[0].AttestationPresent)
+}
+
+func TestLicenseLiveHandler(t *testing.T) {
+	env := setupTestEnv(t)
+	mockStore := env.Store.(*test.MockStore)
+	mockStore.On("GetLicensePolicy").Return(nil, nil).Once()
+	mockStore.On("SaveLicensePolicy", mock.Anything).Return(nil).Once()
+
+	sboms, err := sbom.NewLiveEngine(fakeSigningInventory{}, oci.NewClient(), sbom.LiveOptions{})
+	require.NoError(t, err)
+	engine := licenses.NewLiveEngine(sboms, env.Store)
+	h := NewLiveLicenseHandler(engine, func(context.Context) ([]string, error) { return []string{"prod"}, nil }, env.Store)
+	h.RegisterLiveRoutes(env.App)
+
+	req := httptest.NewRequest("GET", "/supply-chain/licenses/policy", nil)
+	resp, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, 200, resp.StatusCode)
+	var policy licenses.Policy
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&policy))
+	assert.Contains(t, policy.Deny, "GPL-*", "default policy until one is saved")
+
+	req = httptest.NewRequest("PUT", "/supply-chain/licenses/policy", strings.NewReader(`{"allow":["MIT"],"deny":["mit"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp2, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp2.Body.Close() })
+	assert.Equal(t, 400, resp2.StatusCode)
+
+	req = httptest.NewRequest("PUT", "/supply-chain/licenses/policy", strings.NewReader(`{"allow":["apache 2.0"],"deny":["GPL-*"],"unlisted":"denied"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp3, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp3.Body.Close() })
+	assert.Equal(t, 200, resp3.StatusCode)
+	require.NoError(t, json.NewDecoder(resp3.Body).Decode(&policy))
+	assert.Equal(t, []string{"Apache-2.0"}, policy.Allow)
+	assert.Equal(t, licenses.RiskDenied, policy.Unlisted)
+	mockStore.AssertCalled(t, "SaveLicensePolicy", mock.Anything)
+
+	mockStore.On("GetLicensePolicy").Return(nil, nil).Once()
+	req = httptest.NewRequest("GET", "/supply-chain/licenses/live/summary", nil)
+	resp4, err := env.App.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp4.Body.Close() })
+	assert.Equal(t, 200, resp4.StatusCode)
+	var summary licenses.Summary
+	require.NoError(t, json.NewDecoder(resp4.Body).Decode(&summary))
+	assert.Equal(t, licenses.ModeLive, summary.Mode)
+	assert.Equal(t, 1, summary.WorkloadsWithoutSBOM)
+}
@@ -39,6 +39,11 @@ const clusterListTimeout = 15 * time.Second
 // eventCollectTimeout is the per-cluster fetch timeout.
 const eventCollectTimeout = 15 * time.Second
 
+// eventWatchMinInterval is the shortest gap between two informer-triggered
+// collections of the same cluster; changes inside it wait for the next
+// change or poll.
+const eventWatchMinInterval = 10 * time.Second
+
 // eventsPerClusterLimit caps the number of events fetched per cluster per poll.
 const eventsPerClusterLimit = 200
 
@@ -142,6 +147,17 @@ func (h *TimelineHandler) runCollector(done <-chan struct{}) {
 	sweepTicker := time.NewTicker(eventRetentionSweepInterval)
 	defer sweepTicker.Stop()
 
+	// With the informer cache enabled, collect a cluster as soon as its
+	// events change instead of waiting for the next poll. A nil channel
+	// never fires, leaving the poll as the only trigger.
+	var changes <-chan struct{}
+	sub := h.k8sClient.SubscribeInformerChanges()
+	if sub != nil {
+		defer sub.Close()
+		changes = sub.C
+	}
+	lastCollected := make(map[string]time.Time)
+
 	// Run an initial collection immediately.
 	h.collectAll()
 
@@ -152,6 +168,15 @@ func (h *TimelineHandler) runCollector(done <-chan struct{}) {
 			return
 		case <-pollTicker.C:
 			h.collectAll()
+		case <-changes:
+			now := time.Now()
+			for _, change := range sub.Drain() {
+				if change.Resource != k8s.InformerEvents || now.Sub(lastCollected[change.Cluster]) < eventWatchMinInterval {
+					continue
+				}
+				lastCollected[change.Cluster] = now
+				h.collectCluster(k8s.ClusterInfo{Name: change.Cluster})
+			}
 		case <-sweepTicker.C:
 			h.sweepOld()
 		}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/api/middleware"
 	"github.com/kubestellar/console/pkg/store"
 )
@@ -124,7 +128,9 @@ type putUserTokenUsageRequest struct {
 }
 
 // UpdateUserTokenUsage upserts the entire token-usage row for the current
-// user. POST /api/token-usage/me (semantic: full upsert, idempotent).
+// user. POST /api/token-usage/me (semantic: full upsert, idempotent). The
+// row is display state only; AI router budgets are enforced from the
+// server-only counters behind storeTokenUsage.
 func (h *TokenUsageHandler) UpdateUserTokenUsage(c *fiber.Ctx) error {
 	userID := resolveTokenUsageUserID(c)
 	if userID == "" {
@@ -209,3 +215,64 @@ func (h *TokenUsageHandler) AddTokenDelta(c *fiber.Ctx) error {
 	}
 	return c.JSON(toTokenUsageResponse(updated))
 }
+
+// storeTokenUsage backs the AI router's budgets with the
+// ai_budget_usage counters. Enforcement reads only that server-written
+// counter: the user_token_usage row is replaced wholesale by the browser's
+// POST /api/token-usage/me sync, so reading it would let a user reset their
+// own budget. Router spend is still added to the row so the token-budget
+// widget shows it.
+//
+// Category totals across all users, system work included, are kept under
+// the aiBudgetAllUsers ID, which cannot collide with a user's UUID.
+type storeTokenUsage struct {
+	store store.Store
+	now   func() time.Time
+}
+
+// aiBudgetAllUsers is the ai_budget_usage user ID holding category totals.
+const aiBudgetAllUsers = "*"
+
+// NewStoreTokenUsage returns an airouter.UsageStore over s.
+func NewStoreTokenUsage(s store.Store) airouter.UsageStore {
+	return storeTokenUsage{store: s, now: time.Now}
+}
+
+// UserUsage implements airouter.UsageStore.
+func (u storeTokenUsage) UserUsage(ctx context.Context, userID string) (airouter.Usage, error) {
+	byCategory, err := u.store.GetAIBudgetUsage(ctx, userID, airouter.DayKey(u.now()))
+	if err != nil {
+		return airouter.Usage{}, err
+	}
+	usage := airouter.Usage{ByCategory: byCategory}
+	for _, tokens := range byCategory {
+		usage.Total += tokens
+	}
+	return usage, nil
+}
+
+// CategoryUsage implements airouter.UsageStore from the aiBudgetAllUsers
+// rows, so category budgets hold across restarts and replicas.
+func (u storeTokenUsage) CategoryUsage(ctx context.Context) (map[string]int64, error) {
+	return u.store.GetAIBudgetUsage(ctx, aiBudgetAllUsers, airouter.DayKey(u.now()))
+}
+
+// Record implements airouter.UsageStore. Every call adds to the
+// aiBudgetAllUsers totals; user spend is also added to the user's row and
+// to the widget row. The widget update passes no agent session ID, so the
+// delta is always added; a failure there is logged, since the enforced
+// counters are already up to date.
+func (u storeTokenUsage) Record(ctx context.Context, userID, category string, tokens int64) error {
+	day := airouter.DayKey(u.now())
+	err := u.store.AddAIBudgetUsage(ctx, aiBudgetAllUsers, day, category, tokens)
+	if userID == "" {
+		return err
+	}
+	if userErr := u.store.AddAIBudgetUsage(ctx, userID, day, category, tokens); userErr != nil {
+		return errors.Join(err, userErr)
+	}
+	if _, err := u.store.AddUserTokenDelta(ctx, userID, category, tokens, ""); err != nil {
+		slog.Warn("[TokenUsage] failed to mirror router spend to the usage widget", "user", userID, "error", err)
+	}
+	return err
+}
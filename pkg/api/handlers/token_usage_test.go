@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/store"
 )
 
@@ -257,3 +259,53 @@ func TestTokenUsageHandler_PutRejectsTooManyCategories(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	resp.Body.Close()
 }
+
+func TestTokenUsageHandler_PutCannotLowerEnforcedBudget(t *testing.T) {
+	app, s, userID, _ := newTokenUsageTestApp(t)
+	usage := NewStoreTokenUsage(s)
+	ctx := context.Background()
+
+	const spent int64 = 5000
+	require.NoError(t, usage.Record(ctx, userID, "missions", spent))
+
+	body, _ := json.Marshal(putUserTokenUsageRequest{
+		TotalTokens:      0,
+		TokensByCategory: map[string]int64{},
+	})
+	req, err := http.NewRequest(http.MethodPost, "/api/token-usage/me", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, testTokenUsageFiberTimeoutMs)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(0), decodeTokenUsageResponse(t, resp).TotalTokens)
+
+	enforced, err := usage.UserUsage(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, spent, enforced.Total)
+	assert.Equal(t, spent, enforced.ByCategory["missions"])
+}
+
+func TestStoreTokenUsage_CategoryBudgetSurvivesRouterRestart(t *testing.T) {
+	_, s, userID, _ := newTokenUsageTestApp(t)
+	cfg := airouter.Config{CategoryDailyTokens: map[string]int64{"missions": 100}}
+	req := airouter.Request{UserID: userID, Category: "missions", Fallback: []string{"openai"}}
+	call := func(context.Context, string) (int64, error) { return 120, nil }
+	ctx := context.Background()
+
+	_, err := airouter.New(cfg, NewStoreTokenUsage(s)).Route(ctx, req, nil, call)
+	require.NoError(t, err)
+
+	// A restarted replica reads the spend back from the store.
+	restarted := airouter.New(cfg, NewStoreTokenUsage(s))
+	_, err = restarted.Route(ctx, airouter.Request{Category: "missions", Fallback: []string{"openai"}}, nil, call)
+	var budgetErr *airouter.BudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "category", budgetErr.Scope)
+	assert.Equal(t, int64(120), budgetErr.Used)
+	assert.Equal(t, int64(120), restarted.Snapshot(ctx).CategoryUsage["missions"])
+
+	enforced, err := NewStoreTokenUsage(s).UserUsage(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), enforced.Total, "the user row is kept apart from the category totals")
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/kubestellar/console/pkg/agent"
+	"github.com/kubestellar/console/pkg/airouter"
 	"github.com/kubestellar/console/pkg/k8s"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/labels"
@@ -320,14 +321,6 @@ func (h *WorkloadHandlers) GenerateClusterQuery(c *fiber.Ctx) error {
 		clusterContext = buildClusterContextForAI(healthData)
 	}
 
-	// Get the default AI provider
-	registry := agent.GetRegistry()
-	provider, err := registry.GetDefault()
-	if err != nil {
-		slog.Info("[Workloads] no AI provider available", "error", err)
-		return c.Status(503).JSON(fiber.Map{"error": "service unavailable"})
-	}
-
 	systemPrompt := `You are a Kubernetes cluster query generator. Given a natural language description, generate a structured JSON query for selecting clusters from a multi-cluster environment.
 
 Respond with ONLY valid JSON, no markdown code fences, no explanation. The JSON format:
@@ -369,7 +362,23 @@ If the user's request doesn't need label selectors, omit the labelSelector field
 	aiCtx, aiCancel := context.WithTimeout(c.Context(), workloadWriteTimeout)
 	defer aiCancel()
 
-	resp, err := provider.Chat(aiCtx, chatReq)
+	// Route through the shared AI router: the default provider first, then
+	// the configured chain, charged against the caller's token budget.
+	registry := agent.GetRegistry()
+	resp, err := registry.Route(aiCtx, airouter.Request{
+		UserID:    resolveTokenUsageUserID(c),
+		Category:  airouter.CategoryOther,
+		Preferred: registry.GetDefaultName(),
+	}, func(ctx context.Context, provider agent.AIProvider) (*agent.ChatResponse, error) {
+		return provider.Chat(ctx, chatReq)
+	})
+	if handled, respErr := respondIfBudgetExceeded(c, err); handled {
+		return respErr
+	}
+	if err == airouter.ErrNoProvider { // no provider was even tried
+		slog.Info("[Workloads] no AI provider available", "error", err)
+		return c.Status(503).JSON(fiber.Map{"error": "service unavailable"})
+	}
 	if err != nil {
 		slog.Error("[Workloads] AI query generation failed", "error", err)
 		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/kubestellar/console/pkg/api/handlers"
+	"github.com/kubestellar/console/pkg/compliance/airgap"
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/kbmirror"
+)
+
+// missionsKBRequirementID is the air-gap requirement covering where the
+// mission knowledge base comes from.
+const missionsKBRequirementID = "ag-console-kb"
+
+// missionsKBDir is where imported console-kb bundles live.
+func missionsKBDir(cfg Config) string {
+	if cfg.MissionsKBDir != "" {
+		return cfg.MissionsKBDir
+	}
+	return filepath.Join(filepath.Dir(cfg.DatabasePath), "missions-kb")
+}
+
+// loadMissionsKBKey reads MISSIONS_KB_PUBLIC_KEY; nil means bundles need
+// not be signed.
+func loadMissionsKBKey(cfg Config) (ed25519.PublicKey, error) {
+	if cfg.MissionsKBPublicKey == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(cfg.MissionsKBPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("MISSIONS_KB_PUBLIC_KEY: %w", err)
+	}
+	key, err := kbmirror.ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("MISSIONS_KB_PUBLIC_KEY %s: %w", cfg.MissionsKBPublicKey, err)
+	}
+	return key, nil
+}
+
+// openMissionsKB opens the console-kb bundle mirror when MISSIONS_SOURCE is
+// "bundle". A mirror that cannot be opened, including one whose trusted key
+// cannot be read, is logged and left nil so missions are served from
+// GitHub rather than failing startup.
+func openMissionsKB(cfg Config) (*kbmirror.Mirror, *kbmirror.Syncer) {
+	switch cfg.MissionsSource {
+	case "", handlers.MissionsSourceGitHub:
+		return nil, nil
+	case handlers.MissionsSourceBundle:
+	default:
+		slog.Error("[Server] unknown MISSIONS_SOURCE, serving missions from GitHub", "value", cfg.MissionsSource)
+		return nil, nil
+	}
+	dir := missionsKBDir(cfg)
+	key, err := loadMissionsKBKey(cfg)
+	if err != nil {
+		slog.Error("[Server] mission bundle mirror disabled, serving missions from GitHub", "error", err)
+		return nil, nil
+	}
+	mirror, err := kbmirror.Open(dir, key)
+	if err != nil {
+		slog.Error("[Server] mission bundle mirror disabled, serving missions from GitHub", "dir", dir, "error", err)
+		return nil, nil
+	}
+	registry := oci.NewClient()
+	if cfg.MissionsKBOCIUsername != "" {
+		registry.Credentials = func(string) (string, string) {
+			return cfg.MissionsKBOCIUsername, cfg.MissionsKBOCIPassword
+		}
+	}
+	syncer, err := kbmirror.NewSyncer(mirror, kbmirror.SyncOptions{
+		BundlePath: cfg.MissionsKBBundle,
+		OCIRef:     cfg.MissionsKBOCI,
+		Registry:   registry,
+		Interval:   cfg.MissionsKBSyncInterval,
+	})
+	if err != nil {
+		slog.Error("[Server] mission bundle sync disabled", "error", err)
+		return mirror, nil
+	}
+	slog.Info("[Server] serving missions from console-kb bundle mirror", "dir", dir, "version", mirror.Status().Version,
+		"bundle", cfg.MissionsKBBundle, "oci", cfg.MissionsKBOCI, "signed", key != nil)
+	return mirror, syncer
+}
+
+// missionsKBRequirement reports whether missions are served without
+// reaching GitHub, for the air-gap readiness assessment.
+func (s *Server) missionsKBRequirement() airgap.Requirement {
+	r := airgap.Requirement{
+		ID:          missionsKBRequirementID,
+		Category:    "updates",
+		Name:        "Mission Knowledge Base Mirror",
+		Description: "Console missions are served from a verified local console-kb bundle instead of GitHub.",
+	}
+	if s.missionsKB == nil {
+		r.Status = "not_ready"
+		r.Evidence = "Missions are fetched live from github.com/kubestellar/console-kb"
+		r.Remediation = "Set MISSIONS_SOURCE=bundle and provide a console-kb bundle via MISSIONS_KB_BUNDLE or MISSIONS_KB_OCI"
+		return r
+	}
+	st := s.missionsKB.Status()
+	switch {
+	case !st.Installed:
+		r.Status = "not_ready"
+		r.Evidence = "Bundle mirror enabled but no console-kb bundle is installed"
+		r.Remediation = "Import a bundle with \"console kb import\" or set MISSIONS_KB_BUNDLE / MISSIONS_KB_OCI"
+	case !st.SignatureVerified:
+		r.Status = "partial"
+		r.Evidence = fmt.Sprintf("console-kb bundle %s served from disk; files checksum-verified, signature not required", st.Version)
+		r.Remediation = "Sign bundles and set MISSIONS_KB_PUBLIC_KEY to require signatures"
+	default:
+		r.Status = "ready"
+		r.Evidence = fmt.Sprintf("console-kb bundle %s served from disk; signature verified with key %s", st.Version, st.KeyID)
+	}
+	return r
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/kubestellar/console/pkg/api/handlers"
+)
+
+// setupAgentTunnelRoutes registers the kc-agent reverse tunnel when
+// AGENT_TUNNEL_ENABLED is set. Registration and the tunnel itself are
+// called by kc-agent, which cannot send a console JWT, so they sit outside
+// /api (like the Alertmanager webhook) and authenticate with the pairing
+// code and the per-agent credential instead.
+func (s *Server) setupAgentTunnelRoutes(routes *routeSetupContext) {
+	if !s.config.AgentTunnelEnabled {
+		return
+	}
+	// FrontendURL is also the CORS allowlist, comma-separated.
+	tunnel := handlers.NewAgentTunnelHandler(s.store, strings.Split(s.config.FrontendURL, ","))
+
+	s.app.Post("/agent-tunnel/register", routes.publicLimiter, routes.bodyGuard, tunnel.Register)
+	s.app.Get("/agent-tunnel/connect", routes.publicLimiter, tunnel.AuthenticateAgent, tunnel.Connect)
+
+	api := routes.api
+	api.Post("/agent-tunnel/pairing-codes", tunnel.CreatePairingCode)
+	api.Get("/agent-tunnel/agents", tunnel.ListAgents)
+	api.Delete("/agent-tunnel/agents/:id", tunnel.RevokeAgent)
+	api.All("/agent-tunnel/proxy/*", tunnel.Proxy)
+}
@@ -147,6 +147,9 @@ func (s *Server) setupAPICoreRoutes(routes *routeSetupContext) {
 	api.Get("/events", events.GetEvents)
 
 	missions := handlers.NewMissionsHandler().WithStore(s.store)
+	if s.missionsKB != nil {
+		missions.WithMirror(s.missionsKB, s.missionsKBSync)
+	}
 	missions.RegisterRoutes(api.Group("/missions"))
 
 	orbitDataDir := filepath.Dir(s.config.DatabasePath)
@@ -159,9 +162,17 @@ func (s *Server) setupAPICoreRoutes(routes *routeSetupContext) {
 
 	notificationHandler := handlers.NewNotificationHandler(s.store, s.notificationService)
 	api.Post("/notifications/test", notificationHandler.TestNotification)
+	api.Post("/notifications/templates/preview", notificationHandler.PreviewTemplate)
 	api.Post("/notifications/send", notificationHandler.SendAlertNotification)
 	api.Get("/notifications/config", notificationHandler.GetNotificationConfig)
 	api.Post("/notifications/config", notificationHandler.SaveNotificationConfig)
+	api.Get("/notifications/queue", notificationHandler.GetQueueStatus)
+	api.Post("/notifications/queue/:id/retry", notificationHandler.RetryDelivery)
+	api.Get("/notifications/silences", notificationHandler.ListSilences)
+	api.Post("/notifications/silences", notificationHandler.CreateSilence)
+	api.Delete("/notifications/silences/:id", notificationHandler.DeleteSilence)
+
+	handlers.NewAlertRuleHandler(s.alertEngine, s.store).RegisterRoutes(api)
 
 	persistenceHandler := handlers.NewConsolePersistenceHandlers(s.persistenceStore, s.k8sClient, s.hub, s.store)
 	api.Get("/persistence/config", persistenceHandler.GetConfig)
@@ -24,6 +24,7 @@ type routeSetupContext struct {
 	bodyGuard          fiber.Handler
 	feedback           *handlers.FeedbackHandler
 	namespaces         *handlers.NamespaceHandler
+	stellar            *handlers.StellarHandler
 }
 
 // oauthConfigured reports whether the server has a usable GitHub OAuth configuration.
@@ -1,6 +1,16 @@
 package api
 
-import "github.com/kubestellar/console/pkg/api/handlers"
+import (
+	"log/slog"
+
+	"github.com/kubestellar/console/pkg/api/audit"
+	"github.com/kubestellar/console/pkg/api/handlers"
+	"github.com/kubestellar/console/pkg/compliance/frameworks"
+	"github.com/kubestellar/console/pkg/compliance/gxp"
+	"github.com/kubestellar/console/pkg/compliance/hipaa"
+	"github.com/kubestellar/console/pkg/compliance/licenses"
+	"github.com/kubestellar/console/pkg/k8s"
+)
 
 // setupGovernanceRoutes registers RBAC, compliance, namespace, and admin routes.
 func (s *Server) setupGovernanceRoutes(routes *routeSetupContext) {
@@ -19,16 +29,61 @@ func (s *Server) setupGovernanceRoutes(routes *routeSetupContext) {
 
 	auditHandler := handlers.NewAuditHandler(s.store)
 	api.Get("/admin/audit-log", auditHandler.GetAuditLog)
+	api.Get("/admin/audit-log/verify", auditHandler.VerifyAuditChain)
+	// GxP / 21 CFR Part 11 reporting on the real audit chain; the demo
+	// endpoints stay public in setupPublicRoutes.
+	gxpEngine := gxp.NewLiveEngine(audit.NewGxPChainSource(s.store))
+	handlers.NewLiveGxPHandler(gxpEngine, s.store).RegisterLiveRoutes(api)
+
+	// Live HIPAA evaluation probes real clusters; the demo endpoints stay
+	// public in setupPublicRoutes.
+	var frameworksEvaluator *frameworks.Evaluator
+	if s.k8sClient != nil {
+		hipaaEngine := hipaa.NewLiveEngine(k8s.NewHIPAAProber(s.k8sClient), hipaa.LiveOptions{})
+		hipaaLive := handlers.NewLiveHIPAAHandler(hipaaEngine, s.healthyClusterNames)
+		hipaaLive.RegisterLiveRoutes(api)
 
-	complianceFrameworks := handlers.NewComplianceFrameworksHandler(nil)
+		// Live image signature verification (#9646); the demo endpoints stay
+		// public.
+		if signingEngine, err := newLiveSigningEngine(s.config, s.k8sClient); err != nil {
+			slog.Error("[Server] live signing verification disabled", "error", err)
+		} else {
+			handlers.NewLiveSigningHandler(signingEngine, s.healthyClusterNames).RegisterLiveRoutes(api)
+		}
+		if sbomEngine, err := newLiveSBOMEngine(s.config, s.k8sClient); err != nil {
+			slog.Error("[Server] live SBOM inventory disabled", "error", err)
+		} else {
+			handlers.NewLiveSBOMHandler(sbomEngine, s.healthyClusterNames).RegisterLiveRoutes(api)
+			// License compliance classifies the packages of the same SBOMs.
+			licenseEngine := licenses.NewLiveEngine(sbomEngine, s.store)
+			handlers.NewLiveLicenseHandler(licenseEngine, s.healthyClusterNames, s.store).RegisterLiveRoutes(api)
+		}
+		// Framework evaluation falls back to skipping provenance checks when
+		// the SLSA engine is unavailable.
+		var provenance k8s.ProvenanceSource
+		if slsaEngine, err := newLiveSLSAEngine(s.config, s.k8sClient); err != nil {
+			slog.Error("[Server] live SLSA provenance disabled", "error", err)
+		} else {
+			handlers.NewLiveSLSAHandler(slsaEngine, s.healthyClusterNames).RegisterLiveRoutes(api)
+			provenance = slsaEngine
+		}
+		frameworksEvaluator = frameworks.NewEvaluator(k8s.NewFrameworksProber(s.k8sClient, provenance))
+	}
+	// Without a cluster client the evaluator stays nil and the handlers
+	// return demo results.
+	complianceFrameworks := handlers.NewComplianceFrameworksHandler(frameworksEvaluator)
 	complianceFrameworks.RegisterRoutes(api.Group("/compliance/frameworks"))
-	complianceReports := handlers.NewComplianceReportsHandler(nil)
+	complianceReports := handlers.NewComplianceReportsHandler(frameworksEvaluator)
 	complianceReports.RegisterRoutes(api.Group("/compliance/frameworks"))
 
 	routes.namespaces = handlers.NewNamespaceHandler(s.store, s.k8sClient)
 	api.Get("/namespaces", routes.namespaces.ListNamespaces)
 	api.Get("/namespaces/:name/access", routes.namespaces.GetNamespaceAccess)
 
-	adminHandler := handlers.NewAdminHandler(s.failureTracker)
+	adminHandler := handlers.NewAdminHandler(s.failureTracker).
+		WithInformerCache(s.k8sClient, s.store).
+		WithAIRouter(s.aiRouter, s.store)
 	api.Get("/admin/rate-limit-status", adminHandler.GetRateLimitStatus)
+	api.Get("/admin/informer-cache", adminHandler.GetInformerCacheStatus)
+	api.Get("/admin/ai-routing", adminHandler.GetAIRouting)
 }
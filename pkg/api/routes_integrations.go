@@ -54,7 +54,13 @@ func (s *Server) setupIntegrationsRoutes(routes *routeSetupContext) {
 		}
 		return total
 	})
-	gpuHandler := handlers.NewGPUHandler(s.store, gpuCapacity, s.k8sClient)
+	// Pass a nil interface rather than a nil *MultiClusterClient so the
+	// handler and its lifecycle worker see that there is no cluster access.
+	gpuHandler := handlers.NewGPUHandler(s.store, gpuCapacity, nil)
+	if s.k8sClient != nil {
+		gpuHandler = handlers.NewGPUHandler(s.store, gpuCapacity, s.k8sClient)
+	}
+	s.gpuReservations = gpuHandler
 	api.Post("/gpu/reservations", gpuHandler.CreateReservation)
 	api.Get("/gpu/reservations", gpuHandler.ListReservations)
 	api.Get("/gpu/reservations/:id", gpuHandler.GetReservation)
@@ -62,6 +68,9 @@ func (s *Server) setupIntegrationsRoutes(routes *routeSetupContext) {
 	api.Delete("/gpu/reservations/:id", gpuHandler.DeleteReservation)
 	api.Get("/gpu/reservations/:id/utilization", gpuHandler.GetReservationUtilization)
 	api.Get("/gpu/utilizations", gpuHandler.GetBulkUtilizations)
+	api.Get("/gpu/calendar", gpuHandler.GetCapacityCalendar)
+	chargebackHandler := handlers.NewGPUChargebackHandler(s.store, s.chargebackConfig, gpuChargebackSampleGap())
+	api.Get("/gpu/chargeback", chargebackHandler.GetReport)
 
 	gadgetHandler := handlers.NewGadgetHandler(s.bridge)
 	api.Get("/gadget/status", gadgetHandler.GetStatus)
@@ -51,6 +51,7 @@ api.Post("/mcp/resourcequotas", mcpHandlers.CreateOrUpdateResourceQuota)
 api.Delete("/mcp/resourcequotas", mcpHandlers.DeleteResourceQuota)
 api.Get("/mcp/limitranges", mcpHandlers.GetLimitRanges)
 api.Get("/mcp/pods/logs", mcpHandlers.GetPodLogs)
+api.Get("/mcp/pods/logs/stream", mcpHandlers.StreamPodLogs)
 api.Post("/mcp/tools/ops/call", mcpHandlers.CallOpsTool)
 api.Post("/mcp/tools/deploy/call", mcpHandlers.CallDeployTool)
 api.Get("/mcp/wasmcloud/hosts", mcpHandlers.GetWasmCloudHosts)
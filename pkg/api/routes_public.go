@@ -78,8 +78,12 @@ s.app.Get("/api/youtube/thumbnail/:id", publicLimiter, handlers.YouTubeThumbnail
 // Medium blog (public — proxies to Medium RSS feed, cached 1h)
 s.app.Get("/api/medium/blog", publicLimiter, handlers.MediumBlogHandler)
 
-// Mission knowledge base browse/file (public — proxies to public GitHub repo)
+// Mission knowledge base browse/file (public — proxies to public GitHub repo,
+// or serves the local console-kb bundle mirror when MISSIONS_SOURCE=bundle)
 missions := handlers.NewMissionsHandler().WithStore(s.store)
+if s.missionsKB != nil {
+missions.WithMirror(s.missionsKB, s.missionsKBSync)
+}
 missions.RegisterPublicRoutes(s.app.Group("/api/missions"))
 
 // Compliance frameworks public read endpoints (no auth — needed for demo mode).
@@ -115,7 +119,7 @@ nistHandler.RegisterPublicRoutes(publicAPI)
 stigHandler := handlers.NewSTIGHandler()
 stigHandler.RegisterPublicRoutes(publicAPI)
 // Air-gap readiness public read endpoints (demo mode).
-airgapHandler := handlers.NewAirGapHandler()
+airgapHandler := handlers.NewAirGapHandler().WithConsoleCheck(s.missionsKBRequirement)
 airgapHandler.RegisterPublicRoutes(publicAPI)
 // FedRAMP readiness public read endpoints (demo mode).
 fedrampHandler := handlers.NewFedRAMPHandler()
@@ -6,6 +6,9 @@ import (
 
 	"github.com/kubestellar/console/pkg/api/handlers"
 	"github.com/kubestellar/console/pkg/safego"
+	"github.com/kubestellar/console/pkg/stellar/providers"
+	"github.com/kubestellar/console/pkg/stellar/scheduler"
+	"github.com/kubestellar/console/pkg/store"
 )
 
 // setupStellarRoutes registers all Stellar AI agent endpoints.
@@ -18,7 +21,13 @@ func (s *Server) setupStellarRoutes(routes *routeSetupContext) {
 
 	api := routes.api
 
+	// One provider registry for interactive asks and the cron engine, so
+	// scheduled missions resolve the same providers.
+	providerRegistry := providers.NewRegistry()
 	stellar := handlers.NewStellarHandler(stelStore, s.k8sClient)
+	stellar.SetProviderRegistry(providerRegistry)
+	routes.stellar = stellar
+	s.configureStellarMemoryEmbedder(providerRegistry)
 
 	// Derive a context that is cancelled when the server shuts down.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -29,6 +38,16 @@ func (s *Server) setupStellarRoutes(routes *routeSetupContext) {
 	stellar.StartBackgroundWorkers(ctx)
 	stellar.StartStellarV2Workers(ctx)
 
+	// Cron engine — fires scheduled missions and recurring actions.
+	if cronStore, ok := s.store.(scheduler.SchedulerStore); ok {
+		cron := scheduler.New(cronStore, s.k8sClient)
+		cron.SetProviderRegistry(providerRegistry)
+		cron.SetBroadcaster(stellarSchedulerBroadcaster{h: stellar})
+		safego.GoWith("stellar-cron-engine", func() { cron.StartCron(ctx) })
+	} else {
+		slog.Warn("[Server] store does not implement scheduler.SchedulerStore — scheduled missions will not run")
+	}
+
 	// Preferences
 	api.Get("/stellar/preferences", stellar.GetPreferences)
 	api.Put("/stellar/preferences", stellar.UpdatePreferences)
@@ -109,3 +128,54 @@ func (s *Server) setupStellarRoutes(routes *routeSetupContext) {
 	// Health
 	api.Get("/stellar/health", stellar.Health)
 }
+
+// stellarSchedulerBroadcaster forwards scheduler events onto the Stellar SSE
+// stream so scheduled mission results show up live.
+type stellarSchedulerBroadcaster struct {
+	h *handlers.StellarHandler
+}
+
+func (b stellarSchedulerBroadcaster) Broadcast(event scheduler.BroadcastEvent) {
+	b.h.Broadcast(handlers.SSEEvent{Type: event.Type, Data: event.Data})
+}
+
+// stellarEmbeddingBackfillLimit bounds how many pre-existing memories are
+// embedded at startup; later entries are embedded as they are created.
+const stellarEmbeddingBackfillLimit = 200
+
+// configureStellarMemoryEmbedder wires the provider registry's embedding
+// endpoint into the store so Stellar memory search is semantic. Without an
+// embedding-capable provider the store keeps its keyword search.
+func (s *Server) configureStellarMemoryEmbedder(reg *providers.Registry) {
+	type memoryEmbedderStore interface {
+		SetMemoryEmbedder(fn store.MemoryEmbedFunc)
+		BackfillStellarMemoryEmbeddings(ctx context.Context, limit int) (int, error)
+	}
+	st, ok := s.store.(memoryEmbedderStore)
+	if !ok {
+		return
+	}
+	embedder, model := reg.ResolveEmbedder(context.Background())
+	if embedder == nil {
+		slog.Info("[Server] no embedding provider available — Stellar memory uses keyword search")
+		return
+	}
+	st.SetMemoryEmbedder(func(ctx context.Context, text string) ([]float32, error) {
+		vecs, err := embedder.Embed(ctx, model, []string{text})
+		if err != nil {
+			return nil, err
+		}
+		return vecs[0], nil
+	})
+	slog.Info("[Server] Stellar semantic memory enabled", "provider", embedder.Name(), "model", model)
+	safego.GoWith("stellar-memory-embedding-backfill", func() {
+		n, err := st.BackfillStellarMemoryEmbeddings(context.Background(), stellarEmbeddingBackfillLimit)
+		if err != nil {
+			slog.Warn("[Server] Stellar memory embedding backfill stopped", "embedded", n, "error", err)
+			return
+		}
+		if n > 0 {
+			slog.Info("[Server] Stellar memory embedding backfill complete", "embedded", n)
+		}
+	})
+}
@@ -21,6 +21,17 @@ func (s *Server) setupWebSocketStaticRoutes(routes *routeSetupContext) {
 	}
 	s.app.Post("/webhooks/github", feedback.HandleGitHubWebhook)
 
+	// Alertmanager cannot send a console JWT, so its receiver sits outside
+	// /api and authenticates with a shared bearer token instead.
+	var stellarFeed handlers.AlertmanagerStellarStore
+	var stellarBroadcaster handlers.SSEBroadcaster
+	if routes.stellar != nil {
+		stellarFeed, _ = s.store.(handlers.AlertmanagerStellarStore)
+		stellarBroadcaster = routes.stellar
+	}
+	alertmanager := handlers.NewAlertmanagerHandler(s.config.AlertmanagerWebhookToken, s.notificationService, stellarFeed, stellarBroadcaster)
+	s.app.Post("/webhooks/alertmanager", alertmanager.Receive)
+
 	s.app.Use("/ws", routes.publicLimiter, middleware.WebSocketUpgrade())
 	s.app.Get("/ws", websocket.New(func(c *websocket.Conn) {
 		s.hub.HandleConnection(c)
@@ -14,14 +14,19 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/kubestellar/console/pkg/agent"
+	"github.com/kubestellar/console/pkg/airouter"
+	"github.com/kubestellar/console/pkg/alerting"
 	"github.com/kubestellar/console/pkg/api/audit"
 	"github.com/kubestellar/console/pkg/api/handlers"
 	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/chargeback"
 	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/kbmirror"
 	"github.com/kubestellar/console/pkg/mcp"
 	"github.com/kubestellar/console/pkg/notifications"
 	"github.com/kubestellar/console/pkg/safego"
 	"github.com/kubestellar/console/pkg/settings"
+	"github.com/kubestellar/console/pkg/stellar/providers"
 	"github.com/kubestellar/console/pkg/store"
 )
 
@@ -52,9 +57,15 @@ type Server struct {
 	oauthMu             sync.RWMutex          // protects authHandler during manifest flow hot-reload
 	shuttingDown        int32                 // atomic flag: 1 during graceful shutdown
 	gpuUtilWorker       *GPUUtilizationWorker
+	gpuReservations     *handlers.GPUHandler // drives the reservation lifecycle
+	chargebackConfig    *chargeback.Config   // GPU chargeback rates and teams; nil when unconfigured
+	missionsKB          *kbmirror.Mirror     // local console-kb mirror; nil when missions come from GitHub
+	missionsKBSync      *kbmirror.Syncer     // pulls new console-kb bundles into missionsKB
+	alertEngine         *alerting.Engine
 	workloadHandlers    *handlers.WorkloadHandlers // for cache refresh shutdown (#10007)
 	rewardsHandler      *handlers.RewardsHandler   // for eviction goroutine shutdown
 	failureTracker      *middleware.FailureTracker // tracks auth failure counts for rate limiting
+	aiRouter            *airouter.Router           // provider routing shared by kc-agent providers and Stellar
 	done                chan struct{}              // closed on Shutdown to stop background goroutines
 	shutdownOnce        sync.Once                  // ensures Shutdown is idempotent (#6478)
 	quantumWorkloadMu   sync.RWMutex               // protects quantum workload cache
@@ -109,8 +120,15 @@ func NewServer(cfg Config) (*Server, error) {
 
 	// --- Heavy initialization (loading page is already being served) ---
 
-	// Initialize store
-	db, err := store.NewSQLiteStore(cfg.DatabasePath)
+	// Initialize store. DATABASE_URL selects PostgreSQL, which lets several
+	// replicas share one database; otherwise the SQLite file is used.
+	var db store.Store
+	var err error
+	if cfg.DatabaseURL != "" {
+		db, err = store.NewPostgresStore(cfg.DatabaseURL)
+	} else {
+		db, err = store.NewSQLiteStore(cfg.DatabasePath)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize store: %w", err)
 	}
@@ -190,6 +208,7 @@ func NewServer(cfg Config) (*Server, error) {
 				slog.Warn("Kubeconfig file watcher failed to start", "error", err)
 			}
 		}
+		enableInformerCache(cfg, k8sClient)
 	}
 
 	// Initialize AI providers
@@ -221,6 +240,7 @@ func NewServer(cfg Config) (*Server, error) {
 
 	// Initialize notification service
 	notificationService := notifications.NewService()
+	notificationService.EnableQueue(db, notifications.QueueOptions{})
 	slog.Info("Notification service initialized")
 
 	// Initialize persistence store
@@ -250,9 +270,13 @@ func NewServer(cfg Config) (*Server, error) {
 		loadingSrv:          loadingSrv,
 		done:                make(chan struct{}),
 	}
+	server.alertEngine = alerting.NewEngine(db, newAlertSource(k8sClient), notificationService, cfg.AlertEvalInterval)
 
 	// Enable SQLite persistence for audit entries (#8670 Phase 3).
 	audit.SetStore(db)
+	checkpointer := newAuditCheckpointer(cfg, db)
+	server.chargebackConfig = loadChargebackConfig(cfg)
+	server.missionsKB, server.missionsKBSync = openMissionsKB(cfg)
 
 	server.setupMiddleware()
 	server.setupRoutes()
@@ -264,7 +288,33 @@ func NewServer(cfg Config) (*Server, error) {
 	} else {
 		slog.Info("[Server] GPU utilization worker skipped — no Kubernetes client available")
 	}
-	server.startKBGapsSweeper(db)
+	// Activate, expire and promote GPU reservations as their windows pass.
+	if server.gpuReservations != nil {
+		server.goUntilDone("api/gpu-reservation-lifecycle", server.gpuReservations.RunLifecycle)
+	}
+	// Email last month's GPU chargeback reports when configured.
+	if scheduler := chargeback.NewScheduler(db, server.chargebackConfig, gpuChargebackSampleGap()); scheduler != nil {
+		server.goUntilDone("api/gpu-chargeback-reports", scheduler.Run)
+	}
+	// Import new console-kb bundles from the configured file or registry.
+	if server.missionsKBSync != nil {
+		server.goUntilDone("api/missions-kb-sync", server.missionsKBSync.Run)
+	}
+	// Deliver queued notifications, retrying failures with backoff.
+	server.goUntilDone("api/notification-queue", server.notificationService.Queue().Run)
+	// Evaluate server-side alert rules so alerts fire with no browser open.
+	if k8sClient != nil {
+		server.goUntilDone("api/alert-engine", server.alertEngine.Run)
+	} else {
+		slog.Info("[Server] alert rule engine skipped — no Kubernetes client available")
+	}
+	if gapStore, ok := db.(kbGapSweeper); ok {
+		server.startKBGapsSweeper(gapStore)
+	}
+	server.startAuditExport()
+	if checkpointer != nil {
+		server.goUntilDone("api/audit-checkpoints", checkpointer.Run)
+	}
 
 	slog.Info("Server initialization complete")
 
@@ -277,6 +327,8 @@ func (s *Server) setupRoutes() {
 	// Resolve OAuth credentials from SQLite if env vars are empty (manifest flow).
 	s.resolveOAuthCredentials()
 
+	s.setupAIRouter()
+
 	routes := s.setupAuthRoutes(s.app)
 	s.setupPublicRoutes(routes.publicLimiter, routes.analyticsBodyGuard, routes.publicAPI)
 	s.setupAPICoreRoutes(routes)
@@ -284,9 +336,133 @@ func (s *Server) setupRoutes() {
 	s.setupIntegrationsRoutes(routes)
 	s.setupFeedbackRoutes(routes)
 	s.setupStellarRoutes(routes)
+	s.setupAgentTunnelRoutes(routes)
 	s.setupWebSocketStaticRoutes(routes)
 }
 
+// setupAIRouter builds the AI provider router from the AI_ROUTER_*
+// environment and installs it in the kc-agent and Stellar registries, so
+// both share one set of breakers and budgets. Per-user spend is read from
+// and written to the same daily token-usage rows the UI shows.
+func (s *Server) setupAIRouter() {
+	cfg, err := airouter.ConfigFromEnv()
+	if err != nil {
+		slog.Error("[Server] invalid AI routing configuration, budgets and chains disabled", "error", err)
+		cfg = airouter.Config{}
+	}
+	var usage airouter.UsageStore
+	if s.store != nil {
+		usage = handlers.NewStoreTokenUsage(s.store)
+	}
+	s.aiRouter = airouter.New(cfg, usage)
+	agent.GetRegistry().SetRouter(s.aiRouter)
+	providers.SetRouter(s.aiRouter)
+}
+
+// goUntilDone runs fn in the background with a context that is cancelled
+// on Shutdown.
+func (s *Server) goUntilDone(name string, fn func(ctx context.Context)) {
+	safego.GoWith(name, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		safego.GoWith(name+"-stop", func() {
+			select {
+			case <-s.done:
+				cancel()
+			case <-ctx.Done():
+			}
+		})
+		fn(ctx)
+	})
+}
+
+// newAuditCheckpointer loads (or creates) the audit checkpoint signing key
+// at AUDIT_CHECKPOINT_KEY and returns the checkpointer that signs the audit
+// chain head. There is no default location: a key stored beside the
+// database would be readable by anyone able to rewrite the log. Without a
+// key the chain is still written and verifiable; only checkpoints stop.
+func newAuditCheckpointer(cfg Config, db store.Store) *audit.Checkpointer {
+	keyPath := cfg.AuditCheckpointKey
+	if keyPath == "" {
+		slog.Warn("[Server] audit checkpoints disabled: AUDIT_CHECKPOINT_KEY is not set")
+		return nil
+	}
+	key, err := audit.LoadOrCreateCheckpointKey(keyPath)
+	if err != nil {
+		slog.Error("[Server] audit checkpoints disabled: failed to load signing key", "path", keyPath, "error", err)
+		return nil
+	}
+	audit.SetCheckpointKey(key, cfg.AuditCheckpointInterval)
+	return audit.NewCheckpointer(db, key, cfg.AuditCheckpointInterval)
+}
+
+// enableInformerCache turns on the per-cluster informer cache when
+// K8S_INFORMER_CACHE is set. An unknown resource in K8S_INFORMER_RESOURCES
+// is logged and leaves the cache off rather than failing startup.
+func enableInformerCache(cfg Config, client *k8s.MultiClusterClient) {
+	if !cfg.InformerCache {
+		return
+	}
+	resources, err := k8s.ParseInformerResources(cfg.InformerResources)
+	if err != nil {
+		slog.Error("[Server] K8S_INFORMER_RESOURCES invalid, informer cache disabled", "error", err)
+		return
+	}
+	client.EnableInformerCache(k8s.InformerCacheOptions{Resources: resources})
+}
+
+// loadChargebackConfig loads GPU_CHARGEBACK_CONFIG. A broken config is
+// logged and leaves reports without cost and teams rather than failing
+// startup.
+func loadChargebackConfig(cfg Config) *chargeback.Config {
+	if cfg.GPUChargebackConfig == "" {
+		return nil
+	}
+	c, err := chargeback.LoadConfigFile(cfg.GPUChargebackConfig)
+	if err != nil {
+		slog.Error("[Server] GPU chargeback config ignored", "error", err)
+		return nil
+	}
+	return c
+}
+
+// gpuChargebackSampleGap is how long one utilization snapshot counts as
+// used in chargeback reports: two poll intervals, so a single missed poll
+// is bridged but a collection outage is not billed as use.
+func gpuChargebackSampleGap() time.Duration {
+	return 2 * gpuUtilPollInterval()
+}
+
+// startAuditExport registers the SIEM destinations in AUDIT_EXPORT_CONFIG
+// and starts the spooled export pipeline. A broken config is logged and
+// leaves export disabled rather than failing startup.
+func (s *Server) startAuditExport() {
+	if s.config.AuditExportConfig == "" {
+		return
+	}
+	cfgs, err := audit.LoadDestinationsFile(s.config.AuditExportConfig)
+	if err != nil {
+		slog.Error("[Server] audit export disabled: failed to load destinations", "error", err)
+		return
+	}
+	spoolDir := s.config.AuditSpoolDir
+	if spoolDir == "" {
+		spoolDir = filepath.Join(filepath.Dir(s.config.DatabasePath), "audit-spool")
+	}
+	pipeline, err := audit.OpenPipeline(spoolDir, audit.PipelineOptions{})
+	if err != nil {
+		slog.Error("[Server] audit export disabled: failed to open spool", "dir", spoolDir, "error", err)
+		return
+	}
+	for _, cfg := range cfgs {
+		if _, err := audit.RegisterDestination(cfg); err != nil {
+			slog.Error("[Server] skipping audit export destination", "id", cfg.ID, "error", err)
+		}
+	}
+	s.goUntilDone("api/audit-export", pipeline.Run)
+	slog.Info("[Server] audit export pipeline started", "destinations", len(audit.ListDestinations()), "spool", spoolDir)
+}
+
 func (s *Server) startKBGapsSweeper(gapStore kbGapSweeper) {
 	if gapStore == nil {
 		return
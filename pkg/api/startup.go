@@ -273,6 +273,7 @@ func (s *Server) Shutdown() error {
 		middleware.ShutdownTokenRevocation()
 		if s.k8sClient != nil {
 			s.k8sClient.StopWatching()
+			s.k8sClient.StopInformers()
 		}
 		if s.bridge != nil {
 			if err := s.bridge.Stop(); err != nil {
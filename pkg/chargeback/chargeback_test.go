@@ -0,0 +1,306 @@
+package chargeback
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/notifications"
+)
+
+var (
+	monthStart = time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd   = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := &Config{
+		DefaultHourlyRate: 1,
+		HourlyRates:       map[string]float64{"A100": 3, "A100-80GB": 4},
+		Teams: []TeamConfig{
+			{Name: "ml", Namespaces: []string{"ml-*"}},
+			{Name: "research", Users: []string{"carol"}},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	return cfg
+}
+
+// reservation builds a reservation activated at the start of its window
+// when status is active or completed.
+func reservation(user, cluster, ns, gpuType string, gpus int, start time.Time, hours int, status models.ReservationStatus) models.GPUReservation {
+	r := models.GPUReservation{
+		ID:            uuid.New(),
+		UserName:      user,
+		Cluster:       cluster,
+		Namespace:     ns,
+		GPUType:       gpuType,
+		GPUCount:      gpus,
+		StartDate:     start.Format(time.RFC3339),
+		DurationHours: hours,
+		Status:        status,
+	}
+	if status == models.ReservationStatusActive || status == models.ReservationStatusCompleted {
+		r.ActivatedAt = &start
+	}
+	return r
+}
+
+// hourlySnapshots records active GPUs once an hour over [start, start+hours).
+func hourlySnapshots(r models.GPUReservation, start time.Time, hours, active int) []models.GPUUtilizationSnapshot {
+	out := make([]models.GPUUtilizationSnapshot, 0, hours)
+	for h := 0; h < hours; h++ {
+		out = append(out, models.GPUUtilizationSnapshot{
+			ReservationID:  r.ID.String(),
+			Timestamp:      start.Add(time.Duration(h) * time.Hour),
+			ActiveGPUCount: active,
+			TotalGPUCount:  r.GPUCount,
+		})
+	}
+	return out
+}
+
+func findRow(t *testing.T, r *Report, group string) Row {
+	t.Helper()
+	for _, row := range r.Rows {
+		if row.Group == group {
+			return row
+		}
+	}
+	t.Fatalf("no row %q in %+v", group, r.Rows)
+	return Row{}
+}
+
+func TestConfig_HourlyRateAndTeam(t *testing.T) {
+	cfg := testConfig(t)
+	cases := map[string]float64{
+		"NVIDIA A100-80GB-SXM": 4,
+		"nvidia-a100":          3,
+		"H100":                 1,
+		"":                     1,
+	}
+	for gpuType, want := range cases {
+		if got := cfg.HourlyRate(gpuType); got != want {
+			t.Errorf("HourlyRate(%q) = %v, want %v", gpuType, got, want)
+		}
+	}
+	if got := cfg.Team("c1", "ml-train", "alice"); got != "ml" {
+		t.Errorf("namespace team = %q", got)
+	}
+	if got := cfg.Team("c1", "default", "Carol"); got != "research" {
+		t.Errorf("user team = %q", got)
+	}
+	if got := cfg.Team("c1", "default", "dave"); got != UnassignedTeam {
+		t.Errorf("unclaimed team = %q", got)
+	}
+
+	bad := &Config{MonthlyReport: &MonthlyReportConfig{Day: 31}}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected day 31 to be rejected")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	cfg := testConfig(t)
+	now := monthEnd.Add(10 * 24 * time.Hour)
+
+	// 2 A100s for 10h, half used.
+	train := reservation("alice", "c1", "ml-train", "NVIDIA A100", 2, monthStart.Add(24*time.Hour), 10, models.ReservationStatusCompleted)
+	// Straddles the end of the month: only 4 of its 8 hours fall inside.
+	straddle := reservation("bob", "c1", "default", "H100", 1, monthEnd.Add(-4*time.Hour), 8, models.ReservationStatusActive)
+	// Completed after 2 of 24 hours.
+	early := reservation("carol", "c2", "lab", "", 1, monthStart.Add(48*time.Hour), 24, models.ReservationStatusCompleted)
+	endedAt := monthStart.Add(50 * time.Hour)
+	early.UpdatedAt = &endedAt
+	// Never billed.
+	cancelled := reservation("bob", "c1", "default", "", 8, monthStart.Add(72*time.Hour), 8, models.ReservationStatusCancelled)
+	cancelledAt := monthStart.Add(time.Hour)
+	cancelled.UpdatedAt = &cancelledAt
+	waitlisted := reservation("bob", "c1", "default", "", 8, monthStart.Add(72*time.Hour), 8, models.ReservationStatusWaitlisted)
+	// Completed by the lifecycle after its window passed while scheduled.
+	neverActivated := reservation("bob", "c1", "default", "", 4, monthStart.Add(96*time.Hour), 8, models.ReservationStatusCompleted)
+	neverActivated.ActivatedAt = nil
+	expiredAt := monthStart.Add(110 * time.Hour)
+	neverActivated.UpdatedAt = &expiredAt
+	// Cancelled by the lifecycle after its window passed while waitlisted.
+	lateCancelled := reservation("bob", "c1", "default", "", 4, monthStart.Add(120*time.Hour), 8, models.ReservationStatusCancelled)
+	lateCancelledAt := monthStart.Add(130 * time.Hour)
+	lateCancelled.UpdatedAt = &lateCancelledAt
+
+	reservations := []models.GPUReservation{train, straddle, early, cancelled, waitlisted, neverActivated, lateCancelled}
+	snapshots := hourlySnapshots(train, monthStart.Add(24*time.Hour), 10, 1)
+
+	byUser := Build(cfg, reservations, snapshots, Options{From: monthStart, To: monthEnd, GroupBy: GroupByUser, Now: now, MaxSampleGap: time.Hour})
+	alice := findRow(t, byUser, "alice")
+	if alice.ReservedGPUHours != 20 || alice.UsedGPUHours != 10 || alice.IdleWastePct != 50 {
+		t.Errorf("alice = %+v", alice)
+	}
+	if alice.Cost != 60 || alice.UsedCost != 30 || alice.IdleCost != 30 {
+		t.Errorf("alice cost = %+v", alice)
+	}
+	bob := findRow(t, byUser, "bob")
+	if bob.Reservations != 1 || bob.ReservedGPUHours != 4 || bob.Cost != 4 || bob.IdleWastePct != 100 {
+		t.Errorf("bob = %+v", bob)
+	}
+	if carol := findRow(t, byUser, "carol"); carol.ReservedGPUHours != 2 {
+		t.Errorf("carol = %+v", carol)
+	}
+	if byUser.Total.ReservedGPUHours != 26 || byUser.Total.Cost != 66 || byUser.Total.Reservations != 3 {
+		t.Errorf("total = %+v", byUser.Total)
+	}
+	if byUser.Rows[0].Group != "alice" {
+		t.Errorf("rows not ranked by cost: %+v", byUser.Rows)
+	}
+
+	byTeam := Build(cfg, reservations, snapshots, Options{From: monthStart, To: monthEnd, GroupBy: GroupByTeam, Now: now})
+	if got := findRow(t, byTeam, UnassignedTeam); got.ReservedGPUHours != 4 {
+		t.Errorf("unassigned = %+v", got)
+	}
+	findRow(t, byTeam, "ml")
+	findRow(t, byTeam, "research")
+
+	byNamespace := Build(cfg, reservations, snapshots, Options{From: monthStart, To: monthEnd, GroupBy: GroupByNamespace, Now: now})
+	findRow(t, byNamespace, "c1/ml-train")
+
+	// A snapshot gap longer than MaxSampleGap is not counted as use.
+	sparse := []models.GPUUtilizationSnapshot{snapshots[0]}
+	gapped := Build(cfg, []models.GPUReservation{train}, sparse, Options{From: monthStart, To: monthEnd, Now: now, MaxSampleGap: time.Hour})
+	if got := gapped.Total.UsedGPUHours; got != 1 {
+		t.Errorf("used with one snapshot = %v, want 1", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := &Report{
+		From: monthStart, To: monthEnd, GroupBy: GroupByUser, Currency: "EUR",
+		Rows:  []Row{{Group: "=cmd()", Reservations: 1, ReservedGPUHours: 1.5, Cost: 3}},
+		Total: Row{Group: "total", Reservations: 1, ReservedGPUHours: 1.5, Cost: 3},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want header, row and total", len(records))
+	}
+	if records[1][1] != "'=cmd()" {
+		t.Errorf("formula not neutralized: %q", records[1][1])
+	}
+	if records[2][1] != "total" || records[2][4] != "EUR" || records[2][6] != "1.50" {
+		t.Errorf("total record = %v", records[2])
+	}
+	if got := Filename(report, FormatCSV); got != "gpu-chargeback-user-2026-09-01-2026-10-01.csv" {
+		t.Errorf("Filename = %q", got)
+	}
+}
+
+type memStore struct {
+	reservations []models.GPUReservation
+	snapshots    []models.GPUUtilizationSnapshot
+	claimed      map[string]bool
+}
+
+func (m *memStore) ListGPUReservations(context.Context) ([]models.GPUReservation, error) {
+	return m.reservations, nil
+}
+
+func (m *memStore) ListUtilizationSnapshotsInRange(_ context.Context, start, end time.Time) ([]models.GPUUtilizationSnapshot, error) {
+	var out []models.GPUUtilizationSnapshot
+	for _, s := range m.snapshots {
+		if !s.Timestamp.Before(start) && s.Timestamp.Before(end) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) ClaimGPUChargebackRun(_ context.Context, period string) (bool, error) {
+	if m.claimed[period] {
+		return false, nil
+	}
+	m.claimed[period] = true
+	return true, nil
+}
+
+func (m *memStore) ReleaseGPUChargebackRun(_ context.Context, period string) error {
+	delete(m.claimed, period)
+	return nil
+}
+
+type recordingMailer struct {
+	subjects    []string
+	attachments [][]notifications.EmailAttachment
+	err         error
+}
+
+func (m *recordingMailer) SendReport(subject, _ string, attachments []notifications.EmailAttachment) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.subjects = append(m.subjects, subject)
+	m.attachments = append(m.attachments, attachments)
+	return nil
+}
+
+func TestScheduler_SendsLastMonthOnce(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MonthlyReport = &MonthlyReportConfig{Day: 2, Hour: 6, SMTPHost: "smtp.example.com", From: "console@example.com", Recipients: "finance@example.com"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	train := reservation("alice", "c1", "ml-train", "A100", 2, monthStart.Add(24*time.Hour), 10, models.ReservationStatusCompleted)
+	st := &memStore{reservations: []models.GPUReservation{train}, claimed: map[string]bool{}}
+
+	sched := NewScheduler(st, cfg, 0)
+	mailer := &recordingMailer{}
+	sched.mailer = mailer
+	now := monthEnd.Add(24 * time.Hour) // October 2nd, 00:00 — before 06:00
+	sched.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := sched.tick(ctx); err != nil || len(mailer.subjects) != 0 {
+		t.Fatalf("sent before due: err=%v subjects=%v", err, mailer.subjects)
+	}
+
+	now = now.Add(7 * time.Hour)
+	mailer.err = errors.New("smtp down")
+	if err := sched.tick(ctx); err == nil {
+		t.Fatal("expected send failure")
+	}
+	if st.claimed["2026-09"] {
+		t.Fatal("failed send must release its claim")
+	}
+
+	mailer.err = nil
+	if err := sched.tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.subjects) != 1 || mailer.subjects[0] != "GPU chargeback report 2026-09" {
+		t.Fatalf("subjects = %v", mailer.subjects)
+	}
+	attachments := mailer.attachments[0]
+	if len(attachments) != 3 {
+		t.Fatalf("got %d attachments, want user, namespace and team", len(attachments))
+	}
+	if attachments[0].Filename != "gpu-chargeback-user-2026-09-01-2026-10-01.csv" ||
+		!strings.Contains(string(attachments[0].Data), "alice") {
+		t.Errorf("user attachment = %s: %s", attachments[0].Filename, attachments[0].Data)
+	}
+}
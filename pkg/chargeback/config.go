@@ -0,0 +1,184 @@
+package chargeback
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency is used when the config names none.
+const DefaultCurrency = "USD"
+
+// UnassignedTeam is the team of a reservation no team claims.
+const UnassignedTeam = "unassigned"
+
+// Config prices GPU time and maps reservations to teams. It is loaded from
+// the JSON file named by GPU_CHARGEBACK_CONFIG; the zero value reports
+// GPU-hours with no cost and every reservation unassigned.
+type Config struct {
+	Currency string `json:"currency"`
+	// DefaultHourlyRate is the price of one GPU-hour of a type HourlyRates
+	// does not list.
+	DefaultHourlyRate float64 `json:"default_hourly_rate"`
+	// HourlyRates prices one GPU-hour by GPU type. Keys match a
+	// reservation's GPU type case-insensitively, exactly or as a substring
+	// ("A100" prices "NVIDIA A100-SXM4-80GB"); the longest match wins.
+	HourlyRates map[string]float64 `json:"hourly_rates"`
+	// Teams are tried in order; the first that claims a reservation owns it.
+	Teams         []TeamConfig         `json:"teams"`
+	MonthlyReport *MonthlyReportConfig `json:"monthly_report,omitempty"`
+}
+
+// TeamConfig claims reservations for a team by namespace or by owner.
+type TeamConfig struct {
+	Name string `json:"name"`
+	// Namespaces are path.Match patterns against "cluster/namespace" or
+	// just "namespace", e.g. "ml-*" or "prod-*/training".
+	Namespaces []string `json:"namespaces"`
+	// Users are GitHub logins of reservation owners.
+	Users []string `json:"users"`
+}
+
+// MonthlyReportConfig emails the previous calendar month's reports.
+type MonthlyReportConfig struct {
+	// Day of the month (1-28) and UTC hour (0-23) to send on.
+	Day  int `json:"day"`
+	Hour int `json:"hour"`
+	// GroupBy lists the reports to attach; default user, namespace, team.
+	GroupBy []GroupBy `json:"group_by"`
+	// Format of the attachments: "csv" (default) or "json".
+	Format     string `json:"format"`
+	SMTPHost   string `json:"smtp_host"`
+	SMTPPort   int    `json:"smtp_port"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	From       string `json:"from"`
+	Recipients string `json:"recipients"` // comma-separated
+}
+
+// Report formats.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// LoadConfigFile reads and validates a chargeback config file.
+func LoadConfigFile(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the config and fills in defaults.
+func (c *Config) Validate() error {
+	if c.Currency == "" {
+		c.Currency = DefaultCurrency
+	}
+	if c.DefaultHourlyRate < 0 {
+		return errors.New("default_hourly_rate must not be negative")
+	}
+	for gpuType, rate := range c.HourlyRates {
+		if gpuType == "" || rate < 0 {
+			return fmt.Errorf("hourly_rates: invalid rate %v for GPU type %q", rate, gpuType)
+		}
+	}
+	for i, t := range c.Teams {
+		if t.Name == "" {
+			return fmt.Errorf("teams[%d]: name is required", i)
+		}
+		for _, p := range t.Namespaces {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("teams[%d]: invalid namespace pattern %q", i, p)
+			}
+		}
+	}
+	if m := c.MonthlyReport; m != nil {
+		if m.Day < 1 || m.Day > 28 {
+			return errors.New("monthly_report.day must be between 1 and 28")
+		}
+		if m.Hour < 0 || m.Hour > 23 {
+			return errors.New("monthly_report.hour must be between 0 and 23")
+		}
+		if len(m.GroupBy) == 0 {
+			m.GroupBy = []GroupBy{GroupByUser, GroupByNamespace, GroupByTeam}
+		}
+		for _, g := range m.GroupBy {
+			if !g.IsValid() {
+				return fmt.Errorf("monthly_report.group_by: unknown grouping %q", g)
+			}
+		}
+		switch m.Format {
+		case "":
+			m.Format = FormatCSV
+		case FormatCSV, FormatJSON:
+		default:
+			return fmt.Errorf("monthly_report.format must be %q or %q", FormatCSV, FormatJSON)
+		}
+		if m.SMTPHost == "" || m.From == "" || m.Recipients == "" {
+			return errors.New("monthly_report: smtp_host, from and recipients are required")
+		}
+	}
+	return nil
+}
+
+// HourlyRate returns the price of one GPU-hour of gpuType.
+func (c *Config) HourlyRate(gpuType string) float64 {
+	needle := strings.ToLower(gpuType)
+	if needle == "" {
+		return c.DefaultHourlyRate
+	}
+	keys := make([]string, 0, len(c.HourlyRates))
+	for k := range c.HourlyRates {
+		keys = append(keys, k)
+	}
+	// Longest key first so "A100-80GB" beats "A100"; ties break by name
+	// to keep the choice stable.
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		hay := strings.ToLower(k)
+		if hay == needle || strings.Contains(needle, hay) {
+			return c.HourlyRates[k]
+		}
+	}
+	return c.DefaultHourlyRate
+}
+
+// Team returns the team owning a reservation in cluster/namespace made by
+// user, or UnassignedTeam.
+func (c *Config) Team(cluster, namespace, user string) string {
+	qualified := cluster + "/" + namespace
+	for _, t := range c.Teams {
+		for _, p := range t.Namespaces {
+			if ok, _ := path.Match(p, qualified); ok {
+				return t.Name
+			}
+			if ok, _ := path.Match(p, namespace); ok {
+				return t.Name
+			}
+		}
+		for _, u := range t.Users {
+			if strings.EqualFold(u, user) {
+				return t.Name
+			}
+		}
+	}
+	return UnassignedTeam
+}
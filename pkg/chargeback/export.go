@@ -0,0 +1,91 @@
+package chargeback
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader is the column order of WriteCSV.
+var csvHeader = []string{
+	"group_by", "group", "from", "to", "currency", "reservations",
+	"reserved_gpu_hours", "used_gpu_hours", "idle_gpu_hours", "idle_waste_pct",
+	"cost", "used_cost", "idle_cost",
+}
+
+// WriteCSV writes one line per row followed by the total.
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	from, to := r.From.UTC().Format(time.RFC3339), r.To.UTC().Format(time.RFC3339)
+	write := func(row Row) error {
+		return cw.Write([]string{
+			string(r.GroupBy), csvSafe(row.Group), from, to, r.Currency,
+			strconv.Itoa(row.Reservations),
+			formatFloat(row.ReservedGPUHours), formatFloat(row.UsedGPUHours),
+			formatFloat(row.IdleGPUHours), formatFloat(row.IdleWastePct),
+			formatFloat(row.Cost), formatFloat(row.UsedCost), formatFloat(row.IdleCost),
+		})
+	}
+	for _, row := range r.Rows {
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	if err := write(r.Total); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Encode renders a report as csv or json and returns the bytes with their
+// content type.
+func Encode(r *Report, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case FormatCSV:
+		if err := WriteCSV(&buf, r); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "text/csv", nil
+	case FormatJSON, "":
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "application/json", nil
+	default:
+		return nil, "", fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// Filename names an exported report, e.g.
+// gpu-chargeback-team-2026-09-01-2026-10-01.csv.
+func Filename(r *Report, format string) string {
+	if format == "" {
+		format = FormatJSON
+	}
+	return fmt.Sprintf("gpu-chargeback-%s-%s-%s.%s", r.GroupBy,
+		r.From.UTC().Format("2006-01-02"), r.To.UTC().Format("2006-01-02"), format)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// csvSafe neutralizes values a spreadsheet would run as a formula; group
+// names come from user-chosen logins and namespaces.
+func csvSafe(v string) string {
+	if v != "" && (v[0] == '=' || v[0] == '+' || v[0] == '-' || v[0] == '@') {
+		return "'" + v
+	}
+	return v
+}
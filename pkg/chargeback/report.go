@@ -0,0 +1,278 @@
+// Package chargeback turns GPU reservations and the utilization snapshots
+// GPUUtilizationWorker records for them into chargeback and showback
+// reports: reserved vs. used GPU-hours, idle waste and cost per user,
+// namespace or team.
+package chargeback
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kubestellar/console/pkg/models"
+)
+
+// GroupBy selects what a report's rows aggregate.
+type GroupBy string
+
+const (
+	GroupByUser      GroupBy = "user"
+	GroupByNamespace GroupBy = "namespace"
+	GroupByTeam      GroupBy = "team"
+)
+
+// IsValid reports whether g is a known grouping.
+func (g GroupBy) IsValid() bool {
+	return g == GroupByUser || g == GroupByNamespace || g == GroupByTeam
+}
+
+// DefaultMaxSampleGap is how long a utilization snapshot is assumed to hold
+// when the next one is missing: twice the worker's default 20-minute poll
+// interval, so one missed poll is bridged but an outage is not billed as
+// used.
+const DefaultMaxSampleGap = 40 * time.Minute
+
+// Store is the data a report reads. *store.SQLiteStore satisfies it.
+type Store interface {
+	ListGPUReservations(ctx context.Context) ([]models.GPUReservation, error)
+	ListUtilizationSnapshotsInRange(ctx context.Context, start, end time.Time) ([]models.GPUUtilizationSnapshot, error)
+}
+
+// Row is one group's totals. GPU-hours and costs are rounded to two
+// decimals.
+type Row struct {
+	Group            string  `json:"group"`
+	Reservations     int     `json:"reservations"`
+	ReservedGPUHours float64 `json:"reserved_gpu_hours"`
+	UsedGPUHours     float64 `json:"used_gpu_hours"`
+	IdleGPUHours     float64 `json:"idle_gpu_hours"`
+	// IdleWastePct is the share of reserved GPU-hours left unused.
+	IdleWastePct float64 `json:"idle_waste_pct"`
+	// Cost charges reserved GPU-hours (chargeback); UsedCost prices only
+	// what was used (showback); IdleCost is the difference.
+	Cost     float64 `json:"cost"`
+	UsedCost float64 `json:"used_cost"`
+	IdleCost float64 `json:"idle_cost"`
+}
+
+// Report is a chargeback report over [From, To).
+type Report struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GroupBy     GroupBy   `json:"group_by"`
+	Currency    string    `json:"currency"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Rows        []Row     `json:"rows"`
+	Total       Row       `json:"total"`
+}
+
+// Options parameterize Build.
+type Options struct {
+	From, To time.Time
+	GroupBy  GroupBy
+	// Now bounds active reservations, which are billed up to now.
+	Now time.Time
+	// MaxSampleGap caps how long one snapshot counts for; default
+	// DefaultMaxSampleGap.
+	MaxSampleGap time.Duration
+}
+
+// Generate loads reservations and snapshots from s and builds a report.
+func Generate(ctx context.Context, s Store, cfg *Config, opts Options) (*Report, error) {
+	reservations, err := s.ListGPUReservations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list reservations: %w", err)
+	}
+	gap := opts.MaxSampleGap
+	if gap <= 0 {
+		gap = DefaultMaxSampleGap
+	}
+	// A snapshot taken shortly before From still covers its start.
+	snapshots, err := s.ListUtilizationSnapshotsInRange(ctx, opts.From.Add(-gap), opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("list utilization snapshots: %w", err)
+	}
+	return Build(cfg, reservations, snapshots, opts), nil
+}
+
+// Build computes a report. A reservation is billed for the part of its
+// window inside [From, To) during which it held GPUs: active ones up to
+// Now, and ended ones up to their last update, when they were completed or
+// cancelled. Scheduled, waitlisted and undated reservations are not billed.
+// Used GPU-hours integrate each snapshot's active GPU count until the next
+// snapshot (at most MaxSampleGap later), capped at the reserved hours.
+func Build(cfg *Config, reservations []models.GPUReservation, snapshots []models.GPUUtilizationSnapshot, opts Options) *Report {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	currency := cfg.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.MaxSampleGap <= 0 {
+		opts.MaxSampleGap = DefaultMaxSampleGap
+	}
+
+	byReservation := make(map[string][]models.GPUUtilizationSnapshot)
+	for _, s := range snapshots {
+		byReservation[s.ReservationID] = append(byReservation[s.ReservationID], s)
+	}
+
+	groups := make(map[string]*Row)
+	for i := range reservations {
+		r := &reservations[i]
+		start, end, ok := billedWindow(r, opts.Now)
+		if !ok {
+			continue
+		}
+		start, end = clampWindow(start, end, opts.From, opts.To)
+		if !start.Before(end) {
+			continue
+		}
+
+		reserved := float64(r.GPUCount) * end.Sub(start).Hours()
+		used := usedGPUHours(byReservation[r.ID.String()], start, end, opts.MaxSampleGap)
+		if used > reserved {
+			used = reserved
+		}
+		rate := cfg.HourlyRate(r.GPUType)
+
+		key := groupKey(cfg, r, opts.GroupBy)
+		row := groups[key]
+		if row == nil {
+			row = &Row{Group: key}
+			groups[key] = row
+		}
+		row.Reservations++
+		row.ReservedGPUHours += reserved
+		row.UsedGPUHours += used
+		row.Cost += reserved * rate
+		row.UsedCost += used * rate
+	}
+
+	report := &Report{
+		From:        opts.From,
+		To:          opts.To,
+		GroupBy:     opts.GroupBy,
+		Currency:    currency,
+		GeneratedAt: opts.Now,
+		Rows:        make([]Row, 0, len(groups)),
+		Total:       Row{Group: "total"},
+	}
+	for _, row := range groups {
+		report.Total.Reservations += row.Reservations
+		report.Total.ReservedGPUHours += row.ReservedGPUHours
+		report.Total.UsedGPUHours += row.UsedGPUHours
+		report.Total.Cost += row.Cost
+		report.Total.UsedCost += row.UsedCost
+		report.Rows = append(report.Rows, finishRow(*row))
+	}
+	report.Total = finishRow(report.Total)
+	// Most expensive first, then by reserved hours so cost-free reports
+	// are still ranked.
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.ReservedGPUHours != b.ReservedGPUHours {
+			return a.ReservedGPUHours > b.ReservedGPUHours
+		}
+		return a.Group < b.Group
+	})
+	return report
+}
+
+// billedWindow returns the part of a reservation's window during which it
+// held GPUs: from its activation until it was released. A completed or
+// cancelled reservation that was never activated (it expired while
+// scheduled or waitlisted) is not billed.
+func billedWindow(r *models.GPUReservation, now time.Time) (start, end time.Time, ok bool) {
+	start, end, ok = r.Window()
+	if !ok {
+		return start, end, false
+	}
+	switch r.Status {
+	case models.ReservationStatusActive:
+		if now.Before(end) {
+			end = now
+		}
+	case models.ReservationStatusCompleted, models.ReservationStatusCancelled:
+		if r.ActivatedAt == nil {
+			return start, end, false
+		}
+		if r.UpdatedAt != nil && r.UpdatedAt.Before(end) {
+			end = *r.UpdatedAt
+		}
+	default:
+		return start, end, false
+	}
+	if r.ActivatedAt != nil && r.ActivatedAt.After(start) {
+		start = *r.ActivatedAt
+	}
+	return start, end, start.Before(end)
+}
+
+func clampWindow(start, end, from, to time.Time) (time.Time, time.Time) {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return start, end
+}
+
+// usedGPUHours integrates the active GPU count of time-ordered snapshots
+// over [start, end).
+func usedGPUHours(snapshots []models.GPUUtilizationSnapshot, start, end time.Time, maxGap time.Duration) float64 {
+	var used float64
+	for i, s := range snapshots {
+		until := s.Timestamp.Add(maxGap)
+		if i+1 < len(snapshots) && snapshots[i+1].Timestamp.Before(until) {
+			until = snapshots[i+1].Timestamp
+		}
+		from, to := clampWindow(s.Timestamp, until, start, end)
+		if from.Before(to) {
+			used += float64(s.ActiveGPUCount) * to.Sub(from).Hours()
+		}
+	}
+	return used
+}
+
+func groupKey(cfg *Config, r *models.GPUReservation, by GroupBy) string {
+	switch by {
+	case GroupByNamespace:
+		return r.Cluster + "/" + r.Namespace
+	case GroupByTeam:
+		return cfg.Team(r.Cluster, r.Namespace, r.UserName)
+	default:
+		return r.UserName
+	}
+}
+
+// finishRow derives the idle columns and rounds for presentation.
+func finishRow(r Row) Row {
+	r.IdleGPUHours = r.ReservedGPUHours - r.UsedGPUHours
+	r.IdleCost = r.Cost - r.UsedCost
+	if r.ReservedGPUHours > 0 {
+		r.IdleWastePct = r.IdleGPUHours / r.ReservedGPUHours * 100
+	}
+	r.ReservedGPUHours = round2(r.ReservedGPUHours)
+	r.UsedGPUHours = round2(r.UsedGPUHours)
+	r.IdleGPUHours = round2(r.IdleGPUHours)
+	r.IdleWastePct = round2(r.IdleWastePct)
+	r.Cost = round2(r.Cost)
+	r.UsedCost = round2(r.UsedCost)
+	r.IdleCost = round2(r.IdleCost)
+	return r
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,202 @@
+package chargeback
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/notifications"
+)
+
+// schedulerInterval is how often the scheduler checks whether a monthly
+// report is due. Sends are claimed in the store, so checking often is
+// cheap and a report due while the console was down goes out soon after
+// it comes back.
+const schedulerInterval = 15 * time.Minute
+
+// defaultSMTPPort is the submission port used when the config gives none.
+const defaultSMTPPort = 587
+
+// summaryTopRows is how many rows of each report the email body lists;
+// the attachments carry all of them.
+const summaryTopRows = 10
+
+// RunStore is what the scheduler needs from the store.
+type RunStore interface {
+	Store
+	ClaimGPUChargebackRun(ctx context.Context, period string) (bool, error)
+	ReleaseGPUChargebackRun(ctx context.Context, period string) error
+}
+
+// Mailer sends a report email. *notifications.EmailNotifier satisfies it.
+type Mailer interface {
+	SendReport(subject, htmlBody string, attachments []notifications.EmailAttachment) error
+}
+
+// Scheduler emails the previous calendar month's reports once a month.
+type Scheduler struct {
+	store        RunStore
+	cfg          *Config
+	mailer       Mailer
+	maxSampleGap time.Duration
+	now          func() time.Time
+}
+
+// NewScheduler returns a scheduler for cfg.MonthlyReport, or nil when no
+// monthly report is configured. maxSampleGap is passed to every report.
+func NewScheduler(s RunStore, cfg *Config, maxSampleGap time.Duration) *Scheduler {
+	if cfg == nil || cfg.MonthlyReport == nil {
+		return nil
+	}
+	m := cfg.MonthlyReport
+	port := m.SMTPPort
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	var recipients []string
+	for _, r := range strings.Split(m.Recipients, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return &Scheduler{
+		store:        s,
+		cfg:          cfg,
+		mailer:       notifications.NewEmailNotifier(m.SMTPHost, port, m.Username, m.Password, m.From, recipients),
+		maxSampleGap: maxSampleGap,
+		now:          time.Now,
+	}
+}
+
+// Run checks for a due report immediately and then every
+// schedulerInterval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.tick(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("[Chargeback] monthly report failed, will retry", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick sends last month's reports if they are due and not yet sent.
+func (s *Scheduler) tick(ctx context.Context) error {
+	m := s.cfg.MonthlyReport
+	now := s.now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	due := time.Date(now.Year(), now.Month(), m.Day, m.Hour, 0, 0, 0, time.UTC)
+	if now.Before(due) {
+		return nil
+	}
+	from := monthStart.AddDate(0, -1, 0)
+	period := from.Format("2006-01")
+
+	claimed, err := s.store.ClaimGPUChargebackRun(ctx, period)
+	if err != nil {
+		return fmt.Errorf("claim %s: %w", period, err)
+	}
+	if !claimed {
+		return nil
+	}
+	if err := s.send(ctx, period, from, monthStart, now); err != nil {
+		if rerr := s.store.ReleaseGPUChargebackRun(ctx, period); rerr != nil {
+			slog.Error("[Chargeback] failed to release report claim", "period", period, "error", rerr)
+		}
+		return fmt.Errorf("send %s: %w", period, err)
+	}
+	slog.Info("[Chargeback] monthly report sent", "period", period)
+	return nil
+}
+
+func (s *Scheduler) send(ctx context.Context, period string, from, to, now time.Time) error {
+	m := s.cfg.MonthlyReport
+	reports := make([]*Report, 0, len(m.GroupBy))
+	attachments := make([]notifications.EmailAttachment, 0, len(m.GroupBy))
+	for _, by := range m.GroupBy {
+		r, err := Generate(ctx, s.store, s.cfg, Options{From: from, To: to, GroupBy: by, Now: now, MaxSampleGap: s.maxSampleGap})
+		if err != nil {
+			return err
+		}
+		data, contentType, err := Encode(r, m.Format)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, r)
+		attachments = append(attachments, notifications.EmailAttachment{
+			Filename:    Filename(r, m.Format),
+			ContentType: contentType,
+			Data:        data,
+		})
+	}
+	body, err := renderSummary(period, reports)
+	if err != nil {
+		return err
+	}
+	return s.mailer.SendReport("GPU chargeback report "+period, body, attachments)
+}
+
+var summaryTemplate = template.Must(template.New("summary").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #333;">
+	<h2>GPU chargeback report {{.Period}}</h2>
+	{{range .Reports}}
+	<h3>By {{.GroupBy}}</h3>
+	<table cellpadding="6" style="border-collapse: collapse; border: 1px solid #ddd;">
+		<tr style="background: #f5f5f5;">
+			<th align="left">{{.GroupBy}}</th><th align="right">Reserved GPU-h</th><th align="right">Used GPU-h</th>
+			<th align="right">Idle %</th><th align="right">Cost ({{.Currency}})</th>
+		</tr>
+		{{range .Top}}
+		<tr>
+			<td>{{.Group}}</td><td align="right">{{printf "%.2f" .ReservedGPUHours}}</td><td align="right">{{printf "%.2f" .UsedGPUHours}}</td>
+			<td align="right">{{printf "%.1f" .IdleWastePct}}</td><td align="right">{{printf "%.2f" .Cost}}</td>
+		</tr>
+		{{end}}
+		<tr style="font-weight: bold;">
+			<td>Total</td><td align="right">{{printf "%.2f" .Total.ReservedGPUHours}}</td><td align="right">{{printf "%.2f" .Total.UsedGPUHours}}</td>
+			<td align="right">{{printf "%.1f" .Total.IdleWastePct}}</td><td align="right">{{printf "%.2f" .Total.Cost}}</td>
+		</tr>
+	</table>
+	{{if .More}}<p>{{.More}} more rows in the attached report.</p>{{end}}
+	{{end}}
+	<p style="font-size: 12px; color: #777;">Generated by KubeStellar Console from GPU utilization snapshots.</p>
+</body>
+</html>
+`))
+
+// renderSummary renders the email body: the top rows and total of each
+// report.
+func renderSummary(period string, reports []*Report) (string, error) {
+	type section struct {
+		*Report
+		Top  []Row
+		More int
+	}
+	data := struct {
+		Period  string
+		Reports []section
+	}{Period: period}
+	for _, r := range reports {
+		sec := section{Report: r, Top: r.Rows}
+		if len(sec.Top) > summaryTopRows {
+			sec.More = len(sec.Top) - summaryTopRows
+			sec.Top = sec.Top[:summaryTopRows]
+		}
+		data.Reports = append(data.Reports, sec)
+	}
+	var buf bytes.Buffer
+	if err := summaryTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -5,11 +5,17 @@ import (
 	"time"
 )
 
+// ConsoleCheck evaluates a requirement on the console itself, such as
+// where it loads content from. Checks run on every read so they reflect
+// the current configuration.
+type ConsoleCheck func() Requirement
+
 // Engine evaluates air-gap readiness across clusters.
 type Engine struct {
 	mu           sync.RWMutex
 	requirements []Requirement
 	clusters     []ClusterReadiness
+	checks       []ConsoleCheck
 }
 
 // NewEngine returns a pre-populated air-gap readiness engine with demo data.
@@ -20,12 +26,28 @@ func NewEngine() *Engine {
 	return e
 }
 
+// AddConsoleCheck adds a console requirement to the assessment.
+func (e *Engine) AddConsoleCheck(check ConsoleCheck) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.checks = append(e.checks, check)
+}
+
 // Requirements returns all air-gap readiness requirements.
 func (e *Engine) Requirements() []Requirement {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	out := make([]Requirement, len(e.requirements))
+	return e.requirementsLocked()
+}
+
+// requirementsLocked returns the requirements followed by the console
+// checks. REQUIRES: e.mu held.
+func (e *Engine) requirementsLocked() []Requirement {
+	out := make([]Requirement, len(e.requirements), len(e.requirements)+len(e.checks))
 	copy(out, e.requirements)
+	for _, check := range e.checks {
+		out = append(out, check())
+	}
 	return out
 }
 
@@ -49,13 +71,14 @@ func (e *Engine) Summary() Summary {
 			ready++
 		}
 	}
+	requirements := e.requirementsLocked()
 	met := 0
-	for _, r := range e.requirements {
+	for _, r := range requirements {
 		if r.Status == "ready" {
 			met++
 		}
 	}
-	total := len(e.requirements)
+	total := len(requirements)
 	score := 0
 	if total > 0 {
 		score = (met * 100) / total
@@ -70,3 +70,23 @@ func TestRequirementsImmutability(t *testing.T) {
 		t.Error("Requirements() returned a mutable reference")
 	}
 }
+
+func TestConsoleCheck(t *testing.T) {
+	e := NewEngine()
+	before := e.Summary()
+	status := "not_ready"
+	e.AddConsoleCheck(func() Requirement {
+		return Requirement{ID: "ag-console", Category: "updates", Name: "Console check", Status: status}
+	})
+	reqs := e.Requirements()
+	if last := reqs[len(reqs)-1]; last.ID != "ag-console" || last.Status != "not_ready" {
+		t.Fatalf("console check missing: %+v", last)
+	}
+	if s := e.Summary(); s.TotalRequirements != before.TotalRequirements+1 || s.MetRequirements != before.MetRequirements {
+		t.Errorf("summary = %+v", s)
+	}
+	status = "ready"
+	if s := e.Summary(); s.MetRequirements != before.MetRequirements+1 {
+		t.Errorf("check is not re-evaluated: %+v", s)
+	}
+}
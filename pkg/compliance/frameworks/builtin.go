@@ -58,6 +58,7 @@ func PCIDSS4() Framework {
 				Checks: []Check{
 					{ID: "pci-6.1", Name: "Image vulnerability scanning", Description: "Container images must be scanned for known CVEs.", CheckType: "image_scanning"},
 					{ID: "pci-6.2", Name: "No critical CVEs", Description: "No running images should have critical-severity CVEs.", CheckType: "image_scanning", Params: map[string]string{"max_severity": "critical"}},
+					{ID: "pci-6.3", Name: "Verified build provenance", Description: "Running images must carry verified SLSA provenance from a trusted builder and source.", CheckType: "image_provenance"},
 				},
 			},
 			{
@@ -157,6 +158,7 @@ func SOC2Type2() Framework {
 				Checks: []Check{
 					{ID: "cc8.1.1", Name: "Image provenance", Description: "Container images should come from trusted registries.", CheckType: "image_scanning"},
 					{ID: "cc8.1.2", Name: "Pod security enforcement", Description: "Pod security standards enforced to prevent untested configs.", CheckType: "pod_security"},
+					{ID: "cc8.1.3", Name: "Verified build provenance", Description: "Container images should be built by trusted builders from approved source repositories.", CheckType: "image_provenance"},
 				},
 			},
 		},
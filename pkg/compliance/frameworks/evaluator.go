@@ -27,6 +27,10 @@ type ClusterProber interface {
 	EncryptionAtRestEnabled(ctx context.Context, cluster string) (bool, error)
 	// ImageVulnerabilities returns total images and those with critical/high CVEs.
 	ImageVulnerabilities(ctx context.Context, cluster string) (total, critical, high int, err error)
+	// ImageProvenance returns total workloads, those with an SLSA provenance
+	// attestation, and those whose attestation verified and satisfies the
+	// namespace's provenance policy.
+	ImageProvenance(ctx context.Context, cluster string) (total, attested, verified int, err error)
 	// ClusterAdminBindings returns the count of non-system ClusterRoleBindings
 	// that grant cluster-admin.
 	ClusterAdminBindings(ctx context.Context, cluster string) (int, error)
@@ -145,6 +149,8 @@ func (e *Evaluator) runCheck(ctx context.Context, check Check, cluster string) C
 		cr = e.checkEncryption(ctx, check, cluster)
 	case "image_scanning":
 		cr = e.checkImageScanning(ctx, check, cluster)
+	case "image_provenance":
+		cr = e.checkImageProvenance(ctx, check, cluster)
 	case "rbac_least_privilege":
 		cr = e.checkRBAC(ctx, check, cluster)
 	case "auth_provider":
@@ -295,6 +301,32 @@ func (e *Evaluator) checkImageScanning(ctx context.Context, check Check, cluster
 	return cr
 }
 
+func (e *Evaluator) checkImageProvenance(ctx context.Context, check Check, cluster string) CheckResult {
+	cr := CheckResult{CheckID: check.ID, Name: check.Name}
+	total, attested, verified, err := e.prober.ImageProvenance(ctx, cluster)
+	if err != nil {
+		cr.Status = StatusError
+		cr.Message = err.Error()
+		return cr
+	}
+	if total == 0 {
+		cr.Status = StatusSkipped
+		cr.Message = "No workload provenance results available"
+		return cr
+	}
+	// Attested but unverified or off-policy provenance is partial credit.
+	switch {
+	case verified == total:
+		cr.Status = StatusPass
+	case attested == total:
+		cr.Status = StatusPartial
+	default:
+		cr.Status = StatusFail
+	}
+	cr.Evidence = fmt.Sprintf("%d workloads, %d with provenance, %d verified against policy", total, attested, verified)
+	return cr
+}
+
 func (e *Evaluator) checkRBAC(ctx context.Context, check Check, cluster string) CheckResult {
 	cr := CheckResult{CheckID: check.ID, Name: check.Name}
 
@@ -413,7 +445,7 @@ func remediationHint(ctrl Control) string {
 		"Audit & Monitoring":     "Enable Kubernetes API audit logging with an appropriate policy.",
 		"Security Testing":       "Deploy Falco or Tetragon for runtime security monitoring.",
 		"Monitoring":             "Deploy a runtime security monitor and ensure audit logging is active.",
-		"Change Management":      "Scan images for vulnerabilities before deployment. Enforce pod security standards.",
+		"Change Management":      "Require SLSA provenance from trusted builders before deployment. Enforce pod security standards.",
 	}
 	if h, ok := hints[ctrl.Category]; ok {
 		return h
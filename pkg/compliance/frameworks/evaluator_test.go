@@ -32,6 +32,11 @@ type mockProber struct {
 	imgHigh     int
 	imgErr      error
 
+	provTotal    int
+	provAttested int
+	provVerified int
+	provErr      error
+
 	clusterAdminCount int
 	clusterAdminErr   error
 
@@ -66,6 +71,9 @@ func (m *mockProber) EncryptionAtRestEnabled(_ context.Context, _ string) (bool,
 func (m *mockProber) ImageVulnerabilities(_ context.Context, _ string) (int, int, int, error) {
 	return m.imgTotal, m.imgCritical, m.imgHigh, m.imgErr
 }
+func (m *mockProber) ImageProvenance(_ context.Context, _ string) (int, int, int, error) {
+	return m.provTotal, m.provAttested, m.provVerified, m.provErr
+}
 func (m *mockProber) ClusterAdminBindings(_ context.Context, _ string) (int, error) {
 	return m.clusterAdminCount, m.clusterAdminErr
 }
@@ -152,8 +160,8 @@ func TestPCIDSS4Structure(t *testing.T) {
 			}
 		}
 	}
-	if totalChecks != 12 {
-		t.Errorf("expected 12 total checks, got %d", totalChecks)
+	if totalChecks != 13 {
+		t.Errorf("expected 13 total checks, got %d", totalChecks)
 	}
 }
 
@@ -169,8 +177,8 @@ func TestSOC2Structure(t *testing.T) {
 	for _, c := range fw.Controls {
 		totalChecks += len(c.Checks)
 	}
-	if totalChecks != 8 {
-		t.Errorf("expected 8 total checks, got %d", totalChecks)
+	if totalChecks != 9 {
+		t.Errorf("expected 9 total checks, got %d", totalChecks)
 	}
 }
 
@@ -187,6 +195,9 @@ func TestEvaluateAllPassing(t *testing.T) {
 		imgTotal:             10,
 		imgCritical:          0,
 		imgHigh:              0,
+		provTotal:            10,
+		provAttested:         10,
+		provVerified:         10,
 		clusterAdminCount:    0,
 		wildcardCount:        0,
 		authConfigured:       true,
@@ -236,6 +247,9 @@ func TestEvaluateAllFailing(t *testing.T) {
 		imgTotal:             10,
 		imgCritical:          3,
 		imgHigh:              5,
+		provTotal:            10,
+		provAttested:         4,
+		provVerified:         2,
 		clusterAdminCount:    2,
 		wildcardCount:        4,
 		authConfigured:       false,
@@ -313,6 +327,7 @@ func TestEvaluateWithErrors(t *testing.T) {
 		saErr:             errors.New("forbidden"),
 		encryptionErr:     errors.New("not supported"),
 		imgErr:            errors.New("scanner unavailable"),
+		provErr:           errors.New("registry unreachable"),
 		clusterAdminErr:   errors.New("forbidden"),
 		wildcardErr:       errors.New("forbidden"),
 		authErr:           errors.New("not supported"),
@@ -376,6 +391,9 @@ func TestEvaluateSOC2(t *testing.T) {
 		imgTotal:             5,
 		imgCritical:          0,
 		imgHigh:              0,
+		provTotal:            5,
+		provAttested:         5,
+		provVerified:         5,
 	}
 	ev := NewEvaluator(prober)
 	result, err := ev.Evaluate(context.Background(), SOC2Type2(), "soc2-cluster")
@@ -475,8 +493,8 @@ func TestDemoEvaluation(t *testing.T) {
 	if result.ClusterName != "demo-cluster" {
 		t.Errorf("cluster = %q", result.ClusterName)
 	}
-	if result.TotalChecks != 12 {
-		t.Errorf("expected 12 total checks, got %d", result.TotalChecks)
+	if result.TotalChecks != 13 {
+		t.Errorf("expected 13 total checks, got %d", result.TotalChecks)
 	}
 	if result.Score < 0 || result.Score > 100 {
 		t.Errorf("score out of range: %d", result.Score)
@@ -563,6 +581,32 @@ func TestCheckImageScanningGeneral(t *testing.T) {
 	}
 }
 
+func TestCheckImageProvenance(t *testing.T) {
+	prober := &mockProber{provTotal: 10, provAttested: 10, provVerified: 10}
+	ev := NewEvaluator(prober)
+	check := Check{ID: "test", Name: "test", CheckType: "image_provenance"}
+	if result := ev.runCheck(context.Background(), check, "c"); result.Status != StatusPass {
+		t.Errorf("expected pass when all verified, got %s", result.Status)
+	}
+
+	// All attested, some off-policy => partial
+	prober.provVerified = 7
+	if result := ev.runCheck(context.Background(), check, "c"); result.Status != StatusPartial {
+		t.Errorf("expected partial, got %s", result.Status)
+	}
+
+	// Some workloads without provenance => fail
+	prober.provAttested = 8
+	if result := ev.runCheck(context.Background(), check, "c"); result.Status != StatusFail {
+		t.Errorf("expected fail, got %s", result.Status)
+	}
+
+	prober.provTotal = 0
+	if result := ev.runCheck(context.Background(), check, "c"); result.Status != StatusSkipped {
+		t.Errorf("expected skipped with no workloads, got %s", result.Status)
+	}
+}
+
 func TestCheckRBACWildcard(t *testing.T) {
 	prober := &mockProber{wildcardCount: 3}
 	ev := NewEvaluator(prober)
@@ -55,7 +55,7 @@ type Check struct {
 	Description string `json:"description"`
 	// CheckType selects the evaluator: "network_policy", "pod_security",
 	// "rbac_least_privilege", "encryption_at_rest", "audit_logging",
-	// "image_scanning", "auth_provider", "runtime_security".
+	// "image_scanning", "image_provenance", "auth_provider", "runtime_security".
 	CheckType string `json:"check_type"`
 	// Params are type-specific evaluation parameters.
 	Params map[string]string `json:"params,omitempty"`
@@ -6,11 +6,14 @@ import (
 	"time"
 )
 
-// Engine evaluates GxP / 21 CFR Part 11 compliance.
+// Engine evaluates GxP / 21 CFR Part 11 compliance. An engine built with
+// NewEngine serves demo data; one built with NewLiveEngine reports on the
+// console's real audit chain (see live.go).
 type Engine struct {
 	config     Config
 	records    []AuditRecord
 	signatures []Signature
+	source     ChainSource
 }
 
 // NewEngine creates a GxP engine with demo data.
@@ -0,0 +1,91 @@
+package gxp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Engine modes reported in Summary.Mode and ChainStatus.Mode.
+const (
+	ModeDemo = "demo"
+	ModeLive = "live"
+)
+
+// liveRecordLimit is how many of the newest chain records LiveRecords
+// returns.
+const liveRecordLimit = 200
+
+// ChainSource supplies the console's real hash-chained audit log.
+type ChainSource interface {
+	// Records returns up to limit of the newest records, oldest first.
+	Records(ctx context.Context, limit int) ([]AuditRecord, error)
+	// Verify walks the whole chain and its signed checkpoints.
+	Verify(ctx context.Context) (ChainStatus, error)
+}
+
+// NewLiveEngine creates a GxP engine that reports on the audit chain read
+// from source instead of demo records.
+func NewLiveEngine(source ChainSource) *Engine {
+	return &Engine{source: source, config: liveConfig()}
+}
+
+// IsLive reports whether the engine reads the real audit chain.
+func (e *Engine) IsLive() bool {
+	return e.source != nil
+}
+
+// LiveRecords returns the newest records of the real audit chain.
+func (e *Engine) LiveRecords(ctx context.Context) ([]AuditRecord, error) {
+	if !e.IsLive() {
+		return e.AuditRecords(), nil
+	}
+	return e.source.Records(ctx, liveRecordLimit)
+}
+
+// LiveVerifyChain verifies the real audit chain.
+func (e *Engine) LiveVerifyChain(ctx context.Context) (ChainStatus, error) {
+	if !e.IsLive() {
+		status := e.VerifyChain()
+		status.Mode = ModeDemo
+		return status, nil
+	}
+	status, err := e.source.Verify(ctx)
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("verify audit chain: %w", err)
+	}
+	status.Mode = ModeLive
+	return status, nil
+}
+
+// LiveSummary summarizes the real audit chain. The console does not collect
+// electronic signatures yet, so signature counts are zero.
+func (e *Engine) LiveSummary(ctx context.Context) (Summary, error) {
+	if !e.IsLive() {
+		s := e.Summary()
+		s.Mode = ModeDemo
+		return s, nil
+	}
+	chain, err := e.LiveVerifyChain(ctx)
+	if err != nil {
+		return Summary{}, err
+	}
+	return Summary{
+		Mode:           ModeLive,
+		Config:         e.config,
+		TotalRecords:   chain.TotalRecords,
+		ChainIntegrity: chain.Valid,
+		LastVerified:   chain.VerifiedAt,
+		EvaluatedAt:    Now().Format(time.RFC3339),
+	}, nil
+}
+
+// liveConfig describes the console's audit chain: append-only SHA-256
+// links with Ed25519-signed checkpoints, without e-signature enforcement.
+func liveConfig() Config {
+	return Config{
+		Enabled:       true,
+		AppendOnly:    true,
+		HashAlgorithm: "SHA-256",
+	}
+}
@@ -35,6 +35,12 @@ type ChainStatus struct {
 	BrokenAtIndex  int    `json:"broken_at_index"` // -1 if chain is intact
 	VerifiedAt     string `json:"verified_at"`
 	Message        string `json:"message"`
+	// Live-mode fields: the audit_log ID of the broken record, rows that
+	// predate the chain, and signed checkpoints checked.
+	Mode           string `json:"mode,omitempty"`
+	BrokenRecordID string `json:"broken_record_id,omitempty"`
+	LegacyRecords  int    `json:"legacy_records,omitempty"`
+	Checkpoints    int    `json:"checkpoints,omitempty"`
 }
 
 // Config holds GxP mode configuration.
@@ -49,6 +55,7 @@ type Config struct {
 
 // Summary is the overall GxP compliance summary.
 type Summary struct {
+	Mode           string `json:"mode,omitempty"`
 	Config         Config `json:"config"`
 	TotalRecords   int    `json:"total_records"`
 	TotalSignatures int   `json:"total_signatures"`
@@ -1,12 +1,23 @@
 package hipaa
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
-// Engine evaluates HIPAA Security Rule compliance.
+// Engine evaluates HIPAA Security Rule compliance. An engine built with
+// NewEngine serves fixed demo data; one built with NewLiveEngine probes
+// clusters through a Prober (see live.go).
 type Engine struct {
 	safeguards   []Safeguard
 	phiNamespaces []PHINamespace
 	dataFlows    []DataFlow
+
+	prober Prober
+	opts   LiveOptions
+	mu     sync.Mutex
+	cache  map[string]cachedReport
+	now    func() time.Time
 }
 
 // NewEngine creates a HIPAA compliance engine with demo data.
@@ -35,8 +46,17 @@ func (e *Engine) DataFlows() []DataFlow {
 
 // Summary returns the overall HIPAA compliance summary.
 func (e *Engine) Summary() Summary {
-	passed, failed, partial := 0, 0, 0
-	for _, s := range e.safeguards {
+	s := summarize(e.safeguards, e.phiNamespaces, e.dataFlows)
+	s.Mode = ModeDemo
+	return s
+}
+
+// summarize scores a set of safeguards. Skipped safeguards (controls that
+// cannot be observed from cluster state) are excluded from the score rather
+// than counted as failures.
+func summarize(safeguards []Safeguard, namespaces []PHINamespace, flows []DataFlow) Summary {
+	passed, failed, partial, skipped := 0, 0, 0, 0
+	for _, s := range safeguards {
 		switch s.Status {
 		case "pass":
 			passed++
@@ -44,23 +64,25 @@ func (e *Engine) Summary() Summary {
 			failed++
 		case "partial":
 			partial++
+		case "skipped":
+			skipped++
 		}
 	}
-	total := len(e.safeguards)
+	total := len(safeguards)
 	score := 0
-	if total > 0 {
-		score = ((passed * 100) + (partial * 50)) / total
+	if scored := total - skipped; scored > 0 {
+		score = ((passed * 100) + (partial * 50)) / scored
 	}
 
 	compliantNS := 0
-	for _, ns := range e.phiNamespaces {
+	for _, ns := range namespaces {
 		if ns.Compliant {
 			compliantNS++
 		}
 	}
 
 	encryptedFlows := 0
-	for _, f := range e.dataFlows {
+	for _, f := range flows {
 		if f.Encrypted {
 			encryptedFlows++
 		}
@@ -71,10 +93,11 @@ func (e *Engine) Summary() Summary {
 		SafeguardsPassed:  passed,
 		SafeguardsFailed:  failed,
 		SafeguardsPartial: partial,
+		SafeguardsSkipped: skipped,
 		TotalSafeguards:   total,
-		PHINamespaces:     len(e.phiNamespaces),
+		PHINamespaces:     len(namespaces),
 		CompliantNS:       compliantNS,
-		DataFlows:         len(e.dataFlows),
+		DataFlows:         len(flows),
 		EncryptedFlows:    encryptedFlows,
 		EvaluatedAt:       time.Now().UTC().Format(time.RFC3339),
 	}
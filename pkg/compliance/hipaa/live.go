@@ -0,0 +1,767 @@
+package hipaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/safego"
+)
+
+// Engine modes reported in Summary.Mode and Report.Mode.
+const (
+	ModeDemo = "demo"
+	ModeLive = "live"
+)
+
+const (
+	// DefaultPHILabel marks a namespace as handling PHI when set to "true".
+	DefaultPHILabel = "hipaa-phi"
+	// DefaultPHIAnnotation is the annotation alternative to DefaultPHILabel,
+	// for namespaces whose labels are owned by another controller.
+	DefaultPHIAnnotation = "compliance.kubestellar.io/phi"
+	// defaultLiveCacheTTL bounds how stale a cached per-cluster evaluation
+	// may be. Each evaluation issues several list calls per PHI namespace.
+	defaultLiveCacheTTL = 5 * time.Minute
+)
+
+// broadSubjects are RoleBinding subjects that grant access to everyone who
+// can reach the API server, defeating per-user access control.
+var broadSubjects = map[string]bool{
+	"Group:system:authenticated":   true,
+	"Group:system:unauthenticated": true,
+	"Group:system:serviceaccounts": true,
+	"User:system:anonymous":        true,
+}
+
+// LiveOptions configures a live engine.
+type LiveOptions struct {
+	// PHILabel and PHIAnnotation select PHI namespaces (value "true").
+	PHILabel      string
+	PHIAnnotation string
+	// CacheTTL is how long a per-cluster evaluation is reused.
+	CacheTTL time.Duration
+}
+
+// Report is a full HIPAA evaluation across one or more clusters.
+type Report struct {
+	Mode          string            `json:"mode"`
+	Clusters      []string          `json:"clusters"`
+	Safeguards    []Safeguard       `json:"safeguards"`
+	PHINamespaces []PHINamespace    `json:"phi_namespaces"`
+	DataFlows     []DataFlow        `json:"data_flows"`
+	Summary       Summary           `json:"summary"`
+	Errors        map[string]string `json:"errors,omitempty"`
+}
+
+type cachedReport struct {
+	report *Report
+	at     time.Time
+}
+
+// NewLiveEngine creates a HIPAA engine that evaluates safeguards against
+// live clusters through prober. Results are cached per cluster.
+func NewLiveEngine(prober Prober, opts LiveOptions) *Engine {
+	if opts.PHILabel == "" {
+		opts.PHILabel = DefaultPHILabel
+	}
+	if opts.PHIAnnotation == "" {
+		opts.PHIAnnotation = DefaultPHIAnnotation
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = defaultLiveCacheTTL
+	}
+	return &Engine{
+		prober: prober,
+		opts:   opts,
+		cache:  make(map[string]cachedReport),
+		now:    time.Now,
+	}
+}
+
+// IsLive reports whether the engine probes real clusters.
+func (e *Engine) IsLive() bool {
+	return e.prober != nil
+}
+
+// Invalidate drops the cached evaluation for cluster, or for every cluster
+// when cluster is empty.
+func (e *Engine) Invalidate(cluster string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if cluster == "" {
+		e.cache = make(map[string]cachedReport)
+		return
+	}
+	delete(e.cache, cluster)
+}
+
+// Evaluate returns the HIPAA report for the given clusters. A demo engine
+// ignores clusters and returns its fixed data set. A live engine evaluates
+// each cluster (or reuses a fresh cached result) and merges them; clusters
+// that cannot be probed are listed in Report.Errors. It only fails when no
+// cluster could be evaluated.
+func (e *Engine) Evaluate(ctx context.Context, clusters []string) (*Report, error) {
+	if !e.IsLive() {
+		return &Report{
+			Mode:          ModeDemo,
+			Safeguards:    e.safeguards,
+			PHINamespaces: e.phiNamespaces,
+			DataFlows:     e.dataFlows,
+			Summary:       e.Summary(),
+		}, nil
+	}
+	if len(clusters) == 0 {
+		return nil, errors.New("no clusters to evaluate")
+	}
+
+	reports := make([]*Report, len(clusters))
+	errs := make([]error, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		safego.GoWith("hipaa/evaluate/"+cluster, func() {
+			defer wg.Done()
+			reports[i], errs[i] = e.clusterReport(ctx, cluster)
+		})
+	}
+	wg.Wait()
+
+	ok := make([]*Report, 0, len(clusters))
+	failures := make(map[string]string)
+	for i, cluster := range clusters {
+		if errs[i] != nil {
+			failures[cluster] = errs[i].Error()
+			continue
+		}
+		ok = append(ok, reports[i])
+	}
+	if len(ok) == 0 {
+		return nil, fmt.Errorf("HIPAA evaluation failed on all %d cluster(s): %v", len(clusters), errs[0])
+	}
+	merged := mergeReports(ok)
+	if len(failures) > 0 {
+		merged.Errors = failures
+	}
+	return merged, nil
+}
+
+// clusterReport returns a cached evaluation when it is still fresh.
+func (e *Engine) clusterReport(ctx context.Context, cluster string) (*Report, error) {
+	e.mu.Lock()
+	cached, ok := e.cache[cluster]
+	e.mu.Unlock()
+	if ok && e.now().Sub(cached.at) < e.opts.CacheTTL {
+		return cached.report, nil
+	}
+	report, err := e.evaluateCluster(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.cache[cluster] = cachedReport{report: report, at: e.now()}
+	e.mu.Unlock()
+	return report, nil
+}
+
+// namespaceFacts is everything probed for one PHI namespace.
+type namespaceFacts struct {
+	info      NamespaceInfo
+	policies  []NetworkPolicyInfo
+	services  []ServiceInfo
+	ingresses []IngressInfo
+	bindings  []RoleBindingInfo
+	err       error
+}
+
+func (e *Engine) evaluateCluster(ctx context.Context, cluster string) (*Report, error) {
+	all, err := e.prober.Namespaces(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+	phiNames := make(map[string]bool)
+	var facts []*namespaceFacts
+	for _, ns := range all {
+		if !e.isPHINamespace(ns) {
+			continue
+		}
+		phiNames[ns.Name] = true
+		f := &namespaceFacts{info: ns}
+		f.err = errors.Join(
+			probeInto(&f.policies, func() ([]NetworkPolicyInfo, error) { return e.prober.NetworkPolicies(ctx, cluster, ns.Name) }),
+			probeInto(&f.services, func() ([]ServiceInfo, error) { return e.prober.Services(ctx, cluster, ns.Name) }),
+			probeInto(&f.ingresses, func() ([]IngressInfo, error) { return e.prober.Ingresses(ctx, cluster, ns.Name) }),
+			probeInto(&f.bindings, func() ([]RoleBindingInfo, error) { return e.prober.RoleBindings(ctx, cluster, ns.Name) }),
+		)
+		facts = append(facts, f)
+	}
+	sort.Slice(facts, func(i, j int) bool { return facts[i].info.Name < facts[j].info.Name })
+
+	encrypted, encErr := e.prober.EncryptionAtRestEnabled(ctx, cluster)
+	audited, auditErr := e.prober.AuditLoggingEnabled(ctx, cluster)
+
+	report := &Report{
+		Mode:       ModeLive,
+		Clusters:   []string{cluster},
+		Safeguards: liveSafeguards(facts, encrypted, encErr, audited, auditErr),
+	}
+	for _, f := range facts {
+		ns := PHINamespace{
+			Name:            f.info.Name,
+			Cluster:         cluster,
+			Labels:          e.namespaceLabels(f.info),
+			Encrypted:       encErr == nil && encrypted,
+			AuditEnabled:    auditErr == nil && audited,
+			RBACRestricted:  f.err == nil && hasBindings(f) && !hasBroadSubject(f),
+			NetworkIsolated: f.err == nil && hasDefaultDeny(f) && len(openIngressPolicies(f)) == 0,
+		}
+		ns.Compliant = ns.Encrypted && ns.AuditEnabled && ns.RBACRestricted && ns.NetworkIsolated
+		report.PHINamespaces = append(report.PHINamespaces, ns)
+		report.DataFlows = append(report.DataFlows, deriveDataFlows(cluster, f, phiNames)...)
+	}
+	report.Summary = summarize(report.Safeguards, report.PHINamespaces, report.DataFlows)
+	report.Summary.Mode = ModeLive
+	return report, nil
+}
+
+func probeInto[T any](dst *[]T, fn func() ([]T, error)) error {
+	v, err := fn()
+	if err != nil {
+		return err
+	}
+	*dst = v
+	return nil
+}
+
+func (e *Engine) isPHINamespace(ns NamespaceInfo) bool {
+	return strings.EqualFold(ns.Labels[e.opts.PHILabel], "true") ||
+		strings.EqualFold(ns.Annotations[e.opts.PHIAnnotation], "true")
+}
+
+// namespaceLabels renders the namespace's labels as sorted key=value pairs,
+// plus the PHI annotation when that is how it was discovered.
+func (e *Engine) namespaceLabels(ns NamespaceInfo) []string {
+	out := make([]string, 0, len(ns.Labels)+1)
+	for k, v := range ns.Labels {
+		if k == "kubernetes.io/metadata.name" {
+			continue
+		}
+		out = append(out, k+"="+v)
+	}
+	if v, ok := ns.Annotations[e.opts.PHIAnnotation]; ok {
+		out = append(out, e.opts.PHIAnnotation+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func liveSafeguards(facts []*namespaceFacts, encrypted bool, encErr error, audited bool, auditErr error) []Safeguard {
+	var withBindings, withoutBroad, defaultDeny, restricted, meshed []string
+	var missingBindings, broad, noDeny, open, unmeshed, probeFailed []string
+	ingressTotal, ingressTLS := 0, 0
+	var plainIngresses, plainServices []string
+	for _, f := range facts {
+		name := f.info.Name
+		if f.err != nil {
+			probeFailed = append(probeFailed, name)
+			missingBindings = append(missingBindings, name)
+			broad = append(broad, name)
+			noDeny = append(noDeny, name)
+			open = append(open, name)
+		} else {
+			if hasBindings(f) {
+				withBindings = append(withBindings, name)
+			} else {
+				missingBindings = append(missingBindings, name)
+			}
+			if hasBroadSubject(f) {
+				broad = append(broad, name)
+			} else {
+				withoutBroad = append(withoutBroad, name)
+			}
+			if hasDefaultDeny(f) {
+				defaultDeny = append(defaultDeny, name)
+			} else {
+				noDeny = append(noDeny, name)
+			}
+			if offenders := openIngressPolicies(f); len(offenders) > 0 {
+				open = append(open, fmt.Sprintf("%s (%s)", name, strings.Join(offenders, ", ")))
+			} else {
+				restricted = append(restricted, name)
+			}
+		}
+		if meshMTLS(f.info) {
+			meshed = append(meshed, name)
+		} else {
+			unmeshed = append(unmeshed, name)
+		}
+		for _, ing := range f.ingresses {
+			ingressTotal++
+			if ing.TLS {
+				ingressTLS++
+			} else {
+				plainIngresses = append(plainIngresses, name+"/"+ing.Name)
+			}
+		}
+		if !meshMTLS(f.info) {
+			for _, svc := range f.services {
+				if !serviceTLSOnly(svc) {
+					plainServices = append(plainServices, name+"/"+svc.Name)
+				}
+			}
+		}
+	}
+	total := len(facts)
+	probeNote := ""
+	if len(probeFailed) > 0 {
+		probeNote = fmt.Sprintf(" (probe failed for: %s)", strings.Join(probeFailed, ", "))
+	}
+
+	safeguards := []Safeguard{
+		{
+			ID: "164.312(a)", Section: "§164.312(a)(1)", Name: "Access Control",
+			Description: "Implement technical policies to allow access only to authorized persons.",
+			Checks: []Check{
+				ratioCheck("ac-1", "RBAC enforced on PHI namespaces", "Every PHI namespace grants access through namespace-scoped RoleBindings",
+					len(withBindings), total, "%d of %d PHI namespaces have RoleBindings"+probeNote,
+					"Create namespace-scoped Roles and RoleBindings for: "+strings.Join(missingBindings, ", ")),
+				ratioCheck("ac-2", "No anonymous or all-users access", "No RoleBinding in a PHI namespace grants access to system:authenticated, system:unauthenticated or all service accounts",
+					len(withoutBroad), total, "%d of %d PHI namespaces have no cluster-wide group subjects"+probeNote,
+					"Remove broad group subjects from RoleBindings in: "+strings.Join(broad, ", ")),
+				boolCheck("ac-3", "Encryption of PHI at rest", "API server encrypts Secrets in etcd (§164.312(a)(2)(iv))",
+					encrypted, encErr, "EncryptionConfiguration is configured on the API server",
+					"API server has no --encryption-provider-config",
+					"Configure --encryption-provider-config with an aescbc, aesgcm or KMS provider"),
+			},
+		},
+		{
+			ID: "164.312(b)", Section: "§164.312(b)", Name: "Audit Controls",
+			Description: "Implement mechanisms to record and examine activity in systems containing PHI.",
+			Checks: []Check{
+				boolCheck("au-1", "Kubernetes audit logging", "API server audit policy records access to PHI namespaces",
+					audited, auditErr, "API server audit logging is enabled",
+					"API server has no audit policy or audit backend configured",
+					"Configure --audit-policy-file with a log or webhook backend"),
+				manualCheck("au-2", "Log retention 6+ years", "Audit logs retained per HIPAA documentation requirement",
+					"Retention is configured in the log backend, outside the cluster"),
+			},
+		},
+		{
+			ID: "164.312(c)", Section: "§164.312(c)(1)", Name: "Integrity Controls",
+			Description: "Implement policies to protect PHI from improper alteration or destruction.",
+			Checks: []Check{
+				ratioCheck("ic-1", "Default-deny ingress NetworkPolicy", "Every PHI namespace denies ingress unless explicitly allowed",
+					len(defaultDeny), total, "%d of %d PHI namespaces have a default-deny ingress policy"+probeNote,
+					"Add a NetworkPolicy with an empty podSelector and policyTypes [Ingress] to: "+strings.Join(noDeny, ", ")),
+				ratioCheck("ic-2", "NetworkPolicy isolation", "No NetworkPolicy in a PHI namespace allows ingress from every namespace or from anywhere",
+					len(restricted), total, "%d of %d PHI namespaces restrict ingress to named namespaces"+probeNote,
+					"Narrow these policies to specific namespaceSelectors: "+strings.Join(open, "; ")),
+			},
+		},
+		{
+			ID: "164.312(d)", Section: "§164.312(d)", Name: "Person or Entity Authentication",
+			Description: "Verify identity of persons seeking access to PHI.",
+			Checks: []Check{
+				manualCheck("pa-1", "Multi-factor authentication", "MFA required for PHI system access",
+					"MFA is enforced by the identity provider, outside the cluster"),
+			},
+		},
+		{
+			ID: "164.312(e)", Section: "§164.312(e)(1)", Name: "Transmission Security",
+			Description: "Implement measures to guard against unauthorized access to PHI during transmission.",
+			Checks: []Check{
+				ingressTLSCheck(ingressTLS, ingressTotal, plainIngresses),
+				{
+					ID: "ts-2", Name: "TLS on PHI Services", Description: "Services in PHI namespaces expose only TLS ports unless a mesh provides mTLS",
+					Status:   boolStatus(len(plainServices) == 0),
+					Evidence: servicesEvidence(plainServices),
+					Remediation: remediationIf(len(plainServices) > 0,
+						"Serve TLS or enable mesh mTLS for: "+strings.Join(plainServices, ", ")),
+				},
+				ratioCheck("ts-3", "Mutual TLS between services", "PHI namespaces are enrolled in a service mesh that provides mTLS",
+					len(meshed), total, "%d of %d PHI namespaces have mesh sidecar injection enabled",
+					"Enable Istio or Linkerd injection for: "+strings.Join(unmeshed, ", ")),
+			},
+		},
+	}
+	for i := range safeguards {
+		safeguards[i].Status = safeguardStatus(safeguards[i].Checks)
+	}
+	return safeguards
+}
+
+func ratioCheck(id, name, description string, ok, total int, evidenceFmt, remediation string) Check {
+	c := Check{ID: id, Name: name, Description: description}
+	if total == 0 {
+		c.Status = "skipped"
+		c.Evidence = "No PHI namespaces discovered"
+		return c
+	}
+	c.Evidence = fmt.Sprintf(evidenceFmt, ok, total)
+	switch {
+	case ok == total:
+		c.Status = "pass"
+	case ok == 0:
+		c.Status = "fail"
+		c.Remediation = remediation
+	default:
+		c.Status = "partial"
+		c.Remediation = remediation
+	}
+	return c
+}
+
+func boolCheck(id, name, description string, ok bool, err error, passEvidence, failEvidence, remediation string) Check {
+	c := Check{ID: id, Name: name, Description: description}
+	switch {
+	case errors.Is(err, ErrNotObservable):
+		c.Status = "skipped"
+		c.Evidence = "Not observable from cluster state (managed control plane?); attest manually"
+	case err != nil:
+		c.Status = "fail"
+		c.Evidence = fmt.Sprintf("Probe failed: %v", err)
+		c.Remediation = "Grant the console read access to kube-system so the control plane configuration can be inspected"
+	case ok:
+		c.Status = "pass"
+		c.Evidence = passEvidence
+	default:
+		c.Status = "fail"
+		c.Evidence = failEvidence
+		c.Remediation = remediation
+	}
+	return c
+}
+
+func manualCheck(id, name, description, reason string) Check {
+	return Check{
+		ID: id, Name: name, Description: description,
+		Status:   "skipped",
+		Evidence: "Requires manual attestation: " + reason,
+	}
+}
+
+func ingressTLSCheck(tls, total int, plain []string) Check {
+	c := Check{ID: "ts-1", Name: "TLS on PHI Ingresses", Description: "Every Ingress routing to a PHI namespace terminates TLS"}
+	if total == 0 {
+		c.Status = "pass"
+		c.Evidence = "No Ingresses expose PHI namespaces"
+		return c
+	}
+	c.Evidence = fmt.Sprintf("%d of %d Ingresses terminate TLS", tls, total)
+	switch {
+	case tls == total:
+		c.Status = "pass"
+	case tls == 0:
+		c.Status = "fail"
+	default:
+		c.Status = "partial"
+	}
+	if len(plain) > 0 {
+		c.Remediation = "Add a tls section to: " + strings.Join(plain, ", ")
+	}
+	return c
+}
+
+func servicesEvidence(plain []string) string {
+	if len(plain) == 0 {
+		return "All PHI Services use TLS ports or mesh mTLS"
+	}
+	return fmt.Sprintf("%d Service(s) expose plaintext ports: %s", len(plain), strings.Join(plain, ", "))
+}
+
+func remediationIf(cond bool, remediation string) string {
+	if cond {
+		return remediation
+	}
+	return ""
+}
+
+func boolStatus(ok bool) string {
+	if ok {
+		return "pass"
+	}
+	return "fail"
+}
+
+// safeguardStatus rolls check results up: skipped checks are ignored, a
+// mixture of outcomes is partial.
+func safeguardStatus(checks []Check) string {
+	pass, fail, partial := 0, 0, 0
+	for _, c := range checks {
+		switch c.Status {
+		case "pass":
+			pass++
+		case "fail":
+			fail++
+		case "partial":
+			partial++
+		}
+	}
+	switch {
+	case pass+fail+partial == 0:
+		return "skipped"
+	case fail == 0 && partial == 0:
+		return "pass"
+	case pass == 0 && partial == 0:
+		return "fail"
+	default:
+		return "partial"
+	}
+}
+
+func hasBindings(f *namespaceFacts) bool {
+	return len(f.bindings) > 0
+}
+
+func hasBroadSubject(f *namespaceFacts) bool {
+	for _, b := range f.bindings {
+		for _, s := range b.Subjects {
+			if broadSubjects[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasIngressType(p NetworkPolicyInfo) bool {
+	for _, t := range p.PolicyTypes {
+		if t == "Ingress" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDefaultDeny(f *namespaceFacts) bool {
+	for _, p := range f.policies {
+		if p.SelectsAllPods && hasIngressType(p) && len(p.IngressRules) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// openIngressPolicies names the policies that admit traffic from every
+// namespace or from anywhere.
+func openIngressPolicies(f *namespaceFacts) []string {
+	var out []string
+	for _, p := range f.policies {
+		if !hasIngressType(p) {
+			continue
+		}
+		for _, r := range p.IngressRules {
+			if r.AllNamespaces || r.FromAnywhere {
+				out = append(out, p.Name)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// meshMTLS reports whether the namespace is enrolled in a service mesh that
+// encrypts pod-to-pod traffic with mutual TLS.
+func meshMTLS(ns NamespaceInfo) bool {
+	if ns.Labels["istio-injection"] == "enabled" || ns.Labels["istio.io/rev"] != "" ||
+		ns.Labels["istio.io/dataplane-mode"] == "ambient" {
+		return true
+	}
+	return ns.Annotations["linkerd.io/inject"] == "enabled"
+}
+
+func tlsPortNumber(port int32) bool {
+	return port == 443 || port == 8443 || port == 6443
+}
+
+func servicePortTLS(p ServicePort) bool {
+	switch strings.ToLower(p.AppProtocol) {
+	case "https", "tls", "grpcs", "kubernetes.io/wss":
+		return true
+	}
+	name := strings.ToLower(p.Name)
+	if strings.HasPrefix(name, "https") || strings.HasPrefix(name, "tls") || strings.HasPrefix(name, "grpcs") {
+		return true
+	}
+	return tlsPortNumber(p.Port)
+}
+
+func serviceTLSOnly(s ServiceInfo) bool {
+	for _, p := range s.Ports {
+		if !servicePortTLS(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// deriveDataFlows turns Ingresses and NetworkPolicy allow rules into flows
+// into a PHI namespace. A namespace without any ingress policy accepts
+// traffic from everywhere, which is reported as a single open flow.
+func deriveDataFlows(cluster string, f *namespaceFacts, phi map[string]bool) []DataFlow {
+	ns := f.info.Name
+	mesh := meshMTLS(f.info)
+	seen := make(map[string]bool)
+	var flows []DataFlow
+	add := func(flow DataFlow) {
+		key := flow.Source + "|" + flow.Destination + "|" + flow.Protocol
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		flow.Cluster = cluster
+		flows = append(flows, flow)
+	}
+
+	for _, ing := range f.ingresses {
+		protocol := "HTTP"
+		if ing.TLS {
+			protocol = "HTTPS"
+		}
+		clientCerts := ing.Annotations["nginx.ingress.kubernetes.io/auth-tls-verify-client"] == "on"
+		for _, svc := range ing.Services {
+			add(DataFlow{
+				Source:      "ingress/" + ing.Name,
+				Destination: ns + "/" + svc,
+				Protocol:    protocol,
+				Encrypted:   ing.TLS,
+				MutualTLS:   ing.TLS && clientCerts,
+			})
+		}
+	}
+
+	tlsServicePorts := make(map[int32]bool)
+	for _, svc := range f.services {
+		for _, p := range svc.Ports {
+			if servicePortTLS(p) {
+				tlsServicePorts[p.Port] = true
+			}
+		}
+	}
+	portsEncrypted := func(ports []string) bool {
+		if len(ports) == 0 {
+			return false
+		}
+		for _, p := range ports {
+			_, num, _ := strings.Cut(p, "/")
+			n, err := strconv.Atoi(num)
+			if err != nil || !(tlsPortNumber(int32(n)) || tlsServicePorts[int32(n)]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	governed := false
+	for _, p := range f.policies {
+		if !hasIngressType(p) {
+			continue
+		}
+		governed = true
+		for _, r := range p.IngressRules {
+			protocol := "any"
+			if len(r.Ports) > 0 {
+				protocol = strings.Join(r.Ports, ",")
+			}
+			encrypted := mesh || portsEncrypted(r.Ports)
+			var sources []string
+			switch {
+			case r.FromAnywhere:
+				sources = []string{"anywhere"}
+			case r.AllNamespaces:
+				sources = []string{"any namespace"}
+			default:
+				for _, src := range r.FromNamespaces {
+					if src != ns {
+						sources = append(sources, src)
+					}
+				}
+			}
+			for _, src := range sources {
+				// Both ends must be enrolled for the mesh to provide mTLS.
+				mutual := mesh && phi[src]
+				add(DataFlow{Source: src, Destination: ns, Protocol: protocol, Encrypted: encrypted, MutualTLS: mutual})
+			}
+		}
+	}
+	if !governed {
+		add(DataFlow{Source: "anywhere", Destination: ns, Protocol: "any", Encrypted: mesh, MutualTLS: false})
+	}
+	return flows
+}
+
+// mergeReports combines per-cluster reports. A check that passes on some
+// clusters and fails on others becomes partial, with per-cluster evidence.
+func mergeReports(reports []*Report) *Report {
+	if len(reports) == 1 {
+		r := *reports[0]
+		return &r
+	}
+	merged := &Report{Mode: ModeLive}
+	type checkAgg struct {
+		check    Check
+		statuses []string
+		evidence []string
+	}
+	order := make([]string, 0)
+	safeguards := make(map[string]*Safeguard)
+	checks := make(map[string]map[string]*checkAgg)
+	checkOrder := make(map[string][]string)
+
+	for _, r := range reports {
+		cluster := r.Clusters[0]
+		merged.Clusters = append(merged.Clusters, cluster)
+		merged.PHINamespaces = append(merged.PHINamespaces, r.PHINamespaces...)
+		merged.DataFlows = append(merged.DataFlows, r.DataFlows...)
+		for _, s := range r.Safeguards {
+			if _, ok := safeguards[s.ID]; !ok {
+				copied := s
+				copied.Checks = nil
+				safeguards[s.ID] = &copied
+				checks[s.ID] = make(map[string]*checkAgg)
+				order = append(order, s.ID)
+			}
+			for _, c := range s.Checks {
+				agg, ok := checks[s.ID][c.ID]
+				if !ok {
+					agg = &checkAgg{check: c}
+					checks[s.ID][c.ID] = agg
+					checkOrder[s.ID] = append(checkOrder[s.ID], c.ID)
+				}
+				agg.statuses = append(agg.statuses, c.Status)
+				agg.evidence = append(agg.evidence, cluster+": "+c.Evidence)
+				if agg.check.Remediation == "" {
+					agg.check.Remediation = c.Remediation
+				}
+			}
+		}
+	}
+
+	for _, id := range order {
+		s := safeguards[id]
+		for _, cid := range checkOrder[id] {
+			agg := checks[id][cid]
+			c := agg.check
+			statusChecks := make([]Check, len(agg.statuses))
+			for i, st := range agg.statuses {
+				statusChecks[i] = Check{Status: st}
+			}
+			c.Status = safeguardStatus(statusChecks)
+			c.Evidence = strings.Join(agg.evidence, "; ")
+			if c.Status == "pass" || c.Status == "skipped" {
+				c.Remediation = ""
+			}
+			s.Checks = append(s.Checks, c)
+		}
+		s.Status = safeguardStatus(s.Checks)
+		merged.Safeguards = append(merged.Safeguards, *s)
+	}
+	merged.Summary = summarize(merged.Safeguards, merged.PHINamespaces, merged.DataFlows)
+	merged.Summary.Mode = ModeLive
+	return merged
+}
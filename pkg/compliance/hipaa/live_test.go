@@ -0,0 +1,265 @@
+package hipaa
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type stubProber struct {
+	namespaces []NamespaceInfo
+	encrypted  bool
+	encErr     error
+	audited    bool
+	auditErr   error
+	nsErr      error
+	policies   map[string][]NetworkPolicyInfo
+	services   map[string][]ServiceInfo
+	ingresses  map[string][]IngressInfo
+	bindings   map[string][]RoleBindingInfo
+	calls      atomic.Int32
+}
+
+func (p *stubProber) Namespaces(_ context.Context, _ string) ([]NamespaceInfo, error) {
+	p.calls.Add(1)
+	return p.namespaces, p.nsErr
+}
+func (p *stubProber) EncryptionAtRestEnabled(_ context.Context, _ string) (bool, error) {
+	return p.encrypted, p.encErr
+}
+func (p *stubProber) AuditLoggingEnabled(_ context.Context, _ string) (bool, error) {
+	return p.audited, p.auditErr
+}
+func (p *stubProber) NetworkPolicies(_ context.Context, _, ns string) ([]NetworkPolicyInfo, error) {
+	return p.policies[ns], nil
+}
+func (p *stubProber) Services(_ context.Context, _, ns string) ([]ServiceInfo, error) {
+	return p.services[ns], nil
+}
+func (p *stubProber) Ingresses(_ context.Context, _, ns string) ([]IngressInfo, error) {
+	return p.ingresses[ns], nil
+}
+func (p *stubProber) RoleBindings(_ context.Context, _, ns string) ([]RoleBindingInfo, error) {
+	return p.bindings[ns], nil
+}
+
+// compliantProber returns one fully compliant PHI namespace ("patients") and
+// one unlabelled namespace that must be ignored.
+func compliantProber() *stubProber {
+	return &stubProber{
+		namespaces: []NamespaceInfo{
+			{Name: "patients", Labels: map[string]string{DefaultPHILabel: "true", "istio-injection": "enabled"}},
+			{Name: "default"},
+		},
+		encrypted: true,
+		audited:   true,
+		policies: map[string][]NetworkPolicyInfo{
+			"patients": {
+				{Name: "default-deny", SelectsAllPods: true, PolicyTypes: []string{"Ingress"}},
+				{Name: "allow-gateway", PolicyTypes: []string{"Ingress"}, IngressRules: []NetworkPolicyRule{
+					{FromNamespaces: []string{"gateway"}, Ports: []string{"TCP/8443"}},
+				}},
+			},
+		},
+		services: map[string][]ServiceInfo{
+			"patients": {{Name: "api", Ports: []ServicePort{{Name: "http", Port: 8080, Protocol: "TCP"}}}},
+		},
+		ingresses: map[string][]IngressInfo{
+			"patients": {{Name: "portal", TLS: true, Services: []string{"api"}}},
+		},
+		bindings: map[string][]RoleBindingInfo{
+			"patients": {{Name: "clinicians", RoleName: "reader", Subjects: []string{"Group:clinicians"}}},
+		},
+	}
+}
+
+func findCheck(t *testing.T, r *Report, id string) Check {
+	t.Helper()
+	for _, s := range r.Safeguards {
+		for _, c := range s.Checks {
+			if c.ID == id {
+				return c
+			}
+		}
+	}
+	t.Fatalf("check %s not found", id)
+	return Check{}
+}
+
+func TestEvaluate_DemoMode(t *testing.T) {
+	e := NewEngine()
+	if e.IsLive() {
+		t.Fatal("NewEngine should not be live")
+	}
+	r, err := e.Evaluate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if r.Mode != ModeDemo || r.Summary.Mode != ModeDemo {
+		t.Errorf("expected demo mode, got %q / %q", r.Mode, r.Summary.Mode)
+	}
+	if len(r.Safeguards) != 5 {
+		t.Errorf("expected 5 demo safeguards, got %d", len(r.Safeguards))
+	}
+}
+
+func TestEvaluate_LiveCompliantCluster(t *testing.T) {
+	e := NewLiveEngine(compliantProber(), LiveOptions{})
+	r, err := e.Evaluate(context.Background(), []string{"prod"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if r.Mode != ModeLive {
+		t.Errorf("expected live mode, got %q", r.Mode)
+	}
+	if len(r.PHINamespaces) != 1 || r.PHINamespaces[0].Name != "patients" {
+		t.Fatalf("expected only the labelled namespace, got %+v", r.PHINamespaces)
+	}
+	if ns := r.PHINamespaces[0]; !ns.Compliant || ns.Cluster != "prod" {
+		t.Errorf("expected compliant namespace on prod, got %+v", ns)
+	}
+	// MFA and log retention need manual attestation.
+	if c := findCheck(t, r, "pa-1"); c.Status != "skipped" {
+		t.Errorf("pa-1: expected skipped, got %s", c.Status)
+	}
+	// Plain-HTTP service port is acceptable because the mesh provides mTLS.
+	if c := findCheck(t, r, "ts-2"); c.Status != "pass" {
+		t.Errorf("ts-2: expected pass with mesh, got %s (%s)", c.Status, c.Evidence)
+	}
+	if r.Summary.SafeguardsSkipped != 1 || r.Summary.SafeguardsPassed != 4 || r.Summary.OverallScore != 100 {
+		t.Errorf("unexpected summary: %+v", r.Summary)
+	}
+	var gatewayFlow bool
+	for _, f := range r.DataFlows {
+		if f.Source == "gateway" && f.Destination == "patients" {
+			gatewayFlow = f.Encrypted && f.Cluster == "prod"
+		}
+	}
+	if !gatewayFlow {
+		t.Errorf("expected encrypted gateway -> patients flow, got %+v", r.DataFlows)
+	}
+}
+
+func TestEvaluate_LiveFindings(t *testing.T) {
+	p := compliantProber()
+	p.encErr = ErrNotObservable
+	p.audited = false
+	p.namespaces[0].Labels = map[string]string{DefaultPHILabel: "true"}
+	p.policies["patients"] = []NetworkPolicyInfo{{Name: "allow-all", PolicyTypes: []string{"Ingress"},
+		IngressRules: []NetworkPolicyRule{{AllNamespaces: true}}}}
+	p.bindings["patients"] = append(p.bindings["patients"],
+		RoleBindingInfo{Name: "everyone", RoleName: "view", Subjects: []string{"Group:system:authenticated"}})
+
+	r, err := NewLiveEngine(p, LiveOptions{}).Evaluate(context.Background(), []string{"prod"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	want := map[string]string{
+		"ac-2": "fail", "ac-3": "skipped", "au-1": "fail",
+		"ic-1": "fail", "ic-2": "fail", "ts-2": "fail", "ts-3": "fail",
+	}
+	for id, status := range want {
+		if c := findCheck(t, r, id); c.Status != status {
+			t.Errorf("%s: expected %s, got %s (%s)", id, status, c.Status, c.Evidence)
+		}
+	}
+	if r.PHINamespaces[0].Compliant {
+		t.Error("namespace should not be compliant")
+	}
+}
+
+func TestEvaluate_AnnotationSelectsNamespace(t *testing.T) {
+	p := compliantProber()
+	p.namespaces[0].Labels = nil
+	p.namespaces[0].Annotations = map[string]string{DefaultPHIAnnotation: "true"}
+	r, err := NewLiveEngine(p, LiveOptions{}).Evaluate(context.Background(), []string{"prod"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(r.PHINamespaces) != 1 {
+		t.Fatalf("expected annotated namespace to be discovered, got %d", len(r.PHINamespaces))
+	}
+}
+
+func TestEvaluate_MergesClustersAndReportsErrors(t *testing.T) {
+	good := compliantProber()
+	e := NewLiveEngine(&multiProber{byCluster: map[string]Prober{
+		"a": good,
+		"b": &stubProber{namespaces: good.namespaces, audited: true, encrypted: true},
+		"c": &stubProber{nsErr: errors.New("connection refused")},
+	}}, LiveOptions{})
+	r, err := e.Evaluate(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(r.Clusters) != 2 || r.Errors["c"] == "" {
+		t.Errorf("expected clusters a,b and an error for c, got %v / %v", r.Clusters, r.Errors)
+	}
+	if c := findCheck(t, r, "ic-1"); c.Status != "partial" {
+		t.Errorf("ic-1: expected partial across clusters, got %s", c.Status)
+	}
+	if len(r.PHINamespaces) != 2 {
+		t.Errorf("expected one PHI namespace per cluster, got %d", len(r.PHINamespaces))
+	}
+
+	if _, err := e.Evaluate(context.Background(), []string{"c"}); err == nil {
+		t.Error("expected an error when every cluster fails")
+	}
+}
+
+func TestEvaluate_CachesPerCluster(t *testing.T) {
+	p := compliantProber()
+	e := NewLiveEngine(p, LiveOptions{CacheTTL: time.Minute})
+	now := time.Now()
+	e.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := e.Evaluate(context.Background(), []string{"prod"}); err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+	}
+	if got := p.calls.Load(); got != 1 {
+		t.Errorf("expected cached second evaluation, got %d probes", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	e.Evaluate(context.Background(), []string{"prod"})
+	if got := p.calls.Load(); got != 2 {
+		t.Errorf("expected re-probe after TTL, got %d probes", got)
+	}
+
+	e.Invalidate("")
+	e.Evaluate(context.Background(), []string{"prod"})
+	if got := p.calls.Load(); got != 3 {
+		t.Errorf("expected re-probe after Invalidate, got %d probes", got)
+	}
+}
+
+// multiProber dispatches to a per-cluster stub.
+type multiProber struct {
+	byCluster map[string]Prober
+}
+
+func (m *multiProber) Namespaces(ctx context.Context, c string) ([]NamespaceInfo, error) {
+	return m.byCluster[c].Namespaces(ctx, c)
+}
+func (m *multiProber) EncryptionAtRestEnabled(ctx context.Context, c string) (bool, error) {
+	return m.byCluster[c].EncryptionAtRestEnabled(ctx, c)
+}
+func (m *multiProber) AuditLoggingEnabled(ctx context.Context, c string) (bool, error) {
+	return m.byCluster[c].AuditLoggingEnabled(ctx, c)
+}
+func (m *multiProber) NetworkPolicies(ctx context.Context, c, ns string) ([]NetworkPolicyInfo, error) {
+	return m.byCluster[c].NetworkPolicies(ctx, c, ns)
+}
+func (m *multiProber) Services(ctx context.Context, c, ns string) ([]ServiceInfo, error) {
+	return m.byCluster[c].Services(ctx, c, ns)
+}
+func (m *multiProber) Ingresses(ctx context.Context, c, ns string) ([]IngressInfo, error) {
+	return m.byCluster[c].Ingresses(ctx, c, ns)
+}
+func (m *multiProber) RoleBindings(ctx context.Context, c, ns string) ([]RoleBindingInfo, error) {
+	return m.byCluster[c].RoleBindings(ctx, c, ns)
+}
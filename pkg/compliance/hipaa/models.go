@@ -17,7 +17,7 @@ type Check struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	Status      string `json:"status"` // pass, fail, partial
+	Status      string `json:"status"` // pass, fail, partial, skipped
 	Evidence    string `json:"evidence"`
 	Remediation string `json:"remediation"`
 }
@@ -30,11 +30,13 @@ type PHINamespace struct {
 	Encrypted     bool     `json:"encrypted"`
 	AuditEnabled  bool     `json:"audit_enabled"`
 	RBACRestricted bool   `json:"rbac_restricted"`
+	NetworkIsolated bool  `json:"network_isolated"`
 	Compliant     bool     `json:"compliant"`
 }
 
 // DataFlow represents a PHI data flow between components.
 type DataFlow struct {
+	Cluster     string `json:"cluster,omitempty"`
 	Source      string `json:"source"`
 	Destination string `json:"destination"`
 	Protocol    string `json:"protocol"`
@@ -48,10 +50,12 @@ type Summary struct {
 	SafeguardsPassed int            `json:"safeguards_passed"`
 	SafeguardsFailed int            `json:"safeguards_failed"`
 	SafeguardsPartial int           `json:"safeguards_partial"`
+	SafeguardsSkipped int           `json:"safeguards_skipped"`
 	TotalSafeguards  int            `json:"total_safeguards"`
 	PHINamespaces    int            `json:"phi_namespaces"`
 	CompliantNS      int            `json:"compliant_namespaces"`
 	DataFlows        int            `json:"data_flows"`
 	EncryptedFlows   int            `json:"encrypted_flows"`
 	EvaluatedAt      string         `json:"evaluated_at"`
+	Mode             string         `json:"mode"` // demo or live
 }
@@ -0,0 +1,97 @@
+package hipaa
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotObservable is returned by a Prober when a control cannot be read from
+// cluster state — for example API-server flags on a managed control plane.
+// The affected check is reported as skipped instead of failed.
+var ErrNotObservable = errors.New("not observable from cluster state")
+
+// Prober abstracts the cluster queries the live engine needs, in the same
+// spirit as frameworks.ClusterProber. The console implements it with the
+// multi-cluster client; tests provide a stub.
+type Prober interface {
+	// Namespaces lists every namespace with its labels and annotations.
+	Namespaces(ctx context.Context, cluster string) ([]NamespaceInfo, error)
+	// EncryptionAtRestEnabled reports whether the API server encrypts
+	// Secrets in etcd (--encryption-provider-config).
+	EncryptionAtRestEnabled(ctx context.Context, cluster string) (bool, error)
+	// AuditLoggingEnabled reports whether API-server audit logging is on.
+	AuditLoggingEnabled(ctx context.Context, cluster string) (bool, error)
+	// NetworkPolicies lists the NetworkPolicies in a namespace.
+	NetworkPolicies(ctx context.Context, cluster, namespace string) ([]NetworkPolicyInfo, error)
+	// Services lists the Services in a namespace.
+	Services(ctx context.Context, cluster, namespace string) ([]ServiceInfo, error)
+	// Ingresses lists the Ingresses in a namespace.
+	Ingresses(ctx context.Context, cluster, namespace string) ([]IngressInfo, error)
+	// RoleBindings lists the RoleBindings in a namespace.
+	RoleBindings(ctx context.Context, cluster, namespace string) ([]RoleBindingInfo, error)
+}
+
+// NamespaceInfo is the namespace metadata used for PHI discovery.
+type NamespaceInfo struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// NetworkPolicyInfo is a flattened NetworkPolicy.
+type NetworkPolicyInfo struct {
+	Name string
+	// SelectsAllPods is true for an empty podSelector.
+	SelectsAllPods bool
+	// PolicyTypes holds "Ingress" and/or "Egress".
+	PolicyTypes []string
+	// IngressRules are the allow rules; an Ingress policy with none is a
+	// default deny.
+	IngressRules []NetworkPolicyRule
+}
+
+// NetworkPolicyRule is one ingress allow rule.
+type NetworkPolicyRule struct {
+	// FromNamespaces names the peer namespaces resolved from
+	// namespaceSelector (via kubernetes.io/metadata.name). Empty with
+	// AllNamespaces false means same-namespace peers only.
+	FromNamespaces []string
+	// AllNamespaces is true for an empty namespaceSelector or ipBlock peer.
+	AllNamespaces bool
+	// FromAnywhere is true for a rule with no peers at all.
+	FromAnywhere bool
+	// Ports are "PROTO/port" strings; empty means all ports.
+	Ports []string
+}
+
+// ServiceInfo is a flattened Service.
+type ServiceInfo struct {
+	Name        string
+	Type        string
+	Ports       []ServicePort
+	Annotations map[string]string
+}
+
+// ServicePort is one Service port.
+type ServicePort struct {
+	Name        string
+	Port        int32
+	Protocol    string
+	AppProtocol string
+}
+
+// IngressInfo is a flattened Ingress.
+type IngressInfo struct {
+	Name        string
+	TLS         bool
+	Services    []string
+	Annotations map[string]string
+}
+
+// RoleBindingInfo is a RoleBinding reduced to what access control checks need.
+type RoleBindingInfo struct {
+	Name     string
+	RoleName string
+	// Subjects are "Kind:name" strings, e.g. "Group:system:authenticated".
+	Subjects []string
+}
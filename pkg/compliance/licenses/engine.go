@@ -1,17 +1,34 @@
 package licenses
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
-// Engine scans container images and SBOMs for license compliance.
-//
-// TODO (#9648): Replace stub with live Syft SBOM + SPDX license scanning.
-type Engine struct{}
+// Engine scans container images and SBOMs for license compliance. An
+// engine built with NewEngine serves demo data; one built with
+// NewLiveEngine classifies the SBOMs of running workloads (see live.go).
+type Engine struct {
+	sboms SBOMSource
+	store PolicyStore
 
-// NewEngine creates a license compliance engine. Currently returns demo data.
+	mu   sync.Mutex
+	last *Report
+	now  func() time.Time
+}
+
+// NewEngine creates a license compliance engine that serves demo data.
 func NewEngine() *Engine { return &Engine{} }
 
-// Summary returns fleet-wide license compliance metrics.
+// Summary returns fleet-wide license compliance metrics. A live engine
+// returns the result of its most recent Evaluate.
 func (e *Engine) Summary() Summary {
+	if e.IsLive() {
+		if r := e.lastReport(); r != nil {
+			return r.Summary
+		}
+		return Summary{Mode: ModeLive}
+	}
 	return Summary{
 		TotalPackages:    3847,
 		AllowedPackages:  3814,
@@ -20,18 +37,28 @@ func (e *Engine) Summary() Summary {
 		UniqueLicenses:   47,
 		WorkloadsScanned: 37,
 		EvaluatedAt:      time.Now(),
+		Mode:             ModeDemo,
 	}
 }
 
 // Packages returns all scanned packages with their license risk classification.
 func (e *Engine) Packages() []Package {
-	// TODO (#9648): Parse Syft SBOM output, normalize SPDX identifiers,
-	// classify against allow/warn/deny lists loaded from ConfigMap.
+	if r := e.lastReport(); r != nil && r.Packages != nil {
+		return r.Packages
+	}
 	return []Package{}
 }
 
 // Categories returns license risk categories with aggregate counts.
 func (e *Engine) Categories() []Category {
-	// TODO (#9648): Aggregate Packages() results by SPDX license family.
+	if r := e.lastReport(); r != nil && r.Categories != nil {
+		return r.Categories
+	}
 	return []Category{}
 }
+
+func (e *Engine) lastReport() *Report {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last
+}
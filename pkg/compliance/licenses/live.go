@@ -0,0 +1,356 @@
+package licenses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/sbom"
+)
+
+// Engine modes reported in Summary.Mode and Report.Mode.
+const (
+	ModeDemo = "demo"
+	ModeLive = "live"
+)
+
+// maxCategoryExamples bounds the license IDs listed per category.
+const maxCategoryExamples = 5
+
+// SBOMSource supplies the SBOMs of running workloads. *sbom.Engine from
+// sbom.NewLiveEngine implements it.
+type SBOMSource interface {
+	Evaluate(ctx context.Context, clusters []string) (*sbom.Report, error)
+	// Invalidate drops cached SBOMs.
+	Invalidate()
+}
+
+// Report is a fleet license compliance evaluation.
+type Report struct {
+	Mode       string             `json:"mode"`
+	Clusters   []ClusterLicenses  `json:"clusters"`
+	Workloads  []WorkloadLicenses `json:"workloads"`
+	Packages   []Package          `json:"packages"`
+	Categories []Category         `json:"categories"`
+	Summary    Summary            `json:"summary"`
+	// Policy is the policy packages were classified against.
+	Policy Policy            `json:"policy"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// NewLiveEngine creates a license engine that classifies the packages in
+// the SBOMs from sboms against the policy saved in store.
+func NewLiveEngine(sboms SBOMSource, store PolicyStore) *Engine {
+	return &Engine{sboms: sboms, store: store, now: time.Now}
+}
+
+// IsLive reports whether the engine reads real workloads.
+func (e *Engine) IsLive() bool {
+	return e.sboms != nil
+}
+
+// Invalidate drops cached SBOMs so the next evaluation re-reads them.
+func (e *Engine) Invalidate() {
+	if e.sboms != nil {
+		e.sboms.Invalidate()
+	}
+}
+
+// Policy returns the saved license policy, or DefaultPolicy if none has
+// been saved.
+func (e *Engine) Policy(ctx context.Context) (Policy, error) {
+	return loadPolicy(ctx, e.store)
+}
+
+// SetPolicy validates and saves p, recording who changed it. It returns
+// the policy as stored, with entries normalised to SPDX form.
+func (e *Engine) SetPolicy(ctx context.Context, p Policy, updatedBy string) (Policy, error) {
+	if e.store == nil {
+		return Policy{}, fmt.Errorf("license policy storage is not configured")
+	}
+	if err := p.Validate(); err != nil {
+		return Policy{}, err
+	}
+	p.UpdatedAt = e.now().UTC()
+	p.UpdatedBy = updatedBy
+	data, err := json.Marshal(p)
+	if err != nil {
+		return Policy{}, err
+	}
+	if err := e.store.SaveLicensePolicy(ctx, data); err != nil {
+		return Policy{}, err
+	}
+	return p, nil
+}
+
+// Evaluate classifies every package in the SBOMs of the workloads running
+// on clusters. Workloads without an SBOM are counted but cannot be scanned.
+// The result also backs Summary, Packages and Categories until the next
+// evaluation.
+func (e *Engine) Evaluate(ctx context.Context, clusters []string) (*Report, error) {
+	if !e.IsLive() {
+		return &Report{Mode: ModeDemo, Packages: e.Packages(), Categories: e.Categories(), Summary: e.Summary(), Policy: DefaultPolicy()}, nil
+	}
+	policy, err := e.Policy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load license policy: %w", err)
+	}
+	sboms, err := e.sboms.Evaluate(ctx, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Mode: ModeLive, Policy: policy, Errors: sboms.Errors, Packages: []Package{}}
+	workloads := make(map[string]*WorkloadLicenses)
+	var order []string
+	seen := make(map[string]bool)
+	for _, doc := range sboms.Documents {
+		key := doc.Cluster + "/" + doc.Namespace + "/" + doc.Workload
+		w, ok := workloads[key]
+		if !ok {
+			w = &WorkloadLicenses{Cluster: doc.Cluster, Namespace: doc.Namespace, Workload: doc.Workload,
+				Violations: []Package{}, Warnings: []Package{}}
+			workloads[key] = w
+			order = append(order, key)
+		}
+		for _, c := range doc.Components {
+			// A package shipped in several of a workload's images counts once.
+			pkgKey := key + "|" + c.Name + "|" + c.Version + "|" + c.PURL
+			if seen[pkgKey] {
+				continue
+			}
+			seen[pkgKey] = true
+			pkg := classifyComponent(&policy, c)
+			pkg.Workload, pkg.Namespace, pkg.Cluster, pkg.Image = doc.Workload, doc.Namespace, doc.Cluster, doc.Image
+			report.Packages = append(report.Packages, pkg)
+
+			w.Packages++
+			switch pkg.Risk {
+			case RiskAllowed:
+				w.Allowed++
+			case RiskWarn:
+				w.Warned++
+				w.Warnings = append(w.Warnings, pkg)
+			case RiskDenied:
+				w.Denied++
+				w.Violations = append(w.Violations, pkg)
+			}
+		}
+	}
+	for _, key := range order {
+		report.Workloads = append(report.Workloads, *workloads[key])
+	}
+	report.Clusters = summarizeClusters(&policy, sboms.Clusters, report.Workloads, report.Packages)
+	report.Categories = categorize(report.Packages)
+	report.Summary = summarize(report.Packages, report.Workloads, sboms.Summary.TotalWorkloads, e.now())
+
+	e.mu.Lock()
+	e.last = report
+	e.mu.Unlock()
+	return report, nil
+}
+
+// classifyComponent normalises c's license and classifies it.
+func classifyComponent(policy *Policy, c sbom.Component) Package {
+	pkg := Package{Name: c.Name, Version: c.Version, License: c.License, PURL: c.PURL}
+	x, err := parseExpression(c.License)
+	if err != nil {
+		pkg.Risk = policy.Unlisted
+		pkg.Reason = "unparseable license expression"
+		return pkg
+	}
+	if x == nil {
+		pkg.SPDXID = NoAssertion
+	} else {
+		pkg.SPDXID = x.String()
+	}
+	risk, because := policy.classify(x)
+	pkg.Risk = risk
+	pkg.Reason = strings.Join(because, ", ")
+	return pkg
+}
+
+func summarizeClusters(policy *Policy, clusters []string, workloads []WorkloadLicenses, packages []Package) []ClusterLicenses {
+	byName := make(map[string]*ClusterLicenses, len(clusters))
+	out := make([]ClusterLicenses, len(clusters))
+	for i, name := range clusters {
+		out[i] = ClusterLicenses{Cluster: name, DeniedLicenses: []string{}}
+		byName[name] = &out[i]
+	}
+	for _, w := range workloads {
+		cl := byName[w.Cluster]
+		if cl == nil {
+			continue
+		}
+		cl.Workloads++
+		cl.Packages += w.Packages
+		cl.Allowed += w.Allowed
+		cl.Warned += w.Warned
+		cl.Denied += w.Denied
+		if w.Denied > 0 {
+			cl.ViolatingWorkloads++
+		}
+	}
+	denied := make(map[string]map[string]bool)
+	for _, p := range packages {
+		if p.Risk != RiskDenied {
+			continue
+		}
+		x, err := parseExpression(p.SPDXID)
+		if err != nil || x == nil {
+			continue
+		}
+		for _, leaf := range x.leaves() {
+			if risk, _ := policy.classify(leaf); risk == RiskDenied {
+				if denied[p.Cluster] == nil {
+					denied[p.Cluster] = make(map[string]bool)
+				}
+				denied[p.Cluster][leaf.String()] = true
+			}
+		}
+	}
+	for i := range out {
+		for license := range denied[out[i].Cluster] {
+			out[i].DeniedLicenses = append(out[i].DeniedLicenses, license)
+		}
+		sort.Strings(out[i].DeniedLicenses)
+	}
+	return out
+}
+
+// License families used for Categories.
+const (
+	familyPermissive      = "Permissive"
+	familyPublicDomain    = "Public domain"
+	familyWeakCopyleft    = "Weak copyleft"
+	familyStrongCopyleft  = "Strong copyleft"
+	familyNetworkCopyleft = "Network copyleft"
+	familySourceAvailable = "Source available"
+	familyUnknown         = "Unknown"
+)
+
+// licenseFamily groups an SPDX license ID by how much it obliges licensees.
+func licenseFamily(id string) string {
+	upper := strings.ToUpper(id)
+	hasPrefix := func(prefixes ...string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(upper, p) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case id == NoAssertion || strings.HasPrefix(id, "LicenseRef-"):
+		return familyUnknown
+	case hasPrefix("AGPL-"):
+		return familyNetworkCopyleft
+	case hasPrefix("SSPL-", "BUSL-", "CC-BY-NC"):
+		return familySourceAvailable
+	case hasPrefix("GPL-"):
+		return familyStrongCopyleft
+	case hasPrefix("LGPL-", "MPL-", "EPL-", "CDDL-", "EUPL-", "CPL-", "MS-RPL", "OSL-", "CC-BY-SA-"):
+		return familyWeakCopyleft
+	case hasPrefix("CC0-", "UNLICENSE"):
+		return familyPublicDomain
+	case knownIDs[strings.ToLower(strings.TrimSuffix(id, "+"))] != "":
+		return familyPermissive
+	}
+	return familyUnknown
+}
+
+// categorize counts packages per license family. A package whose
+// expression names several licenses counts once in each of their families.
+func categorize(packages []Package) []Category {
+	type agg struct {
+		count    int
+		risk     Risk
+		licenses map[string]bool
+	}
+	families := make(map[string]*agg)
+	for _, p := range packages {
+		counted := make(map[string]bool)
+		for _, id := range packageLicenseIDs(p) {
+			fam := licenseFamily(id)
+			a := families[fam]
+			if a == nil {
+				a = &agg{risk: p.Risk, licenses: make(map[string]bool)}
+				families[fam] = a
+			}
+			a.licenses[id] = true
+			if !counted[fam] {
+				counted[fam] = true
+				a.count++
+				if riskRank[p.Risk] > riskRank[a.risk] {
+					a.risk = p.Risk
+				}
+			}
+		}
+	}
+	out := make([]Category, 0, len(families))
+	for name, a := range families {
+		examples := make([]string, 0, len(a.licenses))
+		for id := range a.licenses {
+			examples = append(examples, id)
+		}
+		sort.Strings(examples)
+		if len(examples) > maxCategoryExamples {
+			examples = examples[:maxCategoryExamples]
+		}
+		out = append(out, Category{Name: name, Count: a.count, Risk: a.risk, Examples: examples})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// packageLicenseIDs returns the license IDs named in p's normalised
+// expression, without exceptions.
+func packageLicenseIDs(p Package) []string {
+	x, err := parseExpression(p.SPDXID)
+	if err != nil || x == nil {
+		return []string{NoAssertion}
+	}
+	var ids []string
+	for _, leaf := range x.leaves() {
+		ids = append(ids, leaf.id)
+	}
+	return ids
+}
+
+func summarize(packages []Package, workloads []WorkloadLicenses, running int, now time.Time) Summary {
+	s := Summary{TotalPackages: len(packages), WorkloadsScanned: len(workloads), EvaluatedAt: now, Mode: ModeLive}
+	licenses := make(map[string]bool)
+	for _, p := range packages {
+		switch p.Risk {
+		case RiskAllowed:
+			s.AllowedPackages++
+		case RiskWarn:
+			s.WarnedPackages++
+		case RiskDenied:
+			s.DeniedPackages++
+		}
+		for _, id := range packageLicenseIDs(p) {
+			if id != NoAssertion {
+				licenses[id] = true
+			}
+		}
+	}
+	s.UniqueLicenses = len(licenses)
+	for _, w := range workloads {
+		if w.Denied > 0 {
+			s.ViolatingWorkloads++
+		}
+	}
+	if running > len(workloads) {
+		s.WorkloadsWithoutSBOM = running - len(workloads)
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package licenses
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/compliance/sbom"
+)
+
+type stubSBOMs struct{ report *sbom.Report }
+
+func (s stubSBOMs) Evaluate(context.Context, []string) (*sbom.Report, error) { return s.report, nil }
+func (stubSBOMs) Invalidate()                                                {}
+
+type memoryPolicyStore struct{ data []byte }
+
+func (m *memoryPolicyStore) GetLicensePolicy(context.Context) ([]byte, error) { return m.data, nil }
+func (m *memoryPolicyStore) SaveLicensePolicy(_ context.Context, data []byte) error {
+	m.data = data
+	return nil
+}
+
+func TestLiveEngine_Evaluate(t *testing.T) {
+	report := &sbom.Report{
+		Mode:     sbom.ModeLive,
+		Clusters: []string{"prod", "edge"},
+		Documents: []sbom.Document{
+			{Cluster: "prod", Namespace: "shop", Workload: "api", Image: "ghcr.io/org/api:v1", Components: []sbom.Component{
+				{Name: "left-pad", Version: "1.0.0", License: "MIT"},
+				{Name: "readline", Version: "8.2", License: "GPL-3.0+"},
+				{Name: "libxml", Version: "2.9", License: "MIT or GPL-2.0"},
+			}},
+			// The sidecar repeats left-pad, which counts once for the workload.
+			{Cluster: "prod", Namespace: "shop", Workload: "api", Image: "ghcr.io/org/sidecar:v1", Components: []sbom.Component{
+				{Name: "left-pad", Version: "1.0.0", License: "MIT"},
+				{Name: "glibc", Version: "2.36", License: "LGPL-2.1"},
+			}},
+			{Cluster: "edge", Namespace: "iot", Workload: "agent", Components: []sbom.Component{
+				{Name: "blob", Version: "1", License: ""},
+				{Name: "zlib", Version: "1.3", License: "Zlib"},
+			}},
+		},
+		Summary: sbom.Summary{TotalWorkloads: 3},
+	}
+	store := &memoryPolicyStore{}
+	e := NewLiveEngine(stubSBOMs{report}, store)
+
+	got, err := e.Evaluate(context.Background(), []string{"prod", "edge"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	s := got.Summary
+	if s.Mode != ModeLive || s.TotalPackages != 6 || s.AllowedPackages != 3 || s.WarnedPackages != 2 || s.DeniedPackages != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.WorkloadsScanned != 2 || s.WorkloadsWithoutSBOM != 1 || s.ViolatingWorkloads != 1 {
+		t.Errorf("unexpected workload counts: %+v", s)
+	}
+	if len(got.Workloads) != 2 {
+		t.Fatalf("expected 2 workloads, got %+v", got.Workloads)
+	}
+	api := got.Workloads[0]
+	if api.Workload != "api" || len(api.Violations) != 1 || api.Violations[0].SPDXID != "GPL-3.0-or-later" || len(api.Warnings) != 1 {
+		t.Errorf("unexpected api result: %+v", api)
+	}
+	if got.Clusters[0].Cluster != "prod" || got.Clusters[0].ViolatingWorkloads != 1 || len(got.Clusters[0].DeniedLicenses) != 1 {
+		t.Errorf("unexpected prod cluster: %+v", got.Clusters[0])
+	}
+	if edge := got.Clusters[1]; edge.Warned != 1 || edge.Denied != 0 {
+		t.Errorf("unexpected edge cluster: %+v", edge)
+	}
+	if len(e.Packages()) != 6 || len(e.Categories()) == 0 {
+		t.Errorf("engine getters should reflect the last report")
+	}
+
+	// Tighten the policy: unlisted licenses are now denied and LGPL is fine.
+	p := DefaultPolicy()
+	p.Unlisted = RiskDenied
+	p.Warn = []string{"MPL-*"}
+	p.Allow = append(p.Allow, "LGPL-*")
+	saved, err := e.SetPolicy(context.Background(), p, "legal")
+	if err != nil {
+		t.Fatalf("SetPolicy: %v", err)
+	}
+	if saved.UpdatedBy != "legal" || saved.UpdatedAt.IsZero() {
+		t.Errorf("policy not stamped: %+v", saved)
+	}
+	got, err = e.Evaluate(context.Background(), []string{"prod", "edge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary.DeniedPackages != 2 || got.Summary.WarnedPackages != 0 || got.Summary.ViolatingWorkloads != 2 {
+		t.Errorf("unexpected summary after policy change: %+v", got.Summary)
+	}
+	if got.Policy.UpdatedBy != "legal" {
+		t.Errorf("report should carry the saved policy: %+v", got.Policy)
+	}
+}
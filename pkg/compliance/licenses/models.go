@@ -2,11 +2,13 @@
 // Detects deny-listed licenses (GPL, AGPL, SSPL) and warn-listed licenses
 // (LGPL, MPL) within container image SBOMs and dependency manifests.
 //
-// TODO (#9648): Full implementation — integrate with:
-//   - License detection from Syft-generated SBOMs
-//   - SPDX license identifier normalization
-//   - Configurable allow/warn/deny lists via ConfigMap
-//   - OCI annotation-based license metadata retrieval
+// The live engine reads the SBOMs the sbom package discovers for running
+// workloads, normalises each package's license expression to SPDX
+// identifiers (see spdx.go) and classifies it against the operator's
+// allow/warn/deny Policy, which the console persists.
+//
+// TODO (#9648): Fall back to OCI annotation-based license metadata for
+// images that ship without an SBOM.
 package licenses
 
 import "time"
@@ -20,7 +22,8 @@ const (
 	RiskDenied  Risk = "denied"
 )
 
-// Package represents an individual dependency and its license.
+// Package represents an individual dependency and its license. License is
+// the expression as found in the SBOM and SPDXID its normalised form.
 type Package struct {
 	Name      string `json:"name"`
 	Version   string `json:"version"`
@@ -30,6 +33,10 @@ type Package struct {
 	Namespace string `json:"namespace"`
 	Cluster   string `json:"cluster"`
 	SPDXID    string `json:"spdx_id"`
+	PURL      string `json:"purl,omitempty"`
+	Image     string `json:"image,omitempty"`
+	// Reason names the licenses that decided Risk.
+	Reason string `json:"reason,omitempty"`
 }
 
 // Category groups licenses by risk tier.
@@ -42,11 +49,41 @@ type Category struct {
 
 // Summary aggregates license compliance metrics fleet-wide.
 type Summary struct {
-	TotalPackages   int       `json:"total_packages"`
-	AllowedPackages int       `json:"allowed_packages"`
-	WarnedPackages  int       `json:"warned_packages"`
-	DeniedPackages  int       `json:"denied_packages"`
-	UniqueLicenses  int       `json:"unique_licenses"`
-	WorkloadsScanned int      `json:"workloads_scanned"`
-	EvaluatedAt     time.Time `json:"evaluated_at"`
+	TotalPackages    int `json:"total_packages"`
+	AllowedPackages  int `json:"allowed_packages"`
+	WarnedPackages   int `json:"warned_packages"`
+	DeniedPackages   int `json:"denied_packages"`
+	UniqueLicenses   int `json:"unique_licenses"`
+	WorkloadsScanned int `json:"workloads_scanned"`
+	// WorkloadsWithoutSBOM are running workloads that could not be scanned.
+	WorkloadsWithoutSBOM int `json:"workloads_without_sbom"`
+	// ViolatingWorkloads have at least one denied package.
+	ViolatingWorkloads int       `json:"violating_workloads"`
+	EvaluatedAt        time.Time `json:"evaluated_at"`
+	Mode               string    `json:"mode"` // demo or live
+}
+
+// WorkloadLicenses is the license posture of one workload.
+type WorkloadLicenses struct {
+	Cluster    string    `json:"cluster"`
+	Namespace  string    `json:"namespace"`
+	Workload   string    `json:"workload"`
+	Packages   int       `json:"packages"`
+	Allowed    int       `json:"allowed"`
+	Warned     int       `json:"warned"`
+	Denied     int       `json:"denied"`
+	Violations []Package `json:"violations"`
+	Warnings   []Package `json:"warnings"`
+}
+
+// ClusterLicenses aggregates license posture for one cluster.
+type ClusterLicenses struct {
+	Cluster            string   `json:"cluster"`
+	Workloads          int      `json:"workloads"`
+	ViolatingWorkloads int      `json:"violating_workloads"`
+	Packages           int      `json:"packages"`
+	Allowed            int      `json:"allowed"`
+	Warned             int      `json:"warned"`
+	Denied             int      `json:"denied"`
+	DeniedLicenses     []string `json:"denied_licenses"`
 }
@@ -0,0 +1,188 @@
+package licenses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PolicyStore persists the operator-defined license policy as an opaque
+// JSON document. GetLicensePolicy returns nil when none has been saved.
+type PolicyStore interface {
+	GetLicensePolicy(ctx context.Context) ([]byte, error)
+	SaveLicensePolicy(ctx context.Context, data []byte) error
+}
+
+// Policy classifies licenses into allow, warn and deny lists. Entries are
+// SPDX IDs ("MIT"), license-with-exception terms
+// ("GPL-2.0-only WITH Classpath-exception-2.0") or prefixes ending in "*"
+// ("GPL-*"). An exact entry wins over a prefix; among entries of the same
+// kind deny wins over warn, and warn over allow.
+type Policy struct {
+	Allow []string `json:"allow"`
+	Warn  []string `json:"warn"`
+	Deny  []string `json:"deny"`
+	// Unlisted is the risk of licenses on no list, including packages that
+	// declare no license at all.
+	Unlisted  Risk      `json:"unlisted"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// DefaultPolicy is used until an operator saves one: permissive licenses
+// are allowed, weak copyleft is flagged for review, and strong and network
+// copyleft is denied.
+func DefaultPolicy() Policy {
+	return Policy{
+		Allow: []string{
+			"0BSD", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "BSL-1.0",
+			"CC0-1.0", "ISC", "MIT", "PSF-2.0", "Python-2.0", "Unlicense",
+			"Zlib", "GPL-2.0-only WITH Classpath-exception-2.0",
+		},
+		Warn:     []string{"CDDL-*", "EPL-*", "EUPL-*", "LGPL-*", "MPL-*"},
+		Deny:     []string{"AGPL-*", "GPL-*", "SSPL-*"},
+		Unlisted: RiskWarn,
+	}
+}
+
+// Validate normalises every entry to canonical SPDX form and checks that
+// no license appears on two lists.
+func (p *Policy) Validate() error {
+	switch p.Unlisted {
+	case "":
+		p.Unlisted = RiskWarn
+	case RiskAllowed, RiskWarn, RiskDenied:
+	default:
+		return fmt.Errorf("unlisted: unknown risk %q", p.Unlisted)
+	}
+	seen := make(map[string]Risk)
+	for _, list := range []struct {
+		risk    Risk
+		entries *[]string
+	}{{RiskAllowed, &p.Allow}, {RiskWarn, &p.Warn}, {RiskDenied, &p.Deny}} {
+		out := make([]string, 0, len(*list.entries))
+		for _, entry := range *list.entries {
+			norm, err := normalizeEntry(entry)
+			if err != nil {
+				return fmt.Errorf("%s: %w", list.risk, err)
+			}
+			if prev, ok := seen[norm]; ok {
+				return fmt.Errorf("%s is listed as both %s and %s", norm, prev, list.risk)
+			}
+			seen[norm] = list.risk
+			out = append(out, norm)
+		}
+		*list.entries = out
+	}
+	return nil
+}
+
+func normalizeEntry(entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return "", fmt.Errorf("empty entry")
+	}
+	if strings.HasSuffix(entry, "*") {
+		return entry, nil
+	}
+	x, err := parseExpression(entry)
+	if err != nil {
+		return "", err
+	}
+	if x == nil || x.op != "" {
+		return "", fmt.Errorf("%q is not a single license", entry)
+	}
+	return x.String(), nil
+}
+
+// riskRank orders risks so the worst of several can be picked.
+var riskRank = map[Risk]int{RiskAllowed: 0, RiskWarn: 1, RiskDenied: 2}
+
+// classify returns the risk of a parsed expression and the licenses that
+// decided it. The licensee may pick any branch of an OR, so it takes the
+// least risky; every term of an AND applies, so it takes the riskiest.
+func (p *Policy) classify(x *expr) (Risk, []string) {
+	if x == nil {
+		return p.Unlisted, []string{NoAssertion}
+	}
+	switch x.op {
+	case opOr, opAnd:
+		var best Risk
+		var because []string
+		for i, a := range x.args {
+			r, why := p.classify(a)
+			switch {
+			case i == 0:
+				best, because = r, why
+			case riskRank[r] == riskRank[best]:
+				because = append(because, why...)
+			case x.op == opOr && riskRank[r] < riskRank[best], x.op == opAnd && riskRank[r] > riskRank[best]:
+				best, because = r, why
+			}
+		}
+		return best, because
+	}
+	term := x.String()
+	if x.exception != "" {
+		// "GPL-2.0-only WITH Classpath-exception-2.0" may be listed on its
+		// own; otherwise the exception does not change the base license.
+		if r, ok := p.lookup(term, false); ok {
+			return r, []string{term}
+		}
+	}
+	if r, ok := p.lookup(x.id, true); ok {
+		return r, []string{term}
+	}
+	return p.Unlisted, []string{term}
+}
+
+// lookup finds the risk a policy assigns to license, trying exact entries
+// before prefix entries. Prefixes are skipped when prefixes is false.
+func (p *Policy) lookup(license string, prefixes bool) (Risk, bool) {
+	lists := []struct {
+		risk    Risk
+		entries []string
+	}{{RiskDenied, p.Deny}, {RiskWarn, p.Warn}, {RiskAllowed, p.Allow}}
+	for _, l := range lists {
+		for _, e := range l.entries {
+			if strings.EqualFold(e, license) {
+				return l.risk, true
+			}
+		}
+	}
+	if !prefixes {
+		return "", false
+	}
+	for _, l := range lists {
+		for _, e := range l.entries {
+			if prefix, ok := strings.CutSuffix(e, "*"); ok && len(license) >= len(prefix) && strings.EqualFold(license[:len(prefix)], prefix) {
+				return l.risk, true
+			}
+		}
+	}
+	return "", false
+}
+
+// loadPolicy reads the saved policy, falling back to DefaultPolicy.
+func loadPolicy(ctx context.Context, store PolicyStore) (Policy, error) {
+	if store == nil {
+		return DefaultPolicy(), nil
+	}
+	data, err := store.GetLicensePolicy(ctx)
+	if err != nil {
+		return Policy{}, err
+	}
+	if data == nil {
+		return DefaultPolicy(), nil
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Policy{}, fmt.Errorf("decode license policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return Policy{}, fmt.Errorf("saved license policy: %w", err)
+	}
+	return p, nil
+}
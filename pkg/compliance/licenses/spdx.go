@@ -0,0 +1,440 @@
+package licenses
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NoAssertion is reported for packages whose SBOM records no license.
+const NoAssertion = "NOASSERTION"
+
+// Expression operators, in increasing binding strength.
+const (
+	opOr   = "OR"
+	opAnd  = "AND"
+	opWith = "WITH"
+)
+
+// expr is a parsed SPDX license expression. Leaves carry a license ID and
+// an optional exception; inner nodes combine their args with op.
+type expr struct {
+	op        string // "", opAnd or opOr
+	id        string
+	exception string
+	args      []*expr
+}
+
+// String renders x in canonical form, parenthesising only where needed.
+func (x *expr) String() string {
+	if x.op == "" {
+		if x.exception != "" {
+			return x.id + " " + opWith + " " + x.exception
+		}
+		return x.id
+	}
+	parts := make([]string, len(x.args))
+	for i, a := range x.args {
+		parts[i] = a.String()
+		// AND binds tighter than OR, so only an OR inside an AND needs
+		// parentheses.
+		if a.op == opOr && x.op == opAnd {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+x.op+" ")
+}
+
+// leaves returns the license terms of x, each rendered with its exception.
+func (x *expr) leaves() []*expr {
+	if x.op == "" {
+		return []*expr{x}
+	}
+	var out []*expr
+	for _, a := range x.args {
+		out = append(out, a.leaves()...)
+	}
+	return out
+}
+
+// NormalizeExpression rewrites a license expression as found in an SBOM
+// (SPDX IDs in any case, deprecated IDs such as "GPL-2.0+", or free-text
+// names such as "Apache License, Version 2.0") as a canonical SPDX
+// expression. Names that match no SPDX ID become LicenseRef- identifiers.
+// An empty or NOASSERTION input yields NoAssertion.
+func NormalizeExpression(raw string) (string, error) {
+	x, err := parseExpression(raw)
+	if err != nil {
+		return "", err
+	}
+	if x == nil {
+		return NoAssertion, nil
+	}
+	return x.String(), nil
+}
+
+// parseExpression parses raw with SPDX precedence (WITH, then AND, then
+// OR). Operators are matched case-insensitively because lower-case "and"
+// and "or" are common in hand-written SBOMs. It returns nil for an empty or
+// NOASSERTION expression.
+func parseExpression(raw string) (*expr, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, NoAssertion) || strings.EqualFold(raw, "NONE") {
+		return nil, nil
+	}
+	p := &exprParser{tokens: tokenize(raw)}
+	x, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("license expression %q: %w", raw, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("license expression %q: unexpected %q", raw, p.tokens[p.pos])
+	}
+	return x, nil
+}
+
+// tokenize splits on whitespace and parentheses.
+func tokenize(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peekOp() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	switch op := strings.ToUpper(p.tokens[p.pos]); op {
+	case opOr, opAnd, opWith:
+		return op
+	}
+	return ""
+}
+
+func (p *exprParser) parseOr() (*expr, error) {
+	return p.parseBinary(opOr, p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (*expr, error) {
+	return p.parseBinary(opAnd, p.parseWith)
+}
+
+// parseBinary parses operands joined by op, flattening nested nodes of the
+// same operator.
+func (p *exprParser) parseBinary(op string, operand func() (*expr, error)) (*expr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	node := &expr{op: op}
+	add := func(x *expr) {
+		if x.op == op {
+			node.args = append(node.args, x.args...)
+		} else {
+			node.args = append(node.args, x)
+		}
+	}
+	add(first)
+	for p.peekOp() == op {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		add(next)
+	}
+	if len(node.args) == 1 {
+		return node.args[0], nil
+	}
+	return node, nil
+}
+
+func (p *exprParser) parseWith() (*expr, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peekOp() != opWith {
+		return x, nil
+	}
+	if x.op != "" || x.exception != "" {
+		return nil, fmt.Errorf("WITH must follow a single license")
+	}
+	p.pos++
+	term := p.term()
+	if term == "" {
+		return nil, fmt.Errorf("WITH without an exception")
+	}
+	x.exception = canonicalException(term)
+	return x, nil
+}
+
+func (p *exprParser) parsePrimary() (*expr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if p.tokens[p.pos] == "(" {
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return x, nil
+	}
+	term := p.term()
+	if term == "" {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return canonicalLicense(term), nil
+}
+
+// term consumes the words of one license name. Free-text names such as
+// "Apache License 2.0" span several tokens.
+func (p *exprParser) term() string {
+	var words []string
+	for p.pos < len(p.tokens) && p.peekOp() == "" && p.tokens[p.pos] != "(" && p.tokens[p.pos] != ")" {
+		words = append(words, p.tokens[p.pos])
+		p.pos++
+	}
+	return strings.Join(words, " ")
+}
+
+// canonicalLicense maps one license term to an SPDX leaf. Deprecated GNU
+// IDs are rewritten to their -only/-or-later forms, which may attach an
+// exception (e.g. "GPL-2.0-with-classpath-exception").
+func canonicalLicense(term string) *expr {
+	key := strings.ToLower(term)
+	if x, ok := deprecatedIDs[key]; ok {
+		return &expr{id: x.id, exception: x.exception}
+	}
+	if id, ok := knownIDs[key]; ok {
+		return &expr{id: id}
+	}
+	if base, ok := strings.CutSuffix(key, "+"); ok {
+		if x, ok := deprecatedIDs[base]; ok && strings.HasSuffix(x.id, "-only") {
+			return &expr{id: strings.TrimSuffix(x.id, "-only") + "-or-later"}
+		}
+		if id, ok := knownIDs[base]; ok {
+			return &expr{id: id + "+"}
+		}
+	}
+	if id, ok := licenseAliases[aliasKey(term)]; ok {
+		return canonicalLicense(id)
+	}
+	if strings.HasPrefix(key, "licenseref-") || strings.HasPrefix(key, "documentref-") || isIDString(term) {
+		// A well-formed ID we do not know; keep it for the policy to match.
+		return &expr{id: term}
+	}
+	return &expr{id: "LicenseRef-" + sanitizeRef(term)}
+}
+
+func canonicalException(term string) string {
+	if id, ok := knownExceptions[strings.ToLower(term)]; ok {
+		return id
+	}
+	return term
+}
+
+// aliasKey reduces a free-text license name to a lookup key: lower case,
+// no punctuation, and without filler words such as "the" and "version".
+func aliasKey(name string) string {
+	name = strings.NewReplacer(",", " ", "(", " ", ")", " ", "\"", " ").Replace(strings.ToLower(name))
+	var words []string
+	for _, w := range strings.Fields(name) {
+		switch w {
+		case "the", "version", "license", "licence":
+			continue
+		}
+		// "v2.0" → "2.0"
+		if len(w) > 1 && w[0] == 'v' && w[1] >= '0' && w[1] <= '9' {
+			w = w[1:]
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+// isIDString reports whether s is a valid SPDX idstring.
+func isIDString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range strings.TrimSuffix(s, "+") {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func sanitizeRef(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// knownIDs maps lower-cased SPDX IDs to their canonical spelling. It covers
+// the licenses that make up nearly all container image SBOMs; other
+// well-formed IDs pass through unchanged.
+var knownIDs = func() map[string]string {
+	ids := []string{
+		"0BSD", "AFL-2.1", "AFL-3.0", "AGPL-1.0-only", "AGPL-1.0-or-later",
+		"AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.0", "Apache-1.1",
+		"Apache-2.0", "APSL-2.0", "Artistic-1.0", "Artistic-1.0-Perl",
+		"Artistic-2.0", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause",
+		"BSD-2-Clause-Patent", "BSD-3-Clause", "BSD-3-Clause-Clear",
+		"BSD-4-Clause", "BSL-1.0", "BUSL-1.1", "bzip2-1.0.6", "CC-BY-3.0",
+		"CC-BY-4.0", "CC-BY-NC-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0", "CC0-1.0",
+		"CDDL-1.0", "CDDL-1.1", "CPL-1.0", "curl", "ECL-2.0", "EPL-1.0",
+		"EPL-2.0", "EUPL-1.1", "EUPL-1.2", "FTL", "GFDL-1.1-only",
+		"GFDL-1.1-or-later", "GFDL-1.2-only", "GFDL-1.2-or-later",
+		"GFDL-1.3-only", "GFDL-1.3-or-later", "GPL-1.0-only",
+		"GPL-1.0-or-later", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only",
+		"GPL-3.0-or-later", "HPND", "ICU", "IJG", "ISC", "LGPL-2.0-only",
+		"LGPL-2.0-or-later", "LGPL-2.1-only", "LGPL-2.1-or-later",
+		"LGPL-3.0-only", "LGPL-3.0-or-later", "Libpng", "libpng-2.0",
+		"MIT", "MIT-0", "MIT-CMU", "MPL-1.1", "MPL-2.0",
+		"MPL-2.0-no-copyleft-exception", "MS-PL", "MS-RPL", "NCSA",
+		"ODbL-1.0", "OFL-1.1", "OpenSSL", "OSL-3.0", "PHP-3.01",
+		"PostgreSQL", "PSF-2.0", "Python-2.0", "Ruby", "SSPL-1.0",
+		"Unicode-3.0", "Unicode-DFS-2016", "Unlicense", "UPL-1.0",
+		"Vim", "W3C", "WTFPL", "X11", "Zlib", "ZPL-2.1",
+	}
+	m := make(map[string]string, len(ids))
+	for _, id := range ids {
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}()
+
+// deprecatedIDs maps deprecated SPDX IDs to their replacements.
+var deprecatedIDs = map[string]expr{
+	"agpl-1.0":                         {id: "AGPL-1.0-only"},
+	"agpl-3.0":                         {id: "AGPL-3.0-only"},
+	"gfdl-1.1":                         {id: "GFDL-1.1-only"},
+	"gfdl-1.2":                         {id: "GFDL-1.2-only"},
+	"gfdl-1.3":                         {id: "GFDL-1.3-only"},
+	"gpl-1.0":                          {id: "GPL-1.0-only"},
+	"gpl-2.0":                          {id: "GPL-2.0-only"},
+	"gpl-3.0":                          {id: "GPL-3.0-only"},
+	"lgpl-2.0":                         {id: "LGPL-2.0-only"},
+	"lgpl-2.1":                         {id: "LGPL-2.1-only"},
+	"lgpl-3.0":                         {id: "LGPL-3.0-only"},
+	"gpl-2.0-with-classpath-exception": {id: "GPL-2.0-only", exception: "Classpath-exception-2.0"},
+	"gpl-2.0-with-gcc-exception":       {id: "GPL-2.0-only", exception: "GCC-exception-2.0"},
+	"gpl-3.0-with-gcc-exception":       {id: "GPL-3.0-only", exception: "GCC-exception-3.1"},
+	"gpl-2.0-with-autoconf-exception":  {id: "GPL-2.0-only", exception: "Autoconf-exception-2.0"},
+	"gpl-3.0-with-autoconf-exception":  {id: "GPL-3.0-only", exception: "Autoconf-exception-3.0"},
+}
+
+// knownExceptions maps lower-cased SPDX exception IDs to their canonical
+// spelling.
+var knownExceptions = func() map[string]string {
+	ids := []string{
+		"Autoconf-exception-2.0", "Autoconf-exception-3.0", "Bison-exception-2.2",
+		"Classpath-exception-2.0", "Font-exception-2.0", "GCC-exception-2.0",
+		"GCC-exception-3.1", "Linux-syscall-note", "LLVM-exception",
+		"OpenJDK-assembly-exception-1.0", "Qt-LGPL-exception-1.1",
+		"Universal-FOSS-exception-1.0",
+	}
+	m := make(map[string]string, len(ids))
+	for _, id := range ids {
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}()
+
+// licenseAliases maps aliasKey forms of common free-text license names, as
+// found in Maven POMs, npm and PyPI metadata, to SPDX IDs.
+var licenseAliases = map[string]string{
+	"apache 2.0":                          "Apache-2.0",
+	"apache 2":                            "Apache-2.0",
+	"apache2":                             "Apache-2.0",
+	"apache software 2.0":                 "Apache-2.0",
+	"asl 2.0":                             "Apache-2.0",
+	"mit":                                 "MIT",
+	"mit/x11":                             "MIT",
+	"expat":                               "MIT",
+	"isc":                                 "ISC",
+	"new bsd":                             "BSD-3-Clause",
+	"revised bsd":                         "BSD-3-Clause",
+	"modified bsd":                        "BSD-3-Clause",
+	"bsd 3-clause":                        "BSD-3-Clause",
+	"3-clause bsd":                        "BSD-3-Clause",
+	"simplified bsd":                      "BSD-2-Clause",
+	"freebsd":                             "BSD-2-Clause",
+	"bsd 2-clause":                        "BSD-2-Clause",
+	"2-clause bsd":                        "BSD-2-Clause",
+	"gplv2":                               "GPL-2.0-only",
+	"gpl 2":                               "GPL-2.0-only",
+	"gnu general public 2":                "GPL-2.0-only",
+	"gplv2+":                              "GPL-2.0-or-later",
+	"gplv3":                               "GPL-3.0-only",
+	"gpl 3":                               "GPL-3.0-only",
+	"gnu general public 3":                "GPL-3.0-only",
+	"gplv3+":                              "GPL-3.0-or-later",
+	"lgplv2":                              "LGPL-2.0-only",
+	"lgplv2.1":                            "LGPL-2.1-only",
+	"lgplv2+":                             "LGPL-2.0-or-later",
+	"lgplv3":                              "LGPL-3.0-only",
+	"lgplv3+":                             "LGPL-3.0-or-later",
+	"gnu lesser general public 2.1":       "LGPL-2.1-only",
+	"gnu lesser general public 3":         "LGPL-3.0-only",
+	"gnu library general public 2":        "LGPL-2.0-only",
+	"agplv3":                              "AGPL-3.0-only",
+	"gnu affero general public 3":         "AGPL-3.0-only",
+	"mozilla public 2.0":                  "MPL-2.0",
+	"mpl 2.0":                             "MPL-2.0",
+	"eclipse public 1.0":                  "EPL-1.0",
+	"eclipse public 2.0":                  "EPL-2.0",
+	"epl 2.0":                             "EPL-2.0",
+	"common development and distribution": "CDDL-1.0",
+	"cddl 1.1":                            "CDDL-1.1",
+	"boost software 1.0":                  "BSL-1.0",
+	"python software foundation":          "PSF-2.0",
+	"psf":                                 "PSF-2.0",
+	"server side public 1":                "SSPL-1.0",
+	"sspl":                                "SSPL-1.0",
+	"unlicense":                           "Unlicense",
+	"cc0":                                 "CC0-1.0",
+	"cc0 1.0":                             "CC0-1.0",
+	"zlib":                                "Zlib",
+	"zlib/libpng":                         "Zlib",
+	"universal permissive 1.0":            "UPL-1.0",
+	"wtfpl":                               "WTFPL",
+}
@@ -0,0 +1,84 @@
+package licenses
+
+import "testing"
+
+func TestNormalizeExpression(t *testing.T) {
+	cases := map[string]string{
+		"":                            NoAssertion,
+		"NOASSERTION":                 NoAssertion,
+		"mit":                         "MIT",
+		"Apache License, Version 2.0": "Apache-2.0",
+		"The Apache Software License, Version 2.0": "Apache-2.0",
+		"GPL-2.0+":                             "GPL-2.0-or-later",
+		"LGPL-2.1":                             "LGPL-2.1-only",
+		"GPLv3":                                "GPL-3.0-only",
+		"MIT or Apache-2.0":                    "MIT OR Apache-2.0",
+		"(MIT OR Apache-2.0) AND BSD-3-Clause": "(MIT OR Apache-2.0) AND BSD-3-Clause",
+		"MIT AND (BSD-2-Clause AND ISC)":       "MIT AND BSD-2-Clause AND ISC",
+		"gpl-2.0-only with classpath-exception-2.0": "GPL-2.0-only WITH Classpath-exception-2.0",
+		"GPL-2.0-with-classpath-exception":          "GPL-2.0-only WITH Classpath-exception-2.0",
+		"Apache License 2.0 AND MIT License":        "Apache-2.0 AND MIT",
+		"LicenseRef-acme-eula":                      "LicenseRef-acme-eula",
+		"Public Domain":                             "LicenseRef-Public-Domain",
+	}
+	for in, want := range cases {
+		got, err := NormalizeExpression(in)
+		if err != nil {
+			t.Errorf("NormalizeExpression(%q): %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeExpression(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"MIT AND", "(MIT OR ISC", "MIT WITH", "(MIT OR ISC) WITH LLVM-exception"} {
+		if _, err := NormalizeExpression(bad); err == nil {
+			t.Errorf("NormalizeExpression(%q): expected error", bad)
+		}
+	}
+}
+
+func TestPolicyClassify(t *testing.T) {
+	p := DefaultPolicy()
+	if err := p.Validate(); err != nil {
+		t.Fatalf("default policy: %v", err)
+	}
+	cases := map[string]Risk{
+		"MIT":                               RiskAllowed,
+		"GPL-3.0-only":                      RiskDenied,
+		"LGPL-2.1-or-later":                 RiskWarn,
+		"AGPL-3.0-only":                     RiskDenied,
+		"MIT OR GPL-3.0-only":               RiskAllowed,
+		"MIT AND GPL-3.0-only":              RiskDenied,
+		"MPL-2.0 AND (MIT OR GPL-2.0-only)": RiskWarn,
+		"GPL-2.0-only WITH Classpath-exception-2.0": RiskAllowed,
+		"GPL-3.0-only WITH GCC-exception-3.1":       RiskDenied,
+		"LicenseRef-acme-eula":                      RiskWarn,
+		"":                                          RiskWarn,
+	}
+	for in, want := range cases {
+		x, err := parseExpression(in)
+		if err != nil {
+			t.Fatalf("parse %q: %v", in, err)
+		}
+		if got, _ := p.classify(x); got != want {
+			t.Errorf("classify(%q) = %s, want %s", in, got, want)
+		}
+	}
+
+	// An exact entry overrides a prefix on another list.
+	p.Allow = append(p.Allow, "lgpl-2.1-only")
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	x, _ := parseExpression("LGPL-2.1")
+	if got, _ := p.classify(x); got != RiskAllowed {
+		t.Errorf("exact allow entry should beat LGPL-* warn, got %s", got)
+	}
+
+	dup := Policy{Allow: []string{"MIT"}, Deny: []string{"mit"}}
+	if err := dup.Validate(); err == nil {
+		t.Error("expected error for a license on two lists")
+	}
+}
@@ -0,0 +1,326 @@
+// Package oci is a minimal OCI distribution API client used by the supply
+// chain engines to read cosign signatures and attestations stored next to
+// images in a registry. It supports anonymous and basic-auth bearer token
+// flows and only ever issues GET requests.
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Media types accepted when fetching manifests.
+const (
+	MediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	MediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+const (
+	// defaultRequestTimeout bounds one registry round-trip.
+	defaultRequestTimeout = 30 * time.Second
+	// maxManifestBytes caps manifest responses; real manifests are a few KB.
+	maxManifestBytes = 4 << 20
+	// maxBlobBytes caps blob downloads. Signature payloads and attestation
+	// envelopes are small; SBOM attestations can reach a few MB.
+	maxBlobBytes = 64 << 20
+)
+
+// ErrNotFound is returned when a manifest or blob does not exist.
+var ErrNotFound = errors.New("not found in registry")
+
+// Descriptor references a blob or manifest.
+type Descriptor struct {
+	MediaType    string            `json:"mediaType"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest (or Docker v2 schema 2 manifest).
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	ArtifactType  string       `json:"artifactType,omitempty"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+	// Subject is the manifest this artifact refers to (OCI 1.1 referrers).
+	Subject     *Descriptor       `json:"subject,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Index is an OCI image index, the response of the referrers API.
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// Credentials returns basic-auth credentials for a registry host, or empty
+// strings for anonymous access.
+type Credentials func(registry string) (username, password string)
+
+// Client reads manifests and blobs from OCI registries.
+type Client struct {
+	// HTTP is the underlying client; defaults to one with a 30s timeout.
+	HTTP *http.Client
+	// Credentials is optional.
+	Credentials Credentials
+	// PlainHTTP forces http:// for every registry. Registries on localhost
+	// and 127.0.0.1 always use plain HTTP.
+	PlainHTTP bool
+
+	mu     sync.Mutex
+	tokens map[string]string // registry/repository -> bearer token
+}
+
+// NewClient returns a client with default settings.
+func NewClient() *Client {
+	return &Client{HTTP: &http.Client{Timeout: defaultRequestTimeout}}
+}
+
+// Manifest fetches the manifest for reference (a tag or digest) and returns
+// it along with its content digest.
+func (c *Client) Manifest(ctx context.Context, registry, repository, reference string) (*Manifest, string, error) {
+	accept := strings.Join([]string{MediaTypeOCIManifest, MediaTypeDockerManifest, MediaTypeOCIIndex, MediaTypeDockerList}, ", ")
+	body, header, err := c.get(ctx, registry, repository, "manifests/"+reference, accept, maxManifestBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = Digest(body)
+	}
+	var m Manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, "", fmt.Errorf("decode manifest %s/%s:%s: %w", registry, repository, reference, err)
+	}
+	return &m, digest, nil
+}
+
+// Referrers lists the artifacts whose subject is digest, filtered to
+// artifactType when it is non-empty. Registries without the OCI 1.1
+// referrers API are queried through the "sha256-<hex>" fallback tag. A
+// digest with no referrers returns nil, nil.
+func (c *Client) Referrers(ctx context.Context, registry, repository, digest, artifactType string) ([]Descriptor, error) {
+	path := "referrers/" + digest
+	if artifactType != "" {
+		path += "?artifactType=" + url.QueryEscape(artifactType)
+	}
+	body, _, err := c.get(ctx, registry, repository, path, MediaTypeOCIIndex, maxManifestBytes)
+	if errors.Is(err, ErrNotFound) {
+		body, _, err = c.get(ctx, registry, repository, "manifests/"+ReferrersTag(digest), MediaTypeOCIIndex, maxManifestBytes)
+	}
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(body, &idx); err != nil {
+		return nil, fmt.Errorf("decode referrers of %s/%s@%s: %w", registry, repository, digest, err)
+	}
+	// The fallback tag is never filtered server-side, and registries may
+	// ignore the artifactType query.
+	out := idx.Manifests[:0]
+	for _, d := range idx.Manifests {
+		if artifactType == "" || d.ArtifactType == artifactType {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// Blob fetches a blob and verifies it against its sha256 digest.
+func (c *Client) Blob(ctx context.Context, registry, repository, digest string) ([]byte, error) {
+	body, _, err := c.get(ctx, registry, repository, "blobs/"+digest, "", maxBlobBytes)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(digest, "sha256:") && Digest(body) != digest {
+		return nil, fmt.Errorf("blob %s: digest mismatch", digest)
+	}
+	return body, nil
+}
+
+// Digest returns the sha256 digest of b in "sha256:<hex>" form.
+func Digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func (c *Client) scheme(registry string) string {
+	host := registry
+	if h, _, ok := strings.Cut(registry, ":"); ok {
+		host = h
+	}
+	if c.PlainHTTP || host == "localhost" || host == "127.0.0.1" {
+		return "http"
+	}
+	return "https"
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) get(ctx context.Context, registry, repository, path, accept string, limit int64) ([]byte, http.Header, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", c.scheme(registry), registry, repository, path)
+	tokenKey := registry + "/" + repository
+
+	do := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		c.mu.Lock()
+		token := c.tokens[tokenKey]
+		c.mu.Unlock()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else if c.Credentials != nil {
+			if user, pass := c.Credentials(registry); user != "" {
+				req.SetBasicAuth(user, pass)
+			}
+		}
+		return c.httpClient().Do(req)
+	}
+
+	resp, err := do()
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := c.authenticate(ctx, registry, tokenKey, repository, challenge); err != nil {
+			return nil, nil, err
+		}
+		if resp, err = do(); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, nil, fmt.Errorf("GET %s: response exceeds %d bytes", u, limit)
+	}
+	return body, resp.Header, nil
+}
+
+// authenticate runs the registry token flow described by a
+// `WWW-Authenticate: Bearer realm=...,service=...,scope=...` challenge.
+func (c *Client) authenticate(ctx context.Context, registry, tokenKey, repository, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("registry %s: unauthorized", registry)
+	}
+	attrs := parseChallenge(params)
+	realm := attrs["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry %s: bearer challenge without realm", registry)
+	}
+	q := url.Values{}
+	if svc := attrs["service"]; svc != "" {
+		q.Set("service", svc)
+	}
+	scope := attrs["scope"]
+	if scope == "" {
+		scope = "repository:" + repository + ":pull"
+	}
+	q.Set("scope", scope)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if c.Credentials != nil {
+		if user, pass := c.Credentials(registry); user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry %s token endpoint: %s", registry, resp.Status)
+	}
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestBytes)).Decode(&tok); err != nil {
+		return fmt.Errorf("registry %s token endpoint: %w", registry, err)
+	}
+	token := tok.Token
+	if token == "" {
+		token = tok.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("registry %s token endpoint returned no token", registry)
+	}
+	c.mu.Lock()
+	if c.tokens == nil {
+		c.tokens = make(map[string]string)
+	}
+	c.tokens[tokenKey] = token
+	c.mu.Unlock()
+	return nil
+}
+
+// parseChallenge parses comma-separated key="value" pairs. Values may
+// contain commas when quoted (e.g. multi-action scopes).
+func parseChallenge(s string) map[string]string {
+	out := make(map[string]string)
+	for s != "" {
+		s = strings.TrimLeft(s, " ,")
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, s = rest[1:], ""
+			} else {
+				value, s = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+		}
+		out[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package oci_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/oci/ocitest"
+)
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		in   string
+		want oci.Reference
+	}{
+		{"nginx", oci.Reference{Registry: "registry-1.docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{"docker.io/bitnami/redis:7", oci.Reference{Registry: "registry-1.docker.io", Repository: "bitnami/redis", Tag: "7"}},
+		{"ghcr.io/org/app:v1.2", oci.Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1.2"}},
+		{"localhost:5000/app@sha256:abc", oci.Reference{Registry: "localhost:5000", Repository: "app", Digest: "sha256:abc"}},
+		{"quay.io/org/app:v1@sha256:abc", oci.Reference{Registry: "quay.io", Repository: "org/app", Tag: "v1", Digest: "sha256:abc"}},
+	}
+	for _, tc := range cases {
+		got, err := oci.ParseReference(tc.in)
+		if err != nil {
+			t.Errorf("%s: %v", tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.in, got, tc.want)
+		}
+	}
+	if _, err := oci.ParseReference(""); err == nil {
+		t.Error("expected error for empty reference")
+	}
+}
+
+func TestClient_ManifestAndBlob(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	digest := reg.PushImage("org/app", "v1")
+
+	c := oci.NewClient()
+	m, got, err := c.Manifest(context.Background(), reg.Host(), "org/app", "v1")
+	if err != nil {
+		t.Fatalf("Manifest: %v", err)
+	}
+	if got != digest || len(m.Layers) != 1 {
+		t.Fatalf("unexpected manifest %s: %+v", got, m)
+	}
+	blob, err := c.Blob(context.Background(), reg.Host(), "org/app", m.Layers[0].Digest)
+	if err != nil || string(blob) != "org/app:v1" {
+		t.Errorf("Blob: %q, %v", blob, err)
+	}
+	if _, _, err := c.Manifest(context.Background(), reg.Host(), "org/app", oci.SignatureTag(digest)); !errors.Is(err, oci.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing signature tag, got %v", err)
+	}
+}
+
+func TestClient_Referrers(t *testing.T) {
+	for _, noAPI := range []bool{false, true} {
+		reg := ocitest.NewRegistry()
+		reg.NoReferrersAPI = noAPI
+		digest := reg.PushImage("org/app", "v1")
+		subject := &oci.Descriptor{MediaType: oci.MediaTypeOCIManifest, Digest: digest}
+		config := reg.PushBlob("application/vnd.oci.empty.v1+json", []byte(`{}`))
+		provenance := reg.PushManifest("org/app", "", oci.Manifest{ArtifactType: "application/vnd.dev.sigstore.bundle.v0.3+json", Config: config, Subject: subject})
+		reg.PushManifest("org/app", "", oci.Manifest{ArtifactType: "application/spdx+json", Config: config, Subject: subject})
+
+		c := oci.NewClient()
+		all, err := c.Referrers(context.Background(), reg.Host(), "org/app", digest, "")
+		if err != nil || len(all) != 2 {
+			t.Errorf("noAPI=%v: expected 2 referrers, got %+v, %v", noAPI, all, err)
+		}
+		bundles, err := c.Referrers(context.Background(), reg.Host(), "org/app", digest, "application/vnd.dev.sigstore.bundle.v0.3+json")
+		if err != nil || len(bundles) != 1 || bundles[0].Digest != provenance {
+			t.Errorf("noAPI=%v: expected the bundle referrer, got %+v, %v", noAPI, bundles, err)
+		}
+		none, err := c.Referrers(context.Background(), reg.Host(), "org/app", provenance, "")
+		if err != nil || len(none) != 0 {
+			t.Errorf("noAPI=%v: expected no referrers, got %+v, %v", noAPI, none, err)
+		}
+		reg.Close()
+	}
+}
+
+func TestClient_BearerTokenFlow(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:org/app:pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": "t0k"})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0k" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="test",scope="repository:org/app:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"schemaVersion":2,"layers":[]}`))
+	}))
+	defer srv.Close()
+
+	c := oci.NewClient()
+	host := strings.TrimPrefix(srv.URL, "http://")
+	if _, _, err := c.Manifest(context.Background(), host, "org/app", "v1"); err != nil {
+		t.Fatalf("Manifest with token flow: %v", err)
+	}
+}
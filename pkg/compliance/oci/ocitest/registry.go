@@ -0,0 +1,160 @@
+// Package ocitest provides an in-memory OCI registry for tests of code that
+// reads signatures and attestations through pkg/compliance/oci.
+package ocitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+)
+
+// Registry is an httptest-backed registry serving the read side of the
+// distribution API (GET manifests, blobs and referrers). Registries on
+// 127.0.0.1 are reached over plain HTTP by oci.Client, so no TLS setup is
+// needed.
+type Registry struct {
+	*httptest.Server
+
+	// NoReferrersAPI makes the registry behave like one predating OCI 1.1:
+	// the referrers endpoint 404s and manifests with a subject are indexed
+	// under the "sha256-<hex>" fallback tag instead.
+	NoReferrersAPI bool
+
+	mu        sync.Mutex
+	manifests map[string][]byte           // "repo@ref" -> manifest JSON; ref is a tag or digest
+	blobs     map[string][]byte           // digest -> content
+	referrers map[string][]oci.Descriptor // "repo@subject-digest" -> referring manifests
+	requests  int
+}
+
+// NewRegistry starts a registry. Call Close when done.
+func NewRegistry() *Registry {
+	r := &Registry{manifests: make(map[string][]byte), blobs: make(map[string][]byte), referrers: make(map[string][]oci.Descriptor)}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+// Host returns the registry host:port to use in image references.
+func (r *Registry) Host() string {
+	return strings.TrimPrefix(r.URL, "http://")
+}
+
+// Requests returns how many requests the registry has served.
+func (r *Registry) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+// PushBlob stores content and returns its descriptor.
+func (r *Registry) PushBlob(mediaType string, content []byte) oci.Descriptor {
+	digest := oci.Digest(content)
+	r.mu.Lock()
+	r.blobs[digest] = content
+	r.mu.Unlock()
+	return oci.Descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+}
+
+// PushManifest stores m under repository:tag (and under its digest) and
+// returns the manifest digest. An empty tag stores it by digest only. A
+// manifest with a Subject is listed as a referrer of that digest.
+func (r *Registry) PushManifest(repository, tag string, m oci.Manifest) string {
+	if m.SchemaVersion == 0 {
+		m.SchemaVersion = 2
+	}
+	if m.MediaType == "" {
+		m.MediaType = oci.MediaTypeOCIManifest
+	}
+	body, _ := json.Marshal(m)
+	digest := oci.Digest(body)
+	r.mu.Lock()
+	r.manifests[repository+"@"+digest] = body
+	if tag != "" {
+		r.manifests[repository+"@"+tag] = body
+	}
+	if m.Subject != nil {
+		artifactType := m.ArtifactType
+		if artifactType == "" {
+			artifactType = m.Config.MediaType
+		}
+		key := repository + "@" + m.Subject.Digest
+		r.referrers[key] = append(r.referrers[key], oci.Descriptor{
+			MediaType:    m.MediaType,
+			Digest:       digest,
+			Size:         int64(len(body)),
+			ArtifactType: artifactType,
+			Annotations:  m.Annotations,
+		})
+		if r.NoReferrersAPI {
+			index, _ := json.Marshal(oci.Index{SchemaVersion: 2, MediaType: oci.MediaTypeOCIIndex, Manifests: r.referrers[key]})
+			r.manifests[repository+"@"+oci.ReferrersTag(m.Subject.Digest)] = index
+		}
+	}
+	r.mu.Unlock()
+	return digest
+}
+
+// PushImage stores a minimal single-layer image under repository:tag and
+// returns its manifest digest.
+func (r *Registry) PushImage(repository, tag string) string {
+	config := r.PushBlob("application/vnd.oci.image.config.v1+json", []byte(`{"architecture":"amd64","os":"linux"}`))
+	layer := r.PushBlob("application/vnd.oci.image.layer.v1.tar", []byte(repository+":"+tag))
+	return r.PushManifest(repository, tag, oci.Manifest{Config: config, Layers: []oci.Descriptor{layer}})
+}
+
+func (r *Registry) serve(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.requests++
+	r.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if req.Method != http.MethodGet || path == req.URL.Path {
+		http.NotFound(w, req)
+		return
+	}
+	if i := strings.LastIndex(path, "/manifests/"); i >= 0 {
+		repo, ref := path[:i], path[i+len("/manifests/"):]
+		r.mu.Lock()
+		body, ok := r.manifests[repo+"@"+ref]
+		r.mu.Unlock()
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", oci.MediaTypeOCIManifest)
+		w.Header().Set("Docker-Content-Digest", oci.Digest(body))
+		w.Write(body)
+		return
+	}
+	if i := strings.LastIndex(path, "/referrers/"); i >= 0 && !r.NoReferrersAPI {
+		repo, digest := path[:i], path[i+len("/referrers/"):]
+		artifactType := req.URL.Query().Get("artifactType")
+		r.mu.Lock()
+		manifests := []oci.Descriptor{}
+		for _, d := range r.referrers[repo+"@"+digest] {
+			if artifactType == "" || d.ArtifactType == artifactType {
+				manifests = append(manifests, d)
+			}
+		}
+		r.mu.Unlock()
+		w.Header().Set("Content-Type", oci.MediaTypeOCIIndex)
+		json.NewEncoder(w).Encode(oci.Index{SchemaVersion: 2, MediaType: oci.MediaTypeOCIIndex, Manifests: manifests})
+		return
+	}
+	if i := strings.LastIndex(path, "/blobs/"); i >= 0 {
+		r.mu.Lock()
+		body, ok := r.blobs[path[i+len("/blobs/"):]]
+		r.mu.Unlock()
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write(body)
+		return
+	}
+	http.NotFound(w, req)
+}
@@ -0,0 +1,104 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// dockerHubRegistry is where references without a registry host resolve.
+	dockerHubRegistry = "registry-1.docker.io"
+	// dockerHubLibrary prefixes single-component Docker Hub repositories.
+	dockerHubLibrary = "library/"
+)
+
+// Reference is a parsed image reference.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image reference such as "nginx",
+// "ghcr.io/org/app:v1" or "quay.io/org/app@sha256:...". Docker Hub short
+// names are expanded the way the container runtime does.
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	if image == "" {
+		return ref, fmt.Errorf("empty image reference")
+	}
+	rest := image
+	if name, digest, ok := strings.Cut(rest, "@"); ok {
+		if !strings.Contains(digest, ":") {
+			return ref, fmt.Errorf("invalid digest in %q", image)
+		}
+		ref.Digest = digest
+		rest = name
+	}
+	// A tag is the part after the last colon that follows the last slash;
+	// an earlier colon belongs to a registry port.
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	first, remainder, hasSlash := strings.Cut(rest, "/")
+	if hasSlash && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		ref.Repository = remainder
+	} else {
+		ref.Registry = dockerHubRegistry
+		ref.Repository = rest
+	}
+	if ref.Registry == "docker.io" || ref.Registry == "index.docker.io" {
+		ref.Registry = dockerHubRegistry
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = dockerHubLibrary + ref.Repository
+	}
+	if ref.Repository == "" {
+		return ref, fmt.Errorf("missing repository in %q", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// Name returns registry/repository without tag or digest.
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// String renders the reference, preferring the digest over the tag.
+func (r Reference) String() string {
+	if r.Digest != "" {
+		return r.Name() + "@" + r.Digest
+	}
+	return r.Name() + ":" + r.Tag
+}
+
+// SignatureTag is the tag cosign stores an image's signatures under:
+// "sha256-<hex>.sig".
+func SignatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+// AttestationTag is the tag cosign stores in-toto attestations under:
+// "sha256-<hex>.att".
+func AttestationTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".att"
+}
+
+// SBOMTag is the tag `cosign attach sbom` stores SBOMs under:
+// "sha256-<hex>.sbom".
+func SBOMTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sbom"
+}
+
+// ReferrersTag is the OCI 1.1 referrers fallback tag: "sha256-<hex>",
+// holding an index of the artifacts that refer to digest.
+func ReferrersTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
@@ -0,0 +1,145 @@
+package sbom
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+)
+
+// In-toto predicate types cosign uses for SBOM attestations
+// (`cosign attest --type spdxjson|cyclonedx`).
+var sbomPredicateTypes = map[string]bool{
+	"https://spdx.dev/Document":  true,
+	"https://cyclonedx.org/bom":  true,
+	"https://cyclonedx.org/bom/": true,
+}
+
+// Layer media types `cosign attach sbom` uses.
+var attachedSBOMMediaTypes = map[string]bool{
+	"text/spdx+json":                 true,
+	"application/spdx+json":          true,
+	"application/vnd.cyclonedx+json": true,
+}
+
+// dsseEnvelope is a DSSE envelope as stored in a cosign .att layer.
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// inTotoStatement is an in-toto v0.1/v1 statement.
+type inTotoStatement struct {
+	Subject []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// fetchSBOM returns the first SBOM found for ref's digest: an in-toto SBOM
+// attestation (".att" tag) or an attached SBOM (".sbom" tag). It returns
+// nil, nil when the image has none.
+func fetchSBOM(ctx context.Context, client *oci.Client, ref oci.Reference) (*parsedSBOM, error) {
+	doc, err := fetchAttestedSBOM(ctx, client, ref)
+	if doc != nil || err != nil {
+		return doc, err
+	}
+	return fetchAttachedSBOM(ctx, client, ref)
+}
+
+func fetchAttestedSBOM(ctx context.Context, client *oci.Client, ref oci.Reference) (*parsedSBOM, error) {
+	manifest, _, err := client.Manifest(ctx, ref.Registry, ref.Repository, oci.AttestationTag(ref.Digest))
+	if errors.Is(err, oci.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range manifest.Layers {
+		// cosign annotates each layer with its predicate type, which lets us
+		// skip provenance and other attestations without downloading them.
+		if pt := layer.Annotations["predicateType"]; pt != "" && !sbomPredicateTypes[pt] {
+			continue
+		}
+		blob, err := client.Blob(ctx, ref.Registry, ref.Repository, layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		stmt, err := decodeStatement(blob)
+		if err != nil || !sbomPredicateTypes[stmt.PredicateType] {
+			continue
+		}
+		if !statementCovers(stmt, ref.Digest) {
+			continue
+		}
+		doc, err := parseDocument(stmt.Predicate)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s attestation: %w", stmt.PredicateType, err)
+		}
+		return doc, nil
+	}
+	return nil, nil
+}
+
+func fetchAttachedSBOM(ctx context.Context, client *oci.Client, ref oci.Reference) (*parsedSBOM, error) {
+	manifest, _, err := client.Manifest(ctx, ref.Registry, ref.Repository, oci.SBOMTag(ref.Digest))
+	if errors.Is(err, oci.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range manifest.Layers {
+		if !attachedSBOMMediaTypes[layer.MediaType] {
+			continue
+		}
+		blob, err := client.Blob(ctx, ref.Registry, ref.Repository, layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := parseDocument(blob)
+		if err != nil {
+			return nil, fmt.Errorf("parse attached SBOM: %w", err)
+		}
+		return doc, nil
+	}
+	return nil, nil
+}
+
+// decodeStatement unwraps a DSSE envelope, or accepts a bare statement.
+func decodeStatement(blob []byte) (*inTotoStatement, error) {
+	var env dsseEnvelope
+	if err := json.Unmarshal(blob, &env); err != nil {
+		return nil, err
+	}
+	payload := blob
+	if env.Payload != "" {
+		decoded, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = decoded
+	}
+	var stmt inTotoStatement
+	if err := json.Unmarshal(payload, &stmt); err != nil {
+		return nil, err
+	}
+	return &stmt, nil
+}
+
+// statementCovers reports whether the statement's subject is digest.
+func statementCovers(stmt *inTotoStatement, digest string) bool {
+	algo, hex, _ := strings.Cut(digest, ":")
+	for _, s := range stmt.Subject {
+		if s.Digest[algo] == hex {
+			return true
+		}
+	}
+	return false
+}
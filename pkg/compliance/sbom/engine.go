@@ -1,17 +1,39 @@
 package sbom
 
-import "time"
+import (
+	"sync"
+	"time"
 
-// Engine provides SBOM data for the supply chain dashboard.
-//
-// TODO (#9644): Replace stub with live Syft/Grype integration.
-type Engine struct{}
+	"github.com/kubestellar/console/pkg/compliance/oci"
+)
 
-// NewEngine creates an SBOM engine. Currently returns demo data.
+// Engine provides SBOM data for the supply chain dashboard. An engine built
+// with NewEngine serves demo data; one built with NewLiveEngine reads SBOMs
+// of running images from their registries (see live.go).
+type Engine struct {
+	inventory Inventory
+	registry  *oci.Client
+	opts      LiveOptions
+
+	mu    sync.Mutex
+	vulns *VulnDB
+	cache map[string]cachedSBOM // "registry/repo@digest"
+	last  *Report
+	now   func() time.Time
+}
+
+// NewEngine creates an SBOM engine that serves demo data.
 func NewEngine() *Engine { return &Engine{} }
 
-// Summary returns fleet-wide SBOM coverage and vulnerability metrics.
+// Summary returns fleet-wide SBOM coverage and vulnerability metrics. A
+// live engine returns the result of its most recent Evaluate.
 func (e *Engine) Summary() Summary {
+	if e.IsLive() {
+		if r := e.lastReport(); r != nil {
+			return r.Summary
+		}
+		return Summary{Mode: ModeLive}
+	}
 	return Summary{
 		TotalWorkloads:       42,
 		SBOMCoverage:         88,
@@ -20,12 +42,20 @@ func (e *Engine) Summary() Summary {
 		CriticalCount:        2,
 		HighCount:            5,
 		GeneratedAt:          time.Now(),
+		Mode:                 ModeDemo,
 	}
 }
 
 // Documents returns available SBOM documents across the fleet.
 func (e *Engine) Documents() []Document {
-	// TODO (#9644): Query OCI registry annotations and Kubernetes API for
-	// running workloads, generate SBOMs via Syft, scan with Grype.
+	if r := e.lastReport(); r != nil && r.Documents != nil {
+		return r.Documents
+	}
 	return []Document{}
 }
+
+func (e *Engine) lastReport() *Report {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last
+}
@@ -0,0 +1,14 @@
+package sbom
+
+import (
+	"context"
+
+	"github.com/kubestellar/console/pkg/compliance/signing"
+)
+
+// Inventory lists the images running on a cluster. It is the subset of
+// signing.Inventory the SBOM engine needs, so the console passes the same
+// implementation to both engines.
+type Inventory interface {
+	RunningImages(ctx context.Context, cluster string) ([]signing.RunningImage, error)
+}
@@ -0,0 +1,303 @@
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/safego"
+)
+
+// Engine modes reported in Summary.Mode and Report.Mode.
+const (
+	ModeDemo = "demo"
+	ModeLive = "live"
+)
+
+const (
+	// sbomCacheTTL is how long a digest's SBOM lookup is reused. Digests
+	// are immutable, so only newly attached SBOMs change the result.
+	sbomCacheTTL = 30 * time.Minute
+	// fetchConcurrency bounds parallel registry lookups.
+	fetchConcurrency = 8
+)
+
+// LiveOptions configures a live SBOM engine.
+type LiveOptions struct {
+	// FeedDir holds the offline OSV feed (*.json records or the
+	// per-ecosystem all.zip exports). Empty disables vulnerability
+	// correlation.
+	FeedDir string
+}
+
+// Report is a fleet SBOM evaluation.
+type Report struct {
+	Mode      string     `json:"mode"`
+	Clusters  []string   `json:"clusters"`
+	Documents []Document `json:"documents"`
+	Summary   Summary    `json:"summary"`
+	// FeedRecords is the number of OSV records components were matched
+	// against.
+	FeedRecords int               `json:"feed_records"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+type cachedSBOM struct {
+	doc *parsedSBOM // nil when the image has no SBOM
+	at  time.Time
+}
+
+// sbomLookup is the registry lookup result for one image digest.
+type sbomLookup struct {
+	doc *parsedSBOM
+	err error
+}
+
+// NewLiveEngine creates an SBOM engine that reads SBOMs of the images
+// running on each cluster from their registries and correlates components
+// with the OSV feed in opts.FeedDir.
+func NewLiveEngine(inventory Inventory, registry *oci.Client, opts LiveOptions) (*Engine, error) {
+	e := &Engine{
+		inventory: inventory,
+		registry:  registry,
+		opts:      opts,
+		cache:     make(map[string]cachedSBOM),
+		now:       time.Now,
+	}
+	if opts.FeedDir != "" {
+		db, err := LoadOSVFeed(opts.FeedDir)
+		if err != nil {
+			return nil, fmt.Errorf("load OSV feed: %w", err)
+		}
+		e.vulns = db
+	}
+	return e, nil
+}
+
+// IsLive reports whether the engine reads real workloads.
+func (e *Engine) IsLive() bool {
+	return e.inventory != nil
+}
+
+// Invalidate drops cached SBOMs and reloads the OSV feed, so an updated
+// feed dropped on disk is picked up without a restart. A feed that fails
+// to load leaves the previous one in place.
+func (e *Engine) Invalidate() {
+	var db *VulnDB
+	if e.opts.FeedDir != "" {
+		var err error
+		if db, err = LoadOSVFeed(e.opts.FeedDir); err != nil {
+			slog.Error("[SBOM] failed to reload OSV feed", "dir", e.opts.FeedDir, "error", err)
+		}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cache = make(map[string]cachedSBOM)
+	if db != nil {
+		e.vulns = db
+	}
+}
+
+// Evaluate collects the SBOMs of every running image on the given clusters.
+// Clusters whose inventory cannot be read are listed in Report.Errors; it
+// only fails when none could be read. The result also backs Summary and
+// Documents until the next evaluation.
+func (e *Engine) Evaluate(ctx context.Context, clusters []string) (*Report, error) {
+	if !e.IsLive() {
+		return &Report{Mode: ModeDemo, Documents: e.Documents(), Summary: e.Summary()}, nil
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no clusters to evaluate")
+	}
+
+	report := &Report{Mode: ModeLive, Documents: []Document{}}
+	failures := make(map[string]string)
+	workloads := make(map[string]bool)
+	var candidates []Document
+	for _, cluster := range clusters {
+		running, err := e.inventory.RunningImages(ctx, cluster)
+		if err != nil {
+			failures[cluster] = err.Error()
+			continue
+		}
+		report.Clusters = append(report.Clusters, cluster)
+		for _, ri := range running {
+			workloads[cluster+"/"+ri.Namespace+"/"+ri.Workload] = true
+			candidates = append(candidates, Document{
+				Workload:  ri.Workload,
+				Namespace: ri.Namespace,
+				Cluster:   cluster,
+				Image:     ri.Image,
+				Digest:    ri.Digest,
+			})
+		}
+	}
+	if len(report.Clusters) == 0 {
+		return nil, fmt.Errorf("SBOM evaluation failed on all %d cluster(s)", len(clusters))
+	}
+
+	e.mu.Lock()
+	vulns := e.vulns
+	e.mu.Unlock()
+	report.FeedRecords = vulns.Records()
+
+	lookups := e.fetchAll(ctx, candidates)
+	covered := make(map[string]bool)
+	findings := make(map[string]string)
+	for i, doc := range candidates {
+		res := lookups[i]
+		if res.err != nil {
+			failures[doc.Cluster+"/"+doc.Namespace+"/"+doc.Workload] = fmt.Sprintf("%s: %v", doc.Image, res.err)
+			continue
+		}
+		if res.doc == nil {
+			continue
+		}
+		covered[doc.Cluster+"/"+doc.Namespace+"/"+doc.Workload] = true
+		report.Documents = append(report.Documents, buildDocument(doc, res.doc, vulns, e.now(), findings))
+	}
+	if len(failures) > 0 {
+		report.Errors = failures
+	}
+
+	sort.Slice(report.Documents, func(i, j int) bool {
+		a, b := report.Documents[i], report.Documents[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload+a.Image < b.Workload+b.Image
+	})
+	report.Summary = summarize(report.Documents, findings, len(workloads), len(covered))
+
+	e.mu.Lock()
+	e.last = report
+	e.mu.Unlock()
+	return report, nil
+}
+
+// fetchAll looks up the SBOM of each candidate, fetching each distinct
+// repository digest once. Images without a resolved digest have no SBOM.
+func (e *Engine) fetchAll(ctx context.Context, candidates []Document) []sbomLookup {
+	type target struct {
+		ref oci.Reference
+		key string
+	}
+	results := make([]sbomLookup, len(candidates))
+	byKey := make(map[string][]int)
+	var targets []target
+	for i, doc := range candidates {
+		if doc.Digest == "" {
+			continue
+		}
+		ref, err := oci.ParseReference(doc.Image)
+		if err != nil {
+			results[i].err = fmt.Errorf("invalid image reference: %w", err)
+			continue
+		}
+		ref.Digest = doc.Digest
+		key := ref.Name() + "@" + doc.Digest
+		if _, ok := byKey[key]; !ok {
+			targets = append(targets, target{ref: ref, key: key})
+		}
+		byKey[key] = append(byKey[key], i)
+	}
+
+	fetched := make([]sbomLookup, len(targets))
+	sem := make(chan struct{}, fetchConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		safego.GoWith("sbom/fetch", func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fetched[i] = e.fetch(ctx, t.ref, t.key)
+		})
+	}
+	wg.Wait()
+
+	for i, t := range targets {
+		for _, idx := range byKey[t.key] {
+			results[idx] = fetched[i]
+		}
+	}
+	return results
+}
+
+func (e *Engine) fetch(ctx context.Context, ref oci.Reference, key string) sbomLookup {
+	e.mu.Lock()
+	cached, ok := e.cache[key]
+	e.mu.Unlock()
+	if ok && e.now().Sub(cached.at) < sbomCacheTTL {
+		return sbomLookup{doc: cached.doc}
+	}
+	doc, err := fetchSBOM(ctx, e.registry, ref)
+	if err != nil {
+		// Registry outages are not cached so the next evaluation retries.
+		return sbomLookup{err: err}
+	}
+	e.mu.Lock()
+	e.cache[key] = cachedSBOM{doc: doc, at: e.now()}
+	e.mu.Unlock()
+	return sbomLookup{doc: doc}
+}
+
+// buildDocument copies the parsed components into a workload document and
+// annotates them with matching vulnerabilities, recording each finding's
+// severity in findings keyed "id|purl". The cached parse is never modified.
+func buildDocument(doc Document, parsed *parsedSBOM, vulns *VulnDB, now time.Time, findings map[string]string) Document {
+	doc.ID = doc.Cluster + "/" + doc.Namespace + "/" + doc.Workload + "@" + doc.Digest
+	doc.Format = parsed.Format
+	doc.GeneratedAt = parsed.Created
+	if doc.GeneratedAt.IsZero() {
+		doc.GeneratedAt = now
+	}
+	doc.Components = make([]Component, len(parsed.Components))
+	for i, c := range parsed.Components {
+		c.Severity = SeverityNone
+		for _, f := range vulns.Match(c.PURL, c.Version) {
+			c.VulnerabilityIDs = append(c.VulnerabilityIDs, f.ID)
+			findings[f.ID+"|"+c.PURL] = f.Severity
+			if severityRank[f.Severity] > severityRank[c.Severity] {
+				c.Severity = f.Severity
+			}
+		}
+		c.Vulnerabilities = len(c.VulnerabilityIDs)
+		if c.Vulnerabilities > 0 {
+			doc.VulnerableCount++
+		}
+		doc.Components[i] = c
+	}
+	doc.ComponentCount = len(doc.Components)
+	return doc
+}
+
+// summarize aggregates documents. Critical and high counts are distinct
+// (vulnerability, package) findings, so a library shared by many
+// workloads counts once.
+func summarize(docs []Document, findings map[string]string, workloads, covered int) Summary {
+	s := Summary{TotalWorkloads: workloads, GeneratedAt: time.Now(), Mode: ModeLive}
+	if workloads > 0 {
+		s.SBOMCoverage = covered * 100 / workloads
+	}
+	for _, d := range docs {
+		s.TotalComponents += d.ComponentCount
+		s.VulnerableComponents += d.VulnerableCount
+	}
+	for _, sev := range findings {
+		switch sev {
+		case SeverityCritical:
+			s.CriticalCount++
+		case SeverityHigh:
+			s.HighCount++
+		}
+	}
+	return s
+}
@@ -0,0 +1,213 @@
+package sbom
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/oci/ocitest"
+	"github.com/kubestellar/console/pkg/compliance/signing"
+)
+
+type stubInventory map[string][]signing.RunningImage
+
+func (s stubInventory) RunningImages(_ context.Context, cluster string) ([]signing.RunningImage, error) {
+	return s[cluster], nil
+}
+
+const testSPDX = `{
+  "spdxVersion": "SPDX-2.3",
+  "creationInfo": {"created": "2026-01-02T03:04:05Z"},
+  "documentDescribes": ["SPDXRef-image"],
+  "packages": [
+    {"SPDXID": "SPDXRef-image", "name": "api"},
+    {"SPDXID": "SPDXRef-lodash", "name": "lodash", "versionInfo": "4.17.20", "licenseConcluded": "MIT",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/lodash@4.17.20"}]},
+    {"SPDXID": "SPDXRef-openssl", "name": "openssl", "versionInfo": "3.0.11-1~deb12u1", "licenseDeclared": "Apache-2.0",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:deb/debian/openssl@3.0.11-1~deb12u1?distro=debian-12"}]}
+  ]
+}`
+
+const testCycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "components": [
+    {"type": "operating-system", "name": "alpine", "version": "3.19"},
+    {"type": "library", "name": "requests", "version": "2.31.0", "purl": "pkg:pypi/requests@2.31.0",
+     "licenses": [{"license": {"id": "Apache-2.0"}}],
+     "components": [{"type": "library", "name": "urllib3", "version": "1.26.5", "purl": "pkg:pypi/urllib3@1.26.5"}]}
+  ]
+}`
+
+var testFeed = map[string]string{
+	"npm/GHSA-lodash.json": `{"id": "GHSA-lodash", "database_specific": {"severity": "HIGH"},
+	  "affected": [{"package": {"ecosystem": "npm", "name": "lodash"},
+	    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]}]}`,
+	"debian/DSA-openssl.json": `{"id": "DSA-openssl",
+	  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+	  "affected": [{"package": {"ecosystem": "Debian:12", "name": "openssl"},
+	    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.13-1~deb12u1"}]}]}]}`,
+	"pypi/PYSEC-urllib3.json": `[{"id": "PYSEC-urllib3", "database_specific": {"severity": "MODERATE"},
+	  "affected": [{"package": {"ecosystem": "PyPI", "name": "urllib3"}, "versions": ["1.26.4", "1.26.5"]}]}]`,
+}
+
+func writeFeed(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range testFeed {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// pushAttestation stores an SPDX attestation for repo@digest as cosign
+// attest does: a DSSE envelope in the ".att" tag.
+func pushAttestation(t *testing.T, reg *ocitest.Registry, repo, digest, predicateType, predicate string) {
+	t.Helper()
+	_, hex, _ := strings.Cut(digest, ":")
+	stmt := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"` + predicateType +
+		`","subject":[{"name":"` + reg.Host() + "/" + repo + `","digest":{"sha256":"` + hex + `"}}],"predicate":` + predicate + `}`
+	env := `{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString([]byte(stmt)) + `","signatures":[]}`
+	layer := reg.PushBlob("application/vnd.dsse.envelope.v1+json", []byte(env))
+	layer.Annotations = map[string]string{"predicateType": predicateType}
+	config := reg.PushBlob("application/vnd.oci.image.config.v1+json", []byte(`{}`))
+	reg.PushManifest(repo, oci.AttestationTag(digest), oci.Manifest{Config: config, Layers: []oci.Descriptor{layer}})
+}
+
+func TestLiveEngine_Evaluate(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+
+	apiDigest := reg.PushImage("org/api", "v1")
+	pushAttestation(t, reg, "org/api", apiDigest, "https://spdx.dev/Document", testSPDX)
+
+	workerDigest := reg.PushImage("org/worker", "v1")
+	sbomLayer := reg.PushBlob("application/vnd.cyclonedx+json", []byte(testCycloneDX))
+	config := reg.PushBlob("application/vnd.oci.image.config.v1+json", []byte(`{}`))
+	reg.PushManifest("org/worker", oci.SBOMTag(workerDigest), oci.Manifest{Config: config, Layers: []oci.Descriptor{sbomLayer}})
+
+	bareDigest := reg.PushImage("org/bare", "v1")
+
+	inv := stubInventory{"c1": {
+		{Image: reg.Host() + "/org/api:v1", Digest: apiDigest, Workload: "api", Namespace: "shop"},
+		{Image: reg.Host() + "/org/worker:v1", Digest: workerDigest, Workload: "worker", Namespace: "shop"},
+		{Image: reg.Host() + "/org/bare:v1", Digest: bareDigest, Workload: "bare", Namespace: "shop"},
+		{Image: reg.Host() + "/org/api:v1", Workload: "pending", Namespace: "shop"},
+	}}
+	e, err := NewLiveEngine(inv, oci.NewClient(), LiveOptions{FeedDir: writeFeed(t)})
+	if err != nil {
+		t.Fatalf("NewLiveEngine: %v", err)
+	}
+	report, err := e.Evaluate(context.Background(), []string{"c1"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if report.FeedRecords != 3 {
+		t.Errorf("FeedRecords = %d, want 3", report.FeedRecords)
+	}
+	if len(report.Documents) != 2 {
+		t.Fatalf("expected 2 documents, got %d: %+v", len(report.Documents), report.Documents)
+	}
+
+	api, worker := report.Documents[0], report.Documents[1]
+	if api.Workload != "api" || api.Format != FormatSPDX || api.ComponentCount != 2 || api.VulnerableCount != 2 {
+		t.Errorf("unexpected api document: %+v", api)
+	}
+	if api.GeneratedAt.Year() != 2026 {
+		t.Errorf("expected SPDX creation time, got %v", api.GeneratedAt)
+	}
+	for _, c := range api.Components {
+		switch c.Name {
+		case "lodash":
+			if c.Severity != SeverityHigh || c.License != "MIT" || len(c.VulnerabilityIDs) != 1 {
+				t.Errorf("unexpected lodash: %+v", c)
+			}
+		case "openssl":
+			if c.Severity != SeverityCritical {
+				t.Errorf("expected CVSS 9.8 to be critical, got %+v", c)
+			}
+		}
+	}
+	if worker.Format != FormatCycloneDX || worker.ComponentCount != 2 || worker.VulnerableCount != 1 {
+		t.Errorf("unexpected worker document: %+v", worker)
+	}
+
+	s := e.Summary()
+	if s.Mode != ModeLive || s.TotalWorkloads != 4 || s.SBOMCoverage != 50 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.TotalComponents != 4 || s.VulnerableComponents != 3 || s.CriticalCount != 1 || s.HighCount != 1 {
+		t.Errorf("unexpected vulnerability counts: %+v", s)
+	}
+	if len(e.Documents()) != 2 {
+		t.Errorf("Documents should return the last report")
+	}
+
+	// Lookups, including the image without an SBOM, are cached per digest.
+	before := reg.Requests()
+	if _, err := e.Evaluate(context.Background(), []string{"c1"}); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Requests() != before {
+		t.Errorf("expected cached SBOMs, registry saw %d new requests", reg.Requests()-before)
+	}
+	e.Invalidate()
+	if _, err := e.Evaluate(context.Background(), []string{"c1"}); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Requests() == before {
+		t.Error("expected Invalidate to refetch SBOMs")
+	}
+}
+
+func TestFetchSBOM_SkipsForeignAttestations(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+
+	digest := reg.PushImage("org/api", "v1")
+	other := reg.PushImage("org/api", "v2")
+	client := oci.NewClient()
+
+	// An SBOM attestation stored under v2's tag but whose subject is v1.
+	pushAttestation(t, reg, "org/api", digest, "https://spdx.dev/Document", testSPDX)
+	att, _, err := client.Manifest(context.Background(), reg.Host(), "org/api", oci.AttestationTag(digest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.PushManifest("org/api", oci.AttestationTag(other), *att)
+
+	doc, err := fetchSBOM(context.Background(), client, oci.Reference{Registry: reg.Host(), Repository: "org/api", Digest: other})
+	if err != nil || doc != nil {
+		t.Errorf("expected attestation for another digest to be ignored, got %+v, %v", doc, err)
+	}
+
+	// Provenance attestations are skipped by their layer annotation.
+	provenance := reg.PushBlob("application/vnd.dsse.envelope.v1+json", []byte(`{}`))
+	provenance.Annotations = map[string]string{"predicateType": "https://slsa.dev/provenance/v1"}
+	config := reg.PushBlob("application/vnd.oci.image.config.v1+json", []byte(`{}`))
+	reg.PushManifest("org/api", oci.AttestationTag(other), oci.Manifest{Config: config, Layers: []oci.Descriptor{provenance}})
+	doc, err = fetchSBOM(context.Background(), client, oci.Reference{Registry: reg.Host(), Repository: "org/api", Digest: other})
+	if err != nil || doc != nil {
+		t.Errorf("expected provenance-only attestation to yield no SBOM, got %+v, %v", doc, err)
+	}
+}
+
+func TestDemoEngineMode(t *testing.T) {
+	e := NewEngine()
+	if e.IsLive() {
+		t.Fatal("demo engine reports live")
+	}
+	if s := e.Summary(); s.Mode != ModeDemo {
+		t.Errorf("Mode = %q, want %q", s.Mode, ModeDemo)
+	}
+}
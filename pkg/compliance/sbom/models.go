@@ -1,11 +1,13 @@
 // Package sbom implements SBOM (Software Bill of Materials) management
 // for SPDX and CycloneDX formats across the Kubernetes fleet.
 //
-// TODO (#9644): Full implementation — integrate with:
-//   - Syft for SBOM generation from running container images
-//   - Grype for vulnerability scanning against generated SBOMs
-//   - OCI registry annotations for pre-built SBOM retrieval
-//   - Kubernetes admission webhook for SBOM policy enforcement
+// The live engine discovers SBOMs that were attached to running image
+// digests at build time (cosign SBOM attestations or "sha256-<hex>.sbom"
+// tags) and matches their components against an offline OSV feed loaded
+// from disk, so it works on air-gapped sites.
+//
+// TODO (#9644): Generate SBOMs for images that ship without one, and
+// enforce SBOM policy through an admission webhook.
 package sbom
 
 import "time"
@@ -16,6 +18,8 @@ type Document struct {
 	Workload       string      `json:"workload"`
 	Namespace      string      `json:"namespace"`
 	Cluster        string      `json:"cluster"`
+	Image          string      `json:"image,omitempty"`
+	Digest         string      `json:"digest,omitempty"`
 	Format         string      `json:"format"` // "SPDX" or "CycloneDX"
 	GeneratedAt    time.Time   `json:"generated_at"`
 	ComponentCount int         `json:"component_count"`
@@ -31,6 +35,7 @@ type Component struct {
 	License         string `json:"license"`
 	Vulnerabilities int    `json:"vulnerabilities"`
 	Severity        string `json:"severity"` // none, low, medium, high, critical
+	VulnerabilityIDs []string `json:"vulnerability_ids,omitempty"`
 }
 
 // Summary aggregates SBOM coverage and vulnerability metrics fleet-wide.
@@ -42,4 +47,5 @@ type Summary struct {
 	CriticalCount       int       `json:"critical_count"`
 	HighCount           int       `json:"high_count"`
 	GeneratedAt         time.Time `json:"generated_at"`
+	Mode                string    `json:"mode"` // demo or live
 }
@@ -0,0 +1,576 @@
+package sbom
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Component severities, ordered by severityRank.
+const (
+	SeverityNone     = "none"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+var severityRank = map[string]int{
+	SeverityNone:     0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// maxOSVRecordBytes caps a single record read from a zip dump.
+const maxOSVRecordBytes = 16 << 20
+
+// purlEcosystems maps package-URL types to OSV ecosystems. Distro types are
+// keyed "type/namespace" because the namespace names the distribution.
+var purlEcosystems = map[string]string{
+	"npm":           "npm",
+	"pypi":          "PyPI",
+	"golang":        "Go",
+	"maven":         "Maven",
+	"cargo":         "crates.io",
+	"gem":           "RubyGems",
+	"nuget":         "NuGet",
+	"composer":      "Packagist",
+	"hex":           "Hex",
+	"pub":           "Pub",
+	"deb/debian":    "Debian",
+	"deb/ubuntu":    "Ubuntu",
+	"apk/alpine":    "Alpine",
+	"rpm/almalinux": "AlmaLinux",
+	"rpm/rocky":     "Rocky Linux",
+}
+
+// osvRecord is the subset of the OSV schema the matcher uses.
+type osvRecord struct {
+	ID       string `json:"id"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions          []string `json:"versions"`
+		EcosystemSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// osvRange is one affected version range.
+type osvRange struct {
+	semver bool
+	events []map[string]string
+}
+
+// osvAffected is one (vulnerability, package) entry of the index.
+type osvAffected struct {
+	id       string
+	severity string
+	versions map[string]bool
+	ranges   []osvRange
+}
+
+// VulnDB is an in-memory index of OSV records keyed by ecosystem and
+// package name. It is built from offline dumps so air-gapped sites can
+// correlate SBOM components without network access.
+type VulnDB struct {
+	index   map[string][]*osvAffected
+	records int
+}
+
+// Records returns how many OSV records were loaded.
+func (db *VulnDB) Records() int {
+	if db == nil {
+		return 0
+	}
+	return db.records
+}
+
+// LoadOSVFeed loads every OSV record under dir: *.json files holding one
+// record or an array of records, and *.zip archives of such files (the
+// format of the per-ecosystem all.zip exports).
+func LoadOSVFeed(dir string) (*VulnDB, error) {
+	db := &VulnDB{index: make(map[string][]*osvAffected)}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return db.addJSON(data, path)
+		case ".zip":
+			return db.addZip(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *VulnDB) addZip(path string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxOSVRecordBytes))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := db.addJSON(data, path+":"+f.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *VulnDB) addJSON(data []byte, source string) error {
+	trimmed := strings.TrimSpace(string(data))
+	var records []osvRecord
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	} else {
+		var r osvRecord
+		if err := json.Unmarshal(data, &r); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		records = []osvRecord{r}
+	}
+	for i := range records {
+		db.add(&records[i])
+	}
+	return nil
+}
+
+func (db *VulnDB) add(r *osvRecord) {
+	if r.ID == "" {
+		return
+	}
+	db.records++
+	base := recordSeverity(r)
+	for _, a := range r.Affected {
+		entry := &osvAffected{id: r.ID, severity: base}
+		if s := normalizeSeverity(a.EcosystemSpecific.Severity); severityRank[s] > severityRank[entry.severity] {
+			entry.severity = s
+		}
+		if len(a.Versions) > 0 {
+			entry.versions = make(map[string]bool, len(a.Versions))
+			for _, v := range a.Versions {
+				entry.versions[v] = true
+			}
+		}
+		for _, rg := range a.Ranges {
+			// GIT ranges name commits, which SBOMs do not record.
+			if rg.Type == "GIT" {
+				continue
+			}
+			entry.ranges = append(entry.ranges, osvRange{semver: rg.Type == "SEMVER", events: rg.Events})
+		}
+		key := indexKey(a.Package.Ecosystem, a.Package.Name)
+		db.index[key] = append(db.index[key], entry)
+	}
+}
+
+// recordSeverity derives a severity from CVSS v3 vectors or, failing that,
+// the database-specific rating.
+func recordSeverity(r *osvRecord) string {
+	best := SeverityNone
+	for _, s := range r.Severity {
+		var sev string
+		if strings.HasPrefix(s.Score, "CVSS:3") {
+			if score, ok := cvss3BaseScore(s.Score); ok {
+				sev = cvssSeverity(score)
+			}
+		} else {
+			sev = normalizeSeverity(s.Score)
+		}
+		if severityRank[sev] > severityRank[best] {
+			best = sev
+		}
+	}
+	if best == SeverityNone {
+		best = normalizeSeverity(r.DatabaseSpecific.Severity)
+	}
+	return best
+}
+
+func normalizeSeverity(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return SeverityCritical
+	case "high", "important":
+		return SeverityHigh
+	case "moderate", "medium":
+		return SeverityMedium
+	case "low", "negligible":
+		return SeverityLow
+	}
+	return SeverityNone
+}
+
+// indexKey normalises an OSV ecosystem ("Debian:12" -> "Debian") and name.
+func indexKey(ecosystem, name string) string {
+	eco, _, _ := strings.Cut(ecosystem, ":")
+	if eco == "PyPI" {
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+	return eco + "|" + name
+}
+
+// vulnFinding is one vulnerability matched to a component.
+type vulnFinding struct {
+	ID       string
+	Severity string
+}
+
+// Match returns the vulnerabilities affecting the package identified by
+// purl at version (the purl's own version when version is empty).
+func (db *VulnDB) Match(purl, version string) []vulnFinding {
+	if db == nil || purl == "" {
+		return nil
+	}
+	eco, name, purlVersion, ok := parsePURL(purl)
+	if !ok {
+		return nil
+	}
+	if version == "" {
+		version = purlVersion
+	}
+	if version == "" {
+		return nil
+	}
+	var out []vulnFinding
+	seen := make(map[string]bool)
+	for _, a := range db.index[indexKey(eco, name)] {
+		if seen[a.id] || !a.affects(version) {
+			continue
+		}
+		seen[a.id] = true
+		out = append(out, vulnFinding{ID: a.id, Severity: a.severity})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (a *osvAffected) affects(version string) bool {
+	if a.versions[version] {
+		return true
+	}
+	for _, r := range a.ranges {
+		if r.affects(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// affects implements the OSV range evaluation: walk the events in version
+// order, entering the affected state at "introduced" and leaving it at
+// "fixed" (inclusive) or after "last_affected".
+func (r osvRange) affects(version string) bool {
+	cmp := compareVersions
+	if r.semver {
+		cmp = compareSemver
+	}
+	type event struct{ kind, version string }
+	events := make([]event, 0, len(r.events))
+	for _, e := range r.events {
+		for kind, v := range e {
+			if kind == "introduced" || kind == "fixed" || kind == "last_affected" {
+				events = append(events, event{kind, v})
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].version == "0" || events[j].version == "0" {
+			return events[i].version == "0" && events[j].version != "0"
+		}
+		return cmp(events[i].version, events[j].version) < 0
+	})
+	affected := false
+	for _, e := range events {
+		switch e.kind {
+		case "introduced":
+			if e.version == "0" || cmp(version, e.version) >= 0 {
+				affected = true
+			}
+		case "fixed":
+			if cmp(version, e.version) >= 0 {
+				affected = false
+			}
+		case "last_affected":
+			if cmp(version, e.version) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// parsePURL extracts the OSV ecosystem, package name and version from a
+// package URL such as "pkg:npm/%40scope/name@1.2.3".
+func parsePURL(purl string) (ecosystem, name, version string, ok bool) {
+	rest, found := strings.CutPrefix(purl, "pkg:")
+	if !found {
+		return "", "", "", false
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	if i := strings.LastIndex(rest, "@"); i > 0 {
+		version, _ = url.PathUnescape(rest[i+1:])
+		rest = rest[:i]
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	for i := range parts {
+		parts[i], _ = url.PathUnescape(parts[i])
+	}
+	typ := strings.ToLower(parts[0])
+	namespace := strings.Join(parts[1:len(parts)-1], "/")
+	pkg := parts[len(parts)-1]
+
+	ecosystem = purlEcosystems[typ]
+	if ecosystem == "" {
+		ecosystem = purlEcosystems[typ+"/"+strings.ToLower(namespace)]
+		namespace = ""
+	}
+	if ecosystem == "" {
+		return "", "", "", false
+	}
+	switch {
+	case namespace == "":
+		name = pkg
+	case typ == "maven":
+		name = namespace + ":" + pkg
+	default:
+		name = namespace + "/" + pkg
+	}
+	return ecosystem, name, version, true
+}
+
+// compareSemver compares semantic versions, ordering pre-releases before
+// their release and ignoring build metadata.
+func compareSemver(a, b string) int {
+	a, _, _ = strings.Cut(strings.TrimPrefix(a, "v"), "+")
+	b, _, _ = strings.Cut(strings.TrimPrefix(b, "v"), "+")
+	coreA, preA, hasPreA := strings.Cut(a, "-")
+	coreB, preB, hasPreB := strings.Cut(b, "-")
+	if c := compareVersions(coreA, coreB); c != 0 {
+		return c
+	}
+	switch {
+	case hasPreA && !hasPreB:
+		return -1
+	case !hasPreA && hasPreB:
+		return 1
+	}
+	return compareVersions(preA, preB)
+}
+
+// compareVersions is a best-effort ordering for ecosystem versions: digit
+// runs compare numerically, other runs lexically. When one version extends
+// the other, a trailing letter segment ("rc1", "~beta", ".dev0") sorts it
+// earlier, a trailing number later.
+func compareVersions(a, b string) int {
+	a = strings.TrimPrefix(a, "v")
+	b = strings.TrimPrefix(b, "v")
+	ta, tb := versionTokens(a), versionTokens(b)
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		x, y := ta[i], tb[i]
+		nx, errX := strconv.ParseUint(x, 10, 64)
+		ny, errY := strconv.ParseUint(y, 10, 64)
+		switch {
+		case errX == nil && errY == nil:
+			if nx != ny {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			// Debian's "~" sorts before anything, including the end.
+			if x == "~" {
+				return -1
+			}
+			if y == "~" {
+				return 1
+			}
+			return strings.Compare(x, y)
+		}
+	}
+	switch {
+	case len(ta) == len(tb):
+		return 0
+	case len(ta) > len(tb):
+		return trailingOrder(ta[len(tb):])
+	default:
+		return -trailingOrder(tb[len(ta):])
+	}
+}
+
+// trailingOrder decides whether extra trailing tokens make a version newer
+// (1) or older (-1, a pre-release marker).
+func trailingOrder(extra []string) int {
+	for _, t := range extra {
+		if t == "~" {
+			return -1
+		}
+		if isSeparator(t) {
+			continue
+		}
+		if t[0] >= '0' && t[0] <= '9' {
+			return 1
+		}
+		return -1
+	}
+	return 1
+}
+
+func isSeparator(t string) bool {
+	return t == "." || t == "-" || t == "_" || t == "+" || t == ":"
+}
+
+// versionTokens splits a version into digit runs, letter runs and single
+// separator characters.
+func versionTokens(v string) []string {
+	var out []string
+	for i := 0; i < len(v); {
+		c := v[i]
+		j := i + 1
+		switch {
+		case c >= '0' && c <= '9':
+			for j < len(v) && v[j] >= '0' && v[j] <= '9' {
+				j++
+			}
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			for j < len(v) && ((v[j] >= 'a' && v[j] <= 'z') || (v[j] >= 'A' && v[j] <= 'Z')) {
+				j++
+			}
+		}
+		out = append(out, strings.ToLower(v[i:j]))
+		i = j
+	}
+	return out
+}
+
+// cvss3BaseScore computes the CVSS v3.x base score of a vector string.
+func cvss3BaseScore(vector string) (float64, bool) {
+	m := make(map[string]string)
+	for _, part := range strings.Split(vector, "/")[1:] {
+		k, v, ok := strings.Cut(part, ":")
+		if ok {
+			m[k] = v
+		}
+	}
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	val := make(map[string]float64)
+	for metric, table := range weights {
+		w, ok := table[m[metric]]
+		if !ok {
+			return 0, false
+		}
+		val[metric] = w
+	}
+	changed := m["S"] == "C"
+	if !changed && m["S"] != "U" {
+		return 0, false
+	}
+	pr := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if changed {
+		pr = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	}
+	prw, ok := pr[m["PR"]]
+	if !ok {
+		return 0, false
+	}
+
+	iss := 1 - (1-val["C"])*(1-val["I"])*(1-val["A"])
+	var impact float64
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * val["AV"] * val["AC"] * prw * val["UI"]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp is the CVSS v3.1 Roundup function.
+func roundUp(x float64) float64 {
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return (math.Floor(float64(i)/10000) + 1) / 10
+}
+
+func cvssSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNone
+}
@@ -0,0 +1,109 @@
+package sbom
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.10", -1},
+		{"v1.10.0", "1.9.9", 1},
+		{"2.0", "2.0.0", -1},
+		{"1.0rc1", "1.0", -1},
+		{"3.0.11-1~deb12u1", "3.0.13-1~deb12u1", -1},
+		{"1.0~beta", "1.0", -1},
+		{"1:2.0", "1:2.0", 0},
+	}
+	for _, c := range cases {
+		if got := compareVersions(c.a, c.b); got != c.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+	if compareSemver("1.0.0-rc.1", "1.0.0") != -1 || compareSemver("1.0.0+build", "1.0.0") != 0 {
+		t.Error("semver pre-release and build metadata ordering is wrong")
+	}
+}
+
+func TestOSVRange(t *testing.T) {
+	r := osvRange{semver: true, events: []map[string]string{
+		{"introduced": "0"}, {"fixed": "1.2.0"}, {"introduced": "2.0.0"}, {"last_affected": "2.1.0"},
+	}}
+	for version, want := range map[string]bool{
+		"1.0.0": true, "1.2.0": false, "1.5.0": false, "2.0.0": true, "2.1.0": true, "2.1.1": false,
+	} {
+		if got := r.affects(version); got != want {
+			t.Errorf("affects(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestParsePURL(t *testing.T) {
+	cases := map[string][3]string{
+		"pkg:npm/%40babel/core@7.0.0":                        {"npm", "@babel/core", "7.0.0"},
+		"pkg:maven/org.apache.logging.log4j/log4j-core@2.14": {"Maven", "org.apache.logging.log4j:log4j-core", "2.14"},
+		"pkg:golang/golang.org/x/net@v0.17.0":                {"Go", "golang.org/x/net", "v0.17.0"},
+		"pkg:deb/debian/curl@7.88.1-10?arch=amd64":           {"Debian", "curl", "7.88.1-10"},
+		"pkg:apk/alpine/musl@1.2.4-r2?distro=3.19":           {"Alpine", "musl", "1.2.4-r2"},
+	}
+	for purl, want := range cases {
+		eco, name, version, ok := parsePURL(purl)
+		if !ok || eco != want[0] || name != want[1] || version != want[2] {
+			t.Errorf("parsePURL(%q) = %q, %q, %q, %v", purl, eco, name, version, ok)
+		}
+	}
+	if _, _, _, ok := parsePURL("pkg:generic/thing@1"); ok {
+		t.Error("expected unknown purl type to be rejected")
+	}
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	cases := map[string]float64{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H": 10,
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N": 6.1,
+		"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N": 5.5,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": 0,
+	}
+	for vector, want := range cases {
+		got, ok := cvss3BaseScore(vector)
+		if !ok || got != want {
+			t.Errorf("cvss3BaseScore(%q) = %v, %v, want %v", vector, got, ok, want)
+		}
+	}
+	if _, ok := cvss3BaseScore("CVSS:3.1/AV:N"); ok {
+		t.Error("expected incomplete vector to be rejected")
+	}
+}
+
+func TestLoadOSVFeed_Zip(t *testing.T) {
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "all.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("GO-2024-0001.json")
+	w.Write([]byte(`{"id": "GO-2024-0001", "affected": [{"package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+	  "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.23.0"}]}]}]}`))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	db, err := LoadOSVFeed(dir)
+	if err != nil {
+		t.Fatalf("LoadOSVFeed: %v", err)
+	}
+	if got := db.Match("pkg:golang/golang.org/x/net@v0.17.0", ""); len(got) != 1 || got[0].ID != "GO-2024-0001" {
+		t.Errorf("expected match, got %+v", got)
+	}
+	if got := db.Match("pkg:golang/golang.org/x/net@v0.23.0", ""); len(got) != 0 {
+		t.Errorf("expected fixed version to be unaffected, got %+v", got)
+	}
+}
@@ -0,0 +1,182 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Document formats.
+const (
+	FormatSPDX      = "SPDX"
+	FormatCycloneDX = "CycloneDX"
+)
+
+// parsedSBOM is an SBOM reduced to the components the engine tracks.
+type parsedSBOM struct {
+	Format     string
+	Created    time.Time
+	Components []Component
+}
+
+// spdxDocument is the subset of an SPDX 2.x JSON document that is read.
+type spdxDocument struct {
+	SPDXVersion  string `json:"spdxVersion"`
+	CreationInfo struct {
+		Created string `json:"created"`
+	} `json:"creationInfo"`
+	DocumentDescribes []string `json:"documentDescribes"`
+	Packages          []struct {
+		SPDXID           string `json:"SPDXID"`
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+		ExternalRefs     []struct {
+			ReferenceCategory string `json:"referenceCategory"`
+			ReferenceType     string `json:"referenceType"`
+			ReferenceLocator  string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+	Relationships []struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+	} `json:"relationships"`
+}
+
+// cyclonedxComponent is the subset of a CycloneDX component that is read.
+type cyclonedxComponent struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	PURL     string `json:"purl"`
+	Licenses []struct {
+		License struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []cyclonedxComponent `json:"components"`
+}
+
+type cyclonedxDocument struct {
+	BOMFormat   string `json:"bomFormat"`
+	SpecVersion string `json:"specVersion"`
+	Metadata    struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"metadata"`
+	Components []cyclonedxComponent `json:"components"`
+}
+
+// parseDocument detects the SBOM format of data and parses it.
+func parseDocument(data []byte) (*parsedSBOM, error) {
+	var probe struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	switch {
+	case probe.SPDXVersion != "":
+		return parseSPDX(data)
+	case strings.EqualFold(probe.BOMFormat, FormatCycloneDX):
+		return parseCycloneDX(data)
+	}
+	return nil, errors.New("unrecognised SBOM format")
+}
+
+func parseSPDX(data []byte) (*parsedSBOM, error) {
+	var doc spdxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	// The described element is the image itself, not a component of it.
+	described := make(map[string]bool)
+	for _, id := range doc.DocumentDescribes {
+		described[id] = true
+	}
+	for _, r := range doc.Relationships {
+		if r.SPDXElementID == "SPDXRef-DOCUMENT" && r.RelationshipType == "DESCRIBES" {
+			described[r.RelatedSPDXElement] = true
+		}
+	}
+	out := &parsedSBOM{Format: FormatSPDX, Created: parseTimestamp(doc.CreationInfo.Created)}
+	for _, p := range doc.Packages {
+		if described[p.SPDXID] {
+			continue
+		}
+		c := Component{Name: p.Name, Version: p.VersionInfo, License: spdxLicense(p.LicenseConcluded, p.LicenseDeclared), Severity: SeverityNone}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				c.PURL = ref.ReferenceLocator
+				break
+			}
+		}
+		out.Components = append(out.Components, c)
+	}
+	return out, nil
+}
+
+// spdxLicense prefers the concluded license, ignoring NOASSERTION/NONE.
+func spdxLicense(values ...string) string {
+	for _, v := range values {
+		if v != "" && v != "NOASSERTION" && v != "NONE" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseCycloneDX(data []byte) (*parsedSBOM, error) {
+	var doc cyclonedxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	out := &parsedSBOM{Format: FormatCycloneDX, Created: parseTimestamp(doc.Metadata.Timestamp)}
+	var walk func([]cyclonedxComponent)
+	walk = func(components []cyclonedxComponent) {
+		for _, cc := range components {
+			// Operating system and file entries are not packages.
+			if cc.Type != "operating-system" && cc.Type != "file" {
+				name := cc.Name
+				if cc.Group != "" {
+					name = cc.Group + "/" + cc.Name
+				}
+				out.Components = append(out.Components, Component{
+					Name: name, Version: cc.Version, PURL: cc.PURL,
+					License: cyclonedxLicense(cc), Severity: SeverityNone,
+				})
+			}
+			walk(cc.Components)
+		}
+	}
+	walk(doc.Components)
+	return out, nil
+}
+
+func cyclonedxLicense(cc cyclonedxComponent) string {
+	var ids []string
+	for _, l := range cc.Licenses {
+		switch {
+		case l.Expression != "":
+			return l.Expression
+		case l.License.ID != "":
+			ids = append(ids, l.License.ID)
+		case l.License.Name != "":
+			ids = append(ids, l.License.Name)
+		}
+	}
+	return strings.Join(ids, " AND ")
+}
+
+// parseTimestamp parses an RFC 3339 creation time; malformed values are
+// ignored rather than failing the whole document.
+func parseTimestamp(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
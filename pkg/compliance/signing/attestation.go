@@ -0,0 +1,195 @@
+package signing
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attestation layer and artifact media types.
+const (
+	// MediaTypeDSSE is the layer type of cosign ".att" attestations.
+	MediaTypeDSSE = "application/vnd.dsse.envelope.v1+json"
+	// MediaTypeSigstoreBundlePrefix prefixes the versioned Sigstore bundle
+	// types GitHub artifact attestations push as OCI referrers.
+	MediaTypeSigstoreBundlePrefix = "application/vnd.dev.sigstore.bundle"
+)
+
+// dsseEnvelope is a DSSE envelope.
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		KeyID string `json:"keyid"`
+		Sig   string `json:"sig"`
+	} `json:"signatures"`
+}
+
+// sigstoreBundle is the subset of a Sigstore bundle (v0.1–v0.3) carrying a
+// DSSE envelope.
+type sigstoreBundle struct {
+	MediaType            string `json:"mediaType"`
+	VerificationMaterial struct {
+		Certificate *struct {
+			RawBytes string `json:"rawBytes"`
+		} `json:"certificate"`
+		X509CertificateChain *struct {
+			Certificates []struct {
+				RawBytes string `json:"rawBytes"`
+			} `json:"certificates"`
+		} `json:"x509CertificateChain"`
+		TlogEntries []bundleTlogEntry `json:"tlogEntries"`
+	} `json:"verificationMaterial"`
+	DSSEEnvelope *dsseEnvelope `json:"dsseEnvelope"`
+}
+
+// bundleTlogEntry is a Rekor entry in a Sigstore bundle. Integers are
+// JSON strings (protobuf int64 encoding) and bytes are base64.
+type bundleTlogEntry struct {
+	LogIndex string `json:"logIndex"`
+	LogID    struct {
+		KeyID string `json:"keyId"`
+	} `json:"logId"`
+	IntegratedTime   string `json:"integratedTime"`
+	InclusionPromise *struct {
+		SignedEntryTimestamp string `json:"signedEntryTimestamp"`
+	} `json:"inclusionPromise"`
+	CanonicalizedBody string `json:"canonicalizedBody"`
+}
+
+// VerifyAttestation verifies a DSSE-signed attestation and returns its
+// decoded payload (normally an in-toto statement). blob is either a bare
+// DSSE envelope, as in cosign ".att" layers where the certificate and Rekor
+// bundle travel in annotations, or a Sigstore bundle as pushed by GitHub
+// artifact attestations. The payload is returned even when verification
+// fails so callers can report what the attestation claims; an error means
+// blob is not an attestation at all.
+func (v *Verifier) VerifyAttestation(blob []byte, annotations map[string]string) ([]byte, Verification, error) {
+	var probe struct {
+		DSSEEnvelope json.RawMessage `json:"dsseEnvelope"`
+	}
+	if err := json.Unmarshal(blob, &probe); err != nil {
+		return nil, Verification{}, fmt.Errorf("decode attestation: %w", err)
+	}
+	if probe.DSSEEnvelope != nil {
+		return v.verifyBundle(blob)
+	}
+
+	var env dsseEnvelope
+	if err := json.Unmarshal(blob, &env); err != nil {
+		return nil, Verification{}, fmt.Errorf("decode DSSE envelope: %w", err)
+	}
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil || env.Payload == "" {
+		return nil, Verification{}, errors.New("DSSE envelope has no payload")
+	}
+	var res Verification
+	entries := v.checkBundle(annotations[annotationBundle])
+	res = v.verifyEnvelope(res, &env, payload, annotations[annotationCertificate], annotations[annotationChain], entries)
+	return payload, res, nil
+}
+
+func (v *Verifier) verifyBundle(blob []byte) ([]byte, Verification, error) {
+	var b sigstoreBundle
+	if err := json.Unmarshal(blob, &b); err != nil {
+		return nil, Verification{}, fmt.Errorf("decode Sigstore bundle: %w", err)
+	}
+	payload, err := base64.StdEncoding.DecodeString(b.DSSEEnvelope.Payload)
+	if err != nil || b.DSSEEnvelope.Payload == "" {
+		return nil, Verification{}, errors.New("Sigstore bundle has no DSSE payload")
+	}
+
+	var res Verification
+	var entries []tlogEntry
+	for _, e := range b.VerificationMaterial.TlogEntries {
+		entry, ok := e.rekorBundle()
+		if !ok {
+			continue
+		}
+		if trusted, ok := v.checkRekorEntry(entry); ok {
+			entries = append(entries, trusted)
+		}
+	}
+
+	// v0.3 bundles carry only the leaf; earlier ones the whole chain.
+	var certs []string
+	if c := b.VerificationMaterial.Certificate; c != nil {
+		certs = append(certs, c.RawBytes)
+	} else if chain := b.VerificationMaterial.X509CertificateChain; chain != nil {
+		for _, c := range chain.Certificates {
+			certs = append(certs, c.RawBytes)
+		}
+	}
+	var certPEM, chainPEM string
+	for i, raw := range certs {
+		der, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			res.FailureReason = "malformed bundle certificate"
+			return payload, res, nil
+		}
+		block := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		if i == 0 {
+			certPEM = block
+		} else {
+			chainPEM += block
+		}
+	}
+	return payload, v.verifyEnvelope(res, b.DSSEEnvelope, payload, certPEM, chainPEM, entries), nil
+}
+
+// rekorBundle converts the entry to the cosign bundle form checkRekorEntry
+// verifies. The SET covers the log ID as hex.
+func (e bundleTlogEntry) rekorBundle() (rekorBundle, bool) {
+	var b rekorBundle
+	idx, err1 := strconv.ParseInt(e.LogIndex, 10, 64)
+	at, err2 := strconv.ParseInt(e.IntegratedTime, 10, 64)
+	id, err3 := base64.StdEncoding.DecodeString(e.LogID.KeyID)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return b, false
+	}
+	b.Payload.Body = e.CanonicalizedBody
+	b.Payload.IntegratedTime = at
+	b.Payload.LogIndex = idx
+	b.Payload.LogID = hex.EncodeToString(id)
+	if e.InclusionPromise != nil {
+		b.SignedEntryTimestamp = e.InclusionPromise.SignedEntryTimestamp
+	}
+	return b, true
+}
+
+// verifyEnvelope accepts the envelope if any of its signatures verifies
+// over the DSSE pre-authentication encoding of the payload.
+func (v *Verifier) verifyEnvelope(res Verification, env *dsseEnvelope, payload []byte, certPEM, chainPEM string, entries []tlogEntry) Verification {
+	if len(env.Signatures) == 0 {
+		res.FailureReason = "attestation is not signed"
+		return res
+	}
+	res.Signed = true
+	msg := pae(env.PayloadType, payload)
+	var reasons []string
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			reasons = append(reasons, "malformed signature encoding")
+			continue
+		}
+		one, reason := v.verifyWith(res, msg, payload, sig, certPEM, chainPEM, entries)
+		if one.Verified {
+			return one
+		}
+		res.Signer, res.Keyless = one.Signer, one.Keyless
+		reasons = append(reasons, reason)
+	}
+	res.FailureReason = strings.Join(dedupe(reasons), "; ")
+	return res
+}
+
+// pae is the DSSE v1 pre-authentication encoding.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
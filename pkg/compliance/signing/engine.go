@@ -1,17 +1,35 @@
 package signing
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
-// Engine performs Sigstore/Cosign image signature verification.
-//
-// TODO (#9646): Replace stub with live cosign verify integration.
-type Engine struct{}
+// Engine performs Sigstore/Cosign image signature verification. An engine
+// built with NewEngine serves demo data; one built with NewLiveEngine
+// verifies running images (see live.go).
+type Engine struct {
+	inventory Inventory
+	verifier  *Verifier
 
-// NewEngine creates a signing engine. Currently returns demo data.
+	mu    sync.Mutex
+	cache map[string]cachedVerification // "registry/repo@digest"
+	last  *Report
+	now   func() time.Time
+}
+
+// NewEngine creates a signing engine that serves demo data.
 func NewEngine() *Engine { return &Engine{} }
 
-// Summary returns fleet-wide signature coverage metrics.
+// Summary returns fleet-wide signature coverage metrics. A live engine
+// returns the result of its most recent Evaluate.
 func (e *Engine) Summary() Summary {
+	if e.IsLive() {
+		if r := e.lastReport(); r != nil {
+			return r.Summary
+		}
+		return Summary{Mode: ModeLive}
+	}
 	return Summary{
 		TotalImages:      37,
 		SignedImages:     33,
@@ -20,18 +38,28 @@ func (e *Engine) Summary() Summary {
 		PolicyViolations: 2,
 		ClustersCovered:  5,
 		EvaluatedAt:      time.Now(),
+		Mode:             ModeDemo,
 	}
 }
 
 // Images returns per-image signature verification results.
 func (e *Engine) Images() []Image {
-	// TODO (#9646): Walk running pods via Kubernetes API, resolve image digests,
-	// run `cosign verify` against each, record Rekor transparency log entries.
+	if r := e.lastReport(); r != nil && r.Images != nil {
+		return r.Images
+	}
 	return []Image{}
 }
 
 // Policies returns cluster-scoped signing policies.
 func (e *Engine) Policies() []Policy {
-	// TODO (#9646): List Policy Controller ClusterImagePolicy resources.
+	if r := e.lastReport(); r != nil && r.Policies != nil {
+		return r.Policies
+	}
 	return []Policy{}
 }
+
+func (e *Engine) lastReport() *Report {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last
+}
@@ -0,0 +1,36 @@
+package signing
+
+import "context"
+
+// Inventory abstracts the cluster queries the live engine needs. The console
+// implements it with the multi-cluster client; tests provide a stub.
+type Inventory interface {
+	// RunningImages lists the images of running containers, one entry per
+	// workload and image.
+	RunningImages(ctx context.Context, cluster string) ([]RunningImage, error)
+	// ClusterImagePolicies lists Sigstore Policy Controller
+	// ClusterImagePolicy resources. A cluster without the CRD returns none.
+	ClusterImagePolicies(ctx context.Context, cluster string) ([]ClusterImagePolicy, error)
+}
+
+// RunningImage is a container image observed in a pod.
+type RunningImage struct {
+	// Image is the reference from the pod spec.
+	Image string
+	// Digest is the resolved manifest digest from the container status
+	// imageID, or empty when the runtime has not reported it yet.
+	Digest    string
+	Workload  string
+	Namespace string
+}
+
+// ClusterImagePolicy is a flattened policy.sigstore.dev ClusterImagePolicy.
+type ClusterImagePolicy struct {
+	Name string
+	// Mode is "enforce" (the default) or "warn".
+	Mode string
+	// Globs are the image patterns the policy applies to.
+	Globs []string
+	// Authorities is the number of keys / keyless identities it accepts.
+	Authorities int
+}
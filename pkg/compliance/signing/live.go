@@ -0,0 +1,306 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/safego"
+)
+
+// Engine modes reported in Summary.Mode and Report.Mode.
+const (
+	ModeDemo = "demo"
+	ModeLive = "live"
+)
+
+const (
+	// verificationCacheTTL is how long a digest's verification is reused.
+	// Digests are immutable, so only new or removed signatures change it.
+	verificationCacheTTL = 30 * time.Minute
+	// verifyConcurrency bounds parallel registry lookups.
+	verifyConcurrency = 8
+	// policyModeEnforce is the Policy Controller default mode.
+	policyModeEnforce = "enforce"
+)
+
+// Report is a fleet signing evaluation.
+type Report struct {
+	Mode     string            `json:"mode"`
+	Clusters []string          `json:"clusters"`
+	Images   []Image           `json:"images"`
+	Policies []Policy          `json:"policies"`
+	Summary  Summary           `json:"summary"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+type cachedVerification struct {
+	result Verification
+	at     time.Time
+}
+
+// NewLiveEngine creates a signing engine that walks running pods through
+// inventory and verifies their signatures with verifier.
+func NewLiveEngine(inventory Inventory, verifier *Verifier) *Engine {
+	return &Engine{
+		inventory: inventory,
+		verifier:  verifier,
+		cache:     make(map[string]cachedVerification),
+		now:       time.Now,
+	}
+}
+
+// IsLive reports whether the engine verifies real workloads.
+func (e *Engine) IsLive() bool {
+	return e.inventory != nil
+}
+
+// Invalidate drops cached verifications so the next Evaluate re-reads
+// every signature from the registries.
+func (e *Engine) Invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cache = make(map[string]cachedVerification)
+}
+
+// Evaluate verifies every running image on the given clusters. Clusters
+// whose inventory cannot be read are listed in Report.Errors; it only fails
+// when none could be read. The result also backs Summary, Images and
+// Policies until the next evaluation.
+func (e *Engine) Evaluate(ctx context.Context, clusters []string) (*Report, error) {
+	if !e.IsLive() {
+		return &Report{Mode: ModeDemo, Images: e.Images(), Policies: e.Policies(), Summary: e.Summary()}, nil
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no clusters to evaluate")
+	}
+
+	report := &Report{Mode: ModeLive}
+	failures := make(map[string]string)
+	var cips [][]ClusterImagePolicy
+	for _, cluster := range clusters {
+		running, err := e.inventory.RunningImages(ctx, cluster)
+		if err != nil {
+			failures[cluster] = err.Error()
+			continue
+		}
+		policies, err := e.inventory.ClusterImagePolicies(ctx, cluster)
+		if err != nil {
+			failures[cluster] = fmt.Sprintf("list ClusterImagePolicies: %v", err)
+			continue
+		}
+		report.Clusters = append(report.Clusters, cluster)
+		cips = append(cips, policies)
+		for _, ri := range running {
+			report.Images = append(report.Images, Image{
+				Image:     ri.Image,
+				Digest:    ri.Digest,
+				Workload:  ri.Workload,
+				Namespace: ri.Namespace,
+				Cluster:   cluster,
+			})
+		}
+	}
+	if len(report.Clusters) == 0 {
+		return nil, fmt.Errorf("signing evaluation failed on all %d cluster(s)", len(clusters))
+	}
+	if len(failures) > 0 {
+		report.Errors = failures
+	}
+
+	e.verifyAll(ctx, report.Images)
+	for i, cluster := range report.Clusters {
+		for _, cip := range cips[i] {
+			report.Policies = append(report.Policies, evaluatePolicy(cluster, cip, report.Images))
+		}
+	}
+	sort.Slice(report.Images, func(i, j int) bool {
+		a, b := report.Images[i], report.Images[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload+a.Image < b.Workload+b.Image
+	})
+	report.Summary = summarize(report.Images, report.Policies, len(report.Clusters))
+
+	e.mu.Lock()
+	e.last = report
+	e.mu.Unlock()
+	return report, nil
+}
+
+// verifyAll fills in verification results, verifying each distinct
+// repository digest once.
+func (e *Engine) verifyAll(ctx context.Context, images []Image) {
+	type target struct {
+		ref oci.Reference
+		key string
+	}
+	byKey := make(map[string][]int)
+	var targets []target
+	for i, img := range images {
+		ref, err := oci.ParseReference(img.Image)
+		if err != nil {
+			reason := fmt.Sprintf("invalid image reference: %v", err)
+			images[i].FailureReason = &reason
+			continue
+		}
+		ref.Digest = img.Digest
+		key := ref.Name() + "@" + img.Digest
+		if _, ok := byKey[key]; !ok {
+			targets = append(targets, target{ref: ref, key: key})
+		}
+		byKey[key] = append(byKey[key], i)
+	}
+
+	results := make([]Verification, len(targets))
+	sem := make(chan struct{}, verifyConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		safego.GoWith("signing/verify", func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = e.verify(ctx, t.ref, t.key)
+		})
+	}
+	wg.Wait()
+
+	for i, t := range targets {
+		for _, idx := range byKey[t.key] {
+			applyVerification(&images[idx], results[i])
+		}
+	}
+}
+
+func (e *Engine) verify(ctx context.Context, ref oci.Reference, key string) Verification {
+	if ref.Digest != "" {
+		e.mu.Lock()
+		cached, ok := e.cache[key]
+		e.mu.Unlock()
+		if ok && e.now().Sub(cached.at) < verificationCacheTTL {
+			return cached.result
+		}
+	}
+	res, err := e.verifier.Verify(ctx, ref)
+	if err != nil {
+		// Registry outages are not cached so the next evaluation retries.
+		return Verification{FailureReason: fmt.Sprintf("registry: %v", err)}
+	}
+	if ref.Digest != "" {
+		e.mu.Lock()
+		e.cache[key] = cachedVerification{result: res, at: e.now()}
+		e.mu.Unlock()
+	}
+	return res
+}
+
+func applyVerification(img *Image, v Verification) {
+	img.Signed = v.Signed
+	img.Verified = v.Verified
+	img.Signer = v.Signer
+	img.Keyless = v.Keyless
+	img.TransparencyLog = v.TransparencyLog
+	img.SignedAt = v.SignedAt
+	if v.FailureReason != "" && !v.Verified {
+		reason := v.FailureReason
+		img.FailureReason = &reason
+	}
+}
+
+// evaluatePolicy counts unverified images on cluster that the policy covers.
+func evaluatePolicy(cluster string, cip ClusterImagePolicy, images []Image) Policy {
+	mode := cip.Mode
+	if mode == "" {
+		mode = policyModeEnforce
+	}
+	p := Policy{
+		Name:    cip.Name,
+		Cluster: cluster,
+		Mode:    mode,
+		Scope:   strings.Join(cip.Globs, ", "),
+		Rules:   cip.Authorities,
+	}
+	matchers := make([]*regexp.Regexp, 0, len(cip.Globs))
+	for _, g := range cip.Globs {
+		matchers = append(matchers, globRegexp(g))
+	}
+	for _, img := range images {
+		if img.Cluster != cluster || img.Verified {
+			continue
+		}
+		if matchesAny(matchers, img.Image) {
+			p.Violations++
+		}
+	}
+	return p
+}
+
+func matchesAny(matchers []*regexp.Regexp, image string) bool {
+	candidates := []string{image}
+	if ref, err := oci.ParseReference(image); err == nil {
+		name := ref.Name()
+		// Policy Controller matches Docker Hub images as index.docker.io/...
+		candidates = append(candidates, name, strings.Replace(name, "registry-1.docker.io/", "index.docker.io/", 1))
+		if ref.Tag != "" {
+			candidates = append(candidates, name+":"+ref.Tag)
+		}
+	}
+	for _, re := range matchers {
+		for _, c := range candidates {
+			if re.MatchString(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// globRegexp converts a Policy Controller image glob: "**" matches across
+// path segments, "*" within one, "?" a single character.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func summarize(images []Image, policies []Policy, clusters int) Summary {
+	s := Summary{TotalImages: len(images), ClustersCovered: clusters, EvaluatedAt: time.Now(), Mode: ModeLive}
+	for _, img := range images {
+		if img.Signed {
+			s.SignedImages++
+		} else {
+			s.UnsignedImages++
+		}
+		if img.Verified {
+			s.VerifiedImages++
+		}
+	}
+	for _, p := range policies {
+		s.PolicyViolations += p.Violations
+	}
+	return s
+}
@@ -0,0 +1,386 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/oci/ocitest"
+)
+
+type stubInventory struct {
+	images   map[string][]RunningImage
+	policies map[string][]ClusterImagePolicy
+}
+
+func (s *stubInventory) RunningImages(_ context.Context, cluster string) ([]RunningImage, error) {
+	return s.images[cluster], nil
+}
+
+func (s *stubInventory) ClusterImagePolicies(_ context.Context, cluster string) ([]ClusterImagePolicy, error) {
+	return s.policies[cluster], nil
+}
+
+func mustKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func sign(t *testing.T, k *ecdsa.PrivateKey, msg []byte) string {
+	t.Helper()
+	sum := sha256.Sum256(msg)
+	sig, err := ecdsa.SignASN1(rand.Reader, k, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+// cosignPayload is the simple signing payload cosign signs for repo@digest.
+func cosignPayload(reg *ocitest.Registry, repo, digest string) []byte {
+	return []byte(`{"critical":{"identity":{"docker-reference":"` + reg.Host() + "/" + repo +
+		`"},"image":{"docker-manifest-digest":"` + digest + `"},"type":"cosign container image signature"},"optional":null}`)
+}
+
+// pushSignature stores a cosign signature for repo@digest with the given
+// extra annotations.
+func pushSignature(t *testing.T, reg *ocitest.Registry, repo, digest string, k *ecdsa.PrivateKey, extra map[string]string) {
+	t.Helper()
+	payload := cosignPayload(reg, repo, digest)
+	pushSignedPayload(reg, repo, digest, payload, sign(t, k, payload), extra)
+}
+
+func pushSignedPayload(reg *ocitest.Registry, repo, digest string, payload []byte, sig string, extra map[string]string) {
+	layer := reg.PushBlob("application/vnd.dev.cosign.simplesigning.v1+json", payload)
+	layer.Annotations = map[string]string{annotationSignature: sig}
+	for key, v := range extra {
+		layer.Annotations[key] = v
+	}
+	config := reg.PushBlob("application/vnd.oci.image.config.v1+json", []byte(`{}`))
+	reg.PushManifest(repo, oci.SignatureTag(digest), oci.Manifest{Config: config, Layers: []oci.Descriptor{layer}})
+}
+
+// fulcio issues short-lived code signing certificates like Fulcio does.
+type fulcio struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+	pool *x509.CertPool
+}
+
+func newFulcio(t *testing.T) *fulcio {
+	t.Helper()
+	key := mustKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-fulcio"},
+		NotBefore:             time.Now().Add(-7 * 24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &fulcio{key: key, cert: cert, pool: pool}
+}
+
+func (f *fulcio) issue(t *testing.T, pub crypto.PublicKey, email, issuer string, notBefore time.Time) string {
+	t.Helper()
+	issuerExt, _ := asn1.Marshal(issuer)
+	tmpl := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       notBefore,
+		NotAfter:        notBefore.Add(10 * time.Minute),
+		EmailAddresses:  []string{email},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuerExt}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, f.cert, pub, f.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// hashedRekordBody is the base64 Rekor entry body recording sig and cert
+// over payload.
+func hashedRekordBody(payload []byte, sig, certPEM string) string {
+	sum := sha256.Sum256(payload)
+	body, _ := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(sum[:])}},
+			"signature": map[string]any{
+				"content":   sig,
+				"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString([]byte(certPEM))},
+			},
+		},
+	})
+	return base64.StdEncoding.EncodeToString(body)
+}
+
+func rekorBundleFor(t *testing.T, rekor *ecdsa.PrivateKey, at time.Time, body string) string {
+	t.Helper()
+	payload := struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{body, at.Unix(), "c0ffee", 42}
+	canonical, _ := json.Marshal(payload)
+	b, _ := json.Marshal(map[string]any{"SignedEntryTimestamp": sign(t, rekor, canonical), "Payload": payload})
+	return string(b)
+}
+
+func TestLiveEngine_VerifiesKeyAndKeylessSignatures(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	host := reg.Host()
+
+	releaseKey := mustKey(t)
+	otherKey := mustKey(t)
+	rekorKey := mustKey(t)
+	ca := newFulcio(t)
+
+	keyed := reg.PushImage("org/api", "v1")
+	pushSignature(t, reg, "org/api", keyed, releaseKey, nil)
+
+	wrongKey := reg.PushImage("org/worker", "v1")
+	pushSignature(t, reg, "org/worker", wrongKey, otherKey, nil)
+
+	unsigned := reg.PushImage("org/sidecar", "v1")
+
+	// Keyless: the certificate expired long ago but was valid when the
+	// transparency log witnessed the signature.
+	signedAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	ephemeral := mustKey(t)
+	keyless := reg.PushImage("org/web", "v1")
+	keylessPayload := cosignPayload(reg, "org/web", keyless)
+	keylessSig := sign(t, ephemeral, keylessPayload)
+	keylessCert := ca.issue(t, &ephemeral.PublicKey, "ci@example.com", "https://issuer.example.com", signedAt.Add(-time.Minute))
+	pushSignedPayload(reg, "org/web", keyless, keylessPayload, keylessSig, map[string]string{
+		annotationCertificate: keylessCert,
+		annotationBundle:      rekorBundleFor(t, rekorKey, signedAt, hashedRekordBody(keylessPayload, keylessSig, keylessCert)),
+	})
+
+	verifier, err := NewVerifier(oci.NewClient(), VerifyOptions{
+		Keys:        []TrustedKey{{Name: "release-key", Key: &releaseKey.PublicKey}},
+		Identities:  []KeylessIdentity{{Issuer: "https://issuer.example.com", SubjectRegexp: `@example\.com$`}},
+		FulcioRoots: ca.pool,
+		RekorKeys:   []crypto.PublicKey{&rekorKey.PublicKey},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := &stubInventory{
+		images: map[string][]RunningImage{"prod": {
+			{Image: host + "/org/api:v1", Digest: keyed, Workload: "api", Namespace: "shop"},
+			{Image: host + "/org/worker:v1", Digest: wrongKey, Workload: "worker", Namespace: "shop"},
+			{Image: host + "/org/sidecar:v1", Digest: unsigned, Workload: "api", Namespace: "shop"},
+			{Image: host + "/org/web:v1", Digest: keyless, Workload: "web", Namespace: "shop"},
+		}},
+		policies: map[string][]ClusterImagePolicy{"prod": {
+			{Name: "org-images", Globs: []string{host + "/org/**"}, Authorities: 2},
+			{Name: "other", Mode: "warn", Globs: []string{"ghcr.io/**"}, Authorities: 1},
+		}},
+	}
+	e := NewLiveEngine(inv, verifier)
+	r, err := e.Evaluate(context.Background(), []string{"prod"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	byWorkloadImage := map[string]Image{}
+	for _, img := range r.Images {
+		byWorkloadImage[img.Image] = img
+	}
+	if img := byWorkloadImage[host+"/org/api:v1"]; !img.Verified || img.Signer != "release-key" || img.Keyless {
+		t.Errorf("key-signed image: %+v", img)
+	}
+	if img := byWorkloadImage[host+"/org/worker:v1"]; !img.Signed || img.Verified || img.FailureReason == nil {
+		t.Errorf("wrong-key image should be signed but unverified: %+v", img)
+	}
+	if img := byWorkloadImage[host+"/org/sidecar:v1"]; img.Signed || img.FailureReason == nil {
+		t.Errorf("unsigned image: %+v", img)
+	}
+	img := byWorkloadImage[host+"/org/web:v1"]
+	if !img.Verified || !img.Keyless || !img.TransparencyLog || img.Signer != "ci@example.com" {
+		t.Errorf("keyless image: %+v", img)
+	}
+	if img.SignedAt == nil || !img.SignedAt.Equal(signedAt) {
+		t.Errorf("expected SignedAt from Rekor bundle, got %v", img.SignedAt)
+	}
+
+	s := e.Summary()
+	if s.Mode != ModeLive || s.TotalImages != 4 || s.SignedImages != 3 || s.VerifiedImages != 2 || s.UnsignedImages != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.PolicyViolations != 2 {
+		t.Errorf("expected 2 violations from org-images, got %d", s.PolicyViolations)
+	}
+	if len(e.Policies()) != 2 || e.Policies()[1].Mode != "warn" {
+		t.Errorf("unexpected policies: %+v", e.Policies())
+	}
+}
+
+func TestVerify_ExpiredKeylessCertNeedsBoundLogEntry(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	ca := newFulcio(t)
+	rekorKey := mustKey(t)
+	ephemeral := mustKey(t)
+	// The certificate expired two days ago; only a log entry for this very
+	// signature may vouch that it was used while still valid.
+	signedAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	cert := ca.issue(t, &ephemeral.PublicKey, "ci@example.com", "https://issuer.example.com", signedAt.Add(-time.Minute))
+	opts := VerifyOptions{
+		Identities:  []KeylessIdentity{{Issuer: "https://issuer.example.com", Subject: "ci@example.com"}},
+		FulcioRoots: ca.pool,
+		RekorKeys:   []crypto.PublicKey{&rekorKey.PublicKey},
+	}
+
+	verify := func(t *testing.T, opts VerifyOptions, bundle func(payload []byte, sig string) string) Verification {
+		t.Helper()
+		digest := reg.PushImage("org/web", "v1")
+		payload := cosignPayload(reg, "org/web", digest)
+		sig := sign(t, ephemeral, payload)
+		pushSignedPayload(reg, "org/web", digest, payload, sig, map[string]string{
+			annotationCertificate: cert,
+			annotationBundle:      bundle(payload, sig),
+		})
+		verifier, err := NewVerifier(oci.NewClient(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := verifier.Verify(context.Background(), oci.Reference{Registry: reg.Host(), Repository: "org/web", Digest: digest})
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		return res
+	}
+
+	t.Run("bound entry", func(t *testing.T) {
+		res := verify(t, opts, func(payload []byte, sig string) string {
+			return rekorBundleFor(t, rekorKey, signedAt, hashedRekordBody(payload, sig, cert))
+		})
+		if !res.Verified || !res.TransparencyLog {
+			t.Errorf("expected verification at the logged time, got %+v", res)
+		}
+	})
+	t.Run("entry for another signature", func(t *testing.T) {
+		res := verify(t, opts, func(payload []byte, _ string) string {
+			other := sign(t, ephemeral, []byte("something else"))
+			return rekorBundleFor(t, rekorKey, signedAt, hashedRekordBody(payload, other, cert))
+		})
+		if res.Verified || res.TransparencyLog || res.SignedAt != nil {
+			t.Errorf("unrelated log entry must not vouch for the signature: %+v", res)
+		}
+	})
+	t.Run("unverified entry", func(t *testing.T) {
+		noRekor := opts
+		noRekor.RekorKeys = nil
+		res := verify(t, noRekor, func(payload []byte, sig string) string {
+			return rekorBundleFor(t, rekorKey, signedAt, hashedRekordBody(payload, sig, cert))
+		})
+		if res.Verified || res.TransparencyLog {
+			t.Errorf("self-reported signing time must not be trusted: %+v", res)
+		}
+	})
+}
+
+func TestLiveEngine_UntrustedKeylessIdentity(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	ca := newFulcio(t)
+	ephemeral := mustKey(t)
+	digest := reg.PushImage("org/web", "v1")
+	pushSignature(t, reg, "org/web", digest, ephemeral, map[string]string{
+		annotationCertificate: ca.issue(t, &ephemeral.PublicKey, "mallory@evil.test", "https://issuer.example.com", time.Now().Add(-time.Minute)),
+	})
+
+	verifier, _ := NewVerifier(oci.NewClient(), VerifyOptions{
+		Identities:  []KeylessIdentity{{Issuer: "https://issuer.example.com", Subject: "ci@example.com"}},
+		FulcioRoots: ca.pool,
+	})
+	res, err := verifier.Verify(context.Background(), oci.Reference{Registry: reg.Host(), Repository: "org/web", Digest: digest})
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !res.Signed || res.Verified || res.Signer != "mallory@evil.test" {
+		t.Errorf("expected untrusted identity to be rejected, got %+v", res)
+	}
+}
+
+func TestLiveEngine_CachesVerificationByDigest(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	key := mustKey(t)
+	digest := reg.PushImage("org/api", "v1")
+	pushSignature(t, reg, "org/api", digest, key, nil)
+
+	verifier, _ := NewVerifier(oci.NewClient(), VerifyOptions{Keys: []TrustedKey{{Name: "k", Key: &key.PublicKey}}})
+	inv := &stubInventory{images: map[string][]RunningImage{
+		"a": {{Image: reg.Host() + "/org/api:v1", Digest: digest, Workload: "api", Namespace: "x"}},
+		"b": {{Image: reg.Host() + "/org/api:v1", Digest: digest, Workload: "api", Namespace: "y"}},
+	}}
+	e := NewLiveEngine(inv, verifier)
+	if _, err := e.Evaluate(context.Background(), []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	first := reg.Requests()
+	if _, err := e.Evaluate(context.Background(), []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Requests() != first {
+		t.Errorf("expected cached verification, registry saw %d more requests", reg.Requests()-first)
+	}
+	if s := e.Summary(); s.VerifiedImages != 2 || s.ClustersCovered != 2 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	cases := []struct {
+		glob, image string
+		want        bool
+	}{
+		{"ghcr.io/org/**", "ghcr.io/org/team/app", true},
+		{"ghcr.io/org/*", "ghcr.io/org/team/app", false},
+		{"ghcr.io/org/*", "ghcr.io/org/app", true},
+		{"index.docker.io/library/nginx*", "index.docker.io/library/nginx", true},
+	}
+	for _, tc := range cases {
+		if got := globRegexp(tc.glob).MatchString(tc.image); got != tc.want {
+			t.Errorf("%s vs %s: got %v", tc.glob, tc.image, got)
+		}
+	}
+	if !matchesAny([]*regexp.Regexp{globRegexp("index.docker.io/library/**")}, "nginx:1.27") {
+		t.Error("expected Docker Hub short name to match index.docker.io glob")
+	}
+}
@@ -2,11 +2,10 @@
 // for the fleet. Checks keyless signatures against the Rekor transparency log
 // and evaluates cluster-scoped signing policies.
 //
-// TODO (#9646): Full implementation — integrate with:
-//   - Cosign verify-attestation for SBOM and SLSA predicates
-//   - Policy Controller (formerly Cosign Policy Controller) for enforcement
-//   - Rekor log entry lookup for transparency verification
-//   - Sigstore TUF root rotation monitoring
+// Signatures are read from the registry (the cosign "sha256-<hex>.sig" tag)
+// and verified against configured public keys or Fulcio keyless identities.
+// Rekor bundles attached to signatures are checked offline; online Rekor
+// lookups and Sigstore TUF root rotation are not implemented.
 package signing
 
 import "time"
@@ -46,4 +45,5 @@ type Summary struct {
 	PolicyViolations int       `json:"policy_violations"`
 	ClustersCovered  int       `json:"clusters_covered"`
 	EvaluatedAt      time.Time `json:"evaluated_at"`
+	Mode             string    `json:"mode"` // demo or live
 }
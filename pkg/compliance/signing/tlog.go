@@ -0,0 +1,205 @@
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"time"
+)
+
+// tlogEntry is a transparency log entry whose SignedEntryTimestamp verified
+// against a configured Rekor key.
+type tlogEntry struct {
+	body           []byte
+	integratedTime time.Time
+}
+
+// rekorEntry is the part of a Rekor entry body that ties it to one
+// signature. hashedrekord records the digest of the signed artifact; dsse and
+// intoto record the digest of the DSSE payload.
+type rekorEntry struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		// hashedrekord
+		Data struct {
+			Hash rekorHash `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+		// dsse
+		PayloadHash rekorHash `json:"payloadHash"`
+		Signatures  []struct {
+			Signature string `json:"signature"`
+			Verifier  string `json:"verifier"`
+		} `json:"signatures"`
+		// intoto v0.0.2
+		Content struct {
+			PayloadHash rekorHash `json:"payloadHash"`
+			Envelope    struct {
+				Signatures []struct {
+					Sig       string `json:"sig"`
+					PublicKey string `json:"publicKey"`
+				} `json:"signatures"`
+			} `json:"envelope"`
+		} `json:"content"`
+	} `json:"spec"`
+}
+
+type rekorHash struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// checkBundle returns the transparency log entry of a cosign bundle
+// annotation when its SignedEntryTimestamp verifies.
+func (v *Verifier) checkBundle(raw string) []tlogEntry {
+	if raw == "" {
+		return nil
+	}
+	var b rekorBundle
+	if err := json.Unmarshal([]byte(raw), &b); err != nil {
+		return nil
+	}
+	if e, ok := v.checkRekorEntry(b); ok {
+		return []tlogEntry{e}
+	}
+	return nil
+}
+
+// checkRekorEntry verifies the entry's SignedEntryTimestamp against the
+// Rekor keys. Without Rekor keys no entry is trusted: until the SET checks
+// out, the integration time and body are whatever the signer supplied.
+func (v *Verifier) checkRekorEntry(b rekorBundle) (tlogEntry, bool) {
+	if b.Payload.IntegratedTime == 0 || len(v.opts.RekorKeys) == 0 {
+		return tlogEntry{}, false
+	}
+	set, err := base64.StdEncoding.DecodeString(b.SignedEntryTimestamp)
+	if err != nil {
+		return tlogEntry{}, false
+	}
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return tlogEntry{}, false
+	}
+	// The SET signs the RFC 8785 canonical form of the payload; with these
+	// field types and alphabetical order, encoding/json produces exactly that.
+	canonical, _ := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{b.Payload.Body, b.Payload.IntegratedTime, b.Payload.LogID, b.Payload.LogIndex})
+	for _, k := range v.opts.RekorKeys {
+		if verifySignature(k, canonical, set) == nil {
+			return tlogEntry{body: body, integratedTime: time.Unix(b.Payload.IntegratedTime, 0).UTC()}, true
+		}
+	}
+	return tlogEntry{}, false
+}
+
+// witnessedAt returns when the log integrated this signature: the time of
+// the first trusted entry whose body records sig, the signer and the SHA-256
+// of logged. For keyless signatures the entry must carry cert itself.
+func witnessedAt(entries []tlogEntry, logged, sig []byte, signer crypto.PublicKey, cert *x509.Certificate) (time.Time, bool) {
+	sum := sha256.Sum256(logged)
+	digest := hex.EncodeToString(sum[:])
+	for _, e := range entries {
+		if entryBinds(e.body, digest, sig, signer, cert) {
+			return e.integratedTime, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func entryBinds(body []byte, digest string, sig []byte, signer crypto.PublicKey, cert *x509.Certificate) bool {
+	var e rekorEntry
+	if err := json.Unmarshal(body, &e); err != nil {
+		return false
+	}
+	switch e.Kind {
+	case "hashedrekord":
+		return hashMatches(e.Spec.Data.Hash, digest) &&
+			sigMatches(e.Spec.Signature.Content, sig) &&
+			keyMatches(e.Spec.Signature.PublicKey.Content, signer, cert)
+	case "dsse":
+		if !hashMatches(e.Spec.PayloadHash, digest) {
+			return false
+		}
+		for _, s := range e.Spec.Signatures {
+			if sigMatches(s.Signature, sig) && keyMatches(s.Verifier, signer, cert) {
+				return true
+			}
+		}
+	case "intoto":
+		if !hashMatches(e.Spec.Content.PayloadHash, digest) {
+			return false
+		}
+		for _, s := range e.Spec.Content.Envelope.Signatures {
+			if sigMatches(s.Sig, sig) && keyMatches(s.PublicKey, signer, cert) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hashMatches(h rekorHash, digest string) bool {
+	return h.Algorithm == "sha256" && strings.EqualFold(h.Value, digest)
+}
+
+// decodeLayers base64-decodes s once, and once more if the result is itself
+// base64: intoto v0.0.2 entries encode signatures and keys twice.
+func decodeLayers(s string) [][]byte {
+	once, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil
+	}
+	out := [][]byte{once}
+	if twice, err := base64.StdEncoding.DecodeString(string(once)); err == nil {
+		out = append(out, twice)
+	}
+	return out
+}
+
+func sigMatches(encoded string, sig []byte) bool {
+	for _, b := range decodeLayers(encoded) {
+		if bytes.Equal(b, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+func keyMatches(encoded string, signer crypto.PublicKey, cert *x509.Certificate) bool {
+	for _, b := range decodeLayers(encoded) {
+		block, _ := pem.Decode(b)
+		if block == nil {
+			continue
+		}
+		switch {
+		case cert != nil:
+			if block.Type == "CERTIFICATE" && bytes.Equal(block.Bytes, cert.Raw) {
+				return true
+			}
+		case block.Type == "PUBLIC KEY":
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if k, ok := pub.(interface{ Equal(crypto.PublicKey) bool }); ok && k.Equal(signer) {
+				return true
+			}
+		}
+	}
+	return false
+}
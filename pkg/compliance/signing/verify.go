@@ -0,0 +1,378 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+)
+
+// Cosign stores each signature as a layer of the "sha256-<hex>.sig" manifest.
+// The layer blob is the signed payload; these annotations carry the rest.
+const (
+	annotationSignature   = "dev.cosignproject.cosign/signature"
+	annotationCertificate = "dev.sigstore.cosign/certificate"
+	annotationChain       = "dev.sigstore.cosign/chain"
+	annotationBundle      = "dev.sigstore.cosign/bundle"
+)
+
+// Fulcio certificate extensions carrying the OIDC issuer. The v1 extension
+// holds the raw string; v2 holds a DER-encoded UTF8String.
+var (
+	oidFulcioIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// TrustedKey is a public key that signatures may verify against.
+type TrustedKey struct {
+	// Name is reported as the signer, e.g. "release-key".
+	Name string
+	Key  crypto.PublicKey
+}
+
+// KeylessIdentity is an accepted Fulcio certificate identity.
+type KeylessIdentity struct {
+	// Issuer is the OIDC issuer, e.g. "https://token.actions.githubusercontent.com".
+	Issuer string
+	// Subject is the certificate SAN (email or URI). It is matched exactly
+	// unless SubjectRegexp is set.
+	Subject       string
+	SubjectRegexp string
+}
+
+// VerifyOptions configures signature verification.
+type VerifyOptions struct {
+	Keys       []TrustedKey
+	Identities []KeylessIdentity
+	// FulcioRoots (and optional intermediates) anchor keyless certificates.
+	FulcioRoots         *x509.CertPool
+	FulcioIntermediates *x509.CertPool
+	// RekorKeys verify the SignedEntryTimestamp of transparency log
+	// bundles. Without them bundles are ignored, so keyless certificates
+	// are checked against the current time and expired ones fail.
+	RekorKeys []crypto.PublicKey
+}
+
+// Verification is the outcome of verifying one image digest.
+type Verification struct {
+	Signed          bool
+	Verified        bool
+	Signer          string
+	Keyless         bool
+	TransparencyLog bool
+	SignedAt        *time.Time
+	FailureReason   string
+}
+
+// Verifier checks cosign signatures stored in OCI registries.
+type Verifier struct {
+	registry   *oci.Client
+	opts       VerifyOptions
+	identities []compiledIdentity
+	now        func() time.Time
+}
+
+type compiledIdentity struct {
+	KeylessIdentity
+	re *regexp.Regexp
+}
+
+// NewVerifier returns a verifier that reads signatures through registry.
+func NewVerifier(registry *oci.Client, opts VerifyOptions) (*Verifier, error) {
+	v := &Verifier{registry: registry, opts: opts, now: time.Now}
+	for _, id := range opts.Identities {
+		ci := compiledIdentity{KeylessIdentity: id}
+		if id.SubjectRegexp != "" {
+			re, err := regexp.Compile(id.SubjectRegexp)
+			if err != nil {
+				return nil, fmt.Errorf("keyless identity %q: %w", id.SubjectRegexp, err)
+			}
+			ci.re = re
+		}
+		v.identities = append(v.identities, ci)
+	}
+	return v, nil
+}
+
+// simpleSigningPayload is the cosign "simple signing" document a signature
+// covers. Only the digest binding is checked.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the offline transparency log proof cosign attaches.
+type rekorBundle struct {
+	SignedEntryTimestamp string `json:"SignedEntryTimestamp"`
+	Payload              struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogIndex       int64  `json:"logIndex"`
+		LogID          string `json:"logID"`
+	} `json:"Payload"`
+}
+
+// Verify looks up the signatures for ref's digest and reports whether any of
+// them verifies against the configured keys or keyless identities. Registry
+// errors other than a missing signature tag are returned as errors.
+func (v *Verifier) Verify(ctx context.Context, ref oci.Reference) (Verification, error) {
+	var res Verification
+	if ref.Digest == "" {
+		res.FailureReason = "image digest unknown"
+		return res, nil
+	}
+	manifest, _, err := v.registry.Manifest(ctx, ref.Registry, ref.Repository, oci.SignatureTag(ref.Digest))
+	if errors.Is(err, oci.ErrNotFound) {
+		res.FailureReason = "no cosign signature found"
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+
+	var reasons []string
+	for _, layer := range manifest.Layers {
+		sig := layer.Annotations[annotationSignature]
+		if sig == "" {
+			continue
+		}
+		res.Signed = true
+		one, reason := v.verifyLayer(ctx, ref, layer)
+		if one.TransparencyLog {
+			res.TransparencyLog = true
+		}
+		if one.Verified {
+			one.Signed = true
+			return one, nil
+		}
+		if res.Signer == "" {
+			res.Signer, res.Keyless, res.SignedAt = one.Signer, one.Keyless, one.SignedAt
+		}
+		reasons = append(reasons, reason)
+	}
+	if !res.Signed {
+		res.FailureReason = "signature manifest has no signatures"
+		return res, nil
+	}
+	res.FailureReason = strings.Join(dedupe(reasons), "; ")
+	return res, nil
+}
+
+func (v *Verifier) verifyLayer(ctx context.Context, ref oci.Reference, layer oci.Descriptor) (Verification, string) {
+	var res Verification
+	payload, err := v.registry.Blob(ctx, ref.Registry, ref.Repository, layer.Digest)
+	if err != nil {
+		return res, fmt.Sprintf("fetch signature payload: %v", err)
+	}
+	var doc simpleSigningPayload
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return res, "malformed signature payload"
+	}
+	if doc.Critical.Image.DockerManifestDigest != ref.Digest {
+		return res, "signature payload is for a different digest"
+	}
+	sig, err := base64.StdEncoding.DecodeString(layer.Annotations[annotationSignature])
+	if err != nil {
+		return res, "malformed signature encoding"
+	}
+
+	entries := v.checkBundle(layer.Annotations[annotationBundle])
+	return v.verifyWith(res, payload, payload, sig, layer.Annotations[annotationCertificate], layer.Annotations[annotationChain], entries)
+}
+
+// verifyWith checks sig over msg against the signing certificate when one
+// is given, or else against the trusted keys. logged is what the
+// transparency log entries record the digest of: msg itself for cosign
+// signatures, the DSSE payload for attestations.
+func (v *Verifier) verifyWith(res Verification, msg, logged, sig []byte, certPEM, chainPEM string, entries []tlogEntry) (Verification, string) {
+	if certPEM != "" {
+		return v.verifyKeyless(res, msg, logged, sig, certPEM, chainPEM, entries)
+	}
+
+	if len(v.opts.Keys) == 0 {
+		return res, "no trusted public keys configured"
+	}
+	for _, k := range v.opts.Keys {
+		if verifySignature(k.Key, msg, sig) == nil {
+			res.Verified = true
+			res.Signer = k.Name
+			if at, ok := witnessedAt(entries, logged, sig, k.Key, nil); ok {
+				res.TransparencyLog = true
+				res.SignedAt = &at
+			}
+			return res, ""
+		}
+	}
+	return res, "signature does not match any trusted key"
+}
+
+func (v *Verifier) verifyKeyless(res Verification, payload, logged, sig []byte, certPEM, chainPEM string, entries []tlogEntry) (Verification, string) {
+	res.Keyless = true
+	cert, err := parseCertificate([]byte(certPEM))
+	if err != nil {
+		return res, "malformed signing certificate"
+	}
+	issuer := certIssuer(cert)
+	res.Signer = certSubject(cert)
+
+	if v.opts.FulcioRoots == nil {
+		return res, "no Fulcio root configured for keyless verification"
+	}
+	intermediates := v.opts.FulcioIntermediates
+	if chainPEM != "" {
+		if intermediates == nil {
+			intermediates = x509.NewCertPool()
+		} else {
+			intermediates = intermediates.Clone()
+		}
+		intermediates.AppendCertsFromPEM([]byte(chainPEM))
+	}
+	// Fulcio certificates live for minutes, so they are checked at the time
+	// the transparency log witnessed this signature. Without a verified log
+	// entry for it there is no trustworthy signing time, and the
+	// certificate must be valid now.
+	at := v.now()
+	if witnessed, ok := witnessedAt(entries, logged, sig, cert.PublicKey, cert); ok {
+		at = witnessed
+		res.TransparencyLog = true
+		res.SignedAt = &witnessed
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         v.opts.FulcioRoots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return res, fmt.Sprintf("certificate chain: %v", err)
+	}
+	if err := verifySignature(cert.PublicKey, payload, sig); err != nil {
+		return res, "signature does not match certificate"
+	}
+	for _, id := range v.identities {
+		if id.Issuer != "" && id.Issuer != issuer {
+			continue
+		}
+		if id.re != nil && !id.re.MatchString(res.Signer) {
+			continue
+		}
+		if id.re == nil && id.Subject != res.Signer {
+			continue
+		}
+		res.Verified = true
+		return res, ""
+	}
+	return res, fmt.Sprintf("identity %s (issuer %s) is not trusted", res.Signer, issuer)
+}
+
+// verifySignature checks sig over the SHA-256 of msg (or msg itself for
+// Ed25519).
+func verifySignature(key crypto.PublicKey, msg, sig []byte) error {
+	digest := sha256.Sum256(msg)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(k, digest[:], sig) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+		if rsa.VerifyPSS(k, crypto.SHA256, digest[:], sig, nil) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(k, msg, sig) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return errors.New("signature mismatch")
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func certSubject(cert *x509.Certificate) string {
+	if len(cert.EmailAddresses) > 0 {
+		return cert.EmailAddresses[0]
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
+
+func certIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			var s string
+			if _, err := asn1.Unmarshal(ext.Value, &s); err == nil {
+				return s
+			}
+		case ext.Id.Equal(oidFulcioIssuerV1):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// ParsePublicKeys parses every PEM "PUBLIC KEY" block in data.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM public keys found")
+	}
+	return keys, nil
+}
+
+func dedupe(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := in[:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
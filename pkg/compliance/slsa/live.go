@@ -0,0 +1,379 @@
+package slsa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/signing"
+	"github.com/kubestellar/console/pkg/safego"
+)
+
+// Engine modes reported in Summary.Mode and Report.Mode.
+const (
+	ModeDemo = "demo"
+	ModeLive = "live"
+)
+
+const (
+	// provenanceCacheTTL is how long a digest's attestations are reused.
+	provenanceCacheTTL = 30 * time.Minute
+	// fetchConcurrency bounds parallel registry lookups.
+	fetchConcurrency = 8
+)
+
+// Requirement IDs, in level order.
+const (
+	ReqProvenanceExists   = "provenance-exists"
+	ReqProvenanceVerified = "provenance-authentic"
+	ReqTrustedBuilder     = "builder-trusted"
+	ReqTrustedSource      = "source-trusted"
+	ReqReproducible       = "reproducible"
+)
+
+// Inventory lists the images running on a cluster. It is the subset of
+// signing.Inventory the SLSA engine needs.
+type Inventory interface {
+	RunningImages(ctx context.Context, cluster string) ([]signing.RunningImage, error)
+}
+
+// Report is a fleet SLSA evaluation.
+type Report struct {
+	Mode      string            `json:"mode"`
+	Clusters  []string          `json:"clusters"`
+	Workloads []Workload        `json:"workloads"`
+	Summary   Summary           `json:"summary"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+type cachedProvenance struct {
+	provenance []Provenance
+	at         time.Time
+}
+
+// NewLiveEngine creates a SLSA engine that reads provenance for the images
+// running on each cluster, verifies it with verifier and applies policies.
+func NewLiveEngine(inventory Inventory, registry *oci.Client, verifier *signing.Verifier, policies Policies) *Engine {
+	return &Engine{
+		inventory: inventory,
+		registry:  registry,
+		verifier:  verifier,
+		policies:  policies,
+		cache:     make(map[string]cachedProvenance),
+		now:       time.Now,
+	}
+}
+
+// IsLive reports whether the engine evaluates real workloads.
+func (e *Engine) IsLive() bool {
+	return e.inventory != nil
+}
+
+// Invalidate drops cached provenance so the next Evaluate re-reads every
+// attestation from the registries.
+func (e *Engine) Invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cache = make(map[string]cachedProvenance)
+}
+
+// Evaluate assesses every running workload on the given clusters. Clusters
+// whose inventory cannot be read are listed in Report.Errors; it only fails
+// when none could be read. The result also backs Summary and Workloads
+// until the next evaluation.
+func (e *Engine) Evaluate(ctx context.Context, clusters []string) (*Report, error) {
+	if !e.IsLive() {
+		return &Report{Mode: ModeDemo, Workloads: e.Workloads(), Summary: e.Summary()}, nil
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no clusters to evaluate")
+	}
+
+	report := &Report{Mode: ModeLive, Workloads: []Workload{}}
+	failures := make(map[string]string)
+	for _, cluster := range clusters {
+		workloads, err := e.evaluateCluster(ctx, cluster)
+		if err != nil {
+			failures[cluster] = err.Error()
+			continue
+		}
+		report.Clusters = append(report.Clusters, cluster)
+		report.Workloads = append(report.Workloads, workloads...)
+	}
+	if len(report.Clusters) == 0 {
+		return nil, fmt.Errorf("SLSA evaluation failed on all %d cluster(s)", len(clusters))
+	}
+	if len(failures) > 0 {
+		report.Errors = failures
+	}
+	report.Summary = summarize(report.Workloads)
+
+	e.mu.Lock()
+	e.last = report
+	e.mu.Unlock()
+	return report, nil
+}
+
+// ImageProvenance returns how many workloads on cluster run images with
+// provenance (attested) and how many of those verify and satisfy their
+// namespace policy (verified). It matches the frameworks.ClusterProber
+// method of the same name so framework evaluations can reference it.
+func (e *Engine) ImageProvenance(ctx context.Context, cluster string) (total, attested, verified int, err error) {
+	if !e.IsLive() {
+		return 0, 0, 0, nil
+	}
+	workloads, err := e.evaluateCluster(ctx, cluster)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, w := range workloads {
+		if w.AttestationPresent {
+			attested++
+		}
+		if w.AttestationVerified && len(w.Violations) == 0 {
+			verified++
+		}
+	}
+	return len(workloads), attested, verified, nil
+}
+
+// evaluateCluster assesses each image, then reports one entry per workload
+// carrying its weakest image: a workload is only as trustworthy as the
+// least attested code it runs.
+func (e *Engine) evaluateCluster(ctx context.Context, cluster string) ([]Workload, error) {
+	running, err := e.inventory.RunningImages(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	provenance := e.fetchAll(ctx, running)
+	now := e.now()
+
+	byWorkload := make(map[string]int)
+	var out []Workload
+	for i, ri := range running {
+		w := assess(ri, cluster, provenance[i], e.policies.For(ri.Namespace), now)
+		key := ri.Namespace + "/" + ri.Workload
+		if idx, ok := byWorkload[key]; ok {
+			if w.SLSALevel < out[idx].SLSALevel || (w.SLSALevel == out[idx].SLSALevel && len(w.Violations) > len(out[idx].Violations)) {
+				out[idx] = w
+			}
+			continue
+		}
+		byWorkload[key] = len(out)
+		out = append(out, w)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Workload < out[j].Workload
+	})
+	return out, nil
+}
+
+// fetchAll looks up provenance for each image, fetching each distinct
+// repository digest once. Images that cannot be looked up get the reason
+// in lookup.err.
+func (e *Engine) fetchAll(ctx context.Context, running []signing.RunningImage) []lookup {
+	type target struct {
+		ref oci.Reference
+		key string
+	}
+	results := make([]lookup, len(running))
+	byKey := make(map[string][]int)
+	var targets []target
+	for i, ri := range running {
+		if ri.Digest == "" {
+			results[i].err = fmt.Errorf("image digest unknown")
+			continue
+		}
+		ref, err := oci.ParseReference(ri.Image)
+		if err != nil {
+			results[i].err = fmt.Errorf("invalid image reference: %w", err)
+			continue
+		}
+		ref.Digest = ri.Digest
+		key := ref.Name() + "@" + ri.Digest
+		if _, ok := byKey[key]; !ok {
+			targets = append(targets, target{ref: ref, key: key})
+		}
+		byKey[key] = append(byKey[key], i)
+	}
+
+	fetched := make([]lookup, len(targets))
+	sem := make(chan struct{}, fetchConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		safego.GoWith("slsa/fetch", func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fetched[i] = e.fetch(ctx, t.ref, t.key)
+		})
+	}
+	wg.Wait()
+
+	for i, t := range targets {
+		for _, idx := range byKey[t.key] {
+			results[idx] = fetched[i]
+		}
+	}
+	return results
+}
+
+// lookup is the provenance found for one image digest.
+type lookup struct {
+	provenance []Provenance
+	err        error
+}
+
+func (e *Engine) fetch(ctx context.Context, ref oci.Reference, key string) lookup {
+	e.mu.Lock()
+	cached, ok := e.cache[key]
+	e.mu.Unlock()
+	if ok && e.now().Sub(cached.at) < provenanceCacheTTL {
+		return lookup{provenance: cached.provenance}
+	}
+	found, err := fetchProvenance(ctx, e.registry, e.verifier, ref)
+	if err != nil {
+		// Registry outages are not cached so the next evaluation retries.
+		return lookup{err: fmt.Errorf("registry: %w", err)}
+	}
+	e.mu.Lock()
+	e.cache[key] = cachedProvenance{provenance: found, at: e.now()}
+	e.mu.Unlock()
+	return lookup{provenance: found}
+}
+
+// assess scores one image, using whichever of its provenance attestations
+// reaches the highest level.
+func assess(ri signing.RunningImage, cluster string, found lookup, policy *Policy, now time.Time) Workload {
+	base := Workload{
+		Workload:    ri.Workload,
+		Namespace:   ri.Namespace,
+		Cluster:     cluster,
+		Image:       ri.Image,
+		Digest:      ri.Digest,
+		EvaluatedAt: now,
+	}
+	if policy != nil {
+		base.RequiredLevel = policy.MinLevel
+	}
+	if len(found.provenance) == 0 {
+		evidence := "no SLSA provenance attestation found"
+		if found.err != nil {
+			evidence = found.err.Error()
+		}
+		w := base
+		w.Requirements = []Requirement{
+			{ID: ReqProvenanceExists, Description: "Provenance exists for the running digest", Evidence: evidence},
+		}
+		w.Violations = levelViolation(w.SLSALevel, w.RequiredLevel)
+		return w
+	}
+
+	var best Workload
+	for i, p := range found.provenance {
+		w := score(base, p, policy)
+		if i == 0 || w.SLSALevel > best.SLSALevel || (w.SLSALevel == best.SLSALevel && len(w.Violations) < len(best.Violations)) {
+			best = w
+		}
+	}
+	return best
+}
+
+// score applies the level rules to one provenance attestation.
+func score(w Workload, p Provenance, policy *Policy) Workload {
+	w.AttestationPresent = true
+	w.AttestationVerified = p.Verification.Verified
+	w.BuilderID = p.BuilderID
+	w.BuildSystem = buildSystem(p.BuilderID)
+	w.SourceURI = p.SourceURI
+
+	authentic := "signature verified"
+	if p.Verification.Signer != "" {
+		authentic = "signed by " + p.Verification.Signer
+	}
+	if !p.Verification.Verified {
+		authentic = p.Verification.FailureReason
+	}
+	builderTrusted := policy.trustsBuilder(p.BuilderID)
+	sourceAccepted := policy.acceptsSource(p.SourceURI)
+	w.Requirements = []Requirement{
+		{ID: ReqProvenanceExists, Description: "Provenance exists for the running digest", Met: true,
+			Evidence: fmt.Sprintf("%s (%s)", p.PredicateType, p.Source)},
+		{ID: ReqProvenanceVerified, Description: "Provenance is signed by a trusted identity", Met: p.Verification.Verified,
+			Evidence: authentic},
+		{ID: ReqTrustedBuilder, Description: "Built by a builder trusted by namespace policy", Met: builderTrusted,
+			Evidence: orUnknown(p.BuilderID)},
+		{ID: ReqTrustedSource, Description: "Built from a source repository allowed by namespace policy", Met: sourceAccepted && p.SourceURI != "",
+			Evidence: orUnknown(p.SourceURI)},
+		{ID: ReqReproducible, Description: "Build is declared reproducible", Met: p.Reproducible,
+			Evidence: strconv.FormatBool(p.Reproducible)},
+	}
+	// The level is the number of consecutive requirements met, with the
+	// builder and source checks together forming L3.
+	switch {
+	case !p.Verification.Verified:
+		w.SLSALevel = Level1
+	case !builderTrusted || !sourceAccepted || p.SourceURI == "":
+		w.SLSALevel = Level2
+	case !p.Reproducible:
+		w.SLSALevel = Level3
+	default:
+		w.SLSALevel = Level4
+	}
+
+	if policy != nil && len(policy.Builders) > 0 && !builderTrusted {
+		w.Violations = append(w.Violations, fmt.Sprintf("builder %s is not trusted for namespace %s", orUnknown(p.BuilderID), w.Namespace))
+	}
+	if !sourceAccepted {
+		w.Violations = append(w.Violations, fmt.Sprintf("source %s is not allowed for namespace %s", orUnknown(p.SourceURI), w.Namespace))
+	}
+	w.Violations = append(w.Violations, levelViolation(w.SLSALevel, w.RequiredLevel)...)
+	return w
+}
+
+func levelViolation(level, required Level) []string {
+	if level >= required {
+		return nil
+	}
+	return []string{fmt.Sprintf("SLSA level %d is below the required level %d", level, required)}
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func summarize(workloads []Workload) Summary {
+	s := Summary{
+		TotalWorkloads:    len(workloads),
+		LevelDistribution: map[string]int{"0": 0, "1": 0, "2": 0, "3": 0, "4": 0},
+		EvaluatedAt:       time.Now(),
+		Mode:              ModeLive,
+	}
+	for i, w := range workloads {
+		s.LevelDistribution[strconv.Itoa(int(w.SLSALevel))]++
+		if w.AttestationPresent {
+			s.AttestedWorkloads++
+		}
+		if w.AttestationVerified {
+			s.VerifiedWorkloads++
+		}
+		if len(w.Violations) > 0 {
+			s.PolicyViolations++
+		}
+		if i == 0 || w.SLSALevel < s.FleetPosture {
+			s.FleetPosture = w.SLSALevel
+		}
+	}
+	return s
+}
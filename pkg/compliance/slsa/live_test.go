@@ -0,0 +1,189 @@
+package slsa
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/oci/ocitest"
+	"github.com/kubestellar/console/pkg/compliance/signing"
+)
+
+type stubInventory map[string][]signing.RunningImage
+
+func (s stubInventory) RunningImages(_ context.Context, cluster string) ([]signing.RunningImage, error) {
+	return s[cluster], nil
+}
+
+const (
+	githubBuilder = "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_container_slsa3.yml@refs/tags/v2.0.0"
+	payloadType   = "application/vnd.in-toto+json"
+)
+
+// envelope returns a DSSE envelope over an SLSA v1 statement for digest,
+// signed with k.
+func envelope(t *testing.T, k *ecdsa.PrivateKey, digest, builder, repo string) string {
+	t.Helper()
+	_, hex, _ := strings.Cut(digest, ":")
+	stmt := fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"img","digest":{"sha256":%q}}],
+		"predicateType":"https://slsa.dev/provenance/v1","predicate":{
+		"buildDefinition":{"buildType":"https://slsa-framework.github.io/github-actions-buildtypes/workflow/v1",
+		  "externalParameters":{"workflow":{"ref":"refs/heads/main","repository":%q,"path":".github/workflows/release.yml"}}},
+		"runDetails":{"builder":{"id":%q}}}}`, hex, repo, builder)
+	msg := fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(stmt), stmt)
+	sum := sha256.Sum256([]byte(msg))
+	sig, err := ecdsa.SignASN1(rand.Reader, k, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf(`{"payloadType":%q,"payload":%q,"signatures":[{"sig":%q}]}`,
+		payloadType, base64.StdEncoding.EncodeToString([]byte(stmt)), base64.StdEncoding.EncodeToString(sig))
+}
+
+// pushCosignAttestation stores env under the cosign ".att" tag.
+func pushCosignAttestation(reg *ocitest.Registry, repo, digest, env string) {
+	layer := reg.PushBlob(signing.MediaTypeDSSE, []byte(env))
+	layer.Annotations = map[string]string{"predicateType": "https://slsa.dev/provenance/v1"}
+	config := reg.PushBlob("application/vnd.oci.image.config.v1+json", []byte(`{}`))
+	reg.PushManifest(repo, oci.AttestationTag(digest), oci.Manifest{Config: config, Layers: []oci.Descriptor{layer}})
+}
+
+// pushBundleReferrer stores env in a Sigstore bundle as an OCI referrer,
+// as GitHub artifact attestations do.
+func pushBundleReferrer(reg *ocitest.Registry, repo, digest, env string) {
+	const bundleType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+	bundle := `{"mediaType":"` + bundleType + `","verificationMaterial":{"publicKey":{"hint":"release"}},"dsseEnvelope":` + env + `}`
+	layer := reg.PushBlob(bundleType, []byte(bundle))
+	config := reg.PushBlob("application/vnd.oci.empty.v1+json", []byte(`{}`))
+	reg.PushManifest(repo, "", oci.Manifest{
+		ArtifactType: bundleType,
+		Config:       config,
+		Layers:       []oci.Descriptor{layer},
+		Subject:      &oci.Descriptor{MediaType: oci.MediaTypeOCIManifest, Digest: digest},
+	})
+}
+
+func TestLiveEngine_Evaluate(t *testing.T) {
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	api := reg.PushImage("org/api", "v1")
+	pushCosignAttestation(reg, "org/api", api, envelope(t, key, api, githubBuilder, "https://github.com/org/api"))
+	web := reg.PushImage("org/web", "v1")
+	pushBundleReferrer(reg, "org/web", web, envelope(t, key, web, githubBuilder, "https://github.com/evil/web"))
+	forged := reg.PushImage("org/forged", "v1")
+	pushCosignAttestation(reg, "org/forged", forged, envelope(t, other, forged, githubBuilder, "https://github.com/org/forged"))
+	bare := reg.PushImage("org/bare", "v1")
+
+	host := reg.Host()
+	inv := stubInventory{"c1": {
+		{Image: host + "/org/api:v1", Digest: api, Workload: "api", Namespace: "prod"},
+		{Image: host + "/org/web:v1", Digest: web, Workload: "web", Namespace: "prod"},
+		{Image: host + "/org/forged:v1", Digest: forged, Workload: "forged", Namespace: "prod"},
+		{Image: host + "/org/bare:v1", Digest: bare, Workload: "bare", Namespace: "dev"},
+		// A sidecar without provenance drags "api2" down to L0.
+		{Image: host + "/org/api:v1", Digest: api, Workload: "api2", Namespace: "prod"},
+		{Image: host + "/org/bare:v1", Digest: bare, Workload: "api2", Namespace: "prod"},
+	}}
+	verifier, err := signing.NewVerifier(oci.NewClient(), signing.VerifyOptions{
+		Keys: []signing.TrustedKey{{Name: "release", Key: &key.PublicKey}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies := Policies{
+		{Namespace: "prod", Builders: []string{"https://github.com/slsa-framework/slsa-github-generator/*"}, SourceRepos: []string{"github.com/org/*"}, MinLevel: Level3},
+		{Namespace: "*"},
+	}
+	e := NewLiveEngine(inv, oci.NewClient(), verifier, policies)
+
+	report, err := e.Evaluate(context.Background(), []string{"c1"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	got := make(map[string]Workload)
+	for _, w := range report.Workloads {
+		got[w.Workload] = w
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected 5 workloads, got %d: %+v", len(got), report.Workloads)
+	}
+	if w := got["api"]; w.SLSALevel != Level3 || !w.AttestationVerified || len(w.Violations) != 0 || w.BuildSystem != "GitHub Actions" {
+		t.Errorf("api: %+v", w)
+	}
+	if w := got["web"]; w.SLSALevel != Level2 || len(w.Violations) != 2 {
+		t.Errorf("web (untrusted source via referrer): level %d, violations %v", w.SLSALevel, w.Violations)
+	}
+	if w := got["forged"]; w.SLSALevel != Level1 || w.AttestationVerified || !w.AttestationPresent {
+		t.Errorf("forged: %+v", w)
+	}
+	if w := got["bare"]; w.SLSALevel != Level0 || len(w.Violations) != 0 {
+		t.Errorf("bare (no policy minimum): %+v", w)
+	}
+	if w := got["api2"]; w.SLSALevel != Level0 || w.Image != host+"/org/bare:v1" {
+		t.Errorf("api2 should report its weakest image: %+v", w)
+	}
+
+	s := e.Summary()
+	if s.Mode != ModeLive || s.TotalWorkloads != 5 || s.FleetPosture != Level0 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.AttestedWorkloads != 3 || s.VerifiedWorkloads != 2 || s.PolicyViolations != 3 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.LevelDistribution["0"] != 2 || s.LevelDistribution["3"] != 1 {
+		t.Errorf("unexpected distribution: %v", s.LevelDistribution)
+	}
+
+	total, attested, verified, err := e.ImageProvenance(context.Background(), "c1")
+	if err != nil || total != 5 || attested != 3 || verified != 1 {
+		t.Errorf("ImageProvenance = %d, %d, %d, %v", total, attested, verified, err)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slsa.yaml")
+	os.WriteFile(path, []byte(`
+policies:
+  - namespace: payments
+    builders: ["https://github.com/slsa-framework/*"]
+    sourceRepos: ["github.com/acme/"]
+    minLevel: 3
+  - namespace: "*"
+    minLevel: 1
+`), 0o644)
+	ps, err := LoadPolicies(path)
+	if err != nil {
+		t.Fatalf("LoadPolicies: %v", err)
+	}
+	if p := ps.For("payments"); p == nil || p.MinLevel != Level3 {
+		t.Errorf("payments policy: %+v", p)
+	}
+	if p := ps.For("other"); p == nil || p.MinLevel != Level1 {
+		t.Errorf("default policy: %+v", p)
+	}
+
+	p := ps.For("payments")
+	p.SourceRepos = []string{"github.com/acme/*"}
+	for uri, want := range map[string]bool{
+		"git+https://github.com/Acme/shop.git@refs/heads/main": true,
+		"git+ssh://git@github.com/acme/shop":                   true,
+		"https://github.com/acme-evil/shop":                    false,
+		"":                                                     false,
+	} {
+		if got := p.acceptsSource(uri); got != want {
+			t.Errorf("acceptsSource(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
@@ -2,11 +2,18 @@
 // provenance tracking. Evaluates attestations against SLSA requirements and
 // assigns level badges (L0–L4) to workloads across the fleet.
 //
-// TODO (#9647): Full implementation — integrate with:
-//   - slsa-verifier for attestation verification
-//   - Rekor transparency log for provenance lookup
-//   - Kubernetes workload annotation for build metadata
-//   - OPA/Gatekeeper policy for SLSA level enforcement
+// The live engine reads in-toto provenance attestations attached to running
+// image digests (OCI referrers and cosign ".att" tags), verifies their
+// signatures, and checks the builder and source repository against a
+// per-namespace Policy to assign each workload a level:
+//
+//	L0  no provenance
+//	L1  provenance exists for the running digest
+//	L2  provenance is signed and the signature verifies
+//	L3  builder and source repository are trusted by policy
+//	L4  the build is declared reproducible
+//
+// TODO (#9647): Enforce policy through OPA/Gatekeeper.
 package slsa
 
 import "time"
@@ -36,6 +43,7 @@ type Workload struct {
 	Namespace            string        `json:"namespace"`
 	Cluster              string        `json:"cluster"`
 	Image                string        `json:"image"`
+	Digest               string        `json:"digest,omitempty"`
 	SLSALevel            Level         `json:"slsa_level"`
 	BuildSystem          string        `json:"build_system"`
 	BuilderID            string        `json:"builder_id"`
@@ -44,6 +52,9 @@ type Workload struct {
 	AttestationVerified  bool          `json:"attestation_verified"`
 	Requirements         []Requirement `json:"requirements"`
 	EvaluatedAt          time.Time     `json:"evaluated_at"`
+	RequiredLevel        Level         `json:"required_level"`
+	// Violations lists how the workload breaks its namespace policy.
+	Violations []string `json:"violations,omitempty"`
 }
 
 // Summary aggregates SLSA posture across the fleet.
@@ -54,4 +65,6 @@ type Summary struct {
 	VerifiedWorkloads int            `json:"verified_workloads"`
 	FleetPosture      Level          `json:"fleet_posture"` // lowest level in fleet
 	EvaluatedAt       time.Time      `json:"evaluated_at"`
+	PolicyViolations  int            `json:"policy_violations"`
+	Mode              string         `json:"mode"` // demo or live
 }
@@ -0,0 +1,122 @@
+package slsa
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultPolicyNamespace selects the policy for namespaces without one.
+const defaultPolicyNamespace = "*"
+
+// Policy constrains the provenance accepted for a namespace's workloads.
+type Policy struct {
+	// Namespace the policy applies to, or "*" for every namespace without
+	// its own policy.
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// Builders are the trusted builder IDs. Only these builders can take a
+	// workload to L3. An entry ending in "*" matches by prefix.
+	Builders []string `yaml:"builders" json:"builders"`
+	// SourceRepos are the accepted source repositories, e.g.
+	// "github.com/org/*". Empty accepts any source.
+	SourceRepos []string `yaml:"sourceRepos" json:"source_repos"`
+	// MinLevel is the level workloads must reach.
+	MinLevel Level `yaml:"minLevel" json:"min_level"`
+}
+
+// Policies is the set of per-namespace policies.
+type Policies []Policy
+
+// For returns the policy for namespace, falling back to the "*" policy. It
+// returns nil when neither exists.
+func (ps Policies) For(namespace string) *Policy {
+	var fallback *Policy
+	for i := range ps {
+		switch ps[i].Namespace {
+		case namespace:
+			return &ps[i]
+		case defaultPolicyNamespace, "":
+			fallback = &ps[i]
+		}
+	}
+	return fallback
+}
+
+// LoadPolicies reads a YAML (or JSON) file holding a "policies" list.
+func LoadPolicies(path string) (Policies, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Policies Policies `yaml:"policies"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse SLSA policy %s: %w", path, err)
+	}
+	for _, p := range doc.Policies {
+		if p.MinLevel < Level0 || p.MinLevel > Level4 {
+			return nil, fmt.Errorf("SLSA policy for namespace %q: minLevel %d out of range", p.Namespace, p.MinLevel)
+		}
+	}
+	return doc.Policies, nil
+}
+
+// trustsBuilder reports whether id is one of the policy's builders.
+func (p *Policy) trustsBuilder(id string) bool {
+	return p != nil && id != "" && matchesPattern(p.Builders, id, strings.TrimSpace)
+}
+
+// acceptsSource reports whether the policy accepts source uri.
+func (p *Policy) acceptsSource(uri string) bool {
+	if p == nil || len(p.SourceRepos) == 0 {
+		return true
+	}
+	return uri != "" && matchesPattern(p.SourceRepos, uri, normalizeRepo)
+}
+
+// matchesPattern matches value against patterns after normalising both;
+// a pattern ending in "*" matches by prefix.
+func matchesPattern(patterns []string, value string, normalize func(string) string) bool {
+	value = normalize(value)
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			np := normalize(prefix)
+			// Keep "org/" from matching "org-other".
+			if strings.HasSuffix(prefix, "/") && !strings.HasSuffix(np, "/") {
+				np += "/"
+			}
+			if strings.HasPrefix(value, np) {
+				return true
+			}
+			continue
+		}
+		if normalize(p) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRepo reduces a source URI such as
+// "git+https://github.com/Org/Repo.git@refs/heads/main" to
+// "github.com/org/repo" so policies need not spell out schemes and refs.
+func normalizeRepo(uri string) string {
+	uri = strings.ToLower(strings.TrimSpace(uri))
+	uri = strings.TrimPrefix(uri, "git+")
+	if _, rest, ok := strings.Cut(uri, "://"); ok {
+		uri = rest
+	}
+	// Drop ssh user info ("git@github.com/...") before cutting the ref.
+	if slash := strings.Index(uri, "/"); slash > 0 {
+		if at := strings.Index(uri[:slash], "@"); at >= 0 {
+			uri = uri[at+1:]
+		}
+	}
+	if i := strings.IndexAny(uri, "@#?"); i >= 0 {
+		uri = uri[:i]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(uri, "/"), ".git")
+}
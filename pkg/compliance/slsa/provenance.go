@@ -0,0 +1,239 @@
+package slsa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/signing"
+)
+
+// SLSA provenance predicate types.
+const (
+	predicateProvenanceV02 = "https://slsa.dev/provenance/v0.2"
+	predicateProvenanceV1  = "https://slsa.dev/provenance/v1"
+)
+
+// artifactTypeInToto is the referrer artifact type of bare in-toto
+// attestations; Sigstore bundles are matched by prefix.
+const artifactTypeInToto = "application/vnd.in-toto+json"
+
+// Provenance is the part of an SLSA provenance predicate the engine uses.
+type Provenance struct {
+	PredicateType string
+	BuilderID     string
+	BuildType     string
+	SourceURI     string
+	Reproducible  bool
+	// Source is where it was found: "referrer" or "attestation-tag".
+	Source       string
+	Verification signing.Verification
+}
+
+type inTotoStatement struct {
+	PredicateType string `json:"predicateType"`
+	Subject       []struct {
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+	Predicate json.RawMessage `json:"predicate"`
+}
+
+// provenanceV02 is the subset of an SLSA v0.2 predicate that is read.
+type provenanceV02 struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	BuildType  string `json:"buildType"`
+	Invocation struct {
+		ConfigSource struct {
+			URI string `json:"uri"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+	Metadata struct {
+		Reproducible bool `json:"reproducible"`
+	} `json:"metadata"`
+	Materials []struct {
+		URI string `json:"uri"`
+	} `json:"materials"`
+}
+
+// provenanceV1 is the subset of an SLSA v1 predicate that is read.
+type provenanceV1 struct {
+	BuildDefinition struct {
+		BuildType          string `json:"buildType"`
+		ExternalParameters struct {
+			// GitHub Actions builders (slsa-github-generator and GitHub
+			// artifact attestations) record the calling workflow.
+			Workflow struct {
+				Repository string `json:"repository"`
+			} `json:"workflow"`
+			Source json.RawMessage `json:"source"`
+		} `json:"externalParameters"`
+		ResolvedDependencies []struct {
+			URI string `json:"uri"`
+		} `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+}
+
+// fetchProvenance returns the provenance attestations for ref's digest
+// from OCI referrers and the cosign ".att" tag. It returns nil, nil when the
+// image has none.
+func fetchProvenance(ctx context.Context, client *oci.Client, verifier *signing.Verifier, ref oci.Reference) ([]Provenance, error) {
+	var out []Provenance
+	referrers, err := client.Referrers(ctx, ref.Registry, ref.Repository, ref.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range referrers {
+		if d.ArtifactType != artifactTypeInToto && !strings.HasPrefix(d.ArtifactType, signing.MediaTypeSigstoreBundlePrefix) {
+			continue
+		}
+		manifest, _, err := client.Manifest(ctx, ref.Registry, ref.Repository, d.Digest)
+		if err != nil {
+			return nil, err
+		}
+		found, err := provenanceFromLayers(ctx, client, verifier, ref, manifest.Layers, "referrer")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, found...)
+	}
+
+	manifest, _, err := client.Manifest(ctx, ref.Registry, ref.Repository, oci.AttestationTag(ref.Digest))
+	if err != nil && !errors.Is(err, oci.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		found, err := provenanceFromLayers(ctx, client, verifier, ref, manifest.Layers, "attestation-tag")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, found...)
+	}
+	return out, nil
+}
+
+func provenanceFromLayers(ctx context.Context, client *oci.Client, verifier *signing.Verifier, ref oci.Reference, layers []oci.Descriptor, source string) ([]Provenance, error) {
+	var out []Provenance
+	for _, layer := range layers {
+		// cosign annotates layers with their predicate type, which lets us
+		// skip SBOMs and other attestations without downloading them.
+		if pt := layer.Annotations["predicateType"]; pt != "" && !isProvenance(pt) {
+			continue
+		}
+		blob, err := client.Blob(ctx, ref.Registry, ref.Repository, layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		payload, verification, err := verifier.VerifyAttestation(blob, layer.Annotations)
+		if err != nil {
+			continue
+		}
+		var stmt inTotoStatement
+		if err := json.Unmarshal(payload, &stmt); err != nil || !isProvenance(stmt.PredicateType) || !covers(&stmt, ref.Digest) {
+			continue
+		}
+		p, ok := parsePredicate(stmt.PredicateType, stmt.Predicate)
+		if !ok {
+			continue
+		}
+		p.Source = source
+		p.Verification = verification
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func isProvenance(predicateType string) bool {
+	return strings.HasPrefix(predicateType, "https://slsa.dev/provenance/")
+}
+
+// covers reports whether the statement's subject is digest.
+func covers(stmt *inTotoStatement, digest string) bool {
+	algo, hex, _ := strings.Cut(digest, ":")
+	for _, s := range stmt.Subject {
+		if s.Digest[algo] == hex {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePredicate(predicateType string, raw json.RawMessage) (Provenance, bool) {
+	p := Provenance{PredicateType: predicateType}
+	if predicateType == predicateProvenanceV1 {
+		var v1 provenanceV1
+		if err := json.Unmarshal(raw, &v1); err != nil {
+			return p, false
+		}
+		p.BuilderID = v1.RunDetails.Builder.ID
+		p.BuildType = v1.BuildDefinition.BuildType
+		p.SourceURI = v1.BuildDefinition.ExternalParameters.Workflow.Repository
+		if p.SourceURI == "" {
+			p.SourceURI = externalSource(v1.BuildDefinition.ExternalParameters.Source)
+		}
+		if p.SourceURI == "" {
+			for _, dep := range v1.BuildDefinition.ResolvedDependencies {
+				if strings.HasPrefix(dep.URI, "git+") {
+					p.SourceURI = dep.URI
+					break
+				}
+			}
+		}
+		return p, true
+	}
+	// v0.1 and v0.2 share the fields read here.
+	var v02 provenanceV02
+	if err := json.Unmarshal(raw, &v02); err != nil {
+		return p, false
+	}
+	p.BuilderID = v02.Builder.ID
+	p.BuildType = v02.BuildType
+	p.SourceURI = v02.Invocation.ConfigSource.URI
+	if p.SourceURI == "" && len(v02.Materials) > 0 {
+		p.SourceURI = v02.Materials[0].URI
+	}
+	p.Reproducible = v02.Metadata.Reproducible
+	return p, true
+}
+
+// externalSource reads a build's "source" parameter, which builders record
+// either as a URI string or as a resource descriptor.
+func externalSource(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var d struct {
+		URI string `json:"uri"`
+	}
+	if json.Unmarshal(raw, &d) == nil {
+		return d.URI
+	}
+	return ""
+}
+
+// buildSystem names the CI system behind a builder ID.
+func buildSystem(builderID string) string {
+	id := strings.ToLower(builderID)
+	switch {
+	case id == "":
+		return ""
+	case strings.Contains(id, "github.com"):
+		return "GitHub Actions"
+	case strings.Contains(id, "gitlab"):
+		return "GitLab CI"
+	case strings.Contains(id, "cloudbuild") || strings.Contains(id, "cloud-build"):
+		return "Google Cloud Build"
+	case strings.Contains(id, "tekton"):
+		return "Tekton Chains"
+	}
+	return "Other"
+}
@@ -133,6 +133,7 @@ type MultiClusterClient struct {
 	inClusterName   string               // Detected friendly name for in-cluster (e.g. "fmaas-vllm-d")
 	slowClusters    map[string]time.Time // clusters that recently timed out (reduced timeout)
 	noClusterMode   bool                 // true when no kubeconfig/in-cluster config is available
+	informers       *informerCache       // per-cluster shared informers; nil unless EnableInformerCache was called
 }
 
 // IsInCluster returns true if the server is running inside a Kubernetes cluster
@@ -235,7 +236,11 @@ func (m *MultiClusterClient) InjectRestConfig(contextName string, config *rest.C
 
 // Reload reloads the kubeconfig from disk
 func (m *MultiClusterClient) Reload() error {
-	return m.LoadConfig()
+	if err := m.LoadConfig(); err != nil {
+		return err
+	}
+	m.reconcileInformers()
+	return nil
 }
 
 // HasClusterConfig reports whether the client currently has a readable
@@ -125,6 +125,9 @@ func (m *MultiClusterClient) RemoveContext(contextName string) error {
 	delete(m.cacheTime, contextName)
 
 	m.rawConfig = config
+	if m.informers != nil {
+		m.informers.stopCluster(contextName)
+	}
 	slog.Info("Removed kubeconfig context", "context", contextName)
 	return nil
 }
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"sort"
 	"time"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Event represents a Kubernetes event
@@ -25,28 +23,23 @@ type Event struct {
 
 // GetEvents returns events from a cluster
 func (m *MultiClusterClient) GetEvents(ctx context.Context, contextName, namespace string, limit int, fieldSelectors ...string) ([]Event, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	listOpts := metav1.ListOptions{}
-	if len(fieldSelectors) > 0 && fieldSelectors[0] != "" {
-		listOpts.FieldSelector = fieldSelectors[0]
+	fieldSelector := ""
+	if len(fieldSelectors) > 0 {
+		fieldSelector = fieldSelectors[0]
 	}
-	events, err := client.CoreV1().Events(namespace).List(ctx, listOpts)
+	events, err := m.listEvents(ctx, contextName, namespace, fieldSelector)
 	if err != nil {
 		return nil, err
 	}
 
 	// Sort by effective event time descending (prefers modern EventTime,
 	// falls back to LastTimestamp for older clusters). See issue #6042.
-	sort.Slice(events.Items, func(i, j int) bool {
-		return EffectiveEventTime(&events.Items[i]).After(EffectiveEventTime(&events.Items[j]))
+	sort.Slice(events, func(i, j int) bool {
+		return EffectiveEventTime(&events[i]).After(EffectiveEventTime(&events[j]))
 	})
 
 	var result []Event
-	for i, event := range events.Items {
+	for i, event := range events {
 		if limit > 0 && i >= limit {
 			break
 		}
@@ -76,26 +69,19 @@ func (m *MultiClusterClient) GetEvents(ctx context.Context, contextName, namespa
 
 // GetWarningEvents returns warning events from a cluster
 func (m *MultiClusterClient) GetWarningEvents(ctx context.Context, contextName, namespace string, limit int) ([]Event, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
-		FieldSelector: "type=Warning",
-	})
+	events, err := m.listEvents(ctx, contextName, namespace, "type=Warning")
 	if err != nil {
 		return nil, err
 	}
 
 	// Sort by effective event time descending (prefers modern EventTime,
 	// falls back to LastTimestamp for older clusters). See issue #6042.
-	sort.Slice(events.Items, func(i, j int) bool {
-		return EffectiveEventTime(&events.Items[i]).After(EffectiveEventTime(&events.Items[j]))
+	sort.Slice(events, func(i, j int) bool {
+		return EffectiveEventTime(&events[i]).After(EffectiveEventTime(&events[j]))
 	})
 
 	var result []Event
-	for i, event := range events.Items {
+	for i, event := range events {
 		if limit > 0 && i >= limit {
 			break
 		}
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"strings"
 	"time"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // NodeCondition represents a node condition status
@@ -58,18 +56,13 @@ type FlatcarNodeInfo struct {
 // GetGPUNodes returns nodes with GPU resources
 
 func (m *MultiClusterClient) GetNodes(ctx context.Context, contextName string) ([]NodeInfo, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := m.listNodes(ctx, contextName)
 	if err != nil {
 		return nil, err
 	}
 
 	var nodeInfos []NodeInfo
-	for _, node := range nodes.Items {
+	for _, node := range nodes {
 		info := NodeInfo{
 			Name:           node.Name,
 			Cluster:        contextName,
@@ -245,18 +238,13 @@ func (m *MultiClusterClient) GetNodes(ctx context.Context, contextName string) (
 // in the given cluster. Detection is based on OSImage containing "flatcar"
 // (case-insensitive).
 func (m *MultiClusterClient) GetFlatcarNodes(ctx context.Context, contextName string) ([]FlatcarNodeInfo, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := m.listNodes(ctx, contextName)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []FlatcarNodeInfo
-	for _, node := range nodes.Items {
+	for _, node := range nodes {
 		osImage := node.Status.NodeInfo.OSImage
 		if strings.Contains(strings.ToLower(osImage), "flatcar") {
 			result = append(result, FlatcarNodeInfo{
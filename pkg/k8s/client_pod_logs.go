@@ -0,0 +1,458 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubestellar/console/pkg/safego"
+)
+
+const (
+	// defaultMaxLogContainers caps how many containers one StreamPodLogs
+	// call tails at once, so a broad selector cannot open thousands of
+	// log connections.
+	defaultMaxLogContainers = 50
+	// maxLogLineBytes truncates pathological single log lines.
+	maxLogLineBytes = 64 << 10 // 64 KB
+	// podLogRewatchDelay is the pause before re-listing pods after a watch
+	// ends or fails.
+	podLogRewatchDelay = 2 * time.Second
+)
+
+// PodLogStreamOptions selects the containers StreamPodLogs tails and the
+// lines it emits.
+type PodLogStreamOptions struct {
+	// Clusters are the kubeconfig contexts to tail.
+	Clusters []string
+	// Namespaces restricts pods to these namespaces; empty means all.
+	Namespaces []string
+	// LabelSelector selects pods, e.g. "app=web,tier!=cache".
+	LabelSelector string
+	// Container, when set, tails only containers whose name matches.
+	Container *regexp.Regexp
+	// Include, when set, emits only lines matching at least one pattern.
+	Include []*regexp.Regexp
+	// Exclude drops lines matching any pattern.
+	Exclude []*regexp.Regexp
+	// SinceTime starts each log at this time instead of TailLines back.
+	SinceTime *time.Time
+	// TailLines is how many existing lines each container starts with.
+	TailLines *int64
+	// Previous tails the previous (terminated) instance of each container.
+	Previous bool
+	// Follow keeps streaming and picks up pods as they appear.
+	Follow bool
+	// Timestamps prefixes each line with its RFC 3339 timestamp.
+	Timestamps bool
+	// MaxContainers caps concurrent tails; zero uses defaultMaxLogContainers.
+	MaxContainers int
+}
+
+// PodLogEventType identifies a PodLogEvent.
+type PodLogEventType string
+
+const (
+	PodLogLine        PodLogEventType = "line"
+	PodLogTailStarted PodLogEventType = "tail_started"
+	PodLogTailStopped PodLogEventType = "tail_stopped"
+	PodLogError       PodLogEventType = "error"
+)
+
+// PodLogEvent is one line or lifecycle change of a StreamPodLogs call.
+// Error events with an empty Pod concern the whole cluster.
+type PodLogEvent struct {
+	Type      PodLogEventType `json:"type"`
+	Cluster   string          `json:"cluster"`
+	Namespace string          `json:"namespace,omitempty"`
+	Pod       string          `json:"pod,omitempty"`
+	Container string          `json:"container,omitempty"`
+	Line      string          `json:"line,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// Prefix returns the stern-style "cluster namespace/pod container" prefix
+// identifying where a line came from.
+func (e PodLogEvent) Prefix() string {
+	return e.Cluster + " " + e.Namespace + "/" + e.Pod + " " + e.Container
+}
+
+// StreamPodLogs tails every container of the pods matching opts across
+// opts.Clusters and passes lines and lifecycle events to emit, interleaved
+// as they arrive. emit is never called concurrently. Without Follow it
+// returns once every selected container's log has been read; with Follow
+// it watches for new and restarted pods until ctx is cancelled. Per-cluster
+// and per-container failures are reported as PodLogError events.
+func (m *MultiClusterClient) StreamPodLogs(ctx context.Context, opts PodLogStreamOptions, emit func(PodLogEvent)) error {
+	if len(opts.Clusters) == 0 {
+		return errors.New("at least one cluster is required")
+	}
+	if opts.MaxContainers <= 0 {
+		opts.MaxContainers = defaultMaxLogContainers
+	}
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	s := &podLogStreamer{opts: opts, emit: emit}
+	var wg sync.WaitGroup
+	for _, cluster := range opts.Clusters {
+		client, err := m.GetClient(cluster)
+		if err != nil {
+			s.send(PodLogEvent{Type: PodLogError, Cluster: cluster, Error: err.Error()})
+			continue
+		}
+		for _, ns := range namespaces {
+			t := &clusterLogTailer{
+				streamer:  s,
+				cluster:   cluster,
+				client:    client,
+				lastID:    map[string]string{},
+				droppedAt: map[string]time.Time{},
+				active:    map[string]context.CancelFunc{},
+			}
+			wg.Add(1)
+			safego.GoWith("pod-logs/"+cluster, func() {
+				defer wg.Done()
+				t.run(ctx, ns)
+			})
+		}
+	}
+	wg.Wait()
+	return nil
+}
+
+// podLogStreamer holds the state shared by every cluster of one
+// StreamPodLogs call.
+type podLogStreamer struct {
+	opts    PodLogStreamOptions
+	emitMu  sync.Mutex
+	emit    func(PodLogEvent)
+	tailing atomic.Int32
+}
+
+func (s *podLogStreamer) send(e PodLogEvent) {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+	s.emit(e)
+}
+
+// matchLine applies the include and exclude filters.
+func (s *podLogStreamer) matchLine(line string) bool {
+	for _, re := range s.opts.Exclude {
+		if re.MatchString(line) {
+			return false
+		}
+	}
+	if len(s.opts.Include) == 0 {
+		return true
+	}
+	for _, re := range s.opts.Include {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterLogTailer tails the selected pods of one cluster and namespace.
+type clusterLogTailer struct {
+	streamer *podLogStreamer
+	cluster  string
+	client   kubernetes.Interface
+
+	mu sync.Mutex
+	// lastID is the container ID last tailed per namespace/pod/container,
+	// so a restarted container is tailed again but a running one is not
+	// tailed twice.
+	lastID map[string]string
+	// droppedAt records when a follow stream ended on its own, so a
+	// container that is still running is tailed again from that point.
+	droppedAt map[string]time.Time
+	// active cancels the running tail per namespace/pod/container.
+	active map[string]context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (t *clusterLogTailer) run(ctx context.Context, ns string) {
+	defer t.wg.Wait()
+	opts := t.streamer.opts
+	listOpts := metav1.ListOptions{LabelSelector: opts.LabelSelector}
+	for {
+		pods, err := t.client.CoreV1().Pods(ns).List(ctx, listOpts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.streamer.send(PodLogEvent{Type: PodLogError, Cluster: t.cluster, Namespace: ns, Error: fmt.Sprintf("list pods: %v", err)})
+			if !opts.Follow || !sleepCtx(ctx, podLogRewatchDelay) {
+				return
+			}
+			continue
+		}
+		for i := range pods.Items {
+			t.reconcile(ctx, &pods.Items[i])
+		}
+		if !opts.Follow {
+			return
+		}
+
+		watchOpts := listOpts
+		watchOpts.ResourceVersion = pods.ResourceVersion
+		w, err := t.client.CoreV1().Pods(ns).Watch(ctx, watchOpts)
+		if err == nil {
+			t.watch(ctx, w)
+			w.Stop()
+		}
+		if !sleepCtx(ctx, podLogRewatchDelay) {
+			return
+		}
+	}
+}
+
+// watch applies pod events until the watch ends or ctx is cancelled.
+func (t *clusterLogTailer) watch(ctx context.Context, w watch.Interface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			pod, isPod := ev.Object.(*corev1.Pod)
+			if !isPod {
+				continue
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				t.reconcile(ctx, pod)
+			case watch.Deleted:
+				t.stopPod(pod)
+			}
+		}
+	}
+}
+
+// reconcile starts tails for the pod's containers that should be tailed
+// and are not yet.
+func (t *clusterLogTailer) reconcile(ctx context.Context, pod *corev1.Pod) {
+	opts := t.streamer.opts
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	for _, c := range pod.Spec.Containers {
+		if opts.Container != nil && !opts.Container.MatchString(c.Name) {
+			continue
+		}
+		cs, ok := statuses[c.Name]
+		if !ok {
+			continue
+		}
+		logOpts := corev1.PodLogOptions{
+			Container:  c.Name,
+			Follow:     opts.Follow,
+			Previous:   opts.Previous,
+			Timestamps: opts.Timestamps,
+			TailLines:  opts.TailLines,
+		}
+		if opts.SinceTime != nil {
+			since := metav1.NewTime(*opts.SinceTime)
+			logOpts.SinceTime = &since
+		}
+		id := cs.ContainerID
+		switch {
+		case opts.Previous:
+			// The previous instance's log is complete; read it once.
+			if cs.LastTerminationState.Terminated == nil {
+				continue
+			}
+			logOpts.Follow = false
+			id = cs.LastTerminationState.Terminated.ContainerID
+		case cs.State.Running != nil:
+		case cs.State.Terminated != nil && !opts.Follow:
+		default:
+			continue
+		}
+
+		key := pod.Namespace + "/" + pod.Name + "/" + c.Name
+		t.mu.Lock()
+		_, running := t.active[key]
+		last, seen := t.lastID[key]
+		droppedAt, dropped := t.droppedAt[key]
+		resume := seen && last == id && dropped && cs.State.Running != nil
+		if running || (seen && last == id && !resume) {
+			t.mu.Unlock()
+			continue
+		}
+		switch {
+		case resume:
+			// The stream of a still-running container ended: pick up
+			// where it dropped instead of replaying TailLines.
+			since := metav1.NewTime(droppedAt)
+			logOpts.SinceTime = &since
+			logOpts.TailLines = nil
+		case seen && cs.State.Running != nil:
+			// A restarted container: start at its new instance instead of
+			// replaying TailLines of it.
+			started := cs.State.Running.StartedAt
+			logOpts.SinceTime = &started
+			logOpts.TailLines = nil
+		}
+		if int(t.streamer.tailing.Load()) >= opts.MaxContainers {
+			t.mu.Unlock()
+			t.streamer.send(PodLogEvent{
+				Type: PodLogError, Cluster: t.cluster, Namespace: pod.Namespace, Pod: pod.Name, Container: c.Name,
+				Error: fmt.Sprintf("not tailed: more than %d containers selected; narrow the selector", opts.MaxContainers),
+			})
+			continue
+		}
+		t.streamer.tailing.Add(1)
+		tailCtx, cancel := context.WithCancel(ctx)
+		t.active[key] = cancel
+		t.lastID[key] = id
+		delete(t.droppedAt, key)
+		t.mu.Unlock()
+
+		ev := PodLogEvent{Cluster: t.cluster, Namespace: pod.Namespace, Pod: pod.Name, Container: c.Name}
+		t.wg.Add(1)
+		safego.GoWith("pod-logs/"+t.cluster+"/"+key, func() {
+			defer t.wg.Done()
+			dropped := false
+			defer func() {
+				t.mu.Lock()
+				delete(t.active, key)
+				if dropped {
+					t.droppedAt[key] = time.Now()
+				}
+				t.mu.Unlock()
+				t.streamer.tailing.Add(-1)
+				cancel()
+				if dropped {
+					t.retail(ctx, ev.Namespace, ev.Pod)
+				}
+			}()
+			t.tail(tailCtx, ev, logOpts)
+			// A follow stream that ends while still wanted was closed by
+			// the apiserver or kubelet, or its container exited.
+			dropped = logOpts.Follow && tailCtx.Err() == nil
+		})
+	}
+}
+
+// stopPod cancels the tails of a deleted pod.
+func (t *clusterLogTailer) stopPod(pod *corev1.Pod) {
+	prefix := pod.Namespace + "/" + pod.Name + "/"
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, cancel := range t.active {
+		if strings.HasPrefix(key, prefix) {
+			cancel()
+		}
+	}
+	for key := range t.lastID {
+		if strings.HasPrefix(key, prefix) {
+			delete(t.lastID, key)
+			delete(t.droppedAt, key)
+		}
+	}
+}
+
+// retail re-reads a pod after one of its follow streams ended and resumes
+// the containers that are still running. Pod events alone would miss a
+// stream dropped on a container whose status never changed.
+func (t *clusterLogTailer) retail(ctx context.Context, namespace, name string) {
+	if !sleepCtx(ctx, podLogRewatchDelay) {
+		return
+	}
+	pod, err := t.client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		// Deleted or unreachable: the watch loop handles both.
+		return
+	}
+	t.reconcile(ctx, pod)
+}
+
+// tail streams one container's log, emitting the lines that pass the
+// filters.
+func (t *clusterLogTailer) tail(ctx context.Context, ev PodLogEvent, logOpts corev1.PodLogOptions) {
+	s := t.streamer
+	started := ev
+	started.Type = PodLogTailStarted
+	s.send(started)
+	defer func() {
+		stopped := ev
+		stopped.Type = PodLogTailStopped
+		s.send(stopped)
+	}()
+
+	stream, err := t.client.CoreV1().Pods(ev.Namespace).GetLogs(ev.Pod, &logOpts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			failed := ev
+			failed.Type = PodLogError
+			failed.Error = err.Error()
+			s.send(failed)
+		}
+		return
+	}
+	defer stream.Close()
+
+	r := bufio.NewReaderSize(stream, maxLogLineBytes)
+	for {
+		line, err := readLogLine(r)
+		// Blank lines are forwarded as-is; only the empty remainder after
+		// the final newline is dropped.
+		if (err == nil || line != "") && s.matchLine(line) {
+			out := ev
+			out.Type = PodLogLine
+			out.Line = line
+			s.send(out)
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				failed := ev
+				failed.Type = PodLogError
+				failed.Error = err.Error()
+				s.send(failed)
+			}
+			return
+		}
+	}
+}
+
+// readLogLine reads one line without its line ending, truncating lines
+// longer than the reader's buffer and discarding the rest of them.
+func readLogLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	out := strings.TrimRight(string(line), "\r\n")
+	for errors.Is(err, bufio.ErrBufferFull) {
+		_, err = r.ReadSlice('\n')
+	}
+	return out, err
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
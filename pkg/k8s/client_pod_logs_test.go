@@ -0,0 +1,241 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func logTestPod(ns, name, app string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{"app": app}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c, Image: "img"})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:        c,
+			ContainerID: "containerd://" + name + "-" + c,
+			State:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now()}},
+		})
+	}
+	return pod
+}
+
+// logEventRecorder collects the events of a StreamPodLogs call.
+type logEventRecorder struct {
+	mu     sync.Mutex
+	events []PodLogEvent
+}
+
+func (r *logEventRecorder) emit(e PodLogEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *logEventRecorder) lines() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := map[string]int{}
+	for _, e := range r.events {
+		if e.Type == PodLogLine {
+			out[e.Prefix()]++
+		}
+	}
+	return out
+}
+
+func TestStreamPodLogs_SelectorAcrossClusters(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		logTestPod("default", "web-1", "web", "app", "sidecar"),
+		logTestPod("default", "db-1", "db", "postgres"),
+	)
+	m.clients["c2"] = k8sfake.NewSimpleClientset(logTestPod("prod", "web-2", "web", "app"))
+
+	rec := &logEventRecorder{}
+	err := m.StreamPodLogs(context.Background(), PodLogStreamOptions{
+		Clusters:      []string{"c1", "c2", "missing"},
+		LabelSelector: "app=web",
+		Container:     regexp.MustCompile("^app$"),
+	}, rec.emit)
+	if err != nil {
+		t.Fatalf("StreamPodLogs: %v", err)
+	}
+
+	// The fake clientset serves "fake logs" for every container.
+	lines := rec.lines()
+	want := map[string]int{"c1 default/web-1 app": 1, "c2 prod/web-2 app": 1}
+	if len(lines) != len(want) {
+		t.Fatalf("lines = %v, want %v", lines, want)
+	}
+	for prefix, n := range want {
+		if lines[prefix] != n {
+			t.Errorf("lines[%q] = %d, want %d", prefix, lines[prefix], n)
+		}
+	}
+
+	var clusterErr bool
+	for _, e := range rec.events {
+		if e.Type == PodLogError && e.Cluster == "missing" {
+			clusterErr = true
+		}
+	}
+	if !clusterErr {
+		t.Error("expected an error event for the unknown cluster")
+	}
+}
+
+func TestStreamPodLogs_IncludeExclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []*regexp.Regexp
+		exclude []*regexp.Regexp
+		want    int
+	}{
+		{name: "no filters", want: 1},
+		{name: "include matches", include: []*regexp.Regexp{regexp.MustCompile("fake")}, want: 1},
+		{name: "include misses", include: []*regexp.Regexp{regexp.MustCompile("ERROR")}, want: 0},
+		{name: "exclude wins", include: []*regexp.Regexp{regexp.MustCompile("fake")}, exclude: []*regexp.Regexp{regexp.MustCompile("logs$")}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := NewMultiClusterClient("")
+			m.clients["c1"] = k8sfake.NewSimpleClientset(logTestPod("default", "web-1", "web", "app"))
+
+			rec := &logEventRecorder{}
+			if err := m.StreamPodLogs(context.Background(), PodLogStreamOptions{
+				Clusters: []string{"c1"},
+				Include:  tt.include,
+				Exclude:  tt.exclude,
+			}, rec.emit); err != nil {
+				t.Fatalf("StreamPodLogs: %v", err)
+			}
+			if got := rec.lines()["c1 default/web-1 app"]; got != tt.want {
+				t.Errorf("lines = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamPodLogs_FollowPicksUpNewPods(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	fake := k8sfake.NewSimpleClientset(logTestPod("default", "web-1", "web", "app"))
+	m.clients["c1"] = fake
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := &logEventRecorder{}
+	done := make(chan error, 1)
+	go func() {
+		done <- m.StreamPodLogs(ctx, PodLogStreamOptions{
+			Clusters:      []string{"c1"},
+			LabelSelector: "app=web",
+			Follow:        true,
+		}, rec.emit)
+	}()
+
+	waitFor := func(prefix string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if rec.lines()[prefix] > 0 {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("no lines from %s; events: %+v", prefix, rec.events)
+	}
+	waitFor("c1 default/web-1 app")
+
+	// The fake watch only delivers events sent after it is established, so
+	// recreate the pod until the tailer notices it.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && rec.lines()["c1 default/web-2 app"] == 0 {
+		_ = fake.CoreV1().Pods("default").Delete(ctx, "web-2", metav1.DeleteOptions{})
+		_, _ = fake.CoreV1().Pods("default").Create(ctx, logTestPod("default", "web-2", "web", "app"), metav1.CreateOptions{})
+		time.Sleep(50 * time.Millisecond)
+	}
+	waitFor("c1 default/web-2 app")
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("StreamPodLogs: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StreamPodLogs did not return after cancellation")
+	}
+}
+
+func TestStreamPodLogs_FollowRetailsDroppedStream(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.clients["c1"] = k8sfake.NewSimpleClientset(logTestPod("default", "web-1", "web", "app"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := &logEventRecorder{}
+	done := make(chan error, 1)
+	go func() {
+		done <- m.StreamPodLogs(ctx, PodLogStreamOptions{
+			Clusters: []string{"c1"},
+			Follow:   true,
+		}, rec.emit)
+	}()
+
+	// The fake log stream ends right after "fake logs" although the
+	// container keeps running, so it must be tailed again.
+	const prefix = "c1 default/web-1 app"
+	deadline := time.Now().Add(3 * podLogRewatchDelay)
+	for time.Now().Before(deadline) && rec.lines()[prefix] < 2 {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := rec.lines()[prefix]; got < 2 {
+		t.Errorf("lines = %d, want the dropped stream re-tailed", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("StreamPodLogs: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StreamPodLogs did not return after cancellation")
+	}
+}
+
+func TestStreamPodLogs_ForwardsBlankLines(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	fake := k8sfake.NewSimpleClientset(logTestPod("default", "web-1", "web", "app"))
+	fake.PrependReactor("get", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "log" {
+			return false, nil, nil
+		}
+		return true, &runtime.Unknown{Raw: []byte("first\n\nthird\n")}, nil
+	})
+	m.clients["c1"] = fake
+
+	rec := &logEventRecorder{}
+	if err := m.StreamPodLogs(context.Background(), PodLogStreamOptions{Clusters: []string{"c1"}}, rec.emit); err != nil {
+		t.Fatalf("StreamPodLogs: %v", err)
+	}
+	var got []string
+	for _, e := range rec.events {
+		if e.Type == PodLogLine {
+			got = append(got, e.Line)
+		}
+	}
+	if want := []string{"first", "", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
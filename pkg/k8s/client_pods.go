@@ -7,7 +7,6 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // PodInfo represents pod information
@@ -114,18 +113,13 @@ func getPrimaryPodIssue(issues []string, fallback string) string {
 }
 
 func (m *MultiClusterClient) GetPods(ctx context.Context, contextName, namespace string) ([]PodInfo, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	pods, err := m.listPods(ctx, contextName, namespace)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []PodInfo
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		ready := 0
 		total := len(pod.Spec.Containers)
 		restarts := 0
@@ -195,12 +189,7 @@ func (m *MultiClusterClient) GetPods(ctx context.Context, contextName, namespace
 
 // FindPodIssues returns pods with issues
 func (m *MultiClusterClient) FindPodIssues(ctx context.Context, contextName, namespace string) ([]PodIssue, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	pods, err := m.listPods(ctx, contextName, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +209,7 @@ func (m *MultiClusterClient) FindPodIssues(ctx context.Context, contextName, nam
 	now := time.Now()
 
 	var issues []PodIssue
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		// Skip completed/succeeded pods (e.g. finished Jobs)
 		if pod.Status.Phase == corev1.PodSucceeded {
 			continue
@@ -108,7 +108,7 @@ func (m *MultiClusterClient) GetServices(ctx context.Context, contextName, names
 		return nil, err
 	}
 
-	services, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	services, err := m.listServices(ctx, contextName, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +136,7 @@ func (m *MultiClusterClient) GetServices(ctx context.Context, contextName, names
 	}
 
 	var result []Service
-	for _, svc := range services.Items {
+	for _, svc := range services {
 		// Build ports list. We populate both the legacy flat []string
 		// form (existing consumers) and the structured PortDetails form
 		// which preserves the port Name (issue #6163).
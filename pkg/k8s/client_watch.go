@@ -101,6 +101,7 @@ func (m *MultiClusterClient) reloadAndNotify() {
 	if err := m.LoadConfig(); err != nil {
 		if errors.Is(err, ErrNoClusterConfigured) {
 			slog.Warn("kubeconfig unavailable; entering no-cluster state", "path", m.kubeconfig)
+			m.reconcileInformers()
 		} else {
 			slog.Error("error reloading kubeconfig", "error", err)
 		}
@@ -146,6 +147,10 @@ func (m *MultiClusterClient) reloadAndNotify() {
 	}
 	m.mu.Unlock()
 
+	// Start informers for new contexts and stop those for removed ones
+	// before listeners refetch.
+	m.reconcileInformers()
+
 	// Notify listeners
 	m.mu.RLock()
 	callback := m.onReload
@@ -121,18 +121,13 @@ type CronJob struct {
 
 // FindDeploymentIssues returns deployments with issues
 func (m *MultiClusterClient) FindDeploymentIssues(ctx context.Context, contextName, namespace string) ([]DeploymentIssue, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	deployments, err := m.listDeployments(ctx, contextName, namespace)
 	if err != nil {
 		return nil, err
 	}
 
 	var issues []DeploymentIssue
-	for _, deploy := range deployments.Items {
+	for _, deploy := range deployments {
 		// Check for issues
 		var reason, message string
 
@@ -189,18 +184,13 @@ func (m *MultiClusterClient) FindDeploymentIssues(ctx context.Context, contextNa
 
 // GetDeployments returns all deployments with rollout status
 func (m *MultiClusterClient) GetDeployments(ctx context.Context, contextName, namespace string) ([]Deployment, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return nil, err
-	}
-
-	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	deployments, err := m.listDeployments(ctx, contextName, namespace)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []Deployment
-	for _, deploy := range deployments.Items {
+	for _, deploy := range deployments {
 		// Kubernetes defaults Replicas to 1 when unset
 		desired := int32(1)
 		if deploy.Spec.Replicas != nil {
@@ -0,0 +1,323 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubestellar/console/pkg/compliance/frameworks"
+)
+
+// clusterAdminRole is the built-in superuser ClusterRole.
+const clusterAdminRole = "cluster-admin"
+
+// runtimeSecurityTools are the DaemonSet name fragments that identify a
+// runtime security monitor.
+var runtimeSecurityTools = []string{"falco", "tetragon", "tracee", "kubearmor"}
+
+// ProvenanceSource reports SLSA provenance coverage for a cluster. The live
+// slsa.Engine implements it.
+type ProvenanceSource interface {
+	ImageProvenance(ctx context.Context, cluster string) (total, attested, verified int, err error)
+}
+
+// frameworksProber implements frameworks.ClusterProber with the multi-cluster
+// client. Control-plane flags are read the same way as for HIPAA.
+type frameworksProber struct {
+	m          *MultiClusterClient
+	apiServer  *hipaaProber
+	provenance ProvenanceSource
+}
+
+// NewFrameworksProber returns a frameworks.ClusterProber that reads cluster
+// state through m. provenance may be nil, in which case provenance checks are
+// skipped. Image vulnerability checks are skipped: the console has no
+// fleet-wide scan results to read.
+func NewFrameworksProber(m *MultiClusterClient, provenance ProvenanceSource) frameworks.ClusterProber {
+	return &frameworksProber{m: m, apiServer: &hipaaProber{m: m}, provenance: provenance}
+}
+
+func (p *frameworksProber) HasNetworkPolicies(ctx context.Context, cluster string) (covered, total int, err error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return 0, 0, err
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	policies, err := client.NetworkingV1().NetworkPolicies("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	withPolicy := make(map[string]bool)
+	for _, np := range policies.Items {
+		withPolicy[np.Namespace] = true
+	}
+	for _, ns := range namespaces.Items {
+		if isSystemNamespace(ns.Name) {
+			continue
+		}
+		total++
+		if withPolicy[ns.Name] {
+			covered++
+		}
+	}
+	return covered, total, nil
+}
+
+func (p *frameworksProber) HasDefaultDenyIngress(ctx context.Context, cluster string) (bool, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return false, err
+	}
+	policies, err := client.NetworkingV1().NetworkPolicies("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, np := range policies.Items {
+		if isSystemNamespace(np.Namespace) {
+			continue
+		}
+		if isDefaultDenyIngress(np.Spec) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isDefaultDenyIngress reports whether spec selects every pod and admits no
+// ingress traffic.
+func isDefaultDenyIngress(spec networkingv1.NetworkPolicySpec) bool {
+	if len(spec.PodSelector.MatchLabels) > 0 || len(spec.PodSelector.MatchExpressions) > 0 {
+		return false
+	}
+	if len(spec.Ingress) > 0 {
+		return false
+	}
+	// An empty policyTypes list implies Ingress.
+	if len(spec.PolicyTypes) == 0 {
+		return true
+	}
+	for _, t := range spec.PolicyTypes {
+		if t == networkingv1.PolicyTypeIngress {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *frameworksProber) PodSecurityIssues(ctx context.Context, cluster string) (privileged, root, hostNet int, err error) {
+	pods, err := p.workloadPods(ctx, cluster)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork {
+			hostNet++
+		}
+		if podIsPrivileged(pod) {
+			privileged++
+		}
+		if podMayRunAsRoot(pod) {
+			root++
+		}
+	}
+	return privileged, root, hostNet, nil
+}
+
+func podIsPrivileged(pod corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+			return true
+		}
+	}
+	return false
+}
+
+// podMayRunAsRoot reports whether any container runs as UID 0 or is not
+// constrained to a non-root user by either its own or the pod's security
+// context.
+func podMayRunAsRoot(pod corev1.Pod) bool {
+	podSC := pod.Spec.SecurityContext
+	for _, c := range pod.Spec.Containers {
+		var runAsUser *int64
+		var runAsNonRoot *bool
+		if podSC != nil {
+			runAsUser, runAsNonRoot = podSC.RunAsUser, podSC.RunAsNonRoot
+		}
+		if sc := c.SecurityContext; sc != nil {
+			if sc.RunAsUser != nil {
+				runAsUser = sc.RunAsUser
+			}
+			if sc.RunAsNonRoot != nil {
+				runAsNonRoot = sc.RunAsNonRoot
+			}
+		}
+		if runAsUser != nil {
+			if *runAsUser == 0 {
+				return true
+			}
+			continue
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *frameworksProber) ServiceAccountAutoMount(ctx context.Context, cluster string) (automounted, total int, err error) {
+	pods, err := p.workloadPods(ctx, cluster)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, pod := range pods {
+		total++
+		sa := pod.Spec.ServiceAccountName
+		if sa != "" && sa != "default" {
+			continue
+		}
+		if pod.Spec.AutomountServiceAccountToken == nil || *pod.Spec.AutomountServiceAccountToken {
+			automounted++
+		}
+	}
+	return automounted, total, nil
+}
+
+func (p *frameworksProber) EncryptionAtRestEnabled(ctx context.Context, cluster string) (bool, error) {
+	return p.apiServer.EncryptionAtRestEnabled(ctx, cluster)
+}
+
+func (p *frameworksProber) AuditLoggingEnabled(ctx context.Context, cluster string) (bool, error) {
+	return p.apiServer.AuditLoggingEnabled(ctx, cluster)
+}
+
+func (p *frameworksProber) AuthProviderConfigured(ctx context.Context, cluster string) (bool, error) {
+	flags, err := p.apiServer.apiServerFlags(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	return flags["--oidc-issuer-url"] != "" ||
+		flags["--authentication-token-webhook-config-file"] != "" ||
+		flags["--authentication-config"] != "", nil
+}
+
+// ImageVulnerabilities reports no scanned images, so the checks are skipped.
+func (p *frameworksProber) ImageVulnerabilities(_ context.Context, _ string) (total, critical, high int, err error) {
+	return 0, 0, 0, nil
+}
+
+func (p *frameworksProber) ImageProvenance(ctx context.Context, cluster string) (total, attested, verified int, err error) {
+	if p.provenance == nil {
+		return 0, 0, 0, nil
+	}
+	return p.provenance.ImageProvenance(ctx, cluster)
+}
+
+func (p *frameworksProber) ClusterAdminBindings(ctx context.Context, cluster string) (int, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return 0, err
+	}
+	bindings, err := client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, b := range bindings.Items {
+		if b.RoleRef.Kind == "ClusterRole" && b.RoleRef.Name == clusterAdminRole && !strings.HasPrefix(b.Name, "system:") {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (p *frameworksProber) WildcardRBACRules(ctx context.Context, cluster string) (int, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return 0, err
+	}
+	clusterRoles, err := client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	roles, err := client.RbacV1().Roles("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, cr := range clusterRoles.Items {
+		// Built-in roles are wildcarded by design.
+		if cr.Name == clusterAdminRole || strings.HasPrefix(cr.Name, "system:") {
+			continue
+		}
+		for _, rule := range cr.Rules {
+			if hasWildcard(rule.Verbs) || hasWildcard(rule.Resources) {
+				count++
+			}
+		}
+	}
+	for _, r := range roles.Items {
+		if isSystemNamespace(r.Namespace) {
+			continue
+		}
+		for _, rule := range r.Rules {
+			if hasWildcard(rule.Verbs) || hasWildcard(rule.Resources) {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func hasWildcard(values []string) bool {
+	for _, v := range values {
+		if v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *frameworksProber) RuntimeSecurityInstalled(ctx context.Context, cluster string) (bool, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return false, err
+	}
+	daemonSets, err := client.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, ds := range daemonSets.Items {
+		name := strings.ToLower(ds.Name)
+		for _, tool := range runtimeSecurityTools {
+			if strings.Contains(name, tool) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// workloadPods lists the pods outside the system namespaces.
+func (p *frameworksProber) workloadPods(ctx context.Context, cluster string) ([]corev1.Pod, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]corev1.Pod, 0, len(list.Items))
+	for _, pod := range list.Items {
+		if !isSystemNamespace(pod.Namespace) {
+			out = append(out, pod)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/compliance/frameworks"
+)
+
+type fakeProvenance struct{ total, attested, verified int }
+
+func (f fakeProvenance) ImageProvenance(context.Context, string) (int, int, int, error) {
+	return f.total, f.attested, f.verified, nil
+}
+
+func TestFrameworksProber(t *testing.T) {
+	privileged := true
+	nonRoot := true
+	noMount := false
+	m, _ := NewMultiClusterClient("")
+	m.clients["c1"] = fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-cp", Namespace: "kube-system", Labels: map[string]string{"component": "kube-apiserver"}},
+			Spec: corev1.PodSpec{HostNetwork: true, Containers: []corev1.Container{{
+				Name:    "kube-apiserver",
+				Command: []string{"kube-apiserver", "--oidc-issuer-url=https://issuer.example.com", "--audit-policy-file=/etc/kubernetes/audit.yaml", "--audit-log-path=/var/log/audit.log"},
+			}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments"},
+			Spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &nonRoot},
+				Containers:      []corev1.Container{{Name: "api"}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "web"},
+			Spec: corev1.PodSpec{
+				HostNetwork:                  true,
+				ServiceAccountName:           "agent",
+				AutomountServiceAccountToken: &noMount,
+				Containers: []corev1.Container{{
+					Name:            "agent",
+					SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+				}},
+			},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-deny", Namespace: "payments"},
+			Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "system:masters-admin"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ops-admin"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, Resources: []string{"*"}}},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci-deployer"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, Resources: []string{"deployments"}}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "web"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get"}, Resources: []string{"pods"}}},
+		},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "falco", Namespace: "falco"}},
+	)
+	p := NewFrameworksProber(m, fakeProvenance{total: 3, attested: 2, verified: 1})
+	ctx := context.Background()
+
+	if covered, total, err := p.HasNetworkPolicies(ctx, "c1"); err != nil || covered != 1 || total != 2 {
+		t.Errorf("HasNetworkPolicies = %d/%d, %v; want 1/2", covered, total, err)
+	}
+	if ok, err := p.HasDefaultDenyIngress(ctx, "c1"); err != nil || !ok {
+		t.Errorf("HasDefaultDenyIngress = %v, %v; want true", ok, err)
+	}
+	// The API server pod is in kube-system and is not counted.
+	if priv, root, hostNet, err := p.PodSecurityIssues(ctx, "c1"); err != nil || priv != 1 || root != 1 || hostNet != 1 {
+		t.Errorf("PodSecurityIssues = %d/%d/%d, %v; want 1/1/1", priv, root, hostNet, err)
+	}
+	if mounted, total, err := p.ServiceAccountAutoMount(ctx, "c1"); err != nil || mounted != 1 || total != 2 {
+		t.Errorf("ServiceAccountAutoMount = %d/%d, %v; want 1/2", mounted, total, err)
+	}
+	if ok, err := p.AuthProviderConfigured(ctx, "c1"); err != nil || !ok {
+		t.Errorf("AuthProviderConfigured = %v, %v; want true", ok, err)
+	}
+	if ok, err := p.AuditLoggingEnabled(ctx, "c1"); err != nil || !ok {
+		t.Errorf("AuditLoggingEnabled = %v, %v; want true", ok, err)
+	}
+	if ok, err := p.EncryptionAtRestEnabled(ctx, "c1"); err != nil || ok {
+		t.Errorf("EncryptionAtRestEnabled = %v, %v; want false", ok, err)
+	}
+	if n, err := p.ClusterAdminBindings(ctx, "c1"); err != nil || n != 1 {
+		t.Errorf("ClusterAdminBindings = %d, %v; want 1", n, err)
+	}
+	if n, err := p.WildcardRBACRules(ctx, "c1"); err != nil || n != 1 {
+		t.Errorf("WildcardRBACRules = %d, %v; want 1", n, err)
+	}
+	if ok, err := p.RuntimeSecurityInstalled(ctx, "c1"); err != nil || !ok {
+		t.Errorf("RuntimeSecurityInstalled = %v, %v; want true", ok, err)
+	}
+	if total, attested, verified, err := p.ImageProvenance(ctx, "c1"); err != nil || total != 3 || attested != 2 || verified != 1 {
+		t.Errorf("ImageProvenance = %d/%d/%d, %v; want 3/2/1", total, attested, verified, err)
+	}
+
+	// The evaluator runs every check against the live prober.
+	res, err := frameworks.NewEvaluator(p).Evaluate(ctx, *frameworks.GetFramework("soc2-type2"), "c1")
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if res.ClusterName != "c1" || len(res.Controls) == 0 || res.Errors != 0 {
+		t.Errorf("unexpected evaluation: %+v", res)
+	}
+}
+
+func TestFrameworksProberWithoutProvenance(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.clients["c1"] = fake.NewSimpleClientset()
+	p := NewFrameworksProber(m, nil)
+	if total, _, _, err := p.ImageProvenance(context.Background(), "c1"); err != nil || total != 0 {
+		t.Errorf("ImageProvenance = %d, %v; want 0 (skipped)", total, err)
+	}
+	// No control-plane pod is visible on managed clusters.
+	if _, err := p.AuthProviderConfigured(context.Background(), "c1"); err == nil {
+		t.Error("AuthProviderConfigured should fail when the API server is not observable")
+	}
+}
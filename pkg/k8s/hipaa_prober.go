@@ -0,0 +1,284 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kubestellar/console/pkg/compliance/hipaa"
+)
+
+// apiServerSelector matches the static kube-apiserver pods that kubeadm,
+// kind and k3d-style clusters run in kube-system. Managed control planes
+// (EKS, GKE, AKS) do not expose them, so control-plane checks are reported
+// as not observable there.
+const apiServerSelector = "component=kube-apiserver"
+
+// namespaceNameLabel is set on every namespace by the API server (1.21+)
+// and is how NetworkPolicy namespaceSelectors usually name a namespace.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// hipaaProber implements hipaa.Prober with the multi-cluster client.
+type hipaaProber struct {
+	m *MultiClusterClient
+}
+
+// NewHIPAAProber returns a hipaa.Prober that reads cluster state through m.
+func NewHIPAAProber(m *MultiClusterClient) hipaa.Prober {
+	return &hipaaProber{m: m}
+}
+
+func (p *hipaaProber) Namespaces(ctx context.Context, cluster string) ([]hipaa.NamespaceInfo, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]hipaa.NamespaceInfo, 0, len(list.Items))
+	for _, ns := range list.Items {
+		out = append(out, hipaa.NamespaceInfo{Name: ns.Name, Labels: ns.Labels, Annotations: ns.Annotations})
+	}
+	return out, nil
+}
+
+func (p *hipaaProber) EncryptionAtRestEnabled(ctx context.Context, cluster string) (bool, error) {
+	flags, err := p.apiServerFlags(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	return flags["--encryption-provider-config"] != "", nil
+}
+
+func (p *hipaaProber) AuditLoggingEnabled(ctx context.Context, cluster string) (bool, error) {
+	flags, err := p.apiServerFlags(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	if flags["--audit-policy-file"] == "" {
+		return false, nil
+	}
+	return flags["--audit-log-path"] != "" || flags["--audit-webhook-config-file"] != "", nil
+}
+
+// apiServerFlags returns the flags of the first kube-apiserver pod found in
+// kube-system, or hipaa.ErrNotObservable when there is none.
+func (p *hipaaProber) apiServerFlags(ctx context.Context, cluster string) (map[string]string, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{LabelSelector: apiServerSelector})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		for _, c := range pod.Spec.Containers {
+			if c.Name != "kube-apiserver" {
+				continue
+			}
+			return parseFlags(append(append([]string{}, c.Command...), c.Args...)), nil
+		}
+	}
+	return nil, hipaa.ErrNotObservable
+}
+
+// parseFlags turns "--name=value" and "--name value" arguments into a map.
+// Flags without a value map to "true".
+func parseFlags(args []string) map[string]string {
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				value = args[i]
+			}
+		}
+		flags[name] = value
+	}
+	return flags
+}
+
+func (p *hipaaProber) NetworkPolicies(ctx context.Context, cluster, namespace string) ([]hipaa.NetworkPolicyInfo, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]hipaa.NetworkPolicyInfo, 0, len(list.Items))
+	for _, np := range list.Items {
+		info := hipaa.NetworkPolicyInfo{
+			Name:           np.Name,
+			SelectsAllPods: len(np.Spec.PodSelector.MatchLabels) == 0 && len(np.Spec.PodSelector.MatchExpressions) == 0,
+		}
+		for _, t := range np.Spec.PolicyTypes {
+			info.PolicyTypes = append(info.PolicyTypes, string(t))
+		}
+		// policyTypes defaults to Ingress when omitted.
+		if len(info.PolicyTypes) == 0 {
+			info.PolicyTypes = []string{string(networkingv1.PolicyTypeIngress)}
+		}
+		for _, rule := range np.Spec.Ingress {
+			info.IngressRules = append(info.IngressRules, networkPolicyRule(namespace, rule, namespaces.Items))
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+// networkPolicyRule flattens one ingress rule, resolving namespaceSelectors
+// against the cluster's namespaces.
+func networkPolicyRule(namespace string, rule networkingv1.NetworkPolicyIngressRule, namespaces []corev1.Namespace) hipaa.NetworkPolicyRule {
+	var r hipaa.NetworkPolicyRule
+	for _, port := range rule.Ports {
+		proto := string(corev1.ProtocolTCP)
+		if port.Protocol != nil {
+			proto = string(*port.Protocol)
+		}
+		if port.Port == nil {
+			r.Ports = append(r.Ports, proto+"/*")
+			continue
+		}
+		r.Ports = append(r.Ports, proto+"/"+port.Port.String())
+	}
+	if len(rule.From) == 0 {
+		r.FromAnywhere = true
+		return r
+	}
+	seen := make(map[string]bool)
+	addNS := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			r.FromNamespaces = append(r.FromNamespaces, name)
+		}
+	}
+	for _, peer := range rule.From {
+		switch {
+		case peer.IPBlock != nil:
+			r.AllNamespaces = true
+		case peer.NamespaceSelector == nil:
+			addNS(namespace)
+		default:
+			sel, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+			if err != nil || sel.Empty() {
+				r.AllNamespaces = true
+				continue
+			}
+			for _, ns := range namespaces {
+				nsLabels := labels.Set(ns.Labels)
+				if _, ok := ns.Labels[namespaceNameLabel]; !ok {
+					nsLabels = labels.Merge(nsLabels, labels.Set{namespaceNameLabel: ns.Name})
+				}
+				if sel.Matches(nsLabels) {
+					addNS(ns.Name)
+				}
+			}
+		}
+	}
+	return r
+}
+
+func (p *hipaaProber) Services(ctx context.Context, cluster, namespace string) ([]hipaa.ServiceInfo, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]hipaa.ServiceInfo, 0, len(list.Items))
+	for _, svc := range list.Items {
+		// ExternalName services are DNS aliases and carry no traffic.
+		if svc.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		info := hipaa.ServiceInfo{Name: svc.Name, Type: string(svc.Spec.Type), Annotations: svc.Annotations}
+		for _, port := range svc.Spec.Ports {
+			sp := hipaa.ServicePort{Name: port.Name, Port: port.Port, Protocol: string(port.Protocol)}
+			if port.AppProtocol != nil {
+				sp.AppProtocol = *port.AppProtocol
+			}
+			info.Ports = append(info.Ports, sp)
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+func (p *hipaaProber) Ingresses(ctx context.Context, cluster, namespace string) ([]hipaa.IngressInfo, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]hipaa.IngressInfo, 0, len(list.Items))
+	for _, ing := range list.Items {
+		info := hipaa.IngressInfo{Name: ing.Name, TLS: len(ing.Spec.TLS) > 0, Annotations: ing.Annotations}
+		seen := make(map[string]bool)
+		addSvc := func(b *networkingv1.IngressBackend) {
+			if b == nil || b.Service == nil || seen[b.Service.Name] {
+				return
+			}
+			seen[b.Service.Name] = true
+			info.Services = append(info.Services, b.Service.Name)
+		}
+		addSvc(ing.Spec.DefaultBackend)
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for i := range rule.HTTP.Paths {
+				addSvc(&rule.HTTP.Paths[i].Backend)
+			}
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+func (p *hipaaProber) RoleBindings(ctx context.Context, cluster, namespace string) ([]hipaa.RoleBindingInfo, error) {
+	client, err := p.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]hipaa.RoleBindingInfo, 0, len(list.Items))
+	for _, rb := range list.Items {
+		info := hipaa.RoleBindingInfo{Name: rb.Name, RoleName: rb.RoleRef.Name}
+		for _, s := range rb.Subjects {
+			info.Subjects = append(info.Subjects, s.Kind+":"+s.Name)
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
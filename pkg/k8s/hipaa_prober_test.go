@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/compliance/hipaa"
+)
+
+func TestHIPAAProber(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.clients["c1"] = fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "patients", Labels: map[string]string{"hipaa-phi": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Labels: map[string]string{"role": "edge"}}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-cp", Namespace: "kube-system", Labels: map[string]string{"component": "kube-apiserver"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:    "kube-apiserver",
+				Command: []string{"kube-apiserver", "--encryption-provider-config=/etc/kubernetes/enc.yaml", "--audit-policy-file=/etc/kubernetes/audit.yaml"},
+			}}},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-deny", Namespace: "patients"},
+			Spec:       networkingv1.NetworkPolicySpec{},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "from-edge", Namespace: "patients"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "edge"}}}},
+				}},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "portal", Namespace: "patients"},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{{Hosts: []string{"portal.example.com"}}},
+				Rules: []networkingv1.IngressRule{{IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api"}}}},
+				}}}},
+			},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "everyone", Namespace: "patients"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbacv1.Subject{{Kind: "Group", Name: "system:authenticated"}},
+		},
+	)
+	p := NewHIPAAProber(m)
+	ctx := context.Background()
+
+	nss, err := p.Namespaces(ctx, "c1")
+	if err != nil || len(nss) != 2 {
+		t.Fatalf("Namespaces: %v, %d", err, len(nss))
+	}
+
+	if ok, err := p.EncryptionAtRestEnabled(ctx, "c1"); err != nil || !ok {
+		t.Errorf("expected encryption at rest, got %v, %v", ok, err)
+	}
+	// Audit policy without a log or webhook backend records nothing.
+	if ok, err := p.AuditLoggingEnabled(ctx, "c1"); err != nil || ok {
+		t.Errorf("expected audit logging off, got %v, %v", ok, err)
+	}
+
+	policies, err := p.NetworkPolicies(ctx, "c1", "patients")
+	if err != nil || len(policies) != 2 {
+		t.Fatalf("NetworkPolicies: %v, %d", err, len(policies))
+	}
+	for _, np := range policies {
+		switch np.Name {
+		case "default-deny":
+			if !np.SelectsAllPods || len(np.IngressRules) != 0 || np.PolicyTypes[0] != "Ingress" {
+				t.Errorf("default-deny flattened incorrectly: %+v", np)
+			}
+		case "from-edge":
+			if len(np.IngressRules) != 1 || len(np.IngressRules[0].FromNamespaces) != 1 || np.IngressRules[0].FromNamespaces[0] != "gateway" {
+				t.Errorf("expected namespaceSelector to resolve to gateway, got %+v", np.IngressRules)
+			}
+		}
+	}
+
+	ings, err := p.Ingresses(ctx, "c1", "patients")
+	if err != nil || len(ings) != 1 || !ings[0].TLS || ings[0].Services[0] != "api" {
+		t.Errorf("Ingresses: %v, %+v", err, ings)
+	}
+
+	rbs, err := p.RoleBindings(ctx, "c1", "patients")
+	if err != nil || len(rbs) != 1 || rbs[0].Subjects[0] != "Group:system:authenticated" {
+		t.Errorf("RoleBindings: %v, %+v", err, rbs)
+	}
+}
+
+func TestHIPAAProber_ManagedControlPlane(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.clients["c1"] = fake.NewSimpleClientset()
+	p := NewHIPAAProber(m)
+
+	if _, err := p.EncryptionAtRestEnabled(context.Background(), "c1"); !errors.Is(err, hipaa.ErrNotObservable) {
+		t.Errorf("expected ErrNotObservable without an apiserver pod, got %v", err)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want map[string]string
+	}{
+		{"equals form", []string{"kube-apiserver", "--audit-log-path=/var/log/audit.log"}, map[string]string{"--audit-log-path": "/var/log/audit.log"}},
+		{"next-argument form", []string{"kube-apiserver", "--encryption-provider-config", "/etc/enc.yaml"}, map[string]string{"--encryption-provider-config": "/etc/enc.yaml"}},
+		{"bare flag", []string{"--profiling"}, map[string]string{"--profiling": "true"}},
+		{"bare flag before another flag", []string{"--profiling", "--audit-policy-file=/etc/audit.yaml"}, map[string]string{"--profiling": "true", "--audit-policy-file": "/etc/audit.yaml"}},
+		{"mixed forms", []string{"--audit-policy-file", "/etc/audit.yaml", "--audit-webhook-config-file=/etc/hook.yaml"}, map[string]string{"--audit-policy-file": "/etc/audit.yaml", "--audit-webhook-config-file": "/etc/hook.yaml"}},
+		{"positional arguments ignored", []string{"kube-apiserver", "extra"}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseFlags(tt.args)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseFlags(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("parseFlags(%q)[%q] = %q, want %q", tt.args, k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,765 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/safego"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InformerResource names a resource type the informer cache can hold.
+type InformerResource string
+
+const (
+	InformerPods        InformerResource = "pods"
+	InformerDeployments InformerResource = "deployments"
+	InformerEvents      InformerResource = "events"
+	InformerNodes       InformerResource = "nodes"
+	InformerServices    InformerResource = "services"
+)
+
+// AllInformerResources is every resource type the informer cache supports,
+// and what it caches when InformerCacheOptions.Resources is empty.
+var AllInformerResources = []InformerResource{
+	InformerPods, InformerDeployments, InformerEvents, InformerNodes, InformerServices,
+}
+
+const (
+	// informerSyncPollInterval is how often a starting cluster is checked
+	// for completed initial lists.
+	informerSyncPollInterval = time.Second
+	// informerReconcileTimeout bounds listing contexts when the cache is
+	// reconciled against the kubeconfig.
+	informerReconcileTimeout = 10 * time.Second
+)
+
+// ParseInformerResources parses a comma-separated list of resource names
+// such as "pods,events". An empty string selects every resource.
+func ParseInformerResources(s string) ([]InformerResource, error) {
+	if strings.TrimSpace(s) == "" {
+		return AllInformerResources, nil
+	}
+	var out []InformerResource
+	seen := make(map[InformerResource]bool)
+	for _, part := range strings.Split(s, ",") {
+		res := InformerResource(strings.ToLower(strings.TrimSpace(part)))
+		if res == "" || seen[res] {
+			continue
+		}
+		known := false
+		for _, r := range AllInformerResources {
+			if r == res {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown informer resource %q", part)
+		}
+		seen[res] = true
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// InformerCacheOptions configures EnableInformerCache.
+type InformerCacheOptions struct {
+	// Resources are the resource types to cache; empty means all.
+	Resources []InformerResource
+	// ResyncPeriod is the informer resync period; zero disables resyncs.
+	ResyncPeriod time.Duration
+}
+
+// InformerChange reports that a cached resource changed on a cluster.
+type InformerChange struct {
+	Cluster  string
+	Resource InformerResource
+}
+
+// InformerSubscription delivers change notifications from the informer
+// cache. Notifications are coalesced: C is signalled at most once between
+// Drain calls, and Drain returns every distinct change since the last call.
+type InformerSubscription struct {
+	// C receives a value whenever changes are pending.
+	C <-chan struct{}
+
+	c       chan struct{}
+	owner   *informerCache
+	id      uint64
+	mu      sync.Mutex
+	pending map[InformerChange]struct{}
+}
+
+// Drain returns and clears the pending changes.
+func (s *InformerSubscription) Drain() []InformerChange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]InformerChange, 0, len(s.pending))
+	for ch := range s.pending {
+		out = append(out, ch)
+	}
+	s.pending = make(map[InformerChange]struct{})
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cluster != out[j].Cluster {
+			return out[i].Cluster < out[j].Cluster
+		}
+		return out[i].Resource < out[j].Resource
+	})
+	return out
+}
+
+// Close stops delivery. Safe to call on a nil subscription.
+func (s *InformerSubscription) Close() {
+	if s == nil {
+		return
+	}
+	s.owner.mu.Lock()
+	delete(s.owner.subs, s.id)
+	s.owner.mu.Unlock()
+}
+
+func (s *InformerSubscription) add(ch InformerChange) {
+	s.mu.Lock()
+	s.pending[ch] = struct{}{}
+	s.mu.Unlock()
+	select {
+	case s.c <- struct{}{}:
+	default:
+	}
+}
+
+// InformerClusterStatus describes one cluster in the informer cache.
+type InformerClusterStatus struct {
+	Cluster     string                    `json:"cluster"`
+	StartedAt   time.Time                 `json:"startedAt"`
+	Synced      map[InformerResource]bool `json:"synced"`
+	Objects     map[InformerResource]int  `json:"objects"`
+	LastError   string                    `json:"lastError,omitempty"`
+	LastErrorAt *time.Time                `json:"lastErrorAt,omitempty"`
+}
+
+// informerCache holds one shared informer factory per cluster.
+type informerCache struct {
+	opts InformerCacheOptions
+	// reconcileMu serializes reconciles so two reloads cannot start the
+	// same cluster twice.
+	reconcileMu sync.Mutex
+
+	mu       sync.RWMutex
+	clusters map[string]*clusterInformers
+	subs     map[uint64]*InformerSubscription
+	subSeq   uint64
+}
+
+type clusterInformers struct {
+	name        string
+	fingerprint string
+	factory     informers.SharedInformerFactory
+	informers   map[InformerResource]cache.SharedIndexInformer
+	cancel      context.CancelFunc
+	startedAt   time.Time
+
+	errMu       sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
+	// failedAt holds, per resource whose list or watch is failing, the
+	// resource version the informer had reached when it failed. The
+	// resource is served from the cache again once that version moves.
+	failedAt map[InformerResource]string
+}
+
+func (ci *clusterInformers) recordError(res InformerResource, err error) {
+	var rv string
+	if inf := ci.informers[res]; inf != nil {
+		rv = inf.LastSyncResourceVersion()
+	}
+	ci.errMu.Lock()
+	first := ci.lastError == ""
+	ci.lastError = fmt.Sprintf("%s: %v", res, err)
+	ci.lastErrorAt = time.Now()
+	if ci.failedAt == nil {
+		ci.failedAt = make(map[InformerResource]string)
+	}
+	ci.failedAt[res] = rv
+	ci.errMu.Unlock()
+	// The reflector retries with backoff; log the first failure loudly and
+	// the rest quietly so an unreachable cluster does not flood the log.
+	if first {
+		slog.Warn("[Informers] watch failed, retrying", "cluster", ci.name, "resource", res, "error", err)
+	} else {
+		slog.Debug("[Informers] watch failed, retrying", "cluster", ci.name, "resource", res, "error", err)
+	}
+}
+
+// watchFailing reports whether res's informer is failing to list or watch,
+// so its cache may be stale. A relist or watch event since the failure
+// means the reflector recovered; once every resource has, the cluster's
+// last error is cleared.
+func (ci *clusterInformers) watchFailing(res InformerResource) bool {
+	inf := ci.informers[res]
+	ci.errMu.Lock()
+	defer ci.errMu.Unlock()
+	rv, failing := ci.failedAt[res]
+	if !failing {
+		return false
+	}
+	if inf == nil || inf.LastSyncResourceVersion() == rv {
+		return true
+	}
+	delete(ci.failedAt, res)
+	slog.Info("[Informers] watch recovered", "cluster", ci.name, "resource", res)
+	if len(ci.failedAt) == 0 {
+		ci.lastError = ""
+		ci.lastErrorAt = time.Time{}
+	}
+	return false
+}
+
+// EnableInformerCache starts shared informers for the hot resource types on
+// every cluster and serves GetPods, FindPodIssues, GetDeployments,
+// GetEvents, GetNodes, GetServices and friends from them once synced.
+// Until a cluster's informer has synced, while its watch is failing, and
+// for clusters it cannot reach, readers fall back to a live LIST. Informers follow kubeconfig reloads:
+// new contexts are started, removed ones stopped and ones whose endpoint
+// or credentials changed restarted. Calling it again is a no-op.
+func (m *MultiClusterClient) EnableInformerCache(opts InformerCacheOptions) {
+	if m == nil {
+		return
+	}
+	if len(opts.Resources) == 0 {
+		opts.Resources = AllInformerResources
+	}
+	m.mu.Lock()
+	if m.informers != nil {
+		m.mu.Unlock()
+		return
+	}
+	m.informers = &informerCache{
+		opts:     opts,
+		clusters: make(map[string]*clusterInformers),
+		subs:     make(map[uint64]*InformerSubscription),
+	}
+	m.mu.Unlock()
+	slog.Info("[Informers] informer cache enabled", "resources", opts.Resources)
+	m.reconcileInformers()
+}
+
+// InformerCacheEnabled reports whether EnableInformerCache has been called.
+func (m *MultiClusterClient) InformerCacheEnabled() bool {
+	return m.informerCache() != nil
+}
+
+// InformerSynced reports whether res is served from a synced informer for
+// cluster.
+func (m *MultiClusterClient) InformerSynced(cluster string, res InformerResource) bool {
+	return m.cachedInformer(cluster, res) != nil
+}
+
+// SubscribeInformerChanges returns a subscription to informer change
+// notifications, or nil when the informer cache is disabled. Receiving from
+// a nil subscription's channel is not possible; callers check for nil.
+func (m *MultiClusterClient) SubscribeInformerChanges() *InformerSubscription {
+	ic := m.informerCache()
+	if ic == nil {
+		return nil
+	}
+	c := make(chan struct{}, 1)
+	sub := &InformerSubscription{C: c, c: c, owner: ic, pending: make(map[InformerChange]struct{})}
+	ic.mu.Lock()
+	ic.subSeq++
+	sub.id = ic.subSeq
+	ic.subs[sub.id] = sub
+	ic.mu.Unlock()
+	return sub
+}
+
+// InformerStatus reports the state of every cached cluster, sorted by name.
+func (m *MultiClusterClient) InformerStatus() []InformerClusterStatus {
+	ic := m.informerCache()
+	if ic == nil {
+		return nil
+	}
+	ic.mu.RLock()
+	defer ic.mu.RUnlock()
+	out := make([]InformerClusterStatus, 0, len(ic.clusters))
+	for _, ci := range ic.clusters {
+		st := InformerClusterStatus{
+			Cluster:   ci.name,
+			StartedAt: ci.startedAt,
+			Synced:    make(map[InformerResource]bool, len(ci.informers)),
+			Objects:   make(map[InformerResource]int, len(ci.informers)),
+		}
+		for res, inf := range ci.informers {
+			st.Synced[res] = inf.HasSynced() && !ci.watchFailing(res)
+			st.Objects[res] = len(inf.GetStore().ListKeys())
+		}
+		ci.errMu.Lock()
+		if ci.lastError != "" {
+			st.LastError = ci.lastError
+			at := ci.lastErrorAt
+			st.LastErrorAt = &at
+		}
+		ci.errMu.Unlock()
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Cluster < out[j].Cluster })
+	return out
+}
+
+// StopInformers stops every cluster's informers. The cache stays enabled
+// and a later reload starts them again.
+func (m *MultiClusterClient) StopInformers() {
+	ic := m.informerCache()
+	if ic == nil {
+		return
+	}
+	ic.mu.Lock()
+	for name, ci := range ic.clusters {
+		ci.stop()
+		delete(ic.clusters, name)
+	}
+	ic.mu.Unlock()
+}
+
+func (m *MultiClusterClient) informerCache() *informerCache {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.informers
+}
+
+// reconcileInformers starts, restarts and stops cluster informers so they
+// match the deduplicated cluster list.
+func (m *MultiClusterClient) reconcileInformers() {
+	ic := m.informerCache()
+	if ic == nil {
+		return
+	}
+	ic.reconcileMu.Lock()
+	defer ic.reconcileMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), informerReconcileTimeout)
+	defer cancel()
+	clusters, err := m.DeduplicatedClusters(ctx)
+	if err != nil {
+		slog.Warn("[Informers] failed to list clusters", "error", err)
+		return
+	}
+
+	want := make(map[string]bool, len(clusters))
+	for _, cl := range clusters {
+		want[cl.Name] = true
+		client, fingerprint, err := m.informerClient(cl.Name)
+		if err != nil {
+			slog.Warn("[Informers] cluster not cached", "cluster", cl.Name, "error", err)
+			ic.stopCluster(cl.Name)
+			continue
+		}
+		ic.mu.RLock()
+		existing := ic.clusters[cl.Name]
+		ic.mu.RUnlock()
+		if existing != nil && existing.fingerprint == fingerprint {
+			continue
+		}
+		if existing != nil {
+			slog.Info("[Informers] cluster connection changed, restarting informers", "cluster", cl.Name)
+		}
+		ic.startCluster(cl.Name, fingerprint, client)
+	}
+
+	ic.mu.Lock()
+	for name, ci := range ic.clusters {
+		if !want[name] {
+			slog.Info("[Informers] cluster removed, stopping informers", "cluster", name)
+			ci.stop()
+			delete(ic.clusters, name)
+		}
+	}
+	ic.mu.Unlock()
+}
+
+// informerClient builds a clientset for long-running watches. The shared
+// clients carry k8sClientTimeout, which would cut every watch after 45s, so
+// the informers get their own client without a request timeout.
+func (m *MultiClusterClient) informerClient(cluster string) (kubernetes.Interface, string, error) {
+	shared, err := m.GetClient(cluster)
+	if err != nil {
+		return nil, "", err
+	}
+	m.mu.RLock()
+	cfg := m.configs[cluster]
+	m.mu.RUnlock()
+	if cfg == nil {
+		// Injected clients (tests) have no rest config; watch through them.
+		return shared, fmt.Sprintf("client:%p", shared), nil
+	}
+	cfg = rest.CopyConfig(cfg)
+	cfg.Timeout = 0
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, restConfigFingerprint(cfg), nil
+}
+
+// restConfigFingerprint identifies the endpoint and credentials of cfg so a
+// kubeconfig reload only restarts informers whose connection changed.
+func restConfigFingerprint(cfg *rest.Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00%v\x00", cfg.Host, cfg.BearerToken, cfg.BearerTokenFile,
+		cfg.Username, cfg.Password, cfg.Impersonate)
+	tls := cfg.TLSClientConfig
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%t\x00", tls.ServerName, tls.CAFile, tls.CertFile, tls.KeyFile, tls.Insecure)
+	h.Write(tls.CAData)
+	h.Write(tls.CertData)
+	h.Write(tls.KeyData)
+	if cfg.ExecProvider != nil {
+		fmt.Fprintf(h, "\x00exec:%s\x00%q\x00%v", cfg.ExecProvider.Command, cfg.ExecProvider.Args, cfg.ExecProvider.Env)
+	}
+	if cfg.AuthProvider != nil {
+		fmt.Fprintf(h, "\x00auth:%s\x00%v", cfg.AuthProvider.Name, cfg.AuthProvider.Config)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (ic *informerCache) startCluster(name, fingerprint string, client kubernetes.Interface) {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, ic.opts.ResyncPeriod,
+		informers.WithTransform(stripManagedFields))
+	ci := &clusterInformers{
+		name:        name,
+		fingerprint: fingerprint,
+		factory:     factory,
+		informers:   make(map[InformerResource]cache.SharedIndexInformer, len(ic.opts.Resources)),
+		startedAt:   time.Now(),
+	}
+	for _, res := range ic.opts.Resources {
+		var inf cache.SharedIndexInformer
+		switch res {
+		case InformerPods:
+			inf = factory.Core().V1().Pods().Informer()
+		case InformerDeployments:
+			inf = factory.Apps().V1().Deployments().Informer()
+		case InformerEvents:
+			inf = factory.Core().V1().Events().Informer()
+		case InformerNodes:
+			inf = factory.Core().V1().Nodes().Informer()
+		case InformerServices:
+			inf = factory.Core().V1().Services().Informer()
+		default:
+			continue
+		}
+		if err := inf.SetWatchErrorHandler(func(_ *cache.Reflector, err error) { ci.recordError(res, err) }); err != nil {
+			slog.Debug("[Informers] could not set watch error handler", "cluster", name, "resource", res, "error", err)
+		}
+		if _, err := inf.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(_ interface{}, isInInitialList bool) {
+				if !isInInitialList {
+					ic.notify(name, res)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if resourceVersionChanged(oldObj, newObj) {
+					ic.notify(name, res)
+				}
+			},
+			DeleteFunc: func(interface{}) { ic.notify(name, res) },
+		}); err != nil {
+			slog.Warn("[Informers] could not watch for changes", "cluster", name, "resource", res, "error", err)
+		}
+		ci.informers[res] = inf
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ci.cancel = cancel
+
+	ic.mu.Lock()
+	if old := ic.clusters[name]; old != nil {
+		old.stop()
+	}
+	ic.clusters[name] = ci
+	ic.mu.Unlock()
+
+	factory.Start(ctx.Done())
+	safego.GoWith("k8s/informer-sync/"+name, func() { ic.awaitSync(ctx, ci) })
+}
+
+// awaitSync notifies subscribers as each resource finishes its initial
+// list, so streams switch from their LIST snapshot to the cache.
+func (ic *informerCache) awaitSync(ctx context.Context, ci *clusterInformers) {
+	pending := make(map[InformerResource]cache.SharedIndexInformer, len(ci.informers))
+	for res, inf := range ci.informers {
+		pending[res] = inf
+	}
+	ticker := time.NewTicker(informerSyncPollInterval)
+	defer ticker.Stop()
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for res, inf := range pending {
+			if inf.HasSynced() {
+				delete(pending, res)
+				ic.notify(ci.name, res)
+			}
+		}
+	}
+	slog.Info("[Informers] cluster cache synced", "cluster", ci.name, "elapsed", time.Since(ci.startedAt).Round(time.Millisecond))
+}
+
+func (ic *informerCache) stopCluster(name string) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if ci := ic.clusters[name]; ci != nil {
+		ci.stop()
+		delete(ic.clusters, name)
+	}
+}
+
+// stop cancels the informers and reaps their goroutines in the background
+// so a kubeconfig reload never waits on a hung watch.
+func (ci *clusterInformers) stop() {
+	ci.cancel()
+	safego.GoWith("k8s/informer-shutdown/"+ci.name, ci.factory.Shutdown)
+}
+
+func (ic *informerCache) notify(cluster string, res InformerResource) {
+	ch := InformerChange{Cluster: cluster, Resource: res}
+	ic.mu.RLock()
+	defer ic.mu.RUnlock()
+	for _, sub := range ic.subs {
+		sub.add(ch)
+	}
+}
+
+// synced returns the informer for res on cluster once its initial list
+// has completed, and nil while its watch is failing so readers do not
+// serve data frozen at the outage.
+func (ic *informerCache) synced(cluster string, res InformerResource) cache.SharedIndexInformer {
+	ic.mu.RLock()
+	ci := ic.clusters[cluster]
+	ic.mu.RUnlock()
+	if ci == nil {
+		return nil
+	}
+	inf := ci.informers[res]
+	if inf == nil || !inf.HasSynced() || ci.watchFailing(res) {
+		return nil
+	}
+	return inf
+}
+
+func (m *MultiClusterClient) cachedInformer(cluster string, res InformerResource) cache.SharedIndexInformer {
+	ic := m.informerCache()
+	if ic == nil {
+		return nil
+	}
+	return ic.synced(cluster, res)
+}
+
+// stripManagedFields drops managedFields before objects enter the cache;
+// nothing in the console reads them and they are often half an object.
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if acc, err := meta.Accessor(obj); err == nil {
+		acc.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
+// resourceVersionChanged filters out resync updates, which redeliver an
+// unchanged object.
+func resourceVersionChanged(oldObj, newObj interface{}) bool {
+	oldMeta, err1 := meta.Accessor(oldObj)
+	newMeta, err2 := meta.Accessor(newObj)
+	if err1 != nil || err2 != nil {
+		return true
+	}
+	return oldMeta.GetResourceVersion() != newMeta.GetResourceVersion()
+}
+
+// cachedList copies the objects of a synced informer, scoped to namespace
+// when one is given. ok is false when the caller must LIST instead.
+func cachedList[T any](m *MultiClusterClient, cluster string, res InformerResource, namespace string) (items []T, ok bool) {
+	inf := m.cachedInformer(cluster, res)
+	if inf == nil {
+		return nil, false
+	}
+	var objs []interface{}
+	if namespace == "" {
+		objs = inf.GetStore().List()
+	} else {
+		var err error
+		if objs, err = inf.GetIndexer().ByIndex(cache.NamespaceIndex, namespace); err != nil {
+			return nil, false
+		}
+	}
+	items = make([]T, 0, len(objs))
+	for _, obj := range objs {
+		if t, isT := obj.(*T); isT {
+			items = append(items, *t)
+		}
+	}
+	return items, true
+}
+
+// listPods returns the pods in namespace (all namespaces when empty) from
+// the informer cache, or from a live LIST when the cache cannot serve it.
+func (m *MultiClusterClient) listPods(ctx context.Context, contextName, namespace string) ([]corev1.Pod, error) {
+	if items, ok := cachedList[corev1.Pod](m, contextName, InformerPods, namespace); ok {
+		return items, nil
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// listDeployments is listPods for deployments.
+func (m *MultiClusterClient) listDeployments(ctx context.Context, contextName, namespace string) ([]appsv1.Deployment, error) {
+	if items, ok := cachedList[appsv1.Deployment](m, contextName, InformerDeployments, namespace); ok {
+		return items, nil
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// cachedUnstructuredDeployments serves the dynamic-client deployment list
+// ListWorkloadsForCluster parses from the informer cache.
+func (m *MultiClusterClient) cachedUnstructuredDeployments(contextName, namespace string) (*unstructured.UnstructuredList, bool) {
+	items, ok := cachedList[appsv1.Deployment](m, contextName, InformerDeployments, namespace)
+	if !ok {
+		return nil, false
+	}
+	list := &unstructured.UnstructuredList{Items: make([]unstructured.Unstructured, 0, len(items))}
+	for i := range items {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&items[i])
+		if err != nil {
+			return nil, false
+		}
+		list.Items = append(list.Items, unstructured.Unstructured{Object: obj})
+	}
+	return list, true
+}
+
+// listNodes is listPods for nodes.
+func (m *MultiClusterClient) listNodes(ctx context.Context, contextName string) ([]corev1.Node, error) {
+	if items, ok := cachedList[corev1.Node](m, contextName, InformerNodes, ""); ok {
+		return items, nil
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// listServices is listPods for services.
+func (m *MultiClusterClient) listServices(ctx context.Context, contextName, namespace string) ([]corev1.Service, error) {
+	if items, ok := cachedList[corev1.Service](m, contextName, InformerServices, namespace); ok {
+		return items, nil
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// listEvents is listPods for events. fieldSelector is applied to cached
+// events when it only uses fields the apiserver supports for events;
+// anything else goes to a live LIST.
+func (m *MultiClusterClient) listEvents(ctx context.Context, contextName, namespace, fieldSelector string) ([]corev1.Event, error) {
+	if items, ok := cachedList[corev1.Event](m, contextName, InformerEvents, namespace); ok {
+		if fieldSelector == "" {
+			return items, nil
+		}
+		if sel, err := fields.ParseSelector(fieldSelector); err == nil && eventSelectorSupported(sel) {
+			out := items[:0]
+			for i := range items {
+				if sel.Matches(eventFields(&items[i])) {
+					out = append(out, items[i])
+				}
+			}
+			return out, nil
+		}
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// eventFields mirrors the apiserver's selectable fields for core/v1 events.
+func eventFields(ev *corev1.Event) fields.Set {
+	return fields.Set{
+		"metadata.name":                  ev.Name,
+		"metadata.namespace":             ev.Namespace,
+		"involvedObject.kind":            ev.InvolvedObject.Kind,
+		"involvedObject.namespace":       ev.InvolvedObject.Namespace,
+		"involvedObject.name":            ev.InvolvedObject.Name,
+		"involvedObject.uid":             string(ev.InvolvedObject.UID),
+		"involvedObject.apiVersion":      ev.InvolvedObject.APIVersion,
+		"involvedObject.resourceVersion": ev.InvolvedObject.ResourceVersion,
+		"involvedObject.fieldPath":       ev.InvolvedObject.FieldPath,
+		"reason":                         ev.Reason,
+		"reportingComponent":             ev.ReportingController,
+		"source":                         ev.Source.Component,
+		"type":                           ev.Type,
+	}
+}
+
+func eventSelectorSupported(sel fields.Selector) bool {
+	supported := eventFields(&corev1.Event{})
+	for _, req := range sel.Requirements() {
+		if _, ok := supported[req.Field]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const informerTestWait = 5 * time.Second
+
+func newInformerTestClient(t *testing.T, clusters map[string]*fake.Clientset) *MultiClusterClient {
+	t.Helper()
+	m, _ := NewMultiClusterClient("")
+	names := make([]string, 0, len(clusters))
+	for name, cs := range clusters {
+		names = append(names, name)
+		m.InjectClient(name, cs)
+	}
+	injectTestClusters(m, names...)
+	t.Cleanup(m.StopInformers)
+	return m
+}
+
+func waitSynced(t *testing.T, m *MultiClusterClient, cluster string, res InformerResource) {
+	t.Helper()
+	require.Eventually(t, func() bool { return m.InformerSynced(cluster, res) },
+		informerTestWait, 10*time.Millisecond, "%s/%s never synced", cluster, res)
+}
+
+func TestInformerCache_ServesReadersWithoutList(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", CreationTimestamp: metav1.Now()},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "web",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx"}}},
+	}
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "data"}}
+	warning := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "shop"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1"},
+	}
+	normal := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1.2", Namespace: "shop"},
+		Type:       corev1.EventTypeNormal,
+		Reason:     "Pulled",
+	}
+	cs := fake.NewSimpleClientset(pod, other, warning, normal)
+	m := newInformerTestClient(t, map[string]*fake.Clientset{"c1": cs})
+
+	m.EnableInformerCache(InformerCacheOptions{Resources: []InformerResource{InformerPods, InformerEvents}})
+	waitSynced(t, m, "c1", InformerPods)
+	waitSynced(t, m, "c1", InformerEvents)
+	assert.False(t, m.InformerSynced("c1", InformerNodes), "nodes were not requested")
+	cs.ClearActions()
+
+	ctx := context.Background()
+	pods, err := m.GetPods(ctx, "c1", "shop")
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "web-1", pods[0].Name)
+
+	all, err := m.GetPods(ctx, "c1", "")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	issues, err := m.FindPodIssues(ctx, "c1", "")
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "web-1", issues[0].Name)
+
+	warnings, err := m.GetWarningEvents(ctx, "c1", "", 10)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "BackOff", warnings[0].Reason)
+
+	for _, a := range cs.Actions() {
+		assert.NotEqual(t, "list", a.GetVerb(), "unexpected LIST %s after sync", a.GetResource().Resource)
+	}
+
+	// A selector on a field events cannot be filtered by locally falls back
+	// to the apiserver.
+	_, err = m.GetEvents(ctx, "c1", "", 10, "metadata.uid=x")
+	require.NoError(t, err)
+	listed := false
+	for _, a := range cs.Actions() {
+		listed = listed || (a.GetVerb() == "list" && a.GetResource().Resource == "events")
+	}
+	assert.True(t, listed)
+}
+
+func TestInformerCache_PushesChanges(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	m := newInformerTestClient(t, map[string]*fake.Clientset{"c1": cs})
+	m.EnableInformerCache(InformerCacheOptions{Resources: []InformerResource{InformerPods}})
+	waitSynced(t, m, "c1", InformerPods)
+
+	sub := m.SubscribeInformerChanges()
+	require.NotNil(t, sub)
+	defer sub.Close()
+
+	_, err := cs.CoreV1().Pods("shop").Create(context.Background(),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "shop"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	select {
+	case <-sub.C:
+	case <-time.After(informerTestWait):
+		t.Fatal("no change notification")
+	}
+	assert.Contains(t, sub.Drain(), InformerChange{Cluster: "c1", Resource: InformerPods})
+	require.Eventually(t, func() bool {
+		pods, err := m.GetPods(context.Background(), "c1", "shop")
+		return err == nil && len(pods) == 1
+	}, informerTestWait, 10*time.Millisecond)
+}
+
+func TestInformerCache_FailingWatchFallsBackToList(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}})
+	var failing atomic.Bool
+	var mu sync.Mutex
+	var watches []watch.Interface
+	cs.PrependReactor("list", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+		if failing.Load() {
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		return false, nil, nil
+	})
+	cs.PrependWatchReactor("pods", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		if failing.Load() {
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		w, err := cs.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		mu.Lock()
+		watches = append(watches, w)
+		mu.Unlock()
+		return true, w, nil
+	})
+	m := newInformerTestClient(t, map[string]*fake.Clientset{"c1": cs})
+	m.EnableInformerCache(InformerCacheOptions{Resources: []InformerResource{InformerPods}})
+	waitSynced(t, m, "c1", InformerPods)
+
+	// The cluster goes away after the initial sync.
+	failing.Store(true)
+	mu.Lock()
+	for _, w := range watches {
+		w.Stop()
+	}
+	mu.Unlock()
+	require.Eventually(t, func() bool { return !m.InformerSynced("c1", InformerPods) },
+		informerTestWait, 10*time.Millisecond, "failing watch still served from cache")
+
+	cs.ClearActions()
+	_, err := m.GetPods(context.Background(), "c1", "shop")
+	assert.Error(t, err, "readers must not get the stale cache")
+	listed := false
+	for _, a := range cs.Actions() {
+		listed = listed || (a.GetVerb() == "list" && a.GetResource().Resource == "pods")
+	}
+	assert.True(t, listed, "readers fall back to a live LIST")
+	status := m.InformerStatus()
+	require.Len(t, status, 1)
+	assert.NotEmpty(t, status[0].LastError)
+
+	// Once the watch recovers the cache serves readers again and the error
+	// is cleared.
+	failing.Store(false)
+	_, err = cs.CoreV1().Pods("shop").Create(context.Background(),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "shop"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return m.InformerSynced("c1", InformerPods) },
+		6*informerTestWait, 50*time.Millisecond, "recovered watch not served from cache")
+	status = m.InformerStatus()
+	require.Len(t, status, 1)
+	assert.Empty(t, status[0].LastError)
+	assert.Nil(t, status[0].LastErrorAt)
+}
+
+func TestInformerCache_FollowsKubeconfigReload(t *testing.T) {
+	m := newInformerTestClient(t, map[string]*fake.Clientset{
+		"c1": fake.NewSimpleClientset(),
+		"c2": fake.NewSimpleClientset(),
+	})
+	m.EnableInformerCache(InformerCacheOptions{Resources: []InformerResource{InformerNodes}})
+	waitSynced(t, m, "c2", InformerNodes)
+	require.Len(t, m.InformerStatus(), 2)
+
+	injectTestClusters(m, "c1")
+	m.reconcileInformers()
+	status := m.InformerStatus()
+	require.Len(t, status, 1)
+	assert.Equal(t, "c1", status[0].Cluster)
+	assert.False(t, m.InformerSynced("c2", InformerNodes))
+}
+
+func TestInformerCache_Disabled(t *testing.T) {
+	m := newInformerTestClient(t, map[string]*fake.Clientset{"c1": fake.NewSimpleClientset()})
+	assert.False(t, m.InformerCacheEnabled())
+	assert.Nil(t, m.SubscribeInformerChanges())
+	assert.Nil(t, m.InformerStatus())
+	pods, err := m.GetPods(context.Background(), "c1", "")
+	require.NoError(t, err)
+	assert.Empty(t, pods)
+}
+
+func TestParseInformerResources(t *testing.T) {
+	all, err := ParseInformerResources("")
+	require.NoError(t, err)
+	assert.Equal(t, AllInformerResources, all)
+
+	res, err := ParseInformerResources(" Pods, events,pods ")
+	require.NoError(t, err)
+	assert.Equal(t, []InformerResource{InformerPods, InformerEvents}, res)
+
+	_, err = ParseInformerResources("pods,secrets")
+	assert.Error(t, err)
+}
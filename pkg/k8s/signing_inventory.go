@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubestellar/console/pkg/compliance/signing"
+)
+
+// ClusterImagePolicyGVR is the Sigstore Policy Controller policy resource.
+var ClusterImagePolicyGVR = schema.GroupVersionResource{
+	Group:    "policy.sigstore.dev",
+	Version:  "v1beta1",
+	Resource: "clusterimagepolicies",
+}
+
+// signingInventory implements signing.Inventory with the multi-cluster client.
+type signingInventory struct {
+	m *MultiClusterClient
+}
+
+// NewSigningInventory returns a signing.Inventory that lists running pods and
+// Policy Controller policies through m.
+func NewSigningInventory(m *MultiClusterClient) signing.Inventory {
+	return &signingInventory{m: m}
+}
+
+func (s *signingInventory) RunningImages(ctx context.Context, cluster string) ([]signing.RunningImage, error) {
+	client, err := s.m.GetClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=" + string(corev1.PodRunning),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var out []signing.RunningImage
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		workload := podWorkloadName(pod)
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			image := specImage(pod, cs.Name)
+			if image == "" {
+				image = cs.Image
+			}
+			digest := imageIDDigest(cs.ImageID)
+			key := pod.Namespace + "/" + workload + "/" + image + "@" + digest
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, signing.RunningImage{
+				Image:     image,
+				Digest:    digest,
+				Workload:  workload,
+				Namespace: pod.Namespace,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (s *signingInventory) ClusterImagePolicies(ctx context.Context, cluster string) ([]signing.ClusterImagePolicy, error) {
+	dynamicClient, err := s.m.GetDynamicClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(ClusterImagePolicyGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || isNoMatchError(err) {
+			// Policy Controller not installed.
+			return nil, nil
+		}
+		slog.Error("[signing] error listing ClusterImagePolicies", "cluster", cluster, "error", err)
+		return nil, err
+	}
+	out := make([]signing.ClusterImagePolicy, 0, len(list.Items))
+	for _, item := range list.Items {
+		p := signing.ClusterImagePolicy{Name: item.GetName()}
+		p.Mode, _, _ = unstructured.NestedString(item.Object, "spec", "mode")
+		images, _, _ := unstructured.NestedSlice(item.Object, "spec", "images")
+		for _, img := range images {
+			if m, ok := img.(map[string]interface{}); ok {
+				if glob, ok := m["glob"].(string); ok && glob != "" {
+					p.Globs = append(p.Globs, glob)
+				}
+			}
+		}
+		authorities, _, _ := unstructured.NestedSlice(item.Object, "spec", "authorities")
+		p.Authorities = len(authorities)
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// specImage returns the image reference a container was declared with.
+func specImage(pod *corev1.Pod, container string) string {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == container {
+			return c.Image
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == container {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+// imageIDDigest extracts the manifest digest from a container status
+// imageID such as "docker-pullable://nginx@sha256:..." or
+// "ghcr.io/org/app@sha256:...". Runtimes that report only the local image
+// config ID (a bare "sha256:...") give no registry digest.
+func imageIDDigest(imageID string) string {
+	if _, digest, ok := strings.Cut(imageID, "@"); ok && strings.HasPrefix(digest, "sha256:") {
+		return digest
+	}
+	return ""
+}
+
+// podWorkloadName names the controller that owns a pod: the Deployment for
+// ReplicaSet-owned pods, the owner for other controllers, or the pod itself.
+func podWorkloadName(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return pod.Name
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" {
+			return strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return owner.Name
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSigningInventory_RunningImages(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	isController := true
+	pod := func(name, hash string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "shop",
+				Labels:          map[string]string{"pod-template-hash": hash},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-" + hash, Controller: &isController}},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "ghcr.io/org/api:v1"}}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:    "api",
+					Image:   "ghcr.io/org/api:v1",
+					ImageID: "ghcr.io/org/api@sha256:aaaa",
+				}},
+			},
+		}
+	}
+	m.clients["c1"] = fake.NewSimpleClientset(pod("api-7d9f-x1", "7d9f"), pod("api-7d9f-x2", "7d9f"))
+
+	images, err := NewSigningInventory(m).RunningImages(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("RunningImages: %v", err)
+	}
+	if len(images) != 1 {
+		t.Fatalf("expected replicas to collapse to one entry, got %d: %+v", len(images), images)
+	}
+	if img := images[0]; img.Workload != "api" || img.Digest != "sha256:aaaa" || img.Image != "ghcr.io/org/api:v1" {
+		t.Errorf("unexpected image: %+v", img)
+	}
+}
+
+func TestSigningInventory_ClusterImagePolicies(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	cip := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy.sigstore.dev/v1beta1",
+		"kind":       "ClusterImagePolicy",
+		"metadata":   map[string]interface{}{"name": "org-images"},
+		"spec": map[string]interface{}{
+			"mode":        "warn",
+			"images":      []interface{}{map[string]interface{}{"glob": "ghcr.io/org/**"}},
+			"authorities": []interface{}{map[string]interface{}{"keyless": map[string]interface{}{}}},
+		},
+	}}
+	m.dynamicClients["c1"] = dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{ClusterImagePolicyGVR: "ClusterImagePolicyList"}, cip)
+
+	policies, err := NewSigningInventory(m).ClusterImagePolicies(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("ClusterImagePolicies: %v", err)
+	}
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 policy, got %d", len(policies))
+	}
+	if p := policies[0]; p.Mode != "warn" || len(p.Globs) != 1 || p.Globs[0] != "ghcr.io/org/**" || p.Authorities != 1 {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+}
+
+func TestImageIDDigest(t *testing.T) {
+	cases := map[string]string{
+		"docker-pullable://nginx@sha256:abcd": "sha256:abcd",
+		"ghcr.io/org/app@sha256:ef01":         "sha256:ef01",
+		"sha256:1234":                         "",
+		"":                                    "",
+	}
+	for in, want := range cases {
+		if got := imageIDDigest(in); got != want {
+			t.Errorf("imageIDDigest(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
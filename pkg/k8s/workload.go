@@ -161,7 +161,9 @@ func (m *MultiClusterClient) ListWorkloadsForCluster(ctx context.Context, contex
 	if workloadType == "" || workloadType == "Deployment" {
 		var deployments interface{}
 		var listErr error
-		if namespace == "" {
+		if cached, ok := m.cachedUnstructuredDeployments(contextName, namespace); ok {
+			deployments = cached
+		} else if namespace == "" {
 			deployments, listErr = dynamicClient.Resource(gvrDeployments).List(ctx, metav1.ListOptions{})
 		} else {
 			deployments, listErr = dynamicClient.Resource(gvrDeployments).Namespace(namespace).List(ctx, metav1.ListOptions{})
@@ -0,0 +1,744 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kubestellar/console/pkg/api/v1alpha1"
+)
+
+// WorkloadDeployment strategies (spec.strategy). The CRD defaults to
+// RollingUpdate; an empty strategy only occurs for objects that bypassed
+// API-server defaulting and is treated as the legacy all-at-once apply.
+const (
+	StrategyRollingUpdate = "RollingUpdate"
+	StrategyRecreate      = "Recreate"
+	StrategyBlueGreen     = "BlueGreen"
+	StrategyCanary        = "Canary"
+)
+
+// WorkloadDeployment status phases, matching the CRD enums.
+const (
+	RolloutPhasePending    = "Pending"
+	RolloutPhaseInProgress = "InProgress"
+	RolloutPhasePaused     = "Paused"
+	RolloutPhaseComplete   = "Complete"
+	RolloutPhaseFailed     = "Failed"
+	RolloutPhaseSkipped    = "Skipped"
+)
+
+const (
+	// defaultRolloutHealthTimeout mirrors the CRD default for
+	// rolloutConfig.healthCheckTimeout.
+	defaultRolloutHealthTimeout = 5 * time.Minute
+	// defaultCanaryStepInterval mirrors the CRD default for canaryConfig.stepInterval.
+	defaultCanaryStepInterval  = 5 * time.Minute
+	defaultCanaryInitialWeight = 10
+	defaultCanaryStepWeight    = 10
+	canaryFullWeight           = 100
+	// defaultRolloutPollInterval is how often a freshly applied workload is
+	// re-read while waiting for it to become healthy.
+	defaultRolloutPollInterval = 5 * time.Second
+	// rolloutClusterTimeout bounds a single per-cluster apply or rollback call.
+	rolloutClusterTimeout = 60 * time.Second
+)
+
+// RolloutClient is the subset of MultiClusterClient the rollout controller
+// needs. Tests substitute a fake backed by fake dynamic clients.
+type RolloutClient interface {
+	DeployWorkload(ctx context.Context, sourceCluster, namespace, name string,
+		targetClusters []string, replicas int32, opts *DeployOptions,
+	) (*v1alpha1.DeployResponse, error)
+	GetDynamicClient(cluster string) (dynamic.Interface, error)
+}
+
+// RolloutController executes a WorkloadDeployment's strategy: clusters are
+// updated one step at a time, each step is gated on CheckResourceHealth, and
+// a health timeout rolls every cluster touched by the attempt back to the
+// manifest it was running before.
+type RolloutController struct {
+	Client RolloutClient
+	// UpdateStatus persists wd.Status. It is called after every step so the
+	// UI can follow progress through the console watcher.
+	UpdateStatus func(ctx context.Context, wd *v1alpha1.WorkloadDeployment)
+	// Refresh re-reads the WorkloadDeployment between steps so setting
+	// spec.suspend pauses an in-flight rollout. Optional.
+	Refresh func(ctx context.Context) (*v1alpha1.WorkloadDeployment, error)
+	// PollInterval overrides defaultRolloutPollInterval (tests use a few ms).
+	PollInterval time.Duration
+}
+
+// IsProgressiveStrategy reports whether a strategy is executed step by step
+// by the RolloutController rather than applied to every target at once.
+func IsProgressiveStrategy(strategy string) bool {
+	return strategy == StrategyRollingUpdate || strategy == StrategyCanary
+}
+
+// rolloutStep is one batch of clusters updated together before the next
+// health gate. weight is the canary weight reached once the step completes.
+type rolloutStep struct {
+	clusters  []string
+	weight    int
+	promotion bool
+}
+
+// rolloutSettings are the parsed, defaulted durations from the spec.
+type rolloutSettings struct {
+	healthTimeout time.Duration
+	pause         time.Duration
+}
+
+// deployedCluster remembers what a cluster ran before this attempt so a
+// failed rollout can put it back.
+type deployedCluster struct {
+	cluster  string
+	previous *unstructured.Unstructured
+}
+
+// Run drives wd through its strategy against targets. When resume is true,
+// clusters already marked Complete in wd.Status are left alone — this is how
+// a Paused rollout (suspended, or a canary awaiting promotion) continues.
+// Run always leaves wd.Status in its final state and persists it.
+func (c *RolloutController) Run(ctx context.Context, wd *v1alpha1.WorkloadDeployment,
+	workload *v1alpha1.ManagedWorkload, targets []string, resume bool,
+) {
+	targets = append([]string(nil), targets...)
+	sort.Strings(targets)
+
+	settings, err := parseRolloutSettings(wd.Spec)
+	if err != nil {
+		c.finish(ctx, wd, RolloutPhaseFailed, err.Error())
+		return
+	}
+	gvr, kind, err := workloadGVRForKind(workload.Spec.WorkloadRef.Kind)
+	if err != nil {
+		c.finish(ctx, wd, RolloutPhaseFailed, err.Error())
+		return
+	}
+
+	completed := make(map[string]bool)
+	if resume {
+		for _, cs := range wd.Status.ClusterStatuses {
+			if cs.Phase == RolloutPhaseComplete {
+				completed[cs.Cluster] = true
+			}
+		}
+	}
+	c.initClusterStatuses(wd, targets, completed)
+	steps := planRolloutSteps(wd.Spec, targets)
+	if wd.Spec.Strategy == StrategyCanary {
+		wd.Status.CanaryStatus = &v1alpha1.CanaryStatus{TotalSteps: len(steps)}
+	} else {
+		wd.Status.CanaryStatus = nil
+	}
+
+	if wd.Spec.DryRun {
+		c.dryRun(ctx, wd, kind, workload.Spec.WorkloadRef.Name, steps)
+		return
+	}
+	if wd.Spec.Suspend {
+		c.pause(ctx, wd, "Rollout suspended")
+		return
+	}
+	if c.Client == nil {
+		c.failPending(wd, "Multi-cluster client not configured")
+		c.finish(ctx, wd, RolloutPhaseFailed, "Internal error: multi-cluster client not configured")
+		return
+	}
+
+	var deployed []deployedCluster
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			slog.Info("[rollout] reconcile cancelled", "name", wd.Name, "step", i+1)
+			return
+		}
+		if step.promotion && !wd.Spec.AutoPromote {
+			c.pause(ctx, wd, fmt.Sprintf("Canary at %d%% awaiting promotion (set spec.autoPromote to continue)",
+				wd.Status.CanaryStatus.CurrentWeight))
+			return
+		}
+		if c.suspended(ctx, wd) {
+			c.pause(ctx, wd, fmt.Sprintf("Rollout suspended after %s", wd.Status.Progress))
+			return
+		}
+
+		pending := make([]string, 0, len(step.clusters))
+		for _, cluster := range step.clusters {
+			if !completed[cluster] {
+				pending = append(pending, cluster)
+			}
+		}
+		if len(pending) > 0 {
+			applied, err := c.runStep(ctx, wd, workload, gvr, kind, pending, settings)
+			deployed = append(deployed, applied...)
+			if err != nil {
+				if ctx.Err() != nil {
+					slog.Info("[rollout] reconcile cancelled mid-step", "name", wd.Name, "step", i+1)
+					return
+				}
+				c.rollback(ctx, wd, gvr, workload, deployed, err)
+				return
+			}
+			for _, cluster := range pending {
+				completed[cluster] = true
+			}
+		}
+
+		if wd.Status.CanaryStatus != nil {
+			now := metav1.Now()
+			wd.Status.CanaryStatus.CurrentStep = i + 1
+			wd.Status.CanaryStatus.CurrentWeight = step.weight
+			wd.Status.CanaryStatus.LastStepTime = &now
+		}
+		c.persist(ctx, wd)
+
+		if i < len(steps)-1 && len(pending) > 0 && settings.pause > 0 {
+			if err := sleepContext(ctx, settings.pause); err != nil {
+				return
+			}
+		}
+	}
+
+	c.finish(ctx, wd, RolloutPhaseComplete,
+		fmt.Sprintf("Rolled out to %d clusters in %d steps", len(targets), len(steps)))
+}
+
+// runStep applies the workload to each cluster in the step and waits for all
+// of them to turn healthy. It returns every cluster it applied to, even on
+// error, so the caller can roll those back too.
+func (c *RolloutController) runStep(ctx context.Context, wd *v1alpha1.WorkloadDeployment,
+	workload *v1alpha1.ManagedWorkload, gvr schema.GroupVersionResource, kind string,
+	clusters []string, settings rolloutSettings,
+) ([]deployedCluster, error) {
+	ref := workload.Spec.WorkloadRef
+	ns := workload.Spec.SourceNamespace
+	replicas := int32(0)
+	if workload.Spec.Replicas != nil {
+		replicas = *workload.Spec.Replicas
+	}
+
+	applied := make([]deployedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		cs := clusterStatusFor(wd, cluster)
+		now := metav1.Now()
+		cs.Phase = RolloutPhaseInProgress
+		cs.Progress = "0%"
+		cs.StartedAt = &now
+		cs.CompletedAt = nil
+		cs.Message = "Applying workload"
+		c.persist(ctx, wd)
+
+		previous, err := c.snapshot(ctx, cluster, gvr, ns, ref.Name)
+		if err != nil {
+			cs.Phase = RolloutPhaseFailed
+			cs.Message = fmt.Sprintf("Failed to read current workload: %v", err)
+			return applied, fmt.Errorf("cluster %s: %w", cluster, err)
+		}
+
+		applyCtx, cancel := context.WithTimeout(ctx, rolloutClusterTimeout)
+		resp, err := c.Client.DeployWorkload(applyCtx, workload.Spec.SourceCluster, ns, ref.Name,
+			[]string{cluster}, replicas, &DeployOptions{DeployedBy: "console-reconciler"})
+		cancel()
+		if err == nil && (resp == nil || !resp.Success) {
+			msg := "deploy reported failure"
+			if resp != nil && resp.Message != "" {
+				msg = resp.Message
+			}
+			err = errors.New(msg)
+		}
+		// Record the cluster even when the apply failed: a partial apply
+		// (dependencies written, workload rejected) still needs undoing.
+		applied = append(applied, deployedCluster{cluster: cluster, previous: previous})
+		if err != nil {
+			cs.Phase = RolloutPhaseFailed
+			cs.Message = fmt.Sprintf("Deploy failed: %v", err)
+			return applied, fmt.Errorf("cluster %s: %w", cluster, err)
+		}
+		cs.Progress = "50%"
+		cs.Message = "Waiting for workload to become healthy"
+		c.persist(ctx, wd)
+	}
+
+	for _, cluster := range clusters {
+		cs := clusterStatusFor(wd, cluster)
+		msg, err := c.waitHealthy(ctx, cluster, gvr, kind, ns, ref.Name, settings.healthTimeout)
+		if err != nil {
+			cs.Phase = RolloutPhaseFailed
+			cs.Message = err.Error()
+			return applied, fmt.Errorf("cluster %s: %w", cluster, err)
+		}
+		now := metav1.Now()
+		cs.Phase = RolloutPhaseComplete
+		cs.Progress = "100%"
+		cs.CompletedAt = &now
+		cs.Message = "Healthy: " + msg
+		cs.RollbackAvailable = true
+	}
+	wd.Status.Progress = rolloutProgress(wd)
+	return applied, nil
+}
+
+// snapshot returns the workload currently running on cluster, or nil when it
+// does not exist yet.
+func (c *RolloutController) snapshot(ctx context.Context, cluster string,
+	gvr schema.GroupVersionResource, namespace, name string,
+) (*unstructured.Unstructured, error) {
+	dyn, err := c.Client.GetDynamicClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := dyn.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// waitHealthy polls the workload on cluster until CheckResourceHealth reports
+// it healthy, or returns an error once timeout elapses.
+func (c *RolloutController) waitHealthy(ctx context.Context, cluster string,
+	gvr schema.GroupVersionResource, kind, namespace, name string, timeout time.Duration,
+) (string, error) {
+	dyn, err := c.Client.GetDynamicClient(cluster)
+	if err != nil {
+		return "", err
+	}
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = defaultRolloutPollInterval
+	}
+	deadline := time.Now().Add(timeout)
+	lastMsg := "workload not found"
+	for {
+		obj, getErr := dyn.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case getErr == nil:
+			// A controller that has not observed the new spec yet still
+			// reports the previous ReplicaSet's readiness.
+			observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+			if found && observed < obj.GetGeneration() {
+				lastMsg = "waiting for controller to observe new generation"
+				break
+			}
+			status, msg := CheckResourceHealth(kind, obj)
+			if status == HealthStatusHealthy {
+				return msg, nil
+			}
+			lastMsg = fmt.Sprintf("%s (%s)", status, msg)
+		case !apierrors.IsNotFound(getErr):
+			lastMsg = getErr.Error()
+		}
+		if !time.Now().Before(deadline) {
+			return "", fmt.Errorf("health check timed out after %s: %s", timeout, lastMsg)
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return "", err
+		}
+	}
+}
+
+// rollback restores every cluster touched by this attempt, newest first, and
+// marks the deployment Failed. Clusters that never started are Skipped.
+func (c *RolloutController) rollback(ctx context.Context, wd *v1alpha1.WorkloadDeployment,
+	gvr schema.GroupVersionResource, workload *v1alpha1.ManagedWorkload,
+	deployed []deployedCluster, cause error,
+) {
+	slog.Warn("[rollout] rolling back", "name", wd.Name, "clusters", len(deployed), "cause", cause)
+	// Rollback must finish even if the reconcile context is about to expire.
+	rbCtx := context.WithoutCancel(ctx)
+	ns := workload.Spec.SourceNamespace
+	name := workload.Spec.WorkloadRef.Name
+
+	var rollbackErrs []string
+	for i := len(deployed) - 1; i >= 0; i-- {
+		d := deployed[i]
+		cs := clusterStatusFor(wd, d.cluster)
+		if err := c.restore(rbCtx, d, gvr, ns, name); err != nil {
+			slog.Error("[rollout] rollback failed", "name", wd.Name, "cluster", d.cluster, "error", err)
+			rollbackErrs = append(rollbackErrs, fmt.Sprintf("%s: %v", d.cluster, err))
+			cs.Message = fmt.Sprintf("%s; rollback failed: %v", cs.Message, err)
+			cs.Phase = RolloutPhaseFailed
+			continue
+		}
+		now := metav1.Now()
+		cs.CompletedAt = &now
+		cs.RollbackAvailable = false
+		cs.Progress = "0%"
+		if cs.Phase == RolloutPhaseFailed {
+			cs.Message += "; rolled back"
+		} else {
+			cs.Phase = RolloutPhaseFailed
+			cs.Message = "Rolled back after rollout failure"
+		}
+	}
+	for i := range wd.Status.ClusterStatuses {
+		cs := &wd.Status.ClusterStatuses[i]
+		if cs.Phase == RolloutPhasePending {
+			cs.Phase = RolloutPhaseSkipped
+			cs.Message = "Not started: rollout aborted"
+		}
+	}
+	wd.Status.Progress = rolloutProgress(wd)
+
+	msg := fmt.Sprintf("Rollout failed and was rolled back on %d cluster(s): %v", len(deployed), cause)
+	if len(rollbackErrs) > 0 {
+		msg = fmt.Sprintf("%s; rollback errors: %s", msg, strings.Join(rollbackErrs, "; "))
+	}
+	c.finish(rbCtx, wd, RolloutPhaseFailed, msg)
+}
+
+// restore puts back the manifest a cluster ran before the attempt, or deletes
+// the workload when the attempt created it.
+func (c *RolloutController) restore(ctx context.Context, d deployedCluster,
+	gvr schema.GroupVersionResource, namespace, name string,
+) error {
+	dyn, err := c.Client.GetDynamicClient(d.cluster)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, rolloutClusterTimeout)
+	defer cancel()
+	res := dyn.Resource(gvr).Namespace(namespace)
+
+	if d.previous == nil {
+		err := res.Delete(ctx, name, metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	obj := d.previous.DeepCopy()
+	delete(obj.Object, "status")
+	obj.SetManagedFields(nil)
+	current, err := res.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj.SetResourceVersion("")
+		obj.SetUID("")
+		_, err = res.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	_, err = res.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// dryRun records the plan without touching any cluster.
+func (c *RolloutController) dryRun(ctx context.Context, wd *v1alpha1.WorkloadDeployment,
+	kind, name string, steps []rolloutStep,
+) {
+	for i, step := range steps {
+		for _, cluster := range step.clusters {
+			cs := clusterStatusFor(wd, cluster)
+			if cs.Phase == RolloutPhaseComplete {
+				continue
+			}
+			cs.Phase = RolloutPhaseSkipped
+			if wd.Spec.Strategy == StrategyCanary {
+				cs.Message = fmt.Sprintf("Dry run: would apply %s/%s in step %d (weight %d%%)", kind, name, i+1, step.weight)
+			} else {
+				cs.Message = fmt.Sprintf("Dry run: would apply %s/%s in step %d", kind, name, i+1)
+			}
+		}
+	}
+	c.finish(ctx, wd, RolloutPhaseComplete,
+		fmt.Sprintf("Dry run: %d cluster(s) would be updated in %d step(s); nothing was applied",
+			len(wd.Status.ClusterStatuses), len(steps)))
+}
+
+// suspended re-reads the CR and reports whether spec.suspend was set since
+// the rollout started.
+func (c *RolloutController) suspended(ctx context.Context, wd *v1alpha1.WorkloadDeployment) bool {
+	if c.Refresh == nil {
+		return false
+	}
+	latest, err := c.Refresh(ctx)
+	if err != nil || latest == nil {
+		if err != nil {
+			slog.Warn("[rollout] failed to refresh WorkloadDeployment", "name", wd.Name, "error", err)
+		}
+		return false
+	}
+	if latest.ResourceVersion != "" {
+		wd.ResourceVersion = latest.ResourceVersion
+	}
+	return latest.Spec.Suspend
+}
+
+func (c *RolloutController) pause(ctx context.Context, wd *v1alpha1.WorkloadDeployment, message string) {
+	wd.Status.Phase = RolloutPhasePaused
+	wd.Status.Progress = rolloutProgress(wd)
+	slog.Info("[rollout] deployment paused", "name", wd.Name, "reason", message)
+	wd.Status.Conditions = setRolloutCondition(wd.Status.Conditions, wd.Generation, "Paused", message)
+	c.persist(ctx, wd)
+}
+
+// failPending marks every cluster that has not completed as Failed.
+func (c *RolloutController) failPending(wd *v1alpha1.WorkloadDeployment, message string) {
+	now := metav1.Now()
+	for i := range wd.Status.ClusterStatuses {
+		cs := &wd.Status.ClusterStatuses[i]
+		if cs.Phase == RolloutPhaseComplete {
+			continue
+		}
+		cs.Phase = RolloutPhaseFailed
+		cs.Message = message
+		cs.CompletedAt = &now
+	}
+	wd.Status.Progress = rolloutProgress(wd)
+}
+
+// finish sets a terminal phase and appends a history entry.
+func (c *RolloutController) finish(ctx context.Context, wd *v1alpha1.WorkloadDeployment, phase, message string) {
+	now := metav1.Now()
+	wd.Status.Phase = phase
+	wd.Status.CompletedAt = &now
+	if len(wd.Status.ClusterStatuses) > 0 {
+		wd.Status.Progress = rolloutProgress(wd)
+	}
+
+	nextRevision := 1
+	for _, entry := range wd.Status.History {
+		if entry.Revision >= nextRevision {
+			nextRevision = entry.Revision + 1
+		}
+	}
+	wd.Status.History = append(wd.Status.History, v1alpha1.DeploymentHistoryEntry{
+		Revision:    nextRevision,
+		StartedAt:   wd.Status.StartedAt,
+		CompletedAt: &now,
+		Phase:       phase,
+		Message:     message,
+	})
+	reason := "RolloutComplete"
+	if phase == RolloutPhaseFailed {
+		reason = "RolloutFailed"
+	}
+	wd.Status.Conditions = setRolloutCondition(wd.Status.Conditions, wd.Generation, reason, message)
+
+	slog.Info("[rollout] deployment reached terminal state",
+		"name", wd.Name, "phase", phase, "message", message)
+	c.persist(ctx, wd)
+}
+
+func (c *RolloutController) persist(ctx context.Context, wd *v1alpha1.WorkloadDeployment) {
+	if c.UpdateStatus != nil {
+		c.UpdateStatus(ctx, wd)
+	}
+}
+
+// initClusterStatuses lays out one status per target, keeping Complete
+// entries from a resumed rollout.
+func (c *RolloutController) initClusterStatuses(wd *v1alpha1.WorkloadDeployment, targets []string, completed map[string]bool) {
+	previous := make(map[string]v1alpha1.ClusterRolloutStatus, len(wd.Status.ClusterStatuses))
+	for _, cs := range wd.Status.ClusterStatuses {
+		previous[cs.Cluster] = cs
+	}
+	statuses := make([]v1alpha1.ClusterRolloutStatus, len(targets))
+	for i, cluster := range targets {
+		if completed[cluster] {
+			statuses[i] = previous[cluster]
+			continue
+		}
+		statuses[i] = v1alpha1.ClusterRolloutStatus{Cluster: cluster, Phase: RolloutPhasePending}
+	}
+	wd.Status.ClusterStatuses = statuses
+	wd.Status.Phase = RolloutPhaseInProgress
+	wd.Status.CompletedAt = nil
+	wd.Status.Progress = rolloutProgress(wd)
+}
+
+// planRolloutSteps splits targets into the batches the strategy updates
+// together. RollingUpdate moves maxUnavailable clusters (default 1) at a
+// time. Canary interprets weights as the share of target clusters running
+// the new version: initialWeight, then +stepWeight up to maxWeight, then a
+// final promotion step to 100% that requires autoPromote. Any other strategy
+// is a single step covering every target.
+func planRolloutSteps(spec v1alpha1.WorkloadDeploymentSpec, targets []string) []rolloutStep {
+	n := len(targets)
+	if n == 0 {
+		return nil
+	}
+	switch spec.Strategy {
+	case StrategyRollingUpdate:
+		batch := 1
+		if spec.RolloutConfig != nil && spec.RolloutConfig.MaxUnavailable != nil && *spec.RolloutConfig.MaxUnavailable > 1 {
+			batch = int(*spec.RolloutConfig.MaxUnavailable)
+		}
+		steps := make([]rolloutStep, 0, (n+batch-1)/batch)
+		for start := 0; start < n; start += batch {
+			end := min(start+batch, n)
+			steps = append(steps, rolloutStep{
+				clusters: targets[start:end],
+				weight:   end * canaryFullWeight / n,
+			})
+		}
+		return steps
+	case StrategyCanary:
+		initial, step, maxWeight := defaultCanaryInitialWeight, defaultCanaryStepWeight, canaryFullWeight
+		if cfg := spec.CanaryConfig; cfg != nil {
+			if cfg.InitialWeight > 0 {
+				initial = cfg.InitialWeight
+			}
+			if cfg.StepWeight > 0 {
+				step = cfg.StepWeight
+			}
+			if cfg.MaxWeight > 0 {
+				maxWeight = cfg.MaxWeight
+			}
+		}
+		maxWeight = min(maxWeight, canaryFullWeight)
+		initial = min(initial, maxWeight)
+
+		var steps []rolloutStep
+		done := 0
+		addStep := func(weight int, promotion bool) {
+			// Round down and hold back the last cluster so that, with more
+			// than one target, only the promotion step reaches 100%.
+			upto := n
+			if !promotion {
+				upto = max(weight*n/canaryFullWeight, 1)
+				if n > 1 {
+					upto = min(upto, n-1)
+				}
+			}
+			if upto <= done {
+				return
+			}
+			steps = append(steps, rolloutStep{clusters: targets[done:upto], weight: weight, promotion: promotion})
+			done = upto
+		}
+		for w := initial; w < maxWeight; w += step {
+			addStep(w, false)
+		}
+		if maxWeight < canaryFullWeight {
+			addStep(maxWeight, false)
+		}
+		addStep(canaryFullWeight, true)
+		return steps
+	default:
+		return []rolloutStep{{clusters: targets, weight: canaryFullWeight}}
+	}
+}
+
+func parseRolloutSettings(spec v1alpha1.WorkloadDeploymentSpec) (rolloutSettings, error) {
+	s := rolloutSettings{healthTimeout: defaultRolloutHealthTimeout}
+	if cfg := spec.RolloutConfig; cfg != nil {
+		if cfg.HealthCheckTimeout != "" {
+			d, err := time.ParseDuration(cfg.HealthCheckTimeout)
+			if err != nil || d <= 0 {
+				return s, fmt.Errorf("invalid rolloutConfig.healthCheckTimeout %q", cfg.HealthCheckTimeout)
+			}
+			s.healthTimeout = d
+		}
+		if cfg.PauseBetweenClusters != "" && spec.Strategy != StrategyCanary {
+			d, err := time.ParseDuration(cfg.PauseBetweenClusters)
+			if err != nil || d < 0 {
+				return s, fmt.Errorf("invalid rolloutConfig.pauseBetweenClusters %q", cfg.PauseBetweenClusters)
+			}
+			s.pause = d
+		}
+	}
+	if spec.Strategy == StrategyCanary {
+		s.pause = defaultCanaryStepInterval
+		if cfg := spec.CanaryConfig; cfg != nil && cfg.StepInterval != "" {
+			d, err := time.ParseDuration(cfg.StepInterval)
+			if err != nil || d < 0 {
+				return s, fmt.Errorf("invalid canaryConfig.stepInterval %q", cfg.StepInterval)
+			}
+			s.pause = d
+		}
+	}
+	return s, nil
+}
+
+// workloadGVRForKind maps a ManagedWorkload's workloadRef.kind to its GVR.
+// An empty kind means Deployment, matching DeployWorkload's lookup order.
+func workloadGVRForKind(kind string) (schema.GroupVersionResource, string, error) {
+	switch kind {
+	case "", "Deployment":
+		return gvrDeployments, "Deployment", nil
+	case "StatefulSet":
+		return gvrStatefulSets, kind, nil
+	case "DaemonSet":
+		return gvrDaemonSets, kind, nil
+	default:
+		return schema.GroupVersionResource{}, "", fmt.Errorf("unsupported workload kind %q for progressive rollout", kind)
+	}
+}
+
+func clusterStatusFor(wd *v1alpha1.WorkloadDeployment, cluster string) *v1alpha1.ClusterRolloutStatus {
+	for i := range wd.Status.ClusterStatuses {
+		if wd.Status.ClusterStatuses[i].Cluster == cluster {
+			return &wd.Status.ClusterStatuses[i]
+		}
+	}
+	wd.Status.ClusterStatuses = append(wd.Status.ClusterStatuses,
+		v1alpha1.ClusterRolloutStatus{Cluster: cluster, Phase: RolloutPhasePending})
+	return &wd.Status.ClusterStatuses[len(wd.Status.ClusterStatuses)-1]
+}
+
+func rolloutProgress(wd *v1alpha1.WorkloadDeployment) string {
+	done := 0
+	for _, cs := range wd.Status.ClusterStatuses {
+		if cs.Phase == RolloutPhaseComplete {
+			done++
+		}
+	}
+	return fmt.Sprintf("%d/%d clusters", done, len(wd.Status.ClusterStatuses))
+}
+
+// setRolloutCondition upserts the "Progressing" condition.
+func setRolloutCondition(conds []metav1.Condition, generation int64, reason, message string) []metav1.Condition {
+	status := metav1.ConditionTrue
+	if reason != "RolloutComplete" {
+		status = metav1.ConditionFalse
+	}
+	cond := metav1.Condition{
+		Type:               "Progressing",
+		Status:             status,
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+	for i := range conds {
+		if conds[i].Type == cond.Type {
+			if conds[i].Status == cond.Status {
+				cond.LastTransitionTime = conds[i].LastTransitionTime
+			}
+			conds[i] = cond
+			return conds
+		}
+	}
+	return append(conds, cond)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
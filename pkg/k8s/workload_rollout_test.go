@@ -0,0 +1,344 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kubestellar/console/pkg/api/v1alpha1"
+)
+
+// fakeRolloutClient applies workloads straight into per-cluster fake dynamic
+// clients. Clusters listed in unhealthy get a Deployment that never becomes
+// ready, which drives the health-timeout and rollback paths.
+type fakeRolloutClient struct {
+	mu        sync.Mutex
+	clusters  map[string]dynamic.Interface
+	unhealthy map[string]bool
+	image     string
+	deployed  []string
+}
+
+func newFakeRolloutClient(names ...string) *fakeRolloutClient {
+	f := &fakeRolloutClient{
+		clusters:  make(map[string]dynamic.Interface),
+		unhealthy: make(map[string]bool),
+		image:     "nginx:v2",
+	}
+	for _, name := range names {
+		f.clusters[name] = dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), buildTestGVRMap())
+	}
+	return f
+}
+
+func (f *fakeRolloutClient) GetDynamicClient(cluster string) (dynamic.Interface, error) {
+	dyn, ok := f.clusters[cluster]
+	if !ok {
+		return nil, fmt.Errorf("no client for %s", cluster)
+	}
+	return dyn, nil
+}
+
+func (f *fakeRolloutClient) DeployWorkload(ctx context.Context, _, namespace, name string,
+	targets []string, _ int32, _ *DeployOptions,
+) (*v1alpha1.DeployResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cluster := range targets {
+		f.deployed = append(f.deployed, cluster)
+		ready := int64(1)
+		if f.unhealthy[cluster] {
+			ready = 0
+		}
+		obj := testRolloutDeployment(namespace, name, f.image, ready)
+		res := f.clusters[cluster].Resource(gvrDeployments).Namespace(namespace)
+		existing, err := res.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if _, err := res.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		if _, err := res.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	return &v1alpha1.DeployResponse{Success: true, DeployedTo: targets}, nil
+}
+
+func (f *fakeRolloutClient) deployedClusters() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.deployed...)
+}
+
+func testRolloutDeployment(namespace, name, image string, ready int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "app", "image": image}},
+			}},
+		},
+		"status": map[string]interface{}{
+			"readyReplicas":     ready,
+			"availableReplicas": ready,
+			"updatedReplicas":   ready,
+		},
+	}}
+}
+
+func deploymentImage(t *testing.T, dyn dynamic.Interface, namespace, name string) string {
+	t.Helper()
+	obj, err := dyn.Resource(gvrDeployments).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatalf("get deployment: %v", err)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if len(containers) == 0 {
+		return ""
+	}
+	image, _, _ := unstructured.NestedString(containers[0].(map[string]interface{}), "image")
+	return image
+}
+
+func testRolloutWorkload() *v1alpha1.ManagedWorkload {
+	return &v1alpha1.ManagedWorkload{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "console"},
+		Spec: v1alpha1.ManagedWorkloadSpec{
+			SourceCluster:   "source",
+			SourceNamespace: "apps",
+			WorkloadRef:     v1alpha1.WorkloadReference{Kind: "Deployment", Name: "web"},
+		},
+	}
+}
+
+func newTestRolloutController(client RolloutClient) (*RolloutController, *int) {
+	writes := 0
+	return &RolloutController{
+		Client:       client,
+		UpdateStatus: func(context.Context, *v1alpha1.WorkloadDeployment) { writes++ },
+		PollInterval: time.Millisecond,
+	}, &writes
+}
+
+func clusterPhases(wd *v1alpha1.WorkloadDeployment) map[string]string {
+	out := make(map[string]string, len(wd.Status.ClusterStatuses))
+	for _, cs := range wd.Status.ClusterStatuses {
+		out[cs.Cluster] = cs.Phase
+	}
+	return out
+}
+
+func TestRolloutController_RollingUpdateSucceeds(t *testing.T) {
+	client := newFakeRolloutClient("c1", "c2", "c3")
+	ctrl, writes := newTestRolloutController(client)
+	wd := &v1alpha1.WorkloadDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "wd", Generation: 2},
+		Spec: v1alpha1.WorkloadDeploymentSpec{
+			Strategy:      StrategyRollingUpdate,
+			RolloutConfig: &v1alpha1.RolloutConfig{PauseBetweenClusters: "1ms", HealthCheckTimeout: "1s"},
+		},
+	}
+
+	ctrl.Run(context.Background(), wd, testRolloutWorkload(), []string{"c3", "c1", "c2"}, false)
+
+	if wd.Status.Phase != RolloutPhaseComplete {
+		t.Fatalf("phase = %s, want Complete (history %+v)", wd.Status.Phase, wd.Status.History)
+	}
+	if got := client.deployedClusters(); fmt.Sprint(got) != "[c1 c2 c3]" {
+		t.Errorf("deploy order = %v, want cluster by cluster in sorted order", got)
+	}
+	for cluster, phase := range clusterPhases(wd) {
+		if phase != RolloutPhaseComplete {
+			t.Errorf("cluster %s phase = %s, want Complete", cluster, phase)
+		}
+	}
+	if wd.Status.Progress != "3/3 clusters" {
+		t.Errorf("progress = %q", wd.Status.Progress)
+	}
+	if len(wd.Status.History) != 1 || wd.Status.History[0].Phase != RolloutPhaseComplete {
+		t.Errorf("history = %+v", wd.Status.History)
+	}
+	if *writes == 0 {
+		t.Error("status was never persisted")
+	}
+}
+
+func TestRolloutController_HealthTimeoutRollsBack(t *testing.T) {
+	client := newFakeRolloutClient("c1", "c2", "c3")
+	client.unhealthy["c2"] = true
+	// c1 already runs v1; c2 has never run the workload.
+	if _, err := client.clusters["c1"].Resource(gvrDeployments).Namespace("apps").Create(context.Background(),
+		testRolloutDeployment("apps", "web", "nginx:v1", 1), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	ctrl, _ := newTestRolloutController(client)
+	wd := &v1alpha1.WorkloadDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "wd"},
+		Spec: v1alpha1.WorkloadDeploymentSpec{
+			Strategy:      StrategyRollingUpdate,
+			RolloutConfig: &v1alpha1.RolloutConfig{HealthCheckTimeout: "20ms"},
+		},
+	}
+
+	ctrl.Run(context.Background(), wd, testRolloutWorkload(), []string{"c1", "c2", "c3"}, false)
+
+	if wd.Status.Phase != RolloutPhaseFailed {
+		t.Fatalf("phase = %s, want Failed", wd.Status.Phase)
+	}
+	phases := clusterPhases(wd)
+	if phases["c1"] != RolloutPhaseFailed || phases["c2"] != RolloutPhaseFailed || phases["c3"] != RolloutPhaseSkipped {
+		t.Errorf("cluster phases = %v", phases)
+	}
+	if got := deploymentImage(t, client.clusters["c1"], "apps", "web"); got != "nginx:v1" {
+		t.Errorf("c1 image after rollback = %q, want nginx:v1", got)
+	}
+	if got := deploymentImage(t, client.clusters["c2"], "apps", "web"); got != "" {
+		t.Errorf("c2 should have had its new workload deleted, image = %q", got)
+	}
+	for _, cluster := range client.deployedClusters() {
+		if cluster == "c3" {
+			t.Error("c3 must not be touched after the health gate failed")
+		}
+	}
+	if len(wd.Status.History) != 1 || wd.Status.History[0].Phase != RolloutPhaseFailed {
+		t.Fatalf("history = %+v", wd.Status.History)
+	}
+}
+
+func TestRolloutController_CanaryPausesForPromotion(t *testing.T) {
+	names := []string{"c0", "c1", "c2", "c3", "c4", "c5", "c6", "c7", "c8", "c9"}
+	client := newFakeRolloutClient(names...)
+	ctrl, _ := newTestRolloutController(client)
+	wd := &v1alpha1.WorkloadDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "wd"},
+		Spec: v1alpha1.WorkloadDeploymentSpec{
+			Strategy:     StrategyCanary,
+			CanaryConfig: &v1alpha1.CanaryConfig{InitialWeight: 20, StepWeight: 30, StepInterval: "1ms"},
+		},
+	}
+
+	ctrl.Run(context.Background(), wd, testRolloutWorkload(), names, false)
+
+	if wd.Status.Phase != RolloutPhasePaused {
+		t.Fatalf("phase = %s, want Paused awaiting promotion", wd.Status.Phase)
+	}
+	if wd.Status.CanaryStatus == nil || wd.Status.CanaryStatus.CurrentWeight != 80 || wd.Status.CanaryStatus.CurrentStep != 3 {
+		t.Fatalf("canary status = %+v, want weight 80 at step 3", wd.Status.CanaryStatus)
+	}
+	if got := len(client.deployedClusters()); got != 8 {
+		t.Fatalf("deployed to %d clusters before promotion, want 8", got)
+	}
+
+	// Promoting resumes without re-applying the canary clusters.
+	wd.Spec.AutoPromote = true
+	ctrl.Run(context.Background(), wd, testRolloutWorkload(), names, true)
+
+	if wd.Status.Phase != RolloutPhaseComplete {
+		t.Fatalf("phase after promotion = %s", wd.Status.Phase)
+	}
+	if got := len(client.deployedClusters()); got != 10 {
+		t.Errorf("total deploys = %d, want 10", got)
+	}
+	if wd.Status.CanaryStatus.CurrentWeight != 100 {
+		t.Errorf("final weight = %d", wd.Status.CanaryStatus.CurrentWeight)
+	}
+}
+
+func TestRolloutController_DryRunAndSuspend(t *testing.T) {
+	client := newFakeRolloutClient("c1", "c2")
+	ctrl, _ := newTestRolloutController(client)
+
+	dry := &v1alpha1.WorkloadDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "dry"},
+		Spec:       v1alpha1.WorkloadDeploymentSpec{Strategy: StrategyRollingUpdate, DryRun: true},
+	}
+	ctrl.Run(context.Background(), dry, testRolloutWorkload(), []string{"c1", "c2"}, false)
+	if dry.Status.Phase != RolloutPhaseComplete {
+		t.Errorf("dry run phase = %s", dry.Status.Phase)
+	}
+	for cluster, phase := range clusterPhases(dry) {
+		if phase != RolloutPhaseSkipped {
+			t.Errorf("dry run cluster %s phase = %s, want Skipped", cluster, phase)
+		}
+	}
+
+	suspended := &v1alpha1.WorkloadDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "held"},
+		Spec:       v1alpha1.WorkloadDeploymentSpec{Strategy: StrategyRollingUpdate, Suspend: true},
+	}
+	ctrl.Run(context.Background(), suspended, testRolloutWorkload(), []string{"c1", "c2"}, false)
+	if suspended.Status.Phase != RolloutPhasePaused {
+		t.Errorf("suspended phase = %s, want Paused", suspended.Status.Phase)
+	}
+	if got := client.deployedClusters(); len(got) != 0 {
+		t.Errorf("dry run / suspend deployed to %v", got)
+	}
+
+	// Suspending mid-rollout stops before the next cluster.
+	refreshes := 0
+	ctrl.Refresh = func(context.Context) (*v1alpha1.WorkloadDeployment, error) {
+		refreshes++
+		return &v1alpha1.WorkloadDeployment{Spec: v1alpha1.WorkloadDeploymentSpec{Suspend: refreshes > 1}}, nil
+	}
+	live := &v1alpha1.WorkloadDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "live"},
+		Spec:       v1alpha1.WorkloadDeploymentSpec{Strategy: StrategyRollingUpdate},
+	}
+	ctrl.Run(context.Background(), live, testRolloutWorkload(), []string{"c1", "c2"}, false)
+	if live.Status.Phase != RolloutPhasePaused || live.Status.Progress != "1/2 clusters" {
+		t.Errorf("mid-rollout suspend: phase = %s progress = %s", live.Status.Phase, live.Status.Progress)
+	}
+}
+
+func TestPlanRolloutSteps(t *testing.T) {
+	targets := []string{"a", "b", "c", "d", "e"}
+	two := int32(2)
+	tests := []struct {
+		name  string
+		spec  v1alpha1.WorkloadDeploymentSpec
+		sizes []int
+	}{
+		{name: "rolling one at a time", spec: v1alpha1.WorkloadDeploymentSpec{Strategy: StrategyRollingUpdate}, sizes: []int{1, 1, 1, 1, 1}},
+		{name: "rolling maxUnavailable", spec: v1alpha1.WorkloadDeploymentSpec{
+			Strategy: StrategyRollingUpdate, RolloutConfig: &v1alpha1.RolloutConfig{MaxUnavailable: &two},
+		}, sizes: []int{2, 2, 1}},
+		{name: "canary defaults", spec: v1alpha1.WorkloadDeploymentSpec{Strategy: StrategyCanary}, sizes: []int{1, 1, 1, 1, 1}},
+		{name: "canary capped weight", spec: v1alpha1.WorkloadDeploymentSpec{
+			Strategy: StrategyCanary, CanaryConfig: &v1alpha1.CanaryConfig{InitialWeight: 40, StepWeight: 50, MaxWeight: 60},
+		}, sizes: []int{2, 1, 2}},
+		{name: "recreate all at once", spec: v1alpha1.WorkloadDeploymentSpec{Strategy: StrategyRecreate}, sizes: []int{5}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			steps := planRolloutSteps(tc.spec, targets)
+			got := make([]int, len(steps))
+			for i, s := range steps {
+				got[i] = len(s.clusters)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.sizes) {
+				t.Fatalf("step sizes = %v, want %v", got, tc.sizes)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package kagent
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,6 +11,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/kubestellar/console/pkg/a2a"
 )
 
 // maxKAgentResponseBytes caps io.ReadAll on kagent API responses.
@@ -26,13 +27,8 @@ type AgentInfo struct {
 	Tools       []string `json:"tools,omitempty"`
 }
 
-// AgentCard is the A2A agent card returned by the /.well-known/agent.json endpoint.
-type AgentCard struct {
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	URL          string   `json:"url"`
-	Capabilities []string `json:"capabilities,omitempty"`
-}
+// AgentCard is the A2A agent card of a kagent agent.
+type AgentCard = a2a.AgentCard
 
 // KagentClient proxies requests to the kagent A2A protocol endpoint.
 type KagentClient struct {
@@ -99,90 +95,28 @@ func (c *KagentClient) ListAgents() ([]AgentInfo, error) {
 	return agents, nil
 }
 
-// Discover fetches the A2A agent card for the given agent.
-func (c *KagentClient) Discover(namespace, agentName string) (*AgentCard, error) {
-	url := fmt.Sprintf("%s/api/a2a/%s/%s/.well-known/agent.json",
+// A2AClient returns a generic A2A client for one kagent agent. kagent
+// serves each agent's A2A endpoint under /api/a2a/<namespace>/<name>.
+func (c *KagentClient) A2AClient(namespace, agentName string) *a2a.Client {
+	url := fmt.Sprintf("%s/api/a2a/%s/%s",
 		c.baseURL, neturl.PathEscape(namespace), neturl.PathEscape(agentName))
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover agent %s/%s: %w", namespace, agentName, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(io.LimitReader(resp.Body, maxKAgentResponseBytes))
-		if err != nil {
-			slog.Warn("failed to read response body", "error", err)
-		}
-		return nil, fmt.Errorf("discover agent %s/%s returned %d: %s", namespace, agentName, resp.StatusCode, string(body))
-	}
-
-	var card AgentCard
-	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
-		return nil, fmt.Errorf("failed to decode agent card: %w", err)
-	}
-	return &card, nil
+	return a2a.NewClient(url, a2a.WithHTTPClient(c.httpClient))
 }
 
-// a2aRequest is the JSON-RPC 2.0 envelope sent to the A2A endpoint.
-type a2aRequest struct {
-	JSONRPC string         `json:"jsonrpc"`
-	Method  string         `json:"method"`
-	Params  map[string]any `json:"params"`
+// Discover fetches the A2A agent card for the given agent.
+func (c *KagentClient) Discover(namespace, agentName string) (*AgentCard, error) {
+	return c.A2AClient(namespace, agentName).Discover(context.Background())
 }
 
 // Invoke sends a message to an agent via the A2A protocol and returns the raw
 // response body for streaming consumption.
 func (c *KagentClient) Invoke(ctx context.Context, namespace, agentName, message string, contextID string) (io.ReadCloser, error) {
-	params := map[string]any{
-		"message": map[string]any{
-			"role": "user",
-			"parts": []map[string]any{
-				{"kind": "text", "text": message},
-			},
+	return c.A2AClient(namespace, agentName).Call(ctx, a2a.MethodSendMessage, a2a.MessageSendParams{
+		Message: a2a.NewTextMessage(message, contextID, ""),
+		Configuration: &a2a.MessageSendConfiguration{
+			AcceptedOutputModes: []string{"text"},
 		},
-		"configuration": map[string]any{
-			"acceptedOutputModes": []string{"text"},
-		},
-	}
-	if contextID != "" {
-		params["contextId"] = contextID
-	}
-
-	body := a2aRequest{
-		JSONRPC: "2.0",
-		Method:  "message/send",
-		Params:  params,
-	}
-
-	payload, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal A2A request: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/api/a2a/%s/%s",
-		c.baseURL, neturl.PathEscape(namespace), neturl.PathEscape(agentName))
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("A2A invoke failed: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		defer resp.Body.Close()
-		errBody, err := io.ReadAll(io.LimitReader(resp.Body, maxKAgentResponseBytes))
-		if err != nil {
-			slog.Warn("failed to read response body", "error", err)
-		}
-		return nil, fmt.Errorf("A2A invoke returned %d: %s", resp.StatusCode, string(errBody))
-	}
-
-	return resp.Body, nil
+	})
 }
 
 // buildDetectCandidates constructs the list of candidate URLs for auto-detection.
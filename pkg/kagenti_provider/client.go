@@ -11,6 +11,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/kubestellar/console/pkg/a2a"
 )
 
 // drainAndClose fully drains and closes an HTTP response body.
@@ -62,13 +64,8 @@ type AgentInfo struct {
 	Tools       []string `json:"tools,omitempty"`
 }
 
-// AgentCard is the A2A agent card returned by the /.well-known/agent.json endpoint.
-type AgentCard struct {
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	URL          string   `json:"url"`
-	Capabilities []string `json:"capabilities,omitempty"`
-}
+// AgentCard is the A2A agent card of a kagenti agent.
+type AgentCard = a2a.AgentCard
 
 // KagentiClient proxies requests to the kagenti A2A protocol endpoint.
 type KagentiClient struct {
@@ -288,29 +285,20 @@ func decodeAgentList(body io.Reader) ([]AgentInfo, error) {
 	return nil, fmt.Errorf("unsupported list response shape")
 }
 
-// Discover fetches the A2A agent card for the given agent.
-func (c *KagentiClient) Discover(namespace, agentName string) (*AgentCard, error) {
-	url := fmt.Sprintf("%s/api/a2a/%s/%s/.well-known/agent.json",
-		c.baseURL, neturl.PathEscape(namespace), neturl.PathEscape(agentName))
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover agent %s/%s: %w", namespace, agentName, err)
-	}
-	defer drainAndClose(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(io.LimitReader(resp.Body, maxKAgentResponseBytes))
-		if err != nil {
-			slog.Warn("failed to read response body", "error", err)
-		}
-		return nil, fmt.Errorf("discover agent %s/%s returned %d: %s", namespace, agentName, resp.StatusCode, string(body))
+// A2AClient returns a generic A2A client for one agent behind the kagenti
+// controller, or for the direct agent when KAGENTI_AGENT_URL is set.
+func (c *KagentiClient) A2AClient(namespace, agentName string) *a2a.Client {
+	url := c.directAgentURL
+	if url == "" {
+		url = fmt.Sprintf("%s/api/a2a/%s/%s",
+			c.baseURL, neturl.PathEscape(namespace), neturl.PathEscape(agentName))
 	}
+	return a2a.NewClient(url, a2a.WithHTTPClient(c.httpClient))
+}
 
-	var card AgentCard
-	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
-		return nil, fmt.Errorf("failed to decode agent card: %w", err)
-	}
-	return &card, nil
+// Discover fetches the A2A agent card for the given agent.
+func (c *KagentiClient) Discover(namespace, agentName string) (*AgentCard, error) {
+	return c.A2AClient(namespace, agentName).Discover(context.Background())
 }
 
 // HistoryMessage represents a single message in conversation history passed to Invoke.
@@ -0,0 +1,353 @@
+// Package kbmirror keeps a verified local copy of the kubestellar/console-kb
+// mission knowledge base so the console can serve missions without reaching
+// GitHub — for air-gapped installs and for installs that keep hitting the
+// GitHub rate limit.
+//
+// A bundle is a gzipped tar holding manifest.json (the version and the
+// SHA-256 of every file in the tree), an optional manifest.sig (a base64
+// Ed25519 signature of manifest.json) and the files themselves under
+// files/. An incremental bundle lists the whole tree in its manifest but
+// only carries the files that changed since its base version; the rest are
+// taken from the installed copy after their checksums are matched.
+package kbmirror
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ManifestFormat identifies the bundle layout in manifest.json.
+	ManifestFormat = "kc-kb-bundle-v1"
+	// MediaType is the OCI layer media type of a bundle.
+	MediaType = "application/vnd.kubestellar.console-kb.bundle.v1.tar+gzip"
+	// ArtifactType is the OCI artifact type of a bundle manifest.
+	ArtifactType = "application/vnd.kubestellar.console-kb.v1"
+	// AnnotationBase marks an OCI layer as an incremental bundle on top of
+	// the version it names.
+	AnnotationBase = "io.kubestellar.console-kb.base"
+
+	manifestName  = "manifest.json"
+	signatureName = "manifest.sig"
+	filesPrefix   = "files/"
+
+	// maxFileBytes matches the largest file the GitHub path would proxy.
+	maxFileBytes = 10 << 20
+	// maxManifestBytes caps manifest.json; a console-kb manifest is a few
+	// MB at most.
+	maxManifestBytes = 32 << 20
+	// maxSignatureBytes caps manifest.sig.
+	maxSignatureBytes = 1 << 10
+	// maxBundleFiles and maxTreeBytes bound what one bundle may unpack.
+	maxBundleFiles = 100000
+	maxTreeBytes   = 1 << 30
+	// maxVersionLen bounds the version string, which names a directory.
+	maxVersionLen = 64
+)
+
+// File is one file of the knowledge base tree.
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Format  string `json:"format"`
+	Version string `json:"version"`
+	// Base is the version an incremental bundle was built against; empty
+	// for a full bundle.
+	Base string `json:"base,omitempty"`
+	// Source records where the tree came from, e.g.
+	// "kubestellar/console-kb@<commit>".
+	Source    string    `json:"source,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Files lists the complete tree, sorted by path.
+	Files []File `json:"files"`
+}
+
+// Validate checks the manifest is well formed and within the unpack limits.
+func (m *Manifest) Validate() error {
+	if m.Format != ManifestFormat {
+		return fmt.Errorf("unsupported bundle format %q", m.Format)
+	}
+	if err := validateVersion(m.Version); err != nil {
+		return err
+	}
+	if m.Base != "" {
+		if err := validateVersion(m.Base); err != nil {
+			return fmt.Errorf("base: %w", err)
+		}
+	}
+	if len(m.Files) == 0 {
+		return errors.New("manifest lists no files")
+	}
+	if len(m.Files) > maxBundleFiles {
+		return fmt.Errorf("manifest lists %d files, more than %d", len(m.Files), maxBundleFiles)
+	}
+	var total int64
+	seen := make(map[string]bool, len(m.Files))
+	for _, f := range m.Files {
+		if err := validatePath(f.Path); err != nil {
+			return fmt.Errorf("file %q: %w", f.Path, err)
+		}
+		if seen[f.Path] {
+			return fmt.Errorf("file %q listed twice", f.Path)
+		}
+		seen[f.Path] = true
+		if b, err := hex.DecodeString(f.SHA256); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("file %q: invalid sha256", f.Path)
+		}
+		if f.Size < 0 || f.Size > maxFileBytes {
+			return fmt.Errorf("file %q: size %d out of range", f.Path, f.Size)
+		}
+		total += f.Size
+	}
+	if total > maxTreeBytes {
+		return fmt.Errorf("tree of %d bytes exceeds %d", total, maxTreeBytes)
+	}
+	// A file cannot also be a directory of another file.
+	for p := range seen {
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if seen[dir] {
+				return fmt.Errorf("%q is both a file and a directory", dir)
+			}
+		}
+	}
+	return nil
+}
+
+// validateVersion keeps versions usable as a directory name.
+func validateVersion(v string) error {
+	if v == "" || len(v) > maxVersionLen || v[0] == '.' || v[0] == '-' {
+		return fmt.Errorf("invalid version %q", v)
+	}
+	for _, ch := range v {
+		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch == '.' || ch == '-' || ch == '_' || ch == '+' {
+			continue
+		}
+		return fmt.Errorf("invalid version %q", v)
+	}
+	return nil
+}
+
+// validatePath accepts clean, relative, slash-separated paths that cannot
+// leave the tree.
+func validatePath(p string) error {
+	if p == "" || strings.HasPrefix(p, "/") || strings.ContainsAny(p, "\\\x00") || path.Clean(p) != p {
+		return errors.New("not a clean relative path")
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == ".." || seg == "." {
+			return errors.New("not a clean relative path")
+		}
+	}
+	return nil
+}
+
+// KeyID identifies a bundle signing key: the first 8 bytes of the SHA-256
+// of the public key, hex-encoded.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// verifySignature checks a manifest.sig against the raw manifest bytes.
+func verifySignature(pub ed25519.PublicKey, manifest, sig []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("decode signature: %w", err)
+	}
+	if !ed25519.Verify(pub, manifest, raw) {
+		return errors.New("bundle signature does not match the trusted key")
+	}
+	return nil
+}
+
+// BuildOptions configures Build.
+type BuildOptions struct {
+	Version string
+	Source  string
+	// Base, when set, makes an incremental bundle: files whose checksum is
+	// unchanged from Base are listed in the manifest but not packed.
+	Base *Manifest
+	// Key signs the manifest when set.
+	Key ed25519.PrivateKey
+	// Now stamps the manifest; defaults to time.Now.
+	Now time.Time
+}
+
+// Build packs the console-kb checkout at srcDir into a bundle written to w.
+// Dot-prefixed entries (.git, .github, ...) are never served and are left
+// out, as are symlinks and other non-regular files.
+func Build(w io.Writer, srcDir string, opts BuildOptions) (*Manifest, error) {
+	if err := validateVersion(opts.Version); err != nil {
+		return nil, err
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	m := &Manifest{Format: ManifestFormat, Version: opts.Version, Source: opts.Source, CreatedAt: now.UTC()}
+	err := filepath.WalkDir(srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != srcDir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		sum, size, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, File{Path: filepath.ToSlash(rel), SHA256: sum, Size: size})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	unchanged := map[string]bool{}
+	if opts.Base != nil {
+		m.Base = opts.Base.Version
+		base := make(map[string]string, len(opts.Base.Files))
+		for _, f := range opts.Base.Files {
+			base[f.Path] = f.SHA256
+		}
+		for _, f := range m.Files {
+			if base[f.Path] == f.SHA256 {
+				unchanged[f.Path] = true
+			}
+		}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, manifestName, manifest, now); err != nil {
+		return nil, err
+	}
+	if opts.Key != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(opts.Key, manifest))
+		if err := writeTarFile(tw, signatureName, []byte(sig), now); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range m.Files {
+		if unchanged[f.Path] {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(srcDir, filepath.FromSlash(f.Path)))
+		if err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, filesPrefix+f.Path, data, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadManifest returns the manifest of the bundle read from r, for use as
+// the base of an incremental build.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.New("bundle has no manifest.json")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bundle: %w", err)
+		}
+		if hdr.Name != manifestName {
+			continue
+		}
+		data, err := readLimited(tr, maxManifestBytes)
+		if err != nil {
+			return nil, err
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decode manifest: %w", err)
+		}
+		return &m, nil
+	}
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("entry exceeds %d bytes", limit)
+	}
+	return data, nil
+}
+
+func hashFile(p string) (string, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
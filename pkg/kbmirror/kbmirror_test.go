@@ -0,0 +1,337 @@
+package kbmirror
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+	"github.com/kubestellar/console/pkg/compliance/oci/ocitest"
+)
+
+// tmTime stamps hand-written tar entries.
+var tmTime = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// writeTree creates files (path -> content) under a fresh directory.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func build(t *testing.T, src string, opts BuildOptions) ([]byte, *Manifest) {
+	t.Helper()
+	var buf bytes.Buffer
+	m, err := Build(&buf, src, opts)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return buf.Bytes(), m
+}
+
+func openMirror(t *testing.T, key ed25519.PublicKey) *Mirror {
+	t.Helper()
+	m, err := Open(t.TempDir(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+var v1Tree = map[string]string{
+	"fixes/index.json":             `{"missions":[]}`,
+	"fixes/cncf/kubernetes/a.json": `{"a":1}`,
+	"fixes/cncf/kubernetes/b.json": `{"b":1}`,
+	"README.md":                    "kb",
+	".github/workflows/ci.yaml":    "skip me",
+}
+
+func TestImportAndServe(t *testing.T) {
+	src := writeTree(t, v1Tree)
+	bundle, man := build(t, src, BuildOptions{Version: "2026.10.01", Source: "kubestellar/console-kb@abc"})
+	if len(man.Files) != 4 {
+		t.Fatalf("dotfiles should be left out, got %+v", man.Files)
+	}
+
+	m := openMirror(t, nil)
+	if _, err := m.List(""); !errors.Is(err, ErrNotInstalled) {
+		t.Fatalf("List before import = %v", err)
+	}
+	st, changed, err := m.Import(bytes.NewReader(bundle))
+	if err != nil || !changed {
+		t.Fatalf("Import: changed=%v err=%v", changed, err)
+	}
+	if st.Version != "2026.10.01" || st.Files != 4 || st.Signed || st.SignatureVerified {
+		t.Errorf("status = %+v", st)
+	}
+
+	root, err := m.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root) != 2 || root[0].Name != "README.md" || root[1].Name != "fixes" || root[1].Type != "dir" {
+		t.Errorf("root = %+v", root)
+	}
+	dir, _ := m.List("fixes/cncf/kubernetes")
+	if len(dir) != 2 || dir[0].Path != "fixes/cncf/kubernetes/a.json" || dir[0].Size != 7 {
+		t.Errorf("dir = %+v", dir)
+	}
+	if single, _ := m.List("fixes/index.json"); len(single) != 1 || single[0].Type != "file" {
+		t.Errorf("file listing = %+v", single)
+	}
+	if _, err := m.List("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("List(nope) = %v", err)
+	}
+	data, err := m.ReadFile("fixes/cncf/kubernetes/b.json")
+	if err != nil || string(data) != `{"b":1}` {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+
+	// Re-importing the same bundle is a no-op.
+	if _, changed, err := m.Import(bytes.NewReader(bundle)); err != nil || changed {
+		t.Errorf("re-import: changed=%v err=%v", changed, err)
+	}
+
+	// A second process opening the same directory serves the same version.
+	again, err := Open(m.dir, nil)
+	if err != nil || again.Status().Version != "2026.10.01" {
+		t.Errorf("reopen = %+v, %v", again.Status(), err)
+	}
+}
+
+func TestIncrementalImport(t *testing.T) {
+	src := writeTree(t, v1Tree)
+	full, v1 := build(t, src, BuildOptions{Version: "1"})
+
+	if err := os.WriteFile(filepath.Join(src, "fixes/cncf/kubernetes/b.json"), []byte(`{"b":2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(src, "README.md")); err != nil {
+		t.Fatal(err)
+	}
+	delta, v2 := build(t, src, BuildOptions{Version: "2", Base: v1})
+	if v2.Base != "1" {
+		t.Fatalf("base = %q", v2.Base)
+	}
+	if entries := tarNames(t, delta); len(entries) != 2 || entries[1] != "files/fixes/cncf/kubernetes/b.json" {
+		t.Fatalf("delta should carry the manifest and the changed file, got %v", entries)
+	}
+
+	// Without the base installed the delta cannot apply.
+	fresh := openMirror(t, nil)
+	if _, _, err := fresh.Import(bytes.NewReader(delta)); err == nil || !strings.Contains(err.Error(), "incremental bundle on 1") {
+		t.Fatalf("delta on empty mirror: %v", err)
+	}
+	if fresh.Status().Installed {
+		t.Fatal("failed import must not install anything")
+	}
+
+	m := openMirror(t, nil)
+	if _, _, err := m.Import(bytes.NewReader(full)); err != nil {
+		t.Fatal(err)
+	}
+	st, changed, err := m.Import(bytes.NewReader(delta))
+	if err != nil || !changed || st.Version != "2" || st.Files != 3 {
+		t.Fatalf("delta import: %+v changed=%v err=%v", st, changed, err)
+	}
+	if data, _ := m.ReadFile("fixes/cncf/kubernetes/b.json"); string(data) != `{"b":2}` {
+		t.Errorf("changed file = %q", data)
+	}
+	if data, _ := m.ReadFile("fixes/cncf/kubernetes/a.json"); string(data) != `{"a":1}` {
+		t.Errorf("carried file = %q", data)
+	}
+	if _, err := m.ReadFile("README.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("removed file: %v", err)
+	}
+}
+
+func TestImportRejectsTampering(t *testing.T) {
+	src := writeTree(t, v1Tree)
+	bundle, _ := build(t, src, BuildOptions{Version: "1"})
+
+	// Swap a file's content after the manifest was written.
+	var tampered bytes.Buffer
+	rewriteTar(t, bundle, &tampered, func(name string, data []byte) []byte {
+		if name == "files/fixes/index.json" {
+			return []byte(`{"missions":["evil"]}`)
+		}
+		return data
+	})
+	m := openMirror(t, nil)
+	if _, _, err := m.Import(&tampered); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("tampered import: %v", err)
+	}
+
+	var traversal bytes.Buffer
+	gz := gzip.NewWriter(&traversal)
+	tw := tar.NewWriter(gz)
+	writeTarFile(tw, "files/../../etc/passwd", []byte("x"), tmTime)
+	tw.Close()
+	gz.Close()
+	if _, _, err := m.Import(&traversal); err == nil || !strings.Contains(err.Error(), "clean relative path") {
+		t.Fatalf("traversal import: %v", err)
+	}
+	if m.Status().Installed {
+		t.Fatal("rejected bundles must not install")
+	}
+}
+
+func TestSignatureRequired(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	src := writeTree(t, v1Tree)
+	unsigned, _ := build(t, src, BuildOptions{Version: "1"})
+	wrongKey, _ := build(t, src, BuildOptions{Version: "1", Key: otherPriv})
+	signed, _ := build(t, src, BuildOptions{Version: "1", Key: priv})
+
+	m := openMirror(t, pub)
+	if _, _, err := m.Import(bytes.NewReader(unsigned)); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("unsigned: %v", err)
+	}
+	if _, _, err := m.Import(bytes.NewReader(wrongKey)); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("wrong key: %v", err)
+	}
+	st, _, err := m.Import(bytes.NewReader(signed))
+	if err != nil || !st.Signed || !st.SignatureVerified || st.KeyID != KeyID(pub) {
+		t.Fatalf("signed: %+v %v", st, err)
+	}
+
+	// A mirror trusting another key refuses to serve the installed bundle.
+	otherPub := otherPriv.Public().(ed25519.PublicKey)
+	if reopened, _ := Open(m.dir, otherPub); reopened.Status().Installed {
+		t.Error("bundle signed by an untrusted key was served")
+	}
+}
+
+func TestSyncerOCI(t *testing.T) {
+	src := writeTree(t, v1Tree)
+	full, v1 := build(t, src, BuildOptions{Version: "1"})
+	if err := os.WriteFile(filepath.Join(src, "fixes/index.json"), []byte(`{"missions":[1]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	full2, _ := build(t, src, BuildOptions{Version: "2"})
+	delta2, _ := build(t, src, BuildOptions{Version: "2", Base: v1})
+
+	reg := ocitest.NewRegistry()
+	defer reg.Close()
+	config := reg.PushBlob("application/vnd.oci.empty.v1+json", []byte("{}"))
+	reg.PushManifest("console/kb", "latest", oci.Manifest{ArtifactType: ArtifactType, Config: config,
+		Layers: []oci.Descriptor{reg.PushBlob(MediaType, full)}})
+
+	m := openMirror(t, nil)
+	s, err := NewSyncer(m, SyncOptions{OCIRef: reg.Host() + "/console/kb:latest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if st, err := s.Sync(ctx); err != nil || st.Version != "1" {
+		t.Fatalf("first sync: %+v %v", st, err)
+	}
+	before := reg.Requests()
+	if _, err := s.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := reg.Requests() - before; got != 1 {
+		t.Errorf("unchanged artifact made %d requests, want just the manifest", got)
+	}
+
+	fullLayer := reg.PushBlob(MediaType, full2)
+	deltaLayer := reg.PushBlob(MediaType, delta2)
+	deltaLayer.Annotations = map[string]string{AnnotationBase: "1"}
+	reg.PushManifest("console/kb", "latest", oci.Manifest{ArtifactType: ArtifactType, Config: config,
+		Layers: []oci.Descriptor{fullLayer, deltaLayer}})
+	before = reg.Requests()
+	if st, err := s.Sync(ctx); err != nil || st.Version != "2" {
+		t.Fatalf("second sync: %+v %v", st, err)
+	}
+	if got := reg.Requests() - before; got != 2 {
+		t.Errorf("delta sync made %d requests, want manifest and one blob", got)
+	}
+	if data, _ := m.ReadFile("fixes/index.json"); string(data) != `{"missions":[1]}` {
+		t.Errorf("index = %q", data)
+	}
+	if state := s.State(); state.LastError != "" || state.LastSync.IsZero() {
+		t.Errorf("state = %+v", state)
+	}
+}
+
+func TestSyncerBundleFile(t *testing.T) {
+	src := writeTree(t, v1Tree)
+	bundle, _ := build(t, src, BuildOptions{Version: "1"})
+	path := filepath.Join(t.TempDir(), "console-kb.tar.gz")
+	if err := os.WriteFile(path, bundle, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := openMirror(t, nil)
+	s, err := NewSyncer(m, SyncOptions{BundlePath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st, err := s.Sync(context.Background()); err != nil || st.Version != "1" {
+		t.Fatalf("sync: %+v %v", st, err)
+	}
+	if err := os.WriteFile(path, []byte("not a bundle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Sync(context.Background()); err == nil {
+		t.Fatal("expected a broken bundle to fail")
+	}
+	if m.Status().Version != "1" || s.State().LastError == "" {
+		t.Errorf("broken bundle must keep serving v1 and report the error: %+v %+v", m.Status(), s.State())
+	}
+}
+
+func tarNames(t *testing.T, bundle []byte) []string {
+	t.Helper()
+	var names []string
+	rewriteTar(t, bundle, &bytes.Buffer{}, func(name string, data []byte) []byte {
+		names = append(names, name)
+		return data
+	})
+	return names
+}
+
+// rewriteTar copies a bundle, passing each entry through fn.
+func rewriteTar(t *testing.T, bundle []byte, w *bytes.Buffer, fn func(name string, data []byte) []byte) {
+	t.Helper()
+	gr, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, _ := readLimited(tr, maxFileBytes)
+		if err := writeTarFile(tw, hdr.Name, fn(hdr.Name, data), tmTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+}
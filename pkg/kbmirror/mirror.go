@@ -0,0 +1,501 @@
+package kbmirror
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/fileutil"
+)
+
+const (
+	currentName = "current"
+	versionsDir = "versions"
+	treeDir     = "files"
+	// mirrorDirMode and mirrorFileMode keep the mirror readable by the
+	// console only.
+	mirrorDirMode  = 0o750
+	mirrorFileMode = 0o640
+)
+
+var (
+	// ErrNotInstalled is returned while no bundle has been imported.
+	ErrNotInstalled = errors.New("no console-kb bundle installed")
+	// ErrNotFound is returned for paths the installed bundle does not have.
+	ErrNotFound = errors.New("not found in console-kb bundle")
+)
+
+// Entry is one item of a directory listing. Type is "file" or "dir", the
+// same values the GitHub contents API uses.
+type Entry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+}
+
+// Status describes the installed bundle.
+type Status struct {
+	Installed  bool      `json:"installed"`
+	Version    string    `json:"version,omitempty"`
+	Base       string    `json:"base,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	ImportedAt time.Time `json:"imported_at,omitempty"`
+	Files      int       `json:"files"`
+	Bytes      int64     `json:"bytes"`
+	// Digest is the SHA-256 of manifest.json, which pins every file.
+	Digest string `json:"digest,omitempty"`
+	Signed bool   `json:"signed"`
+	// SignatureVerified is true when the bundle's signature matched the
+	// trusted key; KeyID names that key.
+	SignatureVerified bool   `json:"signature_verified"`
+	KeyID             string `json:"key_id,omitempty"`
+}
+
+// tree is an installed bundle loaded for serving.
+type tree struct {
+	status Status
+	root   string
+	files  map[string]File
+	dirs   map[string][]Entry
+}
+
+// Mirror serves the installed console-kb bundle from disk and imports new
+// ones. It is safe for concurrent use.
+type Mirror struct {
+	dir string
+	key ed25519.PublicKey
+
+	importMu sync.Mutex // serializes Import and Reload
+	mu       sync.RWMutex
+	tree     *tree
+}
+
+// Open returns the mirror kept in dir, creating the directory if needed.
+// When key is set every bundle must carry a signature made with it,
+// including the one already installed. An installed bundle that fails to
+// load is logged and the mirror starts empty.
+func Open(dir string, key ed25519.PublicKey) (*Mirror, error) {
+	if err := os.MkdirAll(filepath.Join(dir, versionsDir), mirrorDirMode); err != nil {
+		return nil, err
+	}
+	m := &Mirror{dir: dir, key: key}
+	if err := m.Reload(); err != nil {
+		slog.Error("[KBMirror] installed bundle ignored", "dir", dir, "error", err)
+	}
+	return m, nil
+}
+
+// Reload switches to the version named in the current file if it changed,
+// picking up bundles imported by another process such as
+// "console kb import".
+func (m *Mirror) Reload() error {
+	m.importMu.Lock()
+	defer m.importMu.Unlock()
+	data, err := os.ReadFile(filepath.Join(m.dir, currentName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	version := strings.TrimSpace(string(data))
+	if cur := m.current(); cur != nil && cur.status.Version == version {
+		return nil
+	}
+	t, err := m.loadVersion(version)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.tree = t
+	m.mu.Unlock()
+	slog.Info("[KBMirror] serving console-kb bundle", "version", version, "files", t.status.Files)
+	return nil
+}
+
+func (m *Mirror) current() *tree {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tree
+}
+
+// Status describes the installed bundle.
+func (m *Mirror) Status() Status {
+	if t := m.current(); t != nil {
+		return t.status
+	}
+	return Status{}
+}
+
+// List returns the entries of dir ("" for the root), sorted by name. A
+// path naming a file returns that file alone, as the GitHub contents API
+// does.
+func (m *Mirror) List(dir string) ([]Entry, error) {
+	t := m.current()
+	if t == nil {
+		return nil, ErrNotInstalled
+	}
+	dir = strings.Trim(dir, "/")
+	if entries, ok := t.dirs[dir]; ok {
+		return append([]Entry(nil), entries...), nil
+	}
+	if f, ok := t.files[dir]; ok {
+		return []Entry{{Name: path.Base(f.Path), Path: f.Path, Type: "file", Size: f.Size}}, nil
+	}
+	return nil, ErrNotFound
+}
+
+// ReadFile returns the contents of the file at p.
+func (m *Mirror) ReadFile(p string) ([]byte, error) {
+	t := m.current()
+	if t == nil {
+		return nil, ErrNotInstalled
+	}
+	if _, ok := t.files[p]; !ok {
+		return nil, ErrNotFound
+	}
+	return os.ReadFile(filepath.Join(t.root, filepath.FromSlash(p)))
+}
+
+// Import verifies the bundle read from r and makes it the served version.
+// It reports false when the bundle is the version already installed.
+// Nothing changes unless every file matches its manifest checksum and, with
+// a trusted key, the manifest signature verifies.
+func (m *Mirror) Import(r io.Reader) (Status, bool, error) {
+	m.importMu.Lock()
+	defer m.importMu.Unlock()
+
+	versions := filepath.Join(m.dir, versionsDir)
+	staging, err := os.MkdirTemp(versions, ".import-")
+	if err != nil {
+		return Status{}, false, err
+	}
+	defer os.RemoveAll(staging)
+
+	b, err := unpack(r, filepath.Join(staging, treeDir))
+	if err != nil {
+		return Status{}, false, err
+	}
+	man, err := m.checkManifest(b.manifest, b.signature)
+	if err != nil {
+		return Status{}, false, err
+	}
+	cur := m.current()
+	if cur != nil && cur.status.Version == man.Version {
+		if cur.status.Digest == digestOf(b.manifest) {
+			return cur.status, false, nil
+		}
+		return Status{}, false, fmt.Errorf("version %s is already installed with different contents", man.Version)
+	}
+
+	for _, f := range man.Files {
+		if got, ok := b.files[f.Path]; ok {
+			if got.SHA256 != f.SHA256 || got.Size != f.Size {
+				return Status{}, false, fmt.Errorf("checksum mismatch for %s", f.Path)
+			}
+			delete(b.files, f.Path)
+			continue
+		}
+		// Not packed: an incremental bundle reuses the installed copy.
+		if cur != nil {
+			if have, ok := cur.files[f.Path]; ok && have.SHA256 == f.SHA256 {
+				src := filepath.Join(cur.root, filepath.FromSlash(f.Path))
+				dst := filepath.Join(staging, treeDir, filepath.FromSlash(f.Path))
+				if err := copyVerified(src, dst, f.SHA256); err != nil {
+					return Status{}, false, fmt.Errorf("reuse %s: %w", f.Path, err)
+				}
+				continue
+			}
+		}
+		if man.Base != "" {
+			installed := "none"
+			if cur != nil {
+				installed = cur.status.Version
+			}
+			return Status{}, false, fmt.Errorf("incremental bundle on %s cannot be applied to installed version %s: %s is missing", man.Base, installed, f.Path)
+		}
+		return Status{}, false, fmt.Errorf("bundle is missing %s", f.Path)
+	}
+	for p := range b.files {
+		return Status{}, false, fmt.Errorf("bundle carries %s, which its manifest does not list", p)
+	}
+
+	if err := os.WriteFile(filepath.Join(staging, manifestName), b.manifest, mirrorFileMode); err != nil {
+		return Status{}, false, err
+	}
+	if b.signature != nil {
+		if err := os.WriteFile(filepath.Join(staging, signatureName), b.signature, mirrorFileMode); err != nil {
+			return Status{}, false, err
+		}
+	}
+	target := filepath.Join(versions, man.Version)
+	// A leftover copy of this version (rolled back, or a failed switch)
+	// is replaced by the freshly verified one.
+	if err := os.RemoveAll(target); err != nil {
+		return Status{}, false, err
+	}
+	if err := os.Rename(staging, target); err != nil {
+		return Status{}, false, err
+	}
+	t, err := m.loadVersion(man.Version)
+	if err != nil {
+		return Status{}, false, err
+	}
+	if err := fileutil.AtomicWriteFile(filepath.Join(m.dir, currentName), []byte(man.Version+"\n"), mirrorFileMode); err != nil {
+		return Status{}, false, err
+	}
+	m.mu.Lock()
+	m.tree = t
+	m.mu.Unlock()
+
+	// Keep the previous version for rollback and drop the rest.
+	keep := map[string]bool{man.Version: true}
+	if cur != nil {
+		keep[cur.status.Version] = true
+	}
+	m.prune(keep)
+	slog.Info("[KBMirror] imported console-kb bundle", "version", man.Version, "base", man.Base, "files", t.status.Files, "verified", t.status.SignatureVerified)
+	return t.status, true, nil
+}
+
+// checkManifest decodes and validates a manifest and, with a trusted key,
+// its signature.
+func (m *Mirror) checkManifest(raw, sig []byte) (*Manifest, error) {
+	if raw == nil {
+		return nil, errors.New("bundle has no manifest.json")
+	}
+	var man Manifest
+	if err := json.Unmarshal(raw, &man); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if err := man.Validate(); err != nil {
+		return nil, err
+	}
+	if m.key != nil {
+		if sig == nil {
+			return nil, errors.New("bundle is not signed and a signature is required")
+		}
+		if err := verifySignature(m.key, raw, sig); err != nil {
+			return nil, err
+		}
+	}
+	return &man, nil
+}
+
+// loadVersion reads an installed version and indexes it for serving.
+func (m *Mirror) loadVersion(version string) (*tree, error) {
+	if err := validateVersion(version); err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(m.dir, versionsDir, version)
+	raw, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(filepath.Join(dir, signatureName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	man, err := m.checkManifest(raw, sig)
+	if err != nil {
+		return nil, err
+	}
+	if man.Version != version {
+		return nil, fmt.Errorf("directory %s holds version %s", version, man.Version)
+	}
+	info, err := os.Stat(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tree{
+		status: Status{
+			Installed:         true,
+			Version:           man.Version,
+			Base:              man.Base,
+			Source:            man.Source,
+			CreatedAt:         man.CreatedAt,
+			ImportedAt:        info.ModTime().UTC(),
+			Files:             len(man.Files),
+			Digest:            digestOf(raw),
+			Signed:            sig != nil,
+			SignatureVerified: m.key != nil,
+		},
+		root:  filepath.Join(dir, treeDir),
+		files: make(map[string]File, len(man.Files)),
+		dirs:  map[string][]Entry{"": {}},
+	}
+	if m.key != nil {
+		t.status.KeyID = KeyID(m.key)
+	}
+	for _, f := range man.Files {
+		t.files[f.Path] = f
+		t.status.Bytes += f.Size
+		t.addEntry(Entry{Name: path.Base(f.Path), Path: f.Path, Type: "file", Size: f.Size})
+	}
+	for dir := range t.dirs {
+		entries := t.dirs[dir]
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	}
+	return t, nil
+}
+
+// addEntry lists e in its parent directory, creating the parent chain.
+func (t *tree) addEntry(e Entry) {
+	parent := path.Dir(e.Path)
+	if parent == "." {
+		parent = ""
+	}
+	entries, known := t.dirs[parent]
+	t.dirs[parent] = append(entries, e)
+	if !known {
+		t.addEntry(Entry{Name: path.Base(parent), Path: parent, Type: "dir"})
+	}
+}
+
+func (m *Mirror) prune(keep map[string]bool) {
+	versions := filepath.Join(m.dir, versionsDir)
+	entries, err := os.ReadDir(versions)
+	if err != nil {
+		slog.Warn("[KBMirror] failed to list old versions", "error", err)
+		return
+	}
+	for _, e := range entries {
+		if keep[e.Name()] || strings.HasPrefix(e.Name(), ".import-") {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(versions, e.Name())); err != nil {
+			slog.Warn("[KBMirror] failed to remove old version", "version", e.Name(), "error", err)
+		}
+	}
+}
+
+// unpacked is what a bundle carried.
+type unpacked struct {
+	manifest  []byte
+	signature []byte
+	files     map[string]File // checksums of the packed files as written
+}
+
+// unpack extracts a bundle's files under dst, hashing them on the way.
+func unpack(r io.Reader, dst string) (*unpacked, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	b := &unpacked{files: map[string]File{}}
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return b, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bundle: %w", err)
+		}
+		name := strings.TrimPrefix(hdr.Name, "./")
+		switch {
+		case hdr.Typeflag == tar.TypeDir:
+			continue
+		case name == manifestName:
+			if b.manifest, err = readLimited(tr, maxManifestBytes); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		case name == signatureName:
+			if b.signature, err = readLimited(tr, maxSignatureBytes); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		case strings.HasPrefix(name, filesPrefix):
+			p := strings.TrimPrefix(name, filesPrefix)
+			if err := validatePath(p); err != nil {
+				return nil, fmt.Errorf("bundle entry %q: %w", name, err)
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				return nil, fmt.Errorf("bundle entry %q is not a regular file", name)
+			}
+			if _, dup := b.files[p]; dup {
+				return nil, fmt.Errorf("bundle entry %q appears twice", name)
+			}
+			if len(b.files) >= maxBundleFiles {
+				return nil, fmt.Errorf("bundle has more than %d files", maxBundleFiles)
+			}
+			f, err := writeHashed(filepath.Join(dst, filepath.FromSlash(p)), tr)
+			if err != nil {
+				return nil, fmt.Errorf("bundle entry %q: %w", name, err)
+			}
+			if total += f.Size; total > maxTreeBytes {
+				return nil, fmt.Errorf("bundle unpacks to more than %d bytes", maxTreeBytes)
+			}
+			f.Path = p
+			b.files[p] = f
+		default:
+			return nil, fmt.Errorf("unexpected bundle entry %q", hdr.Name)
+		}
+	}
+}
+
+// writeHashed writes at most maxFileBytes from r to p and returns the
+// checksum and size written.
+func writeHashed(p string, r io.Reader) (File, error) {
+	if err := os.MkdirAll(filepath.Dir(p), mirrorDirMode); err != nil {
+		return File{}, err
+	}
+	out, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mirrorFileMode)
+	if err != nil {
+		return File{}, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), io.LimitReader(r, maxFileBytes+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return File{}, err
+	}
+	if n > maxFileBytes {
+		return File{}, fmt.Errorf("file exceeds %d bytes", maxFileBytes)
+	}
+	return File{SHA256: hex.EncodeToString(h.Sum(nil)), Size: n}, nil
+}
+
+// copyVerified copies src to dst and fails unless the copy has the
+// expected checksum, so an installed file damaged on disk is never carried
+// forward.
+func copyVerified(src, dst, sum string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	f, err := writeHashed(dst, in)
+	if err != nil {
+		return err
+	}
+	if f.SHA256 != sum {
+		return errors.New("installed copy does not match its checksum")
+	}
+	return nil
+}
+
+func digestOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,215 @@
+package kbmirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/compliance/oci"
+)
+
+// DefaultSyncInterval is how often the bundle sources are checked for a
+// new version when SyncOptions.Interval is zero.
+const DefaultSyncInterval = time.Hour
+
+// SyncOptions names where new bundles come from. Both sources are
+// optional; with neither, Sync only reloads bundles imported by another
+// process.
+type SyncOptions struct {
+	// BundlePath is a bundle file, re-imported whenever its content changes.
+	BundlePath string
+	// OCIRef is an OCI artifact holding the bundle as a MediaType layer,
+	// e.g. registry.internal/console/console-kb:latest.
+	OCIRef string
+	// Registry pulls OCIRef; defaults to oci.NewClient().
+	Registry *oci.Client
+	Interval time.Duration
+}
+
+// SyncState reports the last sync.
+type SyncState struct {
+	BundlePath string    `json:"bundle_path,omitempty"`
+	OCIRef     string    `json:"oci_ref,omitempty"`
+	Interval   string    `json:"interval"`
+	LastSync   time.Time `json:"last_sync,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Syncer keeps a mirror up to date from its bundle sources.
+type Syncer struct {
+	mirror   *Mirror
+	opts     SyncOptions
+	ref      *oci.Reference
+	registry *oci.Client
+
+	mu       sync.Mutex // serializes Sync and guards the fields below
+	lastSync time.Time
+	lastErr  string
+	// seen holds the digest last imported from each source, so unchanged
+	// sources are not unpacked again.
+	seen map[string]string
+}
+
+// NewSyncer returns a syncer for m.
+func NewSyncer(m *Mirror, opts SyncOptions) (*Syncer, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultSyncInterval
+	}
+	s := &Syncer{mirror: m, opts: opts, registry: opts.Registry, seen: map[string]string{}}
+	if opts.OCIRef != "" {
+		ref, err := oci.ParseReference(opts.OCIRef)
+		if err != nil {
+			return nil, fmt.Errorf("OCI reference: %w", err)
+		}
+		s.ref = &ref
+		if s.registry == nil {
+			s.registry = oci.NewClient()
+		}
+	}
+	return s, nil
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("[KBMirror] sync failed, will retry", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync reloads the mirror and imports any new bundle from its sources,
+// returning the resulting status.
+func (s *Syncer) Sync(ctx context.Context) (Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	if err := s.mirror.Reload(); err != nil {
+		errs = append(errs, fmt.Errorf("reload: %w", err))
+	}
+	if s.opts.BundlePath != "" {
+		if err := s.syncFile(); err != nil {
+			errs = append(errs, fmt.Errorf("bundle %s: %w", s.opts.BundlePath, err))
+		}
+	}
+	if s.ref != nil {
+		if err := s.syncOCI(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("OCI %s: %w", s.ref, err))
+		}
+	}
+	err := errors.Join(errs...)
+	s.lastSync = time.Now().UTC()
+	s.lastErr = ""
+	if err != nil {
+		s.lastErr = err.Error()
+	}
+	return s.mirror.Status(), err
+}
+
+// State reports the configured sources and the outcome of the last sync.
+func (s *Syncer) State() SyncState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SyncState{
+		BundlePath: s.opts.BundlePath,
+		OCIRef:     s.opts.OCIRef,
+		Interval:   s.opts.Interval.String(),
+		LastSync:   s.lastSync,
+		LastError:  s.lastErr,
+	}
+}
+
+func (s *Syncer) syncFile() error {
+	f, err := os.Open(s.opts.BundlePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	digest := "sha256:" + hex.EncodeToString(h.Sum(nil))
+	if s.seen[s.opts.BundlePath] == digest {
+		return nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, _, err := s.mirror.Import(f); err != nil {
+		return err
+	}
+	s.seen[s.opts.BundlePath] = digest
+	return nil
+}
+
+// syncOCI pulls the bundle layer of the artifact at s.ref. An artifact
+// may carry a full bundle and incremental ones annotated with their base
+// version; the incremental layer for the installed version is preferred.
+func (s *Syncer) syncOCI(ctx context.Context) error {
+	reference := s.ref.Digest
+	if reference == "" {
+		reference = s.ref.Tag
+	}
+	manifest, digest, err := s.registry.Manifest(ctx, s.ref.Registry, s.ref.Repository, reference)
+	if err != nil {
+		return err
+	}
+	if s.seen[s.ref.String()] == digest {
+		return nil
+	}
+	installed := s.mirror.Status().Version
+	var full, delta *oci.Descriptor
+	for i := range manifest.Layers {
+		layer := &manifest.Layers[i]
+		if layer.MediaType != MediaType {
+			continue
+		}
+		base := layer.Annotations[AnnotationBase]
+		switch {
+		case base == "" && full == nil:
+			full = layer
+		case base != "" && base == installed:
+			delta = layer
+		}
+	}
+	if full == nil {
+		return fmt.Errorf("artifact has no full bundle layer of type %s", MediaType)
+	}
+	// Fall back to the full bundle if the incremental one does not apply.
+	for _, layer := range []*oci.Descriptor{delta, full} {
+		if layer == nil {
+			continue
+		}
+		if err = s.importBlob(ctx, layer.Digest); err == nil {
+			s.seen[s.ref.String()] = digest
+			return nil
+		}
+		slog.Warn("[KBMirror] bundle layer rejected", "ref", s.ref.String(), "layer", layer.Digest, "error", err)
+	}
+	return err
+}
+
+func (s *Syncer) importBlob(ctx context.Context, digest string) error {
+	blob, err := s.registry.Blob(ctx, s.ref.Registry, s.ref.Repository, digest)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.mirror.Import(bytes.NewReader(blob))
+	return err
+}
@@ -51,6 +51,9 @@ const (
 	NotificationTypeUnableToFix      NotificationType = "unable_to_fix"
 	NotificationTypeClosed           NotificationType = "closed"
 	NotificationTypeFeedbackReceived NotificationType = "feedback_received"
+	// NotificationTypeGPUReservation covers GPU reservation lifecycle
+	// changes: waitlist promotion, start, and expiry.
+	NotificationTypeGPUReservation NotificationType = "gpu_reservation"
 )
 
 // FeatureRequest represents a bug or feature request submitted by a user
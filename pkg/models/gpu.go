@@ -14,25 +14,35 @@ const (
 	ReservationStatusActive    ReservationStatus = "active"
 	ReservationStatusCompleted ReservationStatus = "completed"
 	ReservationStatusCancelled ReservationStatus = "cancelled"
+	// ReservationStatusScheduled holds capacity for a window that has not
+	// started yet; the lifecycle worker provisions it at the start time.
+	ReservationStatusScheduled ReservationStatus = "scheduled"
+	// ReservationStatusWaitlisted did not fit the cluster's capacity for its
+	// window and holds none; it is promoted to scheduled when capacity frees.
+	ReservationStatusWaitlisted ReservationStatus = "waitlisted"
 )
 
 // validStatuses enumerates the allowed reservation status values.
 var validStatuses = map[ReservationStatus]bool{
-	ReservationStatusActive:    true,
-	ReservationStatusCompleted: true,
-	ReservationStatusCancelled: true,
+	ReservationStatusActive:     true,
+	ReservationStatusCompleted:  true,
+	ReservationStatusCancelled:  true,
+	ReservationStatusScheduled:  true,
+	ReservationStatusWaitlisted: true,
 }
 
 // allowedTransitions defines the legal state-transition graph for reservation
 // status. The key is the current status; the value set contains the statuses
 // it may transition to.
 var allowedTransitions = map[ReservationStatus]map[ReservationStatus]bool{
-	ReservationStatusActive:    {ReservationStatusCompleted: true, ReservationStatusCancelled: true},
-	ReservationStatusCompleted: {}, // terminal
-	ReservationStatusCancelled: {}, // terminal
+	ReservationStatusWaitlisted: {ReservationStatusScheduled: true, ReservationStatusActive: true, ReservationStatusCancelled: true},
+	ReservationStatusScheduled:  {ReservationStatusActive: true, ReservationStatusCompleted: true, ReservationStatusCancelled: true},
+	ReservationStatusActive:     {ReservationStatusCompleted: true, ReservationStatusCancelled: true},
+	ReservationStatusCompleted:  {}, // terminal
+	ReservationStatusCancelled:  {}, // terminal
 }
 
-// IsValidStatus returns true when s is one of the four recognised statuses.
+// IsValidStatus returns true when s is one of the recognised statuses.
 func (s ReservationStatus) IsValid() bool {
 	return validStatuses[s]
 }
@@ -51,6 +61,17 @@ func (s ReservationStatus) CanTransitionTo(target ReservationStatus) bool {
 	return allowed[target]
 }
 
+// HoldsCapacity reports whether a reservation in this status counts against
+// its cluster's GPU capacity for its window.
+func (s ReservationStatus) HoldsCapacity() bool {
+	return s == ReservationStatusActive || s == ReservationStatusScheduled
+}
+
+// IsTerminal reports whether no further lifecycle transitions are possible.
+func (s ReservationStatus) IsTerminal() bool {
+	return s == ReservationStatusCompleted || s == ReservationStatusCancelled
+}
+
 // GPUReservation represents a GPU reservation submitted by a user.
 //
 // GPUType (singular) is the legacy single-type field kept for
@@ -86,6 +107,12 @@ type GPUReservation struct {
 	QuotaEnforced bool              `json:"quota_enforced"`
 	CreatedAt     time.Time         `json:"created_at"`
 	UpdatedAt     *time.Time        `json:"updated_at,omitempty"`
+	// ActivatedAt is when the reservation first became active, set by the
+	// store. It stays nil for reservations that never held GPUs.
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+	// NotifiedStage is the last lifecycle notification sent to the owner,
+	// so the lifecycle worker sends each one once.
+	NotifiedStage ReservationNotifyStage `json:"-"`
 }
 
 // NormalizeGPUTypes reconciles the legacy single-type field (GPUType) with
@@ -169,6 +196,9 @@ type CreateGPUReservationInput struct {
 	QuotaName      string   `json:"quota_name"`
 	QuotaEnforced  bool     `json:"quota_enforced"`
 	MaxClusterGPUs int      `json:"max_cluster_gpus"`
+	// Waitlist queues the reservation instead of rejecting it when the
+	// cluster has no capacity left for its window.
+	Waitlist bool `json:"waitlist"`
 }
 
 // GPUUtilizationSnapshot records a point-in-time GPU usage measurement for a reservation
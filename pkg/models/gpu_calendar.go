@@ -0,0 +1,147 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReservationNotifyStage is a lifecycle notification already sent to a
+// reservation's owner. Stages only move forward, in declaration order.
+type ReservationNotifyStage string
+
+const (
+	ReservationNotifyNone       ReservationNotifyStage = ""
+	ReservationNotifyStartsSoon ReservationNotifyStage = "starts_soon"
+	ReservationNotifyStarted    ReservationNotifyStage = "started"
+	ReservationNotifyEndsSoon   ReservationNotifyStage = "ends_soon"
+	ReservationNotifyEnded      ReservationNotifyStage = "ended"
+)
+
+// reservationNotifyOrder ranks the stages so a later one is never followed
+// by an earlier one.
+var reservationNotifyOrder = map[ReservationNotifyStage]int{
+	ReservationNotifyNone:       0,
+	ReservationNotifyStartsSoon: 1,
+	ReservationNotifyStarted:    2,
+	ReservationNotifyEndsSoon:   3,
+	ReservationNotifyEnded:      4,
+}
+
+// Reached reports whether stage s is at or past target.
+func (s ReservationNotifyStage) Reached(target ReservationNotifyStage) bool {
+	return reservationNotifyOrder[s] >= reservationNotifyOrder[target]
+}
+
+// legacyStartDateLayout is the date-only form some pre-RFC 3339 rows use.
+const legacyStartDateLayout = "2006-01-02"
+
+// Window returns the reservation's [start, end) time window. ok is false
+// when StartDate cannot be parsed or DurationHours is not positive; such
+// undated reservations are treated as overlapping every window so they are
+// never silently ignored by capacity checks.
+func (r *GPUReservation) Window() (start, end time.Time, ok bool) {
+	if r.DurationHours <= 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err := time.Parse(time.RFC3339, r.StartDate)
+	if err != nil {
+		if start, err = time.Parse(legacyStartDateLayout, r.StartDate); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return start, start.Add(time.Duration(r.DurationHours) * time.Hour), true
+}
+
+// Overlaps reports whether the reservation's window intersects [start, end).
+// Undated reservations overlap every window.
+func (r *GPUReservation) Overlaps(start, end time.Time) bool {
+	rs, re, ok := r.Window()
+	if !ok {
+		return true
+	}
+	return rs.Before(end) && start.Before(re)
+}
+
+// GPUCapacitySegment is a stretch of time during which the set of
+// capacity-holding reservations on a cluster does not change.
+type GPUCapacitySegment struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Reserved int       `json:"reserved"`
+	// Available is Capacity minus Reserved, or -1 when capacity is unknown.
+	Available int `json:"available"`
+}
+
+// GPUCapacitySegments splits [start, end) at every reservation boundary and
+// reports the GPUs reserved in each piece. Only reservations whose status
+// holds capacity count; excludeID is skipped so an update is not checked
+// against itself. A capacity of 0 or less means unknown.
+func GPUCapacitySegments(reservations []GPUReservation, start, end time.Time, excludeID uuid.UUID, capacity int) []GPUCapacitySegment {
+	if !start.Before(end) {
+		return nil
+	}
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	var undated int
+	var edges []edge
+	for i := range reservations {
+		r := &reservations[i]
+		if r.ID == excludeID || !r.Status.HoldsCapacity() || !r.Overlaps(start, end) {
+			continue
+		}
+		rs, re, ok := r.Window()
+		if !ok {
+			undated += r.GPUCount
+			continue
+		}
+		if rs.Before(start) {
+			rs = start
+		}
+		if re.After(end) {
+			re = end
+		}
+		edges = append(edges, edge{rs, r.GPUCount}, edge{re, -r.GPUCount})
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].at.Before(edges[j].at) })
+
+	segments := make([]GPUCapacitySegment, 0, len(edges)+1)
+	reserved := undated
+	cursor := start
+	emit := func(until time.Time) {
+		if !cursor.Before(until) {
+			return
+		}
+		available := -1
+		if capacity > 0 {
+			available = capacity - reserved
+		}
+		if n := len(segments); n > 0 && segments[n-1].Reserved == reserved {
+			segments[n-1].End = until
+		} else {
+			segments = append(segments, GPUCapacitySegment{Start: cursor, End: until, Reserved: reserved, Available: available})
+		}
+		cursor = until
+	}
+	for _, e := range edges {
+		emit(e.at)
+		reserved += e.delta
+	}
+	emit(end)
+	return segments
+}
+
+// PeakReservedGPUs returns the most GPUs reserved at any instant of
+// [start, end) by the capacity-holding reservations other than excludeID.
+func PeakReservedGPUs(reservations []GPUReservation, start, end time.Time, excludeID uuid.UUID) int {
+	peak := 0
+	for _, seg := range GPUCapacitySegments(reservations, start, end, excludeID, 0) {
+		if seg.Reserved > peak {
+			peak = seg.Reserved
+		}
+	}
+	return peak
+}
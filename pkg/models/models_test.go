@@ -3,7 +3,9 @@ package models
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -333,3 +335,40 @@ func TestFeatureRequest_JSONSerialization(t *testing.T) {
 	require.NotNil(t, decoded.GitHubIssueNumber)
 	require.Equal(t, 42, *decoded.GitHubIssueNumber)
 }
+
+func TestGPUCapacitySegments(t *testing.T) {
+	day := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	at := func(h int) string { return day.Add(time.Duration(h) * time.Hour).Format(time.RFC3339) }
+	excluded := uuid.New()
+	reservations := []GPUReservation{
+		{ID: uuid.New(), GPUCount: 4, StartDate: at(0), DurationHours: 8, Status: ReservationStatusActive},
+		{ID: uuid.New(), GPUCount: 2, StartDate: at(4), DurationHours: 8, Status: ReservationStatusScheduled},
+		{ID: uuid.New(), GPUCount: 8, StartDate: at(0), DurationHours: 24, Status: ReservationStatusWaitlisted},
+		{ID: uuid.New(), GPUCount: 8, StartDate: at(0), DurationHours: 24, Status: ReservationStatusCancelled},
+		{ID: excluded, GPUCount: 8, StartDate: at(0), DurationHours: 24, Status: ReservationStatusActive},
+		// Undated rows count for the whole window.
+		{ID: uuid.New(), GPUCount: 1, Status: ReservationStatusActive},
+	}
+
+	segments := GPUCapacitySegments(reservations, day, day.Add(24*time.Hour), excluded, 8)
+	require.Equal(t, []GPUCapacitySegment{
+		{Start: day, End: day.Add(4 * time.Hour), Reserved: 5, Available: 3},
+		{Start: day.Add(4 * time.Hour), End: day.Add(8 * time.Hour), Reserved: 7, Available: 1},
+		{Start: day.Add(8 * time.Hour), End: day.Add(12 * time.Hour), Reserved: 3, Available: 5},
+		{Start: day.Add(12 * time.Hour), End: day.Add(24 * time.Hour), Reserved: 1, Available: 7},
+	}, segments)
+
+	require.Equal(t, 7, PeakReservedGPUs(reservations, day, day.Add(24*time.Hour), excluded))
+	require.Equal(t, 3, PeakReservedGPUs(reservations, day.Add(8*time.Hour), day.Add(12*time.Hour), excluded))
+	require.Equal(t, 13, PeakReservedGPUs(reservations, day, day.Add(time.Hour), uuid.Nil))
+}
+
+func TestReservationStatus_Transitions(t *testing.T) {
+	require.True(t, ReservationStatusWaitlisted.CanTransitionTo(ReservationStatusScheduled))
+	require.True(t, ReservationStatusScheduled.CanTransitionTo(ReservationStatusActive))
+	require.False(t, ReservationStatusActive.CanTransitionTo(ReservationStatusScheduled))
+	require.False(t, ReservationStatusWaitlisted.HoldsCapacity())
+	require.True(t, ReservationStatusScheduled.HoldsCapacity())
+	require.True(t, ReservationNotifyEndsSoon.Reached(ReservationNotifyStarted))
+	require.False(t, ReservationNotifyStartsSoon.Reached(ReservationNotifyStarted))
+}
@@ -0,0 +1,170 @@
+package notifications
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AlertmanagerVersion is the webhook payload version Alertmanager has sent
+// since 0.15.
+const AlertmanagerVersion = "4"
+
+// AlertmanagerPayload is the body Alertmanager POSTs to a webhook receiver.
+type AlertmanagerPayload struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert of an Alertmanager webhook payload.
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// clusterLabels are the alert labels that name the originating cluster, in
+// order of preference.
+var clusterLabels = []string{"cluster", "k8s_cluster", "kubernetes_cluster", "cluster_name"}
+
+// resourceLabels map kube-state-metrics style labels to a resource kind, in
+// order of preference.
+var resourceLabels = []struct{ label, kind string }{
+	{"pod", "Pod"},
+	{"deployment", "Deployment"},
+	{"statefulset", "StatefulSet"},
+	{"daemonset", "DaemonSet"},
+	{"job_name", "Job"},
+	{"persistentvolumeclaim", "PersistentVolumeClaim"},
+	{"node", "Node"},
+	{"service", "Service"},
+}
+
+// Validate reports whether p is a payload this receiver understands.
+func (p *AlertmanagerPayload) Validate() error {
+	if p.Version != AlertmanagerVersion {
+		return fmt.Errorf("unsupported Alertmanager payload version %q, want %q", p.Version, AlertmanagerVersion)
+	}
+	return nil
+}
+
+// ToAlerts maps the payload to console alerts. Alerts are attributed to the
+// cluster named by their labels, falling back to defaultCluster for
+// Alertmanagers that do not set a cluster external label.
+func (p *AlertmanagerPayload) ToAlerts(defaultCluster string) []Alert {
+	out := make([]Alert, 0, len(p.Alerts))
+	for _, a := range p.Alerts {
+		out = append(out, a.toAlert(p, defaultCluster))
+	}
+	return out
+}
+
+func (a AlertmanagerAlert) toAlert(p *AlertmanagerPayload, defaultCluster string) Alert {
+	name := a.Labels["alertname"]
+	if name == "" {
+		name = "Alertmanager alert"
+	}
+	cluster := AlertmanagerCluster(a.Labels, defaultCluster)
+	alert := Alert{
+		ID:        AlertmanagerAlertID(a, cluster),
+		RuleID:    "alertmanager:" + name,
+		RuleName:  name,
+		Severity:  alertmanagerSeverity(a.Labels["severity"]),
+		Status:    AlertStatusFiring,
+		Message:   alertmanagerMessage(a, name),
+		Cluster:   cluster,
+		Namespace: a.Labels["namespace"],
+		FiredAt:   a.StartsAt,
+		Details: map[string]interface{}{
+			"source":       "alertmanager",
+			"labels":       a.Labels,
+			"annotations":  a.Annotations,
+			"fingerprint":  a.Fingerprint,
+			"generatorURL": a.GeneratorURL,
+			"externalURL":  p.ExternalURL,
+			"receiver":     p.Receiver,
+		},
+	}
+	if a.Status == AlertStatusResolved {
+		alert.Status = AlertStatusResolved
+		alert.Details["resolvedAt"] = a.EndsAt
+	}
+	for _, r := range resourceLabels {
+		if v := a.Labels[r.label]; v != "" {
+			alert.ResourceKind, alert.Resource = r.kind, v
+			break
+		}
+	}
+	return alert
+}
+
+// AlertmanagerCluster returns the cluster an alert's labels name, or
+// fallback when none do.
+func AlertmanagerCluster(labels map[string]string, fallback string) string {
+	for _, l := range clusterLabels {
+		if v := labels[l]; v != "" {
+			return v
+		}
+	}
+	return fallback
+}
+
+// AlertmanagerAlertID derives a stable alert ID from the Alertmanager
+// fingerprint, scoped to the cluster so identical rules on two clusters
+// without a cluster label do not collapse into one alert. Payloads without
+// a fingerprint fall back to a hash of the label set, which is how
+// Alertmanager computes it.
+func AlertmanagerAlertID(a AlertmanagerAlert, cluster string) string {
+	fp := a.Fingerprint
+	if fp == "" {
+		keys := make([]string, 0, len(a.Labels))
+		for k := range a.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		h := sha256.New()
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s\x00%s\x00", k, a.Labels[k])
+		}
+		fp = hex.EncodeToString(h.Sum(nil))[:16]
+	}
+	if cluster == "" {
+		return "am:" + fp
+	}
+	return "am:" + cluster + ":" + fp
+}
+
+func alertmanagerSeverity(s string) AlertSeverity {
+	switch strings.ToLower(s) {
+	case "critical", "error", "page", "high":
+		return SeverityCritical
+	case "warning", "warn", "medium":
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+func alertmanagerMessage(a AlertmanagerAlert, name string) string {
+	for _, k := range []string{"summary", "description", "message"} {
+		if v := strings.TrimSpace(a.Annotations[k]); v != "" {
+			return v
+		}
+	}
+	return name
+}
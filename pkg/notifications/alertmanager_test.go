@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertmanagerPayload_ToAlerts(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	p := AlertmanagerPayload{
+		Version:     "4",
+		Receiver:    "console",
+		ExternalURL: "http://alertmanager:9093",
+		Alerts: []AlertmanagerAlert{
+			{
+				Status:      "firing",
+				Labels:      map[string]string{"alertname": "KubePodCrashLooping", "severity": "critical", "namespace": "shop", "pod": "api-1"},
+				Annotations: map[string]string{"summary": "api-1 is crash looping"},
+				StartsAt:    started,
+				Fingerprint: "abc123",
+			},
+			{
+				Status:      "resolved",
+				Labels:      map[string]string{"alertname": "NodeDown", "cluster": "edge", "node": "n1"},
+				StartsAt:    started,
+				EndsAt:      started.Add(time.Hour),
+				Fingerprint: "def456",
+			},
+		},
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	alerts := p.ToAlerts("prod")
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(alerts))
+	}
+	crash := alerts[0]
+	if crash.ID != "am:prod:abc123" || crash.Cluster != "prod" {
+		t.Errorf("crash ID/cluster = %q/%q, want query cluster attribution", crash.ID, crash.Cluster)
+	}
+	if crash.Severity != SeverityCritical || crash.Status != AlertStatusFiring || crash.RuleName != "KubePodCrashLooping" {
+		t.Errorf("crash = %+v", crash)
+	}
+	if crash.Message != "api-1 is crash looping" || crash.ResourceKind != "Pod" || crash.Resource != "api-1" || !crash.FiredAt.Equal(started) {
+		t.Errorf("crash = %+v", crash)
+	}
+
+	node := alerts[1]
+	if node.Cluster != "edge" || node.ID != "am:edge:def456" {
+		t.Errorf("node ID/cluster = %q/%q, want label cluster attribution", node.ID, node.Cluster)
+	}
+	if node.Status != AlertStatusResolved || node.Severity != SeverityInfo || node.ResourceKind != "Node" || node.Message != "NodeDown" {
+		t.Errorf("node = %+v", node)
+	}
+
+	p.Version = "3"
+	if err := p.Validate(); err == nil {
+		t.Error("Validate accepted a v3 payload")
+	}
+}
+
+func TestAlertmanagerAlertID_WithoutFingerprint(t *testing.T) {
+	a := AlertmanagerAlert{Labels: map[string]string{"alertname": "X", "job": "y"}}
+	b := AlertmanagerAlert{Labels: map[string]string{"job": "y", "alertname": "X"}}
+	if AlertmanagerAlertID(a, "c") != AlertmanagerAlertID(b, "c") {
+		t.Error("label hash depends on map order")
+	}
+	if AlertmanagerAlertID(a, "c") == AlertmanagerAlertID(a, "d") {
+		t.Error("alert ID is not scoped to the cluster")
+	}
+}
@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	discordHTTPTimeout = 10 * time.Second
+	// Discord rejects embeds whose title or description exceed these.
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+)
+
+// DiscordNotifier posts embeds to a Discord channel webhook.
+type DiscordNotifier struct {
+	WebhookURL string
+	HTTPClient *http.Client
+	templated
+}
+
+// NewDiscordNotifier validates the webhook URL and returns a notifier.
+func NewDiscordNotifier(webhookURL string) (*DiscordNotifier, error) {
+	if err := validateOutboundURL(webhookURL, "discord webhook"); err != nil {
+		return nil, err
+	}
+	return &DiscordNotifier{
+		WebhookURL: webhookURL,
+		HTTPClient: newOutboundHTTPClient(discordHTTPTimeout),
+	}, nil
+}
+
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// Send posts the alert as a Discord embed.
+func (d *DiscordNotifier) Send(alert Alert) error {
+	if d.WebhookURL == "" {
+		return fmt.Errorf("discord webhook URL not configured")
+	}
+	title, text := d.render(alert, alert.RuleName, alert.Message)
+
+	fields := []discordField{
+		{Name: "Severity", Value: string(alert.Severity), Inline: true},
+		{Name: "Status", Value: alert.Status, Inline: true},
+	}
+	for _, f := range alertFacts(alert) {
+		fields = append(fields, discordField{Name: f[0], Value: f[1], Inline: true})
+	}
+	embed := discordEmbed{
+		Title:       truncateRunes(title, discordMaxTitle),
+		Description: truncateRunes(text, discordMaxDescription),
+		Color:       discordColor(alert.Severity),
+		Fields:      fields,
+		Footer:      &discordFooter{Text: "KubeStellar Console"},
+	}
+	if !alert.FiredAt.IsZero() {
+		embed.Timestamp = alert.FiredAt.UTC().Format(time.RFC3339)
+	}
+	msg := discordMessage{Username: "KubeStellar Console", Embeds: []discordEmbed{embed}}
+	return sendJSON(d.HTTPClient, http.MethodPost, d.WebhookURL, nil, msg, "discord")
+}
+
+// Test sends a test notification to verify configuration.
+func (d *DiscordNotifier) Test() error {
+	return d.Send(testAlert())
+}
+
+func discordColor(severity AlertSeverity) int {
+	switch severity {
+	case SeverityCritical:
+		return 0xE01E5A
+	case SeverityWarning:
+		return 0xECB22E
+	default:
+		return 0x2EB67D
+	}
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut.
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordNotifier_Send(t *testing.T) {
+	var captured discordMessage
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	n, err := NewDiscordNotifier(ts.URL)
+	require.NoError(t, err)
+	require.NoError(t, n.Send(Alert{
+		RuleName:     "Pod crash looping",
+		Severity:     SeverityWarning,
+		Status:       "firing",
+		Namespace:    "shop",
+		Resource:     "api-1",
+		ResourceKind: "Pod",
+		Message:      strings.Repeat("x", discordMaxDescription+10),
+	}))
+
+	require.Len(t, captured.Embeds, 1)
+	e := captured.Embeds[0]
+	require.Equal(t, "Pod crash looping", e.Title)
+	require.Equal(t, 0xECB22E, e.Color)
+	require.Len(t, []rune(e.Description), discordMaxDescription)
+	require.Empty(t, e.Timestamp)
+	require.Equal(t, discordField{Name: "Resource", Value: "api-1 (Pod)", Inline: true}, e.Fields[len(e.Fields)-1])
+}
+
+func TestDiscordNotifier_ErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	n, err := NewDiscordNotifier(ts.URL)
+	require.NoError(t, err)
+	err = n.Test()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "discord endpoint returned status 429")
+}
@@ -3,11 +3,15 @@ package notifications
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"html/template"
 	"log/slog"
+	"mime"
+	"mime/multipart"
 	"net"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 )
@@ -39,6 +43,7 @@ type EmailNotifier struct {
 	From     string
 	To       []string
 	UseTLS   bool
+	templated
 }
 
 // NewEmailNotifier creates a new email notifier
@@ -66,16 +71,48 @@ func (e *EmailNotifier) Send(alert Alert) error {
 		return fmt.Errorf("no recipients configured")
 	}
 
-	subject := fmt.Sprintf("[%s] %s - %s", alert.Severity, alert.RuleName, alert.Cluster)
+	// The body template replaces the alert message inside the HTML layout,
+	// where it is escaped like any other alert text.
+	subject, message := e.render(alert, fmt.Sprintf("[%s] %s - %s", alert.Severity, alert.RuleName, alert.Cluster), alert.Message)
+	alert.Message = message
 	body, err := e.formatEmailBody(alert)
 	if err != nil {
 		return fmt.Errorf("failed to format email body: %w", err)
 	}
 
 	// Build email message
-	emailMsg := e.buildMessage(subject, body)
+	return e.deliver(e.buildMessage(subject, body))
+}
+
+// EmailAttachment is a file attached to a report email.
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// SendReport emails an HTML report with file attachments, e.g. the monthly
+// GPU chargeback export. Unlike Send it is not an alert, so no alert
+// template is applied.
+func (e *EmailNotifier) SendReport(subject, htmlBody string, attachments []EmailAttachment) error {
+	if e.SMTPHost == "" {
+		return fmt.Errorf("SMTP host not configured")
+	}
+	if e.From == "" {
+		return fmt.Errorf("from address not configured")
+	}
+	if len(e.To) == 0 {
+		return fmt.Errorf("no recipients configured")
+	}
+	msg, err := e.buildMultipartMessage(subject, htmlBody, attachments)
+	if err != nil {
+		return fmt.Errorf("failed to build report email: %w", err)
+	}
+	return e.deliver(msg)
+}
 
-	// Send email
+// deliver sends a fully built message to the configured recipients.
+func (e *EmailNotifier) deliver(emailMsg string) error {
 	addr := fmt.Sprintf("%s:%d", e.SMTPHost, e.SMTPPort)
 	isLocalhost := e.SMTPHost == "localhost" || e.SMTPHost == "127.0.0.1" || e.SMTPHost == "::1"
 
@@ -94,7 +131,7 @@ func (e *EmailNotifier) Send(alert Alert) error {
 		slog.Warn("[Email] SMTP credentials sent without TLS to remote host — enable UseTLS for security", "host", e.SMTPHost)
 	}
 
-	err = smtp.SendMail(addr, auth, e.From, e.To, []byte(emailMsg))
+	err := smtp.SendMail(addr, auth, e.From, e.To, []byte(emailMsg))
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
@@ -232,6 +269,69 @@ func (e *EmailNotifier) buildMessage(subject, body string) string {
 	return msg
 }
 
+// emailBase64LineLen is the RFC 2045 maximum encoded line length.
+const emailBase64LineLen = 76
+
+// buildMultipartMessage constructs a multipart/mixed message holding the
+// HTML body followed by base64-encoded attachments. Headers are sanitized
+// as in buildMessage (#7535).
+func (e *EmailNotifier) buildMultipartMessage(subject, htmlBody string, attachments []EmailAttachment) (string, error) {
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+
+	bodyHeader := textproto.MIMEHeader{}
+	bodyHeader.Set("Content-Type", "text/html; charset=UTF-8")
+	bw, err := mw.CreatePart(bodyHeader)
+	if err != nil {
+		return "", err
+	}
+	if _, err := bw.Write([]byte(htmlBody)); err != nil {
+		return "", err
+	}
+
+	for _, a := range attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", sanitizeHeaderValue(contentType))
+		h.Set("Content-Transfer-Encoding", "base64")
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": sanitizeHeaderValue(a.Filename)}))
+		aw, err := mw.CreatePart(h)
+		if err != nil {
+			return "", err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 0 {
+			n := emailBase64LineLen
+			if n > len(encoded) {
+				n = len(encoded)
+			}
+			if _, err := aw.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+				return "", err
+			}
+			encoded = encoded[n:]
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	safeTo := make([]string, len(e.To))
+	for i, addr := range e.To {
+		safeTo[i] = sanitizeHeaderValue(addr)
+	}
+	msg := fmt.Sprintf("From: %s\r\n", sanitizeHeaderValue(e.From))
+	msg += fmt.Sprintf("To: %s\r\n", strings.Join(safeTo, ", "))
+	msg += fmt.Sprintf("Subject: %s\r\n", sanitizeHeaderValue(subject))
+	msg += "MIME-Version: 1.0\r\n"
+	msg += fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n", mw.Boundary())
+	msg += "\r\n"
+	msg += parts.String()
+	return msg, nil
+}
+
 // formatEmailBody formats the alert as an HTML email
 func (e *EmailNotifier) formatEmailBody(alert Alert) (string, error) {
 	tmpl := `
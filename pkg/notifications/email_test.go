@@ -1,7 +1,13 @@
 package notifications
 
 import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net"
+	"net/mail"
+	"strings"
 	"testing"
 	"time"
 
@@ -100,3 +106,36 @@ func TestSanitizeHeaderValue(t *testing.T) {
 	require.Equal(t, "unsafevalue", sanitizeHeaderValue("unsafe\nvalue"))
 	require.Equal(t, "unsafevalue", sanitizeHeaderValue("unsafe\rvalue"))
 }
+
+func TestEmailNotifier_BuildMultipartMessage(t *testing.T) {
+	e := NewEmailNotifier("smtp.example.com", 587, "", "", "console@example.com", []string{"finance@example.com"})
+	csv := []byte("group,cost\nalice,12.50\n")
+
+	msg, err := e.buildMultipartMessage("GPU report\r\nBcc: evil@example.com", "<p>Summary</p>", []EmailAttachment{
+		{Filename: "gpu-chargeback.csv", ContentType: "text/csv", Data: csv},
+	})
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(msg))
+	require.NoError(t, err)
+	require.Equal(t, "GPU reportBcc: evil@example.com", parsed.Header.Get("Subject"))
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	body, err := mr.NextPart()
+	require.NoError(t, err)
+	html, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, "<p>Summary</p>", string(html))
+
+	attachment, err := mr.NextPart()
+	require.NoError(t, err)
+	require.Equal(t, "gpu-chargeback.csv", attachment.FileName())
+	encoded, err := io.ReadAll(attachment)
+	require.NoError(t, err)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	require.NoError(t, err)
+	require.Equal(t, csv, decoded)
+}
@@ -0,0 +1,89 @@
+package notifications
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const matrixHTTPTimeout = 10 * time.Second
+
+// MatrixNotifier sends m.notice messages to a Matrix room through the
+// client-server API, authenticating as a bot user with an access token.
+type MatrixNotifier struct {
+	HomeserverURL string
+	AccessToken   string
+	RoomID        string
+	HTTPClient    *http.Client
+	templated
+}
+
+// NewMatrixNotifier validates the homeserver URL and returns a notifier.
+// roomID is a room ID ("!abc:example.org") the bot has joined.
+func NewMatrixNotifier(homeserverURL, accessToken, roomID string) (*MatrixNotifier, error) {
+	if err := validateOutboundURL(homeserverURL, "matrix homeserver"); err != nil {
+		return nil, err
+	}
+	if accessToken == "" || roomID == "" {
+		return nil, fmt.Errorf("matrix access token and room ID are required")
+	}
+	return &MatrixNotifier{
+		HomeserverURL: strings.TrimRight(homeserverURL, "/"),
+		AccessToken:   accessToken,
+		RoomID:        roomID,
+		HTTPClient:    newOutboundHTTPClient(matrixHTTPTimeout),
+	}, nil
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// Send posts the alert to the room as a plain-text body with an HTML
+// rendering for clients that support it.
+func (m *MatrixNotifier) Send(alert Alert) error {
+	if m.HomeserverURL == "" || m.AccessToken == "" || m.RoomID == "" {
+		return fmt.Errorf("matrix notifier not configured")
+	}
+	title, text := m.render(alert, fmt.Sprintf("%s [%s] %s", severityIcon(alert.Severity), alert.Severity, alert.RuleName), alert.Message)
+
+	plain := []string{title, text}
+	formatted := []string{"<strong>" + html.EscapeString(title) + "</strong>", strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")}
+	for _, f := range alertFacts(alert) {
+		plain = append(plain, fmt.Sprintf("%s: %s", f[0], f[1]))
+		formatted = append(formatted, fmt.Sprintf("<em>%s:</em> %s", f[0], html.EscapeString(f[1])))
+	}
+	msg := matrixMessage{
+		MsgType:       "m.notice",
+		Body:          strings.Join(plain, "\n"),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: strings.Join(formatted, "<br>"),
+	}
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		m.HomeserverURL, url.PathEscape(m.RoomID), url.PathEscape(matrixTxnID(alert)))
+	headers := map[string]string{"Authorization": "Bearer " + m.AccessToken}
+	return sendJSON(m.HTTPClient, http.MethodPut, endpoint, headers, msg, "matrix")
+}
+
+// matrixTxnID returns the transaction ID for alert. The homeserver
+// deduplicates by transaction ID, so retries of a queued delivery reuse its
+// ID and post the message once; unqueued sends get a fresh one.
+func matrixTxnID(alert Alert) string {
+	if alert.NotificationID != "" {
+		return "kc-" + alert.NotificationID
+	}
+	return uuid.NewString()
+}
+
+// Test sends a test notification to verify configuration.
+func (m *MatrixNotifier) Test() error {
+	return m.Send(testAlert())
+}
@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatrixNotifier_Send(t *testing.T) {
+	var captured matrixMessage
+	var path, auth, method string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth, method = r.URL.EscapedPath(), r.Header.Get("Authorization"), r.Method
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	defer ts.Close()
+
+	n, err := NewMatrixNotifier(ts.URL+"/", "tok", "!room:example.org")
+	require.NoError(t, err)
+	require.NoError(t, n.Send(Alert{
+		RuleName: "Disk <full>",
+		Severity: SeverityCritical,
+		Status:   "firing",
+		Cluster:  "prod",
+		Message:  "95% used",
+	}))
+
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "Bearer tok", auth)
+	require.True(t, strings.HasPrefix(path, "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/"), path)
+	require.Equal(t, "m.notice", captured.MsgType)
+	require.Equal(t, "🚨 [critical] Disk <full>\n95% used\nCluster: prod", captured.Body)
+	require.Contains(t, captured.FormattedBody, "<strong>🚨 [critical] Disk &lt;full&gt;</strong>")
+}
+
+func TestMatrixNotifier_RetriesReuseTransactionID(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	defer ts.Close()
+
+	n, err := NewMatrixNotifier(ts.URL, "tok", "!room:example.org")
+	require.NoError(t, err)
+	queued := Alert{RuleName: "Disk full", Severity: SeverityWarning, NotificationID: "d1"}
+	require.NoError(t, n.Send(queued))
+	require.NoError(t, n.Send(queued))
+	require.NoError(t, n.Send(Alert{RuleName: "Disk full", Severity: SeverityWarning, NotificationID: "d2"}))
+
+	require.Len(t, paths, 3)
+	require.True(t, strings.HasSuffix(paths[0], "/kc-d1"), paths[0])
+	require.Equal(t, paths[0], paths[1], "a retry must reuse the transaction ID")
+	require.NotEqual(t, paths[0], paths[2])
+}
+
+func TestNewMatrixNotifier_RequiresRoomAndToken(t *testing.T) {
+	_, err := NewMatrixNotifier("https://matrix.example.org", "", "!room:example.org")
+	require.Error(t, err)
+	_, err = NewMatrixNotifier("https://matrix.example.org", "tok", "")
+	require.Error(t, err)
+}
@@ -20,6 +20,7 @@ const (
 type OpsGenieNotifier struct {
 	APIKey     string
 	HTTPClient *http.Client
+	templated
 }
 
 // NewOpsGenieNotifier creates a new OpsGenie notifier
@@ -71,8 +72,8 @@ func (o *OpsGenieNotifier) Send(alert Alert) error {
 }
 
 func (o *OpsGenieNotifier) createAlert(alert Alert, alias string) error {
+	message, description := o.render(alert, alert.RuleName, alert.Message)
 	// Truncate message to OpsGenie's 130 char limit
-	message := alert.RuleName
 	if len(message) > 130 {
 		message = message[:127] + "..."
 	}
@@ -97,7 +98,7 @@ func (o *OpsGenieNotifier) createAlert(alert Alert, alias string) error {
 	ogAlert := opsgenieAlert{
 		Message:     message,
 		Alias:       alias,
-		Description: alert.Message,
+		Description: description,
 		Priority:    o.mapPriority(alert.Severity),
 		Tags:        tags,
 		Details:     details,
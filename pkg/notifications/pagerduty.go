@@ -25,6 +25,7 @@ const (
 type PagerDutyNotifier struct {
 	RoutingKey string
 	HTTPClient *http.Client
+	templated
 }
 
 // NewPagerDutyNotifier creates a new PagerDuty notifier
@@ -84,8 +85,11 @@ func (p *PagerDutyNotifier) Send(alert Alert) error {
 		event.EventAction = "resolve"
 	} else {
 		event.EventAction = "trigger"
+		// PagerDuty has a single summary line; a body template goes to
+		// custom_details.message.
+		summary, body := p.render(alert, fmt.Sprintf("[%s] %s — %s", alert.Severity, alert.RuleName, alert.Message), "")
 		event.Payload = &pagerdutyPayload{
-			Summary:   fmt.Sprintf("[%s] %s — %s", alert.Severity, alert.RuleName, alert.Message),
+			Summary:   summary,
 			Severity:  p.mapSeverity(alert.Severity),
 			Source:    alert.Cluster,
 			Component: alert.Resource,
@@ -94,6 +98,14 @@ func (p *PagerDutyNotifier) Send(alert Alert) error {
 			CustomDetails: alert.Details,
 			Timestamp: alert.FiredAt.Format(time.RFC3339),
 		}
+		if body != "" {
+			details := make(map[string]interface{}, len(alert.Details)+1)
+			for k, v := range alert.Details {
+				details[k] = v
+			}
+			details["message"] = body
+			event.Payload.CustomDetails = details
+		}
 	}
 
 	return p.sendEvent(event)
@@ -0,0 +1,744 @@
+package notifications
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// Delivery statuses.
+const (
+	DeliveryPending  = "pending"
+	DeliverySent     = "sent"
+	DeliveryFailed   = "failed"
+	DeliverySilenced = "silenced"
+)
+
+// Alert statuses the queue distinguishes. Anything other than resolved is
+// treated as firing.
+const (
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
+)
+
+// Queue defaults.
+const (
+	defaultGroupWait      = 30 * time.Second
+	defaultRepeatInterval = 4 * time.Hour
+	defaultMaxAttempts    = 8
+	defaultInitialBackoff = 30 * time.Second
+	defaultMaxBackoff     = 30 * time.Minute
+	defaultPollInterval   = 5 * time.Second
+	defaultRetention      = 7 * 24 * time.Hour
+	// deliveryBatchSize caps how many due deliveries one poll attempts.
+	deliveryBatchSize = 50
+	// claimLease is how long a replica owns the deliveries it claims.
+	// Deliveries still unsent when it lapses are left for the next claim.
+	claimLease = 5 * time.Minute
+	// pruneInterval is how often finished deliveries and expired silences
+	// are cleaned up.
+	pruneInterval = time.Hour
+	// statusWindow is how many recent deliveries TargetStatuses summarizes.
+	statusWindow = 500
+)
+
+// ErrDeliveryNotFound is returned by Retry for an unknown delivery.
+var ErrDeliveryNotFound = errors.New("notification delivery not found")
+
+// QueueStore persists queued deliveries, dedup records and silences.
+// store.Store satisfies it.
+type QueueStore interface {
+	SaveNotification(ctx context.Context, d store.NotificationDelivery) error
+	GetNotification(ctx context.Context, id string) (*store.NotificationDelivery, error)
+	ClaimDueNotifications(ctx context.Context, now, until time.Time, limit int) ([]store.NotificationDelivery, error)
+	FindNotificationGroup(ctx context.Context, target, groupKey string, now time.Time) (*store.NotificationDelivery, error)
+	UpdateNotificationGroup(ctx context.Context, id string, alerts []byte, now time.Time) (bool, error)
+	PendingNotifications(ctx context.Context, target, groupKey string) ([]store.NotificationDelivery, error)
+	ListNotifications(ctx context.Context, status string, limit int) ([]store.NotificationDelivery, error)
+	PruneNotifications(ctx context.Context, before time.Time) (int64, error)
+	GetNotifiedAlert(ctx context.Context, fingerprint string) (*store.NotifiedAlert, error)
+	SaveNotifiedAlert(ctx context.Context, a store.NotifiedAlert) error
+	ListSilences(ctx context.Context) ([][]byte, error)
+	SaveSilence(ctx context.Context, id string, data []byte, endsAt time.Time) error
+	DeleteSilence(ctx context.Context, id string) error
+	PruneSilences(ctx context.Context, before time.Time) (int64, error)
+}
+
+// QueueOptions tunes the delivery queue. Zero values select the defaults.
+type QueueOptions struct {
+	// GroupWait is how long alerts for the same rule, cluster and status
+	// are collected into one message; negative disables grouping.
+	// PagerDuty and OpsGenie are never grouped; they deduplicate per alert
+	// themselves.
+	GroupWait time.Duration
+	// RepeatInterval is how long a firing alert is not re-sent.
+	RepeatInterval time.Duration
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+	// InitialBackoff doubles after each failed attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// PollInterval is how often due deliveries are attempted.
+	PollInterval time.Duration
+	// Retention is how long finished deliveries are kept for status.
+	Retention time.Duration
+}
+
+func (o *QueueOptions) setDefaults() {
+	if o.GroupWait < 0 {
+		o.GroupWait = 0
+	} else if o.GroupWait == 0 {
+		o.GroupWait = defaultGroupWait
+	}
+	if o.RepeatInterval <= 0 {
+		o.RepeatInterval = defaultRepeatInterval
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = defaultMaxAttempts
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = defaultInitialBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = defaultMaxBackoff
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+	if o.Retention <= 0 {
+		o.Retention = defaultRetention
+	}
+}
+
+// Queue is a durable outbound notification queue. Alerts are deduplicated
+// by fingerprint, checked against silences, grouped per target and then
+// delivered with exponential-backoff retries.
+type Queue struct {
+	service *Service
+	store   QueueStore
+	opts    QueueOptions
+	now     func() time.Time
+
+	// mu serializes enqueueing and the selection of due deliveries, so an
+	// alert is never appended to a group that is already being sent.
+	mu sync.Mutex
+}
+
+// EnableQueue routes SendAlert and SendAlertToChannels through a durable
+// queue backed by st. The caller must Run the returned queue to deliver.
+func (s *Service) EnableQueue(st QueueStore, opts QueueOptions) *Queue {
+	opts.setDefaults()
+	q := &Queue{service: s, store: st, opts: opts, now: time.Now}
+	s.mu.Lock()
+	s.queue = q
+	s.mu.Unlock()
+	return q
+}
+
+// Queue returns the delivery queue, or nil when SendAlert delivers
+// synchronously.
+func (s *Service) Queue() *Queue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.queue
+}
+
+// Fingerprint identifies an alert across repeats: its ID when it has one,
+// otherwise its rule and resource.
+func Fingerprint(a Alert) string {
+	key := a.ID
+	if key == "" {
+		key = strings.Join([]string{a.RuleID, a.Cluster, a.Namespace, a.ResourceKind, a.Resource}, "\x00")
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Enqueue queues alert for the registered notifiers, or for channels when
+// it is not nil. Silenced alerts, firing alerts already sent within the
+// repeat interval or still queued, and resolutions of alerts nobody was or
+// will be told about are dropped. An alert counts as notified once a
+// delivery carrying it has been sent.
+func (q *Queue) Enqueue(ctx context.Context, alert Alert, channels []NotificationChannel) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now().UTC()
+	if alert.Status != AlertStatusResolved {
+		alert.Status = AlertStatusFiring
+	}
+	if s := q.silencedBy(ctx, alert, now); s != nil {
+		slog.Info("[Notifications] alert silenced", "alert", alert.ID, "rule", alert.RuleName, "silence", s.ID)
+		return nil
+	}
+
+	fp := Fingerprint(alert)
+	prev, err := q.store.GetNotifiedAlert(ctx, fp)
+	if err != nil {
+		return fmt.Errorf("read notified alert: %w", err)
+	}
+	if alert.Status != AlertStatusResolved && prev != nil && prev.Status == AlertStatusFiring &&
+		now.Sub(prev.NotifiedAt) < q.opts.RepeatInterval {
+		slog.Debug("[Notifications] duplicate alert suppressed", "alert", alert.ID, "rule", alert.RuleName)
+		return nil
+	}
+	if alert.Status == AlertStatusResolved && prev != nil && prev.Status == AlertStatusResolved {
+		return nil
+	}
+
+	targets, err := q.targets(channels)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		slog.Info("No notifiers configured, alert will not be sent externally")
+		return nil
+	}
+	if alert.Status == AlertStatusResolved && prev == nil {
+		// Nobody was told it fired; resolve it only if a firing
+		// notification is still on its way.
+		firingAlert := alert
+		firingAlert.Status = AlertStatusFiring
+		pending := false
+		for _, t := range targets {
+			key, _ := q.groupKey(t, firingAlert, fp)
+			if pending, err = q.queued(ctx, t.id, key, fp); err != nil || pending {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("read queued notifications: %w", err)
+		}
+		if !pending {
+			return nil
+		}
+	}
+	for _, t := range targets {
+		if err := q.enqueueTarget(ctx, t, alert, fp, now); err != nil {
+			return fmt.Errorf("queue notification for %s: %w", t.id, err)
+		}
+	}
+	return nil
+}
+
+type queueTarget struct {
+	id      string
+	kind    NotificationType
+	channel []byte
+}
+
+// targets lists the registered notifiers, or the enabled channels when
+// channels is not nil. A channel target's ID includes a hash of its config
+// so two channels of the same type never share a group.
+func (q *Queue) targets(channels []NotificationChannel) ([]queueTarget, error) {
+	var out []queueTarget
+	if channels == nil {
+		for id := range q.service.snapshot() {
+			kind, _, _ := strings.Cut(id, ":")
+			out = append(out, queueTarget{id: id, kind: NotificationType(kind)})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+		return out, nil
+	}
+	for _, ch := range channels {
+		if !ch.Enabled {
+			continue
+		}
+		data, err := json.Marshal(ch)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		out = append(out, queueTarget{
+			id:      fmt.Sprintf("channel:%s:%s", ch.Type, hex.EncodeToString(sum[:4])),
+			kind:    ch.Type,
+			channel: data,
+		})
+	}
+	return out, nil
+}
+
+// groupKey returns the key deliveries of alert to t are grouped under, and
+// whether t groups alerts at all.
+func (q *Queue) groupKey(t queueTarget, alert Alert, fp string) (string, bool) {
+	if q.opts.GroupWait > 0 && t.kind != NotificationTypePagerDuty && t.kind != NotificationTypeOpsGenie {
+		return alert.RuleID + "|" + alert.Cluster + "|" + alert.Status, true
+	}
+	return fp + "|" + alert.Status, false
+}
+
+// queued reports whether a pending delivery to target under groupKey
+// already carries the alert with fingerprint fp.
+func (q *Queue) queued(ctx context.Context, target, groupKey, fp string) (bool, error) {
+	pending, err := q.store.PendingNotifications(ctx, target, groupKey)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range pending {
+		var alerts []Alert
+		if err := json.Unmarshal(d.Alerts, &alerts); err != nil {
+			continue
+		}
+		for _, a := range alerts {
+			if Fingerprint(a) == fp {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (q *Queue) enqueueTarget(ctx context.Context, t queueTarget, alert Alert, fp string, now time.Time) error {
+	groupKey, grouped := q.groupKey(t, alert, fp)
+	if grouped {
+		d, err := q.store.FindNotificationGroup(ctx, t.id, groupKey, now)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			var alerts []Alert
+			if err := json.Unmarshal(d.Alerts, &alerts); err != nil {
+				return err
+			}
+			data, err := json.Marshal(appendAlert(alerts, alert))
+			if err != nil {
+				return err
+			}
+			// Another replica may have claimed the group since it was
+			// read; then the alert goes into a new delivery.
+			if ok, err := q.store.UpdateNotificationGroup(ctx, d.ID, data, now); err != nil || ok {
+				return err
+			}
+		}
+	}
+	// A copy still waiting for delivery, or for a retry, is enough.
+	if ok, err := q.queued(ctx, t.id, groupKey, fp); err != nil || ok {
+		return err
+	}
+
+	data, err := json.Marshal([]Alert{alert})
+	if err != nil {
+		return err
+	}
+	next := now
+	if grouped {
+		next = now.Add(q.opts.GroupWait)
+	}
+	return q.store.SaveNotification(ctx, store.NotificationDelivery{
+		ID:          uuid.New().String(),
+		Target:      t.id,
+		Channel:     t.channel,
+		GroupKey:    groupKey,
+		Alerts:      data,
+		Status:      DeliveryPending,
+		NextAttempt: next,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+// appendAlert adds a to a group, replacing an earlier copy of the same
+// alert.
+func appendAlert(alerts []Alert, a Alert) []Alert {
+	fp := Fingerprint(a)
+	for i := range alerts {
+		if Fingerprint(alerts[i]) == fp {
+			alerts[i] = a
+			return alerts
+		}
+	}
+	return append(alerts, a)
+}
+
+// Run delivers due notifications every PollInterval until ctx is
+// cancelled, pruning old deliveries and expired silences hourly.
+func (q *Queue) Run(ctx context.Context) {
+	poll := time.NewTicker(q.opts.PollInterval)
+	defer poll.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			q.DeliverDue(ctx)
+		case <-prune.C:
+			q.prune(ctx)
+		}
+	}
+}
+
+// DeliverDue claims and attempts every delivery whose next attempt is due.
+// Claims are held in the store, so replicas sharing it never send the same
+// delivery twice.
+func (q *Queue) DeliverDue(ctx context.Context) {
+	q.mu.Lock()
+	now := q.now().UTC()
+	due, err := q.store.ClaimDueNotifications(ctx, now, now.Add(claimLease), deliveryBatchSize)
+	q.mu.Unlock()
+	if err != nil {
+		slog.Warn("[Notifications] failed to claim due deliveries", "error", err)
+		return
+	}
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		if !q.now().UTC().Before(d.ClaimedUntil) {
+			// The claim lapsed; another replica may hold it now.
+			continue
+		}
+		q.deliver(ctx, d)
+	}
+}
+
+func (q *Queue) deliver(ctx context.Context, d store.NotificationDelivery) {
+	now := q.now().UTC()
+	d.UpdatedAt = now
+	// Saving the outcome releases the claim.
+	d.ClaimedUntil = time.Time{}
+
+	var alerts []Alert
+	if err := json.Unmarshal(d.Alerts, &alerts); err != nil {
+		q.finish(ctx, &d, DeliveryFailed, fmt.Sprintf("undecodable alerts: %v", err))
+		return
+	}
+	// Drop alerts silenced while they waited in the queue.
+	kept := alerts[:0]
+	for _, a := range alerts {
+		if q.silencedBy(ctx, a, now) == nil {
+			kept = append(kept, a)
+		}
+	}
+	if len(kept) == 0 {
+		q.finish(ctx, &d, DeliverySilenced, "")
+		return
+	}
+
+	notifier, err := q.notifierFor(d)
+	if err != nil {
+		q.finish(ctx, &d, DeliveryFailed, err.Error())
+		return
+	}
+
+	msg := kept[0]
+	if len(kept) > 1 {
+		msg = groupAlert(kept)
+	}
+	msg.NotificationID = d.ID
+	d.Attempts++
+	if err := notifier.Send(msg); err != nil {
+		if d.Attempts >= q.opts.MaxAttempts {
+			slog.Error("[Notifications] delivery failed permanently", "target", d.Target, "attempts", d.Attempts, "error", err)
+			q.finish(ctx, &d, DeliveryFailed, err.Error())
+			return
+		}
+		d.LastError = err.Error()
+		d.NextAttempt = now.Add(q.backoff(d.Attempts))
+		slog.Warn("[Notifications] delivery failed, will retry", "target", d.Target,
+			"attempt", d.Attempts, "retry_at", d.NextAttempt, "error", err)
+		if err := q.store.SaveNotification(ctx, d); err != nil {
+			slog.Error("[Notifications] failed to save delivery", "id", d.ID, "error", err)
+		}
+		return
+	}
+	slog.Info("sent alert notification", "notifier", d.Target, "alerts", len(kept))
+	q.finish(ctx, &d, DeliverySent, "")
+	for _, a := range kept {
+		rec := store.NotifiedAlert{Fingerprint: Fingerprint(a), Status: a.Status, NotifiedAt: now}
+		if err := q.store.SaveNotifiedAlert(ctx, rec); err != nil {
+			slog.Error("[Notifications] failed to record notified alert", "alert", a.ID, "error", err)
+		}
+	}
+}
+
+func (q *Queue) finish(ctx context.Context, d *store.NotificationDelivery, status, lastError string) {
+	d.Status = status
+	d.LastError = lastError
+	if err := q.store.SaveNotification(ctx, *d); err != nil {
+		slog.Error("[Notifications] failed to save delivery", "id", d.ID, "error", err)
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (q *Queue) backoff(attempts int) time.Duration {
+	wait := q.opts.InitialBackoff
+	for i := 1; i < attempts && wait < q.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > q.opts.MaxBackoff {
+		wait = q.opts.MaxBackoff
+	}
+	return wait
+}
+
+func (q *Queue) notifierFor(d store.NotificationDelivery) (Notifier, error) {
+	if d.Channel == nil {
+		n, ok := q.service.snapshot()[d.Target]
+		if !ok {
+			return nil, fmt.Errorf("notifier %s is no longer registered", d.Target)
+		}
+		return n, nil
+	}
+	var ch NotificationChannel
+	if err := json.Unmarshal(d.Channel, &ch); err != nil {
+		return nil, fmt.Errorf("undecodable channel: %w", err)
+	}
+	n, err := channelNotifier(ch)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, fmt.Errorf("%s channel has incomplete config", ch.Type)
+	}
+	return n, nil
+}
+
+// groupAlert summarizes alerts sharing a rule, cluster and status in one
+// message. Details["alerts"] carries the individual alerts.
+func groupAlert(alerts []Alert) Alert {
+	first := alerts[0]
+	sum := sha256.Sum256([]byte(first.RuleID + "\x00" + first.Cluster))
+	g := Alert{
+		ID:       "group-" + hex.EncodeToString(sum[:8]),
+		RuleID:   first.RuleID,
+		RuleName: first.RuleName,
+		Severity: first.Severity,
+		Status:   first.Status,
+		Cluster:  first.Cluster,
+		FiredAt:  first.FiredAt,
+		Details:  map[string]interface{}{"count": len(alerts), "alerts": alerts},
+	}
+	lines := []string{fmt.Sprintf("%d alerts %s for %s", len(alerts), first.Status, first.RuleName)}
+	if first.Cluster != "" {
+		lines[0] += " on " + first.Cluster
+	}
+	for _, a := range alerts {
+		if severityRank(a.Severity) > severityRank(g.Severity) {
+			g.Severity = a.Severity
+		}
+		if !a.FiredAt.IsZero() && (g.FiredAt.IsZero() || a.FiredAt.Before(g.FiredAt)) {
+			g.FiredAt = a.FiredAt
+		}
+		lines = append(lines, "- "+a.Message)
+	}
+	g.Message = strings.Join(lines, "\n")
+	return g
+}
+
+func severityRank(s AlertSeverity) int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (q *Queue) prune(ctx context.Context) {
+	cutoff := q.now().UTC().Add(-q.opts.Retention)
+	if n, err := q.store.PruneNotifications(ctx, cutoff); err != nil {
+		slog.Warn("[Notifications] failed to prune deliveries", "error", err)
+	} else if n > 0 {
+		slog.Info("[Notifications] pruned old deliveries", "deleted", n)
+	}
+	if _, err := q.store.PruneSilences(ctx, cutoff); err != nil {
+		slog.Warn("[Notifications] failed to prune silences", "error", err)
+	}
+}
+
+// Delivery is a queued notification to one target.
+type Delivery struct {
+	ID          string    `json:"id"`
+	Target      string    `json:"target"`
+	Alerts      []Alert   `json:"alerts"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	LastError   string    `json:"lastError,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func deliveryView(d store.NotificationDelivery) Delivery {
+	v := Delivery{
+		ID:          d.ID,
+		Target:      d.Target,
+		Status:      d.Status,
+		Attempts:    d.Attempts,
+		NextAttempt: d.NextAttempt,
+		LastError:   d.LastError,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+	_ = json.Unmarshal(d.Alerts, &v.Alerts)
+	return v
+}
+
+// Deliveries returns the most recent deliveries, newest first, optionally
+// filtered by status.
+func (q *Queue) Deliveries(ctx context.Context, status string, limit int) ([]Delivery, error) {
+	rows, err := q.store.ListNotifications(ctx, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Delivery, 0, len(rows))
+	for _, d := range rows {
+		out = append(out, deliveryView(d))
+	}
+	return out, nil
+}
+
+// Retry schedules a failed delivery for another round of attempts.
+func (q *Queue) Retry(ctx context.Context, id string) (*Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d, err := q.store.GetNotification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	if d.Status != DeliveryFailed {
+		return nil, fmt.Errorf("only failed deliveries can be retried (status %s)", d.Status)
+	}
+	now := q.now().UTC()
+	d.Status, d.Attempts, d.NextAttempt, d.UpdatedAt = DeliveryPending, 0, now, now
+	if err := q.store.SaveNotification(ctx, *d); err != nil {
+		return nil, err
+	}
+	v := deliveryView(*d)
+	return &v, nil
+}
+
+// TargetStatus summarizes recent deliveries to one notifier or channel.
+type TargetStatus struct {
+	Target      string     `json:"target"`
+	Pending     int        `json:"pending"`
+	Sent        int        `json:"sent"`
+	Failed      int        `json:"failed"`
+	Silenced    int        `json:"silenced"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// TargetStatuses summarizes recent deliveries per target.
+func (q *Queue) TargetStatuses(ctx context.Context) ([]TargetStatus, error) {
+	recent, err := q.store.ListNotifications(ctx, "", statusWindow)
+	if err != nil {
+		return nil, err
+	}
+	byTarget := make(map[string]*TargetStatus)
+	for _, d := range recent {
+		ts := byTarget[d.Target]
+		if ts == nil {
+			ts = &TargetStatus{Target: d.Target}
+			byTarget[d.Target] = ts
+		}
+		switch d.Status {
+		case DeliveryPending:
+			ts.Pending++
+		case DeliverySent:
+			ts.Sent++
+			if ts.LastSuccess == nil || d.UpdatedAt.After(*ts.LastSuccess) {
+				at := d.UpdatedAt
+				ts.LastSuccess = &at
+			}
+		case DeliveryFailed:
+			ts.Failed++
+		case DeliverySilenced:
+			ts.Silenced++
+		}
+		// recent is newest first, so the first error seen is the latest.
+		if ts.LastError == "" && d.LastError != "" {
+			ts.LastError = d.LastError
+		}
+	}
+	out := make([]TargetStatus, 0, len(byTarget))
+	for _, ts := range byTarget {
+		out = append(out, *ts)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	return out, nil
+}
+
+// Silences returns every stored silence, including expired and future
+// ones.
+func (q *Queue) Silences(ctx context.Context) ([]Silence, error) {
+	docs, err := q.store.ListSilences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Silence, 0, len(docs))
+	for _, doc := range docs {
+		var s Silence
+		if err := json.Unmarshal(doc, &s); err != nil {
+			slog.Warn("[Notifications] skipping undecodable silence", "error", err)
+			continue
+		}
+		if err := s.Validate(); err != nil {
+			slog.Warn("[Notifications] skipping invalid silence", "id", s.ID, "error", err)
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// SaveSilence validates and stores a silence, assigning an ID and creation
+// time to a new one. A zero StartsAt starts it now.
+func (q *Queue) SaveSilence(ctx context.Context, s *Silence) error {
+	now := q.now().UTC()
+	if s.StartsAt.IsZero() {
+		s.StartsAt = now
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+		s.CreatedAt = now
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return q.store.SaveSilence(ctx, s.ID, data, s.EndsAt)
+}
+
+// DeleteSilence removes a silence, ending it immediately.
+func (q *Queue) DeleteSilence(ctx context.Context, id string) error {
+	return q.store.DeleteSilence(ctx, id)
+}
+
+// silencedBy returns the active silence matching alert, or nil.
+func (q *Queue) silencedBy(ctx context.Context, alert Alert, now time.Time) *Silence {
+	silences, err := q.Silences(ctx)
+	if err != nil {
+		// Failing open keeps alerts flowing when silences cannot be read.
+		slog.Warn("[Notifications] failed to load silences", "error", err)
+		return nil
+	}
+	for i := range silences {
+		if silences[i].ActiveAt(now) && silences[i].Matches(alert) {
+			return &silences[i]
+		}
+	}
+	return nil
+}
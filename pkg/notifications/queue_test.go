@@ -0,0 +1,298 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/store"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []Alert
+	err  error
+}
+
+func (r *recordingNotifier) Send(a Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, a)
+	return nil
+}
+
+func (r *recordingNotifier) Test() error { return nil }
+
+func newTestQueue(t *testing.T, opts QueueOptions) (*Service, *Queue, *recordingNotifier, *time.Time) {
+	t.Helper()
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "queue.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	svc := NewService()
+	n := &recordingNotifier{}
+	svc.register("slack:test", n)
+	q := svc.EnableQueue(db, opts)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+	return svc, q, n, &now
+}
+
+func firing(id, cluster, msg string) Alert {
+	return Alert{ID: id, RuleID: "r1", RuleName: "Crash loops", Severity: SeverityWarning,
+		Status: AlertStatusFiring, Cluster: cluster, Message: msg}
+}
+
+func TestQueue_GroupsAndDedupes(t *testing.T) {
+	svc, q, n, now := newTestQueue(t, QueueOptions{GroupWait: 30 * time.Second})
+	ctx := context.Background()
+
+	for _, a := range []Alert{firing("a1", "prod", "api crashing"), firing("a2", "prod", "web crashing"), firing("a1", "prod", "api crashing")} {
+		if err := svc.SendAlert(a); err != nil {
+			t.Fatalf("SendAlert: %v", err)
+		}
+	}
+	q.DeliverDue(ctx)
+	if len(n.sent) != 0 {
+		t.Fatalf("sent %d before the group window closed", len(n.sent))
+	}
+
+	*now = now.Add(31 * time.Second)
+	q.DeliverDue(ctx)
+	if len(n.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1 grouped message", len(n.sent))
+	}
+	if got := n.sent[0].Details["count"]; got != 2 {
+		t.Errorf("group count = %v, want 2", got)
+	}
+
+	// A repeat inside the repeat interval is suppressed.
+	if err := svc.SendAlert(firing("a1", "prod", "api crashing")); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(time.Minute)
+	q.DeliverDue(ctx)
+	if len(n.sent) != 1 {
+		t.Fatalf("duplicate alert was re-sent")
+	}
+
+	// Resolutions go out; resolving an alert never notified does not.
+	resolved := firing("a1", "prod", "api crashing")
+	resolved.Status = AlertStatusResolved
+	unknown := firing("a9", "prod", "never fired")
+	unknown.Status = AlertStatusResolved
+	for _, a := range []Alert{resolved, unknown} {
+		if err := svc.SendAlert(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	*now = now.Add(time.Minute)
+	q.DeliverDue(ctx)
+	if len(n.sent) != 2 || n.sent[1].Status != AlertStatusResolved || n.sent[1].ID != "a1" {
+		t.Fatalf("sent = %+v, want a single a1 resolution", n.sent[1:])
+	}
+}
+
+func TestQueue_RetriesWithBackoff(t *testing.T) {
+	svc, q, n, now := newTestQueue(t, QueueOptions{GroupWait: -1, MaxAttempts: 2, InitialBackoff: time.Minute})
+	ctx := context.Background()
+	n.err = errors.New("503 from slack")
+
+	if err := svc.SendAlert(firing("a1", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	q.DeliverDue(ctx)
+	pending, _ := q.Deliveries(ctx, DeliveryPending, 10)
+	if len(pending) != 1 || pending[0].Attempts != 1 || !pending[0].NextAttempt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("pending = %+v, want one retry scheduled a minute out", pending)
+	}
+
+	q.DeliverDue(ctx) // not due yet
+	*now = now.Add(time.Minute)
+	q.DeliverDue(ctx)
+	failed, _ := q.Deliveries(ctx, DeliveryFailed, 10)
+	if len(failed) != 1 || failed[0].LastError != "503 from slack" {
+		t.Fatalf("failed = %+v, want delivery failed after max attempts", failed)
+	}
+	statuses, err := q.TargetStatuses(ctx)
+	if err != nil || len(statuses) != 1 || statuses[0].Failed != 1 {
+		t.Fatalf("statuses = %+v, err = %v", statuses, err)
+	}
+
+	n.err = nil
+	if _, err := q.Retry(ctx, failed[0].ID); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	q.DeliverDue(ctx)
+	if len(n.sent) != 1 {
+		t.Fatalf("sent %d after manual retry, want 1", len(n.sent))
+	}
+	if _, err := q.Retry(ctx, "missing"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("Retry(missing) err = %v", err)
+	}
+}
+
+func TestQueue_DedupesOnlyDeliveredAlerts(t *testing.T) {
+	svc, q, n, now := newTestQueue(t, QueueOptions{GroupWait: -1, MaxAttempts: 1})
+	ctx := context.Background()
+	n.err = errors.New("503 from slack")
+
+	if err := svc.SendAlert(firing("a1", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	// A repeat while the first copy is still queued is not queued again.
+	if err := svc.SendAlert(firing("a1", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := q.Deliveries(ctx, DeliveryPending, 10); len(pending) != 1 {
+		t.Fatalf("pending = %d, want 1", len(pending))
+	}
+	q.DeliverDue(ctx)
+
+	// Nobody was told, so the repeat is not suppressed and the resolution
+	// of the undelivered alert is not sent on its own.
+	n.err = nil
+	*now = now.Add(time.Minute)
+	if err := svc.SendAlert(firing("a1", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	q.DeliverDue(ctx)
+	if len(n.sent) != 1 || n.sent[0].Status != AlertStatusFiring {
+		t.Fatalf("sent = %+v, want the repeated firing alert", n.sent)
+	}
+
+	resolved := firing("a2", "prod", "down")
+	resolved.Status = AlertStatusResolved
+	n.err = errors.New("503 from slack")
+	if err := svc.SendAlert(firing("a2", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	q.DeliverDue(ctx)
+	if err := svc.SendAlert(resolved); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := q.Deliveries(ctx, DeliveryPending, 10); len(pending) != 0 {
+		t.Fatalf("pending = %+v, want the resolution of a failed alert dropped", pending)
+	}
+}
+
+type blockingNotifier struct {
+	recordingNotifier
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingNotifier) Send(a Alert) error {
+	b.entered <- struct{}{}
+	<-b.release
+	return b.recordingNotifier.Send(a)
+}
+
+func TestQueue_ReplicasNeverSendTwice(t *testing.T) {
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "queue.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	svcA, svcB := NewService(), NewService()
+	a := &blockingNotifier{entered: make(chan struct{}), release: make(chan struct{})}
+	b := &recordingNotifier{}
+	svcA.register("slack:test", a)
+	svcB.register("slack:test", b)
+	qa := svcA.EnableQueue(db, QueueOptions{GroupWait: -1})
+	qb := svcB.EnableQueue(db, QueueOptions{GroupWait: -1})
+	qa.now, qb.now = clock, clock
+
+	if err := svcA.SendAlert(firing("a1", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		qa.DeliverDue(ctx)
+		close(done)
+	}()
+	<-a.entered
+	// Replica A holds the claim while it sends.
+	qb.DeliverDue(ctx)
+	close(a.release)
+	<-done
+	qb.DeliverDue(ctx)
+
+	if len(a.sent) != 1 || len(b.sent) != 0 {
+		t.Fatalf("replica A sent %d, replica B sent %d; want 1 and 0", len(a.sent), len(b.sent))
+	}
+}
+
+func TestQueue_Silences(t *testing.T) {
+	svc, q, n, now := newTestQueue(t, QueueOptions{GroupWait: -1})
+	ctx := context.Background()
+
+	window := &Silence{
+		Kind:     SilenceKindMaintenance,
+		Matchers: []Matcher{{Name: "cluster", Operator: MatchRegexp, Value: "staging-.*"}},
+		StartsAt: now.Add(time.Hour),
+		EndsAt:   now.Add(2 * time.Hour),
+	}
+	if err := q.SaveSilence(ctx, window); err != nil {
+		t.Fatalf("SaveSilence: %v", err)
+	}
+
+	// Queued before the window opens, delivered after: dropped at delivery.
+	if err := svc.SendAlert(firing("a1", "staging-eu", "down")); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(90 * time.Minute)
+	q.DeliverDue(ctx)
+	// Raised during the window: never queued.
+	if err := svc.SendAlert(firing("a2", "staging-us", "down")); err != nil {
+		t.Fatal(err)
+	}
+	// Other clusters are unaffected.
+	if err := svc.SendAlert(firing("a3", "prod", "down")); err != nil {
+		t.Fatal(err)
+	}
+	q.DeliverDue(ctx)
+
+	if len(n.sent) != 1 || n.sent[0].ID != "a3" {
+		t.Fatalf("sent = %+v, want only the prod alert", n.sent)
+	}
+	silenced, _ := q.Deliveries(ctx, DeliverySilenced, 10)
+	if len(silenced) != 1 {
+		t.Errorf("silenced deliveries = %d, want 1", len(silenced))
+	}
+}
+
+func TestSilenceValidate(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name string
+		s    Silence
+		ok   bool
+	}{
+		{"valid", Silence{Matchers: []Matcher{{Name: "cluster", Value: "prod"}}, StartsAt: start, EndsAt: start.Add(time.Hour)}, true},
+		{"no matchers", Silence{StartsAt: start, EndsAt: start.Add(time.Hour)}, false},
+		{"ends before start", Silence{Matchers: []Matcher{{Name: "cluster", Value: "prod"}}, StartsAt: start, EndsAt: start}, false},
+		{"unknown label", Silence{Matchers: []Matcher{{Name: "pod", Value: "x"}}, StartsAt: start, EndsAt: start.Add(time.Hour)}, false},
+		{"bad regexp", Silence{Matchers: []Matcher{{Name: "cluster", Operator: "=~", Value: "("}}, StartsAt: start, EndsAt: start.Add(time.Hour)}, false},
+		{"too long", Silence{Matchers: []Matcher{{Name: "cluster", Value: "prod"}}, StartsAt: start, EndsAt: start.Add(100 * 24 * time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.Validate(); (err == nil) != tt.ok {
+				t.Fatalf("Validate() err = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
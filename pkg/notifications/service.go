@@ -1,6 +1,7 @@
 package notifications
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -24,6 +25,7 @@ const (
 type Service struct {
 	mu        sync.RWMutex
 	notifiers map[string]Notifier
+	queue     *Queue // nil until EnableQueue; SendAlert* then deliver synchronously
 }
 
 // NewService creates a new notification service
@@ -112,8 +114,54 @@ func (s *Service) RegisterWebhookNotifier(id, webhookURL string) {
 	slog.Info("registered Webhook notifier", "id", id)
 }
 
-// SendAlert sends an alert to all configured notifiers
+// RegisterTeamsNotifier registers a Microsoft Teams notifier
+func (s *Service) RegisterTeamsNotifier(id, webhookURL string) {
+	if webhookURL == "" {
+		return
+	}
+	n, err := NewTeamsNotifier(webhookURL)
+	if err != nil {
+		slog.Error("failed to register Teams notifier", "id", id, "error", err)
+		return
+	}
+	s.register(fmt.Sprintf("teams:%s", id), n)
+	slog.Info("registered Teams notifier", "id", id)
+}
+
+// RegisterDiscordNotifier registers a Discord notifier
+func (s *Service) RegisterDiscordNotifier(id, webhookURL string) {
+	if webhookURL == "" {
+		return
+	}
+	n, err := NewDiscordNotifier(webhookURL)
+	if err != nil {
+		slog.Error("failed to register Discord notifier", "id", id, "error", err)
+		return
+	}
+	s.register(fmt.Sprintf("discord:%s", id), n)
+	slog.Info("registered Discord notifier", "id", id)
+}
+
+// RegisterMatrixNotifier registers a Matrix notifier
+func (s *Service) RegisterMatrixNotifier(id, homeserverURL, accessToken, roomID string) {
+	if homeserverURL == "" {
+		return
+	}
+	n, err := NewMatrixNotifier(homeserverURL, accessToken, roomID)
+	if err != nil {
+		slog.Error("failed to register Matrix notifier", "id", id, "error", err)
+		return
+	}
+	s.register(fmt.Sprintf("matrix:%s", id), n)
+	slog.Info("registered Matrix notifier", "id", id)
+}
+
+// SendAlert sends an alert to all configured notifiers, or queues it for
+// them once EnableQueue has been called.
 func (s *Service) SendAlert(alert Alert) error {
+	if q := s.Queue(); q != nil {
+		return q.Enqueue(context.Background(), alert, nil)
+	}
 	notifiers := s.snapshot()
 	if len(notifiers) == 0 {
 		slog.Info("No notifiers configured, alert will not be sent externally")
@@ -168,11 +216,15 @@ func parseSMTPPortConfig(config map[string]interface{}) (int, error) {
 	return port, nil
 }
 
-// SendAlertToChannels sends an alert to specific notification channels
+// SendAlertToChannels sends an alert to specific notification channels, or
+// queues it for them once EnableQueue has been called.
 func (s *Service) SendAlertToChannels(alert Alert, channels []NotificationChannel) error {
 	if len(channels) == 0 {
 		return nil
 	}
+	if q := s.Queue(); q != nil {
+		return q.Enqueue(context.Background(), alert, channels)
+	}
 
 	var errors []string
 	for i, channel := range channels {
@@ -180,61 +232,11 @@ func (s *Service) SendAlertToChannels(alert Alert, channels []NotificationChanne
 			continue
 		}
 
-		var notifier Notifier
 		channelID := fmt.Sprintf("channel-%d", i)
-
-		switch channel.Type {
-		case NotificationTypeSlack:
-			webhookURL, _ := channel.Config["slackWebhookUrl"].(string)
-			slackChannel, _ := channel.Config["slackChannel"].(string)
-			if webhookURL != "" {
-				notifier = NewSlackNotifier(webhookURL, slackChannel)
-			}
-
-		case NotificationTypeEmail:
-			smtpHost, _ := channel.Config["emailSMTPHost"].(string)
-			smtpPort, portErr := parseSMTPPortConfig(channel.Config)
-			if portErr != nil {
-				errors = append(errors, fmt.Sprintf("email channel %s: %v", channelID, portErr))
-				continue
-			}
-			username, _ := channel.Config["emailUsername"].(string)
-			password, _ := channel.Config["emailPassword"].(string)
-			from, _ := channel.Config["emailFrom"].(string)
-			to, _ := channel.Config["emailTo"].(string)
-
-			if smtpHost != "" && from != "" && to != "" {
-				recipients := splitAndCleanRecipients(to)
-				if len(recipients) == 0 {
-					errors = append(errors, fmt.Sprintf("email channel %s: no valid recipients", channelID))
-					continue
-				}
-				notifier = NewEmailNotifier(smtpHost, smtpPort, username, password, from, recipients)
-			}
-
-		case NotificationTypePagerDuty:
-			routingKey, _ := channel.Config["pagerdutyRoutingKey"].(string)
-			if routingKey != "" {
-				notifier = NewPagerDutyNotifier(routingKey)
-			}
-
-		case NotificationTypeOpsGenie:
-			apiKey, _ := channel.Config["opsgenieApiKey"].(string)
-			if apiKey != "" {
-				notifier = NewOpsGenieNotifier(apiKey)
-			}
-
-		case NotificationTypeWebhook:
-			// #6633: webhook channel type was declared but not wired in.
-			webhookURL, _ := channel.Config["webhookUrl"].(string)
-			if webhookURL != "" {
-				n, err := NewWebhookNotifier(webhookURL)
-				if err != nil {
-					errors = append(errors, fmt.Sprintf("webhook channel %s: %v", channelID, err))
-					continue
-				}
-				notifier = n
-			}
+		notifier, err := channelNotifier(channel)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s channel %s: %v", channel.Type, channelID, err))
+			continue
 		}
 
 		if notifier != nil {
@@ -261,6 +263,87 @@ func (s *Service) SendAlertToChannels(alert Alert, channels []NotificationChanne
 	return nil
 }
 
+// channelNotifier builds the notifier for a per-rule channel, including
+// its message templates. It returns a nil notifier when required config is
+// missing.
+func channelNotifier(channel NotificationChannel) (Notifier, error) {
+	n, err := baseChannelNotifier(channel)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	return withTemplate(n, channel.Config)
+}
+
+func baseChannelNotifier(channel NotificationChannel) (Notifier, error) {
+	switch channel.Type {
+	case NotificationTypeSlack:
+		webhookURL, _ := channel.Config["slackWebhookUrl"].(string)
+		slackChannel, _ := channel.Config["slackChannel"].(string)
+		if webhookURL != "" {
+			return NewSlackNotifier(webhookURL, slackChannel), nil
+		}
+
+	case NotificationTypeEmail:
+		smtpHost, _ := channel.Config["emailSMTPHost"].(string)
+		smtpPort, err := parseSMTPPortConfig(channel.Config)
+		if err != nil {
+			return nil, err
+		}
+		username, _ := channel.Config["emailUsername"].(string)
+		password, _ := channel.Config["emailPassword"].(string)
+		from, _ := channel.Config["emailFrom"].(string)
+		to, _ := channel.Config["emailTo"].(string)
+
+		if smtpHost != "" && from != "" && to != "" {
+			recipients := splitAndCleanRecipients(to)
+			if len(recipients) == 0 {
+				return nil, fmt.Errorf("no valid recipients")
+			}
+			return NewEmailNotifier(smtpHost, smtpPort, username, password, from, recipients), nil
+		}
+
+	case NotificationTypePagerDuty:
+		routingKey, _ := channel.Config["pagerdutyRoutingKey"].(string)
+		if routingKey != "" {
+			return NewPagerDutyNotifier(routingKey), nil
+		}
+
+	case NotificationTypeOpsGenie:
+		apiKey, _ := channel.Config["opsgenieApiKey"].(string)
+		if apiKey != "" {
+			return NewOpsGenieNotifier(apiKey), nil
+		}
+
+	case NotificationTypeWebhook:
+		// #6633: webhook channel type was declared but not wired in.
+		webhookURL, _ := channel.Config["webhookUrl"].(string)
+		if webhookURL != "" {
+			return NewWebhookNotifier(webhookURL)
+		}
+
+	case NotificationTypeTeams:
+		webhookURL, _ := channel.Config["teamsWebhookUrl"].(string)
+		if webhookURL != "" {
+			return NewTeamsNotifier(webhookURL)
+		}
+
+	case NotificationTypeDiscord:
+		webhookURL, _ := channel.Config["discordWebhookUrl"].(string)
+		if webhookURL != "" {
+			return NewDiscordNotifier(webhookURL)
+		}
+
+	case NotificationTypeMatrix:
+		homeserver, _ := channel.Config["matrixHomeserverUrl"].(string)
+		token, _ := channel.Config["matrixAccessToken"].(string)
+		roomID, _ := channel.Config["matrixRoomId"].(string)
+		if homeserver != "" && token != "" && roomID != "" {
+			return NewMatrixNotifier(homeserver, token, roomID)
+		}
+	}
+	return nil, nil
+}
+
 // TestNotifier tests a specific notifier configuration
 func (s *Service) TestNotifier(notifierType string, config map[string]interface{}) error {
 	var notifier Notifier
@@ -321,10 +404,58 @@ func (s *Service) TestNotifier(notifierType string, config map[string]interface{
 		}
 		notifier = n
 
+	case NotificationTypeTeams:
+		webhookURL, _ := config["teamsWebhookUrl"].(string)
+		if webhookURL == "" {
+			return fmt.Errorf("teams webhook URL is required")
+		}
+		n, err := NewTeamsNotifier(webhookURL)
+		if err != nil {
+			return err
+		}
+		notifier = n
+
+	case NotificationTypeDiscord:
+		webhookURL, _ := config["discordWebhookUrl"].(string)
+		if webhookURL == "" {
+			return fmt.Errorf("discord webhook URL is required")
+		}
+		n, err := NewDiscordNotifier(webhookURL)
+		if err != nil {
+			return err
+		}
+		notifier = n
+
+	case NotificationTypeMatrix:
+		homeserver, _ := config["matrixHomeserverUrl"].(string)
+		token, _ := config["matrixAccessToken"].(string)
+		roomID, _ := config["matrixRoomId"].(string)
+		if homeserver == "" || token == "" || roomID == "" {
+			return fmt.Errorf("matrix homeserver URL, access token, and room ID are required")
+		}
+		n, err := NewMatrixNotifier(homeserver, token, roomID)
+		if err != nil {
+			return err
+		}
+		notifier = n
+
 	default:
 		return fmt.Errorf("unsupported notifier type: %s", notifierType)
 	}
 
+	// Test with the channel's templates, and fail on templates that do not
+	// execute, so a broken template shows up here rather than falling back
+	// to the default format on the first real alert.
+	tmpl, err := templateFromConfig(config)
+	if err != nil {
+		return err
+	}
+	if _, _, err := tmpl.Execute(testAlert()); err != nil {
+		return err
+	}
+	if t, ok := notifier.(templatable); ok && tmpl != nil {
+		t.setTemplate(tmpl)
+	}
 	return notifier.Test()
 }
 
@@ -0,0 +1,146 @@
+package notifications
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SilenceKind distinguishes ad-hoc silences from planned maintenance
+// windows. Both mute matching alerts between StartsAt and EndsAt.
+type SilenceKind string
+
+const (
+	SilenceKindSilence     SilenceKind = "silence"
+	SilenceKindMaintenance SilenceKind = "maintenance"
+)
+
+// maxSilenceDuration bounds how long a single silence may last so a typo
+// cannot mute alerting indefinitely.
+const maxSilenceDuration = 90 * 24 * time.Hour
+
+// Matcher operators, as in Alertmanager.
+const (
+	MatchEqual     = "="
+	MatchNotEqual  = "!="
+	MatchRegexp    = "=~"
+	MatchNotRegexp = "!~"
+)
+
+// Matcher tests one alert label. Labels are alertname (the rule name),
+// rule_id, severity, status, cluster, namespace, resource and
+// resource_kind.
+type Matcher struct {
+	Name     string `json:"name"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+
+	re *regexp.Regexp
+}
+
+// Silence mutes the alerts matched by every one of its matchers while it
+// is active.
+type Silence struct {
+	ID        string      `json:"id"`
+	Kind      SilenceKind `json:"kind"`
+	Matchers  []Matcher   `json:"matchers"`
+	StartsAt  time.Time   `json:"startsAt"`
+	EndsAt    time.Time   `json:"endsAt"`
+	Comment   string      `json:"comment,omitempty"`
+	CreatedBy string      `json:"createdBy,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+var silenceLabels = map[string]bool{
+	"alertname": true, "rule_id": true, "severity": true, "status": true,
+	"cluster": true, "namespace": true, "resource": true, "resource_kind": true,
+}
+
+// Validate checks the silence and compiles its regexp matchers.
+func (s *Silence) Validate() error {
+	switch s.Kind {
+	case "":
+		s.Kind = SilenceKindSilence
+	case SilenceKindSilence, SilenceKindMaintenance:
+	default:
+		return fmt.Errorf("unknown silence kind %q", s.Kind)
+	}
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("at least one matcher is required")
+	}
+	if s.EndsAt.IsZero() || !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("endsAt must be after startsAt")
+	}
+	if s.EndsAt.Sub(s.StartsAt) > maxSilenceDuration {
+		return fmt.Errorf("silences may last at most %d days", int(maxSilenceDuration.Hours()/24))
+	}
+	for i := range s.Matchers {
+		if err := s.Matchers[i].compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Matcher) compile() error {
+	if !silenceLabels[m.Name] {
+		return fmt.Errorf("unknown matcher label %q", m.Name)
+	}
+	switch m.Operator {
+	case "":
+		m.Operator = MatchEqual
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return fmt.Errorf("matcher %s: %w", m.Name, err)
+		}
+		m.re = re
+	default:
+		return fmt.Errorf("unknown matcher operator %q", m.Operator)
+	}
+	return nil
+}
+
+// ActiveAt reports whether the silence is in effect at t.
+func (s *Silence) ActiveAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Matches reports whether every matcher matches alert.
+func (s *Silence) Matches(alert Alert) bool {
+	labels := alertLabels(alert)
+	for i := range s.Matchers {
+		if !s.Matchers[i].matches(labels[s.Matchers[i].Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Matcher) matches(v string) bool {
+	switch m.Operator {
+	case MatchNotEqual:
+		return v != m.Value
+	case MatchRegexp:
+		return m.re != nil && m.re.MatchString(v)
+	case MatchNotRegexp:
+		return m.re == nil || !m.re.MatchString(v)
+	default:
+		return v == m.Value
+	}
+}
+
+func alertLabels(a Alert) map[string]string {
+	return map[string]string{
+		"alertname":     a.RuleName,
+		"rule_id":       a.RuleID,
+		"severity":      string(a.Severity),
+		"status":        strings.ToLower(a.Status),
+		"cluster":       a.Cluster,
+		"namespace":     a.Namespace,
+		"resource":      a.Resource,
+		"resource_kind": a.ResourceKind,
+	}
+}
@@ -18,6 +18,7 @@ type SlackNotifier struct {
 	WebhookURL string
 	Channel    string
 	HTTPClient *http.Client
+	templated
 }
 
 // NewSlackNotifier creates a new Slack notifier
@@ -100,6 +101,7 @@ func (s *SlackNotifier) Send(alert Alert) error {
 		})
 	}
 
+	title, text := s.render(alert, alert.RuleName, alert.Message)
 	msg := slackMessage{
 		Username:  "KubeStellar Console",
 		IconEmoji: emoji,
@@ -107,8 +109,8 @@ func (s *SlackNotifier) Send(alert Alert) error {
 		Attachments: []slackAttachment{
 			{
 				Color:     color,
-				Title:     alert.RuleName,
-				Text:      alert.Message,
+				Title:     title,
+				Text:      text,
 				Fields:    fields,
 				Footer:    "KubeStellar Console",
 				Timestamp: alert.FiredAt.Unix(),
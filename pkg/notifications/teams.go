@@ -0,0 +1,125 @@
+package notifications
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const teamsHTTPTimeout = 10 * time.Second
+
+// TeamsNotifier posts Adaptive Cards to a Microsoft Teams incoming webhook
+// or a Teams Workflows "post to a channel when a webhook request is
+// received" URL; both accept the same message envelope.
+type TeamsNotifier struct {
+	WebhookURL string
+	HTTPClient *http.Client
+	templated
+}
+
+// NewTeamsNotifier validates the webhook URL and returns a notifier.
+func NewTeamsNotifier(webhookURL string) (*TeamsNotifier, error) {
+	if err := validateOutboundURL(webhookURL, "teams webhook"); err != nil {
+		return nil, err
+	}
+	return &TeamsNotifier{
+		WebhookURL: webhookURL,
+		HTTPClient: newOutboundHTTPClient(teamsHTTPTimeout),
+	}, nil
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+}
+
+// Send posts the alert as an Adaptive Card.
+func (t *TeamsNotifier) Send(alert Alert) error {
+	if t.WebhookURL == "" {
+		return fmt.Errorf("teams webhook URL not configured")
+	}
+	title, text := t.render(alert, fmt.Sprintf("%s %s", severityIcon(alert.Severity), alert.RuleName), alert.Message)
+
+	facts := []map[string]interface{}{
+		{"title": "Severity", "value": string(alert.Severity)},
+		{"title": "Status", "value": alert.Status},
+	}
+	for _, f := range alertFacts(alert) {
+		facts = append(facts, map[string]interface{}{"title": f[0], "value": f[1]})
+	}
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]interface{}{
+			{"type": "TextBlock", "text": title, "weight": "Bolder", "size": "Medium", "wrap": true, "color": teamsColor(alert.Severity)},
+			{"type": "TextBlock", "text": text, "wrap": true},
+			{"type": "FactSet", "facts": facts},
+		},
+	}
+	msg := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+	return sendJSON(t.HTTPClient, http.MethodPost, t.WebhookURL, nil, msg, "teams")
+}
+
+// Test sends a test notification to verify configuration.
+func (t *TeamsNotifier) Test() error {
+	return t.Send(testAlert())
+}
+
+func teamsColor(severity AlertSeverity) string {
+	switch severity {
+	case SeverityCritical:
+		return "Attention"
+	case SeverityWarning:
+		return "Warning"
+	default:
+		return "Good"
+	}
+}
+
+// severityIcon prefixes chat titles on notifiers without native severity
+// colors in the title line.
+func severityIcon(severity AlertSeverity) string {
+	switch severity {
+	case SeverityCritical:
+		return "🚨"
+	case SeverityWarning:
+		return "⚠️"
+	default:
+		return "ℹ️"
+	}
+}
+
+// alertFacts returns the optional location fields of an alert as
+// label/value pairs.
+func alertFacts(alert Alert) [][2]string {
+	var facts [][2]string
+	if alert.Cluster != "" {
+		facts = append(facts, [2]string{"Cluster", alert.Cluster})
+	}
+	if alert.Namespace != "" {
+		facts = append(facts, [2]string{"Namespace", alert.Namespace})
+	}
+	if alert.Resource != "" {
+		facts = append(facts, [2]string{"Resource", fmt.Sprintf("%s (%s)", alert.Resource, alert.ResourceKind)})
+	}
+	return facts
+}
@@ -0,0 +1,45 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamsNotifier_Send(t *testing.T) {
+	var captured teamsMessage
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.WriteHeader(http.StatusAccepted) // Teams Workflows answer 202
+	}))
+	defer ts.Close()
+
+	n, err := NewTeamsNotifier(ts.URL)
+	require.NoError(t, err)
+	require.NoError(t, n.Send(Alert{
+		RuleName: "Node down",
+		Severity: SeverityCritical,
+		Status:   "firing",
+		Cluster:  "prod",
+		Message:  "node-1 is NotReady",
+		FiredAt:  time.Now(),
+	}))
+
+	require.Equal(t, "message", captured.Type)
+	require.Len(t, captured.Attachments, 1)
+	card := captured.Attachments[0].Content
+	require.Equal(t, "AdaptiveCard", card.Type)
+	require.Equal(t, "🚨 Node down", card.Body[0]["text"])
+	require.Equal(t, "Attention", card.Body[0]["color"])
+	require.Equal(t, "node-1 is NotReady", card.Body[1]["text"])
+}
+
+func TestNewTeamsNotifier_RejectsPlainHTTP(t *testing.T) {
+	_, err := NewTeamsNotifier("http://example.webhook.office.com/x")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must use https")
+}
@@ -0,0 +1,181 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// maxTemplateBytes caps a user-supplied template's source.
+	maxTemplateBytes = 8 << 10 // 8 KB
+	// maxRenderedBytes caps a rendered title or body so a runaway range
+	// cannot build an unbounded message.
+	maxRenderedBytes = 32 << 10 // 32 KB
+)
+
+// Channel config keys holding a channel's message templates.
+const (
+	ConfigTitleTemplate = "titleTemplate"
+	ConfigBodyTemplate  = "bodyTemplate"
+)
+
+// MessageTemplate customizes how notifiers render an alert. Templates use
+// Go text/template syntax and execute against the Alert, so {{.RuleName}},
+// {{.Severity}}, {{.Cluster}}, {{.Message}} and {{.Details}} are available.
+// Grouped notifications carry the individual alerts in {{.Details.alerts}}.
+// An empty title or body keeps the notifier's built-in formatting for it.
+type MessageTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join": func(sep string, items []string) string {
+		return strings.Join(items, sep)
+	},
+	"default": func(def string, v interface{}) string {
+		if s := fmt.Sprint(v); v != nil && s != "" {
+			return s
+		}
+		return def
+	},
+	"toJson": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+}
+
+// ParseMessageTemplate compiles title and body templates. It returns nil
+// when both are empty.
+func ParseMessageTemplate(title, body string) (*MessageTemplate, error) {
+	if strings.TrimSpace(title) == "" && strings.TrimSpace(body) == "" {
+		return nil, nil
+	}
+	t := &MessageTemplate{}
+	var err error
+	if t.title, err = parseTemplatePart("title", title); err != nil {
+		return nil, err
+	}
+	if t.body, err = parseTemplatePart("body", body); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func parseTemplatePart(name, src string) (*template.Template, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	if len(src) > maxTemplateBytes {
+		return nil, fmt.Errorf("%s template exceeds %d bytes", name, maxTemplateBytes)
+	}
+	t, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// templateFromConfig compiles the templates in a channel or test config.
+func templateFromConfig(config map[string]interface{}) (*MessageTemplate, error) {
+	title, _ := config[ConfigTitleTemplate].(string)
+	body, _ := config[ConfigBodyTemplate].(string)
+	return ParseMessageTemplate(title, body)
+}
+
+// Execute renders alert with the template. Parts without a template render
+// as empty strings.
+func (t *MessageTemplate) Execute(alert Alert) (title, body string, err error) {
+	if t == nil {
+		return "", "", nil
+	}
+	if title, err = executeTemplatePart(t.title, alert); err != nil {
+		return "", "", err
+	}
+	if body, err = executeTemplatePart(t.body, alert); err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+func executeTemplatePart(t *template.Template, alert Alert) (string, error) {
+	if t == nil {
+		return "", nil
+	}
+	var buf cappedBuffer
+	if err := t.Execute(&buf, alert); err != nil {
+		return "", fmt.Errorf("%s template: %w", t.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// cappedBuffer fails writes past maxRenderedBytes, aborting the template.
+type cappedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > maxRenderedBytes {
+		return 0, fmt.Errorf("rendered more than %d bytes", maxRenderedBytes)
+	}
+	return b.Buffer.Write(p)
+}
+
+// templatable is implemented by notifiers embedding templated.
+type templatable interface {
+	setTemplate(*MessageTemplate)
+}
+
+// templated is embedded by every notifier to carry its optional template.
+type templated struct {
+	Template *MessageTemplate
+}
+
+func (t *templated) setTemplate(m *MessageTemplate) {
+	t.Template = m
+}
+
+// render returns the alert's title and body, using the notifier's template
+// where one is set and the notifier's defaults otherwise. A template that
+// fails to execute falls back to the default so the alert still goes out.
+func (t *templated) render(alert Alert, defaultTitle, defaultBody string) (string, string) {
+	m := t.Template
+	if m == nil {
+		return defaultTitle, defaultBody
+	}
+	title, body := defaultTitle, defaultBody
+	if s, err := executeTemplatePart(m.title, alert); err != nil {
+		slog.Warn("[Notifications] title template failed, using default", "error", err)
+	} else if m.title != nil {
+		title = s
+	}
+	if s, err := executeTemplatePart(m.body, alert); err != nil {
+		slog.Warn("[Notifications] body template failed, using default", "error", err)
+	} else if m.body != nil {
+		body = s
+	}
+	return title, body
+}
+
+// withTemplate attaches the templates in config to n.
+func withTemplate(n Notifier, config map[string]interface{}) (Notifier, error) {
+	tmpl, err := templateFromConfig(config)
+	if err != nil || tmpl == nil {
+		return n, err
+	}
+	if t, ok := n.(templatable); ok {
+		t.setTemplate(tmpl)
+	}
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageTemplate_Execute(t *testing.T) {
+	tmpl, err := ParseMessageTemplate(
+		`[{{upper (print .Severity)}}] {{.RuleName}} on {{default "all clusters" .Cluster}}`,
+		`{{.Message}}{{with .Details.runbook}} — runbook: {{.}}{{end}}`,
+	)
+	require.NoError(t, err)
+
+	title, body, err := tmpl.Execute(Alert{
+		RuleName: "High CPU",
+		Severity: SeverityWarning,
+		Message:  "CPU at 93%",
+		Details:  map[string]interface{}{"runbook": "https://runbooks/cpu"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "[WARNING] High CPU on all clusters", title)
+	require.Equal(t, "CPU at 93% — runbook: https://runbooks/cpu", body)
+
+	none, err := ParseMessageTemplate(" ", "")
+	require.NoError(t, err)
+	require.Nil(t, none)
+
+	_, err = ParseMessageTemplate("{{.RuleName", "")
+	require.ErrorContains(t, err, "invalid title template")
+	_, err = ParseMessageTemplate("", strings.Repeat("x", maxTemplateBytes+1))
+	require.ErrorContains(t, err, "exceeds")
+
+	huge, err := ParseMessageTemplate("", `{{range .Details.items}}{{$.Message}}{{end}}`)
+	require.NoError(t, err)
+	_, _, err = huge.Execute(Alert{Message: strings.Repeat("y", 1000), Details: map[string]interface{}{"items": make([]int, 100)}})
+	require.ErrorContains(t, err, "rendered more than")
+}
+
+func TestChannelNotifier_AppliesTemplate(t *testing.T) {
+	var captured slackMessage
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+	}))
+	defer ts.Close()
+
+	n, err := channelNotifier(NotificationChannel{
+		Type:    NotificationTypeSlack,
+		Enabled: true,
+		Config: map[string]interface{}{
+			"slackWebhookUrl":   ts.URL,
+			ConfigTitleTemplate: "{{.Cluster}}: {{.RuleName}}",
+			ConfigBodyTemplate:  "{{.Nope}}", // fails at execution: keeps the default body
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, n.Send(Alert{RuleName: "Crash", Cluster: "prod", Message: "api crashed"}))
+	require.Equal(t, "prod: Crash", captured.Attachments[0].Title)
+	require.Equal(t, "api crashed", captured.Attachments[0].Text)
+
+	_, err = channelNotifier(NotificationChannel{
+		Type:   NotificationTypeSlack,
+		Config: map[string]interface{}{"slackWebhookUrl": ts.URL, ConfigTitleTemplate: "{{"},
+	})
+	require.Error(t, err)
+}
+
+func TestTestNotifier_RejectsTemplateThatFailsToExecute(t *testing.T) {
+	svc := NewService()
+	err := svc.TestNotifier(string(NotificationTypeDiscord), map[string]interface{}{
+		"discordWebhookUrl": "https://discord.example.com/api/webhooks/1/x",
+		ConfigBodyTemplate:  "{{.Nope}}",
+	})
+	require.ErrorContains(t, err, "body template")
+}
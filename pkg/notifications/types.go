@@ -11,6 +11,9 @@ const (
 	NotificationTypeWebhook   NotificationType = "webhook"
 	NotificationTypePagerDuty NotificationType = "pagerduty"
 	NotificationTypeOpsGenie  NotificationType = "opsgenie"
+	NotificationTypeTeams     NotificationType = "teams"
+	NotificationTypeDiscord   NotificationType = "discord"
+	NotificationTypeMatrix    NotificationType = "matrix"
 )
 
 // AlertSeverity represents alert severity levels
@@ -36,6 +39,9 @@ type Alert struct {
 	Resource     string                 `json:"resource,omitempty"`
 	ResourceKind string                 `json:"resourceKind,omitempty"`
 	FiredAt      time.Time              `json:"firedAt"`
+	// NotificationID identifies the queued delivery carrying the alert. It
+	// is the same on every retry, so notifiers can make resends idempotent.
+	NotificationID string `json:"-"`
 }
 
 // NotificationChannel represents a notification channel configuration
@@ -58,6 +64,15 @@ type NotificationConfig struct {
 	WebhookURL          string `json:"webhookUrl,omitempty"`
 	PagerDutyRoutingKey string `json:"pagerdutyRoutingKey,omitempty"`
 	OpsGenieAPIKey      string `json:"opsgenieApiKey,omitempty"`
+	TeamsWebhookURL     string `json:"teamsWebhookUrl,omitempty"`
+	DiscordWebhookURL   string `json:"discordWebhookUrl,omitempty"`
+	MatrixHomeserverURL string `json:"matrixHomeserverUrl,omitempty"`
+	MatrixAccessToken   string `json:"matrixAccessToken,omitempty"`
+	MatrixRoomID        string `json:"matrixRoomId,omitempty"`
+	// Go text/template overrides for the message title and body; see
+	// MessageTemplate.
+	TitleTemplate string `json:"titleTemplate,omitempty"`
+	BodyTemplate  string `json:"bodyTemplate,omitempty"`
 }
 
 // Notifier is the interface for sending notifications
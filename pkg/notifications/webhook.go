@@ -34,6 +34,7 @@ const (
 type WebhookNotifier struct {
 	URL        string
 	HTTPClient *http.Client
+	templated
 }
 
 // webhookPayload is the JSON body sent on each alert.
@@ -53,40 +54,52 @@ type webhookPayload struct {
 // NewWebhookNotifier validates the URL and returns a ready-to-use notifier.
 // Fails fast on malformed URLs and on hosts outside the (optional) allowlist.
 func NewWebhookNotifier(webhookURL string) (*WebhookNotifier, error) {
-	if webhookURL == "" {
-		return nil, fmt.Errorf("webhook URL is required")
+	if err := validateOutboundURL(webhookURL, "webhook"); err != nil {
+		return nil, err
+	}
+	return &WebhookNotifier{
+		URL:        webhookURL,
+		HTTPClient: newOutboundHTTPClient(webhookHTTPTimeout),
+	}, nil
+}
+
+// validateOutboundURL checks a user-supplied notifier endpoint: it must be
+// an https URL (http only for loopback hosts) whose host passes the
+// optional allowlist. kind names the endpoint in error messages.
+func validateOutboundURL(rawURL, kind string) error {
+	if rawURL == "" {
+		return fmt.Errorf("%s URL is required", kind)
 	}
-	u, err := url.Parse(webhookURL)
+	u, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+		return fmt.Errorf("invalid %s URL: %w", kind, err)
 	}
 	if u.Scheme != "http" && u.Scheme != "https" {
-		return nil, fmt.Errorf("webhook URL must be http or https")
+		return fmt.Errorf("%s URL must be http or https", kind)
 	}
 	if u.Host == "" {
-		return nil, fmt.Errorf("webhook URL must have a host")
+		return fmt.Errorf("%s URL must have a host", kind)
 	}
 	// #8392: reject plaintext http by default. Allow it only when the host
 	// is a loopback address so local development and in-cluster testing
 	// against sidecar receivers still work without TLS.
 	if u.Scheme == "http" && !isLoopbackHost(u.Hostname()) {
-		return nil, fmt.Errorf("webhook URL must use https (plaintext http allowed only for loopback hosts)")
+		return fmt.Errorf("%s URL must use https (plaintext http allowed only for loopback hosts)", kind)
 	}
-	if err := checkWebhookHostAllowed(u.Hostname()); err != nil {
-		return nil, err
-	}
-	return &WebhookNotifier{
-		URL: webhookURL,
-		HTTPClient: &http.Client{
-			Timeout: webhookHTTPTimeout,
-			// #6675 Copilot followup: re-check the allowlist on every
-			// redirect hop. Without this a permitted host could 30x to
-			// an internal endpoint and the request would still be sent.
-			CheckRedirect: func(req *http.Request, _ []*http.Request) error {
-				return checkWebhookHostAllowed(req.URL.Hostname())
-			},
+	return checkWebhookHostAllowed(u.Hostname())
+}
+
+// newOutboundHTTPClient returns a client for user-supplied endpoints.
+func newOutboundHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		// #6675 Copilot followup: re-check the allowlist on every
+		// redirect hop. Without this a permitted host could 30x to
+		// an internal endpoint and the request would still be sent.
+		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+			return checkWebhookHostAllowed(req.URL.Hostname())
 		},
-	}, nil
+	}
 }
 
 // isLoopbackHost returns true if host is a recognized loopback hostname or
@@ -144,14 +157,15 @@ func (w *WebhookNotifier) Send(alert Alert) error {
 	if w == nil {
 		return fmt.Errorf("nil webhook notifier")
 	}
+	title, message := w.render(alert, alert.RuleName, alert.Message)
 	payload := webhookPayload{
-		Alert:     alert.RuleName,
+		Alert:     title,
 		Severity:  string(alert.Severity),
 		Status:    alert.Status,
 		Cluster:   alert.Cluster,
 		Namespace: alert.Namespace,
 		Resource:  alert.Resource,
-		Message:   alert.Message,
+		Message:   message,
 		Timestamp: alert.FiredAt,
 		RuleID:    alert.RuleID,
 		ID:        alert.ID,
@@ -201,3 +215,47 @@ func (w *WebhookNotifier) Test() error {
 		FiredAt:  time.Now(),
 	})
 }
+
+// sendJSON sends payload as a JSON request and fails on a non-2xx
+// response. name prefixes error messages.
+func sendJSON(client *http.Client, method, endpoint string, headers map[string]string, payload interface{}, name string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", name, err)
+	}
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "KubeStellar-Console-Webhook/1.0")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", name, err)
+	}
+	defer func() {
+		// Drain body so the underlying TCP connection can be reused.
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s endpoint returned status %d", name, resp.StatusCode)
+	}
+	return nil
+}
+
+// testAlert is the synthetic alert notifiers send from Test.
+func testAlert() Alert {
+	return Alert{
+		ID:       "test-alert",
+		RuleID:   "test-rule",
+		RuleName: "KubeStellar Console Test Alert",
+		Severity: SeverityInfo,
+		Status:   "test",
+		Message:  "This is a test notification from KubeStellar Console",
+		FiredAt:  time.Now(),
+	}
+}
@@ -19,6 +19,10 @@ const (
 	defaultObserverInterval = 60 * time.Second
 	observerRecentLimit     = 5
 	observerMaxRecentFlags  = 3
+	observerRecallLimit     = 3
+	// observerRecallQueryMax caps the recall query so a noisy cluster
+	// doesn't send a huge blob to the embedding endpoint.
+	observerRecallQueryMax = 2000
 )
 
 type ObserverStore interface {
@@ -28,6 +32,7 @@ type ObserverStore interface {
 	GetRecentObservations(ctx context.Context, cluster string, limit int) ([]store.StellarObservation, error)
 	CreateObservation(ctx context.Context, obs *store.StellarObservation) (string, error)
 	GetRecentMemoryEntries(ctx context.Context, userID, cluster string, limit int) ([]store.StellarMemoryEntry, error)
+	SearchStellarMemoryEntries(ctx context.Context, userID, query string, limit int) ([]store.StellarMemoryEntry, error)
 	GetActiveWatchesForCluster(ctx context.Context, cluster string) ([]store.StellarWatch, error)
 	GetActiveWatches(ctx context.Context, userID string) ([]store.StellarWatch, error)
 	UpdateWatchStatus(ctx context.Context, id, status, lastUpdate string) error
@@ -371,14 +376,15 @@ func severityRank(s string) int {
 // parseResourceFromEvents extracts the resource kind and name from a notification's title or body.
 // Falls back to a default Pod kind when the title carries only a name.
 func parseResourceFromEvents(events []store.StellarNotification, title string) (string, string) {
-	// Try to derive kind/name from dedupeKey first (format: "ev:cluster:ns:name:reason")
+	// Try to derive kind/name from dedupeKey first (format: "ev:cluster:ns:name:reason",
+	// or "am:cluster:ns:pod:..." for Alertmanager alerts)
 	for _, n := range events {
 		if n.DedupeKey == "" {
 			continue
 		}
 		parts := strings.Split(n.DedupeKey, ":")
 		offset := 0
-		if parts[0] == "ev" {
+		if parts[0] == "ev" || parts[0] == "am" {
 			offset = 1
 		}
 		if len(parts) >= offset+3 {
@@ -414,6 +420,10 @@ func (o *Observer) observeUser(ctx context.Context, userID string) {
 
 	// Inject live cluster events
 	var liveEvents strings.Builder
+	var recallQuery strings.Builder
+	for _, ev := range events {
+		recallQuery.WriteString(ev.Title + "\n")
+	}
 	if o.client != nil {
 		clusters, clErr := o.client.ListClusters(ctx)
 		if clErr == nil && len(clusters) > 0 {
@@ -428,6 +438,7 @@ func (o *Observer) observeUser(ctx context.Context, userID string) {
 					liveEvents.WriteString(fmt.Sprintf("  %s:\n", clusterName))
 					for _, ev := range warningEvents {
 						liveEvents.WriteString(fmt.Sprintf("    - %s: %s\n", ev.Reason, ev.Message))
+						recallQuery.WriteString(fmt.Sprintf("%s %s %s\n", ev.Reason, ev.Object, clusterName))
 					}
 				}
 			}
@@ -465,6 +476,16 @@ func (o *Observer) observeUser(ctx context.Context, userID string) {
 		}
 	}
 
+	// Recall past incidents that resemble what is happening now, so the
+	// model can say "this looks like last Tuesday" instead of starting cold.
+	if related := o.relatedIncidents(ctx, userID, recallQuery.String()); len(related) > 0 {
+		memoryContext.WriteString("\nSimilar past incidents:\n")
+		for _, m := range related {
+			memoryContext.WriteString(fmt.Sprintf("  [%s] %s: %s\n",
+				m.CreatedAt.Format("Jan 02 15:04"), m.Cluster, truncate(m.Summary, 150)))
+		}
+	}
+
 	contextPayload := buildObserverContext(tasks, events, observations) + liveEvents.String() + memoryContext.String()
 	
 	// Prefer the user's saved provider (set via the Stellar provider UI)
@@ -799,3 +820,22 @@ func extractReasoning(response, surface string) string {
 	reasoning = strings.TrimSpace(reasoning)
 	return reasoning
 }
+
+// relatedIncidents returns memories semantically related to the current
+// situation. The store ranks by embedding similarity when an embedder is
+// configured and falls back to keyword matching otherwise.
+func (o *Observer) relatedIncidents(ctx context.Context, userID, query string) []store.StellarMemoryEntry {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	if len(query) > observerRecallQueryMax {
+		query = query[:observerRecallQueryMax]
+	}
+	related, err := o.store.SearchStellarMemoryEntries(ctx, userID, query, observerRecallLimit)
+	if err != nil {
+		slog.Debug("stellar/observer: memory recall failed", "user", userID, "error", err)
+		return nil
+	}
+	return related
+}
@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/a2a"
+)
+
+// a2aProviderPrefix namespaces A2A agents among the registry's providers.
+const a2aProviderPrefix = "a2a-"
+
+// A2AProvider runs Stellar prompts on an Agent2Agent agent listed in
+// A2A_AGENT_URLS. The agent picks its own model, so GenerateRequest.Model is
+// ignored. Its card is only fetched by Health, because registries are built
+// on request paths.
+type A2AProvider struct {
+	name   string
+	client *a2a.Client
+}
+
+// NewA2AProvider creates a provider for one agent endpoint.
+func NewA2AProvider(ep a2a.Endpoint, opts ...a2a.Option) *A2AProvider {
+	hc := a2a.WithHTTPClient(&http.Client{Timeout: providerHTTPTimeout})
+	return &A2AProvider{
+		name:   a2aProviderPrefix + ep.DefaultName(nil),
+		client: a2a.NewClient(ep.URL, append([]a2a.Option{hc}, opts...)...),
+	}
+}
+
+func (a *A2AProvider) Name() string { return a.name }
+
+// SupportsStreaming is true: replies are streamed when the agent supports
+// it and delivered in one piece otherwise.
+func (a *A2AProvider) SupportsStreaming() bool { return true }
+
+func (a *A2AProvider) Health(ctx context.Context) HealthResult {
+	start := time.Now()
+	_, err := a.client.Discover(ctx)
+	latency := int(time.Since(start).Milliseconds())
+	if err != nil {
+		return HealthResult{Available: false, Error: err.Error(), LatencyMs: latency}
+	}
+	return HealthResult{Available: true, LatencyMs: latency}
+}
+
+func (a *A2AProvider) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	start := time.Now()
+	streaming := req.Stream && req.StreamCh != nil
+	if streaming {
+		defer close(req.StreamCh)
+	}
+
+	opts := a2a.RunOptions{}
+	if streaming {
+		if card, err := a.client.Discover(ctx); err == nil {
+			opts.Stream = card.Capabilities.Streaming
+		}
+		opts.OnText = func(text string) {
+			select {
+			case req.StreamCh <- text:
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	reply, err := a.client.Run(ctx, a2a.MessageSendParams{
+		Message: a2a.NewTextMessage(flattenMessages(req.Messages), "", ""),
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a.name, err)
+	}
+	return &GenerateResponse{
+		Content:    reply.Text,
+		Model:      a.name,
+		Provider:   a.name,
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}, nil
+}
+
+// flattenMessages turns a chat transcript into one A2A message: an agent
+// keeps its own history per context, and Stellar calls are one-shot.
+func flattenMessages(messages []Message) string {
+	if len(messages) == 1 {
+		return messages[0].Content
+	}
+	var sb strings.Builder
+	for i, m := range messages {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		switch m.Role {
+		case "system":
+			sb.WriteString("Instructions:\n")
+		case "assistant":
+			sb.WriteString("Assistant:\n")
+		default:
+			sb.WriteString("User:\n")
+		}
+		sb.WriteString(m.Content)
+	}
+	return sb.String()
+}
@@ -0,0 +1,160 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	openAIEmbeddingsPath = "/embeddings"
+
+	defaultOllamaEmbeddingModel = "nomic-embed-text"
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+
+	// embedderProbeTimeout bounds the startup check that a local Ollama is
+	// running before it is picked for embeddings.
+	embedderProbeTimeout = 3 * time.Second
+)
+
+// Embedder turns text into dense vectors for semantic memory recall. Only
+// providers with an embeddings endpoint implement it — Anthropic does not.
+type Embedder interface {
+	Embed(ctx context.Context, model string, inputs []string) ([][]float32, error)
+	Name() string
+}
+
+// Embed calls Ollama's /api/embed endpoint, which accepts a batch of inputs.
+func (o *OllamaProvider) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	if model == "" {
+		model = defaultOllamaEmbeddingModel
+	}
+	body, err := json.Marshal(map[string]any{"model": model, "input": inputs})
+	if err != nil {
+		return nil, fmt.Errorf("ollama embed marshal: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama embed request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("ollama embed: unexpected status %d", resp.StatusCode)
+	}
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("ollama embed decode: %w", err)
+	}
+	if len(result.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("ollama embed: got %d vectors for %d inputs", len(result.Embeddings), len(inputs))
+	}
+	return result.Embeddings, nil
+}
+
+// Embed calls the OpenAI-compatible /embeddings endpoint.
+func (o *OpenAICompatProvider) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	if model == "" {
+		model = defaultOpenAIEmbeddingModel
+	}
+	body, err := json.Marshal(map[string]any{"model": model, "input": inputs})
+	if err != nil {
+		return nil, fmt.Errorf("%s embed marshal: %w", o.name, err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+openAIEmbeddingsPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s embed request: %w", o.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%s embed: unexpected status %d", o.name, resp.StatusCode)
+	}
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s embed decode: %w", o.name, err)
+	}
+	if len(result.Data) != len(inputs) {
+		return nil, fmt.Errorf("%s embed: got %d vectors for %d inputs", o.name, len(result.Data), len(inputs))
+	}
+	out := make([][]float32, len(inputs))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(out) {
+			return nil, fmt.Errorf("%s embed: index %d out of range", o.name, d.Index)
+		}
+		out[d.Index] = d.Embedding
+	}
+	return out, nil
+}
+
+// ResolveEmbedder picks the provider used for memory embeddings.
+// STELLAR_EMBEDDING_PROVIDER / STELLAR_EMBEDDING_MODEL override the choice;
+// otherwise OpenAI is preferred when a key is configured, then Ollama if a
+// server answers. Ollama is always registered, so without the probe semantic
+// memory would switch on with no server behind it and every embed would fail.
+// The embedding provider is deliberately independent of the chat default:
+// vectors from different models are not comparable, so it must stay fixed
+// even when users switch chat providers.
+//
+// STELLAR_EMBEDDING_MODEL names a model of STELLAR_EMBEDDING_PROVIDER, or of
+// the preferred provider when none is set. If resolution falls back to
+// another provider the override is dropped and that provider's default
+// embedding model is used, rather than sending it a model it does not have.
+func (r *Registry) ResolveEmbedder(ctx context.Context) (Embedder, string) {
+	model := strings.TrimSpace(os.Getenv("STELLAR_EMBEDDING_MODEL"))
+	candidates := []string{"openai", "ollama"}
+	explicit := false
+	if name := strings.TrimSpace(os.Getenv("STELLAR_EMBEDDING_PROVIDER")); name != "" {
+		candidates = []string{name}
+		explicit = true
+	}
+	for _, name := range candidates {
+		p, ok := r.GetGlobal(name)
+		if !ok {
+			continue
+		}
+		e, ok := p.(Embedder)
+		if !ok {
+			continue
+		}
+		if ollama, isOllama := p.(*OllamaProvider); isOllama && !explicit {
+			probeCtx, cancel := context.WithTimeout(ctx, embedderProbeTimeout)
+			health := ollama.Health(probeCtx)
+			cancel()
+			if !health.Available {
+				continue
+			}
+		}
+		if model != "" && name != candidates[0] {
+			slog.Warn("stellar: STELLAR_EMBEDDING_MODEL is for another provider, using the default model",
+				"model", model, "configuredFor", candidates[0], "provider", name)
+			model = ""
+		}
+		return e, model
+	}
+	return nil, ""
+}
@@ -0,0 +1,43 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveEmbedderModelOverride(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"models":[]}`))
+	}))
+	defer ollama.Close()
+
+	tests := []struct {
+		name      string
+		openAIKey string
+		provider  string
+		wantName  string
+		wantModel string
+	}{
+		{"preferred provider keeps the override", "sk-test", "", "openai", "text-embedding-3-large"},
+		{"fallback drops the override", "", "", "ollama", ""},
+		{"explicit provider keeps the override", "", "ollama", "ollama", "text-embedding-3-large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OLLAMA_BASE_URL", ollama.URL)
+			t.Setenv("OPENAI_API_KEY", tt.openAIKey)
+			t.Setenv("STELLAR_EMBEDDING_PROVIDER", tt.provider)
+			t.Setenv("STELLAR_EMBEDDING_MODEL", "text-embedding-3-large")
+
+			e, model := NewRegistry().ResolveEmbedder(context.Background())
+			if e == nil {
+				t.Fatal("no embedder resolved")
+			}
+			if e.Name() != tt.wantName || model != tt.wantModel {
+				t.Errorf("ResolveEmbedder = %s/%q, want %s/%q", e.Name(), model, tt.wantName, tt.wantModel)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+
+	"github.com/kubestellar/console/pkg/a2a"
 )
 
 type ResolvedProvider struct {
@@ -47,6 +49,14 @@ func NewRegistry() *Registry {
 	if k := os.Getenv("TOGETHER_API_KEY"); k != "" {
 		r.global["together"] = NewOpenAICompat("https://api.together.xyz/v1", k, "together")
 	}
+	// A2A agents are only used when requested by name or set as
+	// STELLAR_DEFAULT_PROVIDER; they are never picked as a fallback.
+	for _, ep := range a2a.EndpointsFromEnv() {
+		p := NewA2AProvider(ep, a2a.ClientOptionsFromEnv()...)
+		if _, dup := r.global[p.Name()]; !dup {
+			r.global[p.Name()] = p
+		}
+	}
 
 	// Default-provider selection — preference order:
 	//   1. STELLAR_DEFAULT_PROVIDER env override (explicit operator intent)
@@ -90,7 +100,24 @@ func resolveModel(defaultModel, providerName, requestedModel string) string {
 	return defaultModel
 }
 
+// Resolve picks the provider for system work such as the observer and
+// scheduler loops. The returned provider routes through the shared router,
+// so it falls back to other providers and counts against the category
+// budget. Use ResolveForUser when a user is behind the request.
 func (r *Registry) Resolve(requestProvider, requestModel string, userCfg *ResolvedUserProvider) ResolvedProvider {
+	return r.ResolveForUser("", requestProvider, requestModel, userCfg)
+}
+
+// ResolveForUser is Resolve with the user's daily token budget applied.
+func (r *Registry) ResolveForUser(userID, requestProvider, requestModel string, userCfg *ResolvedUserProvider) ResolvedProvider {
+	resolved := r.resolve(requestProvider, requestModel, userCfg)
+	if resolved.Provider != nil {
+		resolved.Provider = &routedProvider{Provider: resolved.Provider, registry: r, userID: userID}
+	}
+	return resolved
+}
+
+func (r *Registry) resolve(requestProvider, requestModel string, userCfg *ResolvedUserProvider) ResolvedProvider {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -110,7 +137,7 @@ func (r *Registry) Resolve(requestProvider, requestModel string, userCfg *Resolv
 	// We used to hard-pin to Ollama here, which made every background loop fail
 	// with connection-refused when Ollama wasn't running — even though the
 	// operator had a cloud key set.
-	for _, name := range []string{"anthropic", "openai", "groq", "openrouter", "together", "ollama"} {
+	for _, name := range fallbackOrder {
 		if p, ok := r.global[name]; ok {
 			return ResolvedProvider{Provider: p, Model: resolveModel(r.defaultModel, name, ""), Source: "fallback"}
 		}
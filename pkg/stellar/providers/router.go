@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/airouter"
+)
+
+// fallbackOrder is the chain Stellar falls back through when no
+// AI_ROUTER_CHAINS entry covers it: cloud providers first, local Ollama
+// last. A2A agents are never used as a fallback.
+var fallbackOrder = []string{"anthropic", "openai", "groq", "openrouter", "together", "ollama"}
+
+var (
+	routerMu     sync.Mutex
+	sharedRouter *airouter.Router
+)
+
+// SetRouter installs the router shared by every Registry. The console calls
+// it at startup with a usage store backed by the database; without it a
+// router configured from the environment with in-memory usage is used.
+func SetRouter(r *airouter.Router) {
+	routerMu.Lock()
+	defer routerMu.Unlock()
+	sharedRouter = r
+}
+
+// Router returns the router shared by every Registry.
+func Router() *airouter.Router {
+	routerMu.Lock()
+	defer routerMu.Unlock()
+	if sharedRouter == nil {
+		cfg, err := airouter.ConfigFromEnv()
+		if err != nil {
+			slog.Error("[AIRouter] invalid routing configuration, budgets and chains disabled", "error", err)
+			cfg = airouter.Config{}
+		}
+		sharedRouter = airouter.New(cfg, nil)
+	}
+	return sharedRouter
+}
+
+// routedProvider wraps the provider Resolve picked. Generate goes through
+// the shared router: the budgets are checked first, then the picked
+// provider is tried, then the Stellar chain, skipping providers whose
+// breaker is open.
+type routedProvider struct {
+	Provider
+	registry *Registry
+	userID   string
+}
+
+func (p *routedProvider) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	// A stream can only be consumed once, so streaming requests stay on
+	// the picked provider.
+	streaming := req.Stream && req.StreamCh != nil
+	called := false
+	var resp *GenerateResponse
+	_, err := Router().Route(ctx, airouter.Request{
+		UserID:    p.userID,
+		Category:  airouter.CategoryStellar,
+		Preferred: p.Provider.Name(),
+		Fallback:  fallbackOrder,
+		Pinned:    streaming,
+	}, p.available, func(ctx context.Context, name string) (int64, error) {
+		target, attempt := p.Provider, req
+		if name != p.Provider.Name() {
+			// Another provider needs its own model, not the one picked for
+			// the original provider.
+			p.registry.mu.RLock()
+			target = p.registry.global[name]
+			attempt.Model = resolveModel(p.registry.defaultModel, name, "")
+			p.registry.mu.RUnlock()
+		}
+		called = true
+		out, err := target.Generate(ctx, attempt)
+		if err != nil {
+			return 0, err
+		}
+		resp = out
+		return int64(out.TokensInput + out.TokensOutput), nil
+	})
+	if err != nil {
+		// Providers close the stream when they finish; if none ran, the
+		// reader would otherwise wait forever.
+		if streaming && !called {
+			close(req.StreamCh)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// available reports whether name can serve a fallback attempt. The picked
+// provider may be a per-user one that is not in the global map.
+func (p *routedProvider) available(name string) bool {
+	if name == p.Provider.Name() {
+		return true
+	}
+	_, ok := p.registry.GetGlobal(name)
+	return ok
+}
@@ -0,0 +1,272 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes successive fire times for a mission or recurring action.
+type Schedule interface {
+	// Next returns the first fire time strictly after t.
+	Next(t time.Time) time.Time
+}
+
+// cronSearchLimit bounds how far into the future Next will search before
+// giving up on an expression that can never match (Feb 30th and friends).
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// minEveryInterval keeps "@every" schedules from hammering the scheduler
+// loop faster than it can tick.
+const minEveryInterval = time.Minute
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinuteField = cronField{name: "minute", min: 0, max: 59}
+	cronHourField   = cronField{name: "hour", min: 0, max: 23}
+	cronDomField    = cronField{name: "day-of-month", min: 1, max: 31}
+	cronMonthField  = cronField{name: "month", min: 1, max: 12, names: cronMonthNames}
+	// Day-of-week accepts 7 as an alias for Sunday, as Vixie cron does.
+	cronDowField = cronField{name: "day-of-week", min: 0, max: 7, names: cronDayNames}
+)
+
+// ParseSchedule parses a standard 5-field cron expression (minute hour
+// day-of-month month day-of-week) or one of the @yearly/@monthly/@weekly/
+// @daily/@midnight/@hourly/@every <duration> descriptors. Field matching is
+// evaluated in loc, so "0 9 * * *" means 09:00 in the user's timezone.
+func ParseSchedule(spec string, loc *time.Location) (Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty schedule")
+	}
+	if strings.HasPrefix(spec, "@every") {
+		raw := strings.TrimSpace(strings.TrimPrefix(spec, "@every"))
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every duration %q: %w", raw, err)
+		}
+		if d < minEveryInterval {
+			return nil, fmt.Errorf("@every interval must be at least %s", minEveryInterval)
+		}
+		return everySchedule{interval: d}, nil
+	}
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule descriptor %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+	cs := cronSchedule{loc: loc}
+	var err error
+	if cs.minute, err = parseCronField(fields[0], cronMinuteField); err != nil {
+		return nil, err
+	}
+	if cs.hour, err = parseCronField(fields[1], cronHourField); err != nil {
+		return nil, err
+	}
+	if cs.dom, err = parseCronField(fields[2], cronDomField); err != nil {
+		return nil, err
+	}
+	if cs.month, err = parseCronField(fields[3], cronMonthField); err != nil {
+		return nil, err
+	}
+	if cs.dow, err = parseCronField(fields[4], cronDowField); err != nil {
+		return nil, err
+	}
+	if cs.dow&(1<<7) != 0 {
+		cs.dow |= 1 << 0
+	}
+	cs.domRestricted = !isWildcard(fields[2])
+	cs.dowRestricted = !isWildcard(fields[4])
+	if cs.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", spec)
+	}
+	return cs, nil
+}
+
+// ValidateSchedule reports whether spec parses. Callers use it to reject bad
+// input at the API boundary before anything is persisted.
+func ValidateSchedule(spec string) error {
+	_, err := ParseSchedule(spec, time.UTC)
+	return err
+}
+
+// LoadTimezone resolves an IANA zone name from StellarPreferences.Timezone,
+// falling back to UTC for empty or unknown names so a typo in preferences
+// never stops a mission from running.
+func LoadTimezone(name string) *time.Location {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextRun parses spec in the given timezone and returns the first fire time
+// after the supplied instant, in UTC.
+func NextRun(spec, timezone string, after time.Time) (time.Time, error) {
+	sched, err := ParseSchedule(spec, LoadTimezone(timezone))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(after).UTC(), nil
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	// Align to whole seconds so persisted NextRunAt values stay stable
+	// across the SQLite DATETIME round-trip.
+	return t.Add(e.interval).Truncate(time.Second)
+}
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+	loc                           *time.Location
+}
+
+func (c cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the classic cron rule: when both day-of-month and
+// day-of-week are restricted, a day matches if EITHER field matches.
+func (c cronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+func parseCronField(expr string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty %s list element", f.name)
+		}
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, part[i+1:])
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// "5/15" means "from 5 to the end, every 15"; a bare "5" is just 5.
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	if f.names != nil {
+		if v, ok := f.names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", f.name, s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s value %d out of range [%d-%d]", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,409 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/safego"
+	"github.com/kubestellar/console/pkg/stellar/providers"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// MissedRunPolicy controls what the cron engine does with a fire time that
+// passed while the console was down (or the loop was stalled).
+type MissedRunPolicy string
+
+const (
+	// MissedRunCatchUp runs the mission once on restart, coalescing every
+	// missed fire into a single execution.
+	MissedRunCatchUp MissedRunPolicy = "catch-up"
+	// MissedRunSkip drops the missed fires and waits for the next one. A
+	// "skipped" execution is still recorded so the gap is visible.
+	MissedRunSkip MissedRunPolicy = "skip"
+)
+
+const (
+	missedRunPolicyEnv = "STELLAR_MISSED_RUN_POLICY"
+	cronTickInterval   = 30 * time.Second
+	cronBatchLimit     = 25
+	// missedRunGrace is how late a fire may be before it counts as missed.
+	// It must comfortably exceed cronTickInterval so normal tick jitter is
+	// never mistaken for downtime.
+	missedRunGrace = 2 * time.Minute
+	// maxMissedCount caps the missed-run counter so an @every 1m mission
+	// that was down for a month doesn't spin computing fire times.
+	maxMissedCount = 1000
+	// invalidScheduleBackoff delays re-evaluating a mission whose stored
+	// schedule no longer parses so it doesn't re-notify on every tick.
+	invalidScheduleBackoff = 24 * time.Hour
+	missionRunTimeout      = 3 * time.Minute
+	missionMaxTokens       = 800
+	// cronRunLease is how long a recurring action may stay claimed as
+	// running before the engine assumes its process died and re-arms it.
+	// It must exceed executeAction's own timeout.
+	cronRunLease = 15 * time.Minute
+)
+
+// firePlan is the outcome of comparing a schedule cursor against "now".
+type firePlan struct {
+	fire   bool
+	missed int
+	next   time.Time
+}
+
+// planFire decides whether a due fire at scheduledFor should run. Fires within
+// missedRunGrace always run; later ones follow the missed-run policy. next is
+// always the first fire strictly after now, so the cursor never points into
+// the past after a tick.
+func planFire(sched Schedule, scheduledFor, now time.Time, policy MissedRunPolicy) firePlan {
+	plan := firePlan{fire: true, next: sched.Next(now)}
+	if now.Sub(scheduledFor) <= missedRunGrace {
+		return plan
+	}
+	// Late: the fire at scheduledFor plus every later fire up to now missed.
+	plan.missed = 1
+	for t := sched.Next(scheduledFor); !t.IsZero() && !t.After(now) && plan.missed < maxMissedCount; t = sched.Next(t) {
+		plan.missed++
+	}
+	plan.fire = policy != MissedRunSkip
+	return plan
+}
+
+// acquireSlot waits for a free worker slot and reports false if ctx is
+// cancelled first.
+func acquireSlot(ctx context.Context, sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func parseMissedRunPolicy(v string) MissedRunPolicy {
+	switch MissedRunPolicy(strings.ToLower(strings.TrimSpace(v))) {
+	case MissedRunSkip:
+		return MissedRunSkip
+	default:
+		return MissedRunCatchUp
+	}
+}
+
+// SetProviderRegistry shares the server's provider registry so scheduled
+// missions resolve the same providers as interactive asks.
+func (s *Scheduler) SetProviderRegistry(reg *providers.Registry) {
+	if reg != nil {
+		s.registry = reg
+	}
+}
+
+// StartCron runs the cron engine: it fires scheduled missions
+// (StellarMission.Schedule) and recurring actions (StellarAction.CronExpr) in
+// each owner's StellarPreferences.Timezone. It ticks once immediately so runs
+// missed during downtime are handled as soon as the server is back.
+func (s *Scheduler) StartCron(ctx context.Context) {
+	policy := parseMissedRunPolicy(os.Getenv(missedRunPolicyEnv))
+	ticker := time.NewTicker(cronTickInterval)
+	defer ticker.Stop()
+	slog.Info("stellar/scheduler: cron engine started", "missedRunPolicy", policy)
+	sem := make(chan struct{}, s.concurrency)
+	s.cronTick(ctx, time.Now().UTC(), policy, sem)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("stellar/scheduler: cron engine stopped")
+			return
+		case <-ticker.C:
+			s.cronTick(ctx, time.Now().UTC(), policy, sem)
+		}
+	}
+}
+
+func (s *Scheduler) cronTick(ctx context.Context, now time.Time, policy MissedRunPolicy, sem chan struct{}) {
+	if n, err := s.store.RearmStaleCronStellarActions(ctx, now.Add(-cronRunLease)); err != nil {
+		slog.Warn("stellar/scheduler: re-arm stale cron actions failed", "error", err)
+	} else if n > 0 {
+		slog.Warn("stellar/scheduler: re-armed cron actions left running by a stopped process", "count", n)
+	}
+
+	missions, err := s.store.ListDueStellarMissions(ctx, now, cronBatchLimit)
+	if err != nil {
+		slog.Warn("stellar/scheduler: fetch due missions failed", "error", err)
+	}
+	for _, m := range missions {
+		// Take a slot before advancing, so a shutdown while waiting
+		// leaves the run due instead of consuming it.
+		if !acquireSlot(ctx, sem) {
+			return
+		}
+		run := s.advanceMission(ctx, m, now, policy)
+		if run == nil {
+			<-sem
+			continue
+		}
+		safego.GoWith("stellar-cron-mission", func() {
+			defer func() { <-sem }()
+			run()
+		})
+	}
+
+	actions, err := s.store.GetDueCronStellarActions(ctx, now, cronBatchLimit)
+	if err != nil {
+		slog.Warn("stellar/scheduler: fetch due cron actions failed", "error", err)
+	}
+	for _, a := range actions {
+		// Take a slot before advancing, so a shutdown while waiting
+		// leaves the run due instead of consuming it.
+		if !acquireSlot(ctx, sem) {
+			return
+		}
+		run := s.advanceCronAction(ctx, a, now, policy)
+		if run == nil {
+			<-sem
+			continue
+		}
+		safego.GoWith("stellar-cron-action", func() {
+			defer func() { <-sem }()
+			run()
+		})
+	}
+}
+
+// userLocation returns the owner's configured timezone, or UTC.
+func (s *Scheduler) userLocation(ctx context.Context, userID string) *time.Location {
+	prefs, err := s.store.GetStellarPreferences(ctx, userID)
+	if err != nil || prefs == nil {
+		return time.UTC
+	}
+	return LoadTimezone(prefs.Timezone)
+}
+
+// advanceMission moves the mission's schedule cursor forward synchronously —
+// before any work starts — so the next tick can never pick up the same fire
+// twice. It returns the run to perform, or nil when nothing should execute.
+func (s *Scheduler) advanceMission(ctx context.Context, m store.StellarMission, now time.Time, policy MissedRunPolicy) func() {
+	sched, err := ParseSchedule(m.Schedule, s.userLocation(ctx, m.UserID))
+	if err != nil {
+		retry := now.Add(invalidScheduleBackoff)
+		_ = s.store.SetStellarMissionRunTimes(ctx, m.ID, nil, &retry)
+		_ = s.store.CreateStellarNotification(ctx, &store.StellarNotification{
+			UserID:    m.UserID,
+			Type:      "MissionUpdate",
+			Severity:  "warning",
+			Title:     "Mission schedule is invalid: " + m.Name,
+			Body:      fmt.Sprintf("I couldn't parse the schedule %q (%v). Edit the mission to fix it.", m.Schedule, err),
+			MissionID: m.ID,
+			DedupeKey: "mission-bad-schedule:" + m.ID,
+		})
+		return nil
+	}
+
+	if m.NextRunAt == nil {
+		next := sched.Next(now)
+		_ = s.store.SetStellarMissionRunTimes(ctx, m.ID, nil, nilIfZero(next))
+		return nil
+	}
+
+	scheduledFor := m.NextRunAt.UTC()
+	plan := planFire(sched, scheduledFor, now, policy)
+	var lastRun *time.Time
+	if plan.fire {
+		lastRun = &now
+	}
+	if err := s.store.SetStellarMissionRunTimes(ctx, m.ID, lastRun, nilIfZero(plan.next)); err != nil {
+		slog.Warn("stellar/scheduler: advance mission failed", "mission_id", m.ID, "error", err)
+		return nil
+	}
+
+	triggerData, _ := json.Marshal(map[string]any{
+		"scheduledFor": scheduledFor,
+		"missedRuns":   plan.missed,
+		"policy":       policy,
+	})
+	if !plan.fire {
+		completed := now
+		_ = s.store.CreateStellarExecution(ctx, &store.StellarExecution{
+			MissionID:   m.ID,
+			UserID:      m.UserID,
+			TriggerType: "cron",
+			TriggerData: string(triggerData),
+			Status:      "skipped",
+			RawInput:    m.Goal,
+			Output:      fmt.Sprintf("Skipped %d missed run(s) while the console was unavailable.", plan.missed),
+			StartedAt:   now,
+			CompletedAt: &completed,
+		})
+		slog.Info("stellar/scheduler: skipped missed mission runs", "mission_id", m.ID, "missed", plan.missed)
+		return nil
+	}
+	return func() { s.runMission(ctx, m, string(triggerData)) }
+}
+
+// runMission executes one scheduled fire of a mission against the resolved
+// provider and records the result as a StellarExecution plus a feed item.
+func (s *Scheduler) runMission(ctx context.Context, m store.StellarMission, triggerData string) {
+	started := time.Now().UTC()
+	input := s.missionInput(ctx, m)
+	exec := &store.StellarExecution{
+		MissionID:     m.ID,
+		UserID:        m.UserID,
+		TriggerType:   "cron",
+		TriggerData:   triggerData,
+		RawInput:      m.Goal,
+		EnrichedInput: input,
+		StartedAt:     started,
+	}
+
+	resolved := providers.ResolvedProvider{}
+	if s.registry != nil {
+		resolved = s.registry.Resolve(providerFromPolicy(m.ProviderPolicy), "", nil)
+	}
+	if resolved.Provider == nil {
+		exec.Status = "failed"
+		exec.Output = "No AI provider is configured for scheduled missions."
+	} else {
+		runCtx, cancel := context.WithTimeout(ctx, missionRunTimeout)
+		resp, err := resolved.Provider.Generate(runCtx, providers.GenerateRequest{
+			Model: resolved.Model, MaxTokens: missionMaxTokens, Temperature: 0.2,
+			Messages: []providers.Message{
+				{Role: "system", Content: missionPrompt},
+				{Role: "user", Content: input},
+			},
+		})
+		cancel()
+		if err != nil {
+			exec.Status = "failed"
+			exec.Output = sanitizeError(err)
+		} else {
+			exec.Status = "completed"
+			exec.Output = resp.Content
+			exec.TokensInput = resp.TokensInput
+			exec.TokensOutput = resp.TokensOutput
+			exec.Provider = resp.Provider
+			exec.Model = resp.Model
+		}
+	}
+	completed := time.Now().UTC()
+	exec.CompletedAt = &completed
+	exec.DurationMs = int(completed.Sub(started).Milliseconds())
+	if err := s.store.CreateStellarExecution(ctx, exec); err != nil {
+		slog.Warn("stellar/scheduler: record mission execution failed", "mission_id", m.ID, "error", err)
+	}
+
+	severity, title := "info", "Scheduled mission ran: "+m.Name
+	if exec.Status != "completed" {
+		severity, title = "warning", "Scheduled mission failed: "+m.Name
+	}
+	notif := &store.StellarNotification{
+		UserID:    m.UserID,
+		Type:      "MissionUpdate",
+		Severity:  severity,
+		Title:     title,
+		Body:      truncate(exec.Output, 1000),
+		MissionID: m.ID,
+		DedupeKey: "mission-run:" + exec.ID,
+	}
+	_ = s.store.CreateStellarNotification(ctx, notif)
+	if s.broadcaster != nil {
+		s.broadcaster.Broadcast(BroadcastEvent{Type: "notification", Data: notif})
+	}
+	slog.Info("stellar/scheduler: mission fired", "mission_id", m.ID, "status", exec.Status, "duration_ms", exec.DurationMs)
+}
+
+// missionInput is the user message for a scheduled run: the goal plus the
+// cluster and namespace the mission targets. A mission without a cluster
+// runs against the owner's pinned clusters.
+func (s *Scheduler) missionInput(ctx context.Context, m store.StellarMission) string {
+	var scope []string
+	if m.Cluster != "" {
+		scope = append(scope, "Cluster: "+m.Cluster)
+	} else if prefs, err := s.store.GetStellarPreferences(ctx, m.UserID); err == nil && prefs != nil && len(prefs.PinnedClusters) > 0 {
+		scope = append(scope, "Clusters: "+strings.Join(prefs.PinnedClusters, ", "))
+	}
+	if m.Namespace != "" {
+		scope = append(scope, "Namespace: "+m.Namespace)
+	}
+	if len(scope) == 0 {
+		return m.Goal
+	}
+	return m.Goal + "\n\n" + strings.Join(scope, "\n")
+}
+
+// advanceCronAction re-arms a recurring action and returns the dispatch to
+// run, or nil. Unlike one-shot actions, a failed recurring action is not
+// retried in place — the next occurrence is its retry.
+func (s *Scheduler) advanceCronAction(ctx context.Context, a store.StellarAction, now time.Time, policy MissedRunPolicy) func() {
+	sched, err := ParseSchedule(a.CronExpr, s.userLocation(ctx, a.UserID))
+	if err != nil {
+		_ = s.store.UpdateStellarActionStatus(ctx, a.ID, "failed", "", "invalid cron expression: "+err.Error())
+		return nil
+	}
+	if a.ScheduledAt == nil {
+		if next := sched.Next(now); !next.IsZero() {
+			_ = s.store.RescheduleStellarAction(ctx, a.ID, next)
+		}
+		return nil
+	}
+
+	plan := planFire(sched, a.ScheduledAt.UTC(), now, policy)
+	if !plan.fire {
+		if !plan.next.IsZero() {
+			_ = s.store.RescheduleStellarAction(ctx, a.ID, plan.next)
+		}
+		slog.Info("stellar/scheduler: skipped missed action runs", "action_id", a.ID, "missed", plan.missed)
+		return nil
+	}
+	// Claim the action before handing it to a worker so the next tick
+	// doesn't see it as still due.
+	if err := s.store.UpdateStellarActionStatus(ctx, a.ID, "running", "", ""); err != nil {
+		slog.Warn("stellar/scheduler: claim cron action failed", "action_id", a.ID, "error", err)
+		return nil
+	}
+	return func() {
+		s.executeAction(ctx, a)
+		// Recompute in the owner's current timezone, which may have
+		// changed while the action ran.
+		next := plan.next
+		if fresh, err := ParseSchedule(a.CronExpr, s.userLocation(ctx, a.UserID)); err == nil {
+			next = fresh.Next(now)
+		}
+		if next.IsZero() {
+			return
+		}
+		if err := s.store.RescheduleStellarAction(ctx, a.ID, next); err != nil {
+			slog.Warn("stellar/scheduler: reschedule cron action failed", "action_id", a.ID, "error", err)
+		}
+	}
+}
+
+// providerFromPolicy maps a mission's ProviderPolicy to a registry provider
+// name. "auto" (the default) defers to the registry's own default.
+func providerFromPolicy(policy string) string {
+	policy = strings.TrimSpace(policy)
+	if policy == "" || policy == "auto" {
+		return ""
+	}
+	return policy
+}
+
+func nilIfZero(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+const missionPrompt = `You are Stellar, running a scheduled mission on behalf of an operator.
+Carry out the goal you are given and report back.
+
+Format:
+**Result** — one sentence answer
+**Findings** — bullet list of what you checked and what you found
+**Next** — recommended follow-up, or "none"
+
+Under 300 words. No preamble.`
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/store"
+)
+
+func TestMissionInputCarriesScope(t *testing.T) {
+	st, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "cron.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	ctx := context.Background()
+	if err := st.UpdateStellarPreferences(ctx, &store.StellarPreferences{
+		UserID: "u1", PinnedClusters: []string{"prod-a", "prod-b"},
+	}); err != nil {
+		t.Fatalf("save preferences: %v", err)
+	}
+	s := New(st, nil)
+
+	tests := []struct {
+		name    string
+		mission store.StellarMission
+		want    string
+	}{
+		{"cluster and namespace", store.StellarMission{UserID: "u1", Goal: "check", Cluster: "prod-a", Namespace: "payments"},
+			"check\n\nCluster: prod-a\nNamespace: payments"},
+		{"pinned clusters", store.StellarMission{UserID: "u1", Goal: "check"}, "check\n\nClusters: prod-a, prod-b"},
+		{"no scope", store.StellarMission{UserID: "u2", Goal: "check"}, "check"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.missionInput(ctx, tt.mission); got != tt.want {
+				t.Errorf("missionInput = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
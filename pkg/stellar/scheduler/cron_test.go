@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return v
+}
+
+func TestParseScheduleNext(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	tests := []struct {
+		name string
+		spec string
+		loc  *time.Location
+		from string
+		want string
+	}{
+		{name: "every minute", spec: "* * * * *", loc: time.UTC, from: "2026-03-01T10:15:30Z", want: "2026-03-01T10:16:00Z"},
+		{name: "daily at nine", spec: "0 9 * * *", loc: time.UTC, from: "2026-03-01T10:00:00Z", want: "2026-03-02T09:00:00Z"},
+		{name: "daily descriptor", spec: "@daily", loc: time.UTC, from: "2026-03-01T10:00:00Z", want: "2026-03-02T00:00:00Z"},
+		{name: "step minutes", spec: "*/15 * * * *", loc: time.UTC, from: "2026-03-01T10:01:00Z", want: "2026-03-01T10:15:00Z"},
+		{name: "weekday range by name", spec: "30 8 * * mon-fri", loc: time.UTC, from: "2026-03-06T09:00:00Z", want: "2026-03-09T08:30:00Z"},
+		{name: "sunday as seven", spec: "0 0 * * 7", loc: time.UTC, from: "2026-03-02T00:00:00Z", want: "2026-03-08T00:00:00Z"},
+		{name: "dom or dow when both restricted", spec: "0 0 15 * fri", loc: time.UTC, from: "2026-03-01T00:00:00Z", want: "2026-03-06T00:00:00Z"},
+		{name: "user timezone", spec: "0 9 * * *", loc: kolkata, from: "2026-03-01T00:00:00Z", want: "2026-03-01T03:30:00Z"},
+		{name: "every descriptor", spec: "@every 90m", loc: time.UTC, from: "2026-03-01T10:00:00Z", want: "2026-03-01T11:30:00Z"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sched, err := ParseSchedule(tc.spec, tc.loc)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q): %v", tc.spec, err)
+			}
+			got := sched.Next(mustTime(t, tc.from)).UTC()
+			if want := mustTime(t, tc.want); !got.Equal(want) {
+				t.Fatalf("Next = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"0 0 30 2 *",
+		"@fortnightly",
+		"@every 10s",
+		"@every soon",
+	} {
+		if _, err := ParseSchedule(spec, time.UTC); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestPlanFire(t *testing.T) {
+	sched, err := ParseSchedule("0 * * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := mustTime(t, "2026-03-01T10:00:00Z")
+
+	onTime := planFire(sched, due, due.Add(20*time.Second), MissedRunSkip)
+	if !onTime.fire || onTime.missed != 0 {
+		t.Fatalf("on-time fire: got %+v", onTime)
+	}
+	if want := mustTime(t, "2026-03-01T11:00:00Z"); !onTime.next.Equal(want) {
+		t.Fatalf("on-time next = %s, want %s", onTime.next, want)
+	}
+
+	// Console was down from 10:00 until 13:30 — 10, 11, 12 and 13 o'clock missed.
+	restart := mustTime(t, "2026-03-01T13:30:00Z")
+	catchUp := planFire(sched, due, restart, MissedRunCatchUp)
+	if !catchUp.fire || catchUp.missed != 4 {
+		t.Fatalf("catch-up: got %+v", catchUp)
+	}
+	skip := planFire(sched, due, restart, MissedRunSkip)
+	if skip.fire || skip.missed != 4 {
+		t.Fatalf("skip: got %+v", skip)
+	}
+	if want := mustTime(t, "2026-03-01T14:00:00Z"); !skip.next.Equal(want) {
+		t.Fatalf("skip next = %s, want %s", skip.next, want)
+	}
+}
@@ -25,6 +25,14 @@ type SchedulerStore interface {
 	// Sprint 5: digest scheduler
 	GetNotificationsSince(ctx context.Context, since time.Time) ([]store.StellarNotification, error)
 	GetExecutionsSince(ctx context.Context, since time.Time) ([]store.StellarExecution, error)
+	// Cron engine: scheduled missions and recurring actions
+	GetStellarPreferences(ctx context.Context, userID string) (*store.StellarPreferences, error)
+	ListDueStellarMissions(ctx context.Context, now time.Time, limit int) ([]store.StellarMission, error)
+	SetStellarMissionRunTimes(ctx context.Context, missionID string, lastRunAt, nextRunAt *time.Time) error
+	CreateStellarExecution(ctx context.Context, execution *store.StellarExecution) error
+	GetDueCronStellarActions(ctx context.Context, now time.Time, limit int) ([]store.StellarAction, error)
+	RescheduleStellarAction(ctx context.Context, actionID string, next time.Time) error
+	RearmStaleCronStellarActions(ctx context.Context, staleBefore time.Time) (int64, error)
 }
 
 type Scheduler struct {
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +51,12 @@ const (
 	// also sets this via context.WithTimeout; this constant is the canonical
 	// value referenced from the spec.
 	MaxWallClock = 3 * time.Minute
+
+	// RecallLimit is how many similar past incidents the solver pulls from
+	// memory to include in its progress and escalation summary.
+	RecallLimit = 3
+
+	recallSummaryMax = 120
 )
 
 // AllowedActions lists the action types the solver is permitted to dispatch
@@ -81,6 +88,7 @@ type Storage interface {
 	UpdateStellarActionStatus(ctx context.Context, actionID, status, outcome, rejectReason string) error
 	CreateStellarExecution(ctx context.Context, execution *store.StellarExecution) error
 	CreateStellarNotification(ctx context.Context, notification *store.StellarNotification) error
+	SearchStellarMemoryEntries(ctx context.Context, userID, query string, limit int) ([]store.StellarMemoryEntry, error)
 }
 
 // Input is the structured handoff from the HTTP handler to the loop.
@@ -130,6 +138,13 @@ func SolveLoop(
 	}
 	broadcast("reading", "Reading recent pod status…", 0)
 
+	// Recall similar past incidents so the operator (and the escalation
+	// summary) can see "this happened before" context.
+	related := recallRelated(ctx, storage, input)
+	if len(related) > 0 {
+		broadcast("recalling", fmt.Sprintf("Seen something like this before: %s", truncateSummary(related[0].Summary)), 0)
+	}
+
 	// Deterministic action ladder for the v1 loop. The spec wants an LLM at the
 	// plan step; until that slots in, the ladder gives the same "junior engineer"
 	// feel: try the safe thing first, fall back, escalate. Ordered by reversibility.
@@ -190,6 +205,15 @@ func SolveLoop(
 	// Exhausted the ladder without success → escalate to human.
 	summary := fmt.Sprintf("Tried %d action(s) (last: %s — %s). Issue persists; needs your judgment.",
 		actionsTaken, lastAction, lastOutcome)
+	if len(related) > 0 {
+		var sb strings.Builder
+		sb.WriteString(summary)
+		sb.WriteString(" Similar past incidents:")
+		for _, m := range related {
+			sb.WriteString(fmt.Sprintf(" [%s] %s;", m.CreatedAt.Format("Jan 02"), truncateSummary(m.Summary)))
+		}
+		summary = strings.TrimSuffix(sb.String(), ";")
+	}
 	terminate(ctx, storage, input.SolveID, "escalated", summary, "", "", broadcaster, input)
 }
 
@@ -335,3 +359,22 @@ func terminate(
 	slog.Info("solver: terminal",
 		"solve_id", solveID, "status", status, "summary", summary, "limit_hit", limitHit)
 }
+
+// recallRelated looks up memories semantically related to the failing
+// workload. Recall is best-effort: errors just mean no extra context.
+func recallRelated(ctx context.Context, storage Storage, input Input) []store.StellarMemoryEntry {
+	query := fmt.Sprintf("%s %s in %s/%s on %s", input.Reason, input.Workload, input.Namespace, input.PodName, input.Cluster)
+	related, err := storage.SearchStellarMemoryEntries(ctx, input.UserID, query, RecallLimit)
+	if err != nil {
+		slog.Debug("solver: memory recall failed", "solve_id", input.SolveID, "error", err)
+		return nil
+	}
+	return related
+}
+
+func truncateSummary(s string) string {
+	if len(s) <= recallSummaryMax {
+		return s
+	}
+	return s[:recallSummaryMax] + "…"
+}
@@ -0,0 +1,456 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer
+// console than this one. Running against it could silently drop data the
+// newer schema depends on, so the store refuses to start.
+var ErrSchemaTooNew = errors.New("database schema is newer than this console supports")
+
+// ErrIrreversibleMigration is returned when rolling back past a migration
+// that has no down step.
+var ErrIrreversibleMigration = errors.New("migration cannot be rolled back")
+
+// schemaMigration is one numbered step of the store schema. up moves a
+// database from version-1 to version and down reverses it; each runs in a
+// transaction together with its schema_migrations bookkeeping. A nil down
+// marks the step irreversible.
+type schemaMigration struct {
+	version int
+	name    string
+	up      []string
+	down    []string
+}
+
+// baselineSchemaVersion is the schema created by migrateBaseline, which
+// predates version tracking.
+const baselineSchemaVersion = 1
+
+// schemaMigrations lists every schema version in order. Append new steps
+// at the end with the next version number and never edit a released one;
+// a released step has already run against users' databases.
+var schemaMigrations = []schemaMigration{
+	{version: baselineSchemaVersion, name: "baseline"},
+	{
+		// License compliance policy (#9648): the operator's allow/warn/deny
+		// lists, stored as one JSON document.
+		version: 2,
+		name:    "license_policy",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS license_policy (
+				id         INTEGER PRIMARY KEY CHECK (id = 1),
+				data       BLOB NOT NULL,
+				updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)`,
+		},
+		down: []string{`DROP TABLE IF EXISTS license_policy`},
+	},
+	{
+		// Server-side alert rules and the state of their alert instances.
+		version: 3,
+		name:    "alert_rules",
+		up: []string{
+			`CREATE TABLE alert_rules (
+				id         TEXT PRIMARY KEY,
+				data       BLOB NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE TABLE alert_states (
+				rule_id      TEXT NOT NULL,
+				fingerprint  TEXT NOT NULL,
+				status       TEXT NOT NULL,
+				active_since DATETIME NOT NULL,
+				fired_at     DATETIME,
+				alert        BLOB NOT NULL,
+				PRIMARY KEY (rule_id, fingerprint)
+			)`,
+		},
+		down: []string{
+			`DROP TABLE alert_states`,
+			`DROP TABLE alert_rules`,
+		},
+	},
+	{
+		// Durable notification queue: per-target deliveries with retry
+		// state, the last status notified per alert, and silences.
+		version: 4,
+		name:    "notification_queue",
+		up: []string{
+			`CREATE TABLE notification_deliveries (
+				id           TEXT PRIMARY KEY,
+				target       TEXT NOT NULL,
+				channel      BLOB,
+				group_key    TEXT NOT NULL,
+				alerts       BLOB NOT NULL,
+				status       TEXT NOT NULL,
+				attempts     INTEGER NOT NULL DEFAULT 0,
+				next_attempt DATETIME NOT NULL,
+				last_error   TEXT NOT NULL DEFAULT '',
+				created_at   DATETIME NOT NULL,
+				updated_at   DATETIME NOT NULL
+			)`,
+			`CREATE INDEX idx_notification_deliveries_due ON notification_deliveries (status, next_attempt)`,
+			`CREATE TABLE notified_alerts (
+				fingerprint TEXT PRIMARY KEY,
+				status      TEXT NOT NULL,
+				notified_at DATETIME NOT NULL
+			)`,
+			`CREATE TABLE notification_silences (
+				id         TEXT PRIMARY KEY,
+				data       BLOB NOT NULL,
+				ends_at    DATETIME NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)`,
+		},
+		down: []string{
+			`DROP TABLE notification_silences`,
+			`DROP TABLE notified_alerts`,
+			`DROP TABLE notification_deliveries`,
+		},
+	},
+	{
+		// Tamper-evident audit log: every audit_log row carries its own
+		// hash and the previous row's, and audit_checkpoints holds signed
+		// snapshots of the chain head, each linked to the one before.
+		// Rows written before this version keep empty hashes; the highest
+		// of their IDs is recorded in audit_chain_meta so only those are
+		// reported as unchained.
+		version: 5,
+		name:    "audit_chain",
+		up: []string{
+			`ALTER TABLE audit_log ADD COLUMN prev_hash TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE audit_log ADD COLUMN hash TEXT NOT NULL DEFAULT ''`,
+			`CREATE TABLE audit_chain_meta (legacy_cutoff_id INTEGER NOT NULL)`,
+			`INSERT INTO audit_chain_meta (legacy_cutoff_id) SELECT COALESCE(MAX(id), 0) FROM audit_log`,
+			`CREATE TABLE audit_checkpoints (
+				id         INTEGER PRIMARY KEY AUTOINCREMENT,
+				last_id    INTEGER NOT NULL,
+				hash       TEXT NOT NULL,
+				prev_hash  TEXT NOT NULL,
+				key_id     TEXT NOT NULL,
+				signature  TEXT NOT NULL,
+				created_at DATETIME NOT NULL
+			)`,
+		},
+		down: []string{
+			`DROP TABLE audit_checkpoints`,
+			`DROP TABLE audit_chain_meta`,
+			`ALTER TABLE audit_log DROP COLUMN hash`,
+			`ALTER TABLE audit_log DROP COLUMN prev_hash`,
+		},
+	},
+	{
+		// GPU reservation lifecycle: the last owner notification sent for
+		// each reservation, so start/expiry reminders go out once.
+		version: 6,
+		name:    "gpu_reservation_lifecycle",
+		up: []string{
+			`ALTER TABLE gpu_reservations ADD COLUMN notified_stage TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS idx_gpu_reservations_cluster_status ON gpu_reservations(cluster, status)`,
+		},
+		down: []string{
+			`DROP INDEX IF EXISTS idx_gpu_reservations_cluster_status`,
+			`ALTER TABLE gpu_reservations DROP COLUMN notified_stage`,
+		},
+	},
+	{
+		// GPU chargeback reports: utilization snapshots are read by time
+		// range, and each scheduled monthly report is claimed once so a
+		// restart or a second replica does not email it again.
+		version: 7,
+		name:    "gpu_chargeback",
+		up: []string{
+			`CREATE INDEX IF NOT EXISTS idx_utilization_timestamp ON gpu_utilization_snapshots(timestamp)`,
+			`CREATE TABLE gpu_chargeback_runs (
+				period     TEXT PRIMARY KEY,
+				claimed_at DATETIME NOT NULL
+			)`,
+		},
+		down: []string{
+			`DROP TABLE gpu_chargeback_runs`,
+			`DROP INDEX IF EXISTS idx_utilization_timestamp`,
+		},
+	},
+	{
+		// kc-agent reverse tunnel (#6195): agents paired with a user's
+		// account, identified by the hash of their tunnel credential.
+		version: 8,
+		name:    "agent_tunnel_credentials",
+		up: []string{
+			`CREATE TABLE agent_tunnel_credentials (
+				id           TEXT PRIMARY KEY,
+				user_id      TEXT NOT NULL,
+				name         TEXT NOT NULL,
+				token_hash   TEXT NOT NULL UNIQUE,
+				created_at   DATETIME NOT NULL,
+				last_seen_at DATETIME
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_agent_tunnel_credentials_user ON agent_tunnel_credentials(user_id)`,
+		},
+		down: []string{
+			`DROP INDEX IF EXISTS idx_agent_tunnel_credentials_user`,
+			`DROP TABLE agent_tunnel_credentials`,
+		},
+	},
+	{
+		// Notification delivery leases: a replica claims due deliveries
+		// before sending them so two replicas never send the same one.
+		version: 9,
+		name:    "notification_claims",
+		up: []string{
+			`ALTER TABLE notification_deliveries ADD COLUMN claimed_until DATETIME`,
+		},
+		down: []string{
+			`ALTER TABLE notification_deliveries DROP COLUMN claimed_until`,
+		},
+	},
+	{
+		// AI router budgets: per-user daily spend recorded by the server
+		// only, so the browser-synced user_token_usage row cannot lower
+		// what is enforced.
+		version: 10,
+		name:    "ai_budget_usage",
+		up: []string{
+			`CREATE TABLE ai_budget_usage (
+				user_id  TEXT NOT NULL,
+				day      TEXT NOT NULL,
+				category TEXT NOT NULL,
+				tokens   INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (user_id, day, category)
+			)`,
+		},
+		down: []string{
+			`DROP TABLE ai_budget_usage`,
+		},
+	},
+	{
+		// Scheduled missions: the cluster and namespace a mission targets,
+		// sent to the provider with every scheduled run.
+		version: 11,
+		name:    "stellar_mission_scope",
+		up: []string{
+			`ALTER TABLE stellar_missions ADD COLUMN cluster TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE stellar_missions ADD COLUMN namespace TEXT NOT NULL DEFAULT ''`,
+		},
+		down: []string{
+			`ALTER TABLE stellar_missions DROP COLUMN namespace`,
+			`ALTER TABLE stellar_missions DROP COLUMN cluster`,
+		},
+	},
+	{
+		// GPU chargeback: when each reservation first became active, so
+		// reservations that never held GPUs are not billed. Rows that may
+		// have been active before this column existed keep their previous
+		// billing by starting at created_at.
+		version: 12,
+		name:    "gpu_reservation_activation",
+		up: []string{
+			`ALTER TABLE gpu_reservations ADD COLUMN activated_at DATETIME`,
+			`UPDATE gpu_reservations SET activated_at = created_at WHERE status IN ('active', 'completed', 'cancelled')`,
+		},
+		down: []string{
+			`ALTER TABLE gpu_reservations DROP COLUMN activated_at`,
+		},
+	},
+}
+
+// LatestSchemaVersion is the schema version this console migrates to.
+func LatestSchemaVersion() int {
+	return schemaMigrations[len(schemaMigrations)-1].version
+}
+
+// MigrationStatus reports whether one schema migration has been applied.
+type MigrationStatus struct {
+	Version    int
+	Name       string
+	Applied    bool
+	AppliedAt  *time.Time
+	Reversible bool
+}
+
+// migrate brings the schema up to LatestSchemaVersion. It runs on every
+// start.
+func (s *SQLiteStore) migrate() error {
+	ctx := context.Background()
+	applied, err := s.MigrateUp(ctx, 0)
+	if err != nil {
+		return err
+	}
+	slog.Info("[SQLite] schema migrations complete",
+		"applied", applied, "version", LatestSchemaVersion())
+	return nil
+}
+
+func (s *SQLiteStore) ensureMigrationsTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// SchemaVersion returns the highest applied schema version, or 0 for a
+// database that has never been migrated.
+func (s *SQLiteStore) SchemaVersion(ctx context.Context) (int, error) {
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return 0, err
+	}
+	var version sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// MigrationStatus lists every known migration and, for applied ones, when
+// they ran.
+func (s *SQLiteStore) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(schemaMigrations))
+	for _, m := range schemaMigrations {
+		st := MigrationStatus{Version: m.version, Name: m.name, Reversible: m.down != nil}
+		if at, ok := appliedAt[m.version]; ok {
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// MigrateUp applies pending migrations up to and including target, or up
+// to LatestSchemaVersion when target is 0, and returns how many ran. It
+// fails with ErrSchemaTooNew if the database is ahead of this console.
+func (s *SQLiteStore) MigrateUp(ctx context.Context, target int) (int, error) {
+	if target <= 0 {
+		target = LatestSchemaVersion()
+	}
+	applied := 0
+	err := s.withMigrationLock(ctx, func() error {
+		current, err := s.checkSchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range schemaMigrations {
+			if m.version <= current || m.version > target {
+				continue
+			}
+			if err := s.applyMigration(ctx, m); err != nil {
+				return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+			}
+			slog.Info("[SQLite] schema migration applied", "version", m.version, "name", m.name)
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown rolls back applied migrations, newest first, until the
+// schema is at target, and returns how many were reverted. It stops with
+// ErrIrreversibleMigration at a step without a down migration.
+func (s *SQLiteStore) MigrateDown(ctx context.Context, target int) (int, error) {
+	reverted := 0
+	err := s.withMigrationLock(ctx, func() error {
+		current, err := s.checkSchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		for i := len(schemaMigrations) - 1; i >= 0; i-- {
+			m := schemaMigrations[i]
+			if m.version > current || m.version <= target {
+				continue
+			}
+			if m.down == nil {
+				return fmt.Errorf("migration %d (%s): %w", m.version, m.name, ErrIrreversibleMigration)
+			}
+			if err := s.revertMigration(ctx, m); err != nil {
+				return fmt.Errorf("revert migration %d (%s): %w", m.version, m.name, err)
+			}
+			slog.Info("[SQLite] schema migration reverted", "version", m.version, "name", m.name)
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// checkSchemaVersion returns the current schema version, refusing one
+// newer than LatestSchemaVersion.
+func (s *SQLiteStore) checkSchemaVersion(ctx context.Context) (int, error) {
+	current, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if latest := LatestSchemaVersion(); current > latest {
+		return 0, fmt.Errorf("%w: database is at version %d, this console knows up to %d; upgrade the console or roll the database back with the newer one",
+			ErrSchemaTooNew, current, latest)
+	}
+	return current, nil
+}
+
+func (s *SQLiteStore) applyMigration(ctx context.Context, m schemaMigration) error {
+	if m.version == baselineSchemaVersion {
+		// The baseline manages its own statements and is idempotent, so it
+		// is safe to re-run if recording it below fails.
+		if err := s.migrateBaseline(ctx); err != nil {
+			return err
+		}
+		_, err := s.db.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			m.version, m.name, time.Now().UTC())
+		return err
+	}
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range m.up {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			m.version, m.name, time.Now().UTC())
+		return err
+	})
+}
+
+func (s *SQLiteStore) revertMigration(ctx context.Context, m schemaMigration) error {
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range m.down {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.version)
+		return err
+	})
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_FreshDatabaseIsAtLatestVersion(t *testing.T) {
+	s := newTestStore(t)
+
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion(), version)
+
+	status, err := s.MigrationStatus(ctx)
+	require.NoError(t, err)
+	require.Len(t, status, len(schemaMigrations))
+	for _, st := range status {
+		require.True(t, st.Applied, "migration %d not applied", st.Version)
+		require.NotNil(t, st.AppliedAt)
+		require.WithinDuration(t, time.Now(), *st.AppliedAt, time.Minute)
+	}
+	require.False(t, status[0].Reversible, "baseline must be irreversible")
+}
+
+func TestMigrations_DownAndUpRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.SaveLicensePolicy(ctx, []byte(`{}`)))
+
+	reverted, err := s.MigrateDown(ctx, baselineSchemaVersion)
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion()-baselineSchemaVersion, reverted)
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, baselineSchemaVersion, version)
+	_, err = s.GetLicensePolicy(ctx)
+	require.Error(t, err, "license_policy should be dropped")
+
+	applied, err := s.MigrateUp(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, reverted, applied)
+	policy, err := s.GetLicensePolicy(ctx)
+	require.NoError(t, err)
+	require.Nil(t, policy)
+}
+
+func TestMigrations_BaselineIsIrreversible(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.MigrateDown(ctx, 0)
+	require.ErrorIs(t, err, ErrIrreversibleMigration)
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, baselineSchemaVersion, version)
+}
+
+func TestMigrations_RefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		LatestSchemaVersion()+1, "from_the_future", time.Now().UTC())
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = NewSQLiteStore(dbPath)
+	require.ErrorIs(t, err, ErrSchemaTooNew)
+}
+
+func TestMigrations_AdoptsUnversionedDatabase(t *testing.T) {
+	// A database from a console that predates schema_migrations has the
+	// baseline tables but no version rows.
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	legacy, err := OpenSQLiteStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, legacy.migrateBaseline(ctx))
+	createTestUser(t, legacy, "gh-legacy", "legacy")
+	require.NoError(t, legacy.Close())
+
+	s, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion(), version)
+	user, err := s.GetUserByGitHubID(ctx, "gh-legacy")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+}
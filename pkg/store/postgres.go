@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// PostgreSQL connection pool defaults. Unlike SQLite, several console
+// replicas share the server, so each keeps a modest pool.
+const (
+	postgresDefaultMaxOpenConns    = 20
+	postgresDefaultMaxIdleConns    = 5
+	postgresDefaultConnMaxLifetime = 30 * time.Minute
+	postgresDefaultConnMaxIdleTime = 5 * time.Minute
+	// postgresConnectTimeout bounds the startup ping.
+	postgresConnectTimeout = 30 * time.Second
+)
+
+// PostgresStore implements Store on PostgreSQL, so several console
+// replicas can run behind a load balancer against one database. It shares
+// SQLiteStore's queries and schema: its connections translate them to
+// PostgreSQL as they are issued (see postgres_dialect.go), so the two
+// backends cannot drift apart.
+type PostgresStore struct {
+	*SQLiteStore
+}
+
+// NewPostgresStore connects to the PostgreSQL database named by dsn, a
+// postgres:// URL or key=value connection string, and migrates its schema.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	store, err := OpenPostgresStore(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.migrate(); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to migrate: %w", err)
+	}
+	return store, nil
+}
+
+// OpenPostgresStore connects to a PostgreSQL store without touching its
+// schema. It is used by the migrate command; everything else should use
+// NewPostgresStore.
+func OpenPostgresStore(dsn string) (*PostgresStore, error) {
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres dsn: %w", err)
+	}
+	// The simple protocol sends arguments as untyped literals that the
+	// server types from context, as SQLite's type affinity does; the
+	// shared queries rely on that (e.g. an RFC 3339 string written to a
+	// DATETIME column).
+	config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+	db := sql.OpenDB(pgConnector{stdlib.GetConnector(*config)})
+	db.SetMaxOpenConns(getEnvInt("KC_POSTGRES_MAX_OPEN_CONNS", postgresDefaultMaxOpenConns))
+	db.SetMaxIdleConns(postgresDefaultMaxIdleConns)
+	db.SetConnMaxLifetime(postgresDefaultConnMaxLifetime)
+	db.SetConnMaxIdleTime(postgresDefaultConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresConnectTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	slog.Info("[Postgres] connected", "host", config.Host, "database", config.Database)
+	return &PostgresStore{SQLiteStore: &SQLiteStore{db: db, dialect: dialectPostgres}}, nil
+}
+
+// withMigrationLock runs fn while holding a session advisory lock on
+// PostgreSQL, so replicas that start together migrate one at a time. On
+// SQLite it just runs fn.
+func (s *SQLiteStore) withMigrationLock(ctx context.Context, fn func() error) error {
+	if s.dialect != dialectPostgres {
+		return fn()
+	}
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(?)`, pgMigrateLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), rollbackTimeout)
+		defer cancel()
+		_, _ = conn.ExecContext(unlockCtx, `SELECT pg_advisory_unlock(?)`, pgMigrateLockKey)
+	}()
+	return fn()
+}
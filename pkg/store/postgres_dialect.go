@@ -0,0 +1,271 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// sqlDialect identifies the database engine behind a SQLiteStore.
+type sqlDialect int
+
+const (
+	dialectSQLite sqlDialect = iota
+	dialectPostgres
+)
+
+// Advisory lock keys shared by every console replica on a PostgreSQL
+// database.
+const (
+	// pgWriteLockKey is held for the life of a BEGIN IMMEDIATE
+	// transaction, giving those read-modify-write flows the same
+	// single-writer guarantee SQLite's RESERVED lock does.
+	pgWriteLockKey int64 = 0x6b63_7772_6974_6531
+	// pgMigrateLockKey serialises schema migration when several replicas
+	// start at once.
+	pgMigrateLockKey int64 = 0x6b63_6d69_6772_6174
+)
+
+// The store's queries are written for SQLite. rewritePostgres translates
+// the SQLite idioms they use into PostgreSQL as each statement is issued,
+// so one set of queries serves both backends. Rewrites only apply outside
+// string literals, quoted identifiers and comments, except for the
+// function-call patterns below, which are specific enough to match whole.
+var (
+	pgFuncRewrites = []struct {
+		re   *regexp.Regexp
+		repl string
+	}{
+		// datetime('now', '-' || ? || ' days'): a retention cutoff.
+		{regexp.MustCompile(`datetime\('now',\s*'-'\s*\|\|\s*\?\s*\|\|\s*' days'\)`), `(now() - make_interval(days => ?))`},
+		// datetime('now'): SQLite's "YYYY-MM-DD HH:MM:SS" UTC text form.
+		{regexp.MustCompile(`datetime\('now'\)`), `to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')`},
+		// Random 32-character hex ids used as column defaults.
+		{regexp.MustCompile(`lower\(hex\(randomblob\(16\)\)\)`), `replace(gen_random_uuid()::text, '-', '')`},
+	}
+
+	pgCodeRewrites = []struct {
+		re   *regexp.Regexp
+		repl string
+	}{
+		// col = ? COLLATE NOCASE
+		{regexp.MustCompile(`(\w+)\s*=\s*\?\s+COLLATE\s+NOCASE`), `lower($1) = lower(?)`},
+		// SQLite's LIKE ignores ASCII case; PostgreSQL's does not.
+		{regexp.MustCompile(`\bLIKE\b`), `ILIKE`},
+	}
+
+	// pgDDLRewrites map SQLite column types onto PostgreSQL ones. SQLite
+	// INTEGER is 64-bit, so it becomes BIGINT; booleans stay integers
+	// because the queries compare them with 0 and 1.
+	pgDDLRewrites = []struct {
+		re   *regexp.Regexp
+		repl string
+	}{
+		{regexp.MustCompile(`\bINTEGER PRIMARY KEY AUTOINCREMENT\b`), `BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY`},
+		{regexp.MustCompile(`\bINTEGER\b`), `BIGINT`},
+		{regexp.MustCompile(`\bDATETIME\b`), `TIMESTAMPTZ`},
+		{regexp.MustCompile(`\bBLOB\b`), `BYTEA`},
+		{regexp.MustCompile(`\bREAL\b`), `DOUBLE PRECISION`},
+		// Index on col COLLATE NOCASE: index the lowered value instead.
+		{regexp.MustCompile(`(\w+)\s+COLLATE\s+NOCASE\)`), `lower($1))`},
+		// Column migrations are re-run on every start; SQLite reports the
+		// duplicate and migrate skips it.
+		{regexp.MustCompile(`\bADD COLUMN\b`), `ADD COLUMN IF NOT EXISTS`},
+	}
+
+	pgDDLStatement = regexp.MustCompile(`(?i)^(CREATE|ALTER)\b`)
+)
+
+// rewritePostgres translates a query written for SQLite into PostgreSQL,
+// numbering ? placeholders as $1, $2, ... in order.
+func rewritePostgres(query string) string {
+	for _, r := range pgFuncRewrites {
+		query = r.re.ReplaceAllString(query, r.repl)
+	}
+	ddl := pgDDLStatement.MatchString(strings.TrimSpace(stripLeadingComments(query)))
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+	n := 0
+	splitSQL(query, func(seg string, code bool) {
+		if !code {
+			b.WriteString(seg)
+			return
+		}
+		for _, r := range pgCodeRewrites {
+			seg = r.re.ReplaceAllString(seg, r.repl)
+		}
+		if ddl {
+			for _, r := range pgDDLRewrites {
+				seg = r.re.ReplaceAllString(seg, r.repl)
+			}
+		}
+		for {
+			i := strings.IndexByte(seg, '?')
+			if i < 0 {
+				break
+			}
+			n++
+			b.WriteString(seg[:i])
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			seg = seg[i+1:]
+		}
+		b.WriteString(seg)
+	})
+	return b.String()
+}
+
+// splitSQL calls fn with consecutive pieces of query, reporting whether
+// each is SQL code or a string literal, quoted identifier or comment.
+func splitSQL(query string, fn func(seg string, code bool)) {
+	start := 0
+	for i := 0; i < len(query); i++ {
+		var end int
+		switch {
+		case query[i] == '\'' || query[i] == '"':
+			end = closingQuote(query, i)
+		case strings.HasPrefix(query[i:], "--"):
+			if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+				end = i + j
+			} else {
+				end = len(query)
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+				end = i + 2 + j + 2
+			} else {
+				end = len(query)
+			}
+		default:
+			continue
+		}
+		if i > start {
+			fn(query[start:i], true)
+		}
+		fn(query[i:end], false)
+		start = end
+		i = end - 1
+	}
+	if start < len(query) {
+		fn(query[start:], true)
+	}
+}
+
+// closingQuote returns the index just past the quote that closes the one
+// at query[open]. A doubled quote is an escaped quote.
+func closingQuote(query string, open int) int {
+	q := query[open]
+	for i := open + 1; i < len(query); i++ {
+		if query[i] != q {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == q {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+func stripLeadingComments(query string) string {
+	for {
+		query = strings.TrimSpace(query)
+		if !strings.HasPrefix(query, "--") {
+			return query
+		}
+		i := strings.IndexByte(query, '\n')
+		if i < 0 {
+			return ""
+		}
+		query = query[i+1:]
+	}
+}
+
+// pgConnector opens pgx connections that accept the store's SQLite
+// queries.
+type pgConnector struct {
+	driver.Connector
+}
+
+func (c pgConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pc, ok := conn.(*stdlib.Conn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected postgres driver connection %T", conn)
+	}
+	return &pgConn{Conn: pc}, nil
+}
+
+// pgConn rewrites each statement with rewritePostgres before handing it to
+// pgx.
+type pgConn struct {
+	*stdlib.Conn
+}
+
+func (c *pgConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rewritePostgres(query))
+}
+
+func (c *pgConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.PrepareContext(ctx, rewritePostgres(query))
+}
+
+func (c *pgConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if isBeginImmediate(query) {
+		return beginImmediate(ctx, c.Conn)
+	}
+	return c.Conn.ExecContext(ctx, rewritePostgres(query), args)
+}
+
+func (c *pgConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.QueryContext(ctx, rewritePostgres(query), args)
+}
+
+func isBeginImmediate(query string) bool {
+	return strings.EqualFold(strings.TrimSpace(query), "BEGIN IMMEDIATE")
+}
+
+// pgExecer is the part of a pgx connection beginImmediate uses.
+type pgExecer interface {
+	ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error)
+}
+
+// beginImmediate opens a transaction on conn holding pgWriteLockKey until
+// it commits or rolls back.
+func beginImmediate(ctx context.Context, conn pgExecer) (driver.Result, error) {
+	if _, err := conn.ExecContext(ctx, "BEGIN", nil); err != nil {
+		return nil, err
+	}
+	lock := fmt.Sprintf("SELECT pg_advisory_xact_lock(%d)", pgWriteLockKey)
+	if _, err := conn.ExecContext(ctx, lock, nil); err != nil {
+		_, _ = conn.ExecContext(context.Background(), "ROLLBACK", nil)
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// CheckNamedValue converts arguments the way database/sql does for SQLite,
+// then stores booleans as 0 and 1 to match the integer columns they are
+// written to.
+func (c *pgConn) CheckNamedValue(nv *driver.NamedValue) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	if b, ok := v.(bool); ok {
+		v = int64(boolToInt(b))
+	}
+	nv.Value = v
+	return nil
+}
@@ -0,0 +1,327 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewritePostgres(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "numbers placeholders in order",
+			query: `UPDATE users SET role = ? WHERE id = ? AND github_id = ?`,
+			want:  `UPDATE users SET role = $1 WHERE id = $2 AND github_id = $3`,
+		},
+		{
+			name:  "leaves placeholders in literals and comments",
+			query: "SELECT '?', \"a?\" FROM t -- why?\nWHERE x = ? /* and? */ AND y = 'it''s ?'",
+			want:  "SELECT '?', \"a?\" FROM t -- why?\nWHERE x = $1 /* and? */ AND y = 'it''s ?'",
+		},
+		{
+			name:  "datetime now",
+			query: `UPDATE t SET updated_at = datetime('now') WHERE id = ?`,
+			want:  `UPDATE t SET updated_at = to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS') WHERE id = $1`,
+		},
+		{
+			name:  "retention cutoff",
+			query: `DELETE FROM t WHERE created_at < datetime('now', '-' || ? || ' days')`,
+			want:  `DELETE FROM t WHERE created_at < (now() - make_interval(days => $1))`,
+		},
+		{
+			name:  "case-insensitive comparison",
+			query: `SELECT id FROM users WHERE github_login = ? COLLATE NOCASE`,
+			want:  `SELECT id FROM users WHERE lower(github_login) = lower($1)`,
+		},
+		{
+			name:  "case-insensitive index",
+			query: `CREATE INDEX IF NOT EXISTS idx_login ON users(github_login COLLATE NOCASE)`,
+			want:  `CREATE INDEX IF NOT EXISTS idx_login ON users(lower(github_login))`,
+		},
+		{
+			name:  "like ignores case",
+			query: `SELECT id FROM t WHERE summary LIKE ?`,
+			want:  `SELECT id FROM t WHERE summary ILIKE $1`,
+		},
+		{
+			name: "ddl column types",
+			query: `CREATE TABLE IF NOT EXISTS t (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				n INTEGER NOT NULL DEFAULT 0,
+				at DATETIME,
+				blob BLOB,
+				score REAL,
+				label TEXT DEFAULT 'INTEGER'
+			)`,
+			want: `CREATE TABLE IF NOT EXISTS t (
+				id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				n BIGINT NOT NULL DEFAULT 0,
+				at TIMESTAMPTZ,
+				blob BYTEA,
+				score DOUBLE PRECISION,
+				label TEXT DEFAULT 'INTEGER'
+			)`,
+		},
+		{
+			name:  "ddl after leading comment",
+			query: "-- counters\nALTER TABLE t ADD COLUMN hits INTEGER DEFAULT 0",
+			want:  "-- counters\nALTER TABLE t ADD COLUMN IF NOT EXISTS hits BIGINT DEFAULT 0",
+		},
+		{
+			name:  "random hex default",
+			query: `CREATE TABLE t (id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))))`,
+			want:  `CREATE TABLE t (id TEXT PRIMARY KEY DEFAULT (replace(gen_random_uuid()::text, '-', '')))`,
+		},
+		{
+			name:  "types untouched outside ddl",
+			query: `SELECT CAST(? AS INTEGER) FROM t`,
+			want:  `SELECT CAST($1 AS INTEGER) FROM t`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, rewritePostgres(tt.query))
+		})
+	}
+}
+
+func TestPgConnCheckNamedValue(t *testing.T) {
+	c := &pgConn{}
+	tests := []struct {
+		in   any
+		want driver.Value
+	}{
+		{true, int64(1)},
+		{false, int64(0)},
+		{7, int64(7)},
+		{"text", "text"},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		nv := &driver.NamedValue{Value: tt.in}
+		require.NoError(t, c.CheckNamedValue(nv))
+		require.Equal(t, tt.want, nv.Value)
+	}
+}
+
+// recordingExecer records the statements beginImmediate issues.
+type recordingExecer struct {
+	stmts  []string
+	failOn string
+}
+
+func (r *recordingExecer) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	r.stmts = append(r.stmts, query)
+	if query == r.failOn {
+		return nil, errors.New("boom")
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func TestBeginImmediateTakesAdvisoryLock(t *testing.T) {
+	lock := fmt.Sprintf("SELECT pg_advisory_xact_lock(%d)", pgWriteLockKey)
+
+	require.True(t, isBeginImmediate("  begin immediate "))
+	require.False(t, isBeginImmediate("BEGIN"))
+
+	conn := &recordingExecer{}
+	_, err := beginImmediate(context.Background(), conn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"BEGIN", lock}, conn.stmts)
+
+	// A failed lock must not leave the transaction open.
+	conn = &recordingExecer{failOn: lock}
+	_, err = beginImmediate(context.Background(), conn)
+	require.Error(t, err)
+	require.Equal(t, []string{"BEGIN", lock, "ROLLBACK"}, conn.stmts)
+}
+
+// sqliteOnlyStatements are issued only on the SQLite backend.
+var sqliteOnlyStatements = map[string]bool{
+	"PRAGMA foreign_keys = ON":         true, // sqliteConnector
+	"PRAGMA table_info(kb_query_gaps)": true, // skipped for dialectPostgres
+}
+
+// pgUntranslated matches SQLite idioms that must not reach PostgreSQL.
+var pgUntranslated = []*regexp.Regexp{
+	regexp.MustCompile(`\?`),
+	regexp.MustCompile(`(?i)\bdatetime\s*\(`),
+	regexp.MustCompile(`(?i)\brandomblob\b`),
+	regexp.MustCompile(`(?i)\bCOLLATE\s+NOCASE\b`),
+	regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`),
+	regexp.MustCompile(`(?i)\bINSERT\s+OR\b`),
+	regexp.MustCompile(`(?i)\bPRAGMA\b`),
+	regexp.MustCompile(`(?i)\b(strftime|julianday|group_concat|last_insert_rowid)\s*\(`),
+	regexp.MustCompile(`\bDATETIME\b`),
+	regexp.MustCompile(`\bBLOB\b`),
+	regexp.MustCompile(`(?i)\bADD COLUMN\b(?:\s+IF NOT EXISTS)?`),
+	regexp.MustCompile(`\bLIKE\b`),
+}
+
+// TestRewritePostgresStoreStatements translates every SQL statement the
+// store issues from a literal and checks no SQLite idiom survives.
+func TestRewritePostgresStoreStatements(t *testing.T) {
+	stmts := storeStatements(t)
+	// Guards against the collector silently finding nothing.
+	require.Greater(t, len(stmts), 200, "too few store statements found")
+
+	for _, st := range stmts {
+		if sqliteOnlyStatements[strings.TrimSpace(st.query)] || isBeginImmediate(st.query) {
+			continue
+		}
+		got := rewritePostgres(st.query)
+		splitSQL(got, func(seg string, code bool) {
+			if !code {
+				return
+			}
+			for _, re := range pgUntranslated {
+				m := re.FindString(seg)
+				if m == "" || m == "ADD COLUMN IF NOT EXISTS" {
+					continue
+				}
+				t.Errorf("%s: %q survives translation:\n%s", st.pos, m, got)
+			}
+		})
+	}
+}
+
+type storeStatement struct {
+	pos   string
+	query string
+}
+
+// storeQueryMethods are the database/sql methods whose query argument is
+// collected, mapped to that argument's index.
+var storeQueryMethods = map[string]int{
+	"Exec": 0, "Query": 0, "QueryRow": 0, "Prepare": 0,
+	"ExecContext": 1, "QueryContext": 1, "QueryRowContext": 1, "PrepareContext": 1,
+}
+
+// storeDDL matches string literals that are schema statements; the
+// startup migrations run them from slices rather than literal arguments.
+var storeDDL = regexp.MustCompile(`^\s*(CREATE|ALTER|DROP)\s`)
+
+// storeStatements parses the store's sources and returns the query of
+// every database/sql call whose query is a string constant or a
+// concatenation of them, every schema statement literal, and every
+// versioned migration. fmt.Sprintf verbs are filled with a column name.
+func storeStatements(t *testing.T) []storeStatement {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	consts := map[string]string{}
+	var parsed []*ast.File
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || name == "postgres_dialect.go" {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		require.NoError(t, err)
+		f, err := parser.ParseFile(fset, name, src, 0)
+		require.NoError(t, err)
+		parsed = append(parsed, f)
+		ast.Inspect(f, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.ValueSpec); ok {
+				for i, id := range spec.Names {
+					if i < len(spec.Values) {
+						if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							if v, err := strconv.Unquote(lit.Value); err == nil {
+								consts[id.Name] = v
+							}
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	var resolve func(e ast.Expr) (string, bool)
+	resolve = func(e ast.Expr) (string, bool) {
+		switch e := e.(type) {
+		case *ast.BasicLit:
+			if e.Kind != token.STRING {
+				return "", false
+			}
+			v, err := strconv.Unquote(e.Value)
+			return v, err == nil
+		case *ast.Ident:
+			v, ok := consts[e.Name]
+			return v, ok
+		case *ast.ParenExpr:
+			return resolve(e.X)
+		case *ast.BinaryExpr:
+			if e.Op != token.ADD {
+				return "", false
+			}
+			l, ok1 := resolve(e.X)
+			r, ok2 := resolve(e.Y)
+			return l + r, ok1 && ok2
+		case *ast.CallExpr:
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Sprintf" || len(e.Args) == 0 {
+				return "", false
+			}
+			format, ok := resolve(e.Args[0])
+			if !ok {
+				return "", false
+			}
+			return sprintfPlaceholder.ReplaceAllString(format, "col"), true
+		}
+		return "", false
+	}
+
+	var out []storeStatement
+	for _, f := range parsed {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if v, err := strconv.Unquote(lit.Value); err == nil && storeDDL.MatchString(v) {
+					out = append(out, storeStatement{pos: fset.Position(lit.Pos()).String(), query: v})
+				}
+				return true
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			idx, ok := storeQueryMethods[sel.Sel.Name]
+			if !ok || idx >= len(call.Args) {
+				return true
+			}
+			if q, ok := resolve(call.Args[idx]); ok {
+				out = append(out, storeStatement{pos: fset.Position(call.Pos()).String(), query: q})
+			}
+			return true
+		})
+	}
+	for _, m := range schemaMigrations {
+		for i, q := range append(append([]string{}, m.up...), m.down...) {
+			out = append(out, storeStatement{pos: fmt.Sprintf("migration %d statement %d", m.version, i), query: q})
+		}
+	}
+	return out
+}
+
+var sprintfPlaceholder = regexp.MustCompile(`%[-+# 0-9.]*[sdvq]`)
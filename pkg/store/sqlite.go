@@ -10,6 +10,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -256,6 +257,13 @@ type sqlContextExecer interface {
 // SQLiteStore implements Store using SQLite
 type SQLiteStore struct {
 	db *sql.DB
+	// dialect is dialectPostgres when the store backs a PostgresStore.
+	dialect sqlDialect
+
+	// memoryEmbedder vectorises Stellar memory for semantic recall; nil
+	// means keyword-only search. Guarded by embedMu.
+	embedMu        sync.RWMutex
+	memoryEmbedder MemoryEmbedFunc
 }
 
 func (s *SQLiteStore) WithTransaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
@@ -270,8 +278,23 @@ func (s *SQLiteStore) WithTransaction(ctx context.Context, fn func(tx *sql.Tx) e
 	return tx.Commit()
 }
 
-// NewSQLiteStore creates a new SQLite store
+// NewSQLiteStore creates a new SQLite store, applying any pending schema
+// migrations.
 func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
+	store, err := OpenSQLiteStore(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.migrate(); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to migrate: %w", err)
+	}
+	return store, nil
+}
+
+// OpenSQLiteStore opens a SQLite store without touching its schema. It is
+// used by the migrate command; everything else should use NewSQLiteStore.
+func OpenSQLiteStore(dbPath string) (*SQLiteStore, error) {
 	// DSN notes (modernc.org/sqlite accepts PRAGMAs via _pragma=key(value)):
 	//  - journal_mode=WAL enables Write-Ahead Logging so readers don't
 	//    block writers.
@@ -297,18 +320,13 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 	// Configure connection pool for resource management under high load
 	configureConnectionPool(db)
 
-	store := &SQLiteStore{db: db}
-	if err := store.migrate(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to migrate: %w", err)
-	}
-
-	return store, nil
+	return &SQLiteStore{db: db}, nil
 }
 
-// migrate creates the database schema
-func (s *SQLiteStore) migrate() error {
-	ctx := context.Background()
+// migrateBaseline creates the schema as it stood before versioned
+// migrations (schema version 1). Every statement is idempotent, so it also
+// brings databases created by older consoles up to that version.
+func (s *SQLiteStore) migrateBaseline(ctx context.Context) error {
 	schema := `
 	CREATE TABLE IF NOT EXISTS users (
 		id TEXT PRIMARY KEY,
@@ -973,7 +991,7 @@ func (s *SQLiteStore) migrate() error {
 		return fmt.Errorf("migrate kb_query_gaps schema: %w", err)
 	}
 
-	slog.Info("[SQLite] schema migrations complete", "total_migrations", len(migrations))
+	slog.Info("[SQLite] baseline schema ready", "legacy_migrations", len(migrations))
 
 	// Data migration: "pending" status is eliminated — reservations are now
 	// provisioned synchronously and go straight to "active". Flip any
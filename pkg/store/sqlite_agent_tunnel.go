@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/models"
+)
+
+// agentTunnelCredentialsMaxRows caps how many paired agents one user can
+// list; a user pairs a handful of machines, not thousands.
+const agentTunnelCredentialsMaxRows = 100
+
+const agentTunnelCredentialColumns = `id, user_id, name, token_hash, created_at, last_seen_at`
+
+func (s *SQLiteStore) CreateAgentTunnelCredential(ctx context.Context, cred *models.AgentTunnelCredential) error {
+	if cred.ID == uuid.Nil {
+		cred.ID = uuid.New()
+	}
+	if cred.CreatedAt.IsZero() {
+		cred.CreatedAt = time.Now().UTC()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO agent_tunnel_credentials (`+agentTunnelCredentialColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		cred.ID.String(), cred.UserID.String(), cred.Name, cred.TokenHash, cred.CreatedAt, cred.LastSeenAt)
+	return err
+}
+
+func (s *SQLiteStore) GetAgentTunnelCredentialByTokenHash(ctx context.Context, tokenHash string) (*models.AgentTunnelCredential, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+agentTunnelCredentialColumns+` FROM agent_tunnel_credentials WHERE token_hash = ?`, tokenHash)
+	cred, err := scanAgentTunnelCredential(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return cred, err
+}
+
+func (s *SQLiteStore) ListAgentTunnelCredentials(ctx context.Context, userID uuid.UUID) ([]models.AgentTunnelCredential, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+agentTunnelCredentialColumns+` FROM agent_tunnel_credentials WHERE user_id = ? ORDER BY created_at DESC LIMIT ?`,
+		userID.String(), agentTunnelCredentialsMaxRows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	creds := make([]models.AgentTunnelCredential, 0)
+	for rows.Next() {
+		cred, err := scanAgentTunnelCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, *cred)
+	}
+	return creds, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteAgentTunnelCredential(ctx context.Context, userID, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM agent_tunnel_credentials WHERE id = ? AND user_id = ?`, id.String(), userID.String())
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) TouchAgentTunnelCredential(ctx context.Context, id uuid.UUID, seenAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE agent_tunnel_credentials SET last_seen_at = ? WHERE id = ?`, seenAt.UTC(), id.String())
+	return err
+}
+
+func scanAgentTunnelCredential(row rowScanner) (*models.AgentTunnelCredential, error) {
+	var cred models.AgentTunnelCredential
+	var id, userID string
+	var lastSeen sql.NullTime
+	if err := row.Scan(&id, &userID, &cred.Name, &cred.TokenHash, &cred.CreatedAt, &lastSeen); err != nil {
+		return nil, err
+	}
+	cred.ID = parseUUID(id, "agentTunnelCredential.ID")
+	cred.UserID = parseUUID(userID, "agentTunnelCredential.UserID")
+	if lastSeen.Valid {
+		t := lastSeen.Time
+		cred.LastSeenAt = &t
+	}
+	return &cred, nil
+}
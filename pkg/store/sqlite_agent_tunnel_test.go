@@ -0,0 +1,45 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/models"
+)
+
+func TestAgentTunnelCredentials(t *testing.T) {
+	s := newTestStore(t)
+	user := createTestUser(t, s, "gh-tunnel", "tunneluser")
+	other := createTestUser(t, s, "gh-tunnel-2", "otheruser")
+
+	cred := &models.AgentTunnelCredential{UserID: user.ID, Name: "laptop", TokenHash: "hash-1"}
+	require.NoError(t, s.CreateAgentTunnelCredential(ctx, cred))
+	require.NotEqual(t, uuid.Nil, cred.ID)
+
+	got, err := s.GetAgentTunnelCredentialByTokenHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, user.ID, got.UserID)
+	require.Nil(t, got.LastSeenAt)
+
+	missing, err := s.GetAgentTunnelCredentialByTokenHash(ctx, "nope")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	seen := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, s.TouchAgentTunnelCredential(ctx, cred.ID, seen))
+	list, err := s.ListAgentTunnelCredentials(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.NotNil(t, list[0].LastSeenAt)
+	require.True(t, seen.Equal(*list[0].LastSeenAt))
+
+	require.ErrorIs(t, s.DeleteAgentTunnelCredential(ctx, other.ID, cred.ID), ErrNotFound)
+	require.NoError(t, s.DeleteAgentTunnelCredential(ctx, user.ID, cred.ID))
+	list, err = s.ListAgentTunnelCredentials(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
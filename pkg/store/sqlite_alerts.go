@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ListAlertRules returns every alert rule document, oldest first.
+func (s *SQLiteStore) ListAlertRules(ctx context.Context) ([][]byte, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM alert_rules ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rules [][]byte
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		rules = append(rules, data)
+	}
+	return rules, rows.Err()
+}
+
+// SaveAlertRule creates or replaces the alert rule document with the given ID.
+func (s *SQLiteStore) SaveAlertRule(ctx context.Context, id string, data []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO alert_rules (id, data, created_at, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		 ON CONFLICT(id) DO UPDATE SET data = excluded.data, updated_at = CURRENT_TIMESTAMP`,
+		id, data,
+	)
+	return err
+}
+
+// DeleteAlertRule removes an alert rule and its alert states.
+func (s *SQLiteStore) DeleteAlertRule(ctx context.Context, id string) error {
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM alert_states WHERE rule_id = ?`, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = ?`, id)
+		return err
+	})
+}
+
+// ListAlertStates returns every pending and firing alert instance.
+func (s *SQLiteStore) ListAlertStates(ctx context.Context) ([]AlertState, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT rule_id, fingerprint, status, active_since, fired_at, alert FROM alert_states ORDER BY active_since`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var states []AlertState
+	for rows.Next() {
+		var st AlertState
+		var firedAt sql.NullTime
+		if err := rows.Scan(&st.RuleID, &st.Fingerprint, &st.Status, &st.ActiveSince, &firedAt, &st.Alert); err != nil {
+			return nil, err
+		}
+		if firedAt.Valid {
+			st.FiredAt = &firedAt.Time
+		}
+		states = append(states, st)
+	}
+	return states, rows.Err()
+}
+
+// SaveAlertState creates or replaces the state of one alert instance.
+func (s *SQLiteStore) SaveAlertState(ctx context.Context, state AlertState) error {
+	var firedAt any
+	if state.FiredAt != nil {
+		firedAt = state.FiredAt.UTC()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO alert_states (rule_id, fingerprint, status, active_since, fired_at, alert) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(rule_id, fingerprint) DO UPDATE SET
+			status = excluded.status, active_since = excluded.active_since,
+			fired_at = excluded.fired_at, alert = excluded.alert`,
+		state.RuleID, state.Fingerprint, state.Status, state.ActiveSince.UTC(), firedAt, state.Alert,
+	)
+	return err
+}
+
+// DeleteAlertState forgets one alert instance.
+func (s *SQLiteStore) DeleteAlertState(ctx context.Context, ruleID, fingerprint string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM alert_states WHERE rule_id = ? AND fingerprint = ?`, ruleID, fingerprint)
+	return err
+}
@@ -11,11 +11,11 @@ import (
 // Token Revocation methods
 
 // RevokeToken persists a revoked token JTI with its expiration time.
-// INSERT OR IGNORE ensures re-revoking a token cannot silently extend
+// ON CONFLICT DO NOTHING ensures re-revoking a token cannot silently extend
 // its expiry — the first revocation is authoritative (#7731).
 func (s *SQLiteStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
 	_, err := s.db.ExecContext(ctx,
-		`INSERT OR IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`,
+		`INSERT INTO revoked_tokens (jti, expires_at) VALUES (?, ?) ON CONFLICT(jti) DO NOTHING`,
 		jti, expiresAt,
 	)
 	return err
@@ -15,6 +15,10 @@ import (
 
 // GPU Reservation methods
 
+// gpuReservationColumns is the column list scanned by scanGPUReservation
+// and scanGPUReservationRow.
+const gpuReservationColumns = `id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at, notified_stage, activated_at`
+
 // encodeGPUTypes serializes the multi-type preference list into the JSON
 // form persisted in the gpu_reservations.gpu_types column (gpu-multitype). An
 // empty or nil list becomes an empty string, which is what the schema
@@ -62,12 +66,12 @@ func (s *SQLiteStore) CreateGPUReservation(ctx context.Context, reservation *mod
 	reservation.NormalizeGPUTypes()
 	gpuTypesEncoded := encodeGPUTypes(reservation.GPUTypes)
 
-	_, err := s.db.ExecContext(ctx, `INSERT INTO gpu_reservations (id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	_, err := s.db.ExecContext(ctx, `INSERT INTO gpu_reservations (id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, activated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		reservation.ID.String(), reservation.UserID.String(), reservation.UserName,
 		reservation.Title, reservation.Description, reservation.Cluster, reservation.Namespace,
 		reservation.GPUCount, reservation.GPUType, gpuTypesEncoded, reservation.StartDate, reservation.DurationHours,
 		reservation.Notes, string(reservation.Status), reservation.QuotaName,
-		boolToInt(reservation.QuotaEnforced), reservation.CreatedAt)
+		boolToInt(reservation.QuotaEnforced), reservation.CreatedAt, stampActivation(reservation, reservation.CreatedAt))
 	return err
 }
 
@@ -82,22 +86,23 @@ var ErrGPUQuotaExceeded = errors.New("gpu cluster capacity exceeded")
 var ErrGPUReservationNotFound = errors.New("gpu reservation not found")
 
 // CreateGPUReservationWithCapacity atomically enforces a cluster GPU
-// capacity cap and inserts the reservation in a single SQL statement.
+// capacity cap for the reservation's time window and inserts it.
 //
-// #6612: the previous flow was a two-step "SELECT SUM then INSERT", which
-// under WAL mode lets two concurrent creates both observe the same stale
-// reserved total, both proceed to insert, and push the cluster above its
-// declared capacity. This method mirrors the CreateCardWithLimit pattern:
-// INSERT ... SELECT ... WHERE (SELECT SUM(...) FROM ...) + ? <= capacity,
-// so SQLite evaluates the check and the insert under a single write lock
-// and a second concurrent insert cannot observe a pre-insert tally.
+// #6612: the check and the insert run in one BEGIN IMMEDIATE transaction so
+// two concurrent creates cannot both observe the same pre-insert tally and
+// push the cluster above its declared capacity. The tally is the peak
+// number of GPUs held at any instant of the new reservation's window by the
+// cluster's active and scheduled reservations, so bookings in disjoint
+// windows do not block each other. Undated legacy rows count against every
+// window.
 //
-// If capacity <= 0 the function behaves identically to CreateGPUReservation
+// If capacity <= 0, or the reservation does not hold capacity (e.g. it is
+// waitlisted), the function behaves identically to CreateGPUReservation
 // (capacity checks skipped — matches the existing handler semantics when
 // no capacity provider is configured).
 //
-// Returns ErrGPUQuotaExceeded when the insert is rejected by the WHERE
-// clause, so handlers can distinguish "over-allocated" from other errors.
+// Returns ErrGPUQuotaExceeded when the window is already full, so handlers
+// can distinguish "over-allocated" from other errors.
 func (s *SQLiteStore) CreateGPUReservationWithCapacity(ctx context.Context, reservation *models.GPUReservation, capacity int) error {
 	if reservation.ID == uuid.Nil {
 		reservation.ID = uuid.New()
@@ -106,7 +111,7 @@ func (s *SQLiteStore) CreateGPUReservationWithCapacity(ctx context.Context, rese
 	if reservation.Status == "" {
 		reservation.Status = models.ReservationStatusActive
 	}
-	if capacity <= 0 {
+	if capacity <= 0 || !reservation.Status.HoldsCapacity() {
 		// No capacity cap — fall through to the unchecked insert. Matches
 		// the existing ClusterCapacityProvider==nil handler behaviour.
 		return s.CreateGPUReservation(ctx, reservation)
@@ -114,32 +119,104 @@ func (s *SQLiteStore) CreateGPUReservationWithCapacity(ctx context.Context, rese
 	reservation.NormalizeGPUTypes()
 	gpuTypesEncoded := encodeGPUTypes(reservation.GPUTypes)
 
-	result, err := s.db.ExecContext(ctx,
-		`INSERT INTO gpu_reservations (id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at)
-		 SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-		 WHERE (COALESCE((SELECT SUM(gpu_count) FROM gpu_reservations WHERE cluster = ? AND status = 'active'), 0) + ?) <= ?`,
-		reservation.ID.String(), reservation.UserID.String(), reservation.UserName,
-		reservation.Title, reservation.Description, reservation.Cluster, reservation.Namespace,
-		reservation.GPUCount, reservation.GPUType, gpuTypesEncoded, reservation.StartDate, reservation.DurationHours,
-		reservation.Notes, string(reservation.Status), reservation.QuotaName,
-		boolToInt(reservation.QuotaEnforced), reservation.CreatedAt,
-		reservation.Cluster, reservation.GPUCount, capacity,
-	)
+	return s.withGPUCapacityCheck(ctx, reservation, capacity, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx,
+			`INSERT INTO gpu_reservations (id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, activated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			reservation.ID.String(), reservation.UserID.String(), reservation.UserName,
+			reservation.Title, reservation.Description, reservation.Cluster, reservation.Namespace,
+			reservation.GPUCount, reservation.GPUType, gpuTypesEncoded, reservation.StartDate, reservation.DurationHours,
+			reservation.Notes, string(reservation.Status), reservation.QuotaName,
+			boolToInt(reservation.QuotaEnforced), reservation.CreatedAt, stampActivation(reservation, reservation.CreatedAt),
+		); err != nil {
+			return fmt.Errorf("insert gpu reservation: %w", err)
+		}
+		return nil
+	})
+}
+
+// withGPUCapacityCheck runs write inside a BEGIN IMMEDIATE transaction
+// after verifying that reservation fits within capacity for its window,
+// returning ErrGPUQuotaExceeded when it does not. The write lock is taken
+// before the tally is read, so concurrent checked writes serialize.
+func (s *SQLiteStore) withGPUCapacityCheck(ctx context.Context, reservation *models.GPUReservation, capacity int, write func(conn *sql.Conn) error) error {
+	conn, err := s.db.Conn(ctx)
 	if err != nil {
-		return fmt.Errorf("insert gpu reservation: %w", err)
+		return fmt.Errorf("acquire conn: %w", err)
 	}
-	rows, err := result.RowsAffected()
+	defer conn.Close() //nolint:errcheck // best-effort release back to pool
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("begin immediate: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			rollbackConn(conn)
+		}
+	}()
+
+	holders, err := listGPUCapacityHolders(ctx, conn, reservation.Cluster)
 	if err != nil {
-		return fmt.Errorf("read rows affected: %w", err)
+		return err
 	}
-	if rows == 0 {
+	start, end := gpuReservationWindow(reservation)
+	if models.PeakReservedGPUs(holders, start, end, reservation.ID)+reservation.GPUCount > capacity {
 		return ErrGPUQuotaExceeded
 	}
+	if err := write(conn); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("commit immediate tx: %w", err)
+	}
+	committed = true
 	return nil
 }
 
+// gpuUndatedWindowEnd bounds the window checked for a reservation without
+// a parseable start date; such a reservation is checked against all time.
+var gpuUndatedWindowEnd = time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// gpuReservationWindow returns the window a capacity check covers.
+func gpuReservationWindow(r *models.GPUReservation) (time.Time, time.Time) {
+	if start, end, ok := r.Window(); ok {
+		return start, end
+	}
+	return time.Unix(0, 0).UTC(), gpuUndatedWindowEnd
+}
+
+// gpuQuerier is satisfied by *sql.DB and *sql.Conn.
+type gpuQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// listGPUCapacityHolders returns the cluster's active and scheduled
+// reservations. Terminal and waitlisted rows never count, so the set stays
+// small even on long-lived installs.
+func listGPUCapacityHolders(ctx context.Context, q gpuQuerier, cluster string) ([]models.GPUReservation, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, gpu_count, start_date, duration_hours, status FROM gpu_reservations WHERE cluster = ? AND status IN ('active', 'scheduled')`,
+		cluster)
+	if err != nil {
+		return nil, fmt.Errorf("list gpu capacity holders: %w", err)
+	}
+	defer rows.Close()
+	var out []models.GPUReservation
+	for rows.Next() {
+		var r models.GPUReservation
+		var idStr, status string
+		if err := rows.Scan(&idStr, &r.GPUCount, &r.StartDate, &r.DurationHours, &status); err != nil {
+			return nil, err
+		}
+		r.ID = parseUUID(idStr, "r.ID")
+		r.Status = models.ReservationStatus(status)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
 func (s *SQLiteStore) GetGPUReservation(ctx context.Context, id uuid.UUID) (*models.GPUReservation, error) {
-	row := s.db.QueryRowContext(ctx, `SELECT id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at FROM gpu_reservations WHERE id = ?`, id.String())
+	row := s.db.QueryRowContext(ctx, `SELECT `+gpuReservationColumns+` FROM gpu_reservations WHERE id = ?`, id.String())
 	return s.scanGPUReservation(ctx, row)
 }
 
@@ -154,7 +231,7 @@ func (s *SQLiteStore) ListGPUReservations(ctx context.Context) ([]models.GPURese
 	// #6604: bound the result set. The UI has no expectation of seeing
 	// more than a few hundred reservations at once; if an operator ever
 	// needs a full dump they can query the DB directly.
-	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at FROM gpu_reservations ORDER BY start_date DESC LIMIT ?`, gpuReservationsMaxRows)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+gpuReservationColumns+` FROM gpu_reservations ORDER BY start_date DESC LIMIT ?`, gpuReservationsMaxRows)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +250,7 @@ func (s *SQLiteStore) ListGPUReservations(ctx context.Context) ([]models.GPURese
 
 func (s *SQLiteStore) ListUserGPUReservations(ctx context.Context, userID uuid.UUID) ([]models.GPUReservation, error) {
 	// #6604: same defense-in-depth LIMIT as ListGPUReservations.
-	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at FROM gpu_reservations WHERE user_id = ? ORDER BY start_date DESC LIMIT ?`, userID.String(), gpuReservationsMaxRows)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+gpuReservationColumns+` FROM gpu_reservations WHERE user_id = ? ORDER BY start_date DESC LIMIT ?`, userID.String(), gpuReservationsMaxRows)
 	if err != nil {
 		return nil, err
 	}
@@ -194,65 +271,107 @@ func (s *SQLiteStore) UpdateGPUReservation(ctx context.Context, reservation *mod
 	now := time.Now()
 	reservation.UpdatedAt = &now
 	reservation.NormalizeGPUTypes()
-	gpuTypesEncoded := encodeGPUTypes(reservation.GPUTypes)
-
-	_, err := s.db.ExecContext(ctx, `UPDATE gpu_reservations SET user_name = ?, title = ?, description = ?, cluster = ?, namespace = ?, gpu_count = ?, gpu_type = ?, gpu_types = ?, start_date = ?, duration_hours = ?, notes = ?, status = ?, quota_name = ?, quota_enforced = ?, updated_at = ? WHERE id = ?`,
-		reservation.UserName, reservation.Title, reservation.Description,
-		reservation.Cluster, reservation.Namespace, reservation.GPUCount, reservation.GPUType, gpuTypesEncoded,
-		reservation.StartDate, reservation.DurationHours, reservation.Notes,
-		string(reservation.Status), reservation.QuotaName, boolToInt(reservation.QuotaEnforced),
-		reservation.UpdatedAt, reservation.ID.String())
+	_, err := s.db.ExecContext(ctx, gpuReservationUpdateSQL, gpuReservationUpdateArgs(reservation)...)
 	return err
 }
 
+// gpuReservationUpdateSQL writes every mutable column of a reservation.
+const gpuReservationUpdateSQL = `UPDATE gpu_reservations SET user_name = ?, title = ?, description = ?, cluster = ?, namespace = ?, gpu_count = ?, gpu_type = ?, gpu_types = ?, start_date = ?, duration_hours = ?, notes = ?, status = ?, quota_name = ?, quota_enforced = ?, notified_stage = ?, updated_at = ?, activated_at = COALESCE(activated_at, ?) WHERE id = ?`
+
+func gpuReservationUpdateArgs(r *models.GPUReservation) []interface{} {
+	return []interface{}{
+		r.UserName, r.Title, r.Description,
+		r.Cluster, r.Namespace, r.GPUCount, r.GPUType, encodeGPUTypes(r.GPUTypes),
+		r.StartDate, r.DurationHours, r.Notes,
+		string(r.Status), r.QuotaName, boolToInt(r.QuotaEnforced), string(r.NotifiedStage),
+		r.UpdatedAt, stampActivation(r, *r.UpdatedAt), r.ID.String(),
+	}
+}
+
+// stampActivation returns the activation time to record for a reservation
+// being written, or nil when it is not active. The SQL keeps the first
+// value it sees, so a later write cannot move ActivatedAt.
+func stampActivation(r *models.GPUReservation, now time.Time) interface{} {
+	if r.Status != models.ReservationStatusActive {
+		return nil
+	}
+	if r.ActivatedAt == nil {
+		r.ActivatedAt = &now
+	}
+	return now
+}
+
 // UpdateGPUReservationWithCapacity atomically enforces a cluster GPU capacity
-// cap when updating a reservation (#6957). The WHERE clause ensures the update
-// only succeeds if the cluster's total reserved GPUs (excluding this
-// reservation) plus the new count stays within the given capacity.
-// Returns ErrGPUQuotaExceeded when the capacity check fails.
+// cap for the reservation's window when updating it (#6957): the update only
+// succeeds if the peak GPUs held by the cluster's other active and scheduled
+// reservations during the window, plus the new count, stays within capacity.
+// Reservations that do not hold capacity (waitlisted, terminal) are written
+// unchecked. Returns ErrGPUQuotaExceeded when the capacity check fails.
 func (s *SQLiteStore) UpdateGPUReservationWithCapacity(ctx context.Context, reservation *models.GPUReservation, capacity int) error {
 	now := time.Now()
 	reservation.UpdatedAt = &now
 
-	if capacity <= 0 {
+	if capacity <= 0 || !reservation.Status.HoldsCapacity() {
 		return s.UpdateGPUReservation(ctx, reservation)
 	}
 	reservation.NormalizeGPUTypes()
-	gpuTypesEncoded := encodeGPUTypes(reservation.GPUTypes)
 
-	result, err := s.db.ExecContext(ctx,
-		`UPDATE gpu_reservations
-		 SET user_name = ?, title = ?, description = ?, cluster = ?, namespace = ?,
-		     gpu_count = ?, gpu_type = ?, gpu_types = ?, start_date = ?, duration_hours = ?,
-		     notes = ?, status = ?, quota_name = ?, quota_enforced = ?, updated_at = ?
-		 WHERE id = ?
-		   AND (COALESCE((SELECT SUM(gpu_count) FROM gpu_reservations
-		                   WHERE cluster = ? AND status = 'active' AND id != ?), 0) + ?) <= ?`,
-		reservation.UserName, reservation.Title, reservation.Description,
-		reservation.Cluster, reservation.Namespace, reservation.GPUCount, reservation.GPUType, gpuTypesEncoded,
-		reservation.StartDate, reservation.DurationHours, reservation.Notes,
-		string(reservation.Status), reservation.QuotaName, boolToInt(reservation.QuotaEnforced),
-		reservation.UpdatedAt, reservation.ID.String(),
-		reservation.Cluster, reservation.ID.String(), reservation.GPUCount, capacity,
-	)
+	return s.withGPUCapacityCheck(ctx, reservation, capacity, func(conn *sql.Conn) error {
+		result, err := conn.ExecContext(ctx, gpuReservationUpdateSQL, gpuReservationUpdateArgs(reservation)...)
+		if err != nil {
+			return fmt.Errorf("update gpu reservation: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("read rows affected: %w", err)
+		}
+		if rows == 0 {
+			return ErrGPUReservationNotFound
+		}
+		return nil
+	})
+}
+
+// gpuReservationTransitionSQL writes only the columns the lifecycle sweep
+// owns, and only while the row is still in the status the sweep read.
+const gpuReservationTransitionSQL = `UPDATE gpu_reservations SET status = ?, notified_stage = ?, quota_name = ?, quota_enforced = ?, updated_at = ?, activated_at = COALESCE(activated_at, ?) WHERE id = ? AND status = ?`
+
+// TransitionGPUReservation moves a reservation out of status from, writing
+// only its status, notified stage and quota columns. It reports false
+// without writing when the row has left from in the meantime (for example
+// the owner cancelled it), so a lifecycle sweep working from a stale read
+// can never overwrite a newer change. When capacity > 0 and the new status
+// holds capacity, the transition is also checked against the cluster's
+// capacity for the reservation's window, as UpdateGPUReservationWithCapacity
+// does, returning ErrGPUQuotaExceeded when it does not fit.
+func (s *SQLiteStore) TransitionGPUReservation(ctx context.Context, reservation *models.GPUReservation, from models.ReservationStatus, capacity int) (bool, error) {
+	now := time.Now()
+	reservation.UpdatedAt = &now
+	args := []interface{}{
+		string(reservation.Status), string(reservation.NotifiedStage), reservation.QuotaName,
+		boolToInt(reservation.QuotaEnforced), reservation.UpdatedAt, stampActivation(reservation, now),
+		reservation.ID.String(), string(from),
+	}
+
+	var result sql.Result
+	var err error
+	if capacity <= 0 || !reservation.Status.HoldsCapacity() {
+		result, err = s.db.ExecContext(ctx, gpuReservationTransitionSQL, args...)
+	} else {
+		err = s.withGPUCapacityCheck(ctx, reservation, capacity, func(conn *sql.Conn) error {
+			var execErr error
+			result, execErr = conn.ExecContext(ctx, gpuReservationTransitionSQL, args...)
+			return execErr
+		})
+	}
 	if err != nil {
-		return fmt.Errorf("update gpu reservation: %w", err)
+		return false, err
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("read rows affected: %w", err)
-	}
-	if rows == 0 {
-		// Distinguish "row not found" from "capacity exceeded": if the
-		// reservation does not exist at all, return ErrGPUReservationNotFound
-		// so the caller can map it to HTTP 404 instead of 409.
-		var exists int
-		if err := s.db.QueryRowContext(ctx, `SELECT 1 FROM gpu_reservations WHERE id = ?`, reservation.ID.String()).Scan(&exists); err != nil {
-			return ErrGPUReservationNotFound
-		}
-		return ErrGPUQuotaExceeded
+		return false, fmt.Errorf("read rows affected: %w", err)
 	}
-	return nil
+	return rows > 0, nil
 }
 
 func (s *SQLiteStore) DeleteGPUReservation(ctx context.Context, id uuid.UUID) error {
@@ -285,7 +404,7 @@ func (s *SQLiteStore) GetGPUReservationsByIDs(ctx context.Context, ids []uuid.UU
 		}
 
 		rows, err := s.db.QueryContext(ctx,
-			fmt.Sprintf(`SELECT id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at FROM gpu_reservations WHERE id IN (%s)`, placeholders),
+			fmt.Sprintf(`SELECT `+gpuReservationColumns+` FROM gpu_reservations WHERE id IN (%s)`, placeholders),
 			args...,
 		)
 		if err != nil {
@@ -307,36 +426,78 @@ func (s *SQLiteStore) GetGPUReservationsByIDs(ctx context.Context, ids []uuid.UU
 	return result, nil
 }
 
-// GetClusterReservedGPUCount returns the total GPU count for active reservations on a cluster.
-// If excludeID is provided, that reservation is excluded (useful for updates).
+// GetClusterReservedGPUCount returns the GPUs a cluster's active and
+// scheduled reservations hold right now: those whose window contains the
+// current time, plus undated legacy rows. If excludeID is provided, that
+// reservation is excluded (useful for updates).
 func (s *SQLiteStore) GetClusterReservedGPUCount(ctx context.Context, cluster string, excludeID *uuid.UUID) (int, error) {
-	var total int
-	var err error
+	now := time.Now()
+	return s.GetClusterPeakReservedGPUs(ctx, cluster, now, now.Add(time.Second), excludeID)
+}
+
+// GetClusterPeakReservedGPUs returns the most GPUs a cluster's active and
+// scheduled reservations hold at any instant of [start, end). If excludeID
+// is provided, that reservation is excluded.
+func (s *SQLiteStore) GetClusterPeakReservedGPUs(ctx context.Context, cluster string, start, end time.Time, excludeID *uuid.UUID) (int, error) {
+	holders, err := listGPUCapacityHolders(ctx, s.db, cluster)
+	if err != nil {
+		return 0, err
+	}
+	exclude := uuid.Nil
 	if excludeID != nil {
-		err = s.db.QueryRowContext(ctx,
-			`SELECT COALESCE(SUM(gpu_count), 0) FROM gpu_reservations WHERE cluster = ? AND status = 'active' AND id != ?`,
-			cluster, excludeID.String(),
-		).Scan(&total)
-	} else {
-		err = s.db.QueryRowContext(ctx,
-			`SELECT COALESCE(SUM(gpu_count), 0) FROM gpu_reservations WHERE cluster = ? AND status = 'active'`,
-			cluster,
-		).Scan(&total)
+		exclude = *excludeID
 	}
-	return total, err
+	return models.PeakReservedGPUs(holders, start, end, exclude), nil
+}
+
+// ListClusterGPUReservations returns the reservations in any of statuses,
+// oldest first so waitlisted reservations are promoted in arrival order.
+// An empty cluster matches every cluster.
+func (s *SQLiteStore) ListClusterGPUReservations(ctx context.Context, cluster string, statuses []models.ReservationStatus) ([]models.GPUReservation, error) {
+	if len(statuses) == 0 {
+		return []models.GPUReservation{}, nil
+	}
+	args := make([]interface{}, 0, len(statuses)+2)
+	for _, st := range statuses {
+		args = append(args, string(st))
+	}
+	query := `SELECT ` + gpuReservationColumns + ` FROM gpu_reservations WHERE status IN (` +
+		strings.TrimSuffix(strings.Repeat("?,", len(statuses)), ",") + `)`
+	if cluster != "" {
+		query += ` AND cluster = ?`
+		args = append(args, cluster)
+	}
+	query += ` ORDER BY created_at ASC LIMIT ?`
+	args = append(args, gpuReservationsMaxRows)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := make([]models.GPUReservation, 0)
+	for rows.Next() {
+		r, err := s.scanGPUReservationRow(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, *r)
+	}
+	return reservations, rows.Err()
 }
 
 func (s *SQLiteStore) scanGPUReservation(ctx context.Context, row *sql.Row) (*models.GPUReservation, error) {
 	var r models.GPUReservation
 	var idStr, userIDStr, status string
 	var quotaEnforced int
-	var updatedAt sql.NullTime
-	var gpuTypesRaw string
+	var updatedAt, activatedAt sql.NullTime
+	var gpuTypesRaw, notifiedStage string
 
 	err := row.Scan(&idStr, &userIDStr, &r.UserName, &r.Title, &r.Description,
 		&r.Cluster, &r.Namespace, &r.GPUCount, &r.GPUType, &gpuTypesRaw, &r.StartDate,
 		&r.DurationHours, &r.Notes, &status, &r.QuotaName, &quotaEnforced,
-		&r.CreatedAt, &updatedAt)
+		&r.CreatedAt, &updatedAt, &notifiedStage, &activatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -348,9 +509,13 @@ func (s *SQLiteStore) scanGPUReservation(ctx context.Context, row *sql.Row) (*mo
 	r.UserID = parseUUID(userIDStr, "r.UserID")
 	r.Status = models.ReservationStatus(status)
 	r.QuotaEnforced = quotaEnforced == 1
+	r.NotifiedStage = models.ReservationNotifyStage(notifiedStage)
 	if updatedAt.Valid {
 		r.UpdatedAt = &updatedAt.Time
 	}
+	if activatedAt.Valid {
+		r.ActivatedAt = &activatedAt.Time
+	}
 	// Decode multi-type list and promote legacy single-type
 	// reservations to a one-element list so callers always see a
 	// populated GPUTypes slice.
@@ -363,13 +528,13 @@ func (s *SQLiteStore) scanGPUReservationRow(ctx context.Context, rows *sql.Rows)
 	var r models.GPUReservation
 	var idStr, userIDStr, status string
 	var quotaEnforced int
-	var updatedAt sql.NullTime
-	var gpuTypesRaw string
+	var updatedAt, activatedAt sql.NullTime
+	var gpuTypesRaw, notifiedStage string
 
 	err := rows.Scan(&idStr, &userIDStr, &r.UserName, &r.Title, &r.Description,
 		&r.Cluster, &r.Namespace, &r.GPUCount, &r.GPUType, &gpuTypesRaw, &r.StartDate,
 		&r.DurationHours, &r.Notes, &status, &r.QuotaName, &quotaEnforced,
-		&r.CreatedAt, &updatedAt)
+		&r.CreatedAt, &updatedAt, &notifiedStage, &activatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -378,9 +543,13 @@ func (s *SQLiteStore) scanGPUReservationRow(ctx context.Context, rows *sql.Rows)
 	r.UserID = parseUUID(userIDStr, "r.UserID")
 	r.Status = models.ReservationStatus(status)
 	r.QuotaEnforced = quotaEnforced == 1
+	r.NotifiedStage = models.ReservationNotifyStage(notifiedStage)
 	if updatedAt.Valid {
 		r.UpdatedAt = &updatedAt.Time
 	}
+	if activatedAt.Valid {
+		r.ActivatedAt = &activatedAt.Time
+	}
 	// See scanGPUReservation — same normalization logic.
 	r.GPUTypes = decodeGPUTypes(gpuTypesRaw)
 	r.NormalizeGPUTypes()
@@ -499,7 +668,7 @@ func (s *SQLiteStore) queryUtilizationChunk(ctx context.Context, ids []string, r
 			SELECT *, ROW_NUMBER() OVER (PARTITION BY reservation_id ORDER BY timestamp ASC) AS rn
 			FROM gpu_utilization_snapshots
 			WHERE reservation_id IN (%s)
-		)
+		) AS ranked
 		WHERE rn <= ?
 		ORDER BY reservation_id, timestamp ASC`, placeholders)
 
@@ -521,6 +690,73 @@ func (s *SQLiteStore) queryUtilizationChunk(ctx context.Context, ids []string, r
 	return rows.Err()
 }
 
+// utilizationRangeMaxRows caps ListUtilizationSnapshotsInRange. At the
+// default 20-minute poll interval it covers a month of samples for roughly
+// 200 concurrently active reservations.
+const utilizationRangeMaxRows = 500000
+
+// ErrUtilizationRangeTooLarge is returned when a time range holds more
+// snapshots than ListUtilizationSnapshotsInRange will load; callers should
+// narrow the range rather than report on a silently truncated sample.
+var ErrUtilizationRangeTooLarge = errors.New("too many utilization snapshots in range")
+
+// ListUtilizationSnapshotsInRange returns every snapshot taken in
+// [start, end), ordered by reservation and then time.
+func (s *SQLiteStore) ListUtilizationSnapshotsInRange(ctx context.Context, start, end time.Time) ([]models.GPUUtilizationSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, reservation_id, timestamp, gpu_utilization_pct, memory_utilization_pct, active_gpu_count, total_gpu_count
+		FROM gpu_utilization_snapshots
+		WHERE timestamp >= ? AND timestamp < ?
+		ORDER BY reservation_id, timestamp ASC
+		LIMIT ?`,
+		start, end, utilizationRangeMaxRows+1,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]models.GPUUtilizationSnapshot, 0)
+	for rows.Next() {
+		if len(snapshots) == utilizationRangeMaxRows {
+			return nil, ErrUtilizationRangeTooLarge
+		}
+		var snap models.GPUUtilizationSnapshot
+		if err := rows.Scan(&snap.ID, &snap.ReservationID, &snap.Timestamp,
+			&snap.GPUUtilizationPct, &snap.MemoryUtilizationPct,
+			&snap.ActiveGPUCount, &snap.TotalGPUCount); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
+// ClaimGPUChargebackRun records that the report for period is being sent
+// and reports whether this caller claimed it; false means another run (or
+// replica) already has.
+func (s *SQLiteStore) ClaimGPUChargebackRun(ctx context.Context, period string) (bool, error) {
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO gpu_chargeback_runs (period, claimed_at) VALUES (?, ?) ON CONFLICT(period) DO NOTHING`,
+		period, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseGPUChargebackRun drops the claim on period so a failed send is
+// retried.
+func (s *SQLiteStore) ReleaseGPUChargebackRun(ctx context.Context, period string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM gpu_chargeback_runs WHERE period = ?`, period)
+	return err
+}
+
 func (s *SQLiteStore) DeleteOldUtilizationSnapshots(ctx context.Context, before time.Time) (int64, error) {
 	res, err := s.db.ExecContext(ctx, `DELETE FROM gpu_utilization_snapshots WHERE timestamp < ?`, before)
 	if err != nil {
@@ -532,7 +768,7 @@ func (s *SQLiteStore) DeleteOldUtilizationSnapshots(ctx context.Context, before
 func (s *SQLiteStore) ListActiveGPUReservations(ctx context.Context) ([]models.GPUReservation, error) {
 	// #6604: same defense-in-depth LIMIT as ListGPUReservations.
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, gpu_types, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at FROM gpu_reservations WHERE status = 'active' ORDER BY start_date DESC LIMIT ?`,
+		`SELECT `+gpuReservationColumns+` FROM gpu_reservations WHERE status = 'active' ORDER BY start_date DESC LIMIT ?`,
 		gpuReservationsMaxRows,
 	)
 	if err != nil {
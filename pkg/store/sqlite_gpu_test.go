@@ -79,6 +79,121 @@ func TestGPUReservationCRUD(t *testing.T) {
 		require.ErrorIs(t, err, ErrGPUQuotaExceeded)
 	})
 
+	t.Run("CreateGPUReservationWithCapacity only counts overlapping windows", func(t *testing.T) {
+		start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+		reserve := func(title string, offset time.Duration, gpus int) error {
+			return s.CreateGPUReservationWithCapacity(ctx, &models.GPUReservation{
+				UserID:        user.ID,
+				UserName:      user.GitHubLogin,
+				Title:         title,
+				Cluster:       "cal-cluster",
+				Namespace:     "default",
+				GPUCount:      gpus,
+				StartDate:     start.Add(offset).Format(time.RFC3339),
+				DurationHours: 8,
+				Status:        models.ReservationStatusScheduled,
+			}, 8)
+		}
+		require.NoError(t, reserve("Morning", 0, 8))
+		// Back-to-back window: the first one has ended, so all 8 are free.
+		require.NoError(t, reserve("Evening", 8*time.Hour, 8))
+		// Straddles both windows.
+		require.ErrorIs(t, reserve("Overlap", 4*time.Hour, 1), ErrGPUQuotaExceeded)
+
+		peak, err := s.GetClusterPeakReservedGPUs(ctx, "cal-cluster", start, start.Add(16*time.Hour), nil)
+		require.NoError(t, err)
+		require.Equal(t, 8, peak)
+		peak, err = s.GetClusterPeakReservedGPUs(ctx, "cal-cluster", start.Add(16*time.Hour), start.Add(24*time.Hour), nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, peak)
+	})
+
+	t.Run("TransitionGPUReservation only applies from the expected status", func(t *testing.T) {
+		res := &models.GPUReservation{
+			UserID:    user.ID,
+			UserName:  user.GitHubLogin,
+			Title:     "Sweep Job",
+			Cluster:   "sweep-cluster",
+			Namespace: "default",
+			GPUCount:  2,
+			Status:    models.ReservationStatusScheduled,
+		}
+		require.NoError(t, s.CreateGPUReservation(ctx, res))
+
+		// The owner cancels after the sweep read the row as scheduled.
+		cancelled := *res
+		cancelled.Status = models.ReservationStatusCancelled
+		cancelled.Title = "Renamed"
+		require.NoError(t, s.UpdateGPUReservation(ctx, &cancelled))
+
+		sweep := *res
+		sweep.Status = models.ReservationStatusActive
+		sweep.QuotaEnforced = true
+		applied, err := s.TransitionGPUReservation(ctx, &sweep, models.ReservationStatusScheduled, 0)
+		require.NoError(t, err)
+		require.False(t, applied)
+		got, err := s.GetGPUReservation(ctx, res.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.ReservationStatusCancelled, got.Status)
+		require.False(t, got.QuotaEnforced)
+		require.Nil(t, got.ActivatedAt, "never-active reservation has no activation time")
+
+		applied, err = s.TransitionGPUReservation(ctx, &sweep, models.ReservationStatusCancelled, 0)
+		require.NoError(t, err)
+		require.True(t, applied)
+		got, err = s.GetGPUReservation(ctx, res.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.ReservationStatusActive, got.Status)
+		require.Equal(t, "Renamed", got.Title, "only lifecycle columns are written")
+		require.NotNil(t, got.ActivatedAt)
+		activatedAt := *got.ActivatedAt
+
+		sweep.Status = models.ReservationStatusCompleted
+		applied, err = s.TransitionGPUReservation(ctx, &sweep, models.ReservationStatusActive, 0)
+		require.NoError(t, err)
+		require.True(t, applied)
+		got, err = s.GetGPUReservation(ctx, res.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got.ActivatedAt)
+		require.True(t, activatedAt.Equal(*got.ActivatedAt), "activation time is kept on release")
+	})
+
+	t.Run("TransitionGPUReservation enforces capacity", func(t *testing.T) {
+		holder := &models.GPUReservation{UserID: user.ID, Title: "Holder", Cluster: "promo-cluster", GPUCount: 6, Status: models.ReservationStatusActive}
+		require.NoError(t, s.CreateGPUReservation(ctx, holder))
+		queued := &models.GPUReservation{UserID: user.ID, Title: "Queued", Cluster: "promo-cluster", GPUCount: 4, Status: models.ReservationStatusWaitlisted}
+		require.NoError(t, s.CreateGPUReservation(ctx, queued))
+
+		queued.Status = models.ReservationStatusScheduled
+		_, err := s.TransitionGPUReservation(ctx, queued, models.ReservationStatusWaitlisted, 8)
+		require.ErrorIs(t, err, ErrGPUQuotaExceeded)
+		applied, err := s.TransitionGPUReservation(ctx, queued, models.ReservationStatusWaitlisted, 10)
+		require.NoError(t, err)
+		require.True(t, applied)
+	})
+
+	t.Run("ListClusterGPUReservations filters by cluster and status", func(t *testing.T) {
+		for _, status := range []models.ReservationStatus{models.ReservationStatusWaitlisted, models.ReservationStatusCancelled} {
+			require.NoError(t, s.CreateGPUReservation(ctx, &models.GPUReservation{
+				UserID:   user.ID,
+				UserName: user.GitHubLogin,
+				Title:    "List " + string(status),
+				Cluster:  "list-cluster",
+				GPUCount: 1,
+				Status:   status,
+			}))
+		}
+
+		list, err := s.ListClusterGPUReservations(ctx, "list-cluster", []models.ReservationStatus{models.ReservationStatusWaitlisted})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "List waitlisted", list[0].Title)
+
+		all, err := s.ListClusterGPUReservations(ctx, "", []models.ReservationStatus{models.ReservationStatusWaitlisted, models.ReservationStatusCancelled})
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
 	t.Run("ListUserGPUReservations returns user's reservations", func(t *testing.T) {
 		u2 := createTestUser(t, s, "gh-gpu-2", "gpuuser2")
 		require.NoError(t, s.CreateGPUReservation(ctx, &models.GPUReservation{
@@ -157,4 +272,38 @@ func TestGPUUtilizationSnapshots(t *testing.T) {
 		require.Len(t, bulk[resAID], 2)
 		require.Len(t, bulk[resBID], 1)
 	})
+
+	t.Run("ListUtilizationSnapshotsInRange bounds by time", func(t *testing.T) {
+		base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+		for h := 0; h < 4; h++ {
+			require.NoError(t, s.InsertUtilizationSnapshot(ctx, &models.GPUUtilizationSnapshot{
+				ReservationID:  resID,
+				Timestamp:      base.Add(time.Duration(h) * time.Hour),
+				ActiveGPUCount: h,
+			}))
+		}
+
+		snaps, err := s.ListUtilizationSnapshotsInRange(ctx, base.Add(time.Hour), base.Add(3*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, snaps, 2)
+		require.Equal(t, 1, snaps[0].ActiveGPUCount)
+		require.Equal(t, 2, snaps[1].ActiveGPUCount)
+	})
+}
+
+func TestGPUChargebackRunClaims(t *testing.T) {
+	s := newTestStore(t)
+
+	claimed, err := s.ClaimGPUChargebackRun(ctx, "2026-09")
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	claimed, err = s.ClaimGPUChargebackRun(ctx, "2026-09")
+	require.NoError(t, err)
+	require.False(t, claimed, "a period is claimed once")
+
+	require.NoError(t, s.ReleaseGPUChargebackRun(ctx, "2026-09"))
+	claimed, err = s.ClaimGPUChargebackRun(ctx, "2026-09")
+	require.NoError(t, err)
+	require.True(t, claimed, "a released period can be claimed again")
 }
@@ -72,6 +72,10 @@ func (s *SQLiteStore) SweepOldKBGaps(ctx context.Context) (int64, error) {
 }
 
 func (s *SQLiteStore) migrateKBGapsSchema(ctx context.Context) error {
+	// PostgreSQL databases never had the legacy per-query layout.
+	if s.dialect == dialectPostgres {
+		return s.ensureKBGapsIndexes(ctx)
+	}
 	columns, err := s.getKBGapColumns(ctx)
 	if err != nil {
 		return err
@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+const notificationColumns = `id, target, channel, group_key, alerts, status, attempts, next_attempt, last_error, created_at, updated_at, claimed_until`
+
+// SaveNotification creates or replaces a queued notification delivery.
+func (s *SQLiteStore) SaveNotification(ctx context.Context, d NotificationDelivery) error {
+	var claimedUntil any
+	if !d.ClaimedUntil.IsZero() {
+		claimedUntil = d.ClaimedUntil.UTC()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO notification_deliveries (`+notificationColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET
+			alerts = excluded.alerts, status = excluded.status, attempts = excluded.attempts,
+			next_attempt = excluded.next_attempt, last_error = excluded.last_error,
+			updated_at = excluded.updated_at, claimed_until = excluded.claimed_until`,
+		d.ID, d.Target, d.Channel, d.GroupKey, d.Alerts, d.Status, d.Attempts,
+		d.NextAttempt.UTC(), d.LastError, d.CreatedAt.UTC(), d.UpdatedAt.UTC(), claimedUntil,
+	)
+	return err
+}
+
+// GetNotification returns one delivery, or nil if it does not exist.
+func (s *SQLiteStore) GetNotification(ctx context.Context, id string) (*NotificationDelivery, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+notificationColumns+` FROM notification_deliveries WHERE id = ?`, id)
+	d, err := scanNotification(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ClaimDueNotifications claims pending deliveries ready to be attempted
+// until the given time and returns them. The outer conditions repeat the
+// subquery's so that on PostgreSQL a replica blocked on a row another one
+// just claimed re-checks it and skips it.
+func (s *SQLiteStore) ClaimDueNotifications(ctx context.Context, now, until time.Time, limit int) ([]NotificationDelivery, error) {
+	found, err := s.queryNotifications(ctx,
+		`UPDATE notification_deliveries SET claimed_until = ?
+		 WHERE status = 'pending' AND next_attempt <= ? AND (claimed_until IS NULL OR claimed_until <= ?)
+		   AND id IN (
+			SELECT id FROM notification_deliveries
+			WHERE status = 'pending' AND next_attempt <= ? AND (claimed_until IS NULL OR claimed_until <= ?)
+			ORDER BY next_attempt, id LIMIT ?)
+		 RETURNING `+notificationColumns,
+		until.UTC(), now.UTC(), now.UTC(), now.UTC(), now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING does not honour the subquery's order.
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].NextAttempt.Equal(found[j].NextAttempt) {
+			return found[i].NextAttempt.Before(found[j].NextAttempt)
+		}
+		return found[i].ID < found[j].ID
+	})
+	return found, nil
+}
+
+// FindNotificationGroup returns the delivery still collecting alerts for
+// target and groupKey, or nil.
+func (s *SQLiteStore) FindNotificationGroup(ctx context.Context, target, groupKey string, now time.Time) (*NotificationDelivery, error) {
+	found, err := s.queryNotifications(ctx,
+		`SELECT `+notificationColumns+` FROM notification_deliveries
+		 WHERE target = ? AND group_key = ? AND status = 'pending' AND attempts = 0 AND next_attempt > ?
+		 ORDER BY created_at LIMIT 1`,
+		target, groupKey, now.UTC())
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// UpdateNotificationGroup replaces the alerts of a delivery that is still
+// collecting them, reporting false once it has become due or been claimed.
+func (s *SQLiteStore) UpdateNotificationGroup(ctx context.Context, id string, alerts []byte, now time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE notification_deliveries SET alerts = ?, updated_at = ?
+		 WHERE id = ? AND status = 'pending' AND attempts = 0 AND next_attempt > ? AND claimed_until IS NULL`,
+		alerts, now.UTC(), id, now.UTC())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// PendingNotifications returns the pending deliveries for target and
+// groupKey, oldest first.
+func (s *SQLiteStore) PendingNotifications(ctx context.Context, target, groupKey string) ([]NotificationDelivery, error) {
+	return s.queryNotifications(ctx,
+		`SELECT `+notificationColumns+` FROM notification_deliveries
+		 WHERE target = ? AND group_key = ? AND status = 'pending' ORDER BY created_at, id`,
+		target, groupKey)
+}
+
+// ListNotifications returns the most recent deliveries, newest first,
+// limited to status when it is not empty.
+func (s *SQLiteStore) ListNotifications(ctx context.Context, status string, limit int) ([]NotificationDelivery, error) {
+	return s.queryNotifications(ctx,
+		`SELECT `+notificationColumns+` FROM notification_deliveries
+		 WHERE (? = '' OR status = ?) ORDER BY created_at DESC, id LIMIT ?`,
+		status, status, limit)
+}
+
+// PruneNotifications deletes finished deliveries and resolved alert
+// records older than before.
+func (s *SQLiteStore) PruneNotifications(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM notification_deliveries WHERE status != 'pending' AND updated_at < ?`, before.UTC())
+		if err != nil {
+			return err
+		}
+		if deleted, err = res.RowsAffected(); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM notified_alerts WHERE status = 'resolved' AND notified_at < ?`, before.UTC())
+		return err
+	})
+	return deleted, err
+}
+
+func (s *SQLiteStore) queryNotifications(ctx context.Context, query string, args ...any) ([]NotificationDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []NotificationDelivery
+	for rows.Next() {
+		d, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func scanNotification(row interface{ Scan(...any) error }) (NotificationDelivery, error) {
+	var d NotificationDelivery
+	var claimedUntil sql.NullTime
+	err := row.Scan(&d.ID, &d.Target, &d.Channel, &d.GroupKey, &d.Alerts, &d.Status, &d.Attempts,
+		&d.NextAttempt, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &claimedUntil)
+	if claimedUntil.Valid {
+		d.ClaimedUntil = claimedUntil.Time
+	}
+	return d, err
+}
+
+// GetNotifiedAlert returns the last notified status of an alert, or nil.
+func (s *SQLiteStore) GetNotifiedAlert(ctx context.Context, fingerprint string) (*NotifiedAlert, error) {
+	var a NotifiedAlert
+	err := s.db.QueryRowContext(ctx,
+		`SELECT fingerprint, status, notified_at FROM notified_alerts WHERE fingerprint = ?`, fingerprint,
+	).Scan(&a.Fingerprint, &a.Status, &a.NotifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SaveNotifiedAlert records the status most recently notified for an alert.
+func (s *SQLiteStore) SaveNotifiedAlert(ctx context.Context, a NotifiedAlert) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO notified_alerts (fingerprint, status, notified_at) VALUES (?, ?, ?)
+		 ON CONFLICT(fingerprint) DO UPDATE SET status = excluded.status, notified_at = excluded.notified_at`,
+		a.Fingerprint, a.Status, a.NotifiedAt.UTC(),
+	)
+	return err
+}
+
+// ListSilences returns every silence document, soonest ending first.
+func (s *SQLiteStore) ListSilences(ctx context.Context) ([][]byte, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM notification_silences ORDER BY ends_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out [][]byte
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		out = append(out, data)
+	}
+	return out, rows.Err()
+}
+
+// SaveSilence creates or replaces a silence.
+func (s *SQLiteStore) SaveSilence(ctx context.Context, id string, data []byte, endsAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO notification_silences (id, data, ends_at) VALUES (?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET data = excluded.data, ends_at = excluded.ends_at`,
+		id, data, endsAt.UTC(),
+	)
+	return err
+}
+
+// DeleteSilence removes a silence.
+func (s *SQLiteStore) DeleteSilence(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM notification_silences WHERE id = ?`, id)
+	return err
+}
+
+// PruneSilences deletes silences that ended before the cutoff.
+func (s *SQLiteStore) PruneSilences(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM notification_silences WHERE ends_at < ?`, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -11,7 +11,8 @@ import (
 // (single-row table enforced by CHECK constraint).
 func (s *SQLiteStore) SaveOAuthCredentials(ctx context.Context, clientID, clientSecret string) error {
 	_, err := s.db.ExecContext(ctx,
-		`INSERT OR REPLACE INTO oauth_credentials (id, client_id, client_secret) VALUES (1, ?, ?)`,
+		`INSERT INTO oauth_credentials (id, client_id, client_secret) VALUES (1, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET client_id = excluded.client_id, client_secret = excluded.client_secret, created_at = CURRENT_TIMESTAMP`,
 		clientID, clientSecret)
 	return err
 }
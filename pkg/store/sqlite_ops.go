@@ -2,7 +2,11 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -47,6 +51,27 @@ func (s *SQLiteStore) ListClusterGroups(ctx context.Context) (map[string][]byte,
 	return groups, rows.Err()
 }
 
+// GetLicensePolicy returns the saved license policy document, or nil if
+// none has been saved (#9648).
+func (s *SQLiteStore) GetLicensePolicy(ctx context.Context) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM license_policy WHERE id = 1`).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return data, err
+}
+
+// SaveLicensePolicy replaces the license policy document (#9648).
+func (s *SQLiteStore) SaveLicensePolicy(ctx context.Context, data []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO license_policy (id, data, updated_at) VALUES (1, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(id) DO UPDATE SET data = excluded.data, updated_at = CURRENT_TIMESTAMP`,
+		data,
+	)
+	return err
+}
+
 // ---------------------------------------------------------------------------
 // Audit Log (#8670 Phase 3)
 // ---------------------------------------------------------------------------
@@ -58,13 +83,78 @@ const maxAuditQueryLimit = 200
 // defaultAuditQueryLimit is used when the caller passes 0 for limit.
 const defaultAuditQueryLimit = 50
 
-// InsertAuditLog appends an audit entry to the audit_log table.
+// AuditEntryHash returns the chain hash of e: SHA-256 over its ID,
+// timestamp, user, action, detail and PrevHash. Fields are JSON-encoded so
+// no value can shift content into a neighbouring field.
+func AuditEntryHash(e AuditEntry) string {
+	canonical, _ := json.Marshal([]interface{}{e.ID, e.Timestamp, e.UserID, e.Action, e.Detail, e.PrevHash})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// InsertAuditLog appends an audit entry to the audit_log table, chained to
+// the newest existing row. The write lock is taken before reading the
+// chain head so concurrent writers (other goroutines or, on PostgreSQL,
+// other replicas) cannot fork the chain.
 func (s *SQLiteStore) InsertAuditLog(ctx context.Context, userID, action, detail string) error {
-	_, err := s.db.ExecContext(ctx,
-		`INSERT INTO audit_log (timestamp, user_id, action, detail) VALUES (?, ?, ?, ?)`,
-		time.Now().UTC().Format(time.RFC3339), userID, action, detail,
-	)
-	return err
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire conn: %w", err)
+	}
+	defer conn.Close() //nolint:errcheck // best-effort release back to pool
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("begin immediate: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			rollbackConn(conn)
+		}
+	}()
+
+	e := AuditEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		UserID:    userID,
+		Action:    action,
+		Detail:    detail,
+	}
+	err = conn.QueryRowContext(ctx,
+		`SELECT id, hash FROM audit_log ORDER BY id DESC LIMIT 1`,
+	).Scan(&e.ID, &e.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("read audit chain head: %w", err)
+	}
+	e.ID++
+	e.Hash = AuditEntryHash(e)
+
+	if _, err := conn.ExecContext(ctx,
+		`INSERT INTO audit_log (id, timestamp, user_id, action, detail, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.Timestamp, e.UserID, e.Action, e.Detail, e.PrevHash, e.Hash,
+	); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("commit immediate tx: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// auditEntryColumns is the column list scanned by scanAuditEntries.
+const auditEntryColumns = `id, timestamp, user_id, action, COALESCE(detail, ''), prev_hash, hash`
+
+func scanAuditEntries(rows *sql.Rows) ([]AuditEntry, error) {
+	defer rows.Close()
+	entries := make([]AuditEntry, 0)
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.UserID, &e.Action, &e.Detail, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
 }
 
 // QueryAuditLogs returns recent audit entries, newest first. Empty userID or
@@ -78,7 +168,7 @@ func (s *SQLiteStore) QueryAuditLogs(ctx context.Context, limit int, userID, act
 		limit = maxAuditQueryLimit
 	}
 
-	query := `SELECT id, timestamp, user_id, action, COALESCE(detail, '') FROM audit_log`
+	query := `SELECT ` + auditEntryColumns + ` FROM audit_log`
 	args := make([]interface{}, 0)
 	clauses := make([]string, 0)
 
@@ -100,17 +190,63 @@ func (s *SQLiteStore) QueryAuditLogs(ctx context.Context, limit int, userID, act
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return scanAuditEntries(rows)
+}
 
-	entries := make([]AuditEntry, 0)
+// maxAuditChainPage caps one ListAuditChain page.
+const maxAuditChainPage = 1000
+
+// ListAuditChain returns up to limit audit entries with an ID above afterID,
+// oldest first. Limit is clamped to maxAuditChainPage.
+func (s *SQLiteStore) ListAuditChain(ctx context.Context, afterID int64, limit int) ([]AuditEntry, error) {
+	if limit <= 0 || limit > maxAuditChainPage {
+		limit = maxAuditChainPage
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+auditEntryColumns+` FROM audit_log WHERE id > ? ORDER BY id ASC LIMIT ?`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanAuditEntries(rows)
+}
+
+// AuditLegacyCutoff returns the highest audit_log ID written before the
+// hash chain existed, 0 when every row is chained.
+func (s *SQLiteStore) AuditLegacyCutoff(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(legacy_cutoff_id), 0) FROM audit_chain_meta`,
+	).Scan(&id)
+	return id, err
+}
+
+// InsertAuditCheckpoint stores a signed chain checkpoint and sets cp.ID.
+func (s *SQLiteStore) InsertAuditCheckpoint(ctx context.Context, cp *AuditCheckpoint) error {
+	return s.db.QueryRowContext(ctx,
+		`INSERT INTO audit_checkpoints (last_id, hash, prev_hash, key_id, signature, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id`,
+		cp.LastID, cp.Hash, cp.PrevHash, cp.KeyID, cp.Signature, cp.CreatedAt.UTC(),
+	).Scan(&cp.ID)
+}
+
+// ListAuditCheckpoints returns every audit chain checkpoint, oldest first.
+func (s *SQLiteStore) ListAuditCheckpoints(ctx context.Context) ([]AuditCheckpoint, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, last_id, hash, prev_hash, key_id, signature, created_at FROM audit_checkpoints ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]AuditCheckpoint, 0)
 	for rows.Next() {
-		var e AuditEntry
-		if err := rows.Scan(&e.ID, &e.Timestamp, &e.UserID, &e.Action, &e.Detail); err != nil {
+		var cp AuditCheckpoint
+		if err := rows.Scan(&cp.ID, &cp.LastID, &cp.Hash, &cp.PrevHash, &cp.KeyID, &cp.Signature, &cp.CreatedAt); err != nil {
 			return nil, err
 		}
-		entries = append(entries, e)
+		out = append(out, cp)
 	}
-	return entries, rows.Err()
+	return out, rows.Err()
 }
 
 // ---------------------------------------------------------------------------
@@ -31,6 +31,20 @@ func TestClusterGroupCRUD(t *testing.T) {
 	})
 }
 
+func TestLicensePolicyRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+
+	data, err := s.GetLicensePolicy(ctx)
+	require.NoError(t, err)
+	require.Nil(t, data, "no policy saved yet")
+
+	require.NoError(t, s.SaveLicensePolicy(ctx, []byte(`{"deny":["GPL-*"]}`)))
+	require.NoError(t, s.SaveLicensePolicy(ctx, []byte(`{"deny":["AGPL-*"]}`)))
+	data, err = s.GetLicensePolicy(ctx)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"deny":["AGPL-*"]}`, string(data))
+}
+
 func TestAuditLogCRUD(t *testing.T) {
 	s := newTestStore(t)
 	userID := uuid.New().String()
@@ -528,5 +528,54 @@ func (s *SQLiteStore) AddUserTokenDelta(ctx context.Context, userID string, cate
 	return current, nil
 }
 
+// GetAIBudgetUsage returns the per-category router spend recorded for userID
+// on day. A user with no spend gets an empty map.
+func (s *SQLiteStore) GetAIBudgetUsage(ctx context.Context, userID, day string) (map[string]int64, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT category, tokens FROM ai_budget_usage WHERE user_id = ? AND day = ?`, userID, day)
+	if err != nil {
+		return nil, fmt.Errorf("get ai budget usage: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[string]int64)
+	for rows.Next() {
+		var category string
+		var tokens int64
+		if err := rows.Scan(&category, &tokens); err != nil {
+			return nil, fmt.Errorf("scan ai budget usage: %w", err)
+		}
+		out[category] = tokens
+	}
+	return out, rows.Err()
+}
+
+// AddAIBudgetUsage adds tokens to userID's spend in category on day. The
+// increment is a single upsert, so concurrent requests cannot lose spend.
+// Counters from earlier days are dropped on the way, which keeps the table
+// at one day per user.
+func (s *SQLiteStore) AddAIBudgetUsage(ctx context.Context, userID, day, category string, tokens int64) error {
+	if userID == "" || category == "" {
+		return errors.New("user_id and category are required")
+	}
+	if tokens < 0 {
+		return errors.New("tokens must be non-negative")
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO ai_budget_usage (user_id, day, category, tokens)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id, day, category) DO UPDATE SET
+		   tokens = ai_budget_usage.tokens + excluded.tokens`,
+		userID, day, category, tokens,
+	); err != nil {
+		return fmt.Errorf("add ai budget usage: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM ai_budget_usage WHERE user_id = ? AND day < ?`, userID, day,
+	); err != nil {
+		return fmt.Errorf("prune ai budget usage: %w", err)
+	}
+	return nil
+}
+
 // OAuth State methods — persist OAuth state tokens so in-flight logins survive
 // a backend restart between /auth/login and /auth/callback (issue #6028).
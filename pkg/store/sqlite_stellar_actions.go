@@ -109,7 +109,7 @@ func (s *SQLiteStore) DeleteStellarAction(ctx context.Context, userID, actionID
 func (s *SQLiteStore) CompleteDueStellarActions(ctx context.Context, now time.Time) ([]StellarAction, error) {
 	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, description, action_type, parameters, cluster, namespace, scheduled_at, cron_expr, status, approved_by, approved_at, executed_at, outcome, reject_reason, created_by, created_at
 		FROM stellar_actions
-		WHERE status = 'approved' AND scheduled_at IS NOT NULL AND scheduled_at <= ?
+		WHERE status = 'approved' AND cron_expr = '' AND scheduled_at IS NOT NULL AND scheduled_at <= ?
 		ORDER BY scheduled_at ASC
 		LIMIT 50`, now.UTC())
 	if err != nil {
@@ -147,7 +147,7 @@ func (s *SQLiteStore) GetDueApprovedStellarActions(ctx context.Context, now time
 	}
 	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, description, action_type, parameters, cluster, namespace, scheduled_at, cron_expr, status, approved_by, approved_at, executed_at, outcome, reject_reason, created_by, created_at
 		FROM stellar_actions
-		WHERE status = 'approved' AND (scheduled_at IS NULL OR scheduled_at <= ?)
+		WHERE status = 'approved' AND cron_expr = '' AND (scheduled_at IS NULL OR scheduled_at <= ?)
 		ORDER BY COALESCE(scheduled_at, approved_at, created_at) ASC
 		LIMIT ?`, now.UTC(), limit)
 	if err != nil {
@@ -169,6 +169,64 @@ func (s *SQLiteStore) GetDueApprovedStellarActions(ctx context.Context, now time
 }
 
 
+// GetDueCronStellarActions returns approved recurring actions (cron_expr set)
+// whose scheduled_at has arrived, or that have never been scheduled. One-shot
+// actions are excluded — those are handled by GetDueApprovedStellarActions.
+func (s *SQLiteStore) GetDueCronStellarActions(ctx context.Context, now time.Time, limit int) ([]StellarAction, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, description, action_type, parameters, cluster, namespace, scheduled_at, cron_expr, status, approved_by, approved_at, executed_at, outcome, reject_reason, created_by, created_at
+		FROM stellar_actions
+		WHERE status = 'approved' AND cron_expr != '' AND (scheduled_at IS NULL OR scheduled_at <= ?)
+		ORDER BY COALESCE(scheduled_at, approved_at, created_at) ASC
+		LIMIT ?`, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results := make([]StellarAction, 0)
+	for rows.Next() {
+		action, scanErr := scanStellarActionRow(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		if action == nil {
+			continue
+		}
+		results = append(results, *action)
+	}
+	return results, rows.Err()
+}
+
+// RescheduleStellarAction re-arms a recurring action for its next fire time.
+// The action returns to 'approved' with a fresh retry budget; rejected
+// actions are left alone so a reject always stops the recurrence.
+func (s *SQLiteStore) RescheduleStellarAction(ctx context.Context, actionID string, next time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE stellar_actions
+		SET status = 'approved', scheduled_at = ?, retry_count = 0, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status != 'rejected'`,
+		next.UTC(), actionID)
+	return err
+}
+
+
+// RearmStaleCronStellarActions returns recurring actions stuck in 'running'
+// since before staleBefore — the process running them died — to 'approved'.
+// Their scheduled_at is left as is, so the cron engine treats the lost run
+// as missed and applies the missed-run policy. It returns how many actions
+// were re-armed.
+func (s *SQLiteStore) RearmStaleCronStellarActions(ctx context.Context, staleBefore time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `UPDATE stellar_actions
+		SET status = 'approved', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'running' AND cron_expr != '' AND (started_at IS NULL OR started_at < ?)`,
+		staleBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 func (s *SQLiteStore) UpdateStellarActionStatus(ctx context.Context, actionID, status, outcome, rejectReason string) error {
 	now := time.Now().UTC()
 	switch status {
@@ -185,10 +243,12 @@ func (s *SQLiteStore) UpdateStellarActionStatus(ctx context.Context, actionID, s
 			status, rejectReason, now, now, actionID)
 		return err
 	case "running":
+		// started_at is the run's lease; RearmStaleCronStellarActions
+		// re-arms recurring actions whose lease has expired.
 		_, err := s.db.ExecContext(ctx, `UPDATE stellar_actions
-			SET status = ?, outcome = '', reject_reason = ''
+			SET status = ?, outcome = '', reject_reason = '', started_at = ?
 			WHERE id = ?`,
-			status, actionID)
+			status, now, actionID)
 		return err
 	default:
 		_, err := s.db.ExecContext(ctx, `UPDATE stellar_actions
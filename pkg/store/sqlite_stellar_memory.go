@@ -4,11 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"log/slog"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/safego"
 )
 
+// defaultMemoryImportance matches the column default for entries created
+// without an explicit importance.
+const defaultMemoryImportance = 5
+
 func (s *SQLiteStore) ListStellarMemoryEntries(ctx context.Context, userID, cluster, category string, limit, offset int) ([]StellarMemoryEntry, error) {
 	lim := resolvePageLimit(limit, defaultPageLimit)
 	off := resolvePageOffset(offset)
@@ -22,7 +30,7 @@ func (s *SQLiteStore) ListStellarMemoryEntries(ctx context.Context, userID, clus
 		clauses = append(clauses, "category = ?")
 		args = append(args, category)
 	}
-	query := `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at
+	query := `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at, importance
 		FROM stellar_memory_entries WHERE ` + strings.Join(clauses, " AND ") + `
 		ORDER BY created_at DESC LIMIT ? OFFSET ?`
 	args = append(args, lim, off)
@@ -43,9 +51,40 @@ func (s *SQLiteStore) ListStellarMemoryEntries(ctx context.Context, userID, clus
 }
 
 
+// SearchStellarMemoryEntries returns the user's memories related to query.
+// When a memory embedder is configured the results are ranked by cosine
+// similarity blended with importance and recency, with keyword matches
+// appended for entries that have no embedding yet. Without an embedder (or if
+// embedding the query fails) it falls back to a plain keyword match.
 func (s *SQLiteStore) SearchStellarMemoryEntries(ctx context.Context, userID, query string, limit int) ([]StellarMemoryEntry, error) {
 	lim := resolvePageLimit(limit, 20)
-	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at
+	if embed := s.getMemoryEmbedder(); embed != nil && strings.TrimSpace(query) != "" {
+		embedCtx, cancel := context.WithTimeout(ctx, memoryEmbedTimeout)
+		vec, err := embed(embedCtx, query)
+		cancel()
+		if err == nil && len(vec) > 0 {
+			ranked, err := s.semanticMemorySearch(ctx, userID, vec, lim)
+			if err != nil {
+				return nil, err
+			}
+			if len(ranked) >= lim {
+				return ranked, nil
+			}
+			keyword, err := s.keywordMemorySearch(ctx, userID, query, lim)
+			if err != nil {
+				return nil, err
+			}
+			return mergeMemoryResults(ranked, keyword, lim), nil
+		}
+		if err != nil {
+			slog.Debug("stellar memory: query embedding failed, using keyword search", "error", err)
+		}
+	}
+	return s.keywordMemorySearch(ctx, userID, query, lim)
+}
+
+func (s *SQLiteStore) keywordMemorySearch(ctx context.Context, userID, query string, lim int) ([]StellarMemoryEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at, importance
 		FROM stellar_memory_entries
 		WHERE user_id = ? AND (summary LIKE ? OR raw_content LIKE ? OR tags LIKE ?)
 		ORDER BY created_at DESC
@@ -67,6 +106,157 @@ func (s *SQLiteStore) SearchStellarMemoryEntries(ctx context.Context, userID, qu
 }
 
 
+// semanticMemorySearch scores the user's most recent embedded, unexpired
+// memories against vec and returns the top lim by recallScore.
+func (s *SQLiteStore) semanticMemorySearch(ctx context.Context, userID string, vec []float32, lim int) ([]StellarMemoryEntry, error) {
+	now := time.Now().UTC()
+	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at, importance, embedding
+		FROM stellar_memory_entries
+		WHERE user_id = ? AND embedding IS NOT NULL AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at DESC
+		LIMIT ?`,
+		userID, now, recallCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type scored struct {
+		entry StellarMemoryEntry
+		score float64
+	}
+	candidates := make([]scored, 0)
+	for rows.Next() {
+		entry, scanErr := scanStellarMemoryRowWithEmbedding(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		sim := CosineSimilarity(vec, DecodeEmbedding(entry.Embedding))
+		if sim < recallMinSimilarity {
+			continue
+		}
+		candidates = append(candidates, scored{entry: *entry, score: recallScore(sim, entry.Importance, entry.CreatedAt, now)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > lim {
+		candidates = candidates[:lim]
+	}
+	results := make([]StellarMemoryEntry, 0, len(candidates))
+	for _, c := range candidates {
+		results = append(results, c.entry)
+	}
+	return results, nil
+}
+
+// mergeMemoryResults appends keyword hits after the semantic ranking,
+// skipping duplicates, up to lim entries.
+func mergeMemoryResults(ranked, keyword []StellarMemoryEntry, lim int) []StellarMemoryEntry {
+	seen := make(map[string]bool, len(ranked))
+	for _, e := range ranked {
+		seen[e.ID] = true
+	}
+	for _, e := range keyword {
+		if len(ranked) >= lim {
+			break
+		}
+		if !seen[e.ID] {
+			ranked = append(ranked, e)
+			seen[e.ID] = true
+		}
+	}
+	return ranked
+}
+
+// SetMemoryEmbedder installs the function used to vectorise memory entries
+// on create and search queries. Passing nil disables semantic recall.
+func (s *SQLiteStore) SetMemoryEmbedder(fn MemoryEmbedFunc) {
+	s.embedMu.Lock()
+	defer s.embedMu.Unlock()
+	s.memoryEmbedder = fn
+}
+
+func (s *SQLiteStore) getMemoryEmbedder() MemoryEmbedFunc {
+	s.embedMu.RLock()
+	defer s.embedMu.RUnlock()
+	return s.memoryEmbedder
+}
+
+// SetStellarMemoryEmbedding stores a computed vector for an existing entry.
+func (s *SQLiteStore) SetStellarMemoryEmbedding(ctx context.Context, entryID string, embedding []byte) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE stellar_memory_entries SET embedding = ? WHERE id = ?`, embedding, entryID)
+	return err
+}
+
+// embedMemoryEntryAsync computes the entry's vector off the request path so
+// a slow or unreachable embedding endpoint never blocks memory writes.
+func (s *SQLiteStore) embedMemoryEntryAsync(entryID, text string) {
+	embed := s.getMemoryEmbedder()
+	if embed == nil || strings.TrimSpace(text) == "" {
+		return
+	}
+	safego.GoWith("stellar-memory-embed", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), memoryEmbedTimeout)
+		defer cancel()
+		vec, err := embed(ctx, text)
+		if err != nil || len(vec) == 0 {
+			slog.Debug("stellar memory: embedding failed", "entry_id", entryID, "error", err)
+			return
+		}
+		if err := s.SetStellarMemoryEmbedding(ctx, entryID, EncodeEmbedding(vec)); err != nil {
+			slog.Warn("stellar memory: store embedding failed", "entry_id", entryID, "error", err)
+		}
+	})
+}
+
+// BackfillStellarMemoryEmbeddings embeds up to limit unexpired entries that
+// predate the embedder being configured. Returns how many were embedded.
+func (s *SQLiteStore) BackfillStellarMemoryEmbeddings(ctx context.Context, limit int) (int, error) {
+	embed := s.getMemoryEmbedder()
+	if embed == nil {
+		return 0, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at, importance
+		FROM stellar_memory_entries
+		WHERE embedding IS NULL AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at DESC
+		LIMIT ?`, time.Now().UTC(), resolvePageLimit(limit, defaultPageLimit))
+	if err != nil {
+		return 0, err
+	}
+	pending := make([]StellarMemoryEntry, 0)
+	for rows.Next() {
+		entry, scanErr := scanStellarMemoryRow(rows)
+		if scanErr != nil {
+			rows.Close()
+			return 0, scanErr
+		}
+		pending = append(pending, *entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	embedded := 0
+	for i := range pending {
+		embedCtx, cancel := context.WithTimeout(ctx, memoryEmbedTimeout)
+		vec, err := embed(embedCtx, memoryEmbedText(&pending[i]))
+		cancel()
+		if err != nil {
+			return embedded, err
+		}
+		if err := s.SetStellarMemoryEmbedding(ctx, pending[i].ID, EncodeEmbedding(vec)); err != nil {
+			return embedded, err
+		}
+		embedded++
+	}
+	return embedded, nil
+}
+
+
 func (s *SQLiteStore) CreateStellarMemoryEntry(ctx context.Context, entry *StellarMemoryEntry) error {
 	if entry.ID == "" {
 		entry.ID = uuid.NewString()
@@ -79,9 +269,13 @@ func (s *SQLiteStore) CreateStellarMemoryEntry(ctx context.Context, entry *Stell
 	if err != nil {
 		return err
 	}
+	if entry.Importance == 0 {
+		entry.Importance = defaultMemoryImportance
+	}
 	_, err = s.db.ExecContext(ctx, `INSERT INTO stellar_memory_entries (
-		id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))`,
+		id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at,
+		importance, incident_id, embedding
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), ?, ?, ?)`,
 		entry.ID,
 		entry.UserID,
 		entry.Cluster,
@@ -94,8 +288,17 @@ func (s *SQLiteStore) CreateStellarMemoryEntry(ctx context.Context, entry *Stell
 		entry.ExecutionID,
 		entry.ExpiresAt,
 		nullableTime(entry.CreatedAt),
+		entry.Importance,
+		entry.IncidentID,
+		entry.Embedding,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if len(entry.Embedding) == 0 {
+		s.embedMemoryEntryAsync(entry.ID, memoryEmbedText(entry))
+	}
+	return nil
 }
 
 
@@ -107,7 +310,7 @@ func (s *SQLiteStore) DeleteStellarMemoryEntry(ctx context.Context, userID, entr
 
 func (s *SQLiteStore) GetRecentMemoryEntries(ctx context.Context, userID, cluster string, limit int) ([]StellarMemoryEntry, error) {
 	lim := resolvePageLimit(limit, 20)
-	query := `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at
+	query := `SELECT id, user_id, cluster, namespace, category, summary, raw_content, tags, mission_id, execution_id, expires_at, created_at, importance
 		FROM stellar_memory_entries WHERE user_id = ?`
 	args := []interface{}{userID}
 	if strings.TrimSpace(cluster) != "" {
@@ -134,10 +337,20 @@ func (s *SQLiteStore) GetRecentMemoryEntries(ctx context.Context, userID, cluste
 
 
 func scanStellarMemoryRow(rows *sql.Rows) (*StellarMemoryEntry, error) {
+	return scanStellarMemoryFields(rows, false)
+}
+
+// scanStellarMemoryRowWithEmbedding scans rows whose SELECT list ends with the
+// embedding column in addition to the standard memory columns.
+func scanStellarMemoryRowWithEmbedding(rows *sql.Rows) (*StellarMemoryEntry, error) {
+	return scanStellarMemoryFields(rows, true)
+}
+
+func scanStellarMemoryFields(rows *sql.Rows, withEmbedding bool) (*StellarMemoryEntry, error) {
 	var entry StellarMemoryEntry
 	var namespace, rawContent, tagsRaw, missionID, executionID sql.NullString
 	var expiresAt sql.NullTime
-	if err := rows.Scan(
+	dest := []interface{}{
 		&entry.ID,
 		&entry.UserID,
 		&entry.Cluster,
@@ -150,7 +363,12 @@ func scanStellarMemoryRow(rows *sql.Rows) (*StellarMemoryEntry, error) {
 		&executionID,
 		&expiresAt,
 		&entry.CreatedAt,
-	); err != nil {
+		&entry.Importance,
+	}
+	if withEmbedding {
+		dest = append(dest, &entry.Embedding)
+	}
+	if err := rows.Scan(dest...); err != nil {
 		return nil, err
 	}
 	entry.Namespace = namespace.String
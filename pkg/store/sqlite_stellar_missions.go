@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,7 +14,7 @@ func (s *SQLiteStore) ListStellarMissions(ctx context.Context, userID string, li
 	lim := resolvePageLimit(limit, defaultPageLimit)
 	off := resolvePageOffset(offset)
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, user_id, name, goal, schedule, trigger_type, provider_policy, memory_scope, enabled, tool_bindings, last_run_at, next_run_at, created_at, updated_at
+		`SELECT id, user_id, name, goal, schedule, trigger_type, provider_policy, memory_scope, enabled, tool_bindings, cluster, namespace, last_run_at, next_run_at, created_at, updated_at
 		 FROM stellar_missions
 		 WHERE user_id = ?
 		 ORDER BY created_at DESC, id DESC
@@ -38,7 +39,7 @@ func (s *SQLiteStore) ListStellarMissions(ctx context.Context, userID string, li
 
 func (s *SQLiteStore) GetStellarMission(ctx context.Context, userID string, missionID string) (*StellarMission, error) {
 	row := s.db.QueryRowContext(ctx,
-		`SELECT id, user_id, name, goal, schedule, trigger_type, provider_policy, memory_scope, enabled, tool_bindings, last_run_at, next_run_at, created_at, updated_at
+		`SELECT id, user_id, name, goal, schedule, trigger_type, provider_policy, memory_scope, enabled, tool_bindings, cluster, namespace, last_run_at, next_run_at, created_at, updated_at
 		 FROM stellar_missions
 		 WHERE user_id = ? AND id = ?`,
 		userID, missionID)
@@ -59,6 +60,8 @@ func (s *SQLiteStore) GetStellarMission(ctx context.Context, userID string, miss
 		&mission.MemoryScope,
 		&enabledInt,
 		&toolBindingsRaw,
+		&mission.Cluster,
+		&mission.Namespace,
 		&lastRunAt,
 		&nextRunAt,
 		&mission.CreatedAt,
@@ -111,8 +114,8 @@ func (s *SQLiteStore) CreateStellarMission(ctx context.Context, mission *Stellar
 	_, err = s.db.ExecContext(ctx,
 		`INSERT INTO stellar_missions (
 			id, user_id, name, goal, schedule, trigger_type, provider_policy, memory_scope,
-			enabled, tool_bindings, last_run_at, next_run_at, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+			enabled, tool_bindings, cluster, namespace, last_run_at, next_run_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
 		mission.ID,
 		mission.UserID,
 		mission.Name,
@@ -123,6 +126,8 @@ func (s *SQLiteStore) CreateStellarMission(ctx context.Context, mission *Stellar
 		mission.MemoryScope,
 		boolToInt(mission.Enabled),
 		string(toolBindingsJSON),
+		mission.Cluster,
+		mission.Namespace,
 		mission.LastRunAt,
 		mission.NextRunAt,
 	)
@@ -145,7 +150,7 @@ func (s *SQLiteStore) UpdateStellarMission(ctx context.Context, mission *Stellar
 	_, err = s.db.ExecContext(ctx,
 		`UPDATE stellar_missions
 		 SET name = ?, goal = ?, schedule = ?, trigger_type = ?, provider_policy = ?, memory_scope = ?,
-		 	 enabled = ?, tool_bindings = ?, last_run_at = ?, next_run_at = ?, updated_at = CURRENT_TIMESTAMP
+		 	 enabled = ?, tool_bindings = ?, cluster = ?, namespace = ?, last_run_at = ?, next_run_at = ?, updated_at = CURRENT_TIMESTAMP
 		 WHERE user_id = ? AND id = ?`,
 		mission.Name,
 		mission.Goal,
@@ -155,6 +160,8 @@ func (s *SQLiteStore) UpdateStellarMission(ctx context.Context, mission *Stellar
 		mission.MemoryScope,
 		boolToInt(mission.Enabled),
 		string(toolBindingsJSON),
+		mission.Cluster,
+		mission.Namespace,
 		mission.LastRunAt,
 		mission.NextRunAt,
 		mission.UserID,
@@ -176,6 +183,57 @@ func (s *SQLiteStore) DeleteStellarMission(ctx context.Context, userID string, m
 }
 
 
+// ListDueStellarMissions returns enabled, scheduled missions across all users
+// whose next_run_at has arrived. Missions with a schedule but no next_run_at
+// yet are included so the cron engine can seed their first fire time.
+func (s *SQLiteStore) ListDueStellarMissions(ctx context.Context, now time.Time, limit int) ([]StellarMission, error) {
+	lim := resolvePageLimit(limit, defaultPageLimit)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, name, goal, schedule, trigger_type, provider_policy, memory_scope, enabled, tool_bindings, cluster, namespace, last_run_at, next_run_at, created_at, updated_at
+		 FROM stellar_missions
+		 WHERE enabled = 1 AND schedule != '' AND (next_run_at IS NULL OR next_run_at <= ?)
+		 ORDER BY COALESCE(next_run_at, created_at) ASC
+		 LIMIT ?`,
+		now.UTC(), lim)
+	if err != nil {
+		return nil, fmt.Errorf("list due stellar missions: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]StellarMission, 0)
+	for rows.Next() {
+		mission, err := scanStellarMissionRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan stellar mission row: %w", err)
+		}
+		results = append(results, *mission)
+	}
+	return results, rows.Err()
+}
+
+// SetStellarMissionRunTimes records a scheduler fire for a mission. A nil
+// lastRunAt leaves the previous value untouched (used when only seeding or
+// skipping); a nil nextRunAt clears the schedule cursor.
+func (s *SQLiteStore) SetStellarMissionRunTimes(ctx context.Context, missionID string, lastRunAt, nextRunAt *time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE stellar_missions
+		 SET last_run_at = COALESCE(?, last_run_at), next_run_at = ?, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = ?`,
+		utcPtr(lastRunAt), utcPtr(nextRunAt), missionID)
+	if err != nil {
+		return fmt.Errorf("set run times for stellar mission %s: %w", missionID, err)
+	}
+	return nil
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
 func scanStellarMissionRow(rows *sql.Rows) (*StellarMission, error) {
 	var mission StellarMission
 	var enabledInt int
@@ -193,6 +251,8 @@ func scanStellarMissionRow(rows *sql.Rows) (*StellarMission, error) {
 		&mission.MemoryScope,
 		&enabledInt,
 		&toolBindingsRaw,
+		&mission.Cluster,
+		&mission.Namespace,
 		&lastRunAt,
 		&nextRunAt,
 		&mission.CreatedAt,
@@ -109,7 +109,7 @@ func (s *SQLiteStore) ListStellarUserIDs(ctx context.Context) ([]string, error)
 		SELECT user_id FROM stellar_actions
 		UNION ALL
 		SELECT user_id FROM stellar_notifications
-	) WHERE user_id != ''`)
+	) AS stellar_users WHERE user_id != ''`)
 	if err != nil {
 		return nil, err
 	}
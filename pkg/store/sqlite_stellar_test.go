@@ -1,6 +1,8 @@
 package store
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -94,6 +96,89 @@ func TestStellarMissionExecutionAndMemory(t *testing.T) {
 	require.Equal(t, "incident", entries[0].Category)
 }
 
+func TestListDueStellarMissionsAndRunTimes(t *testing.T) {
+	s := newTestStore(t)
+	const userID = "stellar-user-cron"
+	now := time.Now().UTC().Truncate(time.Second)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	due := &StellarMission{UserID: userID, Name: "due", Goal: "g", Schedule: "@hourly", Enabled: true, NextRunAt: &past, Cluster: "prod-a", Namespace: "payments"}
+	unseeded := &StellarMission{UserID: userID, Name: "unseeded", Goal: "g", Schedule: "@daily", Enabled: true}
+	later := &StellarMission{UserID: userID, Name: "later", Goal: "g", Schedule: "@hourly", Enabled: true, NextRunAt: &future}
+	disabled := &StellarMission{UserID: userID, Name: "disabled", Goal: "g", Schedule: "@hourly", Enabled: false, NextRunAt: &past}
+	manual := &StellarMission{UserID: userID, Name: "manual", Goal: "g", Enabled: true}
+	for _, m := range []*StellarMission{due, unseeded, later, disabled, manual} {
+		require.NoError(t, s.CreateStellarMission(ctx, m))
+	}
+
+	got, err := s.ListDueStellarMissions(ctx, now, 10)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(got))
+	for _, m := range got {
+		ids = append(ids, m.ID)
+	}
+	assert.ElementsMatch(t, []string{due.ID, unseeded.ID}, ids)
+	for _, m := range got {
+		if m.ID == due.ID {
+			assert.Equal(t, "prod-a", m.Cluster)
+			assert.Equal(t, "payments", m.Namespace)
+		}
+	}
+
+	require.NoError(t, s.SetStellarMissionRunTimes(ctx, due.ID, &now, &future))
+	reloaded, err := s.GetStellarMission(ctx, userID, due.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.LastRunAt)
+	require.NotNil(t, reloaded.NextRunAt)
+	assert.True(t, reloaded.LastRunAt.Equal(now))
+	assert.True(t, reloaded.NextRunAt.Equal(future))
+
+	// A nil lastRunAt keeps the previous value.
+	require.NoError(t, s.SetStellarMissionRunTimes(ctx, due.ID, nil, &future))
+	reloaded, err = s.GetStellarMission(ctx, userID, due.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.LastRunAt)
+	assert.True(t, reloaded.LastRunAt.Equal(now))
+}
+
+func TestRearmStaleCronStellarActions(t *testing.T) {
+	s := newTestStore(t)
+	const userID = "stellar-user-rearm"
+	when := time.Now().UTC().Add(-time.Minute)
+
+	newAction := func(cron string) *StellarAction {
+		a := &StellarAction{
+			UserID: userID, Description: "restart", ActionType: "RestartDeployment",
+			Parameters: `{}`, Cluster: "prod-a", ScheduledAt: &when, CronExpr: cron,
+			Status: "approved", CreatedBy: userID,
+		}
+		require.NoError(t, s.CreateStellarAction(ctx, a))
+		require.NoError(t, s.UpdateStellarActionStatus(ctx, a.ID, "running", "", ""))
+		return a
+	}
+	recurring := newAction("@hourly")
+	oneShot := newAction("")
+
+	// A run still inside its lease is left alone.
+	n, err := s.RearmStaleCronStellarActions(ctx, time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = s.RearmStaleCronStellarActions(ctx, time.Now().UTC().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	due, err := s.GetDueCronStellarActions(ctx, time.Now().UTC(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, recurring.ID, due[0].ID)
+
+	still, err := s.GetStellarAction(ctx, userID, oneShot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "running", still.Status)
+}
+
 func TestStellarActionsAndNotifications(t *testing.T) {
 	s := newTestStore(t)
 	const userID = "stellar-user-3"
@@ -189,3 +274,59 @@ func TestGetActiveWatchesAutoResolvesInactiveEntries(t *testing.T) {
 	assert.Equal(t, stellarWatchAutoResolvedLastUpdate, resolved[0].LastUpdate)
 	require.NotNil(t, resolved[0].ResolvedAt)
 }
+
+// conceptEmbedder is a deterministic stand-in for a real embedding model: it
+// maps words onto a handful of concept dimensions so synonyms land close
+// together ("OOMKilled" and "out of memory" both hit the memory axis).
+func conceptEmbedder(_ context.Context, text string) ([]float32, error) {
+	concepts := map[string]int{
+		"oomkilled": 0, "memory": 0, "oom": 0,
+		"payments": 1, "payments-api": 1,
+		"certificate": 2, "tls": 2, "expired": 2,
+	}
+	vec := make([]float32, 4)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if dim, ok := concepts[word]; ok {
+			vec[dim]++
+		} else {
+			vec[3] += 0.1
+		}
+	}
+	return vec, nil
+}
+
+func TestSearchStellarMemoryEntriesSemantic(t *testing.T) {
+	s := newTestStore(t)
+	const userID = "stellar-user-recall"
+
+	oom := &StellarMemoryEntry{UserID: userID, Cluster: "prod", Category: "incident", Summary: "payments-api ran out of memory last Tuesday", Importance: 8}
+	tls := &StellarMemoryEntry{UserID: userID, Cluster: "prod", Category: "incident", Summary: "ingress TLS certificate expired", Importance: 9}
+	other := &StellarMemoryEntry{UserID: "someone-else", Cluster: "prod", Category: "incident", Summary: "payments memory leak", Importance: 9}
+	for _, e := range []*StellarMemoryEntry{oom, tls, other} {
+		require.NoError(t, s.CreateStellarMemoryEntry(ctx, e))
+	}
+
+	// Without an embedder the keyword search can't bridge the vocabulary gap.
+	got, err := s.SearchStellarMemoryEntries(ctx, userID, "OOMKilled in payments", 5)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	s.SetMemoryEmbedder(conceptEmbedder)
+	n, err := s.BackfillStellarMemoryEmbeddings(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	got, err = s.SearchStellarMemoryEntries(ctx, userID, "OOMKilled in payments", 5)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, oom.ID, got[0].ID)
+	assert.Equal(t, 8, got[0].Importance)
+}
+
+func TestEmbeddingEncodingRoundTrip(t *testing.T) {
+	vec := []float32{0.25, -1.5, 3}
+	assert.Equal(t, vec, DecodeEmbedding(EncodeEmbedding(vec)))
+	assert.Nil(t, DecodeEmbedding([]byte{1, 2, 3}))
+	assert.InDelta(t, 1.0, CosineSimilarity(vec, vec), 1e-9)
+	assert.Zero(t, CosineSimilarity(vec, []float32{1, 2}))
+}
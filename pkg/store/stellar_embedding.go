@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// MemoryEmbedFunc computes a vector for a memory entry's text. The store
+// stays provider-agnostic: the server wires in a function backed by the
+// Stellar provider registry (Ollama or an OpenAI-compatible endpoint).
+type MemoryEmbedFunc func(ctx context.Context, text string) ([]float32, error)
+
+// Semantic recall ranking weights. Similarity dominates; importance and
+// recency break ties between equally related memories so a fresh,
+// high-importance incident outranks a stale low-importance note.
+const (
+	recallSimilarityWeight = 0.7
+	recallImportanceWeight = 0.2
+	recallRecencyWeight    = 0.1
+	// recallRecencyHalfLife is the age at which the recency term halves.
+	recallRecencyHalfLife = 14 * 24 * time.Hour
+	// recallMinSimilarity drops candidates that are not meaningfully related.
+	recallMinSimilarity = 0.3
+	// recallMaxImportance normalises StellarMemoryEntry.Importance (1-10).
+	recallMaxImportance = 10
+	// recallCandidateLimit bounds how many embedded rows a single search
+	// scores in-process.
+	recallCandidateLimit = 2000
+	// memoryEmbedTimeout bounds one embedding call.
+	memoryEmbedTimeout = 15 * time.Second
+)
+
+// EncodeEmbedding packs a vector into the little-endian float32 layout stored
+// in stellar_memory_entries.embedding.
+func EncodeEmbedding(vec []float32) []byte {
+	if len(vec) == 0 {
+		return nil
+	}
+	buf := make([]byte, 4*len(vec))
+	for i, v := range vec {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// DecodeEmbedding is the inverse of EncodeEmbedding. Malformed blobs decode
+// to nil rather than erroring so one bad row can't break a search.
+func DecodeEmbedding(buf []byte) []float32 {
+	if len(buf) == 0 || len(buf)%4 != 0 {
+		return nil
+	}
+	vec := make([]float32, len(buf)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return vec
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 when
+// the vectors differ in length (e.g. the embedding model changed) or either
+// is all zeros.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// recallScore blends similarity with importance and recency.
+func recallScore(similarity float64, importance int, createdAt, now time.Time) float64 {
+	imp := float64(importance) / recallMaxImportance
+	if imp > 1 {
+		imp = 1
+	}
+	if imp < 0 {
+		imp = 0
+	}
+	age := now.Sub(createdAt)
+	if age < 0 {
+		age = 0
+	}
+	recency := math.Exp(-math.Ln2 * float64(age) / float64(recallRecencyHalfLife))
+	return recallSimilarityWeight*similarity + recallImportanceWeight*imp + recallRecencyWeight*recency
+}
+
+// memoryEmbedText is the text we embed for an entry: the summary carries the
+// meaning; tags add the resource vocabulary (pod names, reasons).
+func memoryEmbedText(entry *StellarMemoryEntry) string {
+	text := entry.Summary
+	if entry.Cluster != "" {
+		text += "\ncluster: " + entry.Cluster
+	}
+	if entry.Namespace != "" {
+		text += "\nnamespace: " + entry.Namespace
+	}
+	for _, tag := range entry.Tags {
+		text += "\n" + tag
+	}
+	return text
+}
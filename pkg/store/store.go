@@ -60,6 +60,8 @@ type StellarPreferences struct {
 }
 
 // StellarMission stores a user-owned long-running or scheduled assistant task.
+// Cluster and Namespace scope its scheduled runs; empty means the owner's
+// pinned clusters and all namespaces.
 type StellarMission struct {
 	ID             string     `json:"id"`
 	UserID         string     `json:"userId"`
@@ -71,6 +73,8 @@ type StellarMission struct {
 	MemoryScope    string     `json:"memoryScope"`
 	Enabled        bool       `json:"enabled"`
 	ToolBindings   []string   `json:"toolBindings"`
+	Cluster        string     `json:"cluster,omitempty"`
+	Namespace      string     `json:"namespace,omitempty"`
 	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
 	NextRunAt      *time.Time `json:"nextRunAt,omitempty"`
 	CreatedAt      time.Time  `json:"createdAt"`
@@ -290,13 +294,71 @@ type KBQueryGap struct {
 	LastSeen time.Time `json:"lastSeen"`
 }
 
+// AlertState is the evaluation state of one alert instance: a rule and the
+// fingerprint of the resource it matched. Alert is the JSON-encoded
+// notification last built for the instance.
+type AlertState struct {
+	RuleID      string     `json:"ruleId"`
+	Fingerprint string     `json:"fingerprint"`
+	Status      string     `json:"status"` // pending | firing
+	ActiveSince time.Time  `json:"activeSince"`
+	FiredAt     *time.Time `json:"firedAt,omitempty"`
+	Alert       []byte     `json:"alert"`
+}
+
+// NotificationDelivery is one queued notification to one target: a
+// registered notifier ID or, for per-rule channels, "channel:<type>" with
+// the channel config in Channel. Alerts is the JSON-encoded list of alerts
+// grouped into the message.
+type NotificationDelivery struct {
+	ID          string    `json:"id"`
+	Target      string    `json:"target"`
+	Channel     []byte    `json:"-"`
+	GroupKey    string    `json:"groupKey"`
+	Alerts      []byte    `json:"alerts"`
+	Status      string    `json:"status"` // pending | sent | failed | silenced
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	LastError   string    `json:"lastError,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	// ClaimedUntil is when the replica sending the delivery loses its
+	// claim. Zero when unclaimed.
+	ClaimedUntil time.Time `json:"-"`
+}
+
+// NotifiedAlert records the last status notified for an alert fingerprint,
+// used to deduplicate repeats and to skip resolutions nobody was told about.
+type NotifiedAlert struct {
+	Fingerprint string
+	Status      string
+	NotifiedAt  time.Time
+}
+
 // AuditEntry represents a single row in the audit_log table (#8670 Phase 3).
+// Hash chains the row to its predecessor (see AuditEntryHash); both hashes
+// are empty on rows written before the chain existed.
 type AuditEntry struct {
 	ID        int64  `json:"id"`
 	Timestamp string `json:"timestamp"`
 	UserID    string `json:"user_id"`
 	Action    string `json:"action"`
 	Detail    string `json:"detail,omitempty"`
+	PrevHash  string `json:"prev_hash,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+}
+
+// AuditCheckpoint is a signed snapshot of the audit chain head: the ID and
+// hash of the newest row at CreatedAt. PrevHash links it to the checkpoint
+// before it so deleting one is detected.
+type AuditCheckpoint struct {
+	ID        int64     `json:"id"`
+	LastID    int64     `json:"last_id"`
+	Hash      string    `json:"hash"`
+	PrevHash  string    `json:"prev_hash"`
+	KeyID     string    `json:"key_id"`
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Store defines the interface for data persistence
@@ -426,8 +488,20 @@ type Store interface {
 	// so concurrent updates cannot bypass the cap (#6957). A capacity
 	// value of 0 or less skips the check and behaves like UpdateGPUReservation.
 	UpdateGPUReservationWithCapacity(ctx context.Context, reservation *models.GPUReservation, capacity int) error
+	// TransitionGPUReservation writes a lifecycle change (status, notified
+	// stage, quota) only while the reservation is still in status from,
+	// reporting whether it applied. capacity > 0 also enforces the cluster
+	// cap, as UpdateGPUReservationWithCapacity does.
+	TransitionGPUReservation(ctx context.Context, reservation *models.GPUReservation, from models.ReservationStatus, capacity int) (bool, error)
 	DeleteGPUReservation(ctx context.Context, id uuid.UUID) error
+	// GetClusterReservedGPUCount returns the GPUs held on a cluster right
+	// now; GetClusterPeakReservedGPUs the most held at any instant of a
+	// window. Only active and scheduled reservations hold capacity.
 	GetClusterReservedGPUCount(ctx context.Context, cluster string, excludeID *uuid.UUID) (int, error)
+	GetClusterPeakReservedGPUs(ctx context.Context, cluster string, start, end time.Time, excludeID *uuid.UUID) (int, error)
+	// ListClusterGPUReservations returns reservations in the given statuses
+	// oldest first; an empty cluster matches all clusters.
+	ListClusterGPUReservations(ctx context.Context, cluster string, statuses []models.ReservationStatus) ([]models.GPUReservation, error)
 	// GetGPUReservationsByIDs fetches multiple reservations in a single
 	// batched query, avoiding N+1 round-trips (#6963).
 	GetGPUReservationsByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.GPUReservation, error)
@@ -437,7 +511,23 @@ type Store interface {
 	GetUtilizationSnapshots(ctx context.Context, reservationID string, limit int) ([]models.GPUUtilizationSnapshot, error)
 	GetBulkUtilizationSnapshots(ctx context.Context, reservationIDs []string) (map[string][]models.GPUUtilizationSnapshot, error)
 	DeleteOldUtilizationSnapshots(ctx context.Context, before time.Time) (int64, error)
+	// ListUtilizationSnapshotsInRange returns the snapshots taken in
+	// [start, end) for chargeback reporting, or ErrUtilizationRangeTooLarge.
+	ListUtilizationSnapshotsInRange(ctx context.Context, start, end time.Time) ([]models.GPUUtilizationSnapshot, error)
 	ListActiveGPUReservations(ctx context.Context) ([]models.GPUReservation, error)
+	// ClaimGPUChargebackRun and ReleaseGPUChargebackRun ensure a scheduled
+	// chargeback report period is sent once.
+	ClaimGPUChargebackRun(ctx context.Context, period string) (bool, error)
+	ReleaseGPUChargebackRun(ctx context.Context, period string) error
+
+	// kc-agent reverse tunnel credentials. GetAgentTunnelCredentialByTokenHash
+	// returns (nil, nil) for an unknown hash; DeleteAgentTunnelCredential
+	// returns ErrNotFound when the user has no such agent.
+	CreateAgentTunnelCredential(ctx context.Context, cred *models.AgentTunnelCredential) error
+	GetAgentTunnelCredentialByTokenHash(ctx context.Context, tokenHash string) (*models.AgentTunnelCredential, error)
+	ListAgentTunnelCredentials(ctx context.Context, userID uuid.UUID) ([]models.AgentTunnelCredential, error)
+	DeleteAgentTunnelCredential(ctx context.Context, userID, id uuid.UUID) error
+	TouchAgentTunnelCredential(ctx context.Context, id uuid.UUID, seenAt time.Time) error
 
 	// Token Revocation
 	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
@@ -488,6 +578,13 @@ type Store interface {
 	//
 	// #6613: accepts a context (see IncrementUserCoins).
 	AddUserTokenDelta(ctx context.Context, userID string, category string, delta int64, agentSessionID string) (*UserTokenUsage, error)
+	// GetAIBudgetUsage returns userID's per-category AI router spend on day
+	// (YYYY-MM-DD). Unlike the user_token_usage row, this counter is only
+	// written by AddAIBudgetUsage, so clients cannot lower enforced spend.
+	GetAIBudgetUsage(ctx context.Context, userID, day string) (map[string]int64, error)
+	// AddAIBudgetUsage atomically adds tokens to userID's spend in category
+	// on day and drops the user's counters from earlier days.
+	AddAIBudgetUsage(ctx context.Context, userID, day, category string, tokens int64) error
 
 	// OAuth Credentials — persisted by the GitHub App Manifest one-click flow
 	// so credentials survive restarts without requiring .env configuration.
@@ -514,6 +611,14 @@ type Store interface {
 	// parameters are optional (empty string = no filter). Limit is clamped
 	// to maxAuditQueryLimit internally.
 	QueryAuditLogs(ctx context.Context, limit int, userID, action string) ([]AuditEntry, error)
+	// ListAuditChain returns up to limit audit entries with an ID above
+	// afterID, oldest first, for walking the hash chain.
+	ListAuditChain(ctx context.Context, afterID int64, limit int) ([]AuditEntry, error)
+	// AuditLegacyCutoff returns the highest ID written before the chain.
+	AuditLegacyCutoff(ctx context.Context) (int64, error)
+	InsertAuditCheckpoint(ctx context.Context, cp *AuditCheckpoint) error
+	// ListAuditCheckpoints returns every checkpoint, oldest first.
+	ListAuditCheckpoints(ctx context.Context) ([]AuditCheckpoint, error)
 
 	// Cluster Groups — persistent storage for cluster group definitions so they
 	// survive server restarts (#7013). The in-memory map is the runtime cache;
@@ -529,6 +634,54 @@ type Store interface {
 	// hit count descending.
 	ListTopKBGaps(ctx context.Context, n int) ([]KBQueryGap, error)
 
+	// License Policy — the operator-defined license allow/warn/deny policy
+	// (#9648), stored as an opaque JSON document. GetLicensePolicy returns
+	// nil when none has been saved.
+	GetLicensePolicy(ctx context.Context) ([]byte, error)
+	SaveLicensePolicy(ctx context.Context, data []byte) error
+
+	// Alert Rules — server-side alert rules evaluated by pkg/alerting, each
+	// stored as an opaque JSON document keyed by rule ID. ListAlertRules
+	// returns them oldest first. DeleteAlertRule also drops the rule's
+	// alert states.
+	ListAlertRules(ctx context.Context) ([][]byte, error)
+	SaveAlertRule(ctx context.Context, id string, data []byte) error
+	DeleteAlertRule(ctx context.Context, id string) error
+	// Alert States — pending and firing alert instances, so a restart
+	// neither re-pages nor forgets to send a resolution.
+	ListAlertStates(ctx context.Context) ([]AlertState, error)
+	SaveAlertState(ctx context.Context, state AlertState) error
+	DeleteAlertState(ctx context.Context, ruleID, fingerprint string) error
+
+	// Notification Queue — durable outbound notifications.
+	// ClaimDueNotifications atomically claims, until the given time, the
+	// unclaimed pending deliveries whose next attempt is at or before now,
+	// oldest first, so concurrent replicas never claim the same one.
+	// FindNotificationGroup returns the pending, never attempted delivery
+	// for target and groupKey that is still collecting alerts (next
+	// attempt after now), or nil; UpdateNotificationGroup replaces its
+	// alerts only while that still holds. PendingNotifications returns
+	// every pending delivery for target and groupKey. ListNotifications
+	// returns the most recent deliveries, optionally filtered by status.
+	// PruneNotifications drops finished deliveries and resolved alert
+	// records last touched before the cutoff.
+	SaveNotification(ctx context.Context, d NotificationDelivery) error
+	GetNotification(ctx context.Context, id string) (*NotificationDelivery, error)
+	ClaimDueNotifications(ctx context.Context, now, until time.Time, limit int) ([]NotificationDelivery, error)
+	FindNotificationGroup(ctx context.Context, target, groupKey string, now time.Time) (*NotificationDelivery, error)
+	UpdateNotificationGroup(ctx context.Context, id string, alerts []byte, now time.Time) (bool, error)
+	PendingNotifications(ctx context.Context, target, groupKey string) ([]NotificationDelivery, error)
+	ListNotifications(ctx context.Context, status string, limit int) ([]NotificationDelivery, error)
+	PruneNotifications(ctx context.Context, before time.Time) (int64, error)
+	GetNotifiedAlert(ctx context.Context, fingerprint string) (*NotifiedAlert, error)
+	SaveNotifiedAlert(ctx context.Context, a NotifiedAlert) error
+	// Notification Silences — opaque JSON documents keyed by ID with their
+	// end time, so expired silences can be pruned.
+	ListSilences(ctx context.Context) ([][]byte, error)
+	SaveSilence(ctx context.Context, id string, data []byte, endsAt time.Time) error
+	DeleteSilence(ctx context.Context, id string) error
+	PruneSilences(ctx context.Context, before time.Time) (int64, error)
+
 	// Cluster Events — cross-cluster event journal (#9967 Phase 1).
 	// InsertOrUpdateEvent upserts an event keyed by event_uid.
 	InsertOrUpdateEvent(ctx context.Context, event ClusterEvent) error
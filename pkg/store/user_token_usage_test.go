@@ -200,3 +200,28 @@ func TestAddUserTokenDelta_ConcurrentIncrementsAreAtomic(t *testing.T) {
 	assert.Equal(t, wantTotal, got.TotalTokens, "concurrent deltas must sum without lost updates")
 	assert.Equal(t, wantTotal, got.TokensByCategory[testCategoryMissions])
 }
+
+func TestAddAIBudgetUsage_AccumulatesPerDayAndDropsOldDays(t *testing.T) {
+	store := newTestStore(t)
+	const yesterday, today = "2026-01-01", "2026-01-02"
+
+	require.NoError(t, store.AddAIBudgetUsage(ctx, testTokenUsageUserID, yesterday, testCategoryMissions, testTokenDelta1))
+	require.NoError(t, store.AddAIBudgetUsage(ctx, testTokenUsageUserID, today, testCategoryMissions, testTokenDelta1))
+	require.NoError(t, store.AddAIBudgetUsage(ctx, testTokenUsageUserID, today, testCategoryMissions, testTokenDelta2))
+	require.NoError(t, store.AddAIBudgetUsage(ctx, testTokenUsageUserID, today, testCategoryDiagnose, testTokenDelta2))
+	require.Error(t, store.AddAIBudgetUsage(ctx, testTokenUsageUserID, today, testCategoryMissions, -1))
+
+	got, err := store.GetAIBudgetUsage(ctx, testTokenUsageUserID, today)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{testCategoryMissions: testTokenDelta1Plus2, testCategoryDiagnose: testTokenDelta2}, got)
+
+	old, err := store.GetAIBudgetUsage(ctx, testTokenUsageUserID, yesterday)
+	require.NoError(t, err)
+	assert.Empty(t, old)
+
+	// A full-row write of the widget state does not touch the budget.
+	require.NoError(t, store.UpdateUserTokenUsage(ctx, &UserTokenUsage{UserID: testTokenUsageUserID}))
+	got, err = store.GetAIBudgetUsage(ctx, testTokenUsageUserID, today)
+	require.NoError(t, err)
+	assert.Equal(t, testTokenDelta1Plus2, got[testCategoryMissions])
+}
@@ -341,6 +341,9 @@ func (m *MockStore) UpdateGPUReservation(ctx context.Context, reservation *model
 func (m *MockStore) UpdateGPUReservationWithCapacity(ctx context.Context, reservation *models.GPUReservation, capacity int) error {
 	return nil
 }
+func (m *MockStore) TransitionGPUReservation(ctx context.Context, reservation *models.GPUReservation, from models.ReservationStatus, capacity int) (bool, error) {
+	return true, nil
+}
 func (m *MockStore) DeleteGPUReservation(ctx context.Context, id uuid.UUID) error { return nil }
 func (m *MockStore) GetGPUReservationsByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.GPUReservation, error) {
 	return nil, nil
@@ -348,6 +351,12 @@ func (m *MockStore) GetGPUReservationsByIDs(ctx context.Context, ids []uuid.UUID
 func (m *MockStore) GetClusterReservedGPUCount(ctx context.Context, cluster string, excludeID *uuid.UUID) (int, error) {
 	return 0, nil
 }
+func (m *MockStore) GetClusterPeakReservedGPUs(ctx context.Context, cluster string, start, end time.Time, excludeID *uuid.UUID) (int, error) {
+	return 0, nil
+}
+func (m *MockStore) ListClusterGPUReservations(ctx context.Context, cluster string, statuses []models.ReservationStatus) ([]models.GPUReservation, error) {
+	return nil, nil
+}
 
 func (m *MockStore) InsertUtilizationSnapshot(ctx context.Context, snapshot *models.GPUUtilizationSnapshot) error {
 	args := m.Called(snapshot)
@@ -363,6 +372,13 @@ func (m *MockStore) DeleteOldUtilizationSnapshots(ctx context.Context, before ti
 	args := m.Called(before)
 	return args.Get(0).(int64), args.Error(1)
 }
+func (m *MockStore) ListUtilizationSnapshotsInRange(ctx context.Context, start, end time.Time) ([]models.GPUUtilizationSnapshot, error) {
+	return nil, nil
+}
+func (m *MockStore) ClaimGPUChargebackRun(ctx context.Context, period string) (bool, error) {
+	return false, nil
+}
+func (m *MockStore) ReleaseGPUChargebackRun(ctx context.Context, period string) error { return nil }
 func (m *MockStore) ListActiveGPUReservations(ctx context.Context) ([]models.GPUReservation, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -371,6 +387,22 @@ func (m *MockStore) ListActiveGPUReservations(ctx context.Context) ([]models.GPU
 	return args.Get(0).([]models.GPUReservation), args.Error(1)
 }
 
+func (m *MockStore) CreateAgentTunnelCredential(ctx context.Context, cred *models.AgentTunnelCredential) error {
+	return nil
+}
+func (m *MockStore) GetAgentTunnelCredentialByTokenHash(ctx context.Context, tokenHash string) (*models.AgentTunnelCredential, error) {
+	return nil, nil
+}
+func (m *MockStore) ListAgentTunnelCredentials(ctx context.Context, userID uuid.UUID) ([]models.AgentTunnelCredential, error) {
+	return nil, nil
+}
+func (m *MockStore) DeleteAgentTunnelCredential(ctx context.Context, userID, id uuid.UUID) error {
+	return nil
+}
+func (m *MockStore) TouchAgentTunnelCredential(ctx context.Context, id uuid.UUID, seenAt time.Time) error {
+	return nil
+}
+
 func (m *MockStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
 	return nil
 }
@@ -504,6 +536,14 @@ func (m *MockStore) AddUserTokenDelta(ctx context.Context, userID string, catego
 	}, nil
 }
 
+func (m *MockStore) GetAIBudgetUsage(_ context.Context, _, _ string) (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+
+func (m *MockStore) AddAIBudgetUsage(_ context.Context, _, _, _ string, _ int64) error {
+	return nil
+}
+
 // OAuth credentials — GitHub App Manifest one-click flow.
 func (m *MockStore) SaveOAuthCredentials(_ context.Context, _, _ string) error { return nil }
 func (m *MockStore) GetOAuthCredentials(_ context.Context) (string, string, error) {
@@ -576,6 +616,31 @@ func (m *MockStore) QueryAuditLogs(_ context.Context, limit int, userID, action
 	return args.Get(0).([]store.AuditEntry), args.Error(1)
 }
 
+func (m *MockStore) ListAuditChain(_ context.Context, afterID int64, limit int) ([]store.AuditEntry, error) {
+	args := m.Called(afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.AuditEntry), args.Error(1)
+}
+
+func (m *MockStore) AuditLegacyCutoff(_ context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) InsertAuditCheckpoint(_ context.Context, _ *store.AuditCheckpoint) error {
+	return nil
+}
+
+func (m *MockStore) ListAuditCheckpoints(_ context.Context) ([]store.AuditCheckpoint, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.AuditCheckpoint), args.Error(1)
+}
+
 func (m *MockStore) RecordKBGap(_ context.Context, path string) error {
 	args := m.Called(path)
 	return args.Error(0)
@@ -589,6 +654,55 @@ func (m *MockStore) ListTopKBGaps(_ context.Context, n int) ([]store.KBQueryGap,
 	return args.Get(0).([]store.KBQueryGap), args.Error(1)
 }
 
+func (m *MockStore) GetLicensePolicy(_ context.Context) ([]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockStore) SaveLicensePolicy(_ context.Context, data []byte) error {
+	args := m.Called(data)
+	return args.Error(0)
+}
+
+func (m *MockStore) ListAlertRules(_ context.Context) ([][]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]byte), args.Error(1)
+}
+
+func (m *MockStore) SaveAlertRule(_ context.Context, id string, data []byte) error {
+	args := m.Called(id, data)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteAlertRule(_ context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStore) ListAlertStates(_ context.Context) ([]store.AlertState, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.AlertState), args.Error(1)
+}
+
+func (m *MockStore) SaveAlertState(_ context.Context, state store.AlertState) error {
+	args := m.Called(state)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteAlertState(_ context.Context, ruleID, fingerprint string) error {
+	args := m.Called(ruleID, fingerprint)
+	return args.Error(0)
+}
+
 func (m *MockStore) InsertOrUpdateEvent(_ context.Context, _ store.ClusterEvent) error {
 	return nil
 }
@@ -606,3 +720,94 @@ func (m *MockStore) SweepOldEvents(_ context.Context, _ int) (int64, error) {
 }
 
 func (m *MockStore) Close() error { return nil }
+
+func (m *MockStore) SaveNotification(_ context.Context, d store.NotificationDelivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetNotification(_ context.Context, id string) (*store.NotificationDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*store.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockStore) ClaimDueNotifications(_ context.Context, now, until time.Time, limit int) ([]store.NotificationDelivery, error) {
+	args := m.Called(now, until, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockStore) FindNotificationGroup(_ context.Context, target, groupKey string, now time.Time) (*store.NotificationDelivery, error) {
+	args := m.Called(target, groupKey, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*store.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockStore) UpdateNotificationGroup(_ context.Context, id string, alerts []byte, now time.Time) (bool, error) {
+	args := m.Called(id, alerts, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) PendingNotifications(_ context.Context, target, groupKey string) ([]store.NotificationDelivery, error) {
+	args := m.Called(target, groupKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockStore) ListNotifications(_ context.Context, status string, limit int) ([]store.NotificationDelivery, error) {
+	args := m.Called(status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockStore) PruneNotifications(_ context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) GetNotifiedAlert(_ context.Context, fingerprint string) (*store.NotifiedAlert, error) {
+	args := m.Called(fingerprint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*store.NotifiedAlert), args.Error(1)
+}
+
+func (m *MockStore) SaveNotifiedAlert(_ context.Context, a store.NotifiedAlert) error {
+	args := m.Called(a)
+	return args.Error(0)
+}
+
+func (m *MockStore) ListSilences(_ context.Context) ([][]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]byte), args.Error(1)
+}
+
+func (m *MockStore) SaveSilence(_ context.Context, id string, data []byte, endsAt time.Time) error {
+	args := m.Called(id, data, endsAt)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteSilence(_ context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStore) PruneSilences(_ context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -217,6 +217,62 @@ describe('AlertRuleEditor Component', () => {
       const apiKey = screen.getByLabelText(/alerts\.opsgenieApiKey/)
       expect(apiKey).toHaveAttribute('id', 'alertRuleOpsgenieApiKey-1')
     })
+
+    it.each([
+      ['Microsoft Teams', /alerts\.teamsWebhookUrl/, 'alertRuleTeamsWebhookUrl-1'],
+      ['Discord', /alerts\.discordWebhookUrl/, 'alertRuleDiscordWebhookUrl-1'],
+      ['Matrix', /alerts\.matrixHomeserverUrl/, 'alertRuleMatrixHomeserverUrl-1'],
+      ['Matrix', /alerts\.matrixRoomId/, 'alertRuleMatrixRoomId-1'],
+      ['Matrix', /alerts\.matrixAccessToken/, 'alertRuleMatrixAccessToken-1'],
+    ])('associates %s channel label %s with its input', (channel, label, id) => {
+      render(
+        <AlertRuleEditor
+          isOpen={true}
+          onSave={mockOnSave}
+          onCancel={mockOnCancel}
+        />,
+      )
+      fireEvent.click(
+        screen.getByRole('button', {
+          name: new RegExp(`Add ${channel} notification channel`, 'i'),
+        }),
+      )
+      expect(screen.getByLabelText(label)).toHaveAttribute('id', id)
+    })
+
+    it('saves message templates with the channel config', () => {
+      render(
+        <AlertRuleEditor
+          isOpen={true}
+          onSave={mockOnSave}
+          onCancel={mockOnCancel}
+        />,
+      )
+      fireEvent.change(screen.getByLabelText(/alerts\.ruleName/), {
+        target: { value: 'Disk full' },
+      })
+      fireEvent.click(
+        screen.getByRole('button', { name: /Add Discord notification channel/i }),
+      )
+      fireEvent.change(screen.getByLabelText(/alerts\.titleTemplate/), {
+        target: { value: '{{.RuleName}}' },
+      })
+      fireEvent.change(screen.getByLabelText(/alerts\.bodyTemplate/), {
+        target: { value: '{{.Message}}' },
+      })
+      fireEvent.click(screen.getByRole('button', { name: 'alerts.createRule' }))
+
+      expect(mockOnSave).toHaveBeenCalledWith(
+        expect.objectContaining({
+          channels: expect.arrayContaining([
+            expect.objectContaining({
+              type: 'discord',
+              config: { titleTemplate: '{{.RuleName}}', bodyTemplate: '{{.Message}}' },
+            }),
+          ]),
+        }),
+      )
+    })
   })
 
   // Issue 9257 — clicking Cancel on a brand-new rule with nothing changed
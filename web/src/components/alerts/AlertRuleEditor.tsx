@@ -1,6 +1,6 @@
 import { useState } from 'react'
 import { useTranslation } from 'react-i18next'
-import { Trash2, Server, Bell, BellOff, Bot, Webhook, Siren, ShieldAlert } from 'lucide-react'
+import { Trash2, Server, Bell, BellOff, Bot, Webhook, Siren, ShieldAlert, Users, MessageCircle, Hash } from 'lucide-react'
 import { Slack } from '@/lib/icons'
 import { useClusters } from '../../hooks/useMCP'
 import { BaseModal, ConfirmDialog } from '../../lib/modals'
@@ -28,6 +28,13 @@ const DEFAULT_DURATION_SECS = 60 // Default condition duration in seconds
 const DEFAULT_TEMPERATURE_F = 100 // Default temperature threshold in Fahrenheit
 const DEFAULT_WIND_SPEED_MPH = 40 // Default wind speed threshold in mph
 
+// Example Go templates shown as placeholders in the channel message template
+// editors. They are code, so they are not translated.
+const TITLE_TEMPLATE_EXAMPLE = '[{{.Severity}}] {{.RuleName}} on {{.Cluster}}'
+const BODY_TEMPLATE_EXAMPLE = '{{.Message}}'
+/** Alert fields the notification templates can reference */
+const TEMPLATE_FIELDS = '.RuleName, .Severity, .Status, .Cluster, .Namespace, .Resource, .Message, .Details'
+
 /** Preset duration options shown as clickable chips in the rule editor */
 const DURATION_PRESETS = [
   { label: 'Immediate', value: 0 },
@@ -607,6 +614,30 @@ export function AlertRuleEditor({ isOpen = true, rule, onSave, onCancel }: Alert
                   <ShieldAlert className="w-3 h-3" aria-hidden="true" />
                   OpsGenie
                 </button>
+                <button
+                  onClick={() => addChannel('teams')}
+                  className="px-2 py-1 text-xs rounded bg-secondary hover:bg-secondary/80 text-foreground transition-colors flex items-center gap-1"
+                  aria-label="Add Microsoft Teams notification channel"
+                >
+                  <Users className="w-3 h-3" aria-hidden="true" />
+                  Teams
+                </button>
+                <button
+                  onClick={() => addChannel('discord')}
+                  className="px-2 py-1 text-xs rounded bg-secondary hover:bg-secondary/80 text-foreground transition-colors flex items-center gap-1"
+                  aria-label="Add Discord notification channel"
+                >
+                  <MessageCircle className="w-3 h-3" aria-hidden="true" />
+                  Discord
+                </button>
+                <button
+                  onClick={() => addChannel('matrix')}
+                  className="px-2 py-1 text-xs rounded bg-secondary hover:bg-secondary/80 text-foreground transition-colors flex items-center gap-1"
+                  aria-label="Add Matrix notification channel"
+                >
+                  <Hash className="w-3 h-3" aria-hidden="true" />
+                  Matrix
+                </button>
               </div>
             </div>
 
@@ -623,6 +654,9 @@ export function AlertRuleEditor({ isOpen = true, rule, onSave, onCancel }: Alert
                       {channel.type === 'webhook' && <Webhook className="w-4 h-4" aria-hidden="true" />}
                       {channel.type === 'pagerduty' && <Siren className="w-4 h-4" aria-hidden="true" />}
                       {channel.type === 'opsgenie' && <ShieldAlert className="w-4 h-4" aria-hidden="true" />}
+                      {channel.type === 'teams' && <Users className="w-4 h-4" aria-hidden="true" />}
+                      {channel.type === 'discord' && <MessageCircle className="w-4 h-4" aria-hidden="true" />}
+                      {channel.type === 'matrix' && <Hash className="w-4 h-4" aria-hidden="true" />}
                       <span className="text-sm font-medium text-foreground capitalize">
                         {channel.type}
                       </span>
@@ -752,6 +786,146 @@ export function AlertRuleEditor({ isOpen = true, rule, onSave, onCancel }: Alert
                       />
                     </>
                   )}
+
+                  {channel.type === 'teams' && (
+                    <>
+                      <label htmlFor={`alertRuleTeamsWebhookUrl-${index}`} className="sr-only">
+                        {t('alerts.teamsWebhookUrl')}
+                      </label>
+                      <input
+                        id={`alertRuleTeamsWebhookUrl-${index}`}
+                        name={`alertRuleTeamsWebhookUrl-${index}`}
+                        type="text"
+                        placeholder={t('alerts.teamsWebhookUrlPlaceholder')}
+                        value={channel.config.teamsWebhookUrl || ''}
+                        onChange={e =>
+                          updateChannel(index, {
+                            config: { ...channel.config, teamsWebhookUrl: e.target.value },
+                          })
+                        }
+                        className="w-full px-3 py-1.5 text-sm rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                      />
+                    </>
+                  )}
+
+                  {channel.type === 'discord' && (
+                    <>
+                      <label htmlFor={`alertRuleDiscordWebhookUrl-${index}`} className="sr-only">
+                        {t('alerts.discordWebhookUrl')}
+                      </label>
+                      <input
+                        id={`alertRuleDiscordWebhookUrl-${index}`}
+                        name={`alertRuleDiscordWebhookUrl-${index}`}
+                        type="text"
+                        placeholder={t('alerts.discordWebhookUrlPlaceholder')}
+                        value={channel.config.discordWebhookUrl || ''}
+                        onChange={e =>
+                          updateChannel(index, {
+                            config: { ...channel.config, discordWebhookUrl: e.target.value },
+                          })
+                        }
+                        className="w-full px-3 py-1.5 text-sm rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                      />
+                    </>
+                  )}
+
+                  {channel.type === 'matrix' && (
+                    <div className="space-y-2">
+                      <label htmlFor={`alertRuleMatrixHomeserverUrl-${index}`} className="sr-only">
+                        {t('alerts.matrixHomeserverUrl')}
+                      </label>
+                      <input
+                        id={`alertRuleMatrixHomeserverUrl-${index}`}
+                        name={`alertRuleMatrixHomeserverUrl-${index}`}
+                        type="text"
+                        placeholder={t('alerts.matrixHomeserverUrlPlaceholder')}
+                        value={channel.config.matrixHomeserverUrl || ''}
+                        onChange={e =>
+                          updateChannel(index, {
+                            config: { ...channel.config, matrixHomeserverUrl: e.target.value },
+                          })
+                        }
+                        className="w-full px-3 py-1.5 text-sm rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                      />
+                      <label htmlFor={`alertRuleMatrixRoomId-${index}`} className="sr-only">
+                        {t('alerts.matrixRoomId')}
+                      </label>
+                      <input
+                        id={`alertRuleMatrixRoomId-${index}`}
+                        name={`alertRuleMatrixRoomId-${index}`}
+                        type="text"
+                        placeholder={t('alerts.matrixRoomIdPlaceholder')}
+                        value={channel.config.matrixRoomId || ''}
+                        onChange={e =>
+                          updateChannel(index, {
+                            config: { ...channel.config, matrixRoomId: e.target.value },
+                          })
+                        }
+                        className="w-full px-3 py-1.5 text-sm rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                      />
+                      <label htmlFor={`alertRuleMatrixAccessToken-${index}`} className="sr-only">
+                        {t('alerts.matrixAccessToken')}
+                      </label>
+                      <input
+                        id={`alertRuleMatrixAccessToken-${index}`}
+                        name={`alertRuleMatrixAccessToken-${index}`}
+                        type="password"
+                        placeholder={t('alerts.matrixAccessTokenPlaceholder')}
+                        value={channel.config.matrixAccessToken || ''}
+                        onChange={e =>
+                          updateChannel(index, {
+                            config: { ...channel.config, matrixAccessToken: e.target.value },
+                          })
+                        }
+                        className="w-full px-3 py-1.5 text-sm rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                      />
+                    </div>
+                  )}
+
+                  {/* Every external channel renders with its built-in format
+                      unless the rule overrides the title or body. */}
+                  {channel.type !== 'browser' && (
+                    <details className="mt-2">
+                      <summary className="text-xs text-muted-foreground cursor-pointer select-none">
+                        {t('alerts.messageTemplate')}
+                      </summary>
+                      <div className="mt-2 space-y-2">
+                        <label htmlFor={`alertRuleTitleTemplate-${index}`} className="sr-only">
+                          {t('alerts.titleTemplate')}
+                        </label>
+                        <input
+                          id={`alertRuleTitleTemplate-${index}`}
+                          name={`alertRuleTitleTemplate-${index}`}
+                          type="text"
+                          placeholder={TITLE_TEMPLATE_EXAMPLE}
+                          value={channel.config.titleTemplate || ''}
+                          onChange={e =>
+                            updateChannel(index, {
+                              config: { ...channel.config, titleTemplate: e.target.value },
+                            })
+                          }
+                          className="w-full px-3 py-1.5 text-sm rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                        />
+                        <label htmlFor={`alertRuleBodyTemplate-${index}`} className="sr-only">
+                          {t('alerts.bodyTemplate')}
+                        </label>
+                        <textarea
+                          id={`alertRuleBodyTemplate-${index}`}
+                          name={`alertRuleBodyTemplate-${index}`}
+                          rows={3}
+                          placeholder={BODY_TEMPLATE_EXAMPLE}
+                          value={channel.config.bodyTemplate || ''}
+                          onChange={e =>
+                            updateChannel(index, {
+                              config: { ...channel.config, bodyTemplate: e.target.value },
+                            })
+                          }
+                          className="w-full px-3 py-1.5 text-sm font-mono rounded bg-secondary border border-border text-foreground placeholder:text-muted-foreground focus:outline-hidden focus:ring-2 focus:ring-purple-500"
+                        />
+                        <p className="text-xs text-muted-foreground">{t('alerts.messageTemplateHint', { fields: TEMPLATE_FIELDS })}</p>
+                      </div>
+                    </details>
+                  )}
                 </div>
               ))}
             </div>
@@ -10,6 +10,7 @@ import { useTranslation } from 'react-i18next'
 import { useNavigate } from 'react-router-dom'
 import { authFetch, safeJson } from '../../lib/api'
 import { useCache } from '../../lib/cache'
+import { fetchHIPAA } from '../../lib/hipaa'
 
 // ── Shared helpers ──────────────────────────────────────────────────────
 
@@ -96,7 +97,7 @@ export function HIPAACard() {
   const [error, setError] = useState<string | null>(null)
   useEffect(() => {
     setIsLoading(true)
-    authFetch('/api/compliance/hipaa/summary')
+    fetchHIPAA('/summary')
       .then(r => r.ok ? safeJson<Record<string, number>>(r) : null)
       .then(setData)
       .catch((err: unknown) => { setError(err instanceof Error ? err.message : t('messages.failedToLoad')); console.error(err) })
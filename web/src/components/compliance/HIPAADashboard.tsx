@@ -7,7 +7,7 @@ import {
   Activity, Lock, Eye, UserCheck, Network,
   ArrowRight, Server,
 } from 'lucide-react'
-import { authFetch } from '../../lib/api'
+import { fetchHIPAA } from '../../lib/hipaa'
 import { DashboardHeader } from '../shared/DashboardHeader'
 import { RotatingTip } from '../ui/RotatingTip'
 
@@ -65,10 +65,10 @@ export const HIPAADashboardContent = memo(function HIPAADashboardContent() {
     setError(null)
     try {
       const [sgRes, nsRes, flRes, smRes] = await Promise.all([
-        authFetch('/api/compliance/hipaa/safeguards'),
-        authFetch('/api/compliance/hipaa/phi-namespaces'),
-        authFetch('/api/compliance/hipaa/data-flows'),
-        authFetch('/api/compliance/hipaa/summary'),
+        fetchHIPAA('/safeguards'),
+        fetchHIPAA('/phi-namespaces'),
+        fetchHIPAA('/data-flows'),
+        fetchHIPAA('/summary'),
       ])
       if (!sgRes.ok || !nsRes.ok || !flRes.ok || !smRes.ok) throw new Error('Failed to load HIPAA data')
       const sgData = await sgRes.json()
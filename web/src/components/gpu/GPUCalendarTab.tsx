@@ -6,6 +6,7 @@ import {
 } from 'lucide-react'
 import { cn } from '../../lib/cn'
 import type { GPUReservation } from '../../hooks/useGPUReservations'
+import { STATUS_DOT_COLORS } from './gpu-constants'
 
 export interface CalendarBar {
   reservation: GPUReservation
@@ -144,8 +145,11 @@ export function GPUCalendarTab({
 
                   {/* Spanning bars */}
                   {week.bars.map((bar, barIdx) => {
-                    const isActive = bar.reservation.status === 'active'
-                    const isInactive = bar.reservation.status === 'completed' || bar.reservation.status === 'cancelled'
+                    const status = bar.reservation.status
+                    const isInactive = status === 'completed' || status === 'cancelled'
+                    // Waitlisted reservations hold no capacity yet, so they are
+                    // drawn as an outline rather than a booked bar.
+                    const isWaitlisted = status === 'waitlisted'
 
                     return (
                       <button
@@ -156,6 +160,8 @@ export function GPUCalendarTab({
                           'h-[20px]',
                           isInactive
                             ? 'bg-secondary/80 text-muted-foreground'
+                            : isWaitlisted
+                            ? 'bg-yellow-500/10 text-yellow-300 border border-dashed border-yellow-500/50'
                             : 'bg-purple-500/30 text-purple-300',
                           bar.isStart ? 'rounded-l-md' : '',
                           bar.isEnd ? 'rounded-r-md' : '',
@@ -168,8 +174,8 @@ export function GPUCalendarTab({
                         title={`${bar.reservation.title} (${bar.reservation.gpu_count} GPUs, ${bar.reservation.status})`}
                         aria-label={`${bar.reservation.title}: ${bar.reservation.gpu_count} GPUs, ${bar.reservation.status}`}
                       >
-                        {bar.isStart && isActive && (
-                          <span className="inline-block w-2 h-2 rounded-full bg-green-400 shrink-0" />
+                        {bar.isStart && STATUS_DOT_COLORS[status] && (
+                          <span className={cn('inline-block w-2 h-2 rounded-full shrink-0', STATUS_DOT_COLORS[status])} />
                         )}
                         {bar.isStart ? bar.reservation.title : ''}
                       </button>
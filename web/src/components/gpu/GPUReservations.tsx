@@ -635,15 +635,19 @@ export function GPUReservations() {
         knownNamespacesByCluster={knownNamespacesByCluster}
         onSave={async (input) => {
           if (editingReservation) {
-            await apiUpdateReservation(editingReservation.id, input as UpdateGPUReservationInput)
-            return editingReservation.id
-          } else {
-            const created = await apiCreateReservation(input as CreateGPUReservationInput)
-            return created.id
+            return apiUpdateReservation(editingReservation.id, input as UpdateGPUReservationInput)
           }
+          return apiCreateReservation(input as CreateGPUReservationInput)
         }}
         onActivate={async (id) => { await apiUpdateReservation(id, { status: 'active' }) }}
-        onSaved={() => showToast(t('gpuReservations.form.success.saved'), 'success')}
+        onSaved={(status) => showToast(
+          status === 'waitlisted'
+            ? t('gpuReservations.form.success.waitlisted')
+            : status === 'scheduled'
+            ? t('gpuReservations.form.success.scheduled')
+            : t('gpuReservations.form.success.saved'),
+          'success',
+        )}
         onError={(msg) => showToast(msg, 'error')}
       />
 
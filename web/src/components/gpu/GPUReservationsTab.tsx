@@ -18,6 +18,7 @@ import type { GPUReservation } from '../../hooks/useGPUReservations'
 import type { GPUUtilizationSnapshot } from '../../hooks/useGPUUtilizations'
 import {
   STATUS_COLORS,
+  STATUS_DOT_COLORS,
   SPARKLINE_HEIGHT_PX,
   computeAvgUtilization,
   countActiveDays,
@@ -261,7 +262,7 @@ export function GPUReservationsTab({
                     <div>
                       <div className="text-sm text-muted-foreground">{t('common:common.status')}</div>
                       <span className={cn('inline-flex items-center gap-1.5 px-2 py-0.5 text-xs rounded-full border', STATUS_COLORS[r.status] || STATUS_COLORS.active)}>
-                        {r.status === 'active' && <span className="w-2 h-2 rounded-full bg-green-400" />}
+                        {STATUS_DOT_COLORS[r.status] && <span className={cn('w-2 h-2 rounded-full', STATUS_DOT_COLORS[r.status])} />}
                         {r.status}
                       </span>
                     </div>
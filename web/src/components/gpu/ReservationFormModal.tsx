@@ -13,7 +13,7 @@ import {
   deleteResourceQuota,
   COMMON_RESOURCE_TYPES } from '../../hooks/useMCP'
 import type { GPUNode } from '../../hooks/useMCP'
-import type { GPUReservation, CreateGPUReservationInput, UpdateGPUReservationInput } from '../../hooks/useGPUReservations'
+import type { GPUReservation, CreateGPUReservationInput, UpdateGPUReservationInput, ReservationStatus } from '../../hooks/useGPUReservations'
 import { normalizeGpuTypes } from '../../hooks/useGPUReservations'
 import { cn } from '../../lib/cn'
 
@@ -125,9 +125,10 @@ export function ReservationFormModal({
    * regardless of what this prop contains.
    */
   knownNamespacesByCluster?: Record<string, string[]>
-  onSave: (input: CreateGPUReservationInput | UpdateGPUReservationInput) => Promise<string | void>
+  /** Resolves to the reservation as saved by the server, when known. */
+  onSave: (input: CreateGPUReservationInput | UpdateGPUReservationInput) => Promise<GPUReservation | undefined>
   onActivate: (id: string) => Promise<void>
-  onSaved: () => void
+  onSaved: (status?: ReservationStatus) => void
   onError: (msg: string) => void
 }) {
   const { t } = useTranslation(['cards', 'common'])
@@ -152,6 +153,8 @@ export function ReservationFormModal({
   const [durationHours, setDurationHours] = useState(editingReservation ? String(editingReservation.duration_hours) : '')
   const [notes, setNotes] = useState(editingReservation?.notes || '')
   const enforceQuota = true
+  // Create-only: queue for the window instead of failing when it is full.
+  const [joinWaitlist, setJoinWaitlist] = useState(false)
   const [extraResources, setExtraResources] = useState<Array<{ key: string; value: string }>>([])
   const [isSaving, setIsSaving] = useState(false)
   const [error, setError] = useState<string | null>(null)
@@ -287,8 +290,12 @@ export function ReservationFormModal({
     // Without this, an edit could request more GPUs than the cluster has.
     const originalCount = editingReservation?.gpu_count ?? 0
     const sameClusterAsOriginal = editingReservation ? cluster === editingReservation.cluster : true
+    // A waitlisted request only has to fit the cluster at all; the server
+    // queues it until enough GPUs free up in its window.
     const capacityCeiling = editingReservation && sameClusterAsOriginal
       ? maxGPUs + originalCount
+      : !editingReservation && joinWaitlist
+      ? Math.max(selectedClusterInfo?.totalGPUs ?? 0, maxGPUs)
       : maxGPUs
     const validationError = !cluster
       ? t('gpuReservations.form.errors.selectCluster')
@@ -306,7 +313,7 @@ export function ReservationFormModal({
 
     setIsSaving(true)
     try {
-      let reservationId: string | void
+      let saved: GPUReservation | undefined
       // Backend requires RFC 3339; <input type="date"> only emits YYYY-MM-DD,
       // so normalize to midnight UTC before sending.
       const rfc3339StartDate = toRFC3339StartDate(startDate)
@@ -340,7 +347,7 @@ export function ReservationFormModal({
           quota_enforced: enforceQuota,
           quota_name: enforceQuota ? quotaName : '',
           max_cluster_gpus: selectedClusterInfo?.totalGPUs }
-        reservationId = await onSave(input)
+        saved = await onSave(input)
       } else {
         // Create
         const input: CreateGPUReservationInput = {
@@ -356,12 +363,16 @@ export function ReservationFormModal({
           notes,
           quota_enforced: enforceQuota,
           quota_name: enforceQuota ? quotaName : '',
-          max_cluster_gpus: selectedClusterInfo?.totalGPUs }
-        reservationId = await onSave(input)
+          max_cluster_gpus: selectedClusterInfo?.totalGPUs,
+          waitlist: joinWaitlist }
+        saved = await onSave(input)
       }
+      const savedStatus = saved?.status
 
-      // Create K8s ResourceQuota (auto-creates namespace if needed)
-      if (enforceQuota) {
+      // Create K8s ResourceQuota (auto-creates namespace if needed). A
+      // scheduled or waitlisted reservation is provisioned by the server
+      // when its window starts, so nothing is created for it here.
+      if (enforceQuota && (!savedStatus || savedStatus === 'active')) {
         try {
           const hard: Record<string, string> = {
             [gpuResourceKey]: String(count) }
@@ -391,7 +402,7 @@ export function ReservationFormModal({
           }
           await createOrUpdateResourceQuota({ cluster, namespace, name: quotaName, hard, ensure_namespace: isNewNamespace })
           // Quota enforced successfully — activate the reservation
-          const id = reservationId || editingReservation?.id
+          const id = saved?.id || editingReservation?.id
           if (id) {
             try { await onActivate(id) } catch { /* non-fatal */ }
           }
@@ -401,7 +412,7 @@ export function ReservationFormModal({
         }
       }
 
-      onSaved()
+      onSaved(savedStatus)
       onClose()
     } catch (err: unknown) {
       const msg = err instanceof Error ? err.message : t('gpuReservations.form.errors.saveFailed')
@@ -647,6 +658,18 @@ export function ReservationFormModal({
             </div>
           </div>
 
+          {/* Waitlist opt-in (create only) */}
+          {!editingReservation && (
+            <label className="flex items-start gap-2 text-sm text-muted-foreground cursor-pointer">
+              <input type="checkbox" checked={joinWaitlist} onChange={e => setJoinWaitlist(e.target.checked)}
+                className="mt-0.5 rounded border-border" />
+              <span>
+                <span className="text-foreground">{t('gpuReservations.form.fields.waitlistLabel')}</span>
+                <span className="block text-xs">{t('gpuReservations.form.fields.waitlistHint')}</span>
+              </span>
+            </label>
+          )}
+
           {/* Additional Resource Limits */}
           {enforceQuota && (
             <div>
@@ -42,7 +42,16 @@ export function computeAvgUtilization(snapshots: GPUUtilizationSnapshot[]): numb
 
 // Status badge colors
 export const STATUS_COLORS: Record<string, string> = {
+  scheduled: 'bg-purple-500/20 text-purple-400 border-purple-500/30',
+  waitlisted: 'bg-yellow-500/20 text-yellow-400 border-yellow-500/30',
   active: 'bg-green-500/20 text-green-400 border-green-500/30',
   completed: 'bg-blue-500/20 text-blue-400 border-blue-500/30',
   cancelled: 'bg-red-500/20 text-red-400 border-red-500/30',
 }
+
+// Status dot colors for reservations that are live or upcoming
+export const STATUS_DOT_COLORS: Record<string, string> = {
+  scheduled: 'bg-purple-400',
+  waitlisted: 'bg-yellow-400',
+  active: 'bg-green-400',
+}
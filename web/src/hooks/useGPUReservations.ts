@@ -5,7 +5,13 @@ import { isInClusterMode } from './useBackendHealth'
 
 const REFRESH_INTERVAL_MS = 30000
 
-export type ReservationStatus = 'active' | 'completed' | 'cancelled'
+/**
+ * Lifecycle status set by the server. `scheduled` reservations hold capacity
+ * for a window that has not started yet; `waitlisted` ones did not fit their
+ * window and are promoted when capacity frees up. Only `completed` and
+ * `cancelled` may be requested through an update.
+ */
+export type ReservationStatus = 'scheduled' | 'waitlisted' | 'active' | 'completed' | 'cancelled'
 
 export interface GPUReservation {
   id: string
@@ -44,6 +50,8 @@ export interface GPUReservation {
   quota_enforced: boolean
   created_at: string
   updated_at?: string
+  /** When the reservation first became active; unset if it never did. */
+  activated_at?: string
 }
 
 /**
@@ -87,6 +95,11 @@ export interface CreateGPUReservationInput {
   quota_name?: string
   quota_enforced?: boolean
   max_cluster_gpus?: number
+  /**
+   * Join the waitlist instead of failing when the window is already full.
+   * The server then answers 202 with a `waitlisted` reservation.
+   */
+  waitlist?: boolean
 }
 
 export interface UpdateGPUReservationInput {
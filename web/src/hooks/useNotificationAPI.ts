@@ -5,8 +5,19 @@ import { FETCH_DEFAULT_TIMEOUT_MS } from '../lib/constants/network'
 
 const API_BASE = import.meta.env.VITE_API_BASE_URL || BACKEND_DEFAULT_URL
 
+/** Notifier types the backend can send a test notification through */
+type TestNotificationType =
+  | 'slack'
+  | 'email'
+  | 'webhook'
+  | 'pagerduty'
+  | 'opsgenie'
+  | 'teams'
+  | 'discord'
+  | 'matrix'
+
 interface TestNotificationRequest {
-  type: 'slack' | 'email' | 'webhook' | 'pagerduty' | 'opsgenie'
+  type: TestNotificationType
   config: Record<string, unknown>
 }
 
@@ -27,7 +38,7 @@ export function useNotificationAPI() {
       ...(token && { Authorization: `Bearer ${token}` }) }
   }
 
-  const testNotification = async (type: 'slack' | 'email' | 'webhook' | 'pagerduty' | 'opsgenie', config: Record<string, unknown>) => {
+  const testNotification = async (type: TestNotificationType, config: Record<string, unknown>) => {
       setIsLoading(true)
       setError(null)
 
@@ -0,0 +1,24 @@
+/**
+ * HIPAA compliance API access.
+ *
+ * The authenticated live endpoints evaluate the user's real clusters. The
+ * public demo endpoints serve fixed sample data; they are used in demo mode
+ * and when the server has no cluster access, in which case the live routes
+ * are not registered and answer 404.
+ */
+
+import { authFetch } from './api'
+import { isDemoMode } from './demoMode'
+
+const HIPAA_LIVE_BASE = '/api/compliance/hipaa/live'
+const HIPAA_DEMO_BASE = '/api/compliance/hipaa'
+const HTTP_NOT_FOUND = 404
+
+/** Fetches a HIPAA resource such as '/summary', live when available. */
+export async function fetchHIPAA(path: string): Promise<Response> {
+  if (!isDemoMode()) {
+    const res = await authFetch(`${HIPAA_LIVE_BASE}${path}`)
+    if (res.status !== HTTP_NOT_FOUND) return res
+  }
+  return authFetch(`${HIPAA_DEMO_BASE}${path}`)
+}
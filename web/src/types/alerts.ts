@@ -39,7 +39,15 @@ export type AlertStatus = 'firing' | 'resolved'
 export type AlertSignalType = 'state' | 'notification' | 'acknowledged'
 
 // Alert channel types
-export type AlertChannelType = 'browser' | 'slack' | 'webhook' | 'pagerduty' | 'opsgenie'
+export type AlertChannelType =
+  | 'browser'
+  | 'slack'
+  | 'webhook'
+  | 'pagerduty'
+  | 'opsgenie'
+  | 'teams'
+  | 'discord'
+  | 'matrix'
 
 // Alert condition configuration
 export interface AlertCondition {
@@ -74,6 +82,13 @@ export interface AlertChannel {
     webhookUrl?: string // for generic webhook type
     pagerdutyRoutingKey?: string
     opsgenieApiKey?: string
+    teamsWebhookUrl?: string
+    discordWebhookUrl?: string
+    matrixHomeserverUrl?: string
+    matrixAccessToken?: string
+    matrixRoomId?: string
+    titleTemplate?: string // Go text/template for the message title
+    bodyTemplate?: string // Go text/template for the message body
   }
 }
 
@@ -145,6 +160,13 @@ export interface NotificationConfig {
   webhookUrl?: string
   pagerdutyRoutingKey?: string
   opsgenieApiKey?: string
+  teamsWebhookUrl?: string
+  discordWebhookUrl?: string
+  matrixHomeserverUrl?: string
+  matrixAccessToken?: string
+  matrixRoomId?: string
+  titleTemplate?: string
+  bodyTemplate?: string
 }
 
 // Alert statistics
@@ -27,6 +27,9 @@ export interface StellarMission {
   memoryScope: string
   enabled: boolean
   toolBindings: string[]
+  /** Cluster and namespace scheduled runs target; empty uses pinned clusters */
+  cluster?: string
+  namespace?: string
   createdAt: string
   updatedAt: string
 }